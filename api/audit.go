@@ -0,0 +1,323 @@
+package api
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"nofx/logger"
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// auditSourceHeader lets first-party callers (the Telegram agent) identify
+// themselves. It only labels the entry; the actor always comes from the JWT.
+const auditSourceHeader = "X-NOFX-Source"
+
+// maxAuditBodyBytes bounds how much of a request body is captured for the
+// "after" state of routes without a resource snapshot.
+const maxAuditBodyBytes = 64 << 10
+
+// auditActions gives mutating routes a stable, human-readable action name.
+// Routes not listed are recorded as "METHOD <path>".
+var auditActions = map[string]string{
+	"POST /api/traders":                     "trader.create",
+	"PUT /api/traders/:id":                  "trader.update",
+	"DELETE /api/traders/:id":               "trader.delete",
+	"POST /api/traders/:id/start":           "trader.start",
+	"POST /api/traders/:id/stop":            "trader.stop",
+	"PUT /api/traders/:id/prompt":           "trader.prompt.update",
+	"POST /api/traders/:id/sync-balance":    "trader.sync_balance",
+	"POST /api/traders/:id/close-position":  "position.force_close",
+	"PUT /api/traders/:id/competition":      "trader.competition.toggle",
+	"PUT /api/models":                       "ai_model.update",
+	"POST /api/exchanges":                   "exchange.create",
+	"PUT /api/exchanges":                    "exchange.update",
+	"DELETE /api/exchanges/:id":             "exchange.delete",
+	"POST /api/telegram":                    "telegram.update",
+	"POST /api/telegram/model":              "telegram.model.update",
+	"DELETE /api/telegram/binding":          "telegram.unbind",
+	"POST /api/strategies":                  "strategy.create",
+	"PUT /api/strategies/:id":               "strategy.update",
+	"DELETE /api/strategies/:id":            "strategy.delete",
+	"POST /api/strategies/:id/activate":     "strategy.activate",
+	"POST /api/strategies/:id/duplicate":    "strategy.duplicate",
+	"POST /api/strategies/import":           "strategy.import",
+	"POST /api/strategies/:id/rollback":     "strategy.rollback",
+	"PUT /api/traders/:id/strategy-version": "trader.strategy_version.pin",
+	"PUT /api/traders/:id/ai-fallbacks":     "trader.ai_fallbacks.update",
+	"PUT /api/ai-budgets":                   "ai_budget.update",
+	"DELETE /api/ai-budgets":                "ai_budget.delete",
+	"PUT /api/ai-costs/prices":              "ai_price.update",
+	"DELETE /api/ai-costs/prices":           "ai_price.delete",
+	"PUT /api/user/password":                "user.password.change",
+	"POST /api/onboarding/beginner":         "onboarding.beginner",
+
+	"POST /api/strategies/:id/publish":        "strategy.publish",
+	"POST /api/strategies/:id/fork":           "strategy.fork",
+	"PUT /api/strategies/:id/subscription":    "strategy.subscription.update",
+	"DELETE /api/strategies/:id/subscription": "strategy.subscription.delete",
+}
+
+// auditSkipRoutes are mutating routes with no configuration or control effect.
+var auditSkipRoutes = map[string]bool{
+	"POST /api/logout":                     true,
+	"POST /api/launch/preflight":           true,
+	"POST /api/strategies/preview-prompt":  true,
+	"POST /api/strategies/test-run":        true,
+	"POST /api/strategies/estimate-tokens": true,
+	"PUT /api/strategies/:id/star":         true,
+	"DELETE /api/strategies/:id/star":      true,
+	"PUT /api/strategies/:id/rating":       true,
+	"POST /api/notifications/read":         true,
+}
+
+// auditActionName returns the recorded action for method+route.
+func auditActionName(method, fullPath string) string {
+	if action, ok := auditActions[method+" "+fullPath]; ok {
+		return action
+	}
+	return method + " " + fullPath
+}
+
+// auditSource resolves where the request came from. CLI entries are written
+// directly by cli.go, never over HTTP, so only the agent label is accepted.
+func auditSource(c *gin.Context) string {
+	if strings.TrimSpace(c.GetHeader(auditSourceHeader)) == store.AuditSourceTelegram {
+		return store.AuditSourceTelegram
+	}
+	return store.AuditSourceWeb
+}
+
+// auditMiddleware records every mutating call on the protected routes. It
+// snapshots the affected resource before and after the handler runs and
+// stores the (secret-redacted) difference; for creates and control actions
+// without a resource snapshot the request body is recorded as the "after"
+// state. Failed calls (4xx/5xx) are not recorded.
+func (s *Server) auditMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		method := c.Request.Method
+		routeKey := method + " " + c.FullPath()
+		// Workspace management handlers record their own, more specific entries.
+		if method == http.MethodGet || method == http.MethodOptions || method == http.MethodHead ||
+			auditSkipRoutes[routeKey] || strings.HasPrefix(c.FullPath(), "/api/workspaces") {
+			c.Next()
+			return
+		}
+
+		userID := c.GetString("user_id")
+		before, hasSnapshot := s.auditSnapshot(c, userID)
+		body := captureAuditBody(c)
+
+		c.Next()
+
+		if c.Writer.Status() >= http.StatusBadRequest {
+			return
+		}
+
+		var changes []store.AuditChange
+		if hasSnapshot {
+			after, _ := s.auditSnapshot(c, userID)
+			changes = store.DiffAuditState(before, after)
+		} else if body != nil {
+			changes = store.DiffAuditState(nil, body)
+		}
+
+		s.recordAudit(c, auditActionName(method, c.FullPath()), auditTarget(c, body), changes)
+	}
+}
+
+// captureAuditBody reads a JSON object body and restores it for the handler.
+func captureAuditBody(c *gin.Context) map[string]any {
+	if c.Request.Body == nil {
+		return nil
+	}
+	raw, err := io.ReadAll(io.LimitReader(c.Request.Body, maxAuditBodyBytes+1))
+	rest := c.Request.Body
+	c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(raw), rest))
+	if err != nil || len(raw) == 0 || len(raw) > maxAuditBodyBytes {
+		return nil
+	}
+	var body map[string]any
+	if json.Unmarshal(raw, &body) != nil {
+		return nil
+	}
+	return body
+}
+
+// auditSnapshot loads the current state of the resource a route mutates.
+// ok is false for routes without a snapshot.
+func (s *Server) auditSnapshot(c *gin.Context, userID string) (state any, ok bool) {
+	path := c.FullPath()
+	id := c.Param("id")
+	switch {
+	case strings.HasPrefix(path, "/api/strategies/:id"):
+		if st, err := s.store.Strategy().Get(userID, id); err == nil {
+			var cfg any
+			_ = json.Unmarshal([]byte(st.Config), &cfg)
+			return gin.H{
+				"name":           st.Name,
+				"description":    st.Description,
+				"is_active":      st.IsActive,
+				"is_public":      st.IsPublic,
+				"config_visible": st.ConfigVisible,
+				"config":         cfg,
+			}, true
+		}
+		return nil, true
+	case strings.HasPrefix(path, "/api/traders/:id"):
+		if t, err := s.store.Trader().GetByID(id); err == nil && t.UserID == userID {
+			return t, true
+		}
+		return nil, true
+	case strings.HasPrefix(path, "/api/exchanges"):
+		exchanges, _ := s.store.Exchange().List(userID)
+		return exchanges, true
+	case path == "/api/models":
+		models, _ := s.store.AIModel().List(userID)
+		return models, true
+	case strings.HasPrefix(path, "/api/telegram"):
+		cfg, _ := s.store.TelegramConfig().Get()
+		return cfg, true
+	}
+	return nil, false
+}
+
+// auditTarget picks the resource identifier of the current request.
+func auditTarget(c *gin.Context, body map[string]any) string {
+	if id := c.Param("id"); id != "" {
+		return id
+	}
+	if id, ok := body["id"].(string); ok && id != "" {
+		return id
+	}
+	return c.Query("trader_id")
+}
+
+// recordAudit appends an entry for the current request. Failures are logged,
+// never surfaced: the action itself already succeeded.
+func (s *Server) recordAudit(c *gin.Context, action, target string, changes []store.AuditChange) {
+	entry := &store.AuditLog{
+		OwnerID:     c.GetString("user_id"),
+		ActorID:     actorID(c),
+		WorkspaceID: c.GetString("workspace_id"),
+		Source:      auditSource(c),
+		Action:      action,
+		Target:      target,
+		Status:      c.Writer.Status(),
+		IP:          c.ClientIP(),
+		ChangeList:  changes,
+	}
+	if err := s.store.Audit().Record(entry); err != nil {
+		logger.Warnf("⚠️ Failed to record audit entry %s: %v", action, err)
+	}
+}
+
+// auditFilterFromQuery builds a filter scoped to the caller's resources from
+// ?actor_id=&source=&action=&target=&since=&until=&limit=&offset=.
+// since/until accept RFC3339 or YYYY-MM-DD.
+func auditFilterFromQuery(c *gin.Context) (store.AuditFilter, error) {
+	filter := store.AuditFilter{
+		OwnerID: c.GetString("user_id"),
+		ActorID: c.Query("actor_id"),
+		Source:  c.Query("source"),
+		Action:  c.Query("action"),
+		Target:  c.Query("target"),
+	}
+	var err error
+	if filter.Since, err = parseAuditTime(c.Query("since")); err != nil {
+		return filter, fmt.Errorf("invalid since: %w", err)
+	}
+	if filter.Until, err = parseAuditTime(c.Query("until")); err != nil {
+		return filter, fmt.Errorf("invalid until: %w", err)
+	}
+	filter.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "100"))
+	filter.Offset, _ = strconv.Atoi(c.DefaultQuery("offset", "0"))
+	return filter, nil
+}
+
+func parseAuditTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// handleListAudit returns the audit trail of the caller's resources
+func (s *Server) handleListAudit(c *gin.Context) {
+	filter, err := auditFilterFromQuery(c)
+	if err != nil {
+		SafeBadRequest(c, err.Error())
+		return
+	}
+	entries, err := s.store.Audit().List(filter)
+	if err != nil {
+		SafeInternalError(c, "Failed to load audit trail", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+		"count":   len(entries),
+	})
+}
+
+// handleExportAudit exports the audit trail as CSV (default) or JSON
+func (s *Server) handleExportAudit(c *gin.Context) {
+	filter, err := auditFilterFromQuery(c)
+	if err != nil {
+		SafeBadRequest(c, err.Error())
+		return
+	}
+	if c.Query("limit") == "" {
+		filter.Limit = 10000
+	}
+	entries, err := s.store.Audit().List(filter)
+	if err != nil {
+		SafeInternalError(c, "Failed to load audit trail", err)
+		return
+	}
+
+	stamp := time.Now().UTC().Format("20060102-150405")
+	if c.DefaultQuery("format", "csv") == "json" {
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="nofx-audit-%s.json"`, stamp))
+		c.JSON(http.StatusOK, entries)
+		return
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"time", "actor_id", "workspace_id", "source", "action", "target", "status", "ip", "changes"})
+	for _, e := range entries {
+		changes := ""
+		if len(e.ChangeList) > 0 {
+			if data, err := json.Marshal(e.ChangeList); err == nil {
+				changes = string(data)
+			}
+		}
+		_ = w.Write([]string{
+			e.CreatedAt.UTC().Format(time.RFC3339),
+			e.ActorID,
+			e.WorkspaceID,
+			e.Source,
+			e.Action,
+			e.Target,
+			strconv.Itoa(e.Status),
+			e.IP,
+			changes,
+		})
+	}
+	w.Flush()
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="nofx-audit-%s.csv"`, stamp))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
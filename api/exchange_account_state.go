@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"nofx/logger"
+	"nofx/secrets"
 	"nofx/store"
 	"nofx/trader"
 	"nofx/trader/aster"
@@ -223,6 +224,10 @@ func probeExchangeAccountState(exchangeCfg *store.Exchange, userID string) Excha
 }
 
 func buildExchangeProbeTrader(exchangeCfg *store.Exchange, userID string) (trader.Trader, error) {
+	exchangeCfg, err := secrets.ResolveExchange(exchangeCfg)
+	if err != nil {
+		return nil, err
+	}
 	switch exchangeCfg.ExchangeType {
 	case "binance":
 		return binance.NewFuturesTrader(string(exchangeCfg.APIKey), string(exchangeCfg.SecretKey), userID), nil
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"nofx/logger"
+	"nofx/market"
+	"nofx/store"
+)
+
+const (
+	// excursionBackfillLimit how many positions without MAE/MFE one run
+	// computes, bounding the kline fetches a single poll can trigger
+	excursionBackfillLimit = 20
+	// excursionCandidateLimit positions without MAE/MFE read per run, so
+	// positions waiting for a retry do not starve the others
+	excursionCandidateLimit = 200
+	// Retry delays after transient kline errors: 1m, 2m, 4m ... up to 6h
+	excursionRetryBase = time.Minute
+	excursionRetryMax  = 6 * time.Hour
+)
+
+// fetchExcursionKlines kline source for MAE/MFE, replaced in tests
+var fetchExcursionKlines = market.GetKlinesRange
+
+// errNoExcursionKlines the exchange has no klines for a position's hold
+var errNoExcursionKlines = errors.New("no klines")
+
+// excursionRetry the backoff of a position whose klines could not be fetched
+type excursionRetry struct {
+	attempts int
+	next     time.Time
+}
+
+// excursionBackfiller computes the MAE/MFE of closed positions that have none
+// in the background, one run per store at a time. A position is marked
+// unavailable only when the exchange has no klines for it (unknown symbol,
+// empty range); other errors are retried with backoff.
+type excursionBackfiller struct {
+	mu      sync.Mutex
+	running map[*store.Store]bool
+	retries map[int64]*excursionRetry
+}
+
+func newExcursionBackfiller() *excursionBackfiller {
+	return &excursionBackfiller{
+		running: make(map[*store.Store]bool),
+		retries: make(map[int64]*excursionRetry),
+	}
+}
+
+// trigger starts a backfill for the filter unless one is already running on
+// the store
+func (b *excursionBackfiller) trigger(st *store.Store, filter store.AnalyticsFilter) {
+	b.mu.Lock()
+	if b.running[st] {
+		b.mu.Unlock()
+		return
+	}
+	b.running[st] = true
+	b.mu.Unlock()
+
+	go func() {
+		defer func() {
+			b.mu.Lock()
+			delete(b.running, st)
+			b.mu.Unlock()
+		}()
+		b.run(st, filter, time.Now())
+	}()
+}
+
+// run computes up to excursionBackfillLimit excursions whose retry is due
+func (b *excursionBackfiller) run(st *store.Store, filter store.AnalyticsFilter, now time.Time) {
+	positions, err := st.Position().ClosedPositionsWithoutExcursion(filter, excursionCandidateLimit)
+	if err != nil {
+		logger.Warnf("⚠️ Failed to list positions without MAE/MFE: %v", err)
+		return
+	}
+	computed := 0
+	for i := range positions {
+		if computed >= excursionBackfillLimit {
+			return
+		}
+		pos := &positions[i]
+		if !b.due(pos.ID, now) {
+			continue
+		}
+		computed++
+		excursion, err := computePositionExcursion(pos)
+		if err != nil {
+			if !isDefinitiveKlineError(err) {
+				delay := b.retryLater(pos.ID, now)
+				logger.Warnf("⚠️ MAE/MFE of position %d (%s) failed, retrying in %s: %v", pos.ID, pos.Symbol, delay, err)
+				continue
+			}
+			logger.Warnf("⚠️ MAE/MFE unavailable for position %d (%s): %v", pos.ID, pos.Symbol, err)
+			excursion = &store.PositionExcursion{PositionID: pos.ID, Timeframe: store.ExcursionUnavailable}
+		}
+		if err := st.Position().SaveExcursion(excursion); err != nil {
+			logger.Warnf("⚠️ Failed to save MAE/MFE of position %d: %v", pos.ID, err)
+			return
+		}
+		b.mu.Lock()
+		delete(b.retries, pos.ID)
+		b.mu.Unlock()
+	}
+}
+
+// due reports whether a position is not waiting for a retry
+func (b *excursionBackfiller) due(positionID int64, now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	r, ok := b.retries[positionID]
+	return !ok || !now.Before(r.next)
+}
+
+// retryLater schedules the next attempt of a position and returns its delay
+func (b *excursionBackfiller) retryLater(positionID int64, now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	r, ok := b.retries[positionID]
+	if !ok {
+		r = &excursionRetry{}
+		b.retries[positionID] = r
+	}
+	delay := excursionRetryMax
+	if r.attempts < 16 {
+		if d := excursionRetryBase << r.attempts; d < excursionRetryMax {
+			delay = d
+		}
+	}
+	r.attempts++
+	r.next = now.Add(delay)
+	return delay
+}
+
+// isDefinitiveKlineError reports whether a kline error means the klines will
+// never be available: no klines in the range, or a symbol Binance does not
+// know (code -1121)
+func isDefinitiveKlineError(err error) bool {
+	if errors.Is(err, errNoExcursionKlines) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "-1121") || strings.Contains(msg, "invalid symbol")
+}
@@ -1,9 +1,14 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 
+	"nofx/logger"
+	"nofx/store"
+
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // handleGetAICosts returns AI charges for a specific trader
@@ -15,29 +20,205 @@ func (s *Server) handleGetAICosts(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "trader_id is required"})
 		return
 	}
+	traderRecord, err := s.store.Trader().GetByID(traderID)
+	if err != nil || traderRecord.UserID != c.GetString("user_id") {
+		SafeNotFound(c, "Trader")
+		return
+	}
 
 	charges, total, err := s.store.AICharge().GetCharges(traderID, period)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		SafeInternalError(c, "Failed to get AI costs", err)
 		return
 	}
 
+	cycles, err := s.store.AICharge().GetCycleCosts(traderID, period)
+	if err != nil {
+		SafeInternalError(c, "Failed to get AI costs", err)
+		return
+	}
+	var avgPerCycle float64
+	if len(cycles) > 0 {
+		var cycleTotal float64
+		for _, cycle := range cycles {
+			cycleTotal += cycle.CostUSD
+		}
+		avgPerCycle = cycleTotal / float64(len(cycles))
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"charges": charges,
-		"total":   total,
-		"count":   len(charges),
+		"charges":            charges,
+		"total":              total,
+		"count":              len(charges),
+		"tokens":             s.store.AICharge().GetTokenTotals([]string{traderID}, period),
+		"cycles":             cycles,
+		"avg_cost_per_cycle": avgPerCycle,
 	})
 }
 
-// handleGetAICostsSummary returns AI cost summary across all traders
+// handleGetAICostsSummary returns AI cost summary across the user's traders
 func (s *Server) handleGetAICostsSummary(c *gin.Context) {
+	userID := c.GetString("user_id")
 	period := c.DefaultQuery("period", "today")
 
-	total, count, byModel := s.store.AICharge().GetSummary(period)
+	traders, err := s.store.Trader().List(userID)
+	if err != nil {
+		SafeInternalError(c, "Failed to get AI cost summary", err)
+		return
+	}
+	traderIDs := make([]string, 0, len(traders))
+	for _, t := range traders {
+		traderIDs = append(traderIDs, t.ID)
+	}
+
+	total, count, byModel := s.store.AICharge().GetSummary(traderIDs, period)
+
+	c.JSON(http.StatusOK, gin.H{
+		"total":           total,
+		"count":           count,
+		"by_model":        byModel,
+		"tokens":          s.store.AICharge().GetTokenTotals(traderIDs, period),
+		"by_model_tokens": s.store.AICharge().GetTokensByModel(traderIDs, period),
+		"budgets":         s.aiBudgetStates(userID),
+	})
+}
+
+// handleListAIPrices returns the token price table used for cost tracking,
+// with the user's overrides applied
+func (s *Server) handleListAIPrices(c *gin.Context) {
+	prices, err := s.store.AICharge().ListPrices(c.GetString("user_id"))
+	if err != nil {
+		SafeInternalError(c, "Failed to list AI prices", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"prices": prices})
+}
+
+// handleSetAIPrice overrides the token price of a model for the user
+func (s *Server) handleSetAIPrice(c *gin.Context) {
+	var req struct {
+		Model string `json:"model"`
+		store.TokenPrice
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	price := &store.AIModelPrice{
+		UserID:     c.GetString("user_id"),
+		Model:      req.Model,
+		TokenPrice: req.TokenPrice,
+	}
+	if err := s.store.AICharge().SetPrice(price); err != nil {
+		SafeBadRequest(c, SanitizeError(err, "Invalid AI price"))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"price": price})
+}
+
+// handleDeleteAIPrice removes the user's price override of ?model=
+func (s *Server) handleDeleteAIPrice(c *gin.Context) {
+	err := s.store.AICharge().DeletePrice(c.GetString("user_id"), c.Query("model"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		SafeNotFound(c, "AI price override")
+		return
+	}
+	if err != nil {
+		SafeInternalError(c, "Failed to delete AI price", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "AI price override deleted"})
+}
+
+// aiBudgetStates evaluates every budget of a user: "user" for the user-wide
+// budget, trader IDs for per-trader budgets
+func (s *Server) aiBudgetStates(userID string) map[string]*store.AIBudgetStatus {
+	states := make(map[string]*store.AIBudgetStatus)
+	budgets, err := s.store.AIBudget().List(userID)
+	if err != nil {
+		logger.Warnf("⚠️ Failed to list AI budgets: %v", err)
+		return states
+	}
+	for _, budget := range budgets {
+		key := budget.TraderID
+		if key == "" {
+			key = "user"
+		}
+		if state, err := s.store.AIBudget().Evaluate(userID, budget.TraderID); err == nil {
+			states[key] = state
+		}
+	}
+	return states
+}
+
+// handleListAIBudgets returns the user's AI spend budgets and their state
+func (s *Server) handleListAIBudgets(c *gin.Context) {
+	userID := c.GetString("user_id")
 
+	budgets, err := s.store.AIBudget().List(userID)
+	if err != nil {
+		SafeInternalError(c, "Failed to list AI budgets", err)
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{
-		"total":    total,
-		"count":    count,
-		"by_model": byModel,
+		"budgets": budgets,
+		"states":  s.aiBudgetStates(userID),
 	})
 }
+
+// handleSetAIBudget creates or replaces the user-wide budget, or a trader's
+// budget when trader_id is set. Running traders apply it on their next cycle.
+func (s *Server) handleSetAIBudget(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req struct {
+		TraderID               string  `json:"trader_id"`
+		DailyLimitUSD          float64 `json:"daily_limit_usd"`
+		MonthlyLimitUSD        float64 `json:"monthly_limit_usd"`
+		DegradeMode            string  `json:"degrade_mode"`
+		DegradeIntervalMinutes int     `json:"degrade_interval_minutes"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	if req.TraderID != "" {
+		traderRecord, err := s.store.Trader().GetByID(req.TraderID)
+		if err != nil || traderRecord.UserID != userID {
+			SafeNotFound(c, "Trader")
+			return
+		}
+	}
+
+	budget := &store.AIBudget{
+		UserID:                 userID,
+		TraderID:               req.TraderID,
+		DailyLimitUSD:          req.DailyLimitUSD,
+		MonthlyLimitUSD:        req.MonthlyLimitUSD,
+		DegradeMode:            req.DegradeMode,
+		DegradeIntervalMinutes: req.DegradeIntervalMinutes,
+	}
+	if err := s.store.AIBudget().Set(budget); err != nil {
+		SafeBadRequest(c, SanitizeError(err, "Invalid AI budget"))
+		return
+	}
+
+	state, _ := s.store.AIBudget().Evaluate(userID, req.TraderID)
+	c.JSON(http.StatusOK, gin.H{
+		"budget": budget,
+		"state":  state,
+	})
+}
+
+// handleDeleteAIBudget removes the user-wide budget, or a trader's budget
+// with ?trader_id=
+func (s *Server) handleDeleteAIBudget(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if err := s.store.AIBudget().Delete(userID, c.Query("trader_id")); err != nil {
+		SafeInternalError(c, "Failed to delete AI budget", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "AI budget deleted"})
+}
@@ -9,6 +9,7 @@ import (
 	"nofx/config"
 	"nofx/crypto"
 	"nofx/logger"
+	"nofx/secrets"
 	"nofx/security"
 	"nofx/store"
 	"nofx/wallet"
@@ -36,6 +37,7 @@ type SafeModelConfig struct {
 	CustomModelName string `json:"customModelName"` // Custom model name (not sensitive)
 	WalletAddress   string `json:"walletAddress,omitempty"`
 	BalanceUSDC     string `json:"balanceUsdc,omitempty"`
+	APIKeyRef       string `json:"apiKeyRef,omitempty"` // set when the key is a reference to an external secret backend
 }
 
 // ModelConfigUpdate is a single model's update payload. It is a named type
@@ -91,10 +93,13 @@ func (s *Server) handleGetModelConfigs(c *gin.Context) {
 			CustomAPIURL:    model.CustomAPIURL,
 			CustomModelName: model.CustomModelName,
 		}
+		if secrets.IsReference(model.APIKey.String()) {
+			safeModel.APIKeyRef = model.APIKey.String()
+		}
 
 		if model.Provider == "claw402" {
 			if privateKey := strings.TrimSpace(model.APIKey.String()); privateKey != "" {
-				if walletAddress, addrErr := walletAddressFromPrivateKey(privateKey); addrErr == nil {
+				if walletAddress, addrErr := walletAddressFromStoredKey(userID, privateKey); addrErr == nil {
 					safeModel.WalletAddress = walletAddress
 					safeModel.BalanceUSDC = wallet.QueryUSDCBalanceStr(walletAddress)
 				} else {
@@ -208,6 +213,11 @@ func (s *Server) handleUpdateModelConfigs(c *gin.Context) {
 			}
 		}
 
+		if err := validateSecretRefs(userID, map[string]string{"api_key": modelData.APIKey}); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid secret reference for model %s: %v", modelID, err)})
+			return
+		}
+
 		// Find traders using this AI model BEFORE updating
 		for candidateID := range modelIDCandidates(modelID) {
 			traders, _ := s.store.Trader().ListByAIModelID(userID, candidateID)
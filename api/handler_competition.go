@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"nofx/kernel"
 	"nofx/logger"
 	"nofx/store"
 
@@ -77,6 +78,11 @@ func (s *Server) handleLatestDecisions(c *gin.Context) {
 	c.JSON(http.StatusOK, records)
 }
 
+// handleDecisionParseStats decision output parse outcomes per AI provider since startup
+func (s *Server) handleDecisionParseStats(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"providers": kernel.GetDecisionParseStats()})
+}
+
 // handleStatistics Statistics information
 func (s *Server) handleStatistics(c *gin.Context) {
 	_, traderID, err := s.getTraderFromQuery(c)
@@ -45,6 +45,8 @@ type SafeExchangeConfig struct {
 	LighterWalletAddr          string `json:"lighterWalletAddr"` // LIGHTER wallet address (not sensitive)
 	HasLighterPrivateKey       bool   `json:"has_lighter_private_key"`
 	HasLighterAPIKey           bool   `json:"has_lighter_api_key_private_key"`
+	// Secret fields stored as references to an external backend, field -> reference
+	SecretRefs map[string]string `json:"secret_refs,omitempty"`
 }
 
 func safeExchangeConfigFromStore(exchange *store.Exchange) SafeExchangeConfig {
@@ -68,6 +70,14 @@ func safeExchangeConfigFromStore(exchange *store.Exchange) SafeExchangeConfig {
 		LighterWalletAddr:          exchange.LighterWalletAddr,
 		HasLighterPrivateKey:       exchange.LighterPrivateKey != "",
 		HasLighterAPIKey:           exchange.LighterAPIKeyPrivateKey != "",
+		SecretRefs: secretRefs(map[string]crypto.EncryptedString{
+			"api_key":                     exchange.APIKey,
+			"secret_key":                  exchange.SecretKey,
+			"passphrase":                  exchange.Passphrase,
+			"aster_private_key":           exchange.AsterPrivateKey,
+			"lighter_private_key":         exchange.LighterPrivateKey,
+			"lighter_api_key_private_key": exchange.LighterAPIKeyPrivateKey,
+		}),
 	}
 }
 
@@ -295,6 +305,18 @@ func (s *Server) handleUpdateExchangeConfigs(c *gin.Context) {
 			return
 		}
 
+		if err := validateSecretRefs(userID, map[string]string{
+			"api_key":                     exchangeData.APIKey,
+			"secret_key":                  exchangeData.SecretKey,
+			"passphrase":                  exchangeData.Passphrase,
+			"aster_private_key":           exchangeData.AsterPrivateKey,
+			"lighter_private_key":         exchangeData.LighterPrivateKey,
+			"lighter_api_key_private_key": exchangeData.LighterAPIKeyPrivateKey,
+		}); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid secret reference for exchange %s: %v", exchangeID, err)})
+			return
+		}
+
 		// Find traders using this exchange BEFORE updating
 		traders, _ := s.store.Trader().ListByExchangeID(userID, exchangeID)
 		for _, t := range traders {
@@ -406,6 +428,18 @@ func (s *Server) handleCreateExchange(c *gin.Context) {
 		return
 	}
 
+	if err := validateSecretRefs(userID, map[string]string{
+		"api_key":                     req.APIKey,
+		"secret_key":                  req.SecretKey,
+		"passphrase":                  req.Passphrase,
+		"aster_private_key":           req.AsterPrivateKey,
+		"lighter_private_key":         req.LighterPrivateKey,
+		"lighter_api_key_private_key": req.LighterAPIKeyPrivateKey,
+	}); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid secret reference: %v", err)})
+		return
+	}
+
 	// Exchange configs only persist once complete; persisted configs are always enabled.
 	effectiveHyperliquidUnifiedAcct := effectiveHyperliquidUnifiedAccount(req.ExchangeType, req.HyperliquidUnifiedAcct)
 	id, err := s.store.Exchange().Create(
@@ -0,0 +1,390 @@
+package api
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"nofx/store"
+	"nofx/trader"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Limits of a position's review notes
+const (
+	maxJournalNoteLen = 10000
+	maxJournalTags    = 20
+	maxJournalTagLen  = 32
+)
+
+// traderHistoryFilters trader IDs and legacy ID patterns whose positions
+// belong to the trader's history. One-click "NOFX Autopilot" relaunches
+// create fresh trader rows, but the closed positions stay under the old
+// generated IDs (which embed userID + "claw402"), see handler_order.go.
+func traderHistoryFilters(t *trader.AutoTrader, userID string) ([]string, []string) {
+	traderIDs := []string{t.GetID()}
+	var traderIDPatterns []string
+	if strings.EqualFold(strings.TrimSpace(t.GetName()), "NOFX Autopilot") && strings.TrimSpace(userID) != "" {
+		traderIDPatterns = append(traderIDPatterns, "%_"+userID+"_claw402_%")
+	}
+	return traderIDs, traderIDPatterns
+}
+
+// journalTrader resolves the ?trader_id= trader and its store, writing the
+// error response when it cannot
+func (s *Server) journalTrader(c *gin.Context) (*trader.AutoTrader, *store.Store, bool) {
+	_, traderID, err := s.getTraderFromQuery(c)
+	if err != nil {
+		SafeBadRequest(c, "Invalid trader ID")
+		return nil, nil, false
+	}
+	t, err := s.traderManager.GetTrader(traderID)
+	if err != nil {
+		SafeNotFound(c, "Trader")
+		return nil, nil, false
+	}
+	st := t.GetStore()
+	if st == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Store not available"})
+		return nil, nil, false
+	}
+	return t, st, true
+}
+
+// journalFilterFromQuery parses the journal filters shared by the list and
+// the export
+func journalFilterFromQuery(c *gin.Context, t *trader.AutoTrader, defaultLimit int) (store.JournalFilter, error) {
+	filter := store.JournalFilter{
+		Symbol: c.Query("symbol"),
+		Tag:    c.Query("tag"),
+	}
+	filter.TraderIDs, filter.TraderIDPatterns = traderHistoryFilters(t, c.GetString("user_id"))
+	var err error
+	if filter.Since, err = parseAuditTime(c.Query("since")); err != nil {
+		return filter, fmt.Errorf("invalid since: %w", err)
+	}
+	if filter.Until, err = parseStatsUntil(c.Query("until")); err != nil {
+		return filter, fmt.Errorf("invalid until: %w", err)
+	}
+	filter.Limit = defaultLimit
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil && limit >= 0 {
+		filter.Limit = limit
+	}
+	return filter, nil
+}
+
+// handleJournal lists closed positions with the decisions that opened and
+// closed them and the user's notes, most recently closed first
+func (s *Server) handleJournal(c *gin.Context) {
+	t, st, ok := s.journalTrader(c)
+	if !ok {
+		return
+	}
+	filter, err := journalFilterFromQuery(c, t, 100)
+	if err != nil {
+		SafeBadRequest(c, err.Error())
+		return
+	}
+	entries, err := st.GetJournal(filter)
+	if err != nil {
+		SafeInternalError(c, "Get trade journal", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+		"count":   len(entries),
+	})
+}
+
+// handleJournalEntry returns the journal of one position, including the
+// chain-of-thought of its opening and closing cycles
+func (s *Server) handleJournalEntry(c *gin.Context) {
+	t, st, ok := s.journalTrader(c)
+	if !ok {
+		return
+	}
+	positionID, err := strconv.ParseInt(c.Param("position_id"), 10, 64)
+	if err != nil {
+		SafeBadRequest(c, "Invalid position ID")
+		return
+	}
+	traderIDs, traderIDPatterns := traderHistoryFilters(t, c.GetString("user_id"))
+	entry, err := st.GetJournalEntry(positionID, traderIDs, traderIDPatterns)
+	if err != nil {
+		SafeInternalError(c, "Get journal entry", err)
+		return
+	}
+	if entry == nil {
+		SafeNotFound(c, "Position")
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// handleUpdateJournalNote sets the review notes and tags of a position
+func (s *Server) handleUpdateJournalNote(c *gin.Context) {
+	t, st, ok := s.journalTrader(c)
+	if !ok {
+		return
+	}
+	positionID, err := strconv.ParseInt(c.Param("position_id"), 10, 64)
+	if err != nil {
+		SafeBadRequest(c, "Invalid position ID")
+		return
+	}
+	var req struct {
+		Note string   `json:"note"`
+		Tags []string `json:"tags"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request body")
+		return
+	}
+	if len(req.Note) > maxJournalNoteLen {
+		SafeBadRequest(c, fmt.Sprintf("Note is longer than %d characters", maxJournalNoteLen))
+		return
+	}
+	tags := store.NormalizeTags(req.Tags)
+	if len(tags) > maxJournalTags {
+		SafeBadRequest(c, fmt.Sprintf("At most %d tags are allowed", maxJournalTags))
+		return
+	}
+	for _, tag := range tags {
+		if len(tag) > maxJournalTagLen {
+			SafeBadRequest(c, fmt.Sprintf("Tag %q is longer than %d characters", tag, maxJournalTagLen))
+			return
+		}
+	}
+
+	traderIDs, traderIDPatterns := traderHistoryFilters(t, c.GetString("user_id"))
+	pos, err := st.Position().GetByTraderFilters(positionID, traderIDs, traderIDPatterns)
+	if err != nil {
+		SafeInternalError(c, "Get position", err)
+		return
+	}
+	if pos == nil {
+		SafeNotFound(c, "Position")
+		return
+	}
+	// Inside a workspace the note is the member's, not the owner's
+	note, err := st.Position().SaveNote(positionID, req.Note, tags, actorID(c))
+	if err != nil {
+		SafeInternalError(c, "Save journal note", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"position_id": note.PositionID,
+		"note":        note.Note,
+		"tags":        note.TagList(),
+		"updated_at":  note.UpdatedAt,
+	})
+}
+
+// handleExportJournal exports the journal as CSV (default) or Markdown for
+// periodic trade reviews
+func (s *Server) handleExportJournal(c *gin.Context) {
+	t, st, ok := s.journalTrader(c)
+	if !ok {
+		return
+	}
+	filter, err := journalFilterFromQuery(c, t, 0)
+	if err != nil {
+		SafeBadRequest(c, err.Error())
+		return
+	}
+	entries, err := st.GetJournal(filter)
+	if err != nil {
+		SafeInternalError(c, "Get trade journal", err)
+		return
+	}
+
+	stamp := time.Now().UTC().Format("20060102-150405")
+	switch format := c.DefaultQuery("format", "csv"); format {
+	case "md", "markdown":
+		body := renderJournalMarkdown(t.GetName(), filter, entries)
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="nofx-journal-%s.md"`, stamp))
+		c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(body))
+	case "csv":
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="nofx-journal-%s.csv"`, stamp))
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", renderJournalCSV(entries))
+	default:
+		SafeBadRequest(c, "Invalid format: use csv or md")
+	}
+}
+
+// renderJournalCSV one row per position with its opening and closing
+// decisions flattened into columns
+func renderJournalCSV(entries []store.JournalEntry) []byte {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{
+		"position_id", "symbol", "side", "leverage", "entry_time", "entry_price", "exit_time", "exit_price",
+		"net_pnl", "fee", "hold_mins", "close_reason", "mae_pct", "mfe_pct",
+		"open_time", "open_confidence", "open_reasoning", "open_price", "stop_loss", "take_profit",
+		"market_regime", "prompt_variant", "ai_model",
+		"close_time", "close_confidence", "close_reasoning", "tags", "note",
+	})
+	for _, e := range entries {
+		p := e.Position
+		row := []string{
+			strconv.FormatInt(p.ID, 10),
+			p.Symbol,
+			strings.ToLower(p.Side),
+			strconv.Itoa(p.Leverage),
+			formatJournalMillis(p.EntryTime),
+			formatJournalFloat(p.EntryPrice),
+			formatJournalMillis(p.ExitTime),
+			formatJournalFloat(p.ExitPrice),
+			strconv.FormatFloat(e.NetPnL, 'f', -1, 64),
+			strconv.FormatFloat(p.Fee, 'f', -1, 64),
+			strconv.FormatFloat(e.HoldMins, 'f', 1, 64),
+			csvText(p.CloseReason),
+		}
+		if e.Excursion != nil {
+			row = append(row, formatJournalFloat(e.Excursion.MAEPct), formatJournalFloat(e.Excursion.MFEPct))
+		} else {
+			row = append(row, "", "")
+		}
+		if d := e.Opening; d != nil {
+			row = append(row,
+				d.Timestamp.Format(time.RFC3339),
+				strconv.Itoa(d.Confidence),
+				csvText(d.Reasoning),
+				formatJournalFloat(d.Price),
+				formatJournalFloat(d.StopLoss),
+				formatJournalFloat(d.TakeProfit),
+				csvText(d.MarketRegime),
+				csvText(d.PromptVariant),
+				csvText(journalModel(d)),
+			)
+		} else {
+			row = append(row, "", "", "", "", "", "", "", "", "")
+		}
+		if d := e.Closing; d != nil {
+			row = append(row, d.Timestamp.Format(time.RFC3339), strconv.Itoa(d.Confidence), csvText(d.Reasoning))
+		} else {
+			row = append(row, "", "", "")
+		}
+		row = append(row, csvText(strings.Join(e.Tags, ";")), csvText(e.Note))
+		_ = w.Write(row)
+	}
+	w.Flush()
+	return buf.Bytes()
+}
+
+// csvText guards a free-text cell against formula injection: spreadsheets
+// evaluate cells starting with = + - @ (or a tab / carriage return before
+// one), so such cells are prefixed with a quote to keep them text
+func csvText(value string) string {
+	if value == "" {
+		return value
+	}
+	switch value[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + value
+	}
+	return value
+}
+
+// renderJournalMarkdown a review document with a summary and one section per
+// position
+func renderJournalMarkdown(traderName string, filter store.JournalFilter, entries []store.JournalEntry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Trade journal — %s\n\n", traderName)
+
+	period := "all time"
+	switch {
+	case !filter.Since.IsZero() && !filter.Until.IsZero():
+		period = fmt.Sprintf("%s to %s", filter.Since.Format("2006-01-02"), filter.Until.Format("2006-01-02"))
+	case !filter.Since.IsZero():
+		period = "since " + filter.Since.Format("2006-01-02")
+	case !filter.Until.IsZero():
+		period = "until " + filter.Until.Format("2006-01-02")
+	}
+	var wins int
+	var total float64
+	for _, e := range entries {
+		total += e.NetPnL
+		if e.NetPnL > 0 {
+			wins++
+		}
+	}
+	fmt.Fprintf(&b, "Period: %s · %d trades · %d wins · net P&L %+.2f USDT\n", period, len(entries), wins, total)
+
+	for _, e := range entries {
+		p := e.Position
+		fmt.Fprintf(&b, "\n## #%d %s %s · %+.2f USDT\n\n", p.ID, p.Symbol, strings.ToUpper(p.Side), e.NetPnL)
+		fmt.Fprintf(&b, "- Entry: %s @ %s · %dx\n", formatJournalMillis(p.EntryTime), formatJournalFloat(p.EntryPrice), p.Leverage)
+		exit := fmt.Sprintf("%s @ %s · held %s", formatJournalMillis(p.ExitTime), formatJournalFloat(p.ExitPrice),
+			time.Duration(e.HoldMins*float64(time.Minute)).Round(time.Minute))
+		if p.CloseReason != "" {
+			exit += " · " + p.CloseReason
+		}
+		fmt.Fprintf(&b, "- Exit: %s\n", exit)
+		if e.Excursion != nil {
+			fmt.Fprintf(&b, "- MAE %.2f%% · MFE %.2f%%\n", e.Excursion.MAEPct, e.Excursion.MFEPct)
+		}
+		if len(e.Tags) > 0 {
+			fmt.Fprintf(&b, "- Tags: %s\n", strings.Join(e.Tags, ", "))
+		}
+		writeJournalDecisionMarkdown(&b, "Why we entered", e.Opening)
+		writeJournalDecisionMarkdown(&b, "Why we exited", e.Closing)
+		if e.Note != "" {
+			fmt.Fprintf(&b, "\n**Notes:** %s\n", e.Note)
+		}
+	}
+	return b.String()
+}
+
+func writeJournalDecisionMarkdown(b *strings.Builder, title string, d *store.JournalDecision) {
+	if d == nil {
+		return
+	}
+	var parts []string
+	if d.Confidence > 0 {
+		parts = append(parts, fmt.Sprintf("confidence %d", d.Confidence))
+	}
+	if d.MarketRegime != "" {
+		parts = append(parts, "regime "+d.MarketRegime)
+	}
+	if model := journalModel(d); model != "" {
+		parts = append(parts, model)
+	}
+	parts = append(parts, "cycle "+strconv.Itoa(d.CycleNumber))
+	fmt.Fprintf(b, "\n**%s** (%s)\n\n", title, strings.Join(parts, ", "))
+	if d.Reasoning != "" {
+		fmt.Fprintf(b, "> %s\n", strings.ReplaceAll(strings.TrimSpace(d.Reasoning), "\n", "\n> "))
+	}
+	if d.StopLoss > 0 || d.TakeProfit > 0 {
+		fmt.Fprintf(b, "\nPrice %s · SL %s · TP %s\n", formatJournalFloat(d.Price), formatJournalFloat(d.StopLoss), formatJournalFloat(d.TakeProfit))
+	}
+}
+
+func journalModel(d *store.JournalDecision) string {
+	switch {
+	case d.AIProvider != "" && d.AIModel != "":
+		return d.AIProvider + "/" + d.AIModel
+	default:
+		return d.AIProvider + d.AIModel
+	}
+}
+
+func formatJournalMillis(ms int64) string {
+	if ms <= 0 {
+		return ""
+	}
+	return time.UnixMilli(ms).UTC().Format(time.RFC3339)
+}
+
+// formatJournalFloat formats a price, leaving unset (zero) ones empty
+func formatJournalFloat(v float64) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"nofx/store"
+)
+
+func testJournalEntries() []store.JournalEntry {
+	entry := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	return []store.JournalEntry{{
+		Position: store.TraderPosition{
+			ID: 42, Symbol: "BTCUSDT", Side: "LONG", Leverage: 5, EntryPrice: 100, ExitPrice: 104,
+			EntryTime: entry.UnixMilli(), ExitTime: entry.Add(90 * time.Minute).UnixMilli(), CloseReason: "ai_close",
+		},
+		NetPnL:   4,
+		HoldMins: 90,
+		Opening: &store.JournalDecision{
+			CycleNumber: 10, Timestamp: entry, Action: "open_long", Confidence: 82,
+			Reasoning: "breakout above range,\nvolume confirms", Price: 99.8, StopLoss: 97, TakeProfit: 106,
+			MarketRegime: "trending", AIProvider: "deepseek", AIModel: "deepseek-chat",
+		},
+		Excursion: &store.PositionExcursion{PositionID: 42, MAEPct: 1.5, MFEPct: 6},
+		Note:      "good entry, early exit",
+		Tags:      []string{"breakout", "early-exit"},
+	}}
+}
+
+func TestRenderJournalCSV(t *testing.T) {
+	rows, err := csv.NewReader(strings.NewReader(string(renderJournalCSV(testJournalEntries())))).ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("rows = %d, want header + 1", len(rows))
+	}
+	row := make(map[string]string)
+	for i, col := range rows[0] {
+		row[col] = rows[1][i]
+	}
+	want := map[string]string{
+		"position_id":     "42",
+		"side":            "long",
+		"exit_time":       "2026-03-02T09:30:00Z",
+		"mae_pct":         "1.5",
+		"open_confidence": "82",
+		"open_reasoning":  "breakout above range,\nvolume confirms",
+		"ai_model":        "deepseek/deepseek-chat",
+		"close_reasoning": "",
+		"tags":            "breakout;early-exit",
+	}
+	for col, v := range want {
+		if row[col] != v {
+			t.Errorf("%s = %q, want %q", col, row[col], v)
+		}
+	}
+}
+
+func TestRenderJournalCSVEscapesFormulas(t *testing.T) {
+	entries := testJournalEntries()
+	entries[0].Note = `=HYPERLINK("http://evil.example","x")`
+	entries[0].Opening.Reasoning = "-2% below support"
+	entries[0].NetPnL = -4
+	rows, err := csv.NewReader(strings.NewReader(string(renderJournalCSV(entries)))).ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv: %v", err)
+	}
+	row := make(map[string]string)
+	for i, col := range rows[0] {
+		row[col] = rows[1][i]
+	}
+	if row["note"] != `'=HYPERLINK("http://evil.example","x")` || row["open_reasoning"] != "'-2% below support" {
+		t.Fatalf("note = %q, reasoning = %q; want both quoted as text", row["note"], row["open_reasoning"])
+	}
+	if row["net_pnl"] != "-4" {
+		t.Fatalf("net_pnl = %q, numbers must stay numbers", row["net_pnl"])
+	}
+}
+
+func TestRenderJournalMarkdown(t *testing.T) {
+	filter := store.JournalFilter{
+		Since: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2026, 3, 8, 23, 59, 59, 0, time.UTC),
+	}
+	md := renderJournalMarkdown("Swing BTC", filter, testJournalEntries())
+	for _, want := range []string{
+		"# Trade journal — Swing BTC",
+		"Period: 2026-03-02 to 2026-03-08 · 1 trades · 1 wins · net P&L +4.00 USDT",
+		"## #42 BTCUSDT LONG · +4.00 USDT",
+		"held 1h30m0s · ai_close",
+		"**Why we entered** (confidence 82, regime trending, deepseek/deepseek-chat, cycle 10)",
+		"> breakout above range,\n> volume confirms",
+		"Price 99.8 · SL 97 · TP 106",
+		"**Notes:** good entry, early exit",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown is missing %q:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "Why we exited") {
+		t.Error("a position without a closing decision should have no exit section")
+	}
+}
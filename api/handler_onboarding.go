@@ -3,6 +3,7 @@ package api
 import (
 	"bufio"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -11,12 +12,16 @@ import (
 
 	"nofx/logger"
 	"nofx/mcp/payment"
+	"nofx/secrets"
 	"nofx/wallet"
 
 	gethcrypto "github.com/ethereum/go-ethereum/crypto"
 	"github.com/gin-gonic/gin"
 )
 
+// errExternalWalletKey the user's claw402 wallet key is a secret reference
+var errExternalWalletKey = errors.New("claw402 wallet key is a secret reference")
+
 type beginnerOnboardingResponse struct {
 	Address           string `json:"address"`
 	PrivateKey        string `json:"private_key"`
@@ -61,6 +66,10 @@ func (s *Server) handleBeginnerOnboarding(c *gin.Context) {
 	}
 
 	privateKey, address, configuredModelID, reusedExisting, err := s.resolveBeginnerWallet(userID)
+	if errors.Is(err, errExternalWalletKey) {
+		c.JSON(http.StatusConflict, gin.H{"error": "the claw402 wallet key is kept in an external secret backend and cannot be exported"})
+		return
+	}
 	if err != nil {
 		logger.Errorf("Failed to resolve beginner wallet for user %s: %v", userID, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to prepare beginner wallet"})
@@ -133,7 +142,7 @@ func (s *Server) handleCurrentBeginnerWallet(c *gin.Context) {
 			continue
 		}
 
-		address, addrErr := walletAddressFromPrivateKey(privateKey)
+		address, addrErr := walletAddressFromStoredKey(userID, privateKey)
 		if addrErr != nil {
 			logger.Warnf("Failed to derive current beginner wallet for user %s: %v", userID, addrErr)
 			continue
@@ -186,6 +195,11 @@ func (s *Server) resolveBeginnerWallet(userID string) (privateKey string, addres
 		if existingKey == "" {
 			continue
 		}
+		// The onboarding response hands the key to the browser; a key kept in
+		// an external secret backend must never be resolved for that
+		if secrets.IsReference(existingKey) {
+			return "", "", "", false, errExternalWalletKey
+		}
 
 		addr, addrErr := walletAddressFromPrivateKey(existingKey)
 		if addrErr != nil {
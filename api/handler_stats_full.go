@@ -1,12 +1,23 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
+	"time"
+
+	"nofx/store"
 
 	"github.com/gin-gonic/gin"
 )
 
+// fullStatisticsResponse keeps the TraderStats fields at the top level, as
+// before the analytics were added
+type fullStatisticsResponse struct {
+	*store.TraderStats
+	Analytics *store.PerformanceAnalytics `json:"analytics"`
+}
+
 // handleStatisticsFull returns the full set of computed performance metrics for
 // a single trader: win rate, profit factor, Sharpe ratio, max drawdown, and the
 // average win/loss amounts. These are derived from the trader's CLOSED positions
@@ -16,6 +27,11 @@ import (
 //
 // The existing GET /statistics endpoint only returns cycle/position counts; this
 // endpoint exposes the richer trade-quality metrics the terminal dashboard needs.
+// "analytics" adds equity-curve ratios, MAE/MFE, expectancy, streaks, exposure
+// and per-setup attribution. ?since= and ?until= (RFC3339 or YYYY-MM-DD)
+// restrict everything to positions closed in that range. MAE/MFE of positions
+// that have none yet is computed in the background and shows up on a later
+// poll.
 func (s *Server) handleStatisticsFull(c *gin.Context) {
 	_, traderID, err := s.getTraderFromQuery(c)
 	if err != nil {
@@ -29,30 +45,99 @@ func (s *Server) handleStatisticsFull(c *gin.Context) {
 		return
 	}
 
-	store := trader.GetStore()
-	if store == nil {
+	st := trader.GetStore()
+	if st == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Store not available"})
 		return
 	}
 
+	since, err := parseAuditTime(c.Query("since"))
+	if err != nil {
+		SafeBadRequest(c, "Invalid since: use RFC3339 or YYYY-MM-DD")
+		return
+	}
+	until, err := parseStatsUntil(c.Query("until"))
+	if err != nil {
+		SafeBadRequest(c, "Invalid until: use RFC3339 or YYYY-MM-DD")
+		return
+	}
+
 	// Aggregate across the trader's historical IDs exactly like the position
-	// history endpoint (handler_order.go). One-click "NOFX Autopilot" relaunches
-	// create fresh trader rows, but the closed positions stay under the old
-	// generated IDs (which embed userID + "claw402"). Without this, a freshly
-	// relaunched Autopilot would report only the current incarnation's trades
-	// instead of its real lifetime history.
-	userID := c.GetString("user_id")
-	traderIDs := []string{trader.GetID()}
-	var traderIDPatterns []string
-	if strings.EqualFold(strings.TrimSpace(trader.GetName()), "NOFX Autopilot") && strings.TrimSpace(userID) != "" {
-		traderIDPatterns = append(traderIDPatterns, "%_"+userID+"_claw402_%")
-	}
-
-	stats, err := store.Position().GetFullStatsByTraderFilters(traderIDs, traderIDPatterns, trader.GetInitialBalance())
+	// history endpoint, so a freshly relaunched Autopilot reports its real
+	// lifetime history instead of only the current incarnation's trades.
+	traderIDs, traderIDPatterns := traderHistoryFilters(trader, c.GetString("user_id"))
+
+	filter := store.AnalyticsFilter{
+		TraderIDs:        traderIDs,
+		TraderIDPatterns: traderIDPatterns,
+		Since:            since,
+		Until:            until,
+		StartingEquity:   trader.GetInitialBalance(),
+	}
+	s.excursions.trigger(st, filter)
+
+	stats, analytics, err := st.GetPerformanceAnalytics(filter)
 	if err != nil {
 		SafeInternalError(c, "Get full statistics", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, stats)
+	c.JSON(http.StatusOK, fullStatisticsResponse{TraderStats: stats, Analytics: analytics})
+}
+
+// parseStatsUntil parses ?until=; a bare date includes that whole day
+func parseStatsUntil(value string) (time.Time, error) {
+	until, err := parseAuditTime(value)
+	if err != nil || until.IsZero() {
+		return until, err
+	}
+	if _, dateErr := time.Parse("2006-01-02", strings.TrimSpace(value)); dateErr == nil {
+		until = until.Add(24*time.Hour - time.Millisecond)
+	}
+	return until, nil
+}
+
+// computePositionExcursion fetches the klines of a position's hold and
+// measures its excursion on their lowest low and highest high
+func computePositionExcursion(pos *store.TraderPosition) (*store.PositionExcursion, error) {
+	entry := time.UnixMilli(pos.EntryTime).UTC()
+	exit := time.UnixMilli(pos.ExitTime).UTC()
+	timeframe := excursionTimeframe(exit.Sub(entry))
+
+	klines, err := fetchExcursionKlines(pos.Symbol, timeframe, entry, exit)
+	if err != nil {
+		return nil, err
+	}
+	low, high := 0.0, 0.0
+	for _, k := range klines {
+		if k.Low > 0 && (low == 0 || k.Low < low) {
+			low = k.Low
+		}
+		if k.High > high {
+			high = k.High
+		}
+	}
+	if low == 0 || high == 0 {
+		return nil, fmt.Errorf("%w: no %s klines between %s and %s", errNoExcursionKlines, timeframe, entry.Format(time.RFC3339), exit.Format(time.RFC3339))
+	}
+
+	mae, mfe := store.ComputeExcursion(pos.Side, pos.EntryPrice, low, high)
+	return &store.PositionExcursion{PositionID: pos.ID, MAEPct: mae, MFEPct: mfe, Timeframe: timeframe}, nil
+}
+
+// excursionTimeframe the finest timeframe that covers a hold in a few
+// kline requests (at most 1500 candles each)
+func excursionTimeframe(hold time.Duration) string {
+	switch {
+	case hold <= 24*time.Hour:
+		return "1m"
+	case hold <= 5*24*time.Hour:
+		return "5m"
+	case hold <= 15*24*time.Hour:
+		return "15m"
+	case hold <= 60*24*time.Hour:
+		return "1h"
+	default:
+		return "4h"
+	}
 }
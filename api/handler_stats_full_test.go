@@ -0,0 +1,123 @@
+package api
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"nofx/market"
+	"nofx/store"
+)
+
+func TestParseStatsUntilIncludesWholeDay(t *testing.T) {
+	until, err := parseStatsUntil("2026-03-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2026, 3, 2, 23, 59, 59, int(999*time.Millisecond), time.UTC); !until.Equal(want) {
+		t.Fatalf("until = %v, want %v", until, want)
+	}
+
+	until, err = parseStatsUntil("2026-03-02T12:00:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC); !until.Equal(want) {
+		t.Fatalf("until = %v, want %v", until, want)
+	}
+
+	if until, err = parseStatsUntil(""); err != nil || !until.IsZero() {
+		t.Fatalf("empty until = %v, %v; want zero", until, err)
+	}
+	if _, err = parseStatsUntil("yesterday"); err == nil {
+		t.Fatal("expected an error for an unparseable until")
+	}
+}
+
+func TestComputePositionExcursion(t *testing.T) {
+	entry := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	pos := &store.TraderPosition{
+		ID: 7, Symbol: "BTCUSDT", Side: "SHORT", EntryPrice: 100,
+		EntryTime: entry.UnixMilli(), ExitTime: entry.Add(3 * 24 * time.Hour).UnixMilli(),
+	}
+
+	orig := fetchExcursionKlines
+	defer func() { fetchExcursionKlines = orig }()
+	var gotTimeframe string
+	fetchExcursionKlines = func(symbol, timeframe string, start, end time.Time) ([]market.Kline, error) {
+		gotTimeframe = timeframe
+		return []market.Kline{{Low: 98, High: 103}, {Low: 92, High: 99}}, nil
+	}
+
+	e, err := computePositionExcursion(pos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotTimeframe != "5m" {
+		t.Fatalf("timeframe = %s, want 5m for a three-day hold", gotTimeframe)
+	}
+	if e.PositionID != 7 || e.MAEPct != 3 || e.MFEPct != 8 {
+		t.Fatalf("excursion = %+v, want MAE 3%% and MFE 8%% for the short", e)
+	}
+
+	fetchExcursionKlines = func(string, string, time.Time, time.Time) ([]market.Kline, error) {
+		return nil, errors.New("invalid symbol")
+	}
+	if _, err := computePositionExcursion(pos); err == nil {
+		t.Fatal("expected an error when klines cannot be fetched")
+	}
+}
+
+func TestExcursionBackfillRetriesTransientErrors(t *testing.T) {
+	st, err := store.New(t.TempDir() + "/nofx.db")
+	if err != nil {
+		t.Fatalf("store.New failed: %v", err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+	entry := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	for _, symbol := range []string{"BTCUSDT", "GONEUSDT"} {
+		pos := &store.TraderPosition{TraderID: "t1", Symbol: symbol, Side: "LONG", Quantity: 1, EntryPrice: 100, ExitPrice: 101,
+			EntryTime: entry.UnixMilli(), ExitTime: entry.Add(time.Hour).UnixMilli(), Status: "CLOSED"}
+		if err := st.GormDB().Create(pos).Error; err != nil {
+			t.Fatalf("create position: %v", err)
+		}
+	}
+
+	orig := fetchExcursionKlines
+	defer func() { fetchExcursionKlines = orig }()
+	calls := 0
+	fetchExcursionKlines = func(symbol, _ string, _, _ time.Time) ([]market.Kline, error) {
+		calls++
+		if symbol == "GONEUSDT" {
+			return nil, errors.New(`binance klines api returned status 400: {"code":-1121,"msg":"Invalid symbol."}`)
+		}
+		return nil, errors.New("binance klines api returned status 503: service unavailable")
+	}
+
+	b := newExcursionBackfiller()
+	filter := store.AnalyticsFilter{TraderIDs: []string{"t1"}}
+	now := time.Now()
+	b.run(st, filter, now)
+
+	missing, err := st.Position().ClosedPositionsWithoutExcursion(filter, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(missing) != 1 || missing[0].Symbol != "BTCUSDT" {
+		t.Fatalf("positions without excursion = %+v, want only the one that hit a transient error", missing)
+	}
+
+	// The failed position waits for its backoff before the next attempt
+	calls = 0
+	b.run(st, filter, now.Add(30*time.Second))
+	if calls != 0 {
+		t.Fatalf("%d kline fetches before the retry was due, want 0", calls)
+	}
+	fetchExcursionKlines = func(string, string, time.Time, time.Time) ([]market.Kline, error) {
+		return []market.Kline{{Low: 99, High: 104}}, nil
+	}
+	b.run(st, filter, now.Add(2*time.Minute))
+	if missing, _ = st.Position().ClosedPositionsWithoutExcursion(filter, 0); len(missing) != 0 {
+		t.Fatalf("positions without excursion after the retry = %+v, want none", missing)
+	}
+}
@@ -22,9 +22,9 @@ func (s *Server) handleGetTelegramConfig(c *gin.Context) {
 
 	// Mask bot token for security (show only last 6 chars)
 	tokenMasked := ""
-	if cfg.BotToken != "" {
-		if len(cfg.BotToken) > 6 {
-			tokenMasked = "***" + cfg.BotToken[len(cfg.BotToken)-6:]
+	if token := string(cfg.BotToken); token != "" {
+		if len(token) > 6 {
+			tokenMasked = "***" + token[len(token)-6:]
 		} else {
 			tokenMasked = "***"
 		}
@@ -96,7 +96,7 @@ func (s *Server) handleUpdateTelegramModel(c *gin.Context) {
 		return
 	}
 
-	if err := s.store.TelegramConfig().Save(cfg.BotToken, req.ModelID); err != nil {
+	if err := s.store.TelegramConfig().Save(string(cfg.BotToken), req.ModelID); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save model config"})
 		return
 	}
@@ -696,6 +696,14 @@ func (s *Server) handleUpdateTrader(c *gin.Context) {
 		return
 	}
 
+	// A version pin only applies to the strategy it was set for
+	if strategyID != existingTrader.StrategyID && existingTrader.StrategyVersion > 0 {
+		if err := s.store.Trader().UpdateStrategyVersion(userID, traderID, 0); err != nil {
+			SafeInternalError(c, "Failed to reset strategy version pin", err)
+			return
+		}
+	}
+
 	if resetInitialBalance {
 		logger.Infof("🔄 Exchange changed for trader %s, resetting stale initial_balance to 0", traderID)
 		if err := s.store.Trader().UpdateInitialBalance(userID, traderID, 0); err != nil {
@@ -761,6 +769,7 @@ func (s *Server) handleDeleteTrader(c *gin.Context) {
 
 	// Remove trader from memory
 	s.traderManager.RemoveTrader(traderID)
+	logger.ForgetTrader(traderID)
 
 	logger.Infof("✓ Trader deleted: %s", traderID)
 	c.JSON(http.StatusOK, gin.H{"message": "Trader deleted"})
@@ -77,3 +77,139 @@ func (s *Server) handleToggleCompetition(c *gin.Context) {
 		"show_in_competition": req.ShowInCompetition,
 	})
 }
+
+// handleSetTraderStrategyVersion pins a trader to a strategy version, or
+// unpins it with version 0. A pinned trader keeps running that version while
+// the strategy is edited; moving the pin forward promotes the new version.
+func (s *Server) handleSetTraderStrategyVersion(c *gin.Context) {
+	traderID := c.Param("id")
+	userID := c.GetString("user_id")
+
+	var req struct {
+		Version int `json:"version"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.Version < 0 {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	traderRecord, err := s.store.Trader().GetByID(traderID)
+	if err != nil || traderRecord.UserID != userID {
+		SafeNotFound(c, "Trader")
+		return
+	}
+	if traderRecord.StrategyID == "" {
+		SafeBadRequest(c, "Trader has no strategy")
+		return
+	}
+	var warnings []string
+	if req.Version > 0 {
+		v, err := s.store.Strategy().GetVersion(userID, traderRecord.StrategyID, req.Version)
+		if err != nil {
+			SafeNotFound(c, "Strategy version")
+			return
+		}
+		if warnings, err = strategyVersionWarnings(v.Config); err != nil {
+			SafeBadRequest(c, "Strategy version has an invalid config")
+			return
+		}
+	}
+
+	if err := s.store.Trader().UpdateStrategyVersion(userID, traderID, req.Version); err != nil {
+		SafeInternalError(c, "Failed to pin strategy version", err)
+		return
+	}
+
+	// The running trader picks up the pinned config at the start of its next cycle
+	if trader, err := s.traderManager.GetTrader(traderID); err == nil {
+		trader.SetPinnedStrategyVersion(req.Version)
+	}
+
+	logger.Infof("📌 Trader %s strategy version pin set to %d (0 = latest)", traderID, req.Version)
+	response := gin.H{
+		"message":          "Strategy version updated",
+		"strategy_id":      traderRecord.StrategyID,
+		"strategy_version": req.Version,
+	}
+	if len(warnings) > 0 {
+		response["warnings"] = warnings
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// handleSetTraderAIFallbacks sets the ordered AI models the trader falls over
+// to when its primary model is failing. The trader is reloaded so the new
+// chain takes effect immediately.
+func (s *Server) handleSetTraderAIFallbacks(c *gin.Context) {
+	traderID := c.Param("id")
+	userID := c.GetString("user_id")
+
+	var req struct {
+		ModelIDs []string `json:"model_ids"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	traderRecord, err := s.store.Trader().GetByID(traderID)
+	if err != nil || traderRecord.UserID != userID {
+		SafeNotFound(c, "Trader")
+		return
+	}
+
+	seen := make(map[string]bool)
+	modelIDs := make([]string, 0, len(req.ModelIDs))
+	for _, id := range req.ModelIDs {
+		if id == "" || seen[id] {
+			continue
+		}
+		if id == traderRecord.AIModelID {
+			SafeBadRequest(c, "The primary AI model cannot be its own fallback")
+			return
+		}
+		model, err := s.store.AIModel().Get(userID, id)
+		if err != nil || model.UserID != userID {
+			SafeNotFound(c, "AI model")
+			return
+		}
+		if !model.Enabled {
+			SafeBadRequest(c, "Fallback AI model "+model.Name+" is disabled")
+			return
+		}
+		seen[id] = true
+		modelIDs = append(modelIDs, id)
+	}
+
+	if err := s.store.Trader().UpdateFallbackAIModels(userID, traderID, modelIDs); err != nil {
+		SafeInternalError(c, "Failed to update AI fallbacks", err)
+		return
+	}
+
+	// Reload the trader so its AI client is rebuilt with the new chain
+	wasRunning := false
+	if memTrader, memErr := s.traderManager.GetTrader(traderID); memErr == nil {
+		if running, ok := memTrader.GetStatus()["is_running"].(bool); ok && running {
+			wasRunning = true
+		}
+	}
+	s.traderManager.RemoveTrader(traderID)
+	if err := s.traderManager.LoadUserTradersFromStore(s.store, userID); err != nil {
+		logger.Infof("⚠️ Failed to reload user traders into memory: %v", err)
+	}
+	if wasRunning {
+		if reloadedTrader, getErr := s.traderManager.GetTrader(traderID); getErr == nil {
+			go func() {
+				if runErr := reloadedTrader.Run(); runErr != nil {
+					logger.Infof("❌ Trader %s runtime error: %v", traderID, runErr)
+				}
+			}()
+		}
+	}
+
+	logger.Infof("🔀 Trader %s AI fallbacks set to %v", traderID, modelIDs)
+	c.JSON(http.StatusOK, gin.H{
+		"message":               "AI fallbacks updated",
+		"fallback_ai_model_ids": modelIDs,
+	})
+}
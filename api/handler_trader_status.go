@@ -3,10 +3,12 @@ package api
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"nofx/logger"
+	"nofx/secrets"
 	"nofx/store"
 	"nofx/trader"
 	"nofx/trader/aster"
@@ -22,6 +24,51 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// traderLogTailMax caps the lines returned by the log tail endpoint
+const traderLogTailMax = 1000
+
+// handleTraderLogs returns the latest log lines of a trader (?lines=200)
+func (s *Server) handleTraderLogs(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	// Verify trader belongs to current user
+	if _, err := s.store.Trader().GetFullConfig(userID, traderID); err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+
+	n := 200
+	if v := c.Query("lines"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "lines must be a positive number"})
+			return
+		}
+		n = parsed
+	}
+	if n > traderLogTailMax {
+		n = traderLogTailMax
+	}
+
+	lines := logger.RecentTraderLogs(traderID, n)
+	if lines == nil {
+		lines = []string{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"trader_id": traderID,
+		"format":    logFormat(),
+		"lines":     lines,
+	})
+}
+
+func logFormat() string {
+	if logger.IsJSON() {
+		return logger.FormatJSON
+	}
+	return logger.FormatText
+}
+
 // handleGetGridRiskInfo returns current risk information for a grid trader
 func (s *Server) handleGetGridRiskInfo(c *gin.Context) {
 	traderID := c.Param("id")
@@ -150,6 +197,12 @@ func (s *Server) handleClosePosition(c *gin.Context) {
 		return
 	}
 
+	exchangeCfg, err = secrets.ResolveExchange(exchangeCfg)
+	if err != nil {
+		SafeInternalError(c, "Failed to resolve exchange secrets", err)
+		return
+	}
+
 	// Create temporary trader to execute close position
 	var tempTrader trader.Trader
 	var createErr error
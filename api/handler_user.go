@@ -45,7 +45,8 @@ func (s *Server) handleLogout(c *gin.Context) {
 
 // handleRegister Handle user registration request.
 // handleRegister allows registration only when no users exist yet (first-time setup).
-// This is a single-user system; subsequent registrations are permanently closed.
+// Subsequent registrations are permanently closed; teammates join through
+// workspace invitations instead (see handleAcceptWorkspaceInvitation).
 func (s *Server) handleRegister(c *gin.Context) {
 	userCount, err := s.store.User().Count()
 	if err != nil {
@@ -181,7 +182,7 @@ func (s *Server) handleLogin(c *gin.Context) {
 
 // handleChangePassword changes the password for the currently authenticated user.
 func (s *Server) handleChangePassword(c *gin.Context) {
-	userID := c.GetString("user_id")
+	userID := actorID(c)
 	var req struct {
 		NewPassword string `json:"new_password" binding:"required,min=8"`
 	}
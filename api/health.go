@@ -0,0 +1,146 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"nofx/logger"
+	"nofx/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EnvMetricsToken is the bearer token required on /metrics. Without it the
+// endpoint only answers loopback and private-network peers, which is where
+// a Prometheus scraper normally runs.
+const EnvMetricsToken = "METRICS_TOKEN"
+
+// readyCheckTimeout bounds the database ping of the readiness probe
+const readyCheckTimeout = 3 * time.Second
+
+// readyCheck the result of one readiness check. The probe is unauthenticated,
+// so the error detail is only logged, never returned.
+type readyCheck struct {
+	Status string `json:"status"` // ok, degraded or fail
+	Error  string `json:"-"`
+}
+
+// handleReady Readiness probe: the database answers, the crypto service can
+// encrypt and decrypt, and every trader loaded. A trader that failed to load
+// only degrades readiness (200) unless ?strict=1 is given, so one broken
+// trader config does not take the whole instance out of a load balancer.
+// The response carries only the overall status and each check's status.
+func (s *Server) handleReady(c *gin.Context) {
+	checks := map[string]readyCheck{
+		"database": s.checkDatabase(c.Request.Context()),
+		"crypto":   s.checkCrypto(),
+	}
+
+	traders := readyCheck{Status: "ok"}
+	if s.traderManager != nil {
+		errs := s.traderManager.LoadErrors()
+		if len(errs) > 0 {
+			details := make([]string, 0, len(errs))
+			for id, err := range errs {
+				details = append(details, id+": "+err.Error())
+			}
+			sort.Strings(details)
+			traders = readyCheck{Status: "degraded", Error: "traders failed to load: " + strings.Join(details, "; ")}
+		}
+	}
+	checks["traders"] = traders
+
+	for name, check := range checks {
+		if check.Status != "ok" {
+			logger.Warnf("⚠️ Readiness check %s %s: %s", name, check.Status, check.Error)
+		}
+	}
+
+	status, code := readyStatus(checks, c.Query("strict") == "1")
+	c.JSON(code, gin.H{"status": status, "checks": checks})
+}
+
+// readyStatus combines the checks: any failure is 503, a degraded check is
+// 200 "degraded", or 503 in strict mode
+func readyStatus(checks map[string]readyCheck, strict bool) (string, int) {
+	status, code := "ok", http.StatusOK
+	for _, check := range checks {
+		switch {
+		case check.Status == "fail", check.Status == "degraded" && strict:
+			status, code = "fail", http.StatusServiceUnavailable
+		case check.Status == "degraded" && status == "ok":
+			status = "degraded"
+		}
+	}
+	return status, code
+}
+
+func (s *Server) checkDatabase(ctx context.Context) readyCheck {
+	if s.store == nil || s.store.DB() == nil {
+		return readyCheck{Status: "fail", Error: "database not initialized"}
+	}
+	ctx, cancel := context.WithTimeout(ctx, readyCheckTimeout)
+	defer cancel()
+	if err := s.store.DB().PingContext(ctx); err != nil {
+		return readyCheck{Status: "fail", Error: err.Error()}
+	}
+	return readyCheck{Status: "ok"}
+}
+
+func (s *Server) checkCrypto() readyCheck {
+	if s.cryptoHandler == nil || s.cryptoHandler.cryptoService == nil {
+		return readyCheck{Status: "fail", Error: "crypto service not initialized"}
+	}
+	cs := s.cryptoHandler.cryptoService
+	const probe = "nofx-ready"
+	enc, err := cs.EncryptForStorage(probe)
+	if err != nil {
+		return readyCheck{Status: "fail", Error: err.Error()}
+	}
+	if plain, err := cs.DecryptFromStorage(enc); err != nil || plain != probe {
+		if err == nil {
+			err = errors.New("decrypted value does not match")
+		}
+		return readyCheck{Status: "fail", Error: err.Error()}
+	}
+	return readyCheck{Status: "ok"}
+}
+
+// metricsAuthMiddleware guards /metrics with METRICS_TOKEN, or limits it to
+// loopback and private-network clients when no token is set. The client is
+// the TCP peer: forwarding headers are client-controlled, and a scraper
+// behind a reverse proxy should use the token.
+func metricsAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token := strings.TrimSpace(os.Getenv(EnvMetricsToken)); token != "" {
+			got := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				c.AbortWithStatus(http.StatusUnauthorized)
+				return
+			}
+			c.Next()
+			return
+		}
+		ip := net.ParseIP(c.RemoteIP())
+		if ip == nil || !(ip.IsLoopback() || ip.IsPrivate()) {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		c.Next()
+	}
+}
+
+// handleMetrics serves the Prometheus metrics
+func handleMetrics() gin.HandlerFunc {
+	h := metrics.Handler()
+	return func(c *gin.Context) {
+		h.ServeHTTP(c.Writer, c.Request)
+	}
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"nofx/crypto"
+	"nofx/manager"
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newReadyTestServer(t *testing.T) *Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	st, err := store.New(t.TempDir() + "/nofx.db")
+	if err != nil {
+		t.Fatalf("store.New failed: %v", err)
+	}
+	t.Cleanup(func() { st.Close() })
+
+	privateKey, _, err := crypto.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dataKey, err := crypto.GenerateDataKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(crypto.EnvRSAPrivateKey, privateKey)
+	t.Setenv(crypto.EnvDataEncryptionKey, dataKey)
+	cs, err := crypto.NewCryptoService()
+	if err != nil {
+		t.Fatalf("crypto service: %v", err)
+	}
+
+	s := &Server{
+		router:        gin.New(),
+		store:         st,
+		traderManager: manager.NewTraderManager(),
+		cryptoHandler: NewCryptoHandler(cs),
+	}
+	s.setupRoutes()
+	return s
+}
+
+func getReady(t *testing.T, s *Server, path string) (int, map[string]interface{}) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode %s: %v", w.Body.String(), err)
+	}
+	return w.Code, body
+}
+
+func TestReadyProbe(t *testing.T) {
+	s := newReadyTestServer(t)
+	if code, body := getReady(t, s, "/api/ready"); code != http.StatusOK || body["status"] != "ok" {
+		t.Fatalf("ready = %d %v", code, body)
+	}
+
+	s.store.Close()
+	code, body := getReady(t, s, "/api/ready")
+	if code != http.StatusServiceUnavailable || body["status"] != "fail" {
+		t.Fatalf("ready with a closed database = %d %v", code, body)
+	}
+	// The unauthenticated probe must not leak error text
+	db := body["checks"].(map[string]interface{})["database"].(map[string]interface{})
+	if len(db) != 1 || db["status"] != "fail" {
+		t.Fatalf("database check = %v, want only its status", db)
+	}
+}
+
+func TestReadyStatusTraderLoadErrors(t *testing.T) {
+	checks := map[string]readyCheck{
+		"database": {Status: "ok"},
+		"crypto":   {Status: "ok"},
+		"traders":  {Status: "degraded", Error: "traders failed to load: trader-x"},
+	}
+	if status, code := readyStatus(checks, false); status != "degraded" || code != http.StatusOK {
+		t.Fatalf("readyStatus = %s %d, want degraded 200", status, code)
+	}
+	if status, code := readyStatus(checks, true); status != "fail" || code != http.StatusServiceUnavailable {
+		t.Fatalf("strict readyStatus = %s %d, want fail 503", status, code)
+	}
+	checks["database"] = readyCheck{Status: "fail"}
+	if status, code := readyStatus(checks, false); status != "fail" || code != http.StatusServiceUnavailable {
+		t.Fatalf("readyStatus with a failed database = %s %d", status, code)
+	}
+}
+
+func TestReadyProbeWithoutCrypto(t *testing.T) {
+	s := newReadyTestServer(t)
+	s.cryptoHandler = nil
+	if code, _ := getReady(t, s, "/api/ready"); code != http.StatusServiceUnavailable {
+		t.Fatalf("ready without crypto = %d, want 503", code)
+	}
+}
+
+func TestMetricsEndpointAccess(t *testing.T) {
+	s := newReadyTestServer(t)
+	get := func(remote, auth string, headers ...string) int {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.RemoteAddr = remote
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		w := httptest.NewRecorder()
+		s.router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	t.Setenv(EnvMetricsToken, "")
+	if code := get("127.0.0.1:9000", ""); code != http.StatusOK {
+		t.Fatalf("loopback scrape = %d, want 200", code)
+	}
+	if code := get("203.0.113.7:9000", ""); code != http.StatusForbidden {
+		t.Fatalf("public scrape without token = %d, want 403", code)
+	}
+	if code := get("203.0.113.7:9000", "", "X-Forwarded-For", "127.0.0.1", "X-Real-IP", "127.0.0.1"); code != http.StatusForbidden {
+		t.Fatalf("public scrape with a spoofed forwarding header = %d, want 403", code)
+	}
+
+	t.Setenv(EnvMetricsToken, "scrape-secret")
+	if code := get("127.0.0.1:9000", ""); code != http.StatusUnauthorized {
+		t.Fatalf("scrape without bearer token = %d, want 401", code)
+	}
+	if code := get("203.0.113.7:9000", "Bearer scrape-secret"); code != http.StatusOK {
+		t.Fatalf("scrape with bearer token = %d, want 200", code)
+	}
+}
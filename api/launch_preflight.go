@@ -218,7 +218,7 @@ func checkLaunchAIWallet(model *store.AIModel) []LaunchCheck {
 		return []LaunchCheck{walletCheck, fundsCheck}
 	}
 
-	address, err := walletAddressFromPrivateKey(model.APIKey.String())
+	address, err := walletAddressFromStoredKey(model.UserID, model.APIKey.String())
 	if err != nil {
 		walletCheck.Status = launchCheckStatusFailed
 		walletCheck.Code = "AI_WALLET_INVALID_KEY"
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+
+	"nofx/crypto"
+	"nofx/secrets"
+)
+
+// validateSecretRefs checks the secret fields of a save request. Plain
+// values pass; references must be usable by this user. Returns a message
+// safe to show the client.
+func validateSecretRefs(userID string, fields map[string]string) error {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := secrets.Validate(userID, fields[name]); err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// secretRefs returns the fields that hold references, so the UI can show
+// where a secret lives. The references themselves are not secret.
+func secretRefs(fields map[string]crypto.EncryptedString) map[string]string {
+	var refs map[string]string
+	for name, value := range fields {
+		if secrets.IsReference(string(value)) {
+			if refs == nil {
+				refs = make(map[string]string)
+			}
+			refs[name] = string(value)
+		}
+	}
+	return refs
+}
+
+// walletAddressFromStoredKey derives the wallet address of a stored private
+// key, resolving it first when it is a reference. Only the address leaves
+// this function.
+func walletAddressFromStoredKey(userID, storedKey string) (string, error) {
+	privateKey, err := secrets.Resolve(userID, storedKey)
+	if err != nil {
+		return "", err
+	}
+	return walletAddressFromPrivateKey(privateKey)
+}
@@ -28,6 +28,8 @@ type Server struct {
 	port                      int
 	telegramReloadCh          chan<- struct{} // signal Telegram bot to reload
 	authLimiter               *ipRateLimiter  // per-IP throttle for login/register
+	excursions                *excursionBackfiller
+	marketPerformance         *marketPerformanceCache
 }
 
 // NewServer Creates API server
@@ -53,7 +55,9 @@ func NewServer(traderManager *manager.TraderManager, st *store.Store, cryptoServ
 		// Auth throttle: allow a small burst (typos / page reloads) then ~1
 		// attempt every 6s (10/min) sustained per IP. Generous for a human,
 		// hostile to online password brute-force.
-		authLimiter: newIPRateLimiter(1.0/6.0, 8),
+		authLimiter:       newIPRateLimiter(1.0/6.0, 8),
+		excursions:        newExcursionBackfiller(),
+		marketPerformance: newMarketPerformanceCache(),
 	}
 
 	// Setup routes
@@ -111,7 +115,7 @@ func corsMiddleware() gin.HandlerFunc {
 			}
 		}
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, "+workspaceHeader)
 		c.Writer.Header().Set("Access-Control-Max-Age", "600")
 
 		if c.Request.Method == "OPTIONS" {
@@ -130,11 +134,15 @@ func (s *Server) setupRoutes() {
 		s.authLimiter = newIPRateLimiter(1.0/6.0, 8)
 	}
 
+	// Prometheus metrics, outside /api so scrapers use the conventional path
+	s.router.GET("/metrics", metricsAuthMiddleware(), handleMetrics())
+
 	// API route group
 	api := s.router.Group("/api")
 	{
 		// Health check
 		api.Any("/health", s.handleHealth)
+		api.GET("/ready", s.handleReady)
 
 		// Admin login (used in admin mode, public)
 
@@ -177,7 +185,14 @@ func (s *Server) setupRoutes() {
 		s.route(api, "GET", "/symbols", "Available trading symbols", s.handleSymbols)
 
 		// Public strategy market (no authentication required)
-		s.route(api, "GET", "/strategies/public", "Public strategy market", s.handlePublicStrategies)
+		s.routeWithSchema(api, "GET", "/strategies/public", "Public strategy market",
+			`Query: ?sort=recent|stars|rating|sharpe&limit=<int, default 50, max 100>&offset=<int>
+Returns "strategies" plus "total", "limit" and "offset". Each entry has stars, forks, rating_avg, rating_count and performance (live stats of competition-visible traders running it, refreshed every 5 minutes).`,
+			s.handlePublicStrategies)
+		s.routeWithSchema(api, "GET", "/strategies/public/:id", "Public strategy market detail",
+			`Returns the market entry plus "lineage" (fork ancestors, nearest first), "ratings" and "performance.equity_curve" (daily, last 90 days) of competition-visible traders running it.`,
+			s.handlePublicStrategyDetail)
+		s.route(api, "GET", "/strategies/public/:id/export", "Export a public strategy as a portable bundle", s.handleExportPublicStrategy)
 		s.route(api, "POST", "/strategies/estimate-tokens", "Estimate token usage for a strategy config", s.handleEstimateTokens)
 
 		// Authentication related routes (no authentication required).
@@ -187,6 +202,9 @@ func (s *Server) setupRoutes() {
 		authRoutes := api.Group("/", rateLimitMiddleware(s.authLimiter))
 		s.route(authRoutes, "POST", "/register", "Register new user", s.handleRegister)
 		s.route(authRoutes, "POST", "/login", "User login, returns JWT token", s.handleLogin)
+		s.routeWithSchema(authRoutes, "POST", "/workspace-invitations/accept", "Accept a workspace invitation, returns JWT token",
+			`Body: {"token":"<invitation token>","password":"<string, min 8 chars — existing password, or the new account password if the invited email has no account yet>"}`,
+			s.handleAcceptWorkspaceInvitation)
 		// SECURITY: password/account recovery is NOT exposed over HTTP. An
 		// unauthenticated recovery endpoint is a remote auth-bypass on any
 		// public-facing deployment (the confirm phrase is in the frontend and
@@ -195,8 +213,10 @@ func (s *Server) setupRoutes() {
 		// `nofx reset-account` — which requires shell access the attacker lacks.
 		// See cli.go.
 
-		// Routes requiring authentication
-		protected := api.Group("/", s.authMiddleware())
+		// Routes requiring authentication. The workspace middleware scopes the
+		// request to a shared workspace when X-Workspace-ID is sent (workspace.go);
+		// the audit middleware records every successful mutating call (audit.go).
+		protected := api.Group("/", s.authMiddleware(), s.workspaceMiddleware(), s.auditMiddleware())
 		{
 			// Logout (add to blacklist)
 			s.route(protected, "POST", "/logout", "Logout (blacklist token)", s.handleLogout)
@@ -208,6 +228,34 @@ func (s *Server) setupRoutes() {
 				`Body: {"new_password":"<string, min 8 chars>"}`,
 				s.handleChangePassword)
 
+			// Team workspaces: members act on the owner's resources by sending
+			// X-Workspace-ID; roles are owner, operator and viewer.
+			s.route(protected, "GET", "/workspaces", "List workspaces the current user belongs to", s.handleListWorkspaces)
+			s.routeWithSchema(protected, "POST", "/workspaces", "Create a team workspace sharing the current user's resources",
+				`Body: {"name":"<string, required>"}`,
+				s.handleCreateWorkspace)
+			s.route(protected, "DELETE", "/workspaces/:id", "Delete a workspace (owner only)", s.handleDeleteWorkspace)
+			s.route(protected, "GET", "/workspaces/:id/members", "List workspace members and roles", s.handleListWorkspaceMembers)
+			s.routeWithSchema(protected, "PUT", "/workspaces/:id/members/:user_id", "Change a member's role (owner only)",
+				`Body: {"role":"owner|operator|viewer"}`,
+				s.handleUpdateWorkspaceMember)
+			s.route(protected, "DELETE", "/workspaces/:id/members/:user_id", "Remove a member, or leave the workspace", s.handleRemoveWorkspaceMember)
+			s.route(protected, "GET", "/workspaces/:id/invitations", "List pending invitations (owner only)", s.handleListWorkspaceInvitations)
+			s.routeWithSchema(protected, "POST", "/workspaces/:id/invitations", "Invite a teammate (owner only)",
+				`Body: {"email":"<string>","role":"owner|operator|viewer"}
+Returns the invitation token once; the invitee redeems it via POST /api/workspace-invitations/accept.`,
+				s.handleCreateWorkspaceInvitation)
+			s.route(protected, "DELETE", "/workspaces/:id/invitations/:invitation_id", "Revoke a pending invitation (owner only)", s.handleRevokeWorkspaceInvitation)
+			s.route(protected, "GET", "/workspaces/:id/audit", "Workspace audit trail (owner only, ?limit=&actor_id=)", s.handleWorkspaceAudit)
+
+			// Audit trail of configuration and control actions on the caller's resources
+			s.routeWithSchema(protected, "GET", "/audit", "Audit log of configuration and control actions",
+				`Query: ?actor_id=&source=web|telegram_agent|cli&action=<prefix, e.g. strategy. or trader.start>&target=<resource id>&since=<RFC3339|YYYY-MM-DD>&until=<...>&limit=<int, default 100>&offset=<int>
+Returns: {"entries":[{"id":<int>,"actor_id":"<string>","source":"<string>","action":"<string>","target":"<string>","status":<int>,"ip":"<string>","changes":[{"field":"<path>","before":<any>,"after":<any>}],"created_at":"<timestamp>"}],"count":<int>}
+Secret fields appear as "[REDACTED]".`,
+				s.handleListAudit)
+			s.route(protected, "GET", "/audit/export", "Export the audit log (?format=csv|json plus the /audit filters)", s.handleExportAudit)
+
 			// Server IP query (requires authentication, for whitelist configuration)
 			s.route(protected, "GET", "/server-ip", "Get server public IP (for exchange whitelist)", s.handleGetServerIP)
 
@@ -265,6 +313,20 @@ Body: {"symbol":"<string, e.g. BTCUSDT — must match an open position symbol fr
 				`:id = trader_id from GET /api/my-traders.
 Body: {"show_in_competition":<bool>}`,
 				s.handleToggleCompetition)
+			s.routeWithSchema(protected, "PUT", "/traders/:id/strategy-version", "Pin the trader to a strategy version (promote) or follow latest",
+				`:id = trader_id from GET /api/my-traders.
+Body: {"version":<int — version from GET /api/strategies/:id/versions; 0 = follow the latest saved version>}
+A pinned trader ignores later strategy edits until the pin is moved.`,
+				s.handleSetTraderStrategyVersion)
+			s.routeWithSchema(protected, "PUT", "/traders/:id/ai-fallbacks", "Set the trader's AI failover chain",
+				`:id = trader_id from GET /api/my-traders.
+Body: {"model_ids":["<ai model id from GET /api/models>", ...]} — tried in order when the primary model fails; [] clears the chain.
+Circuit breaker state is reported as "ai_failover" in GET /api/status.`,
+				s.handleSetTraderAIFallbacks)
+			s.routeWithSchema(protected, "GET", "/traders/:id/logs", "Tail a trader's recent log lines",
+				`:id = trader_id from GET /api/my-traders. Query: ?lines=<1-1000, default 200>.
+Returns: {"trader_id":"<string>","format":"text|json","lines":["<log line>", ...]} — oldest first; json lines carry trader_id, cycle, cycle_id, exchange and symbol.`,
+				s.handleTraderLogs)
 			s.routeWithSchema(protected, "GET", "/traders/:id/grid-risk", "Get grid trading risk info",
 				`:id = trader_id from GET /api/my-traders.`,
 				s.handleGetGridRiskInfo)
@@ -272,6 +334,17 @@ Body: {"show_in_competition":<bool>}`,
 			// AI cost tracking
 			s.route(protected, "GET", "/ai-costs", "Get AI call costs for a trader (?trader_id=xxx&period=today)", s.handleGetAICosts)
 			s.route(protected, "GET", "/ai-costs/summary", "Get AI cost summary (?period=today)", s.handleGetAICostsSummary)
+			s.route(protected, "GET", "/ai-costs/prices", "List per-model token prices used for AI cost tracking", s.handleListAIPrices)
+			s.routeWithSchema(protected, "PUT", "/ai-costs/prices", "Override the token price of a model",
+				`Body: {"model":"<model name as recorded in /api/ai-costs>","input_per_mtok":<USD per 1M prompt tokens>,"output_per_mtok":<USD per 1M completion tokens>,"cached_input_per_mtok":<USD per 1M cached prompt tokens, 0 = input price>,"cache_write_per_mtok":<USD per 1M cache-write tokens, 0 = input price>}`,
+				s.handleSetAIPrice)
+			s.route(protected, "DELETE", "/ai-costs/prices", "Remove a token price override (?model=)", s.handleDeleteAIPrice)
+			s.route(protected, "GET", "/ai-budgets", "List AI spend budgets and their state", s.handleListAIBudgets)
+			s.routeWithSchema(protected, "PUT", "/ai-budgets", "Set a daily/monthly AI spend budget for the user or a trader",
+				`Body: {"trader_id":"<optional — omit for the budget across all your traders>","daily_limit_usd":<float, 0 = unlimited>,"monthly_limit_usd":<float, 0 = unlimited>,"degrade_mode":"model|interval","degrade_interval_minutes":<int, 0 = twice the scan interval>}
+At 80% of a limit the trader switches to its cheapest fallback model ("model", falls back to "interval" without a cheaper model) or calls the AI less often ("interval"); at 100% it stops calling the AI until the period rolls over.`,
+				s.handleSetAIBudget)
+			s.route(protected, "DELETE", "/ai-budgets", "Delete an AI spend budget (?trader_id= for a trader budget)", s.handleDeleteAIBudget)
 
 			// AI model configuration
 			s.routeWithSchema(protected, "GET", "/models", "List AI model configs",
@@ -388,7 +461,8 @@ StrategyConfig fields:
   prompt_sections.decision_process: step-by-step decision-making framework`,
 				s.handleCreateStrategy)
 			s.routeWithSchema(protected, "PUT", "/strategies/:id", "Update an existing strategy — WORKFLOW: 1) GET /api/strategies/:id first to read current config 2) Merge your changes into the full config 3) PUT with complete merged config 4) GET again to verify saved values",
-				`Body: {"name":"<string>","description":"<string>","config":<complete StrategyConfig — same structure as POST /api/strategies>}
+				`Body: {"name":"<string>","description":"<string>","config":<complete StrategyConfig — same structure as POST /api/strategies>,"note":"<optional version note>"}
+Every save creates a new version; response includes "version".
 IMPORTANT: config is merged with existing values server-side, but always send the complete section you are modifying.
 After updating, always GET /api/strategies/:id to verify and show the user actual saved values.`,
 				s.handleUpdateStrategy)
@@ -403,6 +477,52 @@ After activating, create or update a trader with this strategy_id to apply it.`,
 			s.routeWithSchema(protected, "POST", "/strategies/:id/duplicate", "Duplicate an existing strategy",
 				`:id = EXACT id from GET /api/strategies. Creates a copy with " (copy)" appended to the name.`,
 				s.handleDuplicateStrategy)
+			s.route(protected, "GET", "/strategies/:id/export", "Export a strategy as a portable bundle (secrets stripped, signed if configured)", s.handleExportStrategy)
+			s.routeWithSchema(protected, "POST", "/strategies/import", "Import a strategy bundle as a new strategy",
+				`Body: the bundle JSON from GET /api/strategies/:id/export ({"format":"nofx-strategy-bundle","schema_version":<int>,"strategy":{...},"config":{...},"signature":{...optional}})
+Query: ?name=<optional name override>&dry_run=true (validate and report without creating)
+Returns: {"id":"<new strategy id>","version":1,"schema_version":<int>,"migrated":<bool>,"dropped_fields":["<dotted path>"],"signed":<bool>,"signer_fingerprint":"<hex>","trusted":<bool>,"warnings":["<string>"]}`,
+				s.handleImportStrategy)
+			s.routeWithSchema(protected, "GET", "/strategies/:id/versions", "List a strategy's saved versions",
+				`Returns: {"versions":[{"version":<int>,"author_id":"<string>","note":"<string>","name":"<string>","created_at":"<time>"}]} newest first.`,
+				s.handleListStrategyVersions)
+			s.route(protected, "GET", "/strategies/:id/versions/:version", "Get one strategy version with its config", s.handleGetStrategyVersion)
+			s.routeWithSchema(protected, "GET", "/strategies/:id/diff", "Diff two strategy versions",
+				`Query: ?from=<version>&to=<version, default current>
+Returns: {"from":<int>,"to":<int>,"changes":[{"field":"<dotted path, e.g. config.risk_control.max_positions>","before":<value>,"after":<value>}]}`,
+				s.handleDiffStrategyVersions)
+			s.routeWithSchema(protected, "POST", "/strategies/:id/rollback", "Roll a strategy back to an earlier version",
+				`Body: {"version":<int, required>,"note":"<optional>"}
+The restored config is saved as a new version. Unpinned running traders pick it up on their next cycle.`,
+				s.handleRollbackStrategy)
+
+			// Strategy market: publishing, forks, stars, ratings, subscriptions
+			s.routeWithSchema(protected, "POST", "/strategies/:id/publish", "Publish the current version of a strategy to the market",
+				`No request body. Makes the strategy public and its current version the published one.
+Subscribed forks are notified; forks subscribed with mode auto_update are updated to the new version.`,
+				s.handlePublishStrategy)
+			s.routeWithSchema(protected, "POST", "/strategies/:id/fork", "Fork a market strategy into a new private strategy",
+				`:id = id from GET /api/strategies/public (or one of your own strategies)
+Body: {"name":"<optional>","subscribe":"notify|auto_update (optional)"}
+Secrets are not copied. Returns: {"id":"<new strategy id>","forked_from_id":"<string>","forked_from_version":<int>}`,
+				s.handleForkStrategy)
+			s.route(protected, "PUT", "/strategies/:id/star", "Star a market strategy", s.handleStarStrategy)
+			s.route(protected, "DELETE", "/strategies/:id/star", "Remove star from a market strategy", s.handleStarStrategy)
+			s.routeWithSchema(protected, "PUT", "/strategies/:id/rating", "Rate a market strategy",
+				`Body: {"score":<int 1-5, required>,"review":"<optional, max 2000 chars>"}. Rating your own strategy is not allowed.`,
+				s.handleRateStrategy)
+			s.route(protected, "GET", "/strategy-subscriptions", "List fork subscriptions to upstream strategies", s.handleListStrategySubscriptions)
+			s.routeWithSchema(protected, "PUT", "/strategies/:id/subscription", "Subscribe a fork to new versions of its source strategy",
+				`:id = id of YOUR fork. Body: {"mode":"notify|auto_update"}`,
+				s.handleSubscribeStrategy)
+			s.route(protected, "DELETE", "/strategies/:id/subscription", "Unsubscribe a fork from its source strategy", s.handleSubscribeStrategy)
+			s.routeWithSchema(protected, "GET", "/notifications", "List notifications",
+				`Query: ?unread=true&limit=<int, default 50>
+Returns: {"notifications":[{"id":<int>,"kind":"<string>","title":"<string>","body":"<string>","ref":"<string>","read_at":"<time|null>","created_at":"<time>"}],"unread":<int>}`,
+				s.handleListNotifications)
+			s.routeWithSchema(protected, "POST", "/notifications/read", "Mark notifications as read",
+				`Body: {"id":<int>} marks one notification; empty body marks all.`,
+				s.handleMarkNotificationsRead)
 
 			// Data for specified trader (using query parameter ?trader_id=xxx)
 			// IMPORTANT: All ?trader_id= values must be the EXACT "trader_id" field from GET /api/my-traders
@@ -441,14 +561,38 @@ Returns: [{"id":"<string>","symbol":"<string>","action":"open_long|open_short|cl
 				`Query: ?trader_id=<EXACT trader_id from GET /api/my-traders>
 Returns the most recent AI decision for each symbol analyzed in the last scan cycle.`,
 				s.handleLatestDecisions)
+			s.routeWithSchema(protected, "GET", "/decisions/parse-stats", "AI decision output parse outcomes per provider",
+				`Counts since server start. Returns: {"providers":[{"provider":"<string>","calls":<int>,"structured":<int>,"structured_fallback":<int>,"text":<int>,"failures":<int>,"failure_rate":<float 0-1>}]}
+structured = parsed from native structured output; structured_fallback = structured reply recovered by the text parser; text = text-only provider.`,
+				s.handleDecisionParseStats)
 			s.routeWithSchema(protected, "GET", "/statistics", "Trading performance statistics",
 				`Query: ?trader_id=<EXACT trader_id from GET /api/my-traders>
 Returns: {"total_trades":<int>,"winning_trades":<int>,"win_rate":<float>,"total_pnl":<float>,"sharpe_ratio":<float>,"max_drawdown":<float>}`,
 				s.handleStatistics)
-			s.routeWithSchema(protected, "GET", "/statistics/full", "Full trade-quality metrics (win rate, profit factor, Sharpe, max drawdown)",
-				`Query: ?trader_id=<EXACT trader_id from GET /api/my-traders>
-Returns: {"total_trades","win_trades","loss_trades","win_rate","profit_factor","sharpe_ratio","total_pnl","total_fee","avg_win","avg_loss","max_drawdown_pct"}`,
+			s.routeWithSchema(protected, "GET", "/statistics/full", "Full trade-quality metrics and strategy analytics (Sharpe/Sortino/Calmar, MAE/MFE, attribution)",
+				`Query: ?trader_id=<EXACT trader_id from GET /api/my-traders>&since=<RFC3339|YYYY-MM-DD>&until=<RFC3339|YYYY-MM-DD> (range optional, filters by position close time)
+Returns: {"total_trades","win_trades","loss_trades","win_rate","profit_factor","sharpe_ratio","total_pnl","total_fee","avg_win","avg_loss","max_drawdown_pct",
+"analytics":{"equity_curve":{"return_pct","annualized_return_pct","volatility_pct","sharpe_ratio","sortino_ratio","calmar_ratio","max_drawdown_pct"},
+"expectancy","longest_win_streak","longest_loss_streak","exposure_pct","avg_hold_mins",
+"excursions":{"avg_mae_pct","avg_mfe_pct","winner_avg_mae_pct","loser_avg_mfe_pct","by_position":[...]},
+"attribution":{"symbol"|"side"|"hour_of_day"|"confidence"|"leverage"|"coin_source":[{"key","trades","wins","win_rate","total_pnl","avg_pnl"}]}}}`,
 				s.handleStatisticsFull)
+			s.routeWithSchema(protected, "GET", "/journal", "Trade journal: closed positions with the AI reasoning that opened and closed them",
+				`Query: ?trader_id=<EXACT trader_id from GET /api/my-traders>&since=<RFC3339|YYYY-MM-DD>&until=<...>&symbol=<e.g. BTCUSDT>&tag=<string>&limit=<int, default 100, 0 = all>
+Returns: {"entries":[{"position":{<closed position>},"net_pnl":<float>,"hold_mins":<float>,
+"opening":{"decision_id","cycle_number","timestamp","action","confidence","reasoning","price","stop_loss","take_profit","leverage","market_regime","prompt_variant","ai_provider","ai_model","strategy_id","strategy_version"}|null,
+"closing":{...}|null (null when closed by stop loss, take profit or by hand, see position.close_reason),
+"excursion":{"mae_pct","mfe_pct"},"note":"<string>","tags":["<string>"]}],"count":<int>}`,
+				s.handleJournal)
+			s.route(protected, "GET", "/journal/export", "Export the trade journal for reviews (?format=csv|md plus the /journal filters)", s.handleExportJournal)
+			s.routeWithSchema(protected, "GET", "/journal/:position_id", "Journal entry of one position, with the chain-of-thought of its opening and closing cycles",
+				`:position_id = position.id from GET /api/journal or /api/positions/history. Query: ?trader_id=<EXACT trader_id>
+Returns: one /journal entry; opening.cot_trace and closing.cot_trace hold the full AI reasoning of those cycles`,
+				s.handleJournalEntry)
+			s.routeWithSchema(protected, "PUT", "/journal/:position_id/notes", "Set review notes and tags on a position",
+				`Query: ?trader_id=<EXACT trader_id>
+Body: {"note":"<string, max 10000 chars>","tags":["<string, max 32 chars>", ... max 20]} — replaces the earlier note and tags; tags are lowercased`,
+				s.handleUpdateJournalNote)
 
 		}
 	}
@@ -691,6 +835,8 @@ func (s *Server) Start() error {
 	logger.Infof("🌐 API server starting at http://localhost%s", addr)
 	logger.Infof("📊 API Documentation:")
 	logger.Infof("  • GET  /api/health           - Health check")
+	logger.Infof("  • GET  /api/ready            - Readiness probe (database, crypto, traders loaded)")
+	logger.Infof("  • GET  /metrics              - Prometheus metrics (METRICS_TOKEN or private network)")
 	logger.Infof("  • GET  /api/traders          - Public AI trader leaderboard top 50 (no auth required)")
 	logger.Infof("  • GET  /api/competition      - Public competition data (no auth required)")
 	logger.Infof("  • GET  /api/top-traders      - Top 5 trader data (no auth required, for performance comparison)")
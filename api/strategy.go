@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	appconfig "nofx/config"
 	"nofx/kernel"
 	"nofx/logger"
 	"nofx/market"
@@ -32,6 +33,13 @@ func validateStrategyConfig(config *store.StrategyConfig) []string {
 		warnings = append(warnings, "NofxOS API key is not configured. NofxOS data sources may not work properly.")
 	}
 
+	// Market cap tier rules need a market cap source
+	if config.RiskControl.UsesMarketCap() {
+		if cfg := appconfig.Get(); cfg == nil || cfg.CoinankAPIKey == "" {
+			warnings = append(warnings, "Asset tiers use market cap rules but COINANK_API_KEY is not configured; those rules will not match any symbol.")
+		}
+	}
+
 	return warnings
 }
 
@@ -60,44 +68,6 @@ func (s *Server) handleEstimateTokens(c *gin.Context) {
 	c.JSON(http.StatusOK, estimate)
 }
 
-// handlePublicStrategies Get public strategies for strategy market (no auth required)
-func (s *Server) handlePublicStrategies(c *gin.Context) {
-	strategies, err := s.store.Strategy().ListPublic()
-	if err != nil {
-		SafeInternalError(c, "Failed to get public strategies", err)
-		return
-	}
-
-	// Convert to frontend format with visibility control
-	result := make([]gin.H, 0, len(strategies))
-	for _, st := range strategies {
-		item := gin.H{
-			"id":             st.ID,
-			"name":           st.Name,
-			"description":    st.Description,
-			"author_email":   "", // Will be filled if we have user info
-			"is_public":      st.IsPublic,
-			"config_visible": st.ConfigVisible,
-			"created_at":     st.CreatedAt,
-			"updated_at":     st.UpdatedAt,
-		}
-
-		// Only include config if config_visible is true
-		if st.ConfigVisible {
-			var config store.StrategyConfig
-			json.Unmarshal([]byte(st.Config), &config)
-			attachPublishConfig(&config, st)
-			item["config"] = config
-		}
-
-		result = append(result, item)
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"strategies": result,
-	})
-}
-
 // handleGetStrategies Get strategy list
 func (s *Server) handleGetStrategies(c *gin.Context) {
 	userID := c.GetString("user_id")
@@ -136,6 +106,7 @@ func (s *Server) handleGetStrategies(c *gin.Context) {
 			"is_public":      st.IsPublic,
 			"config_visible": st.ConfigVisible,
 			"config":         config,
+			"version":        st.CurrentVersion,
 			"created_at":     st.CreatedAt,
 			"updated_at":     st.UpdatedAt,
 		})
@@ -173,6 +144,7 @@ func (s *Server) handleGetStrategy(c *gin.Context) {
 		"is_active":   strategy.IsActive,
 		"is_default":  strategy.IsDefault,
 		"config":      config,
+		"version":     strategy.CurrentVersion,
 		"created_at":  strategy.CreatedAt,
 		"updated_at":  strategy.UpdatedAt,
 	})
@@ -244,7 +216,7 @@ func (s *Server) handleCreateStrategy(c *gin.Context) {
 		Config:        string(configJSON),
 	}
 
-	if err := s.store.Strategy().Create(strategy); err != nil {
+	if err := s.store.Strategy().CreateWithVersion(strategy, store.StrategyVersionMeta{AuthorID: actorID(c), Note: "created"}); err != nil {
 		SafeInternalError(c, "Failed to create strategy", err)
 		return
 	}
@@ -255,6 +227,7 @@ func (s *Server) handleCreateStrategy(c *gin.Context) {
 
 	response := gin.H{
 		"id":      strategy.ID,
+		"version": strategy.CurrentVersion,
 		"message": "Strategy created successfully",
 	}
 	if len(warnings) > 0 {
@@ -268,6 +241,7 @@ func (s *Server) handleCreateStrategy(c *gin.Context) {
 // The incoming config is merged with the existing one: top-level sections present in the
 // request overwrite the corresponding existing sections; absent sections are preserved.
 // This prevents partial updates from zeroing out unmentioned fields.
+// Every save is recorded as a new immutable version; "note" describes the change.
 func (s *Server) handleUpdateStrategy(c *gin.Context) {
 	userID := c.GetString("user_id")
 	strategyID := c.Param("id")
@@ -294,6 +268,7 @@ func (s *Server) handleUpdateStrategy(c *gin.Context) {
 		Config        json.RawMessage `json:"config"` // raw JSON so we can merge
 		IsPublic      bool            `json:"is_public"`
 		ConfigVisible bool            `json:"config_visible"`
+		Note          string          `json:"note"` // version note
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -350,7 +325,8 @@ func (s *Server) handleUpdateStrategy(c *gin.Context) {
 		ConfigVisible: req.ConfigVisible,
 	}
 
-	if err := s.store.Strategy().Update(strategy); err != nil {
+	version, err := s.store.Strategy().UpdateWithVersion(strategy, store.StrategyVersionMeta{AuthorID: actorID(c), Note: req.Note})
+	if err != nil {
 		SafeInternalError(c, "Failed to update strategy", err)
 		return
 	}
@@ -378,7 +354,7 @@ func (s *Server) handleUpdateStrategy(c *gin.Context) {
 	warnings := validateStrategyConfig(&mergedConfig)
 	warnings = append(warnings, store.StrategyClampWarnings(beforeClamp, mergedConfig, mergedConfig.Language)...)
 
-	response := gin.H{"message": "Strategy updated successfully"}
+	response := gin.H{"message": "Strategy updated successfully", "version": version.Version}
 	if len(warnings) > 0 {
 		response["warnings"] = warnings
 	}
@@ -636,7 +612,7 @@ func (s *Server) handleStrategyTestRun(c *gin.Context) {
 	for _, c := range candidates {
 		symbols = append(symbols, c.Symbol)
 	}
-	quantDataMap := engine.FetchQuantDataBatch(symbols)
+	quantDataMap := engine.FetchQuantDataBatch(context.Background(), symbols)
 	vergexDataMap := engine.FetchVergexDataBatch(context.Background(), symbols)
 
 	// Fetch OI ranking data (market-wide position changes)
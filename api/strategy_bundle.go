@@ -0,0 +1,172 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"nofx/config"
+	"nofx/logger"
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxStrategyBundleBytes bounds the size of an imported bundle
+const maxStrategyBundleBytes = 1 << 20
+
+var bundleFilenameUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// handleExportStrategy exports one of the user's strategies (or a public
+// strategy with a visible config) as a portable bundle. Secrets are stripped;
+// the bundle is signed when STRATEGY_SIGNING_KEY is configured, unless ?sign=false.
+func (s *Server) handleExportStrategy(c *gin.Context) {
+	strategy, err := s.store.Strategy().Get(c.GetString("user_id"), c.Param("id"))
+	if err != nil {
+		if strategy, err = s.store.Strategy().GetPublic(c.Param("id")); err != nil {
+			SafeNotFound(c, "Strategy")
+			return
+		}
+	}
+	s.writeStrategyBundle(c, strategy)
+}
+
+// handleExportPublicStrategy exports a public strategy without authentication,
+// so it can be imported on another instance
+func (s *Server) handleExportPublicStrategy(c *gin.Context) {
+	strategy, err := s.store.Strategy().GetPublic(c.Param("id"))
+	if err != nil {
+		SafeNotFound(c, "Strategy")
+		return
+	}
+	s.writeStrategyBundle(c, strategy)
+}
+
+func (s *Server) writeStrategyBundle(c *gin.Context, strategy *store.Strategy) {
+	bundle, err := store.NewStrategyBundle(strategy)
+	if err != nil {
+		SafeInternalError(c, "Failed to export strategy", err)
+		return
+	}
+	if cfg := config.Get(); cfg != nil && cfg.StrategySigningKey != nil && c.Query("sign") != "false" {
+		if err := bundle.Sign(cfg.StrategySigningKey); err != nil {
+			SafeInternalError(c, "Failed to sign strategy bundle", err)
+			return
+		}
+	}
+
+	name := strings.Trim(bundleFilenameUnsafe.ReplaceAllString(strategy.Name, "-"), "-")
+	if name == "" {
+		name = "strategy"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.nofx-strategy.json"`, name))
+	c.JSON(http.StatusOK, bundle)
+}
+
+// handleImportStrategy imports a strategy bundle as a new private strategy.
+// Older schema versions are migrated, secrets and unknown fields are dropped
+// and reported, and limits are clamped like any other save.
+// Query: ?name=<override>&dry_run=true (report only, nothing is created)
+func (s *Server) handleImportStrategy(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, maxStrategyBundleBytes+1))
+	if err != nil || len(data) == 0 {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	if len(data) > maxStrategyBundleBytes {
+		SafeBadRequest(c, "Strategy bundle is too large")
+		return
+	}
+
+	imported, err := store.ReadStrategyBundle(data)
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrStrategyBundleSignature):
+			SafeBadRequest(c, "Strategy bundle signature is invalid")
+		case errors.Is(err, store.ErrStrategyBundleSchema):
+			SafeBadRequest(c, err.Error())
+		default:
+			SafeBadRequest(c, "Invalid strategy bundle")
+		}
+		return
+	}
+
+	strategyConfig := imported.Config
+	beforeClamp := strategyConfig
+	strategyConfig.ClampLimits()
+	warnings := validateStrategyConfig(&strategyConfig)
+	warnings = append(warnings, store.StrategyClampWarnings(beforeClamp, strategyConfig, strategyConfig.Language)...)
+
+	trusted := false
+	if imported.Signed {
+		if cfg := config.Get(); cfg != nil {
+			for _, key := range cfg.StrategyTrustedKeys {
+				if key == imported.SignerKey {
+					trusted = true
+					break
+				}
+			}
+		}
+	}
+
+	name := strings.TrimSpace(c.Query("name"))
+	if name == "" {
+		name = imported.Meta.Name
+	}
+	if name == "" {
+		name = "Imported strategy"
+	}
+
+	response := gin.H{
+		"name":               name,
+		"schema_version":     imported.FromSchemaVersion,
+		"migrated":           imported.Migrated,
+		"dropped_fields":     imported.DroppedFields,
+		"signed":             imported.Signed,
+		"signer_fingerprint": imported.SignerFingerprint,
+		"trusted":            trusted,
+	}
+	if len(warnings) > 0 {
+		response["warnings"] = warnings
+	}
+	if c.Query("dry_run") == "true" {
+		response["dry_run"] = true
+		response["config"] = strategyConfig
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	strategy := &store.Strategy{
+		ID:            uuid.New().String(),
+		UserID:        userID,
+		Name:          name,
+		Description:   imported.Meta.Description,
+		ConfigVisible: true,
+	}
+	strategyConfig.PublishConfig = &store.PublishStrategyConfig{ConfigVisible: true}
+	if err := strategy.SetConfig(&strategyConfig); err != nil {
+		SafeInternalError(c, "Serialize configuration", err)
+		return
+	}
+
+	note := fmt.Sprintf("imported from bundle (schema v%d)", imported.FromSchemaVersion)
+	if imported.Signed {
+		note += ", signed by " + imported.SignerFingerprint
+	}
+	if err := s.store.Strategy().CreateWithVersion(strategy, store.StrategyVersionMeta{AuthorID: actorID(c), Note: note}); err != nil {
+		SafeInternalError(c, "Failed to import strategy", err)
+		return
+	}
+
+	logger.Infof("📥 Imported strategy %s (%s) for user %s: schema v%d, %d field(s) dropped", strategy.Name, strategy.ID, userID, imported.FromSchemaVersion, len(imported.DroppedFields))
+	response["id"] = strategy.ID
+	response["version"] = strategy.CurrentVersion
+	response["message"] = "Strategy imported successfully"
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,549 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"nofx/logger"
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// marketCurveDays is the window of the marketplace equity curve
+const marketCurveDays = 90
+
+const (
+	// marketPerformanceTTL how long the market list reuses live performance
+	marketPerformanceTTL = 5 * time.Minute
+	// Page size of the market list
+	marketDefaultLimit = 50
+	marketMaxLimit     = 100
+)
+
+// marketPerformanceCache live performance of public strategies for the
+// market list. The list is unauthenticated, so the trade stats of every
+// strategy are computed at most once per TTL rather than on each request.
+type marketPerformanceCache struct {
+	mu       sync.Mutex
+	perfs    map[string]*strategyPerformance
+	cachedAt time.Time
+}
+
+func newMarketPerformanceCache() *marketPerformanceCache {
+	return &marketPerformanceCache{perfs: make(map[string]*strategyPerformance)}
+}
+
+// get returns the performance of the strategies, computing the missing ones
+// and all of them once the cache is stale. Concurrent callers wait for one
+// refresh instead of each running their own.
+func (c *marketPerformanceCache) get(ids []string, compute func(string) *strategyPerformance) map[string]*strategyPerformance {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if time.Since(c.cachedAt) >= marketPerformanceTTL {
+		c.perfs = make(map[string]*strategyPerformance, len(ids))
+		c.cachedAt = time.Now()
+	}
+	result := make(map[string]*strategyPerformance, len(ids))
+	for _, id := range ids {
+		perf, ok := c.perfs[id]
+		if !ok {
+			perf = compute(id)
+			c.perfs[id] = perf
+		}
+		result[id] = perf
+	}
+	return result
+}
+
+// strategyPerformance live results of the traders running a public strategy.
+// Only traders shown in the competition are counted.
+type strategyPerformance struct {
+	TraderCount    int                 `json:"trader_count"`
+	TotalTrades    int                 `json:"total_trades"`
+	WinRate        float64             `json:"win_rate"`
+	TotalPnL       float64             `json:"total_pnl"`
+	SharpeRatio    float64             `json:"sharpe_ratio"`
+	MaxDrawdownPct float64             `json:"max_drawdown_pct"`
+	EquityCurve    []marketEquityPoint `json:"equity_curve,omitempty"`
+}
+
+// marketEquityPoint daily aggregate equity of all counted traders
+type marketEquityPoint struct {
+	Date      string  `json:"date"`
+	Equity    float64 `json:"equity"`
+	ReturnPct float64 `json:"return_pct"`
+}
+
+// strategyLivePerformance aggregates trade stats (and optionally the daily
+// equity curve) of the traders running a strategy
+func (s *Server) strategyLivePerformance(strategyID string, withCurve bool) (*strategyPerformance, error) {
+	traders, err := s.store.Trader().ListPublicByStrategyID(strategyID)
+	if err != nil {
+		return nil, err
+	}
+	perf := &strategyPerformance{TraderCount: len(traders)}
+	if len(traders) == 0 {
+		return perf, nil
+	}
+
+	traderIDs := make([]string, 0, len(traders))
+	startingEquity := 0.0
+	for _, t := range traders {
+		traderIDs = append(traderIDs, t.ID)
+		startingEquity += t.InitialBalance
+	}
+	stats, err := s.store.Position().GetFullStatsByTraderFilters(traderIDs, nil, startingEquity)
+	if err != nil {
+		return nil, err
+	}
+	perf.TotalTrades = stats.TotalTrades
+	perf.WinRate = stats.WinRate
+	perf.TotalPnL = stats.TotalPnL
+	perf.SharpeRatio = stats.SharpeRatio
+	perf.MaxDrawdownPct = stats.MaxDrawdownPct
+
+	if withCurve {
+		perf.EquityCurve = s.aggregateEquityCurve(traderIDs)
+	}
+	return perf, nil
+}
+
+// aggregateEquityCurve sums the last equity snapshot of each trader per UTC
+// day, carrying a trader's last value forward over days without snapshots
+func (s *Server) aggregateEquityCurve(traderIDs []string) []marketEquityPoint {
+	end := time.Now().UTC()
+	start := end.AddDate(0, 0, -marketCurveDays)
+
+	daily := make(map[string]map[string]float64) // trader -> day -> equity
+	daySet := make(map[string]bool)
+	for _, id := range traderIDs {
+		snapshots, err := s.store.Equity().GetByTimeRange(id, start, end)
+		if err != nil {
+			logger.Warnf("⚠️ Market equity curve: failed to load snapshots for %s: %v", id, err)
+			continue
+		}
+		days := make(map[string]float64)
+		for _, snap := range snapshots {
+			day := snap.Timestamp.UTC().Format("2006-01-02")
+			days[day] = snap.TotalEquity // ascending order: last one wins
+			daySet[day] = true
+		}
+		daily[id] = days
+	}
+
+	dates := make([]string, 0, len(daySet))
+	for day := range daySet {
+		dates = append(dates, day)
+	}
+	sort.Strings(dates)
+
+	last := make(map[string]float64)
+	curve := make([]marketEquityPoint, 0, len(dates))
+	base := 0.0
+	for _, day := range dates {
+		total := 0.0
+		for id, days := range daily {
+			if v, ok := days[day]; ok {
+				last[id] = v
+			}
+			total += last[id]
+		}
+		if base == 0 {
+			base = total
+		}
+		point := marketEquityPoint{Date: day, Equity: total}
+		if base > 0 {
+			point.ReturnPct = (total - base) / base * 100
+		}
+		curve = append(curve, point)
+	}
+	return curve
+}
+
+// marketStrategyItem the market view of a public strategy
+func (s *Server) marketStrategyItem(st *store.Strategy, stats *store.StrategyMarketStats, perf *strategyPerformance) gin.H {
+	name, description, configJSON, version := s.store.Strategy().GetPublishedConfig(st)
+	item := gin.H{
+		"id":                st.ID,
+		"name":              name,
+		"description":       description,
+		"author_email":      "", // Will be filled if we have user info
+		"is_public":         st.IsPublic,
+		"config_visible":    st.ConfigVisible,
+		"published_version": version,
+		"forked_from_id":    st.ForkedFromID,
+		"created_at":        st.CreatedAt,
+		"updated_at":        st.UpdatedAt,
+		"stars":             stats.Stars,
+		"forks":             stats.Forks,
+		"rating_avg":        stats.RatingAvg,
+		"rating_count":      stats.RatingCount,
+		"performance":       perf,
+	}
+
+	// Only include config if config_visible is true
+	if st.ConfigVisible {
+		var config store.StrategyConfig
+		json.Unmarshal([]byte(configJSON), &config)
+		attachPublishConfig(&config, st)
+		config.StripSecrets()
+		item["config"] = config
+	}
+	return item
+}
+
+// handlePublicStrategies Get public strategies for strategy market (no auth required).
+// Each entry carries stars, forks, ratings and verified live performance,
+// refreshed every few minutes.
+// Query: ?sort=recent|stars|rating|sharpe (default recent), ?limit= (default 50, max 100), ?offset=
+func (s *Server) handlePublicStrategies(c *gin.Context) {
+	strategies, err := s.store.Strategy().ListPublic()
+	if err != nil {
+		SafeInternalError(c, "Failed to get public strategies", err)
+		return
+	}
+
+	ids := make([]string, 0, len(strategies))
+	for _, st := range strategies {
+		ids = append(ids, st.ID)
+	}
+	stats, err := s.store.Strategy().MarketStats(ids)
+	if err != nil {
+		SafeInternalError(c, "Failed to get public strategies", err)
+		return
+	}
+
+	perfs := s.marketPerformance.get(ids, func(id string) *strategyPerformance {
+		perf, err := s.strategyLivePerformance(id, false)
+		if err != nil {
+			logger.Warnf("⚠️ Market performance for strategy %s failed: %v", id, err)
+			return &strategyPerformance{}
+		}
+		return perf
+	})
+
+	switch c.Query("sort") {
+	case "stars":
+		sort.SliceStable(strategies, func(i, j int) bool { return stats[strategies[i].ID].Stars > stats[strategies[j].ID].Stars })
+	case "rating":
+		sort.SliceStable(strategies, func(i, j int) bool {
+			return stats[strategies[i].ID].RatingAvg > stats[strategies[j].ID].RatingAvg
+		})
+	case "sharpe":
+		sort.SliceStable(strategies, func(i, j int) bool {
+			return perfs[strategies[i].ID].SharpeRatio > perfs[strategies[j].ID].SharpeRatio
+		})
+	}
+
+	total := len(strategies)
+	limit := parsePositiveInt(c.Query("limit"), marketDefaultLimit)
+	if limit > marketMaxLimit {
+		limit = marketMaxLimit
+	}
+	offset, _ := strconv.Atoi(c.Query("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > total {
+		offset = total
+	}
+	page := strategies[offset:]
+	if len(page) > limit {
+		page = page[:limit]
+	}
+
+	// Convert to frontend format with visibility control
+	result := make([]gin.H, 0, len(page))
+	for _, st := range page {
+		result = append(result, s.marketStrategyItem(st, stats[st.ID], perfs[st.ID]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"strategies": result,
+		"total":      total,
+		"limit":      limit,
+		"offset":     offset,
+	})
+}
+
+// handlePublicStrategyDetail market detail of a public strategy: performance
+// with equity curve, fork lineage and recent ratings (no auth required)
+func (s *Server) handlePublicStrategyDetail(c *gin.Context) {
+	st, err := s.store.Strategy().GetListed(c.Param("id"))
+	if err != nil {
+		SafeNotFound(c, "Strategy")
+		return
+	}
+
+	stats, err := s.store.Strategy().MarketStats([]string{st.ID})
+	if err != nil {
+		SafeInternalError(c, "Failed to get strategy", err)
+		return
+	}
+	perf, err := s.strategyLivePerformance(st.ID, true)
+	if err != nil {
+		SafeInternalError(c, "Failed to get strategy performance", err)
+		return
+	}
+	item := s.marketStrategyItem(st, stats[st.ID], perf)
+
+	lineage := make([]gin.H, 0)
+	for _, ancestor := range s.store.Strategy().Lineage(st) {
+		entry := gin.H{"id": ancestor.ID, "public": ancestor.IsPublic}
+		if ancestor.IsPublic {
+			entry["name"] = ancestor.Name
+		}
+		lineage = append(lineage, entry)
+	}
+	item["lineage"] = lineage
+	item["forked_from_version"] = st.ForkedFromVersion
+
+	ratings, err := s.store.Strategy().ListRatings(st.ID, 20)
+	if err == nil {
+		reviews := make([]gin.H, 0, len(ratings))
+		for _, r := range ratings {
+			reviews = append(reviews, gin.H{"score": r.Score, "review": r.Review, "updated_at": r.UpdatedAt})
+		}
+		item["ratings"] = reviews
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// handlePublishStrategy publishes the current version of a strategy to the
+// market and delivers it to subscribed forks
+func (s *Server) handlePublishStrategy(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	st, err := s.store.Strategy().Publish(userID, c.Param("id"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		SafeNotFound(c, "Strategy")
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": SanitizeError(err, "Failed to publish strategy")})
+		return
+	}
+
+	notified, updated := s.propagateStrategyPublish(st)
+	c.JSON(http.StatusOK, gin.H{
+		"message":            "Strategy published successfully",
+		"published_version":  st.PublishedVersion,
+		"subscribers_notify": notified,
+		"subscribers_update": updated,
+	})
+}
+
+// propagateStrategyPublish delivers a newly published version to every
+// subscription that has not seen it: auto_update forks get the new config
+// (keeping their own secrets), all subscribers get a notification
+func (s *Server) propagateStrategyPublish(source *store.Strategy) (notified, updated int) {
+	name, _, configJSON, version := s.store.Strategy().GetPublishedConfig(source)
+	subs, err := s.store.Strategy().PendingSubscriptions(source.ID, version)
+	if err != nil {
+		logger.Warnf("⚠️ Failed to load subscriptions of strategy %s: %v", source.ID, err)
+		return 0, 0
+	}
+
+	for _, sub := range subs {
+		fork, err := s.store.Strategy().Get(sub.UserID, sub.ForkID)
+		if err != nil {
+			// Fork was deleted: the subscription is meaningless now
+			_ = s.store.Strategy().Unsubscribe(sub.UserID, sub.ForkID)
+			continue
+		}
+
+		n := &store.Notification{
+			UserID: sub.UserID,
+			Kind:   store.NotificationStrategyUpdate,
+			Title:  fmt.Sprintf("%s v%d published", name, version),
+			Body:   fmt.Sprintf("A new version of \"%s\", which your strategy \"%s\" was forked from, is available.", name, fork.Name),
+			Ref:    fork.ID,
+		}
+		if sub.Mode == store.SubscriptionModeAutoUpdate {
+			note := fmt.Sprintf("auto-update from %s v%d", name, version)
+			if _, err := s.store.Strategy().ApplyUpstreamVersion(fork, configJSON, version, note); err != nil {
+				logger.Warnf("⚠️ Auto-update of fork %s failed: %v", fork.ID, err)
+				n.Body += " Automatic update failed; apply it manually."
+			} else {
+				n.Kind = store.NotificationStrategyUpdated
+				n.Body = fmt.Sprintf("Your strategy \"%s\" was updated to %s v%d. Traders pinned to a version keep running it until promoted.", fork.Name, name, version)
+				updated++
+			}
+		} else {
+			notified++
+		}
+		if err := s.store.Notification().Create(n); err != nil {
+			logger.Warnf("⚠️ Failed to create notification for user %s: %v", sub.UserID, err)
+		}
+		if err := s.store.Strategy().MarkSubscriptionSeen(sub.ID, version); err != nil {
+			logger.Warnf("⚠️ Failed to update subscription %d: %v", sub.ID, err)
+		}
+	}
+	return notified, updated
+}
+
+// handleForkStrategy forks a public strategy (or one of the user's own) with
+// lineage tracking. Body: {"name":"<optional>","subscribe":"notify|auto_update|<empty>"}
+func (s *Server) handleForkStrategy(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req struct {
+		Name      string `json:"name"`
+		Subscribe string `json:"subscribe"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	if req.Subscribe != "" && !store.IsValidSubscriptionMode(req.Subscribe) {
+		SafeBadRequest(c, "Invalid subscription mode")
+		return
+	}
+
+	fork, err := s.store.Strategy().Fork(userID, c.Param("id"), uuid.New().String(), req.Name, req.Subscribe)
+	if errors.Is(err, store.ErrStrategyNotPublic) {
+		SafeNotFound(c, "Strategy")
+		return
+	}
+	if err != nil {
+		SafeInternalError(c, "Failed to fork strategy", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":                  fork.ID,
+		"name":                fork.Name,
+		"forked_from_id":      fork.ForkedFromID,
+		"forked_from_version": fork.ForkedFromVersion,
+		"subscribed":          req.Subscribe,
+		"message":             "Strategy forked successfully",
+	})
+}
+
+// handleStarStrategy stars (PUT) or unstars (DELETE) a public strategy. Stars
+// and ratings are personal, so they go under the real caller even inside a
+// workspace.
+func (s *Server) handleStarStrategy(c *gin.Context) {
+	on := c.Request.Method == http.MethodPut
+	if err := s.store.Strategy().Star(actorID(c), c.Param("id"), on); err != nil {
+		if errors.Is(err, store.ErrStrategyNotPublic) {
+			SafeNotFound(c, "Strategy")
+			return
+		}
+		SafeInternalError(c, "Failed to update star", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"starred": on})
+}
+
+// handleRateStrategy rates a public strategy. Body: {"score":1-5,"review":"<optional>"}
+func (s *Server) handleRateStrategy(c *gin.Context) {
+	var req struct {
+		Score  int    `json:"score" binding:"required"`
+		Review string `json:"review"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	if len(req.Review) > 2000 {
+		SafeBadRequest(c, "Review is too long")
+		return
+	}
+
+	err := s.store.Strategy().Rate(actorID(c), c.Param("id"), req.Score, req.Review)
+	if errors.Is(err, store.ErrStrategyNotPublic) {
+		SafeNotFound(c, "Strategy")
+		return
+	}
+	if err != nil {
+		SafeBadRequest(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Rating saved"})
+}
+
+// handleListStrategySubscriptions lists the user's fork subscriptions
+func (s *Server) handleListStrategySubscriptions(c *gin.Context) {
+	subs, err := s.store.Strategy().ListSubscriptions(c.GetString("user_id"))
+	if err != nil {
+		SafeInternalError(c, "Failed to get subscriptions", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"subscriptions": subs})
+}
+
+// handleSubscribeStrategy subscribes a fork (:id) to its source (PUT, body
+// {"mode":"notify|auto_update"}) or removes the subscription (DELETE)
+func (s *Server) handleSubscribeStrategy(c *gin.Context) {
+	userID := c.GetString("user_id")
+	forkID := c.Param("id")
+
+	if c.Request.Method == http.MethodDelete {
+		if err := s.store.Strategy().Unsubscribe(userID, forkID); err != nil {
+			SafeInternalError(c, "Failed to unsubscribe", err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Unsubscribed"})
+		return
+	}
+
+	var req struct {
+		Mode string `json:"mode" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	sub, err := s.store.Strategy().Subscribe(userID, forkID, req.Mode)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		SafeNotFound(c, "Strategy")
+		return
+	}
+	if err != nil {
+		SafeBadRequest(c, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, sub)
+}
+
+// handleListNotifications lists the user's notifications. Query: ?unread=true&limit=<int>
+func (s *Server) handleListNotifications(c *gin.Context) {
+	userID := c.GetString("user_id")
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	list, err := s.store.Notification().List(userID, c.Query("unread") == "true", limit)
+	if err != nil {
+		SafeInternalError(c, "Failed to get notifications", err)
+		return
+	}
+	unread, _ := s.store.Notification().CountUnread(userID)
+	c.JSON(http.StatusOK, gin.H{"notifications": list, "unread": unread})
+}
+
+// handleMarkNotificationsRead marks one notification ({"id":<int>}) or all ({}) as read
+func (s *Server) handleMarkNotificationsRead(c *gin.Context) {
+	var req struct {
+		ID int64 `json:"id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	if err := s.store.Notification().MarkRead(c.GetString("user_id"), req.ID); err != nil {
+		SafeInternalError(c, "Failed to update notifications", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Notifications marked as read"})
+}
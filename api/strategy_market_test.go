@@ -0,0 +1,29 @@
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMarketPerformanceCacheComputesOncePerTTL(t *testing.T) {
+	cache := newMarketPerformanceCache()
+	computed := make(map[string]int)
+	compute := func(id string) *strategyPerformance {
+		computed[id]++
+		return &strategyPerformance{TraderCount: computed[id]}
+	}
+
+	cache.get([]string{"a", "b"}, compute)
+	perfs := cache.get([]string{"a", "b", "c"}, compute)
+	if computed["a"] != 1 || computed["b"] != 1 || computed["c"] != 1 {
+		t.Fatalf("computed = %v, want each strategy once while fresh", computed)
+	}
+	if perfs["a"].TraderCount != 1 || perfs["c"] == nil {
+		t.Fatalf("perfs = %+v", perfs)
+	}
+
+	cache.cachedAt = time.Now().Add(-marketPerformanceTTL)
+	if perfs = cache.get([]string{"a"}, compute); perfs["a"].TraderCount != 2 {
+		t.Fatalf("stale cache returned %+v, want a recomputed entry", perfs["a"])
+	}
+}
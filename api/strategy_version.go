@@ -0,0 +1,190 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"nofx/logger"
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// handleListStrategyVersions lists a strategy's version history (newest first, without configs)
+func (s *Server) handleListStrategyVersions(c *gin.Context) {
+	userID := c.GetString("user_id")
+	strategyID := c.Param("id")
+
+	versions, err := s.store.Strategy().ListVersions(userID, strategyID)
+	if err != nil {
+		SafeNotFound(c, "Strategy")
+		return
+	}
+
+	result := make([]gin.H, 0, len(versions))
+	for _, v := range versions {
+		result = append(result, gin.H{
+			"version":    v.Version,
+			"author_id":  v.AuthorID,
+			"note":       v.Note,
+			"name":       v.Name,
+			"created_at": v.CreatedAt,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"versions": result})
+}
+
+// handleGetStrategyVersion returns one version including its config
+func (s *Server) handleGetStrategyVersion(c *gin.Context) {
+	userID := c.GetString("user_id")
+	strategyID := c.Param("id")
+
+	versionNum, err := strconv.Atoi(c.Param("version"))
+	if err != nil || versionNum <= 0 {
+		SafeBadRequest(c, "Invalid version")
+		return
+	}
+	v, err := s.store.Strategy().GetVersion(userID, strategyID, versionNum)
+	if err != nil {
+		SafeNotFound(c, "Strategy version")
+		return
+	}
+	config, err := v.ParseConfig()
+	if err != nil {
+		SafeInternalError(c, "Failed to parse strategy version", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"strategy_id": v.StrategyID,
+		"version":     v.Version,
+		"author_id":   v.AuthorID,
+		"note":        v.Note,
+		"name":        v.Name,
+		"description": v.Description,
+		"config":      config,
+		"created_at":  v.CreatedAt,
+	})
+}
+
+// handleDiffStrategyVersions compares two versions field by field.
+// Query: ?from=<version>&to=<version, default current>. Secrets are redacted.
+func (s *Server) handleDiffStrategyVersions(c *gin.Context) {
+	userID := c.GetString("user_id")
+	strategyID := c.Param("id")
+
+	strategy, err := s.store.Strategy().Get(userID, strategyID)
+	if err != nil {
+		SafeNotFound(c, "Strategy")
+		return
+	}
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil || from <= 0 {
+		SafeBadRequest(c, "Invalid from version")
+		return
+	}
+	to := strategy.CurrentVersion
+	if raw := c.Query("to"); raw != "" {
+		if to, err = strconv.Atoi(raw); err != nil || to <= 0 {
+			SafeBadRequest(c, "Invalid to version")
+			return
+		}
+	}
+
+	fromVersion, err := s.store.Strategy().GetVersion(userID, strategyID, from)
+	if err != nil {
+		SafeNotFound(c, "Strategy version")
+		return
+	}
+	toVersion, err := s.store.Strategy().GetVersion(userID, strategyID, to)
+	if err != nil {
+		SafeNotFound(c, "Strategy version")
+		return
+	}
+
+	changes := store.DiffAuditState(strategyVersionState(fromVersion), strategyVersionState(toVersion))
+	c.JSON(http.StatusOK, gin.H{
+		"strategy_id": strategyID,
+		"from":        from,
+		"to":          to,
+		"changes":     changes,
+	})
+}
+
+// strategyVersionState is the diffable view of a version
+func strategyVersionState(v *store.StrategyVersion) gin.H {
+	var config any
+	_ = json.Unmarshal([]byte(v.Config), &config)
+	return gin.H{
+		"name":        v.Name,
+		"description": v.Description,
+		"config":      config,
+	}
+}
+
+// handleRollbackStrategy restores an earlier version. The restored state is
+// saved as a new version, so the rollback itself can be undone.
+func (s *Server) handleRollbackStrategy(c *gin.Context) {
+	userID := c.GetString("user_id")
+	strategyID := c.Param("id")
+
+	var req struct {
+		Version int    `json:"version" binding:"required"`
+		Note    string `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.Version <= 0 {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	target, err := s.store.Strategy().GetVersion(userID, strategyID, req.Version)
+	if err != nil {
+		SafeNotFound(c, "Strategy version")
+		return
+	}
+	warnings, err := strategyVersionWarnings(target.Config)
+	if err != nil {
+		SafeBadRequest(c, "Strategy version has an invalid config")
+		return
+	}
+
+	version, err := s.store.Strategy().Rollback(userID, strategyID, req.Version, store.StrategyVersionMeta{
+		AuthorID: actorID(c),
+		Note:     req.Note,
+	})
+	if errors.Is(err, store.ErrStrategyVersionNotFound) || errors.Is(err, gorm.ErrRecordNotFound) {
+		SafeNotFound(c, "Strategy version")
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": SanitizeError(err, "Failed to roll back strategy")})
+		return
+	}
+
+	logger.Infof("↩️ Strategy %s rolled back to v%d (now v%d)", strategyID, req.Version, version.Version)
+	response := gin.H{
+		"message":       "Strategy rolled back successfully",
+		"restored_from": req.Version,
+		"version":       version.Version,
+	}
+	if len(warnings) > 0 {
+		response["warnings"] = warnings
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// strategyVersionWarnings checks the config of a stored version the way a
+// save does: the clamp and validation warnings it raises when it runs again
+func strategyVersionWarnings(raw string) ([]string, error) {
+	var config store.StrategyConfig
+	if err := json.Unmarshal([]byte(raw), &config); err != nil {
+		return nil, err
+	}
+	beforeClamp := config
+	config.ClampLimits()
+	warnings := validateStrategyConfig(&config)
+	return append(warnings, store.StrategyClampWarnings(beforeClamp, config, config.Language)...), nil
+}
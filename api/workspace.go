@@ -0,0 +1,482 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"nofx/auth"
+	"nofx/logger"
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// workspaceHeader selects the workspace a request acts in. Without it the
+// request acts on the caller's own resources, exactly as before workspaces.
+const workspaceHeader = "X-Workspace-ID"
+
+// workspaceRoutePermissions lists the minimum workspace role for routes that do
+// not follow the default rule (GET → viewer, anything else → owner). Keys are
+// "METHOD <gin full path>". Unknown mutating routes fall through to owner, so a
+// new endpoint is never writable by operators or viewers until listed here.
+var workspaceRoutePermissions = map[string]string{
+	// Operators run traders and edit strategies.
+	"POST /api/traders/:id/start":           store.WorkspaceRoleOperator,
+	"POST /api/traders/:id/stop":            store.WorkspaceRoleOperator,
+	"POST /api/traders/:id/sync-balance":    store.WorkspaceRoleOperator,
+	"POST /api/traders/:id/close-position":  store.WorkspaceRoleOperator,
+	"PUT /api/traders/:id/prompt":           store.WorkspaceRoleOperator,
+	"POST /api/launch/preflight":            store.WorkspaceRoleOperator,
+	"POST /api/strategies":                  store.WorkspaceRoleOperator,
+	"PUT /api/strategies/:id":               store.WorkspaceRoleOperator,
+	"DELETE /api/strategies/:id":            store.WorkspaceRoleOperator,
+	"POST /api/strategies/:id/activate":     store.WorkspaceRoleOperator,
+	"POST /api/strategies/:id/duplicate":    store.WorkspaceRoleOperator,
+	"POST /api/strategies/import":           store.WorkspaceRoleOperator,
+	"POST /api/strategies/:id/rollback":     store.WorkspaceRoleOperator,
+	"PUT /api/traders/:id/strategy-version": store.WorkspaceRoleOperator,
+	"PUT /api/traders/:id/ai-fallbacks":     store.WorkspaceRoleOperator,
+	"POST /api/strategies/preview-prompt":   store.WorkspaceRoleOperator,
+	"POST /api/strategies/test-run":         store.WorkspaceRoleOperator,
+
+	// Strategy market actions on the workspace's strategies.
+	"POST /api/strategies/:id/fork":           store.WorkspaceRoleOperator,
+	"PUT /api/strategies/:id/subscription":    store.WorkspaceRoleOperator,
+	"DELETE /api/strategies/:id/subscription": store.WorkspaceRoleOperator,
+	"PUT /api/strategies/:id/star":            store.WorkspaceRoleOperator,
+	"DELETE /api/strategies/:id/star":         store.WorkspaceRoleOperator,
+	"PUT /api/strategies/:id/rating":          store.WorkspaceRoleOperator,
+	"POST /api/notifications/read":            store.WorkspaceRoleOperator,
+
+	// Reads that expose owner-only material (bot token, wallet details).
+	"GET /api/telegram":                    store.WorkspaceRoleOwner,
+	"GET /api/onboarding/beginner/current": store.WorkspaceRoleOwner,
+	"GET /api/audit":                       store.WorkspaceRoleOwner,
+	"GET /api/audit/export":                store.WorkspaceRoleOwner,
+
+	// Token-level, does not touch shared resources.
+	"POST /api/logout": store.WorkspaceRoleViewer,
+}
+
+// accountRoutes act on the caller's own login account rather than on shared
+// resources. They refuse to run inside a workspace, so a member can never
+// reach the owner's account through the rewritten "user_id".
+var accountRoutes = map[string]bool{
+	"PUT /api/user/password": true,
+}
+
+// workspaceRequiredRole returns the minimum role needed to call method+path
+// inside a workspace.
+func workspaceRequiredRole(method, fullPath string) string {
+	if role, ok := workspaceRoutePermissions[method+" "+fullPath]; ok {
+		return role
+	}
+	if method == http.MethodGet {
+		return store.WorkspaceRoleViewer
+	}
+	return store.WorkspaceRoleOwner
+}
+
+// actorID returns the authenticated user making the request. Inside a
+// workspace "user_id" is rewritten to the workspace owner so handlers scope
+// queries to the shared resources; the real caller stays in "actor_id".
+func actorID(c *gin.Context) string {
+	if actor := c.GetString("actor_id"); actor != "" {
+		return actor
+	}
+	return c.GetString("user_id")
+}
+
+// workspaceMiddleware resolves the X-Workspace-ID header. Members get their
+// request scoped to the workspace owner's resources after the route's
+// required role is checked. Recording is left to auditMiddleware, which runs
+// after this one and so sees the workspace and the real actor.
+func (s *Server) workspaceMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor := c.GetString("user_id")
+		c.Set("actor_id", actor)
+
+		workspaceID := strings.TrimSpace(c.GetHeader(workspaceHeader))
+		// Workspace management endpoints check membership themselves and always
+		// act as the real caller.
+		if workspaceID == "" || strings.HasPrefix(c.FullPath(), "/api/workspaces") {
+			c.Next()
+			return
+		}
+
+		if accountRoutes[c.Request.Method+" "+c.FullPath()] {
+			writeAPIError(c, http.StatusForbidden,
+				"Account settings cannot be changed inside a workspace",
+				"workspace.account_route", nil)
+			c.Abort()
+			return
+		}
+
+		ws, err := s.store.Workspace().Get(workspaceID)
+		if err != nil {
+			SafeNotFound(c, "Workspace")
+			c.Abort()
+			return
+		}
+		member, err := s.store.Workspace().GetMember(ws.ID, actor)
+		if err != nil {
+			SafeForbidden(c, "You are not a member of this workspace")
+			c.Abort()
+			return
+		}
+
+		required := workspaceRequiredRole(c.Request.Method, c.FullPath())
+		if store.WorkspaceRoleRank(member.Role) < store.WorkspaceRoleRank(required) {
+			writeAPIError(c, http.StatusForbidden,
+				"Your workspace role does not allow this action",
+				"workspace.forbidden",
+				mapStringPairs("role", member.Role, "required_role", required))
+			c.Abort()
+			return
+		}
+
+		c.Set("user_id", ws.OwnerID)
+		c.Set("workspace_id", ws.ID)
+		c.Set("workspace_role", member.Role)
+		c.Next()
+	}
+}
+
+// requireWorkspaceRole loads the workspace in :id and checks the caller's role.
+// It writes the error response and returns ok=false when access is denied.
+func (s *Server) requireWorkspaceRole(c *gin.Context, minRole string) (*store.Workspace, *store.WorkspaceMember, bool) {
+	ws, err := s.store.Workspace().Get(c.Param("id"))
+	if err != nil {
+		SafeNotFound(c, "Workspace")
+		return nil, nil, false
+	}
+	member, err := s.store.Workspace().GetMember(ws.ID, actorID(c))
+	if err != nil {
+		// Hide existence of workspaces the caller is not part of.
+		SafeNotFound(c, "Workspace")
+		return nil, nil, false
+	}
+	if store.WorkspaceRoleRank(member.Role) < store.WorkspaceRoleRank(minRole) {
+		SafeForbidden(c, "Your workspace role does not allow this action")
+		return nil, nil, false
+	}
+	return ws, member, true
+}
+
+// recordWorkspaceAudit records a membership action against the workspace owner's trail.
+func (s *Server) recordWorkspaceAudit(c *gin.Context, ws *store.Workspace, action, target string) {
+	entry := &store.AuditLog{
+		OwnerID:     ws.OwnerID,
+		ActorID:     actorID(c),
+		WorkspaceID: ws.ID,
+		Source:      auditSource(c),
+		Action:      action,
+		Target:      target,
+		Status:      http.StatusOK,
+		IP:          c.ClientIP(),
+	}
+	if err := s.store.Audit().Record(entry); err != nil {
+		logger.Warnf("⚠️ Failed to record audit entry %s: %v", action, err)
+	}
+}
+
+// handleListWorkspaces lists the workspaces the caller belongs to
+func (s *Server) handleListWorkspaces(c *gin.Context) {
+	workspaces, err := s.store.Workspace().ListForUser(actorID(c))
+	if err != nil {
+		SafeInternalError(c, "Failed to list workspaces", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"workspaces": workspaces})
+}
+
+// handleCreateWorkspace creates a workspace sharing the caller's resources
+func (s *Server) handleCreateWorkspace(c *gin.Context) {
+	var req struct {
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	ws := &store.Workspace{
+		ID:      uuid.New().String(),
+		Name:    strings.TrimSpace(req.Name),
+		OwnerID: actorID(c),
+	}
+	if err := s.store.Workspace().Create(ws); err != nil {
+		SafeInternalError(c, "Failed to create workspace", err)
+		return
+	}
+	s.recordWorkspaceAudit(c, ws, "workspace.create", ws.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"workspace": ws,
+		"message":   "Workspace created successfully",
+	})
+}
+
+// handleDeleteWorkspace deletes a workspace (owner only)
+func (s *Server) handleDeleteWorkspace(c *gin.Context) {
+	ws, _, ok := s.requireWorkspaceRole(c, store.WorkspaceRoleOwner)
+	if !ok {
+		return
+	}
+	if err := s.store.Workspace().Delete(ws.ID); err != nil {
+		SafeInternalError(c, "Failed to delete workspace", err)
+		return
+	}
+	s.recordWorkspaceAudit(c, ws, "workspace.delete", ws.ID)
+	c.JSON(http.StatusOK, gin.H{"message": "Workspace deleted successfully"})
+}
+
+// handleListWorkspaceMembers lists members with their email (any member)
+func (s *Server) handleListWorkspaceMembers(c *gin.Context) {
+	ws, _, ok := s.requireWorkspaceRole(c, store.WorkspaceRoleViewer)
+	if !ok {
+		return
+	}
+	members, err := s.store.Workspace().ListMembers(ws.ID)
+	if err != nil {
+		SafeInternalError(c, "Failed to list workspace members", err)
+		return
+	}
+
+	result := make([]gin.H, 0, len(members))
+	for _, m := range members {
+		email := ""
+		if u, err := s.store.User().GetByID(m.UserID); err == nil {
+			email = u.Email
+		}
+		result = append(result, gin.H{
+			"user_id":    m.UserID,
+			"email":      email,
+			"role":       m.Role,
+			"invited_by": m.InvitedBy,
+			"created_at": m.CreatedAt,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"members": result})
+}
+
+// handleUpdateWorkspaceMember changes a member's role (owner only)
+func (s *Server) handleUpdateWorkspaceMember(c *gin.Context) {
+	ws, _, ok := s.requireWorkspaceRole(c, store.WorkspaceRoleOwner)
+	if !ok {
+		return
+	}
+	var req struct {
+		Role string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || !store.IsValidWorkspaceRole(req.Role) {
+		SafeBadRequest(c, "role must be one of owner, operator, viewer")
+		return
+	}
+
+	memberID := c.Param("user_id")
+	if err := s.store.Workspace().UpdateMemberRole(ws.ID, memberID, req.Role); err != nil {
+		s.writeWorkspaceMemberError(c, err, "Failed to update member role")
+		return
+	}
+	s.recordWorkspaceAudit(c, ws, "workspace.member.role:"+req.Role, memberID)
+	c.JSON(http.StatusOK, gin.H{"message": "Member role updated"})
+}
+
+// handleRemoveWorkspaceMember removes a member. Owners can remove anyone;
+// any member can remove themselves (leave).
+func (s *Server) handleRemoveWorkspaceMember(c *gin.Context) {
+	memberID := c.Param("user_id")
+	minRole := store.WorkspaceRoleOwner
+	if memberID == actorID(c) {
+		minRole = store.WorkspaceRoleViewer
+	}
+	ws, _, ok := s.requireWorkspaceRole(c, minRole)
+	if !ok {
+		return
+	}
+	if err := s.store.Workspace().RemoveMember(ws.ID, memberID); err != nil {
+		s.writeWorkspaceMemberError(c, err, "Failed to remove member")
+		return
+	}
+	s.recordWorkspaceAudit(c, ws, "workspace.member.remove", memberID)
+	c.JSON(http.StatusOK, gin.H{"message": "Member removed"})
+}
+
+func (s *Server) writeWorkspaceMemberError(c *gin.Context, err error, operation string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		SafeNotFound(c, "Member")
+	case errors.Is(err, store.ErrLastOwner):
+		SafeBadRequest(c, err.Error())
+	default:
+		SafeInternalError(c, operation, err)
+	}
+}
+
+// handleCreateWorkspaceInvitation invites an email with a role (owner only).
+// The token is returned once so the owner can share the join link.
+func (s *Server) handleCreateWorkspaceInvitation(c *gin.Context) {
+	ws, _, ok := s.requireWorkspaceRole(c, store.WorkspaceRoleOwner)
+	if !ok {
+		return
+	}
+	var req struct {
+		Email string `json:"email" binding:"required,email"`
+		Role  string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || !store.IsValidWorkspaceRole(req.Role) {
+		SafeBadRequest(c, "email and role (owner, operator, viewer) are required")
+		return
+	}
+
+	inv := &store.WorkspaceInvitation{
+		ID:          uuid.New().String(),
+		WorkspaceID: ws.ID,
+		Email:       req.Email,
+		Role:        req.Role,
+		InvitedBy:   actorID(c),
+	}
+	if err := s.store.Workspace().CreateInvitation(inv); err != nil {
+		SafeInternalError(c, "Failed to create invitation", err)
+		return
+	}
+	s.recordWorkspaceAudit(c, ws, "workspace.invite:"+inv.Role, inv.Email)
+
+	c.JSON(http.StatusOK, gin.H{
+		"invitation": inv,
+		"token":      inv.Token,
+	})
+}
+
+// handleListWorkspaceInvitations lists pending invitations (owner only)
+func (s *Server) handleListWorkspaceInvitations(c *gin.Context) {
+	ws, _, ok := s.requireWorkspaceRole(c, store.WorkspaceRoleOwner)
+	if !ok {
+		return
+	}
+	invs, err := s.store.Workspace().ListInvitations(ws.ID)
+	if err != nil {
+		SafeInternalError(c, "Failed to list invitations", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"invitations": invs})
+}
+
+// handleRevokeWorkspaceInvitation revokes a pending invitation (owner only)
+func (s *Server) handleRevokeWorkspaceInvitation(c *gin.Context) {
+	ws, _, ok := s.requireWorkspaceRole(c, store.WorkspaceRoleOwner)
+	if !ok {
+		return
+	}
+	invitationID := c.Param("invitation_id")
+	if err := s.store.Workspace().RevokeInvitation(ws.ID, invitationID); err != nil {
+		if errors.Is(err, store.ErrInvitationNotFound) {
+			SafeNotFound(c, "Invitation")
+			return
+		}
+		SafeInternalError(c, "Failed to revoke invitation", err)
+		return
+	}
+	s.recordWorkspaceAudit(c, ws, "workspace.invite.revoke", invitationID)
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation revoked"})
+}
+
+// handleWorkspaceAudit returns the workspace's audit trail (owner only)
+func (s *Server) handleWorkspaceAudit(c *gin.Context) {
+	ws, _, ok := s.requireWorkspaceRole(c, store.WorkspaceRoleOwner)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	logs, err := s.store.Audit().List(store.AuditFilter{
+		WorkspaceID: ws.ID,
+		ActorID:     c.Query("actor_id"),
+		Limit:       limit,
+	})
+	if err != nil {
+		SafeInternalError(c, "Failed to load audit trail", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"entries": logs})
+}
+
+// handleAcceptWorkspaceInvitation redeems an invitation token (public,
+// throttled). Registration is closed after first-time setup, so this is also
+// how invited teammates get an account: if no user exists for the invited
+// email one is created with the supplied password; otherwise the password
+// must match the existing account.
+func (s *Server) handleAcceptWorkspaceInvitation(c *gin.Context) {
+	var req struct {
+		Token    string `json:"token" binding:"required"`
+		Password string `json:"password" binding:"required,min=8"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "token and password (min 8 chars) are required")
+		return
+	}
+
+	inv, err := s.store.Workspace().GetInvitationByToken(req.Token)
+	if err != nil {
+		if errors.Is(err, store.ErrInvitationNotFound) || errors.Is(err, store.ErrInvitationExpired) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invitation is invalid or has expired"})
+			return
+		}
+		SafeInternalError(c, "Failed to load invitation", err)
+		return
+	}
+
+	user, err := s.store.User().GetByEmail(inv.Email)
+	switch {
+	case err == nil:
+		if !auth.CheckPassword(req.Password, user.PasswordHash) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Email or password incorrect"})
+			return
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		hash, hashErr := auth.HashPassword(req.Password)
+		if hashErr != nil {
+			SafeInternalError(c, "Password processing failed", hashErr)
+			return
+		}
+		user = &store.User{ID: uuid.New().String(), Email: inv.Email, PasswordHash: hash}
+		if err := s.store.User().Create(user); err != nil {
+			SafeInternalError(c, "Failed to create user", err)
+			return
+		}
+	default:
+		SafeInternalError(c, "Failed to load user", err)
+		return
+	}
+
+	if _, err := s.store.Workspace().AcceptInvitation(req.Token, user.ID); err != nil {
+		if errors.Is(err, store.ErrInvitationExpired) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invitation is invalid or has expired"})
+			return
+		}
+		SafeInternalError(c, "Failed to accept invitation", err)
+		return
+	}
+	if ws, err := s.store.Workspace().Get(inv.WorkspaceID); err == nil {
+		c.Set("actor_id", user.ID)
+		s.recordWorkspaceAudit(c, ws, "workspace.join:"+inv.Role, user.ID)
+	}
+
+	token, err := auth.GenerateJWT(user.ID, user.Email)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"token":        token,
+		"user_id":      user.ID,
+		"email":        user.Email,
+		"workspace_id": inv.WorkspaceID,
+		"role":         inv.Role,
+		"message":      "Invitation accepted",
+	})
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+func workspaceTestRouter(t *testing.T) (*gin.Engine, *store.Store) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	st, err := store.New(t.TempDir() + "/nofx.db")
+	if err != nil {
+		t.Fatalf("store.New failed: %v", err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+
+	if err := st.Workspace().Create(&store.Workspace{ID: "ws-1", Name: "Desk", OwnerID: "owner"}); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	for user, role := range map[string]string{"op": store.WorkspaceRoleOperator, "viewer": store.WorkspaceRoleViewer} {
+		inv := &store.WorkspaceInvitation{ID: "inv-" + user, WorkspaceID: "ws-1", Email: user + "@example.com", Role: role, InvitedBy: "owner"}
+		if err := st.Workspace().CreateInvitation(inv); err != nil {
+			t.Fatalf("create invitation: %v", err)
+		}
+		if _, err := st.Workspace().AcceptInvitation(inv.Token, user); err != nil {
+			t.Fatalf("accept invitation: %v", err)
+		}
+	}
+
+	s := &Server{store: st}
+	r := gin.New()
+	fakeAuth := func(c *gin.Context) { c.Set("user_id", c.GetHeader("X-Test-User")) }
+	g := r.Group("/api", fakeAuth, s.workspaceMiddleware(), s.auditMiddleware())
+	echo := func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"user_id": c.GetString("user_id"), "actor": actorID(c)})
+	}
+	g.GET("/my-traders", echo)
+	g.POST("/traders/:id/start", echo)
+	g.PUT("/exchanges", echo)
+	g.PUT("/user/password", echo)
+	return r, st
+}
+
+func TestWorkspaceMiddlewareEnforcesRoles(t *testing.T) {
+	r, st := workspaceTestRouter(t)
+
+	cases := []struct {
+		user, method, path string
+		want               int
+	}{
+		{"viewer", http.MethodGet, "/api/my-traders", http.StatusOK},
+		{"viewer", http.MethodPost, "/api/traders/t1/start", http.StatusForbidden},
+		{"op", http.MethodPost, "/api/traders/t1/start", http.StatusOK},
+		{"op", http.MethodPut, "/api/exchanges", http.StatusForbidden},
+		{"owner", http.MethodPut, "/api/exchanges", http.StatusOK},
+		{"owner", http.MethodPut, "/api/user/password", http.StatusForbidden},
+		{"stranger", http.MethodGet, "/api/my-traders", http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		req.Header.Set("X-Test-User", tc.user)
+		req.Header.Set(workspaceHeader, "ws-1")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Errorf("%s %s as %s: got %d want %d (%s)", tc.method, tc.path, tc.user, w.Code, tc.want, w.Body.String())
+		}
+	}
+
+	logs, err := st.Audit().List(store.AuditFilter{WorkspaceID: "ws-1"})
+	if err != nil {
+		t.Fatalf("list audit: %v", err)
+	}
+	var sawStart bool
+	for _, l := range logs {
+		if l.ActorID == "op" && l.Action == "trader.start" && l.Target == "t1" && l.OwnerID == "owner" {
+			sawStart = true
+		}
+	}
+	if !sawStart {
+		t.Fatalf("expected audit entry for operator start, got %+v", logs)
+	}
+}
+
+func TestWorkspaceMiddlewareScopesToOwner(t *testing.T) {
+	r, _ := workspaceTestRouter(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/my-traders", nil)
+	req.Header.Set("X-Test-User", "viewer")
+	req.Header.Set(workspaceHeader, "ws-1")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Body.String() != `{"actor":"viewer","user_id":"owner"}` {
+		t.Fatalf("expected owner-scoped request, got %d %s", w.Code, w.Body.String())
+	}
+
+	// Without the header the caller stays in their own scope.
+	req = httptest.NewRequest(http.MethodGet, "/api/my-traders", nil)
+	req.Header.Set("X-Test-User", "viewer")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Body.String() != `{"actor":"viewer","user_id":"viewer"}` {
+		t.Fatalf("expected personal scope, got %s", w.Body.String())
+	}
+}
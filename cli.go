@@ -2,17 +2,21 @@ package main
 
 import (
 	"bufio"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"nofx/auth"
 	"nofx/config"
 	"nofx/crypto"
 	"nofx/logger"
+	"nofx/maintenance"
 	"nofx/store"
 
 	"github.com/joho/godotenv"
@@ -47,6 +51,21 @@ func runCLISubcommand(args []string) bool {
 	case "reset-account":
 		runResetAccount(args[1:])
 		return true
+	case "migrate":
+		runMigrate(args[1:])
+		return true
+	case "retention":
+		runRetention(args[1:])
+		return true
+	case "backup":
+		runBackup(args[1:])
+		return true
+	case "restore":
+		runRestore(args[1:])
+		return true
+	case "rotate-data-key":
+		runRotateDataKey(args[1:])
+		return true
 	default:
 		return false
 	}
@@ -55,6 +74,16 @@ func runCLISubcommand(args []string) bool {
 // openStoreForCLI loads config + encryption and opens the same database the
 // server uses, so subcommands operate on the live data.
 func openStoreForCLI(dbPathOverride string) (*store.Store, error) {
+	dbCfg, _, err := cliDBConfig(dbPathOverride)
+	if err != nil {
+		return nil, err
+	}
+	return store.NewWithConfig(dbCfg)
+}
+
+// cliDBConfig loads config + encryption and returns the database
+// configuration the server uses, along with the crypto service.
+func cliDBConfig(dbPathOverride string) (store.DBConfig, *crypto.CryptoService, error) {
 	_ = godotenv.Load()
 	logger.Init(nil)
 	config.MustInit()
@@ -65,14 +94,14 @@ func openStoreForCLI(dbPathOverride string) (*store.Store, error) {
 
 	cryptoService, err := crypto.NewCryptoService()
 	if err != nil {
-		return nil, fmt.Errorf("initialize encryption service: %w", err)
+		return store.DBConfig{}, nil, fmt.Errorf("initialize encryption service: %w", err)
 	}
 	crypto.SetGlobalCryptoService(cryptoService)
 
 	if cfg.DBType == "sqlite" {
 		if dir := filepath.Dir(cfg.DBPath); dir != "." {
 			if err := os.MkdirAll(dir, 0o755); err != nil {
-				return nil, fmt.Errorf("create data directory: %w", err)
+				return store.DBConfig{}, nil, fmt.Errorf("create data directory: %w", err)
 			}
 		}
 	}
@@ -81,7 +110,7 @@ func openStoreForCLI(dbPathOverride string) (*store.Store, error) {
 	if cfg.DBType == "postgres" {
 		dbType = store.DBTypePostgres
 	}
-	return store.NewWithConfig(store.DBConfig{
+	return store.DBConfig{
 		Type:     dbType,
 		Path:     cfg.DBPath,
 		Host:     cfg.DBHost,
@@ -90,7 +119,7 @@ func openStoreForCLI(dbPathOverride string) (*store.Store, error) {
 		Password: cfg.DBPassword,
 		DBName:   cfg.DBName,
 		SSLMode:  cfg.DBSSLMode,
-	})
+	}, cryptoService, nil
 }
 
 // runResetPassword resets the password for a single account from the command
@@ -137,6 +166,7 @@ func runResetPassword(args []string) {
 		os.Exit(1)
 	}
 
+	recordCLIAudit(st, user.ID, "user.password.reset", user.ID)
 	fmt.Printf("✓ Password reset for %s. Log in with the new password.\n", user.Email)
 }
 
@@ -171,6 +201,9 @@ func runResetAccount(args []string) {
 		tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&store.Strategy{})
 		tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&store.AIModel{})
 		tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&store.Exchange{})
+		tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&store.WorkspaceInvitation{})
+		tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&store.WorkspaceMember{})
+		tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&store.Workspace{})
 		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&store.User{}).Error; err != nil {
 			return fmt.Errorf("failed to delete users: %w", err)
 		}
@@ -181,9 +214,632 @@ func runResetAccount(args []string) {
 		os.Exit(1)
 	}
 
+	recordCLIAudit(st, "", "system.reset", "")
 	fmt.Println("✓ System wiped. Register a fresh account and re-import everything.")
 }
 
+// runMigrate shows or changes the database schema version.
+// Usage: `nofx migrate status|up|down [--to N] [--steps N] [--dry-run]`.
+func runMigrate(args []string) {
+	const usage = "usage: nofx migrate status|up|down [--to N] [--steps N] [--dry-run] [--db path]"
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+	action := args[0]
+	fs := flag.NewFlagSet("migrate "+action, flag.ExitOnError)
+	dbPath := fs.String("db", "", "override SQLite DB path (defaults to config / DB_PATH)")
+	dryRun := fs.Bool("dry-run", false, "print the migrations that would run without applying them")
+	to := fs.Int("to", 0, "up: migrate to this version instead of the latest")
+	steps := fs.Int("steps", 1, "down: number of migrations to roll back")
+	_ = fs.Parse(args[1:])
+
+	dbCfg, _, err := cliDBConfig(*dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	// Opened without migrating, so status and down see the schema as it is
+	st, err := store.OpenWithConfig(dbCfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	defer st.Close()
+
+	m := st.Migrator()
+	m.DryRun = *dryRun
+	verb := "Applied"
+	if *dryRun {
+		verb = "Would apply"
+	}
+
+	var done []store.Migration
+	switch action {
+	case "status":
+		statuses, err := m.Status()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		current, _ := m.CurrentVersion()
+		fmt.Printf("Schema version %d (binary supports %d)\n", current, m.LatestVersion())
+		for _, s := range statuses {
+			state := "pending"
+			if s.Applied {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("  %4d  %-50s  %s\n", s.Version, s.Name, state)
+		}
+		if err := m.CheckVersion(); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		}
+		return
+	case "up":
+		done, err = m.Up(*to)
+	case "down":
+		if *steps < 1 {
+			fmt.Fprintln(os.Stderr, "error: --steps must be at least 1")
+			os.Exit(2)
+		}
+		done, err = m.Down(*steps)
+		verb = "Rolled back"
+		if *dryRun {
+			verb = "Would roll back"
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "error: unknown migrate action %q\n", action)
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	for _, mig := range done {
+		fmt.Printf("✓ %s migration %d: %s\n", verb, mig.Version, mig.Name)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(done) == 0 {
+		fmt.Println("Nothing to do.")
+		return
+	}
+	if !*dryRun {
+		recordCLIAudit(st, "", "system.migrate."+action, "")
+	}
+}
+
+// runRetention shows, changes or runs the data retention policy.
+// Usage: `nofx retention show|set|run [flags]`.
+func runRetention(args []string) {
+	const usage = "usage: nofx retention show|set|run [--db path] (see `nofx retention set -h` for the policy flags)"
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+	action := args[0]
+	fs := flag.NewFlagSet("retention "+action, flag.ExitOnError)
+	dbPath := fs.String("db", "", "override SQLite DB path (defaults to config / DB_PATH)")
+	enabled := fs.Bool("enabled", true, "set: run retention on its interval")
+	interval := fs.Int("interval-hours", 0, "set: hours between runs")
+	stripPrompts := fs.Int("strip-prompts-days", 0, "set: clear decision prompts and raw responses after N days (0 = never)")
+	deleteDecisions := fs.Int("delete-decisions-days", 0, "set: delete decision records after N days (0 = never)")
+	equityHourly := fs.Int("equity-hourly-days", 0, "set: keep one equity snapshot per hour after N days (0 = never)")
+	equityDaily := fs.Int("equity-daily-days", 0, "set: keep one equity snapshot per day after N days (0 = never)")
+	deleteEquity := fs.Int("delete-equity-days", 0, "set: delete equity snapshots after N days (0 = never)")
+	archiveOrders := fs.Int("archive-orders-days", 0, "set: archive closed orders and fills after N days (0 = never)")
+	archiveDir := fs.String("archive-dir", "", "set: directory for order archives")
+	vacuum := fs.Bool("vacuum", true, "set: compact the database after each run")
+	_ = fs.Parse(args[1:])
+
+	st, err := openStoreForCLI(*dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	defer st.Close()
+
+	policy, err := st.GetRetentionPolicy()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch action {
+	case "show":
+		printJSON(policy)
+		if last, err := st.GetLastRetentionReport(); err == nil && last != nil {
+			fmt.Println("Last run:")
+			printJSON(last)
+		}
+	case "set":
+		// Only the flags given on the command line change the policy
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "enabled":
+				policy.Enabled = *enabled
+			case "interval-hours":
+				policy.IntervalHours = *interval
+			case "strip-prompts-days":
+				policy.StripPromptsAfterDays = *stripPrompts
+			case "delete-decisions-days":
+				policy.DeleteDecisionsAfterDays = *deleteDecisions
+			case "equity-hourly-days":
+				policy.EquityHourlyAfterDays = *equityHourly
+			case "equity-daily-days":
+				policy.EquityDailyAfterDays = *equityDaily
+			case "delete-equity-days":
+				policy.DeleteEquityAfterDays = *deleteEquity
+			case "archive-orders-days":
+				policy.ArchiveOrdersAfterDays = *archiveOrders
+			case "archive-dir":
+				policy.ArchiveDir = *archiveDir
+			case "vacuum":
+				policy.Vacuum = *vacuum
+			}
+		})
+		if err := st.SetRetentionPolicy(policy); err != nil {
+			fmt.Fprintf(os.Stderr, "error: failed to save retention policy: %v\n", err)
+			os.Exit(1)
+		}
+		recordCLIAudit(st, "", "system.retention.update", "")
+		fmt.Println("✓ Retention policy saved:")
+		printJSON(policy.Normalize())
+	case "run":
+		report := st.ApplyRetention(policy)
+		printJSON(report)
+		if len(report.Errors) > 0 {
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "error: unknown retention action %q\n", action)
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+// runBackup writes an online backup of the database, verifies a backup file
+// or configures scheduled backups.
+// Usage: `nofx backup [--out file | --dir dir] [--no-verify]`,
+// `nofx backup verify <file>`, `nofx backup schedule [flags]`.
+func runBackup(args []string) {
+	if len(args) > 0 && args[0] == "verify" {
+		runBackupVerify(args[1:])
+		return
+	}
+	if len(args) > 0 && args[0] == "schedule" {
+		runBackupSchedule(args[1:])
+		return
+	}
+
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	dbPath := fs.String("db", "", "override SQLite DB path (defaults to config / DB_PATH)")
+	out := fs.String("out", "", "backup file to write (default: a timestamped file in --dir)")
+	dir := fs.String("dir", "", "directory for the backup (default: the scheduled backup dir)")
+	noVerify := fs.Bool("no-verify", false, "skip verifying the backup after writing it")
+	_ = fs.Parse(args)
+
+	dbCfg, cs, err := cliDBConfig(*dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	st, err := store.NewWithConfig(dbCfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	defer st.Close()
+
+	path := *out
+	if path == "" {
+		policy, err := st.GetBackupPolicy()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		if *dir != "" {
+			policy.Dir = *dir
+		}
+		if err := os.MkdirAll(policy.Dir, 0o700); err != nil {
+			fmt.Fprintf(os.Stderr, "error: create backup dir: %v\n", err)
+			os.Exit(1)
+		}
+		path = filepath.Join(policy.Dir, maintenance.BackupFileName(st, time.Now()))
+	}
+
+	info, err := st.Backup(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✓ Backup written to %s (%s, schema version %d, %.1f MB)\n",
+		info.Path, info.Format, info.SchemaVersion, float64(info.Size)/(1<<20))
+
+	if !*noVerify && !verifyBackupFile(info.Path, cs) {
+		os.Exit(1)
+	}
+}
+
+// runBackupVerify checks a backup file. Usage: `nofx backup verify <file>`.
+func runBackupVerify(args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "usage: nofx backup verify <file>")
+		os.Exit(2)
+	}
+	_, cs, err := cliDBConfig("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	if !verifyBackupFile(args[0], cs) {
+		os.Exit(1)
+	}
+}
+
+// verifyBackupFile verifies a backup and prints the outcome. Returns false
+// when the backup is unreadable or has values the current keys cannot decrypt.
+func verifyBackupFile(path string, cs *crypto.CryptoService) bool {
+	v, err := store.VerifyBackup(path, cs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: backup verification failed: %v\n", err)
+		return false
+	}
+	fmt.Printf("✓ Backup readable: %d tables, %d rows, schema version %d\n", v.Tables, v.Rows, v.SchemaVersion)
+	if !v.OK() {
+		fmt.Fprintf(os.Stderr, "error: encrypted values in %s do not decrypt with the current DATA_ENCRYPTION_KEY\n",
+			strings.Join(v.DecryptFailures, ", "))
+		return false
+	}
+	fmt.Printf("✓ All %d encrypted values decrypt with the current keys\n", v.EncryptedValues)
+	return true
+}
+
+// runBackupSchedule shows or changes the scheduled backup policy.
+// Usage: `nofx backup schedule [--enabled] [--interval-hours N] [--dir dir] [--keep N] [--verify]`.
+func runBackupSchedule(args []string) {
+	fs := flag.NewFlagSet("backup schedule", flag.ExitOnError)
+	dbPath := fs.String("db", "", "override SQLite DB path (defaults to config / DB_PATH)")
+	enabled := fs.Bool("enabled", true, "take backups on the interval while the server runs")
+	interval := fs.Int("interval-hours", 0, "hours between backups")
+	dir := fs.String("dir", "", "directory for scheduled backups")
+	keep := fs.Int("keep", 0, "number of backups to keep")
+	verify := fs.Bool("verify", true, "verify each backup after writing it")
+	_ = fs.Parse(args)
+
+	st, err := openStoreForCLI(*dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	defer st.Close()
+
+	policy, err := st.GetBackupPolicy()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	if fs.NFlag() == 0 || (fs.NFlag() == 1 && *dbPath != "") {
+		printJSON(policy)
+		if last, err := st.GetLastBackupReport(); err == nil && last != nil {
+			fmt.Println("Last scheduled backup:")
+			printJSON(last)
+		}
+		return
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "enabled":
+			policy.Enabled = *enabled
+		case "interval-hours":
+			policy.IntervalHours = *interval
+		case "dir":
+			policy.Dir = *dir
+		case "keep":
+			policy.Keep = *keep
+		case "verify":
+			policy.Verify = *verify
+		}
+	})
+	if err := st.SetBackupPolicy(policy); err != nil {
+		fmt.Fprintf(os.Stderr, "error: failed to save backup policy: %v\n", err)
+		os.Exit(1)
+	}
+	recordCLIAudit(st, "", "system.backup.schedule", "")
+	fmt.Println("✓ Backup schedule saved:")
+	printJSON(policy.Normalize())
+}
+
+// runRestore replaces the database with a backup. A SQLite backup replaces
+// the database file (the current one is kept aside); a dump replaces the rows
+// of every dumped table. Stop the server first.
+// Usage: `nofx restore <file> [--yes]`.
+func runRestore(args []string) {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	dbPath := fs.String("db", "", "override SQLite DB path (defaults to config / DB_PATH)")
+	yes := fs.Bool("yes", false, "skip the interactive confirmation prompt")
+	force := fs.Bool("force", false, "restore even when encrypted values do not decrypt with the current keys")
+	// The file may come before or after the flags
+	var backupPath string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		backupPath, args = args[0], args[1:]
+	}
+	_ = fs.Parse(args)
+	if backupPath == "" && fs.NArg() == 1 {
+		backupPath = fs.Arg(0)
+	}
+	if backupPath == "" || fs.NArg() > 1 || (fs.NArg() == 1 && fs.Arg(0) != backupPath) {
+		fmt.Fprintln(os.Stderr, "usage: nofx restore <file> [--yes] [--force] [--db path]")
+		os.Exit(2)
+	}
+
+	dbCfg, cs, err := cliDBConfig(*dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	format, err := store.DetectBackupFormat(backupPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	if format == store.BackupFormatSQLite && dbCfg.Type != store.DBTypeSQLite {
+		fmt.Fprintln(os.Stderr, "error: a SQLite backup can only be restored into a SQLite deployment")
+		os.Exit(1)
+	}
+	if !verifyBackupFile(backupPath, cs) && !*force {
+		fmt.Fprintln(os.Stderr, "aborted: backup did not verify (use --force to restore anyway)")
+		os.Exit(1)
+	}
+
+	if !*yes {
+		fmt.Print("This replaces ALL data in the database with the backup.\n" +
+			"Stop the nofx server before continuing.\n" +
+			"Type 'restore' to confirm: ")
+		line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if strings.TrimSpace(line) != "restore" {
+			fmt.Fprintln(os.Stderr, "aborted")
+			os.Exit(1)
+		}
+	}
+
+	if format == store.BackupFormatSQLite {
+		previous, err := store.RestoreSQLiteFile(backupPath, dbCfg.Path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		if previous != "" {
+			fmt.Printf("✓ Previous database kept at %s\n", previous)
+		}
+		// Opening the restored database applies any migrations it is missing
+		st, err := store.NewWithConfig(dbCfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: restored database does not open: %v\n", err)
+			os.Exit(1)
+		}
+		defer st.Close()
+		recordCLIAudit(st, "", "system.restore", filepath.Base(backupPath))
+	} else {
+		st, err := store.NewWithConfig(dbCfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		defer st.Close()
+		if err := st.RestoreDump(backupPath); err != nil {
+			fmt.Fprintf(os.Stderr, "error: restore failed, database unchanged: %v\n", err)
+			os.Exit(1)
+		}
+		recordCLIAudit(st, "", "system.restore", filepath.Base(backupPath))
+	}
+	fmt.Printf("✓ Database restored from %s\n", backupPath)
+}
+
+// runRotateDataKey re-encrypts every stored secret with a new data key and
+// retires the old one.
+// Usage: `nofx rotate-data-key [--new-key KEY] [--env-file .env] [--keep-old-key] [--dry-run] [--yes]`.
+//
+// When the env file holds the active DATA_ENCRYPTION_KEY it is updated in two
+// steps: first the new key becomes primary with the old one kept in
+// DATA_ENCRYPTION_KEYS_PREVIOUS, then after the re-encryption commits the old
+// key is dropped. If the command fails midway the server still starts with
+// either key set.
+func runRotateDataKey(args []string) {
+	fs := flag.NewFlagSet("rotate-data-key", flag.ExitOnError)
+	dbPath := fs.String("db", "", "override SQLite DB path (defaults to config / DB_PATH)")
+	newKeyFlag := fs.String("new-key", "", "new data key (Base64 of 32 bytes); generated when empty")
+	envFile := fs.String("env-file", ".env", "env file holding DATA_ENCRYPTION_KEY to update")
+	keepOld := fs.Bool("keep-old-key", false, "keep the old key in "+crypto.EnvDataEncryptionKeysPrevious+" (e.g. to verify older backups)")
+	dryRun := fs.Bool("dry-run", false, "report what would be re-encrypted without changing anything")
+	yes := fs.Bool("yes", false, "skip the interactive confirmation prompt")
+	_ = fs.Parse(args)
+
+	dbCfg, cs, err := cliDBConfig(*dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	newKey := strings.TrimSpace(*newKeyFlag)
+	if newKey == "" {
+		if newKey, err = crypto.GenerateDataKey(); err != nil {
+			fmt.Fprintf(os.Stderr, "error: generate data key: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	keyBytes, err := crypto.ParseDataKey(newKey)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: --new-key: %v\n", err)
+		os.Exit(1)
+	}
+	if crypto.DataKeyID(keyBytes) == cs.Keyring().PrimaryID() {
+		fmt.Fprintln(os.Stderr, "error: the new key is the current DATA_ENCRYPTION_KEY")
+		os.Exit(1)
+	}
+	ring, err := cs.Keyring().WithPrimary(keyBytes)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	rotated := cs.WithKeyring(ring)
+
+	st, err := store.NewWithConfig(dbCfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	defer st.Close()
+
+	if *dryRun {
+		report, err := st.ReEncryptSecrets(rotated, true)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		printJSON(report)
+		return
+	}
+
+	// The old keys as configured, so the env file can keep them for decryption
+	oldKeys := []string{strings.TrimSpace(os.Getenv(crypto.EnvDataEncryptionKey))}
+	for _, k := range strings.Split(os.Getenv(crypto.EnvDataEncryptionKeysPrevious), ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			oldKeys = append(oldKeys, k)
+		}
+	}
+	managed := false
+	if env, err := godotenv.Read(*envFile); err == nil {
+		managed = strings.TrimSpace(env[crypto.EnvDataEncryptionKey]) == oldKeys[0]
+	}
+
+	if !*yes {
+		fmt.Print("This re-encrypts all stored API keys, private keys and tokens with a new data key.\n" +
+			"Stop the nofx server before continuing and take a backup (`nofx backup`).\n")
+		if !managed {
+			fmt.Printf("%s does not hold the active key: you must set the new key in your environment yourself.\n", *envFile)
+		}
+		fmt.Print("Type 'rotate' to confirm: ")
+		line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if strings.TrimSpace(line) != "rotate" {
+			fmt.Fprintln(os.Stderr, "aborted")
+			os.Exit(1)
+		}
+	}
+
+	if managed {
+		if err := setEnvFileValues(*envFile, map[string]string{
+			crypto.EnvDataEncryptionKey:          newKey,
+			crypto.EnvDataEncryptionKeysPrevious: strings.Join(oldKeys, ","),
+		}); err != nil {
+			fmt.Fprintf(os.Stderr, "error: update %s: %v\n", *envFile, err)
+			os.Exit(1)
+		}
+	}
+
+	report, err := st.ReEncryptSecrets(rotated, false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: re-encryption failed, database unchanged: %v\n", err)
+		if managed {
+			fmt.Fprintf(os.Stderr, "%s lists the old key in %s, so the server still starts\n",
+				*envFile, crypto.EnvDataEncryptionKeysPrevious)
+		}
+		os.Exit(1)
+	}
+	recordCLIAudit(st, "", "system.rotate_data_key", report.KeyID)
+	fmt.Printf("✓ Re-encrypted %d values with data key %s (%d were stored in plaintext)\n",
+		report.Total(), report.KeyID, report.Plaintext)
+
+	previous := ""
+	if *keepOld {
+		previous = strings.Join(oldKeys, ",")
+	}
+	if !managed {
+		fmt.Printf("\nSet the new key wherever DATA_ENCRYPTION_KEY is configured, then restart nofx:\n\n"+
+			"  %s=%s\n  %s=%s\n\n", crypto.EnvDataEncryptionKey, newKey, crypto.EnvDataEncryptionKeysPrevious, previous)
+		return
+	}
+	if err := setEnvFileValues(*envFile, map[string]string{crypto.EnvDataEncryptionKeysPrevious: previous}); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: could not drop the old key from %s: %v\n", *envFile, err)
+		os.Exit(1)
+	}
+	if *keepOld {
+		fmt.Printf("✓ %s updated; the old key stays in %s\n", *envFile, crypto.EnvDataEncryptionKeysPrevious)
+	} else {
+		fmt.Printf("✓ %s updated and the old key retired. Backups taken before now need the old key to restore.\n", *envFile)
+	}
+}
+
+// setEnvFileValues sets KEY=value lines in an env file, appending keys that
+// are missing. The file is replaced atomically and keeps its permissions.
+func setEnvFileValues(path string, values map[string]string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	done := make(map[string]bool)
+	for i, line := range lines {
+		name, _, ok := strings.Cut(strings.TrimPrefix(strings.TrimSpace(line), "export "), "=")
+		if !ok {
+			continue
+		}
+		if v, ok := values[strings.TrimSpace(name)]; ok {
+			lines[i] = strings.TrimSpace(name) + "=" + v
+			done[strings.TrimSpace(name)] = true
+		}
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !done[name] {
+			lines = append(lines, name+"="+values[name])
+		}
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strings.Join(lines, "\n")+"\n"), info.Mode().Perm()); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// printJSON prints v as indented JSON
+func printJSON(v interface{}) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return
+	}
+	fmt.Println(string(data))
+}
+
+// recordCLIAudit appends a local admin action to the audit log. The audit
+// table survives reset-account, so the wipe itself stays on record.
+func recordCLIAudit(st *store.Store, ownerID, action, target string) {
+	actor := "cli"
+	if u := os.Getenv("USER"); u != "" {
+		actor = "cli:" + u
+	}
+	if err := st.Audit().Record(&store.AuditLog{
+		OwnerID: ownerID,
+		ActorID: actor,
+		Source:  store.AuditSourceCLI,
+		Action:  action,
+		Target:  target,
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to record audit entry: %v\n", err)
+	}
+}
+
 // resolveNewPassword returns the new password from the --password flag, or
 // prompts for it (hidden) on a TTY, or reads a single line from piped stdin.
 func resolveNewPassword(flagValue string) (string, error) {
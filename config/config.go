@@ -1,6 +1,8 @@
 package config
 
 import (
+	"crypto/ed25519"
+	"encoding/base64"
 	"fmt"
 	"nofx/mcp"
 	"nofx/telemetry"
@@ -52,7 +54,13 @@ type Config struct {
 	AlpacaAPIKey    string // Alpaca API key for US stocks
 	AlpacaSecretKey string // Alpaca secret key
 	TwelveDataKey   string // TwelveData API key for forex & metals
+	CoinankAPIKey   string // CoinAnk OpenAPI key for market caps (asset tier rules)
 
+	// Strategy bundle signing (optional)
+	// StrategySigningKey signs exported strategy bundles (ed25519, base64 32-byte seed)
+	StrategySigningKey ed25519.PrivateKey
+	// StrategyTrustedKeys base64 ed25519 public keys whose signed bundles are marked trusted on import
+	StrategyTrustedKeys []string
 }
 
 // MustInit initializes global configuration or panics. Use from main() so the
@@ -122,6 +130,21 @@ func initConfig() error {
 	cfg.AlpacaAPIKey = os.Getenv("ALPACA_API_KEY")
 	cfg.AlpacaSecretKey = os.Getenv("ALPACA_SECRET_KEY")
 	cfg.TwelveDataKey = os.Getenv("TWELVEDATA_API_KEY")
+	cfg.CoinankAPIKey = os.Getenv("COINANK_API_KEY")
+
+	// Strategy bundle signing
+	if v := strings.TrimSpace(os.Getenv("STRATEGY_SIGNING_KEY")); v != "" {
+		seed, err := base64.StdEncoding.DecodeString(v)
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return fmt.Errorf("STRATEGY_SIGNING_KEY must be a base64 encoded %d-byte ed25519 seed (generate via `openssl rand -base64 32`)", ed25519.SeedSize)
+		}
+		cfg.StrategySigningKey = ed25519.NewKeyFromSeed(seed)
+	}
+	for _, key := range strings.Split(os.Getenv("STRATEGY_TRUSTED_KEYS"), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			cfg.StrategyTrustedKeys = append(cfg.StrategyTrustedKeys, key)
+		}
+	}
 
 	// Database configuration
 	if v := os.Getenv("DB_TYPE"); v != "" {
@@ -19,8 +19,12 @@ import (
 	"time"
 )
 
+// Stored values are "ENC:v2:<key id>:<nonce>:<ciphertext>". Values written
+// before key IDs existed are "ENC:v1:<nonce>:<ciphertext>" and are decrypted
+// by trying each key in the keyring.
 const (
-	storagePrefix    = "ENC:v1:"
+	storagePrefix    = "ENC:v2:"
+	storagePrefixV1  = "ENC:v1:"
 	storageDelimiter = ":"
 )
 
@@ -49,7 +53,7 @@ type AADData struct {
 type CryptoService struct {
 	privateKey *rsa.PrivateKey
 	publicKey  *rsa.PublicKey
-	dataKey    []byte
+	keyring    *Keyring
 }
 
 // NewCryptoService creates crypto service (loads keys from environment variables)
@@ -60,8 +64,8 @@ func NewCryptoService() (*CryptoService, error) {
 		return nil, fmt.Errorf("failed to load RSA private key: %w", err)
 	}
 
-	// 2. Load AES data encryption key
-	dataKey, err := loadDataKeyFromEnv()
+	// 2. Load AES data encryption keys
+	keyring, err := loadKeyringFromEnv()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load data encryption key: %w", err)
 	}
@@ -69,10 +73,25 @@ func NewCryptoService() (*CryptoService, error) {
 	return &CryptoService{
 		privateKey: privateKey,
 		publicKey:  &privateKey.PublicKey,
-		dataKey:    dataKey,
+		keyring:    keyring,
 	}, nil
 }
 
+// WithKeyring returns a copy of the service that uses keyring for storage
+// encryption. The RSA key is shared.
+func (cs *CryptoService) WithKeyring(keyring *Keyring) *CryptoService {
+	return &CryptoService{
+		privateKey: cs.privateKey,
+		publicKey:  cs.publicKey,
+		keyring:    keyring,
+	}
+}
+
+// Keyring returns the data encryption keys
+func (cs *CryptoService) Keyring() *Keyring {
+	return cs.keyring
+}
+
 // loadRSAPrivateKeyFromEnv loads RSA private key from environment variable
 func loadRSAPrivateKeyFromEnv() (*rsa.PrivateKey, error) {
 	keyPEM := os.Getenv(EnvRSAPrivateKey)
@@ -93,16 +112,7 @@ func loadDataKeyFromEnv() ([]byte, error) {
 		return nil, fmt.Errorf("environment variable %s not set, please configure data encryption key in .env", EnvDataEncryptionKey)
 	}
 
-	// Try to decode
-	if key, ok := decodePossibleKey(keyStr); ok {
-		return key, nil
-	}
-
-	// If decoding fails, use SHA256 hash as key
-	sum := sha256.Sum256([]byte(keyStr))
-	key := make([]byte, len(sum))
-	copy(key, sum[:])
-	return key, nil
+	return ParseDataKey(keyStr)
 }
 
 // ParseRSAPrivateKeyFromPEM parses RSA private key from PEM format
@@ -165,7 +175,7 @@ func normalizeAESKey(raw []byte) ([]byte, bool) {
 }
 
 func (cs *CryptoService) HasDataKey() bool {
-	return len(cs.dataKey) > 0
+	return cs.keyring != nil
 }
 
 func (cs *CryptoService) GetPublicKeyPEM() string {
@@ -193,12 +203,7 @@ func (cs *CryptoService) EncryptForStorage(plaintext string, aadParts ...string)
 		return plaintext, nil
 	}
 
-	block, err := aes.NewCipher(cs.dataKey)
-	if err != nil {
-		return "", err
-	}
-
-	gcm, err := cipher.NewGCM(block)
+	gcm, err := newGCM(cs.keyring.primaryKey())
 	if err != nil {
 		return "", err
 	}
@@ -211,7 +216,7 @@ func (cs *CryptoService) EncryptForStorage(plaintext string, aadParts ...string)
 	aad := composeAAD(aadParts)
 	ciphertext := gcm.Seal(nil, nonce, []byte(plaintext), aad)
 
-	return storagePrefix +
+	return storagePrefix + cs.keyring.PrimaryID() + storageDelimiter +
 		base64.StdEncoding.EncodeToString(nonce) + storageDelimiter +
 		base64.StdEncoding.EncodeToString(ciphertext), nil
 }
@@ -227,7 +232,7 @@ func (cs *CryptoService) DecryptFromStorage(value string, aadParts ...string) (s
 		return "", errors.New("data not encrypted")
 	}
 
-	payload := strings.TrimPrefix(value, storagePrefix)
+	keyID, payload := splitStorageValue(value)
 	parts := strings.SplitN(payload, storageDelimiter, 2)
 	if len(parts) != 2 {
 		return "", errors.New("invalid encrypted data format")
@@ -243,33 +248,93 @@ func (cs *CryptoService) DecryptFromStorage(value string, aadParts ...string) (s
 		return "", fmt.Errorf("failed to decode ciphertext: %w", err)
 	}
 
-	block, err := aes.NewCipher(cs.dataKey)
-	if err != nil {
-		return "", err
+	// v1 values carry no key ID, so try every key; GCM authentication tells
+	// which one is right
+	keyIDs := []string{keyID}
+	if keyID == "" {
+		keyIDs = cs.keyring.IDs()
+	} else if !cs.keyring.Has(keyID) {
+		return "", fmt.Errorf("data key %s is not in the keyring", keyID)
 	}
 
-	gcm, err := cipher.NewGCM(block)
-	if err != nil {
-		return "", err
+	aad := composeAAD(aadParts)
+	var openErr error
+	for _, id := range keyIDs {
+		gcm, err := newGCM(cs.keyring.keys[id])
+		if err != nil {
+			return "", err
+		}
+		if len(nonce) != gcm.NonceSize() {
+			return "", fmt.Errorf("invalid nonce length: expected %d, got %d", gcm.NonceSize(), len(nonce))
+		}
+		plaintext, err := gcm.Open(nil, nonce, ciphertext, aad)
+		if err == nil {
+			return string(plaintext), nil
+		}
+		openErr = err
 	}
+	return "", fmt.Errorf("decryption failed: %w", openErr)
+}
 
-	if len(nonce) != gcm.NonceSize() {
-		return "", fmt.Errorf("invalid nonce length: expected %d, got %d", gcm.NonceSize(), len(nonce))
+// ReEncryptForStorage returns value encrypted with the primary key. Values
+// already under the primary key are returned unchanged and plaintext values
+// are encrypted, so running it twice is harmless.
+func (cs *CryptoService) ReEncryptForStorage(value string) (string, error) {
+	if value == "" || !cs.NeedsReEncrypt(value) {
+		return value, nil
+	}
+	plaintext := value
+	if isEncryptedStorageValue(value) {
+		var err error
+		if plaintext, err = cs.DecryptFromStorage(value); err != nil {
+			return "", err
+		}
 	}
+	return cs.EncryptForStorage(plaintext)
+}
 
-	aad := composeAAD(aadParts)
-	plaintext, err := gcm.Open(nil, nonce, ciphertext, aad)
-	if err != nil {
-		return "", fmt.Errorf("decryption failed: %w", err)
+// NeedsReEncrypt reports whether a non-empty stored value is plaintext or
+// encrypted with a key other than the primary key
+func (cs *CryptoService) NeedsReEncrypt(value string) bool {
+	if value == "" {
+		return false
 	}
+	return StorageKeyID(value) != cs.keyring.PrimaryID()
+}
 
-	return string(plaintext), nil
+// StorageKeyID returns the data key ID of an encrypted storage value; it is
+// empty for plaintext and for v1 values
+func StorageKeyID(value string) string {
+	keyID, _ := splitStorageValue(value)
+	return keyID
 }
 
 func (cs *CryptoService) IsEncryptedStorageValue(value string) bool {
 	return isEncryptedStorageValue(value)
 }
 
+// splitStorageValue splits an encrypted storage value into its key ID (empty
+// for v1) and the "<nonce>:<ciphertext>" payload
+func splitStorageValue(value string) (keyID, payload string) {
+	if strings.HasPrefix(value, storagePrefixV1) {
+		return "", strings.TrimPrefix(value, storagePrefixV1)
+	}
+	if !strings.HasPrefix(value, storagePrefix) {
+		return "", ""
+	}
+	rest := strings.TrimPrefix(value, storagePrefix)
+	keyID, payload, _ = strings.Cut(rest, storageDelimiter)
+	return keyID, payload
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
 func composeAAD(parts []string) []byte {
 	if len(parts) == 0 {
 		return nil
@@ -278,7 +343,7 @@ func composeAAD(parts []string) []byte {
 }
 
 func isEncryptedStorageValue(value string) bool {
-	return strings.HasPrefix(value, storagePrefix)
+	return strings.HasPrefix(value, storagePrefix) || strings.HasPrefix(value, storagePrefixV1)
 }
 
 func (cs *CryptoService) DecryptPayload(payload *EncryptedPayload) ([]byte, error) {
@@ -0,0 +1,117 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EnvDataEncryptionKeysPrevious lists retired data keys (comma separated) that
+// are still accepted for decryption while their values are re-encrypted
+const EnvDataEncryptionKeysPrevious = "DATA_ENCRYPTION_KEYS_PREVIOUS"
+
+// Keyring holds the data encryption keys by key ID. The primary key encrypts;
+// every key decrypts.
+type Keyring struct {
+	primary string
+	keys    map[string][]byte
+	order   []string // primary first, then previous keys in the order given
+}
+
+// NewKeyring creates a keyring with primary as the encryption key and the
+// previous keys for decryption only. Duplicates are ignored.
+func NewKeyring(primary []byte, previous ...[]byte) (*Keyring, error) {
+	k := &Keyring{keys: make(map[string][]byte)}
+	for _, key := range append([][]byte{primary}, previous...) {
+		switch len(key) {
+		case 16, 24, 32:
+		default:
+			return nil, fmt.Errorf("data key must be 16, 24 or 32 bytes, got %d", len(key))
+		}
+		id := DataKeyID(key)
+		if _, ok := k.keys[id]; ok {
+			continue
+		}
+		k.keys[id] = key
+		k.order = append(k.order, id)
+	}
+	k.primary = k.order[0]
+	return k, nil
+}
+
+// DataKeyID the identifier stored with values encrypted by key. It is derived
+// from the key, so the same key always has the same ID and no registry is needed.
+func DataKeyID(key []byte) string {
+	sum := sha256.Sum256(append([]byte("nofx-data-key:"), key...))
+	return hex.EncodeToString(sum[:4])
+}
+
+// ParseDataKey decodes a data key the way DATA_ENCRYPTION_KEY is read: Base64
+// or hex of 16/24/32 bytes, otherwise the SHA-256 of the string
+func ParseDataKey(value string) ([]byte, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, errors.New("data key is empty")
+	}
+	if key, ok := decodePossibleKey(value); ok {
+		return key, nil
+	}
+	sum := sha256.Sum256([]byte(value))
+	key := make([]byte, len(sum))
+	copy(key, sum[:])
+	return key, nil
+}
+
+// WithPrimary returns a keyring that encrypts with key and still decrypts
+// with every key of k
+func (k *Keyring) WithPrimary(key []byte) (*Keyring, error) {
+	previous := make([][]byte, 0, len(k.order))
+	for _, id := range k.order {
+		previous = append(previous, k.keys[id])
+	}
+	return NewKeyring(key, previous...)
+}
+
+// PrimaryID the ID of the key new values are encrypted with
+func (k *Keyring) PrimaryID() string {
+	return k.primary
+}
+
+// IDs the IDs of all keys, primary first
+func (k *Keyring) IDs() []string {
+	return append([]string(nil), k.order...)
+}
+
+// Has reports whether the keyring holds the key with the given ID
+func (k *Keyring) Has(id string) bool {
+	_, ok := k.keys[id]
+	return ok
+}
+
+func (k *Keyring) primaryKey() []byte {
+	return k.keys[k.primary]
+}
+
+// loadKeyringFromEnv loads DATA_ENCRYPTION_KEY as the primary key and
+// DATA_ENCRYPTION_KEYS_PREVIOUS as decryption-only keys
+func loadKeyringFromEnv() (*Keyring, error) {
+	primary, err := loadDataKeyFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	var previous [][]byte
+	for _, s := range strings.Split(os.Getenv(EnvDataEncryptionKeysPrevious), ",") {
+		if strings.TrimSpace(s) == "" {
+			continue
+		}
+		key, err := ParseDataKey(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key in %s: %w", EnvDataEncryptionKeysPrevious, err)
+		}
+		previous = append(previous, key)
+	}
+	return NewKeyring(primary, previous...)
+}
@@ -0,0 +1,125 @@
+package crypto
+
+import (
+	"strings"
+	"testing"
+)
+
+func newTestService(t *testing.T) *CryptoService {
+	t.Helper()
+	privateKey, _, err := GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("generate key pair: %v", err)
+	}
+	dataKey, err := GenerateDataKey()
+	if err != nil {
+		t.Fatalf("generate data key: %v", err)
+	}
+	t.Setenv(EnvRSAPrivateKey, privateKey)
+	t.Setenv(EnvDataEncryptionKey, dataKey)
+	t.Setenv(EnvDataEncryptionKeysPrevious, "")
+	cs, err := NewCryptoService()
+	if err != nil {
+		t.Fatalf("crypto service: %v", err)
+	}
+	return cs
+}
+
+func newTestKey(t *testing.T) []byte {
+	t.Helper()
+	s, err := GenerateDataKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ParseDataKey(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestStorageValuesCarryKeyID(t *testing.T) {
+	cs := newTestService(t)
+	enc, err := cs.EncryptForStorage("secret")
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if !strings.HasPrefix(enc, "ENC:v2:"+cs.Keyring().PrimaryID()+":") {
+		t.Fatalf("encrypted value %q does not carry the key ID", enc)
+	}
+	if StorageKeyID(enc) != cs.Keyring().PrimaryID() || cs.NeedsReEncrypt(enc) {
+		t.Fatalf("StorageKeyID = %q", StorageKeyID(enc))
+	}
+	if plain, err := cs.DecryptFromStorage(enc); err != nil || plain != "secret" {
+		t.Fatalf("decrypt = %q, %v", plain, err)
+	}
+}
+
+func TestRotatedKeyringDecryptsOldValues(t *testing.T) {
+	cs := newTestService(t)
+	oldValue, err := cs.EncryptForStorage("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A v1 value has no key ID and must still decrypt
+	v1Value := storagePrefixV1 + strings.SplitN(oldValue, ":", 4)[3]
+
+	ring, err := cs.Keyring().WithPrimary(newTestKey(t))
+	if err != nil {
+		t.Fatalf("WithPrimary: %v", err)
+	}
+	rotated := cs.WithKeyring(ring)
+	for _, value := range []string{oldValue, v1Value} {
+		if !rotated.NeedsReEncrypt(value) {
+			t.Fatalf("%q should need re-encryption", value)
+		}
+		reEncrypted, err := rotated.ReEncryptForStorage(value)
+		if err != nil {
+			t.Fatalf("ReEncryptForStorage(%q): %v", value, err)
+		}
+		if StorageKeyID(reEncrypted) != ring.PrimaryID() {
+			t.Fatalf("re-encrypted value %q is not under the new key", reEncrypted)
+		}
+		if again, _ := rotated.ReEncryptForStorage(reEncrypted); again != reEncrypted {
+			t.Fatal("re-encrypting a current value changed it")
+		}
+
+		// Once the old key is retired only the new values decrypt
+		retired, _ := NewKeyring(rotated.Keyring().primaryKey())
+		newOnly := cs.WithKeyring(retired)
+		if plain, err := newOnly.DecryptFromStorage(reEncrypted); err != nil || plain != "secret" {
+			t.Fatalf("decrypt with the new key = %q, %v", plain, err)
+		}
+		if _, err := newOnly.DecryptFromStorage(value); err == nil {
+			t.Fatalf("%q decrypted without the old key", value)
+		}
+	}
+}
+
+func TestReEncryptEncryptsPlaintext(t *testing.T) {
+	cs := newTestService(t)
+	enc, err := cs.ReEncryptForStorage("legacy-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain, err := cs.DecryptFromStorage(enc); err != nil || plain != "legacy-token" {
+		t.Fatalf("decrypt = %q, %v", plain, err)
+	}
+}
+
+func TestKeyringFromEnvIncludesPreviousKeys(t *testing.T) {
+	old, err := GenerateDataKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs := newTestService(t)
+	t.Setenv(EnvDataEncryptionKeysPrevious, " "+old+", ")
+	ring, err := loadKeyringFromEnv()
+	if err != nil {
+		t.Fatalf("loadKeyringFromEnv: %v", err)
+	}
+	oldKey, _ := ParseDataKey(old)
+	if ids := ring.IDs(); len(ids) != 2 || ids[0] != cs.Keyring().PrimaryID() || ids[1] != DataKeyID(oldKey) {
+		t.Fatalf("keyring IDs = %v", ids)
+	}
+}
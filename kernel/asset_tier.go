@@ -0,0 +1,74 @@
+package kernel
+
+import (
+	"fmt"
+	"strings"
+
+	"nofx/market"
+	"nofx/provider/hyperliquid"
+	"nofx/store"
+)
+
+// AssetInfoFor returns what the asset tier rules know about a symbol: its
+// category (crypto, or the Hyperliquid XYZ category) and, when a tier needs
+// it, the market cap of its base coin
+func AssetInfoFor(riskControl store.RiskControlConfig, symbol string) store.AssetInfo {
+	base := store.AssetBase(symbol)
+	info := store.AssetInfo{Category: store.AssetCategoryCrypto}
+	if market.IsXyzDexAsset(symbol) {
+		info.Category = hyperliquid.XYZCategory(base)
+	} else if riskControl.UsesMarketCap() {
+		info.MarketCapUSD, _ = market.MarketCapUSD(symbol, base)
+	}
+	return info
+}
+
+// ResolveAssetTier returns the asset tier whose limits apply to symbol
+func ResolveAssetTier(riskControl store.RiskControlConfig, symbol string) store.AssetTierConfig {
+	return riskControl.AssetTierFor(symbol, AssetInfoFor(riskControl, symbol))
+}
+
+// writeAssetTierConstraints writes the limits of each configured asset tier
+// into the hard constraints; the USDT values follow in the Account Limits
+// suffix
+func writeAssetTierConstraints(sb *strings.Builder, riskControl store.RiskControlConfig) {
+	sb.WriteString("- Asset Tiers (a symbol uses the first tier listing it, otherwise the first tier whose rule matches):\n")
+	hasCatchAll := false
+	for i := range riskControl.AssetTiers {
+		t := &riskControl.AssetTiers[i]
+		hasCatchAll = hasCatchAll || t.MatchesAll()
+		sb.WriteString(fmt.Sprintf("  - %s (%s): %s\n", t.Name, t.Describe(), assetTierLimits(t)))
+	}
+	if !hasCatchAll {
+		fallback := riskControl.FallbackAssetTier()
+		sb.WriteString(fmt.Sprintf("  - %s (all other symbols): %s\n", fallback.Name, assetTierLimits(&fallback)))
+	}
+}
+
+func assetTierLimits(t *store.AssetTierConfig) string {
+	limits := fmt.Sprintf("leverage max %dx | position value max equity × %.1fx", t.MaxLeverage, t.MaxPositionValueRatio)
+	if t.MinPositionSize > 0 {
+		limits += fmt.Sprintf(" | min size %.0f USDT", t.MinPositionSize)
+	}
+	if t.MaxPositions > 0 {
+		limits += fmt.Sprintf(" | max %d open positions", t.MaxPositions)
+	}
+	return limits
+}
+
+// writeAssetTierAccountLimits writes the USDT position value limit of each
+// configured asset tier for the current equity
+func writeAssetTierAccountLimits(sb *strings.Builder, accountEquity float64, riskControl store.RiskControlConfig) {
+	tiers := riskControl.AssetTiers
+	hasCatchAll := false
+	for _, t := range tiers {
+		hasCatchAll = hasCatchAll || t.MatchesAll()
+	}
+	if !hasCatchAll {
+		tiers = append(tiers[:len(tiers):len(tiers)], riskControl.FallbackAssetTier())
+	}
+	for _, t := range tiers {
+		sb.WriteString(fmt.Sprintf("- Position Value Limit (%s tier): max %.0f USDT (= equity %.0f × %.1fx)\n",
+			t.Name, accountEquity*t.MaxPositionValueRatio, accountEquity, t.MaxPositionValueRatio))
+	}
+}
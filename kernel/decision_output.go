@@ -0,0 +1,152 @@
+package kernel
+
+import (
+	"context"
+	"encoding/json"
+	"nofx/logger"
+	"nofx/mcp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Decision output modes (FullDecision.OutputMode)
+const (
+	DecisionOutputStructured = "structured" // parsed from the provider's native structured output
+	DecisionOutputText       = "text"       // recovered from free text by extractDecisions
+	DecisionOutputFailed     = "failed"     // no decision could be parsed
+)
+
+// structuredOutputNote overrides the tag-based output format of the prompts
+// when the reply is constrained to the decision schema
+const structuredOutputNote = "\n\n# OUTPUT FORMAT OVERRIDE\n" +
+	"Reply with the " + DecisionToolName + " JSON object only: put your full analysis in \"reasoning\" " +
+	"and the decision array in \"decisions\". Do not use <reasoning> or <decision> tags."
+
+// decisionCallResult the reply of a decision call
+type decisionCallResult struct {
+	raw          string                   // reply text (the JSON object in structured mode)
+	systemPrompt string                   // system prompt actually sent
+	reply        *structuredDecisionReply // set when the structured reply parsed
+	structured   bool                     // structured output was requested and answered
+	provider     string
+}
+
+// callDecisionModel asks the model for decisions, constraining the reply to
+// the decision schema when the provider supports structured output. A
+// provider rejecting the schema falls back to a plain text call.
+// The system prompt prefix is sent as a cacheable message; the text
+// fallback sends the prompt as one string, prefix first, which keeps
+// automatic prefix caching on providers that have it.
+// ctx carries the trace of the structured call; the text fallback has no
+// request to carry it.
+func callDecisionModel(ctx context.Context, mcpClient mcp.AIClient, systemPrompt PromptParts, userPrompt string, actions []string) (*decisionCallResult, error) {
+	res := &decisionCallResult{systemPrompt: systemPrompt.String(), provider: "unknown"}
+	supported := false
+	if embedder, ok := mcpClient.(mcp.ClientEmbedder); ok {
+		base := embedder.BaseClient()
+		res.provider = base.Provider
+		supported = base.SupportsStructuredOutput()
+	}
+
+	if supported {
+		// The override is static, so it belongs to the cached prefix
+		structuredPrompt := PromptParts{Prefix: systemPrompt.Prefix + structuredOutputNote + "\n\n", Suffix: systemPrompt.Suffix}
+		messages := append(mcp.NewCachedSystemMessages(structuredPrompt.Prefix, structuredPrompt.Suffix), mcp.NewUserMessage(userPrompt))
+		resp, err := mcpClient.CallWithRequestFull(&mcp.Request{
+			Ctx:      ctx,
+			Messages: messages,
+			ResponseFormat: &mcp.ResponseFormat{
+				Name:        DecisionToolName,
+				Description: "Submit the trading decisions for this cycle",
+				Schema:      DecisionJSONSchema(actions),
+			},
+		})
+		if err == nil {
+			res.structured = true
+			res.systemPrompt = structuredPrompt.String()
+			res.raw = resp.StructuredContent(DecisionToolName)
+			var reply structuredDecisionReply
+			if jerr := json.Unmarshal([]byte(strings.TrimSpace(res.raw)), &reply); jerr == nil && len(reply.Decisions) > 0 {
+				res.reply = &reply
+			} else {
+				logger.Warnf("⚠️  [%s] Structured decision reply did not match the schema, falling back to text parsing", res.provider)
+			}
+			return res, nil
+		}
+		if !mcp.IsSchemaRejection(err) {
+			return nil, err
+		}
+		logger.Warnf("⚠️  [%s] Structured output rejected, retrying as text: %v", res.provider, err)
+	}
+
+	raw, err := mcpClient.CallWithMessages(systemPrompt.String(), userPrompt)
+	if err != nil {
+		return nil, err
+	}
+	res.raw = raw
+	return res, nil
+}
+
+// isSafeWaitFallback reports whether decisions are the safe-wait placeholder
+// extractDecisions returns when the reply contained no JSON
+func isSafeWaitFallback(decisions []Decision) bool {
+	return len(decisions) == 1 && decisions[0].Symbol == safeWaitSymbol && decisions[0].Action == "wait"
+}
+
+// ============================================================================
+// Parse outcome statistics
+// ============================================================================
+
+// DecisionParseStats decision parse outcomes of one provider since startup
+type DecisionParseStats struct {
+	Provider           string  `json:"provider"`
+	Calls              int64   `json:"calls"`
+	Structured         int64   `json:"structured"`          // parsed from native structured output
+	StructuredFallback int64   `json:"structured_fallback"` // structured reply had to be recovered by the text parser
+	Text               int64   `json:"text"`                // text-only provider, parsed by the text parser
+	Failures           int64   `json:"failures"`            // no decision could be parsed
+	FailureRate        float64 `json:"failure_rate"`        // failures / calls
+}
+
+var decisionParseStats = struct {
+	sync.Mutex
+	byProvider map[string]*DecisionParseStats
+}{byProvider: make(map[string]*DecisionParseStats)}
+
+// recordDecisionParse counts the outcome of one decision call
+func recordDecisionParse(provider string, structured bool, mode string) {
+	decisionParseStats.Lock()
+	defer decisionParseStats.Unlock()
+
+	stats, ok := decisionParseStats.byProvider[provider]
+	if !ok {
+		stats = &DecisionParseStats{Provider: provider}
+		decisionParseStats.byProvider[provider] = stats
+	}
+	stats.Calls++
+	switch {
+	case mode == DecisionOutputFailed:
+		stats.Failures++
+	case mode == DecisionOutputStructured:
+		stats.Structured++
+	case structured:
+		stats.StructuredFallback++
+	default:
+		stats.Text++
+	}
+	stats.FailureRate = float64(stats.Failures) / float64(stats.Calls)
+}
+
+// GetDecisionParseStats returns parse outcome counters per provider
+func GetDecisionParseStats() []DecisionParseStats {
+	decisionParseStats.Lock()
+	defer decisionParseStats.Unlock()
+
+	result := make([]DecisionParseStats, 0, len(decisionParseStats.byProvider))
+	for _, stats := range decisionParseStats.byProvider {
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Provider < result[j].Provider })
+	return result
+}
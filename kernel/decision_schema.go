@@ -0,0 +1,130 @@
+package kernel
+
+import (
+	"reflect"
+	"strings"
+)
+
+// ============================================================================
+// Structured Decision Output
+// ============================================================================
+// The decision call asks providers with native structured output (OpenAI
+// response_format, Anthropic tool use, Gemini responseSchema) for a JSON
+// object generated from Decision instead of free text. extractDecisions
+// remains the fallback for every other provider and for replies that do not
+// match the schema.
+// ============================================================================
+
+// DecisionToolName names the structured reply (the Anthropic tool / OpenAI schema)
+const DecisionToolName = "submit_decisions"
+
+// StandardDecisionActions actions accepted by validateDecision
+var StandardDecisionActions = []string{"open_long", "open_short", "close_long", "close_short", "hold", "wait"}
+
+// GridDecisionActions actions accepted by isValidGridAction
+var GridDecisionActions = []string{
+	"place_buy_limit", "place_sell_limit", "cancel_order", "cancel_all_orders",
+	"pause_grid", "resume_grid", "adjust_grid", "hold",
+	"open_long", "open_short", "close_long", "close_short",
+}
+
+// decisionFieldDescriptions schema descriptions of Decision fields, keyed by JSON name
+var decisionFieldDescriptions = map[string]string{
+	"symbol":            "Trading pair, e.g. BTCUSDT",
+	"action":            "Action to take",
+	"leverage":          "Leverage for open actions",
+	"position_size_usd": "Position notional value in USD for open actions",
+	"stop_loss":         "Stop-loss price for open actions",
+	"take_profit":       "Take-profit price for open actions",
+	"price":             "Limit order price (grid)",
+	"quantity":          "Order quantity (grid)",
+	"level_index":       "Grid level index (grid)",
+	"order_id":          "Order ID to cancel (grid)",
+	"confidence":        "Confidence level 0-100",
+	"risk_usd":          "Maximum USD risk",
+	"reasoning":         "Short justification of this decision",
+}
+
+// structuredDecisionReply the object returned through the decision schema
+type structuredDecisionReply struct {
+	Reasoning string     `json:"reasoning"`
+	Decisions []Decision `json:"decisions"`
+}
+
+// DecisionJSONSchema generates the JSON schema of the structured decision
+// reply from the Decision struct. actions restricts the "action" enum.
+func DecisionJSONSchema(actions []string) map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"reasoning": map[string]any{
+				"type":        "string",
+				"description": "Chain of thought: market analysis that leads to the decisions",
+			},
+			"decisions": map[string]any{
+				"type":  "array",
+				"items": decisionItemSchema(actions),
+			},
+		},
+		"required":             []string{"reasoning", "decisions"},
+		"additionalProperties": false,
+	}
+}
+
+// decisionItemSchema reflects Decision into an object schema. Fields without
+// omitempty are required.
+func decisionItemSchema(actions []string) map[string]any {
+	properties := make(map[string]any)
+	var required []string
+
+	t := reflect.TypeOf(Decision{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		name := parts[0]
+
+		prop := map[string]any{"type": jsonSchemaType(field.Type.Kind())}
+		if desc := decisionFieldDescriptions[name]; desc != "" {
+			prop["description"] = desc
+		}
+		if name == "action" && len(actions) > 0 {
+			prop["enum"] = actions
+		}
+		properties[name] = prop
+
+		omitempty := false
+		for _, opt := range parts[1:] {
+			if opt == "omitempty" {
+				omitempty = true
+			}
+		}
+		if !omitempty {
+			required = append(required, name)
+		}
+	}
+
+	return map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+func jsonSchemaType(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	default:
+		return "string"
+	}
+}
@@ -0,0 +1,158 @@
+package kernel
+
+import (
+	"context"
+	"testing"
+
+	"nofx/mcp"
+)
+
+func TestDecisionJSONSchemaFromStruct(t *testing.T) {
+	schema := DecisionJSONSchema(StandardDecisionActions)
+	items := schema["properties"].(map[string]any)["decisions"].(map[string]any)["items"].(map[string]any)
+	props := items["properties"].(map[string]any)
+
+	if got := props["leverage"].(map[string]any)["type"]; got != "integer" {
+		t.Errorf("leverage type = %v, want integer", got)
+	}
+	if got := props["position_size_usd"].(map[string]any)["type"]; got != "number" {
+		t.Errorf("position_size_usd type = %v, want number", got)
+	}
+	if enum := props["action"].(map[string]any)["enum"].([]string); len(enum) != len(StandardDecisionActions) {
+		t.Errorf("action enum = %v", enum)
+	}
+
+	required := map[string]bool{}
+	for _, name := range items["required"].([]string) {
+		required[name] = true
+	}
+	if !required["symbol"] || !required["action"] || !required["reasoning"] || required["leverage"] {
+		t.Errorf("required fields should be those without omitempty, got %v", items["required"])
+	}
+}
+
+// structuredStubClient answers structured requests with a canned reply
+type structuredStubClient struct {
+	*mcp.Client
+	full      *mcp.LLMResponse
+	fullErr   error
+	text      string
+	textCalls int
+	lastReq   *mcp.Request
+}
+
+func (c *structuredStubClient) CallWithRequestFull(req *mcp.Request) (*mcp.LLMResponse, error) {
+	c.lastReq = req
+	return c.full, c.fullErr
+}
+
+func (c *structuredStubClient) CallWithMessages(systemPrompt, userPrompt string) (string, error) {
+	c.textCalls++
+	return c.text, nil
+}
+
+func newStructuredStub(provider string) *structuredStubClient {
+	base := mcp.NewClient(mcp.WithProvider(provider), mcp.WithLogger(mcp.NewNoopLogger())).(*mcp.Client)
+	return &structuredStubClient{Client: base}
+}
+
+func TestCallDecisionModelStructured(t *testing.T) {
+	stub := newStructuredStub(mcp.ProviderClaude)
+	stub.full = &mcp.LLMResponse{ToolCalls: []mcp.ToolCall{{
+		Type: "function",
+		Function: mcp.ToolCallFunction{
+			Name:      DecisionToolName,
+			Arguments: `{"reasoning":"trend up","decisions":[{"symbol":"BTCUSDT","action":"hold","reasoning":"wait"}]}`,
+		},
+	}}}
+
+	res, err := callDecisionModel(context.Background(), stub, PromptParts{Prefix: "sys"}, "user", StandardDecisionActions)
+	if err != nil {
+		t.Fatalf("callDecisionModel: %v", err)
+	}
+	if res.reply == nil || res.reply.Reasoning != "trend up" || len(res.reply.Decisions) != 1 {
+		t.Fatalf("expected structured reply, got %+v", res.reply)
+	}
+	if stub.lastReq.ResponseFormat == nil || stub.lastReq.ResponseFormat.Name != DecisionToolName {
+		t.Fatalf("expected response format on the request")
+	}
+	if stub.textCalls != 0 {
+		t.Fatalf("text call should not be made")
+	}
+}
+
+func TestCallDecisionModelFallbacks(t *testing.T) {
+	// Schema rejected by the API: retry as text
+	stub := newStructuredStub(mcp.ProviderOpenAI)
+	stub.fullErr = errString("API returned error (status 400): response_format is not supported")
+	stub.text = `<decision>[{"symbol":"BTCUSDT","action":"wait","reasoning":"x"}]</decision>`
+	res, err := callDecisionModel(context.Background(), stub, PromptParts{Prefix: "sys"}, "user", StandardDecisionActions)
+	if err != nil || res.structured || res.reply != nil || stub.textCalls != 1 {
+		t.Fatalf("expected text fallback, got %+v err=%v", res, err)
+	}
+
+	// Structured reply that does not match the schema: parsed as text
+	stub = newStructuredStub(mcp.ProviderOpenAI)
+	stub.full = &mcp.LLMResponse{Content: `[{"symbol":"BTCUSDT","action":"wait","reasoning":"x"}]`}
+	res, err = callDecisionModel(context.Background(), stub, PromptParts{Prefix: "sys"}, "user", StandardDecisionActions)
+	if err != nil || !res.structured || res.reply != nil {
+		t.Fatalf("expected unparsed structured reply, got %+v err=%v", res, err)
+	}
+
+	// Provider without structured output: plain text call
+	stub = newStructuredStub(mcp.ProviderDeepSeek)
+	stub.text = "no json"
+	if _, err := callDecisionModel(context.Background(), stub, PromptParts{Prefix: "sys"}, "user", StandardDecisionActions); err != nil || stub.textCalls != 1 || stub.lastReq != nil {
+		t.Fatalf("expected only a text call for deepseek, err=%v", err)
+	}
+}
+
+func TestStructuredRejectionRetriesOnSameChainMember(t *testing.T) {
+	primary := newStructuredStub(mcp.ProviderOpenAI)
+	primary.fullErr = errString("API returned error (status 400): response_format is not supported")
+	primary.text = `<decision>[{"symbol":"BTCUSDT","action":"wait","reasoning":"x"}]</decision>`
+	secondary := newStructuredStub(mcp.ProviderClaude)
+	chain := mcp.NewFailoverClient([]mcp.FailoverMember{
+		{Name: "primary", Client: primary},
+		{Name: "backup", Client: secondary},
+	}, mcp.NewNoopLogger())
+
+	res, err := callDecisionModel(context.Background(), chain, PromptParts{Prefix: "sys"}, "user", StandardDecisionActions)
+	if err != nil || res.structured {
+		t.Fatalf("expected a text reply, err=%v", err)
+	}
+	if primary.textCalls != 1 {
+		t.Errorf("text retry should reach the primary, got %d calls", primary.textCalls)
+	}
+	if secondary.lastReq != nil || secondary.textCalls != 0 {
+		t.Error("a schema rejection must not fall over to the next member")
+	}
+	if name, _, _ := chain.LastAnswered(); name != "primary" {
+		t.Errorf("LastAnswered = %s, want primary", name)
+	}
+	if status := chain.BreakerStatus()[0]; status.ConsecutiveFailures != 0 {
+		t.Errorf("schema rejection counted as a breaker failure: %+v", status)
+	}
+}
+
+func TestRecordDecisionParse(t *testing.T) {
+	recordDecisionParse("test-provider", true, DecisionOutputStructured)
+	recordDecisionParse("test-provider", true, DecisionOutputText)
+	recordDecisionParse("test-provider", false, DecisionOutputFailed)
+	recordDecisionParse("test-provider", false, DecisionOutputText)
+
+	for _, stats := range GetDecisionParseStats() {
+		if stats.Provider != "test-provider" {
+			continue
+		}
+		if stats.Calls != 4 || stats.Structured != 1 || stats.StructuredFallback != 1 || stats.Text != 1 || stats.Failures != 1 || stats.FailureRate != 0.25 {
+			t.Fatalf("unexpected stats: %+v", stats)
+		}
+		return
+	}
+	t.Fatalf("provider stats not recorded")
+}
+
+type errString string
+
+func (e errString) Error() string { return string(e) }
@@ -13,11 +13,14 @@ import (
 	"nofx/provider/vergex"
 	"nofx/security"
 	"nofx/store"
+	"nofx/tracing"
 	"os"
 	"sort"
 	"strings"
 	"sync"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // ============================================================================
@@ -38,6 +41,7 @@ type PositionInfo struct {
 	LiquidationPrice float64 `json:"liquidation_price"`
 	MarginUsed       float64 `json:"margin_used"`
 	UpdateTime       int64   `json:"update_time"` // Position update timestamp (milliseconds)
+	FundingFee       float64 `json:"funding_fee"` // Funding settled while held, positive = received
 }
 
 // AccountInfo account information
@@ -93,6 +97,8 @@ type RecentOrder struct {
 
 // Context trading context (complete information passed to AI)
 type Context struct {
+	Log                *logger.Scope                      `json:"-"` // trader's correlation fields; nil logs without them
+	TraceCtx           context.Context                    `json:"-"` // parent of the analysis spans (the trader's cycle span); nil starts new traces
 	CurrentTime        string                             `json:"current_time"`
 	RuntimeMinutes     int                                `json:"runtime_minutes"`
 	CallCount          int                                `json:"call_count"`
@@ -100,6 +106,7 @@ type Context struct {
 	Positions          []PositionInfo                     `json:"positions"`
 	CandidateCoins     []CandidateCoin                    `json:"candidate_coins"`
 	PromptVariant      string                             `json:"prompt_variant,omitempty"`
+	MarketRegime       *MarketRegime                      `json:"market_regime,omitempty"` // set when the strategy selects the variant by regime
 	TradingStats       *TradingStats                      `json:"trading_stats,omitempty"`
 	RecentOrders       []RecentOrder                      `json:"recent_orders,omitempty"`
 	MarketDataMap      map[string]*market.Data            `json:"-"`
@@ -141,13 +148,16 @@ type Decision struct {
 
 // FullDecision AI's complete decision (including chain of thought)
 type FullDecision struct {
-	SystemPrompt        string     `json:"system_prompt"`
-	UserPrompt          string     `json:"user_prompt"`
-	CoTTrace            string     `json:"cot_trace"`
-	Decisions           []Decision `json:"decisions"`
-	RawResponse         string     `json:"raw_response"`
-	Timestamp           time.Time  `json:"timestamp"`
-	AIRequestDurationMs int64      `json:"ai_request_duration_ms,omitempty"`
+	SystemPrompt        string        `json:"system_prompt"`
+	UserPrompt          string        `json:"user_prompt"`
+	CoTTrace            string        `json:"cot_trace"`
+	Decisions           []Decision    `json:"decisions"`
+	RawResponse         string        `json:"raw_response"`
+	Timestamp           time.Time     `json:"timestamp"`
+	AIRequestDurationMs int64         `json:"ai_request_duration_ms,omitempty"`
+	OutputMode          string        `json:"output_mode,omitempty"` // structured / text / failed
+	PromptVariant       string        `json:"prompt_variant,omitempty"`
+	MarketRegime        *MarketRegime `json:"market_regime,omitempty"`
 }
 
 // QuantData quantitative data structure (fund flow, position changes, price changes)
@@ -1098,13 +1108,19 @@ func (e *StrategyEngine) FetchQuantData(symbol string) (*QuantData, error) {
 }
 
 // FetchQuantDataBatch batch fetches quantitative data
-func (e *StrategyEngine) FetchQuantDataBatch(symbols []string) map[string]*QuantData {
+func (e *StrategyEngine) FetchQuantDataBatch(ctx context.Context, symbols []string) map[string]*QuantData {
 	result := make(map[string]*QuantData)
 
 	if !e.config.Indicators.EnableQuantData {
 		return result
 	}
 
+	_, span := tracing.Start(ctx, "kernel.FetchQuantDataBatch", attribute.Int("symbols", len(symbols)))
+	defer func() {
+		span.SetAttributes(attribute.Int("fetched", len(result)))
+		span.End()
+	}()
+
 	for _, symbol := range symbols {
 		data, err := e.FetchQuantData(symbol)
 		if err != nil {
@@ -1128,9 +1144,11 @@ func (e *StrategyEngine) FetchVergexDataBatch(ctx context.Context, symbols []str
 		logger.Warnf("⚠️ Vergex signal data skipped: claw402 wallet is not configured")
 		return result
 	}
-	if ctx == nil {
-		ctx = context.Background()
-	}
+	ctx, span := tracing.Start(ctx, "kernel.FetchVergexDataBatch", attribute.Int("symbols", len(symbols)))
+	defer func() {
+		span.SetAttributes(attribute.Int("fetched", len(result)))
+		span.End()
+	}()
 
 	source := e.config.CoinSource
 	marketType := source.VergexMarketType
@@ -6,10 +6,14 @@ import (
 	"nofx/logger"
 	"nofx/market"
 	"nofx/mcp"
+	"nofx/metrics"
 	"nofx/store"
+	"nofx/tracing"
 	"regexp"
 	"strings"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // ============================================================================
@@ -68,19 +72,24 @@ func GetFullDecisionWithStrategy(ctx *Context, mcpClient mcp.AIClient, engine *S
 	}
 
 	if estimate.Total > contextLimit {
-		logger.Errorf("🚫 Token estimate %d exceeds %s context limit %d — blocking analysis",
+		ctx.Log.Errorf("🚫 Token estimate %d exceeds %s context limit %d — blocking analysis",
 			estimate.Total, providerName, contextLimit)
 		return nil, fmt.Errorf("estimated %d tokens exceeds model context limit of %d; reduce coins, timeframes, or K-line count",
 			estimate.Total, contextLimit)
 	}
 	if estimate.Total*100/contextLimit >= 80 {
-		logger.Infof("⚠️  Token estimate %d — approaching %s context limit %d",
+		ctx.Log.Infof("⚠️  Token estimate %d — approaching %s context limit %d",
 			estimate.Total, providerName, contextLimit)
 	}
 
 	// 1. Fetch market data using strategy config
 	if len(ctx.MarketDataMap) == 0 {
-		if err := fetchMarketDataWithStrategy(ctx, engine); err != nil {
+		_, span := tracing.Start(ctx.TraceCtx, "kernel.fetchMarketData",
+			attribute.Int("positions", len(ctx.Positions)), attribute.Int("candidates", len(ctx.CandidateCoins)))
+		err := fetchMarketDataWithStrategy(ctx, engine)
+		span.SetAttributes(attribute.Int("fetched", len(ctx.MarketDataMap)))
+		tracing.End(span, err)
+		if err != nil {
 			return nil, fmt.Errorf("failed to fetch market data: %w", err)
 		}
 	}
@@ -103,40 +112,65 @@ func GetFullDecisionWithStrategy(ctx *Context, mcpClient mcp.AIClient, engine *S
 		}
 	}
 
+	// Select the prompt variant from the market regime when the strategy asks for it
+	if regime := engine.detectRegimeForCycle(ctx); regime != nil {
+		ctx.MarketRegime = regime
+		variant = regime.Variant
+		ctx.Log.Infof("🧭 Market regime: %s → %s mode", regime, variant)
+	}
+	if variant == "" {
+		variant = store.PromptVariantBalanced
+	}
+	ctx.PromptVariant = variant
+
 	// 2. Build System Prompt using strategy engine
 	riskConfig := engine.GetRiskControlConfig()
-	systemPrompt := engine.BuildSystemPrompt(ctx.Account.TotalEquity, variant)
+	systemPrompt := engine.BuildSystemPromptParts(ctx.Account.TotalEquity, variant)
 
 	// 3. Build User Prompt using strategy engine
 	userPrompt := engine.BuildUserPrompt(ctx)
 
-	// 4. Call AI API
+	// 4. Call AI API (structured output where the provider supports it)
 	aiCallStart := time.Now()
-	aiResponse, err := mcpClient.CallWithMessages(systemPrompt, userPrompt)
+	aiCtx, aiSpan := tracing.Start(ctx.TraceCtx, "kernel.aiDecision", attribute.String("ai.provider", providerName))
+	reply, err := callDecisionModel(aiCtx, mcpClient, systemPrompt, userPrompt, StandardDecisionActions)
+	tracing.End(aiSpan, err)
 	aiCallDuration := time.Since(aiCallStart)
+	metrics.ObserveAIRequest(providerName, aiCallDuration, err)
 	if err != nil {
 		return nil, fmt.Errorf("AI API call failed: %w", err)
 	}
 
-	// 5. Parse AI response
-	decision, err := parseFullDecisionResponse(
-		aiResponse,
-		ctx.Account.TotalEquity,
-		riskConfig.BTCETHMaxLeverage,
-		riskConfig.AltcoinMaxLeverage,
-		riskConfig.BTCETHMaxPositionValueRatio,
-		riskConfig.AltcoinMaxPositionValueRatio,
-	)
+	// 5. Parse AI response: the structured reply when it matched the schema,
+	// otherwise recover decisions from the text
+	var decision *FullDecision
+	if reply.reply != nil {
+		decision, err = parseStructuredDecisionReply(
+			reply.reply,
+			ctx.Account.TotalEquity,
+			riskConfig,
+		)
+	} else {
+		decision, err = parseFullDecisionResponse(
+			reply.raw,
+			ctx.Account.TotalEquity,
+			riskConfig,
+		)
+	}
 
 	if decision != nil {
 		decision.Timestamp = time.Now()
-		decision.SystemPrompt = systemPrompt
+		decision.SystemPrompt = reply.systemPrompt
 		decision.UserPrompt = userPrompt
 		decision.AIRequestDurationMs = aiCallDuration.Milliseconds()
-		decision.RawResponse = aiResponse
+		decision.RawResponse = reply.raw
+		decision.PromptVariant = variant
+		decision.MarketRegime = ctx.MarketRegime
+		recordDecisionParse(reply.provider, reply.structured, decision.OutputMode)
 	}
 
 	if err != nil {
+		metrics.IncAIParseFailure(providerName)
 		return decision, fmt.Errorf("failed to parse AI response: %w", err)
 	}
 
@@ -164,7 +198,7 @@ func enrichVergexDataWithStrategy(ctx *Context, engine *StrategyEngine) {
 			symbols = append(symbols, pos.Symbol)
 		}
 	}
-	ctx.VergexDataMap = engine.FetchVergexDataBatch(nil, symbols)
+	ctx.VergexDataMap = engine.FetchVergexDataBatch(ctx.TraceCtx, symbols)
 }
 
 // ============================================================================
@@ -198,13 +232,13 @@ func fetchMarketDataWithStrategy(ctx *Context, engine *StrategyEngine) error {
 		klineCount = 30
 	}
 
-	logger.Infof("📊 Strategy timeframes: %v, Primary: %s, Kline count: %d", timeframes, primaryTimeframe, klineCount)
+	ctx.Log.Infof("📊 Strategy timeframes: %v, Primary: %s, Kline count: %d", timeframes, primaryTimeframe, klineCount)
 
 	// 1. First fetch data for position coins (must fetch)
 	for _, pos := range ctx.Positions {
 		data, err := market.GetWithTimeframes(pos.Symbol, timeframes, primaryTimeframe, klineCount)
 		if err != nil {
-			logger.Infof("⚠️  Failed to fetch market data for position %s: %v", pos.Symbol, err)
+			ctx.Log.Infof("⚠️  Failed to fetch market data for position %s: %v", pos.Symbol, err)
 			continue
 		}
 		ctx.MarketDataMap[pos.Symbol] = data
@@ -225,7 +259,7 @@ func fetchMarketDataWithStrategy(ctx *Context, engine *StrategyEngine) error {
 
 		data, err := market.GetWithTimeframes(coin.Symbol, timeframes, primaryTimeframe, klineCount)
 		if err != nil {
-			logger.Infof("⚠️  Failed to fetch market data for %s: %v", coin.Symbol, err)
+			ctx.Log.Infof("⚠️  Failed to fetch market data for %s: %v", coin.Symbol, err)
 			continue
 		}
 
@@ -236,7 +270,7 @@ func fetchMarketDataWithStrategy(ctx *Context, engine *StrategyEngine) error {
 			oiValue := data.OpenInterest.Latest * data.CurrentPrice
 			oiValueInMillions := oiValue / 1_000_000
 			if oiValueInMillions < minOIThresholdMillions {
-				logger.Infof("⚠️  %s OI value too low (%.2fM USD < %.1fM), skipping coin",
+				ctx.Log.Infof("⚠️  %s OI value too low (%.2fM USD < %.1fM), skipping coin",
 					coin.Symbol, oiValueInMillions, minOIThresholdMillions)
 				continue
 			}
@@ -245,7 +279,7 @@ func fetchMarketDataWithStrategy(ctx *Context, engine *StrategyEngine) error {
 		ctx.MarketDataMap[coin.Symbol] = data
 	}
 
-	logger.Infof("📊 Successfully fetched multi-timeframe market data for %d coins", len(ctx.MarketDataMap))
+	ctx.Log.Infof("📊 Successfully fetched multi-timeframe market data for %d coins", len(ctx.MarketDataMap))
 	return nil
 }
 
@@ -259,7 +293,7 @@ func pruneCandidateCoinsWithoutMarketData(ctx *Context) {
 			kept = append(kept, coin)
 			continue
 		}
-		logger.Infof("⚠️  Skipping candidate %s in AI prompt: no valid market/K-line data", coin.Symbol)
+		ctx.Log.Infof("⚠️  Skipping candidate %s in AI prompt: no valid market/K-line data", coin.Symbol)
 	}
 	ctx.CandidateCoins = kept
 }
@@ -268,27 +302,47 @@ func pruneCandidateCoinsWithoutMarketData(ctx *Context) {
 // AI Response Parsing
 // ============================================================================
 
-func parseFullDecisionResponse(aiResponse string, accountEquity float64, btcEthLeverage, altcoinLeverage int, btcEthPosRatio, altcoinPosRatio float64) (*FullDecision, error) {
+// safeWaitSymbol marks the wait decision substituted for a reply without JSON
+const safeWaitSymbol = "ALL"
+
+func parseFullDecisionResponse(aiResponse string, accountEquity float64, riskControl store.RiskControlConfig) (*FullDecision, error) {
 	cotTrace := extractCoTTrace(aiResponse)
 
 	decisions, err := extractDecisions(aiResponse)
 	if err != nil {
 		return &FullDecision{
-			CoTTrace:  cotTrace,
-			Decisions: []Decision{},
+			CoTTrace:   cotTrace,
+			Decisions:  []Decision{},
+			OutputMode: DecisionOutputFailed,
 		}, fmt.Errorf("failed to extract decisions: %w", err)
 	}
 
-	if err := validateDecisions(decisions, accountEquity, btcEthLeverage, altcoinLeverage, btcEthPosRatio, altcoinPosRatio); err != nil {
+	mode := DecisionOutputText
+	if isSafeWaitFallback(decisions) {
+		mode = DecisionOutputFailed
+	}
+	return validatedFullDecision(cotTrace, decisions, mode, accountEquity, riskControl)
+}
+
+// parseStructuredDecisionReply validates decisions returned through the decision schema
+func parseStructuredDecisionReply(reply *structuredDecisionReply, accountEquity float64, riskControl store.RiskControlConfig) (*FullDecision, error) {
+	return validatedFullDecision(strings.TrimSpace(reply.Reasoning), reply.Decisions, DecisionOutputStructured,
+		accountEquity, riskControl)
+}
+
+func validatedFullDecision(cotTrace string, decisions []Decision, mode string, accountEquity float64, riskControl store.RiskControlConfig) (*FullDecision, error) {
+	if err := validateDecisions(decisions, accountEquity, riskControl); err != nil {
 		return &FullDecision{
-			CoTTrace:  cotTrace,
-			Decisions: decisions,
+			CoTTrace:   cotTrace,
+			Decisions:  decisions,
+			OutputMode: mode,
 		}, fmt.Errorf("decision validation failed: %w", err)
 	}
 
 	return &FullDecision{
-		CoTTrace:  cotTrace,
-		Decisions: decisions,
+		CoTTrace:   cotTrace,
+		Decisions:  decisions,
+		OutputMode: mode,
 	}, nil
 }
 
@@ -352,7 +406,7 @@ func extractDecisions(response string) ([]Decision, error) {
 		}
 
 		fallbackDecision := Decision{
-			Symbol:    "ALL",
+			Symbol:    safeWaitSymbol,
 			Action:    "wait",
 			Reasoning: fmt.Sprintf("Model didn't output structured JSON decision, entering safe wait; summary: %s", cotSummary),
 		}
@@ -4,22 +4,23 @@ import (
 	"fmt"
 	"nofx/logger"
 	"nofx/market"
+	"nofx/store"
 )
 
 // ============================================================================
 // Decision Validation
 // ============================================================================
 
-func validateDecisions(decisions []Decision, accountEquity float64, btcEthLeverage, altcoinLeverage int, btcEthPosRatio, altcoinPosRatio float64) error {
+func validateDecisions(decisions []Decision, accountEquity float64, riskControl store.RiskControlConfig) error {
 	for i := range decisions {
-		if err := validateDecision(&decisions[i], accountEquity, btcEthLeverage, altcoinLeverage, btcEthPosRatio, altcoinPosRatio); err != nil {
+		if err := validateDecision(&decisions[i], accountEquity, riskControl); err != nil {
 			return fmt.Errorf("decision #%d validation failed: %w", i+1, err)
 		}
 	}
 	return nil
 }
 
-func validateDecision(d *Decision, accountEquity float64, btcEthLeverage, altcoinLeverage int, btcEthPosRatio, altcoinPosRatio float64) error {
+func validateDecision(d *Decision, accountEquity float64, riskControl store.RiskControlConfig) error {
 	validActions := map[string]bool{
 		"open_long":   true,
 		"open_short":  true,
@@ -34,22 +35,17 @@ func validateDecision(d *Decision, accountEquity float64, btcEthLeverage, altcoi
 	}
 
 	if d.Action == "open_long" || d.Action == "open_short" {
-		// Asset tiering for validation:
+		// Limits come from the symbol's asset tier. Without configured tiers:
 		//   - BTC/ETH crypto perps use the BTC/ETH tier (typically 5x equity).
 		//   - Hyperliquid XYZ assets (US equities, commodities, forex) are
 		//     also treated as the higher tier — they are not crypto altcoins
 		//     and the user's quick-trade flow shows them at the higher cap,
 		//     so the validator must match.
 		//   - Everything else is altcoin (1x equity by default).
-		maxLeverage := altcoinLeverage
-		posRatio := altcoinPosRatio
+		tier := ResolveAssetTier(riskControl, d.Symbol)
+		maxLeverage := tier.MaxLeverage
+		posRatio := tier.MaxPositionValueRatio
 		maxPositionValue := accountEquity * posRatio
-		isMajor := d.Symbol == "BTCUSDT" || d.Symbol == "ETHUSDT" || market.IsXyzDexAsset(d.Symbol)
-		if isMajor {
-			maxLeverage = btcEthLeverage
-			posRatio = btcEthPosRatio
-			maxPositionValue = accountEquity * posRatio
-		}
 
 		if d.Leverage <= 0 {
 			return fmt.Errorf("leverage must be greater than 0: %d", d.Leverage)
@@ -66,19 +62,22 @@ func validateDecision(d *Decision, accountEquity float64, btcEthLeverage, altcoi
 		const minPositionSizeGeneral = 12.0
 		const minPositionSizeBTCETH = 60.0
 
+		minPositionSize := minPositionSizeGeneral
 		if d.Symbol == "BTCUSDT" || d.Symbol == "ETHUSDT" {
-			if d.PositionSizeUSD < minPositionSizeBTCETH {
-				return fmt.Errorf("%s opening amount too small (%.2f USDT), must be ≥%.2f USDT", d.Symbol, d.PositionSizeUSD, minPositionSizeBTCETH)
-			}
-		} else {
-			if d.PositionSizeUSD < minPositionSizeGeneral {
-				return fmt.Errorf("opening amount too small (%.2f USDT), must be ≥%.2f USDT", d.PositionSizeUSD, minPositionSizeGeneral)
-			}
+			minPositionSize = minPositionSizeBTCETH
+		}
+		if tier.MinPositionSize > minPositionSize {
+			minPositionSize = tier.MinPositionSize
+		}
+		if d.PositionSizeUSD < minPositionSize {
+			return fmt.Errorf("%s opening amount too small (%.2f USDT), must be ≥%.2f USDT", d.Symbol, d.PositionSizeUSD, minPositionSize)
 		}
 
 		tolerance := maxPositionValue * 0.01
 		if d.PositionSizeUSD > maxPositionValue+tolerance {
 			switch {
+			case len(riskControl.AssetTiers) > 0:
+				return fmt.Errorf("%s position value cannot exceed %.0f USDT (%s tier: %.1fx account equity), actual: %.0f", d.Symbol, maxPositionValue, tier.Name, posRatio, d.PositionSizeUSD)
 			case d.Symbol == "BTCUSDT" || d.Symbol == "ETHUSDT":
 				return fmt.Errorf("BTC/ETH single coin position value cannot exceed %.0f USDT (%.1fx account equity), actual: %.0f", maxPositionValue, posRatio, d.PositionSizeUSD)
 			case market.IsXyzDexAsset(d.Symbol):
@@ -14,8 +14,29 @@ import (
 // Prompt Building - System Prompt
 // ============================================================================
 
+// PromptParts a system prompt split for provider prompt caching: Prefix
+// depends only on the strategy configuration and is identical from cycle to
+// cycle; Suffix carries values that change every cycle (account equity).
+type PromptParts struct {
+	Prefix string
+	Suffix string
+}
+
+// String returns the full prompt, prefix first
+func (p PromptParts) String() string {
+	return p.Prefix + p.Suffix
+}
+
 // BuildSystemPrompt builds System Prompt according to strategy configuration
 func (e *StrategyEngine) BuildSystemPrompt(accountEquity float64, variant string) string {
+	return e.BuildSystemPromptParts(accountEquity, variant).String()
+}
+
+// BuildSystemPromptParts builds the system prompt as a cacheable prefix
+// (schema, rules, constraints, output format) and a per-cycle suffix with
+// the USDT limits derived from the current equity. Nothing derived from
+// market or account state may be written to the prefix.
+func (e *StrategyEngine) BuildSystemPromptParts(accountEquity float64, variant string) PromptParts {
 	var sb strings.Builder
 	riskControl := e.config.RiskControl
 	promptSections := e.config.PromptSections
@@ -75,7 +96,7 @@ func (e *StrategyEngine) BuildSystemPrompt(accountEquity float64, variant string
 		altcoinPosValueRatio = 1.0
 	}
 
-	writeHardConstraints(&sb, accountEquity, riskControl, btcEthPosValueRatio, altcoinPosValueRatio, singleSymbol, primarySymbol, zh)
+	writeHardConstraints(&sb, riskControl, btcEthPosValueRatio, altcoinPosValueRatio, singleSymbol, primarySymbol, zh)
 
 	// 4. Trading frequency (editable)
 	tradingFrequency := englishOnlyPromptSection(promptSections.TradingFrequency)
@@ -142,7 +163,7 @@ func (e *StrategyEngine) BuildSystemPrompt(accountEquity float64, variant string
 
 	// 7. Output format — schema spec stays in English (this is a parser
 	//    contract; reasoning copy is localized below).
-	writeOutputFormat(&sb, accountEquity, btcEthPosValueRatio, riskControl, singleSymbol, primarySymbol, zh)
+	writeOutputFormat(&sb, riskControl, singleSymbol, primarySymbol, zh)
 
 	// 8. Custom Prompt.
 	//
@@ -177,7 +198,9 @@ func (e *StrategyEngine) BuildSystemPrompt(accountEquity float64, variant string
 		}
 	}
 
-	return sb.String()
+	var suffix strings.Builder
+	writeAccountLimits(&suffix, accountEquity, riskControl, btcEthPosValueRatio, altcoinPosValueRatio, singleSymbol, primarySymbol)
+	return PromptParts{Prefix: sb.String(), Suffix: suffix.String()}
 }
 
 func (e *StrategyEngine) usesVergexSignalPrompt() bool {
@@ -194,7 +217,7 @@ func (e *StrategyEngine) usesVergexSignalPrompt() bool {
 		coinSource.VergexLimit > 0
 }
 
-func (e *StrategyEngine) buildVergexSystemPrompt(accountEquity float64, variant string, lang Language, zh bool, singleSymbol bool, primarySymbol string) string {
+func (e *StrategyEngine) buildVergexSystemPrompt(accountEquity float64, variant string, lang Language, zh bool, singleSymbol bool, primarySymbol string) PromptParts {
 	var sb strings.Builder
 	riskControl := e.config.RiskControl
 
@@ -239,8 +262,8 @@ func (e *StrategyEngine) buildVergexSystemPrompt(accountEquity float64, variant
 	if altcoinPosValueRatio <= 0 {
 		altcoinPosValueRatio = 1.0
 	}
-	writeVergexHardConstraints(&sb, accountEquity, riskControl, altcoinPosValueRatio, zh)
-	writeVergexOutputFormat(&sb, accountEquity, riskControl, altcoinPosValueRatio, singleSymbol, primarySymbol, zh)
+	writeVergexHardConstraints(&sb, riskControl, altcoinPosValueRatio, zh)
+	writeVergexOutputFormat(&sb, riskControl, singleSymbol, primarySymbol, zh)
 
 	customPrompt := vergexCustomPromptSection(e.config.CustomPrompt)
 	if customPrompt != "" {
@@ -249,7 +272,17 @@ func (e *StrategyEngine) buildVergexSystemPrompt(accountEquity float64, variant
 		sb.WriteString("\n\n")
 	}
 
-	return sb.String()
+	var suffix strings.Builder
+	suffix.WriteString("# Account Limits (This Cycle)\n\n")
+	suffix.WriteString(fmt.Sprintf("- Account equity: %.0f USDT\n", accountEquity))
+	if len(riskControl.AssetTiers) > 0 {
+		writeAssetTierAccountLimits(&suffix, accountEquity, riskControl)
+		suffix.WriteString("- Use the position value limit of the symbol's tier as position_size_usd for every open\n")
+	} else {
+		suffix.WriteString(fmt.Sprintf("- Max notional per position: %.0f USDT (= equity %.0f × %.1fx); use it as position_size_usd for every open\n",
+			accountEquity*altcoinPosValueRatio, accountEquity, altcoinPosValueRatio))
+	}
+	return PromptParts{Prefix: sb.String(), Suffix: suffix.String()}
 }
 
 // vergexCustomPromptSection returns the user's custom prompt for the vergex
@@ -322,17 +355,16 @@ func writeVergexSchemaPrompt(sb *strings.Builder, zh bool) {
 	}
 }
 
-func writeVergexHardConstraints(sb *strings.Builder, accountEquity float64, riskControl store.RiskControlConfig, tradeFiPositionValueRatio float64, zh bool) {
-	maxPositionValue := accountEquity * tradeFiPositionValueRatio
+func writeVergexHardConstraints(sb *strings.Builder, riskControl store.RiskControlConfig, tradeFiPositionValueRatio float64, zh bool) {
 	if zh {
 		sb.WriteString("# Hard Risk Constraints\n\n")
 		sb.WriteString("## Backend enforced\n")
 		sb.WriteString(fmt.Sprintf("- Max positions: %d Claw402 candidate instruments at the same time\n", riskControl.MaxPositions))
-		sb.WriteString(fmt.Sprintf("- Max notional per position: %.0f USDT (= equity %.0f × %.1fx)\n", maxPositionValue, accountEquity, tradeFiPositionValueRatio))
+		writeVergexPositionLimit(sb, riskControl, tradeFiPositionValueRatio)
 		sb.WriteString(fmt.Sprintf("- Max margin usage: ≤%.0f%%\n", riskControl.MaxMarginUsage*100))
 		sb.WriteString(fmt.Sprintf("- Min order size: ≥%.0f USDT\n\n", riskControl.MinPositionSize))
 		sb.WriteString("## AI guided\n")
-		sb.WriteString(fmt.Sprintf("- Leverage: every open position must use exactly %dx\n", riskControl.AltcoinMaxLeverage))
+		writeVergexLeverage(sb, riskControl)
 		sb.WriteString(fmt.Sprintf("- Risk/reward: ≥1:%.1f\n", riskControl.MinRiskRewardRatio))
 		sb.WriteString(fmt.Sprintf("- Min confidence to open: ≥%d\n\n", riskControl.MinConfidence))
 		sb.WriteString("# Position Sizing\n\n")
@@ -345,11 +377,11 @@ func writeVergexHardConstraints(sb *strings.Builder, accountEquity float64, risk
 		sb.WriteString("# Hard Risk Constraints\n\n")
 		sb.WriteString("## Backend enforced\n")
 		sb.WriteString(fmt.Sprintf("- Max positions: %d Claw402 candidate instruments at the same time\n", riskControl.MaxPositions))
-		sb.WriteString(fmt.Sprintf("- Max notional per position: %.0f USDT (= equity %.0f × %.1fx)\n", maxPositionValue, accountEquity, tradeFiPositionValueRatio))
+		writeVergexPositionLimit(sb, riskControl, tradeFiPositionValueRatio)
 		sb.WriteString(fmt.Sprintf("- Max margin usage: ≤%.0f%%\n", riskControl.MaxMarginUsage*100))
 		sb.WriteString(fmt.Sprintf("- Min order size: ≥%.0f USDT\n\n", riskControl.MinPositionSize))
 		sb.WriteString("## AI guided\n")
-		sb.WriteString(fmt.Sprintf("- Leverage: every open position must use exactly %dx\n", riskControl.AltcoinMaxLeverage))
+		writeVergexLeverage(sb, riskControl)
 		sb.WriteString(fmt.Sprintf("- Risk/reward: ≥1:%.1f\n", riskControl.MinRiskRewardRatio))
 		sb.WriteString(fmt.Sprintf("- Min confidence to open: ≥%d\n\n", riskControl.MinConfidence))
 		sb.WriteString("# Position Sizing\n\n")
@@ -361,14 +393,32 @@ func writeVergexHardConstraints(sb *strings.Builder, accountEquity float64, risk
 	}
 }
 
-func writeVergexOutputFormat(sb *strings.Builder, accountEquity float64, riskControl store.RiskControlConfig, tradeFiPositionValueRatio float64, singleSymbol bool, primarySymbol string, zh bool) {
+// writeVergexPositionLimit writes the max notional per position: one
+// equity ratio, or the limits of each configured asset tier
+func writeVergexPositionLimit(sb *strings.Builder, riskControl store.RiskControlConfig, tradeFiPositionValueRatio float64) {
+	if len(riskControl.AssetTiers) > 0 {
+		writeAssetTierConstraints(sb, riskControl)
+		return
+	}
+	sb.WriteString(fmt.Sprintf("- Max notional per position: equity × %.1fx (USDT value in Account Limits below)\n", tradeFiPositionValueRatio))
+}
+
+func writeVergexLeverage(sb *strings.Builder, riskControl store.RiskControlConfig) {
+	if len(riskControl.AssetTiers) > 0 {
+		sb.WriteString("- Leverage: every open position must use exactly its asset tier's max leverage\n")
+		return
+	}
+	sb.WriteString(fmt.Sprintf("- Leverage: every open position must use exactly %dx\n", riskControl.AltcoinMaxLeverage))
+}
+
+func writeVergexOutputFormat(sb *strings.Builder, riskControl store.RiskControlConfig, singleSymbol bool, primarySymbol string, zh bool) {
 	exampleSymbol := "xyz:NVDA"
 	secondSymbol := "xyz:AAPL"
 	if singleSymbol && strings.TrimSpace(primarySymbol) != "" {
 		exampleSymbol = primarySymbol
 		secondSymbol = primarySymbol
 	}
-	positionSize := accountEquity * tradeFiPositionValueRatio
+	positionSize := examplePositionSizeUSD
 	leverage := riskControl.AltcoinMaxLeverage
 	if leverage <= 0 {
 		leverage = 1
@@ -515,7 +565,7 @@ func writeModeVariant(sb *strings.Builder, variant string, zh bool) {
 	}
 }
 
-func writeHardConstraints(sb *strings.Builder, accountEquity float64, riskControl store.RiskControlConfig, btcEthPosValueRatio, altcoinPosValueRatio float64, singleSymbol bool, primarySymbol string, zh bool) {
+func writeHardConstraints(sb *strings.Builder, riskControl store.RiskControlConfig, btcEthPosValueRatio, altcoinPosValueRatio float64, singleSymbol bool, primarySymbol string, zh bool) {
 	if zh {
 		sb.WriteString("# Hard Constraints (Risk Control)\n\n")
 		sb.WriteString("## CODE ENFORCED (backend validation, cannot be bypassed):\n")
@@ -526,6 +576,7 @@ func writeHardConstraints(sb *strings.Builder, accountEquity float64, riskContro
 		sb.WriteString(fmt.Sprintf("- Max Positions: %d instruments simultaneously\n", riskControl.MaxPositions))
 	}
 
+	tiered := len(riskControl.AssetTiers) > 0
 	if singleSymbol {
 		// One symbol — pick the higher of the two configured ratios so the
 		// limit isn't accidentally clamped to the altcoin cap for a stock.
@@ -533,20 +584,24 @@ func writeHardConstraints(sb *strings.Builder, accountEquity float64, riskContro
 		if btcEthPosValueRatio > ratio {
 			ratio = btcEthPosValueRatio
 		}
-		maxVal := accountEquity * ratio
+		if tiered {
+			ratio = ResolveAssetTier(riskControl, primarySymbol).MaxPositionValueRatio
+		}
 		symLabel := primarySymbol
 		if zh {
-			sb.WriteString(fmt.Sprintf("- Position Value Limit (%s): max %.0f USDT (= equity %.0f × %.1fx)\n", symLabel, maxVal, accountEquity, ratio))
+			sb.WriteString(fmt.Sprintf("- Position Value Limit (%s): max equity × %.1fx (USDT value in Account Limits below)\n", symLabel, ratio))
 		} else {
-			sb.WriteString(fmt.Sprintf("- Position Value Limit (%s): max %.0f USDT (= equity %.0f × %.1fx)\n", symLabel, maxVal, accountEquity, ratio))
+			sb.WriteString(fmt.Sprintf("- Position Value Limit (%s): max equity × %.1fx (USDT value in Account Limits below)\n", symLabel, ratio))
 		}
+	} else if tiered {
+		writeAssetTierConstraints(sb, riskControl)
 	} else {
 		if zh {
-			sb.WriteString(fmt.Sprintf("- Position Value Limit (Altcoin/Stock): max %.0f USDT (= equity %.0f × %.1fx)\n", accountEquity*altcoinPosValueRatio, accountEquity, altcoinPosValueRatio))
-			sb.WriteString(fmt.Sprintf("- Position Value Limit (BTC/ETH): max %.0f USDT (= equity %.0f × %.1fx)\n", accountEquity*btcEthPosValueRatio, accountEquity, btcEthPosValueRatio))
+			sb.WriteString(fmt.Sprintf("- Position Value Limit (Altcoin/Stock): max equity × %.1fx (USDT value in Account Limits below)\n", altcoinPosValueRatio))
+			sb.WriteString(fmt.Sprintf("- Position Value Limit (BTC/ETH): max equity × %.1fx (USDT value in Account Limits below)\n", btcEthPosValueRatio))
 		} else {
-			sb.WriteString(fmt.Sprintf("- Position Value Limit (Altcoin/Stock): max %.0f USDT (= equity %.0f × %.1fx)\n", accountEquity*altcoinPosValueRatio, accountEquity, altcoinPosValueRatio))
-			sb.WriteString(fmt.Sprintf("- Position Value Limit (BTC/ETH): max %.0f USDT (= equity %.0f × %.1fx)\n", accountEquity*btcEthPosValueRatio, accountEquity, btcEthPosValueRatio))
+			sb.WriteString(fmt.Sprintf("- Position Value Limit (Altcoin/Stock): max equity × %.1fx (USDT value in Account Limits below)\n", altcoinPosValueRatio))
+			sb.WriteString(fmt.Sprintf("- Position Value Limit (BTC/ETH): max equity × %.1fx (USDT value in Account Limits below)\n", btcEthPosValueRatio))
 		}
 	}
 
@@ -565,11 +620,16 @@ func writeHardConstraints(sb *strings.Builder, accountEquity float64, riskContro
 		if riskControl.BTCETHMaxLeverage > lev {
 			lev = riskControl.BTCETHMaxLeverage
 		}
+		if tiered {
+			lev = ResolveAssetTier(riskControl, primarySymbol).MaxLeverage
+		}
 		if zh {
 			sb.WriteString(fmt.Sprintf("- Trading Leverage (%s): max %dx\n", primarySymbol, lev))
 		} else {
 			sb.WriteString(fmt.Sprintf("- Trading Leverage (%s): max %dx\n", primarySymbol, lev))
 		}
+	} else if tiered {
+		sb.WriteString("- Trading Leverage: the asset tier maximum above (higher leverage is cut to it)\n")
 	} else {
 		if zh {
 			sb.WriteString(fmt.Sprintf("- Trading Leverage: Altcoin/Stock max %dx | BTC/ETH max %dx\n", riskControl.AltcoinMaxLeverage, riskControl.BTCETHMaxLeverage))
@@ -586,20 +646,12 @@ func writeHardConstraints(sb *strings.Builder, accountEquity float64, riskContro
 	}
 
 	// Position sizing guidance
-	exampleRatio := btcEthPosValueRatio
-	if singleSymbol {
-		exampleRatio = altcoinPosValueRatio
-		if btcEthPosValueRatio > exampleRatio {
-			exampleRatio = btcEthPosValueRatio
-		}
-	}
 	if zh {
 		sb.WriteString("## Position Sizing Guidance\n")
 		sb.WriteString("Calculate `position_size_usd` from your confidence and the Position Value Limits above:\n")
 		sb.WriteString("- High confidence (≥85): use 80-100%% of the position value limit\n")
 		sb.WriteString("- Medium confidence (70-84): use 50-80%% of the position value limit\n")
 		sb.WriteString("- Low confidence (60-69): use 30-50%% of the position value limit\n")
-		sb.WriteString(fmt.Sprintf("- Example: equity %.0f × %.1fx = max %.0f USDT\n", accountEquity, exampleRatio, accountEquity*exampleRatio))
 		sb.WriteString("- **DO NOT** just use available_balance as position_size_usd. Use the Position Value Limit!\n\n")
 	} else {
 		sb.WriteString("## Position Sizing Guidance\n")
@@ -607,12 +659,72 @@ func writeHardConstraints(sb *strings.Builder, accountEquity float64, riskContro
 		sb.WriteString("- High confidence (≥85): use 80-100%% of the position value limit\n")
 		sb.WriteString("- Medium confidence (70-84): use 50-80%% of the position value limit\n")
 		sb.WriteString("- Low confidence (60-69): use 30-50%% of the position value limit\n")
-		sb.WriteString(fmt.Sprintf("- Example: equity %.0f × %.1fx = max %.0f USDT\n", accountEquity, exampleRatio, accountEquity*exampleRatio))
 		sb.WriteString("- **DO NOT** just use available_balance as position_size_usd. Use the Position Value Limit!\n\n")
 	}
+	writePositionSizingMode(sb, riskControl.PositionSizing)
 }
 
-func writeOutputFormat(sb *strings.Builder, accountEquity, btcEthPosValueRatio float64, riskControl store.RiskControlConfig, singleSymbol bool, primarySymbol string, zh bool) {
+// writePositionSizingMode tells the model how the code-enforced sizing mode
+// treats its position_size_usd
+func writePositionSizingMode(sb *strings.Builder, sizing store.PositionSizingConfig) {
+	if !sizing.Enabled() {
+		return
+	}
+	sizing = sizing.WithDefaults()
+	var method string
+	switch sizing.Mode {
+	case store.PositionSizingFixedFractional:
+		method = fmt.Sprintf("fixed fractional: the loss at your stop_loss is %.2f%% of equity, so the size follows from the stop distance", sizing.RiskPerTradePct)
+	case store.PositionSizingATR:
+		method = fmt.Sprintf("volatility target: a %.1f×ATR adverse move loses %.2f%% of equity", sizing.ATRMultiple, sizing.RiskPerTradePct)
+	case store.PositionSizingKelly:
+		method = fmt.Sprintf("fractional Kelly (%.2f× of full Kelly from the historical win rate, at most %.1f%% of equity at risk)", sizing.KellyFraction, sizing.MaxRiskPerTradePct)
+	}
+	sb.WriteString("## Code-Enforced Position Sizing\n")
+	sb.WriteString(fmt.Sprintf("- The backend sizes every open by %s.\n", method))
+	if sizing.AISizeRole == store.AISizeHint {
+		sb.WriteString("- Your position_size_usd is only a hint; still provide it, it is used when no size can be computed.\n")
+	} else {
+		sb.WriteString("- Your position_size_usd is an upper bound: the smaller of it and the computed size is used.\n")
+	}
+	if sizing.Mode != store.PositionSizingATR {
+		sb.WriteString("- Place stop_loss precisely: a wider stop means a smaller position.\n")
+	}
+	sb.WriteString("\n")
+}
+
+// examplePositionSizeUSD is the position_size_usd shown in the output format
+// examples. The examples live in the cacheable prefix, so they cannot use the
+// equity-derived limits, which are given in the Account Limits suffix.
+const examplePositionSizeUSD = 1000.0
+
+// writeAccountLimits writes the per-cycle suffix of the system prompt: the
+// USDT position value limits for the current equity
+func writeAccountLimits(sb *strings.Builder, accountEquity float64, riskControl store.RiskControlConfig, btcEthPosValueRatio, altcoinPosValueRatio float64, singleSymbol bool, primarySymbol string) {
+	sb.WriteString("# Account Limits (This Cycle)\n\n")
+	sb.WriteString(fmt.Sprintf("- Account equity: %.0f USDT\n", accountEquity))
+	exampleRatio := btcEthPosValueRatio
+	if singleSymbol {
+		ratio := altcoinPosValueRatio
+		if btcEthPosValueRatio > ratio {
+			ratio = btcEthPosValueRatio
+		}
+		if len(riskControl.AssetTiers) > 0 {
+			ratio = ResolveAssetTier(riskControl, primarySymbol).MaxPositionValueRatio
+		}
+		exampleRatio = ratio
+		sb.WriteString(fmt.Sprintf("- Position Value Limit (%s): max %.0f USDT (= equity %.0f × %.1fx)\n", primarySymbol, accountEquity*ratio, accountEquity, ratio))
+	} else if len(riskControl.AssetTiers) > 0 {
+		exampleRatio = riskControl.AssetTiers[0].MaxPositionValueRatio
+		writeAssetTierAccountLimits(sb, accountEquity, riskControl)
+	} else {
+		sb.WriteString(fmt.Sprintf("- Position Value Limit (Altcoin/Stock): max %.0f USDT (= equity %.0f × %.1fx)\n", accountEquity*altcoinPosValueRatio, accountEquity, altcoinPosValueRatio))
+		sb.WriteString(fmt.Sprintf("- Position Value Limit (BTC/ETH): max %.0f USDT (= equity %.0f × %.1fx)\n", accountEquity*btcEthPosValueRatio, accountEquity, btcEthPosValueRatio))
+	}
+	sb.WriteString(fmt.Sprintf("- Sizing example: equity %.0f × %.1fx = max %.0f USDT; size position_size_usd from these limits, not from the output format example\n", accountEquity, exampleRatio, accountEquity*exampleRatio))
+}
+
+func writeOutputFormat(sb *strings.Builder, riskControl store.RiskControlConfig, singleSymbol bool, primarySymbol string, zh bool) {
 	// Output format schema MUST stay English/structural; parser depends on it.
 	sb.WriteString("# Output Format (Strictly Follow)\n\n")
 	if zh {
@@ -644,12 +756,11 @@ func writeOutputFormat(sb *strings.Builder, accountEquity, btcEthPosValueRatio f
 		if riskControl.BTCETHMaxLeverage > lev {
 			lev = riskControl.BTCETHMaxLeverage
 		}
-		ratio := btcEthPosValueRatio // already chosen as the larger above when single-symbol
-		size := accountEquity * ratio
+		size := examplePositionSizeUSD
 		sb.WriteString(fmt.Sprintf("  {\"symbol\": \"%s\", \"action\": \"open_long\", \"leverage\": %d, \"position_size_usd\": %.0f, \"stop_loss\": 0, \"take_profit\": 0, \"confidence\": 85, \"risk_usd\": 0},\n", primarySymbol, lev, size))
 		sb.WriteString(fmt.Sprintf("  {\"symbol\": \"%s\", \"action\": \"wait\"}\n", primarySymbol))
 	} else {
-		examplePositionSize := accountEquity * btcEthPosValueRatio
+		examplePositionSize := examplePositionSizeUSD
 		sb.WriteString(fmt.Sprintf("  {\"symbol\": \"BTCUSDT\", \"action\": \"open_short\", \"leverage\": %d, \"position_size_usd\": %.0f, \"stop_loss\": 97000, \"take_profit\": 91000, \"confidence\": 85, \"risk_usd\": 300},\n",
 			riskControl.BTCETHMaxLeverage, examplePositionSize))
 		sb.WriteString("  {\"symbol\": \"ETHUSDT\", \"action\": \"close_long\"}\n")
@@ -774,6 +885,9 @@ func (e *StrategyEngine) BuildUserPrompt(ctx *Context) string {
 			btcData.CurrentMACD, btcData.CurrentRSI7))
 	}
 
+	// Market regime (when the strategy selects its mode by regime)
+	writeMarketRegime(&sb, ctx.MarketRegime)
+
 	// Account information
 	sb.WriteString(fmt.Sprintf("Account: Equity %.2f | Balance %.2f (%.1f%%) | PnL %+.2f%% | Margin %.1f%% | Positions %d\n\n",
 		ctx.Account.TotalEquity,
@@ -960,10 +1074,10 @@ func (e *StrategyEngine) formatPositionInfo(index int, pos PositionInfo, ctx *Co
 		positionValue = -positionValue
 	}
 
-	sb.WriteString(fmt.Sprintf("%d. %s %s | Entry %.4f Current %.4f | Qty %.4f | Position Value %.2f USDT | PnL%+.2f%% | PnL Amount%+.2f USDT | Peak PnL%.2f%% | Leverage %dx | Margin %.0f | Liq Price %.4f%s\n\n",
+	sb.WriteString(fmt.Sprintf("%d. %s %s | Entry %.4f Current %.4f | Qty %.4f | Position Value %.2f USDT | PnL%+.2f%% | PnL Amount%+.2f USDT | Peak PnL%.2f%% | Leverage %dx | Margin %.0f | Liq Price %.4f%s%s\n\n",
 		index, pos.Symbol, strings.ToUpper(pos.Side),
 		pos.EntryPrice, pos.MarkPrice, pos.Quantity, positionValue, pos.UnrealizedPnLPct, pos.UnrealizedPnL, pos.PeakPnLPct,
-		pos.Leverage, pos.MarginUsed, pos.LiquidationPrice, holdingDuration))
+		pos.Leverage, pos.MarginUsed, pos.LiquidationPrice, holdingDuration, formatFundingFee(pos.FundingFee)))
 
 	if marketData, ok := ctx.MarketDataMap[pos.Symbol]; ok {
 		sb.WriteString(e.formatMarketData(marketData))
@@ -984,6 +1098,17 @@ func (e *StrategyEngine) formatPositionInfo(index int, pos PositionInfo, ctx *Co
 	return sb.String()
 }
 
+// formatFundingFee the funding accumulated on a held position, "" when none settled yet
+func formatFundingFee(fundingFee float64) string {
+	switch {
+	case fundingFee < 0:
+		return fmt.Sprintf(" | Funding Paid %.2f USDT", -fundingFee)
+	case fundingFee > 0:
+		return fmt.Sprintf(" | Funding Received %.2f USDT", fundingFee)
+	}
+	return ""
+}
+
 func (e *StrategyEngine) formatCoinSourceTag(sources []string) string {
 	if len(sources) > 1 {
 		// Multiple signal source combination
@@ -122,3 +122,73 @@ func containsCJK(text string) bool {
 	}
 	return false
 }
+
+func TestBuildSystemPromptPartsPrefixIndependentOfEquity(t *testing.T) {
+	for _, source := range []string{"static", "vergex_signal"} {
+		cfg := store.GetDefaultStrategyConfig("en")
+		cfg.CoinSource.SourceType = source
+		engine := NewStrategyEngine(&cfg)
+
+		a := engine.BuildSystemPromptParts(1234, "balanced")
+		b := engine.BuildSystemPromptParts(98765, "balanced")
+		if a.Prefix != b.Prefix {
+			t.Fatalf("%s: cacheable prefix changed with equity", source)
+		}
+		if strings.Contains(a.Prefix, "1234") {
+			t.Fatalf("%s: equity leaked into the prefix", source)
+		}
+		if !strings.Contains(a.Suffix, "Account equity: 1234 USDT") {
+			t.Fatalf("%s: suffix missing the equity limits:\n%s", source, a.Suffix)
+		}
+		if engine.BuildSystemPrompt(1234, "balanced") != a.String() {
+			t.Fatalf("%s: BuildSystemPrompt should be prefix + suffix", source)
+		}
+	}
+}
+
+func TestBuildSystemPromptRendersAssetTiers(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en")
+	cfg.CoinSource.SourceType = "static"
+	cfg.CoinSource.StaticCoins = []string{"BTCUSDT", "DOGEUSDT"}
+	cfg.RiskControl.AssetTiers = []store.AssetTierConfig{
+		{Name: "majors", Symbols: []string{"BTCUSDT"}, MaxLeverage: 10, MaxPositionValueRatio: 5},
+		{Name: "memes", Symbols: []string{"DOGEUSDT"}, MaxLeverage: 2, MaxPositionValueRatio: 0.5, MaxPositions: 1},
+	}
+	engine := NewStrategyEngine(&cfg)
+
+	parts := engine.BuildSystemPromptParts(1000, "balanced")
+	for _, want := range []string{
+		"  - majors (BTCUSDT): leverage max 10x | position value max equity × 5.0x\n",
+		"  - memes (DOGEUSDT): leverage max 2x | position value max equity × 0.5x | max 1 open positions\n",
+		"  - altcoin (all other symbols):",
+	} {
+		if !strings.Contains(parts.Prefix, want) {
+			t.Errorf("prefix missing %q:\n%s", want, parts.Prefix)
+		}
+	}
+	if strings.Contains(parts.Prefix, "Position Value Limit (BTC/ETH)") {
+		t.Error("configured tiers should replace the BTC/ETH and altcoin split")
+	}
+	if !strings.Contains(parts.Suffix, "Position Value Limit (memes tier): max 500 USDT") {
+		t.Errorf("suffix missing the memes tier limit:\n%s", parts.Suffix)
+	}
+}
+
+func TestFormatPositionInfoShowsAccumulatedFunding(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en")
+	engine := NewStrategyEngine(&cfg)
+	ctx := &Context{}
+
+	pos := PositionInfo{Symbol: "BTCUSDT", Side: "long", EntryPrice: 100, MarkPrice: 101, Quantity: 1, Leverage: 5, FundingFee: -1.234}
+	if got := engine.formatPositionInfo(1, pos, ctx); !strings.Contains(got, "| Funding Paid 1.23 USDT") {
+		t.Errorf("paid funding missing:\n%s", got)
+	}
+	pos.FundingFee = 0.5
+	if got := engine.formatPositionInfo(1, pos, ctx); !strings.Contains(got, "| Funding Received 0.50 USDT") {
+		t.Errorf("received funding missing:\n%s", got)
+	}
+	pos.FundingFee = 0
+	if got := engine.formatPositionInfo(1, pos, ctx); strings.Contains(got, "Funding") {
+		t.Errorf("no funding settled should render nothing:\n%s", got)
+	}
+}
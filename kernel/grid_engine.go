@@ -1,6 +1,7 @@
 package kernel
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"nofx/logger"
@@ -449,16 +450,33 @@ func GetGridDecisions(ctx *GridContext, mcpClient mcp.AIClient, config *store.Gr
 
 	logger.Infof("🤖 [Grid] Calling AI for grid decisions...")
 
-	// Call AI
-	response, err := mcpClient.CallWithMessages(systemPrompt, userPrompt)
+	// Call AI (structured output where the provider supports it)
+	reply, err := callDecisionModel(context.Background(), mcpClient, PromptParts{Prefix: systemPrompt}, userPrompt, GridDecisionActions)
 	if err != nil {
 		return nil, fmt.Errorf("AI call failed: %w", err)
 	}
+	response := reply.raw
 
 	// Parse decisions from response
-	decisions, err := parseGridDecisions(response, ctx.Symbol)
+	var decisions []Decision
+	mode := DecisionOutputStructured
+	cotTrace := ""
+	if reply.reply != nil {
+		decisions = reply.reply.Decisions
+		cotTrace = strings.TrimSpace(reply.reply.Reasoning)
+		for i := range decisions {
+			if decisions[i].Symbol == "" {
+				decisions[i].Symbol = ctx.Symbol
+			}
+		}
+	} else {
+		mode = DecisionOutputText
+		cotTrace = extractCoTTrace(response)
+		decisions, err = parseGridDecisions(response, ctx.Symbol)
+	}
 	if err != nil {
 		logger.Warnf("Failed to parse grid decisions: %v", err)
+		mode = DecisionOutputFailed
 		// Return hold decision as fallback
 		decisions = []Decision{{
 			Symbol:     ctx.Symbol,
@@ -467,21 +485,20 @@ func GetGridDecisions(ctx *GridContext, mcpClient mcp.AIClient, config *store.Gr
 			Reasoning:  "Failed to parse AI response, holding current state",
 		}}
 	}
+	recordDecisionParse(reply.provider, reply.structured, mode)
 
 	duration := time.Since(startTime).Milliseconds()
 	logger.Infof("⏱️ [Grid] AI call duration: %d ms, decisions: %d", duration, len(decisions))
 
-	// Extract chain of thought from response
-	cotTrace := extractCoTTrace(response)
-
 	return &FullDecision{
-		SystemPrompt:        systemPrompt,
+		SystemPrompt:        reply.systemPrompt,
 		UserPrompt:          userPrompt,
 		CoTTrace:            cotTrace,
 		Decisions:           decisions,
 		RawResponse:         response,
 		AIRequestDurationMs: duration,
 		Timestamp:           time.Now(),
+		OutputMode:          mode,
 	}, nil
 }
 
@@ -0,0 +1,181 @@
+package kernel
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"nofx/logger"
+	"nofx/market"
+	"nofx/provider/nofxos"
+	"nofx/store"
+)
+
+// ============================================================================
+// Market Regime Detection
+// ============================================================================
+
+// regimeBenchmark the symbol whose volatility and trend define the regime
+const regimeBenchmark = "BTCUSDT"
+
+// Regime thresholds
+const (
+	// trendStrengthThreshold EMA20/EMA50 gap of the benchmark, in ATRs,
+	// from which the market counts as trending
+	trendStrengthThreshold = 1.0
+	// breadthThreshold share of the universe that must move with the trend
+	breadthThreshold = 0.55
+)
+
+// MarketRegime the market regime detected for a cycle
+type MarketRegime struct {
+	Regime         string             `json:"regime"`          // store.MarketRegime*
+	Variant        string             `json:"variant"`         // prompt variant selected for the regime
+	Volatility     market.RegimeLevel `json:"volatility"`      // benchmark volatility level (grid classification)
+	BollingerWidth float64            `json:"bollinger_width"` // benchmark Bollinger width, %
+	ATRPct         float64            `json:"atr_pct"`         // benchmark ATR14, % of price
+	TrendStrength  float64            `json:"trend_strength"`  // benchmark EMA20-EMA50 gap in ATRs, signed
+	Breadth        float64            `json:"breadth"`         // share of the universe rising over 4h, -1 when unknown
+}
+
+// String a one-line summary for logs and prompts
+func (r *MarketRegime) String() string {
+	breadth := "n/a"
+	if r.Breadth >= 0 {
+		breadth = fmt.Sprintf("%.0f%% rising", r.Breadth*100)
+	}
+	return fmt.Sprintf("%s (volatility %s: Bollinger width %.2f%%, ATR %.2f%% | trend strength %+.2f ATR | breadth %s)",
+		r.Regime, r.Volatility, r.BollingerWidth, r.ATRPct, r.TrendStrength, breadth)
+}
+
+// DetectMarketRegime classifies the market from the benchmark's volatility
+// (the grid regime classification) and trend strength, and from the breadth
+// of the candidate universe and the market-wide price rankings.
+// It returns nil when the benchmark has no indicator data.
+func DetectMarketRegime(benchmark *market.Data, universe map[string]*market.Data, ranking *nofxos.PriceRankingData, primaryTimeframe string) *MarketRegime {
+	bollWidth, atrPct, series, ok := market.VolatilityIndicators(benchmark, primaryTimeframe)
+	if !ok {
+		return nil
+	}
+	r := &MarketRegime{
+		Volatility:     market.ClassifyRegimeLevel(bollWidth, atrPct),
+		BollingerWidth: bollWidth,
+		ATRPct:         atrPct,
+		Breadth:        marketBreadth(universe, ranking),
+	}
+	if n, m := len(series.EMA20Values), len(series.EMA50Values); n > 0 && m > 0 && series.EMA50Values[m-1] > 0 && atrPct > 0 {
+		gapPct := (series.EMA20Values[n-1] - series.EMA50Values[m-1]) / series.EMA50Values[m-1] * 100
+		r.TrendStrength = gapPct / atrPct
+	}
+
+	// A trend needs the benchmark's EMAs apart and, when breadth is known,
+	// most of the market moving the same way
+	switch {
+	case r.TrendStrength >= trendStrengthThreshold && (r.Breadth < 0 || r.Breadth >= breadthThreshold):
+		r.Regime = store.MarketRegimeTrendingUp
+	case r.TrendStrength <= -trendStrengthThreshold && (r.Breadth < 0 || r.Breadth <= 1-breadthThreshold):
+		r.Regime = store.MarketRegimeTrendingDown
+	case r.Volatility == market.RegimeLevelWide || r.Volatility == market.RegimeLevelVolatile:
+		r.Regime = store.MarketRegimeVolatile
+	default:
+		r.Regime = store.MarketRegimeRanging
+	}
+	return r
+}
+
+// marketBreadth returns the share of rising symbols over 4h: the candidate
+// universe averaged with the price rankings (weight of gainers against
+// losers), or whichever is available; -1 without either
+func marketBreadth(universe map[string]*market.Data, ranking *nofxos.PriceRankingData) float64 {
+	var sources []float64
+
+	rising, total := 0, 0
+	for _, data := range universe {
+		if data == nil || data.PriceChange4h == 0 {
+			continue
+		}
+		total++
+		if data.PriceChange4h > 0 {
+			rising++
+		}
+	}
+	if total > 0 {
+		sources = append(sources, float64(rising)/float64(total))
+	}
+
+	if ranking != nil {
+		duration := ranking.Durations["4h"]
+		if duration == nil {
+			duration = ranking.Durations["1h"]
+		}
+		if duration != nil {
+			var gains, losses float64
+			for _, item := range duration.Top {
+				gains += math.Max(item.PriceDelta, 0)
+			}
+			for _, item := range duration.Low {
+				losses += math.Max(-item.PriceDelta, 0)
+			}
+			if gains+losses > 0 {
+				sources = append(sources, gains/(gains+losses))
+			}
+		}
+	}
+
+	if len(sources) == 0 {
+		return -1
+	}
+	sum := 0.0
+	for _, s := range sources {
+		sum += s
+	}
+	return sum / float64(len(sources))
+}
+
+// detectRegimeForCycle detects the regime of a cycle when the strategy
+// selects its prompt variant automatically, fetching the benchmark when it
+// is not among the cycle's symbols. It returns nil when disabled or when
+// the benchmark data is unavailable.
+func (e *StrategyEngine) detectRegimeForCycle(ctx *Context) *MarketRegime {
+	if e == nil || e.config == nil || !e.config.MarketRegime.Enabled {
+		return nil
+	}
+	klines := e.config.Indicators.Klines
+	benchmark := ctx.MarketDataMap[regimeBenchmark]
+	if benchmark == nil {
+		timeframes := klines.SelectedTimeframes
+		if len(timeframes) == 0 && klines.PrimaryTimeframe != "" {
+			timeframes = []string{klines.PrimaryTimeframe}
+		}
+		if len(timeframes) == 0 {
+			timeframes = []string{"5m"}
+		}
+		count := klines.PrimaryCount
+		if count <= 0 {
+			count = 30
+		}
+		data, err := market.GetWithTimeframes(regimeBenchmark, timeframes, klines.PrimaryTimeframe, count)
+		if err != nil {
+			logger.Warnf("⚠️  Market regime: failed to fetch %s, keeping the default variant: %v", regimeBenchmark, err)
+			return nil
+		}
+		benchmark = data
+	}
+
+	regime := DetectMarketRegime(benchmark, ctx.MarketDataMap, ctx.PriceRankingData, klines.PrimaryTimeframe)
+	if regime == nil {
+		logger.Warnf("⚠️  Market regime: no indicator data for %s, keeping the default variant", regimeBenchmark)
+		return nil
+	}
+	regime.Variant = e.config.MarketRegime.VariantFor(regime.Regime)
+	return regime
+}
+
+// writeMarketRegime writes the detected regime section of the user prompt
+func writeMarketRegime(sb *strings.Builder, regime *MarketRegime) {
+	if regime == nil {
+		return
+	}
+	sb.WriteString(fmt.Sprintf("## Market Regime: %s\n", regime.String()))
+	sb.WriteString(fmt.Sprintf("Trading mode selected for this regime: %s\n\n", regime.Variant))
+}
@@ -0,0 +1,96 @@
+package kernel
+
+import (
+	"testing"
+
+	"nofx/market"
+	"nofx/provider/nofxos"
+	"nofx/store"
+)
+
+// regimeTestData a benchmark with the given Bollinger width and ATR (% of a
+// 100 price) and an EMA20/EMA50 gap in percent
+func regimeTestData(bollWidth, atrPct, emaGapPct float64) *market.Data {
+	return &market.Data{
+		Symbol:       "BTCUSDT",
+		CurrentPrice: 100,
+		TimeframeData: map[string]*market.TimeframeSeriesData{
+			"15m": {
+				BOLLUpper:   []float64{100 + bollWidth/2},
+				BOLLMiddle:  []float64{100},
+				BOLLLower:   []float64{100 - bollWidth/2},
+				ATR14:       atrPct,
+				EMA20Values: []float64{100 + emaGapPct},
+				EMA50Values: []float64{100},
+			},
+		},
+	}
+}
+
+func TestDetectMarketRegime(t *testing.T) {
+	rising := map[string]*market.Data{
+		"ETHUSDT": {PriceChange4h: 2}, "SOLUSDT": {PriceChange4h: 3}, "XRPUSDT": {PriceChange4h: -1},
+	}
+	falling := map[string]*market.Data{
+		"ETHUSDT": {PriceChange4h: -2}, "SOLUSDT": {PriceChange4h: -3}, "XRPUSDT": {PriceChange4h: 1},
+	}
+
+	tests := []struct {
+		name      string
+		benchmark *market.Data
+		universe  map[string]*market.Data
+		want      string
+	}{
+		{"uptrend with breadth", regimeTestData(2.5, 1.5, 3), rising, store.MarketRegimeTrendingUp},
+		{"downtrend with breadth", regimeTestData(2.5, 1.5, -3), falling, store.MarketRegimeTrendingDown},
+		{"uptrend without breadth", regimeTestData(2.5, 1.5, 3), falling, store.MarketRegimeRanging},
+		{"quiet range", regimeTestData(1.5, 0.5, 0.1), rising, store.MarketRegimeRanging},
+		{"choppy and volatile", regimeTestData(5, 3.5, 0.5), rising, store.MarketRegimeVolatile},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := DetectMarketRegime(tt.benchmark, tt.universe, nil, "15m")
+			if r == nil || r.Regime != tt.want {
+				t.Fatalf("regime = %+v, want %s", r, tt.want)
+			}
+		})
+	}
+
+	if r := DetectMarketRegime(&market.Data{CurrentPrice: 100}, nil, nil, "15m"); r != nil {
+		t.Errorf("benchmark without indicators should give no regime, got %+v", r)
+	}
+}
+
+func TestMarketBreadthCombinesUniverseAndRankings(t *testing.T) {
+	universe := map[string]*market.Data{"A": {PriceChange4h: 1}, "B": {PriceChange4h: -1}}
+	ranking := &nofxos.PriceRankingData{Durations: map[string]*nofxos.PriceRankingDuration{
+		"4h": {
+			Top: []nofxos.PriceRankingItem{{PriceDelta: 0.09}},
+			Low: []nofxos.PriceRankingItem{{PriceDelta: -0.01}},
+		},
+	}}
+	// universe 0.5, rankings 0.9
+	if got := marketBreadth(universe, ranking); got < 0.69 || got > 0.71 {
+		t.Errorf("breadth = %f, want 0.7", got)
+	}
+	if got := marketBreadth(nil, nil); got != -1 {
+		t.Errorf("breadth without data = %f, want -1", got)
+	}
+}
+
+func TestMarketRegimeVariantMapping(t *testing.T) {
+	cfg := store.MarketRegimeConfig{Enabled: true, Variants: map[string]string{store.MarketRegimeVolatile: store.PromptVariantScalping}}
+	if v := cfg.VariantFor(store.MarketRegimeVolatile); v != store.PromptVariantScalping {
+		t.Errorf("user mapping ignored: %s", v)
+	}
+	if v := cfg.VariantFor(store.MarketRegimeTrendingUp); v != store.PromptVariantAggressive {
+		t.Errorf("default mapping = %s", v)
+	}
+
+	strategy := store.GetDefaultStrategyConfig("en")
+	strategy.MarketRegime = store.MarketRegimeConfig{Enabled: true, Variants: map[string]string{"Ranging": "CONSERVATIVE", "sideways": "scalping"}}
+	strategy.NormalizeProductSchema()
+	if len(strategy.MarketRegime.Variants) != 1 || strategy.MarketRegime.Variants[store.MarketRegimeRanging] != store.PromptVariantConservative {
+		t.Errorf("normalized variants = %v", strategy.MarketRegime.Variants)
+	}
+}
@@ -2,6 +2,8 @@ package kernel
 
 import (
 	"testing"
+
+	"nofx/store"
 )
 
 // TestLeverageFallback tests automatic correction when leverage exceeds limit
@@ -84,7 +86,12 @@ func TestLeverageFallback(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Use default position value ratios for testing (10x for BTC/ETH, 1.5x for altcoins)
-			err := validateDecision(&tt.decision, tt.accountEquity, tt.btcEthLeverage, tt.altcoinLeverage, 10.0, 1.5)
+			err := validateDecision(&tt.decision, tt.accountEquity, store.RiskControlConfig{
+				BTCETHMaxLeverage:            tt.btcEthLeverage,
+				AltcoinMaxLeverage:           tt.altcoinLeverage,
+				BTCETHMaxPositionValueRatio:  10.0,
+				AltcoinMaxPositionValueRatio: 1.5,
+			})
 
 			// Check error status
 			if (err != nil) != tt.wantError {
@@ -110,11 +117,51 @@ func TestClaw402XyzAllowsFullTenXNotional(t *testing.T) {
 		TakeProfit:      120,
 	}
 
-	if err := validateDecision(&decision, 30.68, 10, 10, 10.0, 10.0); err != nil {
+	riskControl := store.RiskControlConfig{
+		BTCETHMaxLeverage:            10,
+		AltcoinMaxLeverage:           10,
+		BTCETHMaxPositionValueRatio:  10.0,
+		AltcoinMaxPositionValueRatio: 10.0,
+	}
+	if err := validateDecision(&decision, 30.68, riskControl); err != nil {
 		t.Fatalf("xyz TradeFi Claw402 full 10x notional should pass validation: %v", err)
 	}
 }
 
+func TestValidateDecisionUsesAssetTierLimits(t *testing.T) {
+	riskControl := store.RiskControlConfig{
+		AltcoinMaxLeverage:           5,
+		AltcoinMaxPositionValueRatio: 1,
+		AssetTiers: []store.AssetTierConfig{
+			{Name: "memes", Symbols: []string{"DOGE"}, MaxLeverage: 2, MaxPositionValueRatio: 0.5, MinPositionSize: 20},
+			{Name: "equities", Categories: []string{store.AssetCategoryStock}, MaxLeverage: 8, MaxPositionValueRatio: 4},
+		},
+	}
+
+	meme := Decision{Symbol: "DOGEUSDT", Action: "open_long", Leverage: 10, PositionSizeUSD: 50, StopLoss: 50, TakeProfit: 200}
+	if err := validateDecision(&meme, 100, riskControl); err != nil {
+		t.Fatalf("meme within tier limits should pass: %v", err)
+	}
+	if meme.Leverage != 2 {
+		t.Errorf("meme leverage should be cut to the tier max 2x, got %dx", meme.Leverage)
+	}
+
+	meme.PositionSizeUSD = 80
+	if err := validateDecision(&meme, 100, riskControl); err == nil || !contains(err.Error(), "memes tier") {
+		t.Errorf("meme above 0.5x equity should fail with the tier name, got %v", err)
+	}
+
+	meme.PositionSizeUSD = 15
+	if err := validateDecision(&meme, 100, riskControl); err == nil {
+		t.Error("meme below the tier minimum size should fail")
+	}
+
+	stock := Decision{Symbol: "xyz:TSLA", Action: "open_short", Leverage: 8, PositionSizeUSD: 350, StopLoss: 200, TakeProfit: 50}
+	if err := validateDecision(&stock, 100, riskControl); err != nil {
+		t.Fatalf("stock within the equities tier should pass: %v", err)
+	}
+}
+
 // contains checks if string contains substring (helper function)
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(substr) == 0 ||
@@ -1,8 +1,28 @@
 package logger
 
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Log formats
+const (
+	FormatText = "text" // compact emoji-friendly lines (default)
+	FormatJSON = "json" // one JSON object per line with the correlation fields
+)
+
 // Config is the logger configuration (simplified version)
 type Config struct {
-	Level string `json:"level"` // Log level: debug, info, warn, error (default: info)
+	Level  string `json:"level"`  // Log level: debug, info, warn, error (default: info)
+	Format string `json:"format"` // Log format: text or json (default: text)
+
+	// Per-trader log files: lines carrying a trader_id are also written to
+	// <TraderDir>/<trader_id>.log, rotated at TraderMaxSizeMB
+	TraderFiles      bool   `json:"trader_files"`
+	TraderDir        string `json:"trader_dir"`         // default: data/logs/traders
+	TraderMaxSizeMB  int    `json:"trader_max_size_mb"` // default: 10
+	TraderMaxBackups int    `json:"trader_max_backups"` // rotated files kept per trader (default: 5)
 }
 
 // SetDefaults sets default values
@@ -10,4 +30,31 @@ func (c *Config) SetDefaults() {
 	if c.Level == "" {
 		c.Level = "info"
 	}
+	if c.Format == "" {
+		c.Format = FormatText
+	}
+	if c.TraderDir == "" {
+		c.TraderDir = "data/logs/traders"
+	}
+	if c.TraderMaxSizeMB <= 0 {
+		c.TraderMaxSizeMB = 10
+	}
+	if c.TraderMaxBackups <= 0 {
+		c.TraderMaxBackups = 5
+	}
+}
+
+// ConfigFromEnv reads LOG_LEVEL, LOG_FORMAT, LOG_TRADER_FILES, LOG_TRADER_DIR,
+// LOG_TRADER_MAX_SIZE_MB and LOG_TRADER_MAX_BACKUPS
+func ConfigFromEnv() *Config {
+	cfg := &Config{
+		Level:     strings.TrimSpace(os.Getenv("LOG_LEVEL")),
+		Format:    strings.ToLower(strings.TrimSpace(os.Getenv("LOG_FORMAT"))),
+		TraderDir: strings.TrimSpace(os.Getenv("LOG_TRADER_DIR")),
+	}
+	cfg.TraderFiles, _ = strconv.ParseBool(os.Getenv("LOG_TRADER_FILES"))
+	cfg.TraderMaxSizeMB, _ = strconv.Atoi(os.Getenv("LOG_TRADER_MAX_SIZE_MB"))
+	cfg.TraderMaxBackups, _ = strconv.Atoi(os.Getenv("LOG_TRADER_MAX_BACKUPS"))
+	cfg.SetDefaults()
+	return cfg
 }
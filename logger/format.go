@@ -0,0 +1,45 @@
+package logger
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// jsonFormatter writes one JSON object per line: time, level, caller, msg
+// and every field of the entry (trader_id, cycle_id, ...)
+type jsonFormatter struct{}
+
+func (f *jsonFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	data := make(map[string]interface{}, len(entry.Data)+4)
+	for k, v := range entry.Data {
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
+		data[k] = v
+	}
+	data["time"] = entry.Time.Format(time.RFC3339Nano)
+	data["level"] = entry.Level.String()
+	data["caller"] = findCaller()
+	data["msg"] = entry.Message
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+func newFormatter(format string) logrus.Formatter {
+	if format == FormatJSON {
+		return &jsonFormatter{}
+	}
+	return &compactFormatter{}
+}
+
+// IsJSON reports whether logs are written as JSON, where the correlation
+// fields make message prefixes such as "[trader_id=...]" redundant
+func IsJSON() bool {
+	_, ok := Log.Formatter.(*jsonFormatter)
+	return ok
+}
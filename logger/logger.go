@@ -27,26 +27,26 @@ type compactFormatter struct {
 func (f *compactFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 	level := strings.ToUpper(entry.Level.String())[0:4]
 	timestamp := entry.Time.Format("01-02 15:04:05")
+	msg := fmt.Sprintf("%s [%s] %s %s\n", timestamp, level, findCaller(), entry.Message)
+	return []byte(msg), nil
+}
 
-	// Skip frames to find actual caller (skip logrus + our wrapper functions)
-	caller := ""
-	for i := 3; i < 10; i++ {
+// findCaller returns "pkg/file.go:line" of the code that logged, skipping
+// logrus and this package's wrapper functions
+func findCaller() string {
+	for i := 2; i < 20; i++ {
 		_, file, line, ok := runtime.Caller(i)
 		if !ok {
 			break
 		}
-		// Skip logrus internal and our logger.go
-		if !strings.Contains(file, "logrus") && !strings.HasSuffix(file, "logger/logger.go") {
-			// Get package name from path (e.g., "nofx/manager/trader_manager.go" -> "manager")
-			dir := filepath.Dir(file)
-			pkg := filepath.Base(dir)
-			caller = fmt.Sprintf("%s/%s:%d", pkg, filepath.Base(file), line)
-			break
+		// Get package name from path (e.g., "nofx/manager/trader_manager.go" -> "manager")
+		pkg := filepath.Base(filepath.Dir(file))
+		if strings.Contains(file, "logrus") || pkg == "logger" {
+			continue
 		}
+		return fmt.Sprintf("%s/%s:%d", pkg, filepath.Base(file), line)
 	}
-
-	msg := fmt.Sprintf("%s [%s] %s %s\n", timestamp, level, caller, entry.Message)
-	return []byte(msg), nil
+	return ""
 }
 
 func init() {
@@ -55,6 +55,7 @@ func init() {
 	Log.SetLevel(logrus.InfoLevel)
 	Log.SetFormatter(&compactFormatter{})
 	Log.SetOutput(os.Stdout)
+	installTraderHook(Log, Log.Formatter, &Config{})
 }
 
 // ============================================================================
@@ -81,8 +82,10 @@ func Init(cfg *Config) error {
 	}
 	Log.SetLevel(level)
 
-	// Set compact formatter
-	Log.SetFormatter(&compactFormatter{})
+	// Set compact (default) or JSON formatter
+	formatter := newFormatter(cfg.Format)
+	Log.SetFormatter(formatter)
+	installTraderHook(Log, formatter, cfg)
 
 	// Setup log file output (write to both stdout and file)
 	logDir := "data"
@@ -117,6 +120,7 @@ func Shutdown() {
 		logFile.Close()
 		logFile = nil
 	}
+	closeTraderFiles()
 }
 
 // ============================================================================
@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Fields correlation fields attached to log lines
+type Fields = logrus.Fields
+
+// Correlation field names
+const (
+	FieldTraderID  = "trader_id"
+	FieldCycle     = "cycle"
+	FieldCycleID   = "cycle_id"
+	FieldExchange  = "exchange"
+	FieldSymbol    = "symbol"
+	FieldRequestID = "request_id"
+)
+
+// Scope carries correlation fields onto every line logged through it. Its
+// fields never change: a trader keeps one base Scope (trader, exchange) for
+// its lifetime and hands it to its exchange adapter, and derives a Scope per
+// cycle and per order with With, so every line of a cycle can be found by its
+// cycle_id and lines logged outside a cycle never carry one. A nil Scope logs
+// through the global logger without fields.
+//
+// Scope implements mcp.Logger.
+type Scope struct {
+	fields Fields
+}
+
+// NewScope creates a scope with the given fields
+func NewScope(fields Fields) *Scope {
+	s := &Scope{fields: make(Fields, len(fields))}
+	for k, v := range fields {
+		s.fields[k] = v
+	}
+	return s
+}
+
+// With returns a new scope with the fields of s plus key, e.g. the cycle or
+// the symbol of the order being placed
+func (s *Scope) With(key string, value interface{}) *Scope {
+	fields := s.Fields()
+	fields[key] = value
+	return NewScope(fields)
+}
+
+// Fields returns a copy of the fields
+func (s *Scope) Fields() Fields {
+	fields := make(Fields)
+	if s == nil {
+		return fields
+	}
+	for k, v := range s.fields {
+		fields[k] = v
+	}
+	return fields
+}
+
+func (s *Scope) entry() *logrus.Entry {
+	return Log.WithFields(s.Fields())
+}
+
+func (s *Scope) Debugf(format string, args ...any) {
+	s.entry().Debugf(format, args...)
+}
+
+func (s *Scope) Info(args ...any) {
+	s.entry().Info(args...)
+}
+
+func (s *Scope) Infof(format string, args ...any) {
+	s.entry().Infof(format, args...)
+}
+
+func (s *Scope) Warnf(format string, args ...any) {
+	s.entry().Warnf(format, args...)
+}
+
+func (s *Scope) Errorf(format string, args ...any) {
+	s.entry().Errorf(format, args...)
+}
+
+// NewCorrelationID returns a random ID for a cycle or request
+func NewCorrelationID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,225 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// recentLinesPerTrader the number of lines kept in memory per trader for the
+// log tail API
+const recentLinesPerTrader = 1000
+
+// traderLogs routes lines carrying a trader_id to that trader's in-memory
+// tail and, when enabled, to its own rotated log file. Concurrently running
+// traders share stdout; their own streams stay readable this way.
+type traderLogs struct {
+	formatter logrus.Formatter
+	cfg       Config
+
+	mu     sync.Mutex
+	recent map[string][]string
+	files  map[string]*rotatingFile
+}
+
+var (
+	traderLogsMu sync.RWMutex
+	traderStream *traderLogs
+)
+
+// installTraderHook adds the per-trader hook to l. The recent lines of the
+// previous hook are kept so re-initializing the logger does not empty tails.
+func installTraderHook(l *logrus.Logger, formatter logrus.Formatter, cfg *Config) {
+	h := &traderLogs{
+		formatter: formatter,
+		cfg:       *cfg,
+		recent:    make(map[string][]string),
+		files:     make(map[string]*rotatingFile),
+	}
+	traderLogsMu.Lock()
+	if old := traderStream; old != nil {
+		old.mu.Lock()
+		for id, lines := range old.recent {
+			h.recent[id] = lines
+		}
+		old.closeFiles()
+		old.mu.Unlock()
+	}
+	traderStream = h
+	traderLogsMu.Unlock()
+	l.AddHook(h)
+}
+
+func (h *traderLogs) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *traderLogs) Fire(entry *logrus.Entry) error {
+	traderID, _ := entry.Data[FieldTraderID].(string)
+	if traderID == "" || !entry.Logger.IsLevelEnabled(entry.Level) {
+		return nil
+	}
+	line, err := h.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	lines := append(h.recent[traderID], strings.TrimRight(string(line), "\n"))
+	if len(lines) > recentLinesPerTrader {
+		lines = append([]string(nil), lines[len(lines)-recentLinesPerTrader:]...)
+	}
+	h.recent[traderID] = lines
+
+	if !h.cfg.TraderFiles {
+		return nil
+	}
+	f, ok := h.files[traderID]
+	if !ok {
+		f = &rotatingFile{
+			path:    filepath.Join(h.cfg.TraderDir, traderLogFileName(traderID)),
+			maxSize: int64(h.cfg.TraderMaxSizeMB) << 20,
+			backups: h.cfg.TraderMaxBackups,
+		}
+		h.files[traderID] = f
+	}
+	return f.Write(line)
+}
+
+func (h *traderLogs) closeFiles() {
+	for id, f := range h.files {
+		f.Close()
+		delete(h.files, id)
+	}
+}
+
+// RecentTraderLogs returns up to n of the latest log lines of a trader,
+// oldest first, formatted like the main log
+func RecentTraderLogs(traderID string, n int) []string {
+	traderLogsMu.RLock()
+	h := traderStream
+	traderLogsMu.RUnlock()
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	lines := h.recent[traderID]
+	if n > 0 && len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return append([]string(nil), lines...)
+}
+
+// ForgetTrader drops the in-memory tail of a deleted trader and closes its
+// log file (the file itself is kept)
+func ForgetTrader(traderID string) {
+	traderLogsMu.RLock()
+	h := traderStream
+	traderLogsMu.RUnlock()
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.recent, traderID)
+	if f, ok := h.files[traderID]; ok {
+		f.Close()
+		delete(h.files, traderID)
+	}
+}
+
+func closeTraderFiles() {
+	traderLogsMu.RLock()
+	h := traderStream
+	traderLogsMu.RUnlock()
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closeFiles()
+}
+
+// traderLogFileName keeps trader IDs from escaping the log directory
+func traderLogFileName(traderID string) string {
+	safe := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, traderID)
+	return safe + ".log"
+}
+
+// rotatingFile appends to path and renames it to path.1 (path.1 to path.2,
+// ...) once it reaches maxSize, keeping at most backups old files
+type rotatingFile struct {
+	path    string
+	maxSize int64
+	backups int
+
+	f    *os.File
+	size int64
+}
+
+func (r *rotatingFile) Write(p []byte) error {
+	if r.f == nil {
+		if err := r.open(); err != nil {
+			return err
+		}
+	}
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return err
+}
+
+func (r *rotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.f, r.size = f, info.Size()
+	return nil
+}
+
+func (r *rotatingFile) rotate() error {
+	r.Close()
+	for i := r.backups - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+	}
+	if r.backups > 0 {
+		if err := os.Rename(r.path, r.path+".1"); err != nil {
+			return err
+		}
+	} else if err := os.Remove(r.path); err != nil {
+		return err
+	}
+	return r.open()
+}
+
+func (r *rotatingFile) Close() {
+	if r.f != nil {
+		r.f.Close()
+		r.f = nil
+	}
+}
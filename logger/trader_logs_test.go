@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func initTestLogger(t *testing.T, cfg *Config) *bytes.Buffer {
+	t.Helper()
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	var out bytes.Buffer
+	Log.SetOutput(&out)
+	t.Cleanup(func() {
+		Shutdown()
+		Init(nil)
+	})
+	return &out
+}
+
+func TestJSONLinesCarryScopeFields(t *testing.T) {
+	t.Chdir(t.TempDir())
+	out := initTestLogger(t, &Config{Format: FormatJSON})
+
+	scope := NewScope(Fields{FieldTraderID: "t1", FieldExchange: "binance"}).With(FieldCycleID, "c-42")
+	scope.With(FieldSymbol, "BTCUSDT").Infof("opened %s", "long")
+	scope.Infof("cycle done")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines: %q", len(lines), out.String())
+	}
+	var first, second map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("line is not JSON: %v", err)
+	}
+	json.Unmarshal([]byte(lines[1]), &second)
+	if first["msg"] != "opened long" || first["trader_id"] != "t1" || first["cycle_id"] != "c-42" || first["symbol"] != "BTCUSDT" {
+		t.Fatalf("first line = %v", first)
+	}
+	if _, ok := second["symbol"]; ok {
+		t.Fatalf("With changed the parent scope: %v", second)
+	}
+	if caller, _ := first["caller"].(string); caller == "" {
+		t.Fatal("line has no caller")
+	}
+}
+
+func TestRecentTraderLogs(t *testing.T) {
+	t.Chdir(t.TempDir())
+	initTestLogger(t, nil)
+
+	a := NewScope(Fields{FieldTraderID: "tail-a"})
+	for i := 0; i < recentLinesPerTrader+5; i++ {
+		a.Infof("line %d", i)
+	}
+	NewScope(Fields{FieldTraderID: "tail-b"}).Warnf("other trader")
+	Infof("no trader")
+
+	lines := RecentTraderLogs("tail-a", 2)
+	if len(lines) != 2 || !strings.HasSuffix(lines[1], "line 1004") {
+		t.Fatalf("tail = %q", lines)
+	}
+	if n := len(RecentTraderLogs("tail-a", 0)); n != recentLinesPerTrader {
+		t.Fatalf("kept %d lines, want %d", n, recentLinesPerTrader)
+	}
+	if lines := RecentTraderLogs("tail-b", 10); len(lines) != 1 || strings.Contains(lines[0], "line") {
+		t.Fatalf("traders share a tail: %q", lines)
+	}
+
+	ForgetTrader("tail-a")
+	if lines := RecentTraderLogs("tail-a", 10); len(lines) != 0 {
+		t.Fatalf("tail after ForgetTrader = %q", lines)
+	}
+}
+
+func TestTraderLogFilesRotate(t *testing.T) {
+	t.Chdir(t.TempDir())
+	dir := t.TempDir()
+	initTestLogger(t, &Config{TraderFiles: true, TraderDir: dir, TraderMaxSizeMB: 1, TraderMaxBackups: 2})
+
+	scope := NewScope(Fields{FieldTraderID: "../escape"})
+	payload := strings.Repeat("x", 300<<10)
+	for i := 0; i < 8; i++ {
+		scope.Infof("%s", payload)
+	}
+	Shutdown()
+
+	base := filepath.Join(dir, "___escape.log")
+	for _, name := range []string{base, base + ".1", base + ".2"} {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatalf("missing %s: %v", name, err)
+		}
+		if info.Size() > 1<<20 {
+			t.Fatalf("%s is %d bytes, over the rotation size", name, info.Size())
+		}
+	}
+	if _, err := os.Stat(base + ".3"); !os.IsNotExist(err) {
+		t.Fatalf("kept more than 2 backups: %v", err)
+	}
+}
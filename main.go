@@ -1,20 +1,26 @@
 package main
 
 import (
+	"context"
 	"nofx/api"
 	"nofx/auth"
 	"nofx/config"
 	"nofx/crypto"
 	"nofx/logger"
+	"nofx/maintenance"
 	"nofx/manager"
 	_ "nofx/mcp/payment"
 	_ "nofx/mcp/provider"
+	"nofx/secrets"
 	"nofx/store"
 	"nofx/telemetry"
+	"nofx/tracing"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
@@ -32,8 +38,8 @@ func main() {
 	// Load .env environment variables
 	_ = godotenv.Load()
 
-	// Initialize logger
-	logger.Init(nil)
+	// Initialize logger (LOG_FORMAT=json for structured lines)
+	logger.Init(logger.ConfigFromEnv())
 
 	logger.Info("╔════════════════════════════════════════════════════════════╗")
 	logger.Info("║           🚀 NOFX - AI-Powered Trading System              ║")
@@ -45,6 +51,12 @@ func main() {
 	cfg := config.Get()
 	logger.Info("✅ Configuration loaded")
 
+	// OpenTelemetry tracing (only when OTEL_EXPORTER_OTLP_ENDPOINT is set)
+	shutdownTracing, err := tracing.InitFromEnv(context.Background())
+	if err != nil {
+		logger.Warnf("⚠️ Failed to initialize tracing: %v", err)
+	}
+
 	// Initialize encryption service BEFORE database (so EncryptedString can decrypt on read)
 	logger.Info("🔐 Initializing encryption service...")
 	cryptoService, err := crypto.NewCryptoService()
@@ -54,6 +66,14 @@ func main() {
 	crypto.SetGlobalCryptoService(cryptoService)
 	logger.Info("✅ Encryption service initialized successfully")
 
+	// External secret backends for exchange / AI model secrets stored as references
+	secretResolver, err := secrets.NewResolverFromEnv()
+	if err != nil {
+		logger.Fatalf("❌ Failed to initialize secret backends: %v", err)
+	}
+	secrets.SetDefault(secretResolver)
+	logger.Infof("✅ Secret backends: %s", strings.Join(secretResolver.Backends(), ", "))
+
 	// Initialize database from configuration
 	// For backward compatibility: command line arg overrides config (SQLite only)
 	if len(os.Args) > 1 {
@@ -91,6 +111,16 @@ func main() {
 	// Initialize installation ID for experience improvement (anonymous statistics)
 	initInstallationID(st)
 
+	// Prune and archive old decision, equity and order data, when enabled in the retention policy
+	retention := maintenance.NewRetentionService(st)
+	retention.Start()
+	defer retention.Stop()
+
+	// Scheduled backups, when enabled in the backup policy
+	backups := maintenance.NewBackupService(st, cryptoService)
+	backups.Start()
+	defer backups.Stop()
+
 	// Set JWT secret
 	auth.SetJWTSecret(cfg.JWTSecret)
 	logger.Info("🔑 JWT secret configured")
@@ -163,6 +193,13 @@ func main() {
 
 	// Stop all traders
 	traderManager.StopAll()
+
+	// Flush the spans of the last cycles
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := shutdownTracing(flushCtx); err != nil {
+		logger.Warnf("⚠️ Tracing shutdown error: %v", err)
+	}
+	cancel()
 	logger.Info("✅ System shut down safely")
 }
 
@@ -0,0 +1,136 @@
+package maintenance
+
+import (
+	"fmt"
+	"nofx/crypto"
+	"nofx/logger"
+	"nofx/store"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// backupFilePrefix names scheduled backups; rotation only touches these files
+const backupFilePrefix = "nofx-backup-"
+
+// BackupService writes scheduled backups per the backup policy in
+// system_config and keeps the newest ones
+type BackupService struct {
+	scheduler
+	st *store.Store
+	cs *crypto.CryptoService
+}
+
+// NewBackupService creates a backup service. cs verifies that encrypted
+// fields of each backup decrypt; nil skips that check.
+func NewBackupService(st *store.Store, cs *crypto.CryptoService) *BackupService {
+	return &BackupService{scheduler: newScheduler(), st: st, cs: cs}
+}
+
+// Start runs the service in the background until Stop
+func (s *BackupService) Start() {
+	s.start(s.runIfDue)
+}
+
+func (s *BackupService) runIfDue(now time.Time) {
+	policy, err := s.st.GetBackupPolicy()
+	if err != nil {
+		logger.Warnf("⚠️ Backup: %v", err)
+		return
+	}
+	if !policy.Enabled {
+		return
+	}
+	last, err := s.st.GetLastBackupReport()
+	if err != nil {
+		logger.Warnf("⚠️ Backup: %v", err)
+	}
+	if last != nil && !due(last.StartedAt, policy.IntervalHours, now) {
+		return
+	}
+
+	report := RunBackup(s.st, s.cs, policy, now)
+	if report.Error != "" {
+		logger.Errorf("❌ Scheduled backup failed: %s", report.Error)
+	} else {
+		logger.Infof("💾 Scheduled backup written to %s (%.1f MB, %d ms)",
+			report.Backup.Path, float64(report.Backup.Size)/(1<<20), report.Backup.DurationMs)
+	}
+	if err := s.st.SaveBackupReport(report); err != nil {
+		logger.Warnf("⚠️ Backup: failed to save report: %v", err)
+	}
+}
+
+// RunBackup writes a backup into the policy's directory, verifies it when
+// the policy asks for it and deletes the backups beyond the keep count
+func RunBackup(st *store.Store, cs *crypto.CryptoService, policy store.BackupPolicy, now time.Time) *store.BackupReport {
+	policy = policy.Normalize()
+	report := &store.BackupReport{StartedAt: now}
+	if err := os.MkdirAll(policy.Dir, 0o700); err != nil {
+		report.Error = fmt.Sprintf("create backup dir: %v", err)
+		return report
+	}
+
+	path := filepath.Join(policy.Dir, BackupFileName(st, now))
+	info, err := st.Backup(path)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	report.Backup = info
+
+	if policy.Verify {
+		v, err := store.VerifyBackup(path, cs)
+		if err != nil {
+			report.Error = fmt.Sprintf("verification failed: %v", err)
+			return report
+		}
+		report.Verification = v
+		if !v.OK() {
+			report.Error = fmt.Sprintf("encrypted fields do not decrypt with the current keys: %s", strings.Join(v.DecryptFailures, ", "))
+			return report
+		}
+	}
+
+	rotated, err := rotateBackups(policy.Dir, policy.Keep)
+	report.Rotated = rotated
+	if err != nil {
+		report.Error = fmt.Sprintf("rotation failed: %v", err)
+	}
+	return report
+}
+
+// BackupFileName the file name of a backup taken at t
+func BackupFileName(st *store.Store, t time.Time) string {
+	return backupFilePrefix + t.UTC().Format("20060102-150405") + st.BackupExtension()
+}
+
+// rotateBackups deletes all but the newest keep backups in dir. Names sort
+// by time, so the oldest come first.
+func rotateBackups(dir string, keep int) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var backups []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasPrefix(e.Name(), backupFilePrefix) {
+			backups = append(backups, e.Name())
+		}
+	}
+	if len(backups) <= keep {
+		return nil, nil
+	}
+	sort.Strings(backups)
+	var removed []string
+	for _, name := range backups[:len(backups)-keep] {
+		path := filepath.Join(dir, name)
+		if err := os.Remove(path); err != nil {
+			return removed, err
+		}
+		removed = append(removed, path)
+	}
+	return removed, nil
+}
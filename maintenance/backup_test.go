@@ -0,0 +1,35 @@
+package maintenance
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRotateBackupsKeepsNewest(t *testing.T) {
+	dir := t.TempDir()
+	names := []string{
+		"nofx-backup-20250101-000000.db",
+		"nofx-backup-20250102-000000.db",
+		"nofx-backup-20250103-000000.db",
+		"notes.txt",
+	}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := rotateBackups(dir, 2)
+	if err != nil {
+		t.Fatalf("rotateBackups: %v", err)
+	}
+	if len(removed) != 1 || filepath.Base(removed[0]) != "nofx-backup-20250101-000000.db" {
+		t.Fatalf("removed %v, want only the oldest backup", removed)
+	}
+	for _, name := range names[1:] {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Fatalf("%s should be kept: %v", name, err)
+		}
+	}
+}
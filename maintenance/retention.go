@@ -0,0 +1,65 @@
+// Package maintenance runs scheduled housekeeping jobs against the store
+package maintenance
+
+import (
+	"nofx/logger"
+	"nofx/store"
+	"time"
+)
+
+// RetentionService applies the retention policy from system_config on its
+// configured interval
+type RetentionService struct {
+	scheduler
+	st *store.Store
+}
+
+// NewRetentionService creates a retention service for the store
+func NewRetentionService(st *store.Store) *RetentionService {
+	return &RetentionService{scheduler: newScheduler(), st: st}
+}
+
+// Start runs the service in the background until Stop
+func (s *RetentionService) Start() {
+	s.start(s.runIfDue)
+}
+
+func (s *RetentionService) runIfDue(now time.Time) {
+	policy, err := s.st.GetRetentionPolicy()
+	if err != nil {
+		logger.Warnf("⚠️ Retention: %v", err)
+		return
+	}
+	if !policy.Enabled {
+		return
+	}
+	last, err := s.st.GetLastRetentionReport()
+	if err != nil {
+		logger.Warnf("⚠️ Retention: %v", err)
+	}
+	if !retentionDue(policy, last, now) {
+		return
+	}
+	LogRetentionReport(s.st.ApplyRetention(policy))
+}
+
+// retentionDue reports whether the interval since the last run has passed
+func retentionDue(policy store.RetentionPolicy, last *store.RetentionReport, now time.Time) bool {
+	if last == nil {
+		return true
+	}
+	return due(last.StartedAt, policy.Normalize().IntervalHours, now)
+}
+
+// LogRetentionReport logs the outcome of a retention run
+func LogRetentionReport(r *store.RetentionReport) {
+	logger.Infof("🧹 Retention: %d prompts stripped, %d decisions deleted, %d equity snapshots thinned, %d deleted, %d orders / %d fills archived, %.1f MB reclaimed (%d ms)",
+		r.PromptsStripped, r.DecisionsDeleted, r.EquityDownsampled, r.EquityDeleted, r.OrdersArchived, r.FillsArchived,
+		float64(r.BytesReclaimed)/(1<<20), r.DurationMs)
+	if r.ArchiveFile != "" {
+		logger.Infof("🧹 Retention: archived orders written to %s", r.ArchiveFile)
+	}
+	for _, e := range r.Errors {
+		logger.Warnf("⚠️ Retention: %s", e)
+	}
+}
@@ -0,0 +1,25 @@
+package maintenance
+
+import (
+	"nofx/store"
+	"testing"
+	"time"
+)
+
+func TestRetentionDue(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	policy := store.RetentionPolicy{Enabled: true, IntervalHours: 24}
+
+	if !retentionDue(policy, nil, now) {
+		t.Fatal("first run should be due")
+	}
+	if retentionDue(policy, &store.RetentionReport{StartedAt: now.Add(-23 * time.Hour)}, now) {
+		t.Fatal("run within the interval should not be due")
+	}
+	if !retentionDue(policy, &store.RetentionReport{StartedAt: now.Add(-24 * time.Hour)}, now) {
+		t.Fatal("run after the interval should be due")
+	}
+	if !retentionDue(store.RetentionPolicy{Enabled: true}, &store.RetentionReport{StartedAt: now.Add(-25 * time.Hour)}, now) {
+		t.Fatal("unset interval should default to 24h")
+	}
+}
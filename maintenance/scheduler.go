@@ -0,0 +1,53 @@
+package maintenance
+
+import (
+	"sync"
+	"time"
+)
+
+// checkInterval how often a service checks whether its job is due. Policies
+// are re-read each time, so edits apply without a restart.
+const checkInterval = 15 * time.Minute
+
+// scheduler calls a check function at start and then every checkInterval
+// until stopped
+type scheduler struct {
+	stop chan struct{}
+	wg   sync.WaitGroup
+	once sync.Once
+}
+
+func newScheduler() scheduler {
+	return scheduler{stop: make(chan struct{})}
+}
+
+func (s *scheduler) start(check func(now time.Time)) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+		for {
+			check(time.Now().UTC())
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the service and waits for a running job to finish
+func (s *scheduler) Stop() {
+	s.once.Do(func() { close(s.stop) })
+	s.wg.Wait()
+}
+
+// due reports whether intervalHours have passed since the last run
+func due(last time.Time, intervalHours int, now time.Time) bool {
+	if last.IsZero() {
+		return true
+	}
+	return !now.Before(last.Add(time.Duration(intervalHours) * time.Hour))
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"nofx/logger"
+	"nofx/metrics"
+	"nofx/secrets"
 	"nofx/store"
 	"nofx/trader"
 	"sort"
@@ -52,6 +54,18 @@ func (tm *TraderManager) GetLoadError(traderID string) error {
 	return tm.loadErrors[traderID]
 }
 
+// LoadErrors returns a copy of the last load error of every trader that
+// failed to load
+func (tm *TraderManager) LoadErrors() map[string]error {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	errs := make(map[string]error, len(tm.loadErrors))
+	for id, err := range tm.loadErrors {
+		errs[id] = err
+	}
+	return errs
+}
+
 // GetTrader retrieves a trader by ID
 func (tm *TraderManager) GetTrader(id string) (*trader.AutoTrader, error) {
 	tm.mu.RLock()
@@ -409,6 +423,7 @@ func (tm *TraderManager) RemoveTrader(traderID string) {
 		delete(tm.traders, traderID)
 		logger.Infof("✓ Trader %s removed from memory", traderID)
 	}
+	metrics.ForgetTrader(traderID)
 }
 
 func ensureHyperliquidNativeStrategy(traderName, exchangeType string, cfg *store.StrategyConfig) {
@@ -637,21 +652,63 @@ func (tm *TraderManager) LoadTradersFromStore(st *store.Store) error {
 	return nil
 }
 
+// resolveFallbackAIModels loads the trader's AI failover chain, skipping
+// models that were deleted, disabled or have no API key
+func resolveFallbackAIModels(st *store.Store, traderCfg *store.Trader, primaryID string) []trader.FallbackAIModel {
+	var fallbacks []trader.FallbackAIModel
+	for _, id := range traderCfg.FallbackIDs() {
+		if id == primaryID {
+			continue
+		}
+		model, err := st.AIModel().Get(traderCfg.UserID, id)
+		if err != nil || !model.Enabled || model.APIKey == "" {
+			logger.Warnf("⚠️ Trader %s: fallback AI model %s unavailable, skipped", traderCfg.Name, id)
+			continue
+		}
+		apiKey, err := secrets.Resolve(model.UserID, string(model.APIKey))
+		if err != nil {
+			logger.Warnf("⚠️ Trader %s: fallback AI model %s unavailable, skipped: %v", traderCfg.Name, id, err)
+			continue
+		}
+		fallbacks = append(fallbacks, trader.FallbackAIModel{
+			ID:              model.ID,
+			Provider:        model.Provider,
+			APIKey:          apiKey,
+			CustomAPIURL:    model.CustomAPIURL,
+			CustomModelName: model.CustomModelName,
+		})
+	}
+	return fallbacks
+}
+
 // addTraderFromStore internal method: adds trader from store configuration
 func (tm *TraderManager) addTraderFromStore(traderCfg *store.Trader, aiModelCfg *store.AIModel, exchangeCfg *store.Exchange, st *store.Store) error {
 	if _, exists := tm.traders[traderCfg.ID]; exists {
 		return fmt.Errorf("trader ID '%s' already exists", traderCfg.ID)
 	}
 
+	// Secrets may be references to an external backend. Resolve them into
+	// copies used only for the in-memory trader config.
+	exchangeCfg, err := secrets.ResolveExchange(exchangeCfg)
+	if err != nil {
+		return fmt.Errorf("failed to resolve exchange secrets for trader %s: %w", traderCfg.Name, err)
+	}
+	aiModelCfg, err = secrets.ResolveAIModel(aiModelCfg)
+	if err != nil {
+		return fmt.Errorf("failed to resolve AI model secrets for trader %s: %w", traderCfg.Name, err)
+	}
+
 	// Load strategy config (must have strategy)
 	var strategyConfig *store.StrategyConfig
 	strategyConfigRaw := ""
+	strategyVersion := 0
 	if traderCfg.StrategyID != "" {
-		strategy, err := st.Strategy().Get(traderCfg.UserID, traderCfg.StrategyID)
+		strategy, err := st.Strategy().GetAtVersion(traderCfg.UserID, traderCfg.StrategyID, traderCfg.StrategyVersion)
 		if err != nil {
 			return fmt.Errorf("failed to load strategy %s for trader %s: %w", traderCfg.StrategyID, traderCfg.Name, err)
 		}
 		strategyConfigRaw = strategy.Config
+		strategyVersion = strategy.CurrentVersion
 		// Parse JSON config
 		strategyConfig, err = strategy.ParseConfig()
 		if err != nil {
@@ -695,6 +752,8 @@ func (tm *TraderManager) addTraderFromStore(traderCfg *store.Trader, aiModelCfg
 		ShowInCompetition:     traderCfg.ShowInCompetition,
 		StrategyConfig:        strategyConfig,
 		StrategyConfigRaw:     strategyConfigRaw,
+		StrategyVersion:       strategyVersion,
+		PinnedStrategyVersion: traderCfg.StrategyVersion,
 	}
 
 	logger.Infof("📊 Loading trader %s: ScanIntervalMinutes=%d (from DB), ScanInterval=%v",
@@ -754,6 +813,7 @@ func (tm *TraderManager) addTraderFromStore(traderCfg *store.Trader, aiModelCfg
 	}
 
 	traderConfig.Claw402WalletKey = resolveTraderDataWalletKey(st, traderCfg.UserID, aiModelCfg)
+	traderConfig.FallbackAIModels = resolveFallbackAIModels(st, traderCfg, aiModelCfg.ID)
 
 	// Create trader instance
 	at, err := trader.NewAutoTrader(traderConfig, st, traderCfg.UserID)
@@ -810,6 +870,9 @@ func resolveTraderDataWalletKey(st *store.Store, userID string, selectedModel *s
 	// selected as the AI brain.
 	preferredID := ""
 	walletKey, err := st.AIModel().ResolveClaw402WalletKey(userID, preferredID)
+	if err == nil {
+		walletKey, err = secrets.Resolve(userID, walletKey)
+	}
 	if err != nil {
 		logger.Warnf("⚠️ Failed to load claw402 wallet for trader data routing: %v", err)
 		return ""
@@ -108,6 +108,22 @@ func TestGetLoadError(t *testing.T) {
 	})
 }
 
+func TestLoadErrorsReturnsCopy(t *testing.T) {
+	tm := NewTraderManager()
+	tm.mu.Lock()
+	tm.loadErrors["trader-x"] = errors.New("boom")
+	tm.mu.Unlock()
+
+	errs := tm.LoadErrors()
+	if len(errs) != 1 || errs["trader-x"] == nil {
+		t.Fatalf("LoadErrors = %v, want trader-x", errs)
+	}
+	delete(errs, "trader-x")
+	if tm.GetLoadError("trader-x") == nil {
+		t.Error("deleting from the returned map changed the manager")
+	}
+}
+
 func TestGetAllTradersReturnsCopy(t *testing.T) {
 	tm := NewTraderManager()
 	at1 := newIdleTrader()
@@ -0,0 +1,84 @@
+package market
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	appconfig "nofx/config"
+	"nofx/logger"
+	"nofx/provider/coinank"
+	"nofx/provider/coinank/coinank_enum"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	marketCapCacheTTL   = 6 * time.Hour
+	marketCapRetryAfter = 10 * time.Minute // after a failed lookup, report unknown until then
+	marketCapTimeout    = 10 * time.Second
+)
+
+type marketCapEntry struct {
+	usd     float64 // 0 after a failed lookup
+	expires time.Time
+}
+
+var marketCapCache = struct {
+	sync.Mutex
+	entries map[string]marketCapEntry
+}{entries: make(map[string]marketCapEntry)}
+
+// marketCapFetches lets concurrent lookups of one coin share a single fetch;
+// the cache lock is never held while fetching
+var marketCapFetches singleflight.Group
+
+// fetchMarketCap market cap source, replaced in tests
+var fetchMarketCap = fetchCoinankMarketCap
+
+// MarketCapUSD returns the market cap of a crypto symbol's base coin, cached
+// for hours. ok is false for non-crypto assets and when no market cap source
+// is configured (COINANK_API_KEY) or the lookup failed.
+func MarketCapUSD(symbol, baseCoin string) (float64, bool) {
+	if IsXyzDexAsset(symbol) || baseCoin == "" {
+		return 0, false
+	}
+
+	marketCapCache.Lock()
+	entry, ok := marketCapCache.entries[baseCoin]
+	marketCapCache.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.usd, entry.usd > 0
+	}
+
+	v, _, _ := marketCapFetches.Do(baseCoin, func() (interface{}, error) {
+		usd, err := fetchMarketCap(baseCoin)
+		if err != nil {
+			logger.Warnf("⚠️  Market cap lookup failed for %s: %v", baseCoin, err)
+		}
+		entry := marketCapEntry{usd: usd, expires: time.Now().Add(marketCapCacheTTL)}
+		if err != nil || usd <= 0 {
+			entry = marketCapEntry{expires: time.Now().Add(marketCapRetryAfter)}
+		}
+		marketCapCache.Lock()
+		marketCapCache.entries[baseCoin] = entry
+		marketCapCache.Unlock()
+		return entry.usd, nil
+	})
+	usd := v.(float64)
+	return usd, usd > 0
+}
+
+func fetchCoinankMarketCap(baseCoin string) (float64, error) {
+	cfg := appconfig.Get()
+	if cfg == nil || cfg.CoinankAPIKey == "" {
+		return 0, nil // no source configured
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), marketCapTimeout)
+	defer cancel()
+	resp, err := coinank.NewCoinankClient(coinank_enum.MainUrl, cfg.CoinankAPIKey).GetCoinMarketCap(ctx, baseCoin)
+	if err != nil {
+		return 0, err
+	}
+	return resp.MarketCap, nil
+}
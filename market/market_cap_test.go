@@ -0,0 +1,58 @@
+package market
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMarketCapUSDFetchesOutsideTheCacheLock(t *testing.T) {
+	orig := fetchMarketCap
+	defer func() { fetchMarketCap = orig }()
+	marketCapCache.Lock()
+	marketCapCache.entries = make(map[string]marketCapEntry)
+	marketCapCache.Unlock()
+
+	release := make(chan struct{})
+	var slowFetches int32
+	fetchMarketCap = func(baseCoin string) (float64, error) {
+		if baseCoin == "SLOW" {
+			atomic.AddInt32(&slowFetches, 1)
+			<-release
+			return 5e9, nil
+		}
+		return 1e9, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if usd, ok := MarketCapUSD("SLOWUSDT", "SLOW"); !ok || usd != 5e9 {
+				t.Errorf("SLOW = %v, %v", usd, ok)
+			}
+		}()
+	}
+
+	// Another coin is answered while the slow fetch is in flight
+	done := make(chan struct{})
+	go func() {
+		if usd, ok := MarketCapUSD("FASTUSDT", "FAST"); !ok || usd != 1e9 {
+			t.Errorf("FAST = %v, %v", usd, ok)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("a slow lookup blocked another coin")
+	}
+
+	close(release)
+	wg.Wait()
+	if n := atomic.LoadInt32(&slowFetches); n != 1 {
+		t.Fatalf("SLOW fetched %d times, want one shared fetch", n)
+	}
+}
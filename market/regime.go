@@ -0,0 +1,56 @@
+package market
+
+import "sort"
+
+// ClassifyRegimeLevel determines the ranging regime level from volatility.
+// bollingerWidth: Bollinger band width as percentage of the middle band
+// atr14Pct: ATR14 as percentage of current price
+func ClassifyRegimeLevel(bollingerWidth, atr14Pct float64) RegimeLevel {
+	// Narrow: Bollinger < 2%, ATR < 1%
+	if bollingerWidth < 2.0 && atr14Pct < 1.0 {
+		return RegimeLevelNarrow
+	}
+
+	// Standard: Bollinger 2-3%, ATR 1-2%
+	if bollingerWidth <= 3.0 && atr14Pct <= 2.0 {
+		return RegimeLevelStandard
+	}
+
+	// Wide: Bollinger 3-4%, ATR 2-3%
+	if bollingerWidth <= 4.0 && atr14Pct <= 3.0 {
+		return RegimeLevelWide
+	}
+
+	// Volatile: Bollinger > 4%, ATR > 3%
+	return RegimeLevelVolatile
+}
+
+// VolatilityIndicators returns the Bollinger width and ATR14 of a symbol as
+// percentages from the preferred timeframe, else the 5m series grids use,
+// else the first timeframe (by name) with Bollinger bands. ok is false when
+// no series has them.
+func VolatilityIndicators(data *Data, preferred string) (bollingerWidth, atr14Pct float64, series *TimeframeSeriesData, ok bool) {
+	if data == nil || len(data.TimeframeData) == 0 || data.CurrentPrice <= 0 {
+		return 0, 0, nil, false
+	}
+	names := make([]string, 0, len(data.TimeframeData))
+	for tf := range data.TimeframeData {
+		names = append(names, tf)
+	}
+	sort.Strings(names)
+	for _, tf := range append([]string{preferred, "5m"}, names...) {
+		if s, found := data.TimeframeData[tf]; found && len(s.BOLLMiddle) > 0 {
+			series = s
+			break
+		}
+	}
+	if series == nil {
+		return 0, 0, nil, false
+	}
+	n := len(series.BOLLMiddle)
+	if series.BOLLMiddle[n-1] > 0 && len(series.BOLLUpper) == n && len(series.BOLLLower) == n {
+		bollingerWidth = (series.BOLLUpper[n-1] - series.BOLLLower[n-1]) / series.BOLLMiddle[n-1] * 100
+	}
+	atr14Pct = series.ATR14 / data.CurrentPrice * 100
+	return bollingerWidth, atr14Pct, series, true
+}
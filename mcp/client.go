@@ -10,6 +10,8 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"nofx/tracing"
 )
 
 const (
@@ -49,9 +51,11 @@ var (
 type TokenUsage struct {
 	Provider         string // payment channel: "claw402" or native provider name
 	Model            string
-	PromptTokens     int
+	PromptTokens     int // all input tokens, including cached and cache-write tokens
 	CompletionTokens int
 	TotalTokens      int
+	CachedTokens     int // input tokens read from the provider's prompt cache
+	CacheWriteTokens int // input tokens written to the prompt cache (Anthropic)
 }
 
 // Channel returns the payment channel category for telemetry.
@@ -83,10 +87,11 @@ type Client struct {
 	// Zero when the last call carried no settlement information.
 	LastCallSettledUSD float64
 
-	// LastCallUsage is the token usage of the most recent streamed call.
-	// On SSE responses the gateway cannot deliver the settlement header
-	// (headers are flushed before usage is known), so callers derive the
-	// actual cost from this instead. Nil when the stream carried no usage.
+	// LastCallUsage is the token usage of the most recent call, set by
+	// RecordUsage. On SSE responses the gateway cannot deliver the
+	// settlement header (headers are flushed before usage is known), so
+	// callers derive the actual cost from this instead. Nil when the
+	// response carried no usage.
 	LastCallUsage *TokenUsage
 
 	// Hooks are used to implement dynamic dispatch (polymorphism)
@@ -308,11 +313,7 @@ func (client *Client) ParseMCPResponseFull(body []byte) (*LLMResponse, error) {
 				ToolCalls        []ToolCall `json:"tool_calls"`
 			} `json:"message"`
 		} `json:"choices"`
-		Usage struct {
-			PromptTokens     int `json:"prompt_tokens"`
-			CompletionTokens int `json:"completion_tokens"`
-			TotalTokens      int `json:"total_tokens"`
-		} `json:"usage"`
+		Usage *openAIUsage `json:"usage"`
 	}
 
 	if err := json.Unmarshal(body, &result); err != nil {
@@ -323,16 +324,7 @@ func (client *Client) ParseMCPResponseFull(body []byte) (*LLMResponse, error) {
 		return nil, fmt.Errorf("API returned empty response")
 	}
 
-	// Report token usage if callback is set
-	if TokenUsageCallback != nil && result.Usage.TotalTokens > 0 {
-		TokenUsageCallback(TokenUsage{
-			Provider:         client.Provider,
-			Model:            client.Model,
-			PromptTokens:     result.Usage.PromptTokens,
-			CompletionTokens: result.Usage.CompletionTokens,
-			TotalTokens:      result.Usage.TotalTokens,
-		})
-	}
+	client.RecordUsage(result.Usage.tokenUsage())
 
 	msg := result.Choices[0].Message
 	return &LLMResponse{
@@ -405,6 +397,8 @@ func (client *Client) Call(systemPrompt, userPrompt string) (string, error) {
 		client.Log.Debugf("[%s]   API Key: %s...%s", client.String(), client.APIKey[:4], client.APIKey[len(client.APIKey)-4:])
 	}
 
+	client.LastCallUsage = nil
+
 	// Step 1: Build request body (via hooks for dynamic dispatch)
 	requestBody := client.Hooks.BuildMCPRequestBody(systemPrompt, userPrompt)
 
@@ -456,6 +450,17 @@ func (client *Client) String() string {
 		client.Provider, client.Model)
 }
 
+// SupportsStructuredOutput reports whether the provider's API can constrain a
+// reply to a JSON schema (Request.ResponseFormat). Gateways and other
+// OpenAI-compatible providers are not assumed to honour response_format.
+func (client *Client) SupportsStructuredOutput() bool {
+	switch client.Provider {
+	case ProviderOpenAI, ProviderGemini, ProviderClaude:
+		return true
+	}
+	return false
+}
+
 // BaseClient returns the underlying *Client (satisfies ClientEmbedder interface).
 func (c *Client) BaseClient() *Client { return c }
 
@@ -485,7 +490,14 @@ func (client *Client) CallWithRequest(req *Request) (string, error) {
 	if req.Model == "" {
 		req.Model = client.Model
 	}
+	req, span := client.startRequestSpan(req)
+	result, err := client.callWithRequestRetries(req)
+	tracing.End(span, err)
+	return result, err
+}
 
+// callWithRequestRetries the retry flow of CallWithRequest
+func (client *Client) callWithRequestRetries(req *Request) (string, error) {
 	// Fixed retry flow
 	var lastErr error
 	maxRetries := client.Cfg.MaxRetries
@@ -496,7 +508,9 @@ func (client *Client) CallWithRequest(req *Request) (string, error) {
 		}
 
 		// Call single request
-		result, err := client.callWithRequest(req)
+		attemptReq, span := startAttemptSpan(req, attempt)
+		result, err := client.callWithRequest(attemptReq)
+		tracing.End(span, err)
 		if err == nil {
 			if attempt > 1 {
 				client.Log.Infof("✓ AI API retry succeeded")
@@ -531,14 +545,23 @@ func (client *Client) CallWithRequestFull(req *Request) (*LLMResponse, error) {
 	if req.Model == "" {
 		req.Model = client.Model
 	}
+	req, span := client.startRequestSpan(req)
+	result, err := client.callWithRequestFullRetries(req)
+	tracing.End(span, err)
+	return result, err
+}
 
+// callWithRequestFullRetries the retry flow of CallWithRequestFull
+func (client *Client) callWithRequestFullRetries(req *Request) (*LLMResponse, error) {
 	var lastErr error
 	maxRetries := client.Cfg.MaxRetries
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		if attempt > 1 {
 			client.Log.Warnf("⚠️  AI API call failed, retrying (%d/%d)...", attempt, maxRetries)
 		}
-		result, err := client.callWithRequestFull(req)
+		attemptReq, span := startAttemptSpan(req, attempt)
+		result, err := client.callWithRequestFull(attemptReq)
+		tracing.End(span, err)
 		if err == nil {
 			return result, nil
 		}
@@ -559,6 +582,7 @@ func (client *Client) CallWithRequestFull(req *Request) (*LLMResponse, error) {
 // callWithRequestFull single call that returns LLMResponse (content + tool calls).
 func (client *Client) callWithRequestFull(req *Request) (*LLMResponse, error) {
 	client.Log.Infof("📡 [%s] Request AI Server (full): BaseURL: %s", client.String(), client.BaseURL)
+	client.LastCallUsage = nil
 
 	requestBody := client.Hooks.BuildRequestBodyFromRequest(req)
 	jsonData, err := client.Hooks.MarshalRequestBody(requestBody)
@@ -594,6 +618,7 @@ func (client *Client) callWithRequest(req *Request) (string, error) {
 	// Print current AI configuration
 	client.Log.Infof("📡 [%s] Request AI Server with Builder: BaseURL: %s", client.String(), client.BaseURL)
 	client.Log.Debugf("[%s] Messages count: %d", client.String(), len(req.Messages))
+	client.LastCallUsage = nil
 
 	requestBody := client.Hooks.BuildRequestBodyFromRequest(req)
 
@@ -637,8 +662,11 @@ func (client *Client) callWithRequest(req *Request) (string, error) {
 func (client *Client) BuildRequestBodyFromRequest(req *Request) map[string]any {
 	// Convert Message to API format — must use map[string]any to support
 	// tool-call messages (tool_calls, tool_call_id fields).
-	messages := make([]map[string]any, 0, len(req.Messages))
-	for _, msg := range req.Messages {
+	// System messages are merged prefix first so a cacheable prefix stays
+	// the byte-identical start of the prompt (automatic prefix caching).
+	reqMessages := mergeSystemMessages(req.Messages)
+	messages := make([]map[string]any, 0, len(reqMessages))
+	for _, msg := range reqMessages {
 		m := map[string]any{"role": msg.Role}
 		if len(msg.ToolCalls) > 0 {
 			// Assistant message that contains tool invocations.
@@ -725,10 +753,31 @@ func (client *Client) BuildRequestBodyFromRequest(req *Request) map[string]any {
 		requestBody["tool_choice"] = req.ToolChoice
 	}
 
+	if req.ResponseFormat != nil && client.SupportsStructuredOutput() {
+		// Non-strict: strict mode would require every optional Decision field
+		// to be listed as required and nullable.
+		requestBody["response_format"] = map[string]any{
+			"type": "json_schema",
+			"json_schema": map[string]any{
+				"name":        req.ResponseFormat.Name,
+				"description": req.ResponseFormat.Description,
+				"schema":      req.ResponseFormat.Schema,
+				"strict":      false,
+			},
+		}
+	}
+
 	if req.Stream {
 		requestBody["stream"] = true
 	}
 
+	// prompt_cache_key routes calls sharing a prefix to the same cache
+	if client.Provider == ProviderOpenAI {
+		if prefix, _ := CachedSystemPrefix(req); prefix != "" {
+			requestBody["prompt_cache_key"] = PromptCacheKey(prefix)
+		}
+	}
+
 	return requestBody
 }
 
@@ -742,6 +791,7 @@ func (client *Client) CallWithRequestStream(req *Request, onChunk func(string))
 	if client.APIKey == "" {
 		return "", fmt.Errorf("AI API key not set")
 	}
+	client.LastCallUsage = nil
 	if req.Model == "" {
 		req.Model = client.Model
 	}
@@ -806,7 +856,7 @@ func (client *Client) CallWithRequestStream(req *Request, onChunk func(string))
 		default:
 		}
 	})
-	ReportStreamUsage(usage, client.Provider, client.Model)
+	client.RecordUsage(usage)
 	return text, err
 }
 
@@ -841,22 +891,14 @@ func ParseSSEStream(body io.Reader, onChunk func(string), onLine func()) (string
 				} `json:"delta"`
 				FinishReason *string `json:"finish_reason"`
 			} `json:"choices"`
-			Usage *struct {
-				PromptTokens     int `json:"prompt_tokens"`
-				CompletionTokens int `json:"completion_tokens"`
-				TotalTokens      int `json:"total_tokens"`
-			} `json:"usage,omitempty"`
+			Usage *openAIUsage `json:"usage,omitempty"`
 		}
 		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
 			continue // skip malformed chunks
 		}
 
-		if chunk.Usage != nil && chunk.Usage.TotalTokens > 0 {
-			usage = &TokenUsage{
-				PromptTokens:     chunk.Usage.PromptTokens,
-				CompletionTokens: chunk.Usage.CompletionTokens,
-				TotalTokens:      chunk.Usage.TotalTokens,
-			}
+		if u := chunk.Usage.tokenUsage(); u != nil {
+			usage = u
 		}
 
 		if len(chunk.Choices) == 0 {
@@ -893,5 +935,7 @@ func ReportStreamUsage(usage *TokenUsage, provider, model string) {
 		PromptTokens:     usage.PromptTokens,
 		CompletionTokens: usage.CompletionTokens,
 		TotalTokens:      usage.TotalTokens,
+		CachedTokens:     usage.CachedTokens,
+		CacheWriteTokens: usage.CacheWriteTokens,
 	})
 }
@@ -0,0 +1,380 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Circuit breaker defaults
+const (
+	DefaultBreakerThreshold = 3               // consecutive outage errors that open the breaker
+	DefaultBreakerCooldown  = 5 * time.Minute // how long an open breaker skips its provider
+)
+
+// Circuit breaker states
+const (
+	BreakerClosed   = "closed"    // healthy, calls go through
+	BreakerOpen     = "open"      // skipped until the cooldown elapses
+	BreakerHalfOpen = "half_open" // cooldown elapsed, the next call is a trial
+)
+
+// breakerErrors are the failures that count towards opening a breaker:
+// provider outages, throttling and timeouts. Request errors (bad key,
+// invalid parameters) fail over but do not trip the breaker.
+var breakerErrors = []string{
+	"status 429", "status 500", "status 502", "status 503", "status 504",
+	"status 520", "status 524", "rate_limit_error", "overloaded",
+	"timeout", "deadline exceeded",
+}
+
+// IsBreakerError reports whether err is an outage-class error
+func IsBreakerError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, pattern := range breakerErrors {
+		if strings.Contains(msg, strings.ToLower(pattern)) {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaRejectionMarkers are the fragments providers put in a 400/422 body
+// when they reject the structured output part of a request: OpenAI and
+// compatible APIs name response_format/json_schema, Claude names the tool's
+// input_schema or tool_choice, Gemini names response_schema or function calling
+var schemaRejectionMarkers = []string{
+	"response_format",
+	"json_schema",
+	"response_schema",
+	"input_schema",
+	"tool_choice",
+	"tools",
+	"function calling",
+	"structured output",
+}
+
+// IsSchemaRejection reports whether err is the provider rejecting the
+// structured output part of a request (e.g. a model without response_format
+// support). Other 400/422 errors, such as an oversized prompt or a bad model
+// name, would fail a text retry as well.
+func IsSchemaRejection(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	if !strings.Contains(msg, "status 400") && !strings.Contains(msg, "status 422") {
+		return false
+	}
+	for _, marker := range schemaRejectionMarkers {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// FailoverMember one client in a failover chain
+type FailoverMember struct {
+	Name   string   // label shown in status, e.g. the AI model config ID
+	Client AIClient // configured client
+}
+
+// BreakerStatus circuit breaker state of one chain member
+type BreakerStatus struct {
+	Name                string     `json:"name"`
+	Provider            string     `json:"provider"`
+	Model               string     `json:"model"`
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+}
+
+type failoverMember struct {
+	FailoverMember
+	failures int
+	openedAt time.Time
+	lastErr  string
+}
+
+// FailoverClient is an AIClient that tries an ordered chain of clients with
+// the same request. Each member has a circuit breaker: after Threshold
+// consecutive outage errors it is skipped for Cooldown, then given one trial
+// call. When every breaker is open the first member is tried anyway so a
+// recovered primary is noticed.
+type FailoverClient struct {
+	Threshold int
+	Cooldown  time.Duration
+
+	mu       sync.Mutex
+	members  []*failoverMember
+	answered int // index of the member that answered the last call
+	log      Logger
+}
+
+// NewFailoverClient creates a failover chain; members[0] is the primary
+func NewFailoverClient(members []FailoverMember, log Logger) *FailoverClient {
+	if log == nil {
+		log = NewNoopLogger()
+	}
+	fc := &FailoverClient{
+		Threshold: DefaultBreakerThreshold,
+		Cooldown:  DefaultBreakerCooldown,
+		log:       log,
+	}
+	for _, m := range members {
+		if m.Client != nil {
+			fc.members = append(fc.members, &failoverMember{FailoverMember: m})
+		}
+	}
+	return fc
+}
+
+// order returns member indexes to try: available members in chain order,
+// or the primary alone when every breaker is open
+func (fc *FailoverClient) order() []int {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	now := time.Now()
+	var order []int
+	for i, m := range fc.members {
+		if m.failures < fc.Threshold || now.Sub(m.openedAt) >= fc.Cooldown {
+			order = append(order, i)
+		}
+	}
+	if len(order) == 0 && len(fc.members) > 0 {
+		order = []int{0}
+	}
+	return order
+}
+
+func (fc *FailoverClient) recordResult(i int, err error) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	m := fc.members[i]
+	if err == nil {
+		if m.failures >= fc.Threshold {
+			fc.log.Infof("✅ [Failover] %s recovered, closing circuit breaker", m.Name)
+		}
+		m.failures = 0
+		m.lastErr = ""
+		fc.answered = i
+		return
+	}
+	m.lastErr = err.Error()
+	if !IsBreakerError(err) {
+		// A request error still means the provider answered, so a half-open
+		// trial ends here and closes the breaker instead of staying half-open
+		if m.failures >= fc.Threshold {
+			fc.log.Infof("✅ [Failover] %s answered its trial call (%v), closing circuit breaker", m.Name, err)
+			m.failures = 0
+		}
+		return
+	}
+	m.failures++
+	if m.failures >= fc.Threshold {
+		// (Re)open: a failed half-open trial restarts the cooldown
+		m.openedAt = time.Now()
+		fc.log.Warnf("⚡ [Failover] %s failed %d times in a row, circuit open for %v", m.Name, m.failures, fc.Cooldown)
+	}
+}
+
+// call runs fn against each available member until one succeeds. A
+// structured request stops at the first schema rejection and returns it, so
+// the caller can retry as text on the same member instead of the next one
+// receiving a response format it may reject too.
+func (fc *FailoverClient) call(req *Request, fn func(AIClient) error) error {
+	if len(fc.members) == 0 {
+		return fmt.Errorf("no AI client configured")
+	}
+	var errs []error
+	for n, i := range fc.order() {
+		m := fc.members[i]
+		if n > 0 {
+			fc.log.Warnf("🔀 [Failover] Falling over to %s", m.Name)
+		}
+		err := fn(m.Client)
+		fc.recordResult(i, err)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", m.Name, err))
+		if req != nil && req.ResponseFormat != nil && IsSchemaRejection(err) {
+			break
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ── AIClient ──────────────────────────────────────────────────────────────────
+
+// SetAPIKey configures the primary client
+func (fc *FailoverClient) SetAPIKey(apiKey string, customURL string, customModel string) {
+	if len(fc.members) > 0 {
+		fc.members[0].Client.SetAPIKey(apiKey, customURL, customModel)
+	}
+}
+
+// SetTimeout applies the timeout to every member
+func (fc *FailoverClient) SetTimeout(timeout time.Duration) {
+	for _, m := range fc.members {
+		m.Client.SetTimeout(timeout)
+	}
+}
+
+func (fc *FailoverClient) CallWithMessages(systemPrompt, userPrompt string) (string, error) {
+	var result string
+	err := fc.call(nil, func(c AIClient) error {
+		var err error
+		result, err = c.CallWithMessages(systemPrompt, userPrompt)
+		return err
+	})
+	return result, err
+}
+
+func (fc *FailoverClient) CallWithRequest(req *Request) (string, error) {
+	var result string
+	err := fc.call(req, func(c AIClient) error {
+		var err error
+		result, err = c.CallWithRequest(cloneRequestForMember(req))
+		return err
+	})
+	return result, err
+}
+
+func (fc *FailoverClient) CallWithRequestStream(req *Request, onChunk func(string)) (string, error) {
+	var result string
+	err := fc.call(req, func(c AIClient) error {
+		var err error
+		result, err = c.CallWithRequestStream(cloneRequestForMember(req), onChunk)
+		return err
+	})
+	return result, err
+}
+
+func (fc *FailoverClient) CallWithRequestFull(req *Request) (*LLMResponse, error) {
+	var result *LLMResponse
+	err := fc.call(req, func(c AIClient) error {
+		var err error
+		result, err = c.CallWithRequestFull(cloneRequestForMember(req))
+		return err
+	})
+	return result, err
+}
+
+// cloneRequestForMember copies a request without its model so each member
+// uses its own configured model
+func cloneRequestForMember(req *Request) *Request {
+	if req == nil {
+		return nil
+	}
+	clone := *req
+	clone.Model = ""
+	return &clone
+}
+
+// ── Introspection ─────────────────────────────────────────────────────────────
+
+// BaseClient returns the base client of the member expected to answer the
+// next call (satisfies ClientEmbedder), so context limits and structured
+// output support follow the active provider
+func (fc *FailoverClient) BaseClient() *Client {
+	for _, i := range fc.order() {
+		if embedder, ok := fc.members[i].Client.(ClientEmbedder); ok {
+			return embedder.BaseClient()
+		}
+	}
+	return nil
+}
+
+// SetLogger replaces the logger used for failover messages; call it from
+// the goroutine that makes the calls, between calls
+func (fc *FailoverClient) SetLogger(log Logger) {
+	if log != nil {
+		fc.log = log
+	}
+}
+
+// Members returns the chain members in order
+func (fc *FailoverClient) Members() []FailoverMember {
+	members := make([]FailoverMember, len(fc.members))
+	for i, m := range fc.members {
+		members[i] = m.FailoverMember
+	}
+	return members
+}
+
+// LastAnswered returns the chain member that answered the most recent call
+// and its provider and model
+func (fc *FailoverClient) LastAnswered() (name, provider, model string) {
+	fc.mu.Lock()
+	m := fc.members[fc.answered]
+	fc.mu.Unlock()
+	provider, model = memberModel(m.Client)
+	return m.Name, provider, model
+}
+
+// LastCallCostUSD forwards the settled cost of the answering member, when it reports one
+func (fc *FailoverClient) LastCallCostUSD() (float64, bool) {
+	fc.mu.Lock()
+	m := fc.members[fc.answered]
+	fc.mu.Unlock()
+	if r, ok := m.Client.(interface{ LastCallCostUSD() (float64, bool) }); ok {
+		return r.LastCallCostUSD()
+	}
+	return 0, false
+}
+
+// LastCallTokenUsage forwards the token usage of the answering member
+func (fc *FailoverClient) LastCallTokenUsage() *TokenUsage {
+	fc.mu.Lock()
+	m := fc.members[fc.answered]
+	fc.mu.Unlock()
+	if r, ok := m.Client.(interface{ LastCallTokenUsage() *TokenUsage }); ok {
+		return r.LastCallTokenUsage()
+	}
+	return nil
+}
+
+// BreakerStatus reports the circuit breaker state of every member, in chain order
+func (fc *FailoverClient) BreakerStatus() []BreakerStatus {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	now := time.Now()
+	statuses := make([]BreakerStatus, 0, len(fc.members))
+	for _, m := range fc.members {
+		provider, model := memberModel(m.Client)
+		status := BreakerStatus{
+			Name:                m.Name,
+			Provider:            provider,
+			Model:               model,
+			State:               BreakerClosed,
+			ConsecutiveFailures: m.failures,
+			LastError:           m.lastErr,
+		}
+		if m.failures >= fc.Threshold {
+			opened := m.openedAt
+			status.OpenedAt = &opened
+			status.State = BreakerOpen
+			if now.Sub(m.openedAt) >= fc.Cooldown {
+				status.State = BreakerHalfOpen
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+func memberModel(c AIClient) (provider, model string) {
+	if embedder, ok := c.(ClientEmbedder); ok {
+		base := embedder.BaseClient()
+		return base.Provider, base.Model
+	}
+	return "", ""
+}
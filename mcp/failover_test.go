@@ -0,0 +1,177 @@
+package mcp
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// stubFailoverClient an AIClient that returns queued errors, then succeeds
+type stubFailoverClient struct {
+	*Client
+	errs  []error
+	calls int
+}
+
+func newStubFailoverClient(provider, model string, errs ...error) *stubFailoverClient {
+	return &stubFailoverClient{Client: &Client{Provider: provider, Model: model}, errs: errs}
+}
+
+func (s *stubFailoverClient) BaseClient() *Client { return s.Client }
+
+func (s *stubFailoverClient) CallWithMessages(systemPrompt, userPrompt string) (string, error) {
+	s.calls++
+	if len(s.errs) > 0 {
+		err := s.errs[0]
+		s.errs = s.errs[1:]
+		if err != nil {
+			return "", err
+		}
+	}
+	return s.Provider + " reply", nil
+}
+
+var errUnavailable = errors.New("API returned error (status 503): service unavailable")
+
+func TestFailoverClient_FallsOverAndRecordsAnsweredModel(t *testing.T) {
+	primary := newStubFailoverClient("deepseek", "deepseek-chat", errUnavailable)
+	secondary := newStubFailoverClient("openai", "gpt-4o")
+	fc := NewFailoverClient([]FailoverMember{
+		{Name: "primary", Client: primary},
+		{Name: "backup", Client: secondary},
+	}, NewMockLogger())
+
+	reply, err := fc.CallWithMessages("sys", "user")
+	if err != nil {
+		t.Fatalf("CallWithMessages: %v", err)
+	}
+	if reply != "openai reply" {
+		t.Errorf("reply = %q, want the secondary's", reply)
+	}
+	name, provider, model := fc.LastAnswered()
+	if name != "backup" || provider != "openai" || model != "gpt-4o" {
+		t.Errorf("LastAnswered = %s %s %s", name, provider, model)
+	}
+
+	// Primary recovers: it is tried first again
+	if _, err := fc.CallWithMessages("sys", "user"); err != nil {
+		t.Fatalf("CallWithMessages: %v", err)
+	}
+	if name, _, _ := fc.LastAnswered(); name != "primary" {
+		t.Errorf("LastAnswered = %s, want primary", name)
+	}
+}
+
+func TestFailoverClient_BreakerOpensAndHalfOpens(t *testing.T) {
+	primary := newStubFailoverClient("deepseek", "deepseek-chat", errUnavailable, errUnavailable, errUnavailable)
+	secondary := newStubFailoverClient("openai", "gpt-4o")
+	fc := NewFailoverClient([]FailoverMember{
+		{Name: "primary", Client: primary},
+		{Name: "backup", Client: secondary},
+	}, NewMockLogger())
+
+	for i := 0; i < 3; i++ {
+		if _, err := fc.CallWithMessages("sys", "user"); err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+	}
+	if got := fc.BreakerStatus()[0].State; got != BreakerOpen {
+		t.Fatalf("primary breaker = %s, want open", got)
+	}
+
+	// Open breaker: the primary is skipped
+	if _, err := fc.CallWithMessages("sys", "user"); err != nil {
+		t.Fatal(err)
+	}
+	if primary.calls != 3 {
+		t.Errorf("primary called %d times while open, want 3", primary.calls)
+	}
+
+	// Cooldown elapsed: half-open trial succeeds and closes the breaker
+	fc.Cooldown = 0
+	if got := fc.BreakerStatus()[0].State; got != BreakerHalfOpen {
+		t.Errorf("primary breaker = %s, want half_open", got)
+	}
+	if _, err := fc.CallWithMessages("sys", "user"); err != nil {
+		t.Fatal(err)
+	}
+	status := fc.BreakerStatus()[0]
+	if status.State != BreakerClosed || status.ConsecutiveFailures != 0 {
+		t.Errorf("primary breaker after recovery = %+v", status)
+	}
+}
+
+func TestFailoverClient_RequestErrorsDoNotTrip(t *testing.T) {
+	badKey := errors.New("API returned error (status 401): invalid api key")
+	primary := newStubFailoverClient("deepseek", "deepseek-chat", badKey, badKey, badKey, badKey)
+	fc := NewFailoverClient([]FailoverMember{
+		{Name: "primary", Client: primary},
+		{Name: "backup", Client: newStubFailoverClient("openai", "gpt-4o")},
+	}, NewMockLogger())
+	fc.Cooldown = time.Hour
+
+	for i := 0; i < 4; i++ {
+		if _, err := fc.CallWithMessages("sys", "user"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if status := fc.BreakerStatus()[0]; status.State != BreakerClosed || status.LastError == "" {
+		t.Errorf("primary breaker = %+v, want closed with last error", status)
+	}
+}
+
+func TestFailoverClient_HalfOpenTrialResolvesOnRequestError(t *testing.T) {
+	badRequest := errors.New("API returned error (status 400): invalid parameter")
+	primary := newStubFailoverClient("deepseek", "deepseek-chat", errUnavailable, errUnavailable, errUnavailable, badRequest)
+	fc := NewFailoverClient([]FailoverMember{
+		{Name: "primary", Client: primary},
+		{Name: "backup", Client: newStubFailoverClient("openai", "gpt-4o")},
+	}, NewMockLogger())
+
+	for i := 0; i < 3; i++ {
+		if _, err := fc.CallWithMessages("sys", "user"); err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+	}
+
+	// Half-open trial fails with a request error: the breaker closes
+	fc.Cooldown = 0
+	if _, err := fc.CallWithMessages("sys", "user"); err != nil {
+		t.Fatal(err)
+	}
+	fc.Cooldown = time.Hour
+	status := fc.BreakerStatus()[0]
+	if status.State != BreakerClosed || status.ConsecutiveFailures != 0 || status.LastError == "" {
+		t.Errorf("primary breaker after request error trial = %+v, want closed with last error", status)
+	}
+}
+
+func TestFailoverClient_AllFail(t *testing.T) {
+	fc := NewFailoverClient([]FailoverMember{
+		{Name: "primary", Client: newStubFailoverClient("deepseek", "", errUnavailable)},
+		{Name: "backup", Client: newStubFailoverClient("openai", "", errors.New("context deadline exceeded"))},
+	}, NewMockLogger())
+
+	if _, err := fc.CallWithMessages("sys", "user"); err == nil {
+		t.Fatal("expected an error when every member fails")
+	}
+}
+
+func TestIsSchemaRejection(t *testing.T) {
+	cases := []struct {
+		msg  string
+		want bool
+	}{
+		{"API returned error (status 400): Invalid parameter: 'response_format' of type 'json_schema' is not supported with this model.", true},
+		{`API returned error (status 400): {"type":"error","error":{"type":"invalid_request_error","message":"tools.0.input_schema: JSON schema is invalid"}}`, true},
+		{"API returned error (status 422): Function calling is not enabled for this model", true},
+		{"API returned error (status 400): This model's maximum context length is 128000 tokens", false},
+		{"API returned error (status 400): The model `gpt-5-turbo` does not exist", false},
+		{"API returned error (status 500): response_format handler crashed", false},
+	}
+	for _, c := range cases {
+		if got := IsSchemaRejection(errors.New(c.msg)); got != c.want {
+			t.Errorf("IsSchemaRejection(%q) = %v, want %v", c.msg, got, c.want)
+		}
+	}
+}
@@ -17,9 +17,12 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/crypto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/crypto/sha3"
 
 	"nofx/mcp"
+	"nofx/tracing"
 )
 
 const (
@@ -231,6 +234,8 @@ func captureSettledUSD(c *mcp.Client, h http.Header) {
 
 // DoX402Request executes an HTTP request and handles the x402 v2 payment flow.
 // An optional headerSink receives the headers of the successful response.
+// The whole negotiation is one "x402.request" span; the 402 challenge and
+// each signed payment attempt are recorded as span events.
 func DoX402Request(
 	ctx context.Context,
 	httpClient *http.Client,
@@ -240,9 +245,22 @@ func DoX402Request(
 	logger mcp.Logger,
 	headerSink ...*http.Header,
 ) ([]byte, error) {
-	if ctx == nil {
-		ctx = context.Background()
-	}
+	ctx, span := tracing.Start(ctx, "x402.request", attribute.String("ai.provider", providerTag))
+	body, err := doX402Request(ctx, httpClient, buildReqFn, signFn, providerTag, logger, headerSink...)
+	tracing.End(span, err)
+	return body, err
+}
+
+func doX402Request(
+	ctx context.Context,
+	httpClient *http.Client,
+	buildReqFn func() (*http.Request, error),
+	signFn X402SignFunc,
+	providerTag string,
+	logger mcp.Logger,
+	headerSink ...*http.Header,
+) ([]byte, error) {
+	span := trace.SpanFromContext(ctx)
 
 	resp, err := doInitialX402Request(ctx, httpClient, buildReqFn, providerTag, logger)
 	if err != nil {
@@ -262,6 +280,7 @@ func DoX402Request(
 
 		// Drain 402 body to allow HTTP connection reuse.
 		_, _ = io.Copy(io.Discard, resp.Body)
+		span.AddEvent("x402.payment_required")
 
 		paymentSig, err := signFn(paymentHeader)
 		if err != nil {
@@ -282,6 +301,8 @@ func DoX402Request(
 
 			resp2, err := httpClient.Do(req2)
 			if err != nil {
+				span.AddEvent("x402.payment_attempt", trace.WithAttributes(
+					attribute.Int("attempt", attempt), attribute.String("error", err.Error())))
 				if attempt < X402MaxPaymentRetries {
 					wait := X402RetryBaseWait * time.Duration(attempt)
 					logger.Warnf("⚠️  [%s] Payment request failed: %v, retrying in %v (%d/%d)...",
@@ -299,6 +320,8 @@ func DoX402Request(
 			if readErr != nil {
 				return nil, fmt.Errorf("failed to read payment retry response: %w", readErr)
 			}
+			span.AddEvent("x402.payment_attempt", trace.WithAttributes(
+				attribute.Int("attempt", attempt), attribute.Int("http.status_code", resp2.StatusCode)))
 
 			if resp2.StatusCode == http.StatusOK {
 				if txHash := resp2.Header.Get("Payment-Response"); txHash != "" {
@@ -578,8 +601,7 @@ func X402CallStream(c *mcp.Client, signFn X402SignFunc, tag string, systemPrompt
 	tee := io.TeeReader(resp.Body, &bodyBuf)
 
 	text, usage, sseErr := mcp.ParseSSEStream(tee, onChunk, onLine)
-	mcp.ReportStreamUsage(usage, c.Provider, c.Model)
-	c.LastCallUsage = usage
+	c.RecordUsage(usage)
 
 	if text != "" {
 		c.Log.Infof("📡 [%s] SSE stream complete, got %d chars", tag, len(text))
@@ -627,6 +649,7 @@ func X402Call(c *mcp.Client, signFn X402SignFunc, tag string, systemPrompt, user
 	}
 
 	c.LastCallSettledUSD = 0
+	c.LastCallUsage = nil
 	var respHeader http.Header
 	body, err := DoX402Request(context.Background(), c.HTTPClient, func() (*http.Request, error) {
 		return c.Hooks.BuildRequest(c.Hooks.BuildUrl(), jsonData)
@@ -656,6 +679,7 @@ func X402CallFull(c *mcp.Client, signFn X402SignFunc, tag string, req *mcp.Reque
 	}
 
 	c.LastCallSettledUSD = 0
+	c.LastCallUsage = nil
 	var respHeader http.Header
 	body, err := DoX402Request(x402ContextFromRequest(req), c.HTTPClient, func() (*http.Request, error) {
 		return c.Hooks.BuildRequest(c.Hooks.BuildUrl(), jsonData)
@@ -0,0 +1,93 @@
+package mcp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Prompt caching
+//
+// A request marks the end of its stable prefix by setting Cacheable on the
+// last message of the prefix, usually the static part of the system prompt
+// (see NewCachedSystemMessages). Everything up to and including that message
+// must be byte-identical between calls for a cache hit. Providers use the
+// mark as follows:
+//
+//	Anthropic:          cache_control breakpoint on the prefix system block
+//	OpenAI-compatible:  automatic prefix caching; system messages are merged
+//	                    prefix first, OpenAI also receives a prompt_cache_key
+//	Gemini:             explicit context caching of the prefix (provider.GeminiClient)
+//
+// Cache hits are reported in TokenUsage.CachedTokens.
+
+// NewCachedSystemMessages returns the system messages of a prompt split into
+// a stable prefix, marked Cacheable, and a per-call suffix (omitted when empty)
+func NewCachedSystemMessages(prefix, suffix string) []Message {
+	msgs := []Message{{Role: "system", Content: prefix, Cacheable: true}}
+	if suffix != "" {
+		msgs = append(msgs, NewSystemMessage(suffix))
+	}
+	return msgs
+}
+
+// CachedSystemPrefix returns the cacheable prefix of a request when it
+// consists of system messages only, and the index of its last message.
+// It returns "", -1 when the request has no such prefix.
+func CachedSystemPrefix(req *Request) (string, int) {
+	last := -1
+	for i, m := range req.Messages {
+		if m.Cacheable {
+			last = i
+		}
+	}
+	if last < 0 {
+		return "", -1
+	}
+	parts := make([]string, 0, last+1)
+	for _, m := range req.Messages[:last+1] {
+		if m.Role != "system" {
+			return "", -1
+		}
+		parts = append(parts, m.Content)
+	}
+	return joinPromptParts(parts), last
+}
+
+// mergeSystemMessages joins consecutive system messages into one, keeping
+// their order, for APIs that expect a single system message. The merged
+// message is Cacheable when any of its parts is.
+func mergeSystemMessages(msgs []Message) []Message {
+	merged := make([]Message, 0, len(msgs))
+	for _, m := range msgs {
+		if n := len(merged); n > 0 && m.Role == "system" && merged[n-1].Role == "system" {
+			prev := &merged[n-1]
+			prev.Content = joinPromptParts([]string{prev.Content, m.Content})
+			prev.Cacheable = prev.Cacheable || m.Cacheable
+			continue
+		}
+		merged = append(merged, m)
+	}
+	return merged
+}
+
+func joinPromptParts(parts []string) string {
+	var sb strings.Builder
+	for i, p := range parts {
+		if i > 0 {
+			sb.WriteString("\n\n")
+		}
+		if i < len(parts)-1 {
+			p = strings.TrimRight(p, "\n")
+		}
+		sb.WriteString(p)
+	}
+	return sb.String()
+}
+
+// PromptCacheKey a short stable key of a prompt prefix, used to route calls
+// sharing the prefix to the same provider cache
+func PromptCacheKey(prefix string) string {
+	sum := sha256.Sum256([]byte(prefix))
+	return hex.EncodeToString(sum[:8])
+}
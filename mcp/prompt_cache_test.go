@@ -0,0 +1,43 @@
+package mcp
+
+import "testing"
+
+func TestBuildRequestBodyMergesCachedSystemPrefix(t *testing.T) {
+	req := &Request{
+		Model:    "gpt-test",
+		Messages: append(NewCachedSystemMessages("static rules\n", "equity 1000"), NewUserMessage("market data")),
+	}
+
+	openai := NewClient(WithProvider(ProviderOpenAI), WithLogger(NewNoopLogger())).(*Client)
+	body := openai.BuildRequestBodyFromRequest(req)
+	messages := body["messages"].([]map[string]any)
+	if len(messages) != 2 || messages[0]["content"] != "static rules\n\nequity 1000" {
+		t.Fatalf("system messages not merged prefix first: %v", messages)
+	}
+	if body["prompt_cache_key"] != PromptCacheKey("static rules\n") {
+		t.Errorf("prompt_cache_key = %v", body["prompt_cache_key"])
+	}
+
+	// The key follows the prefix only
+	req.Messages[1].Content = "equity 2000"
+	if openai.BuildRequestBodyFromRequest(req)["prompt_cache_key"] != body["prompt_cache_key"] {
+		t.Error("prompt_cache_key changed with the volatile suffix")
+	}
+
+	deepseek := NewClient(WithProvider(ProviderDeepSeek), WithLogger(NewNoopLogger())).(*Client)
+	if _, ok := deepseek.BuildRequestBodyFromRequest(req)["prompt_cache_key"]; ok {
+		t.Error("prompt_cache_key must only be sent to OpenAI")
+	}
+}
+
+func TestCachedSystemPrefix(t *testing.T) {
+	req := &Request{Messages: append(NewCachedSystemMessages("a", "b"), NewUserMessage("u"))}
+	if prefix, last := CachedSystemPrefix(req); prefix != "a" || last != 0 {
+		t.Errorf("prefix = %q, last = %d", prefix, last)
+	}
+
+	plain := &Request{Messages: []Message{NewSystemMessage("a"), NewUserMessage("u")}}
+	if prefix, last := CachedSystemPrefix(plain); prefix != "" || last != -1 {
+		t.Errorf("request without a cache mark: prefix = %q, last = %d", prefix, last)
+	}
+}
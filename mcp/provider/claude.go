@@ -13,6 +13,8 @@
 //	│ Assistant tool call │ tool_calls array           │ content[{type:tool_use,...}]    │
 //	│ Tool result         │ role=tool + tool_call_id   │ role=user content[tool_result]  │
 //	│ Max tokens          │ max_tokens                 │ max_tokens (same)               │
+//	│ JSON schema reply   │ response_format            │ forced tool_use (tool_choice)   │
+//	│ Prompt caching      │ automatic prefix match     │ cache_control breakpoint        │
 //	└─────────────────────┴───────────────────────────┴─────────────────────────────────┘
 package provider
 
@@ -20,6 +22,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"nofx/mcp"
 )
@@ -108,11 +111,10 @@ func (c *ClaudeClient) BuildMCPRequestBody(systemPrompt, userPrompt string) map[
 // API wire format.
 func (c *ClaudeClient) BuildRequestBodyFromRequest(req *mcp.Request) map[string]any {
 	// ── 1. Separate system prompt from conversation messages ──────────────────
-	var systemPrompt string
-	var convMsgs []mcp.Message
+	var systemMsgs, convMsgs []mcp.Message
 	for _, m := range req.Messages {
 		if m.Role == "system" {
-			systemPrompt = m.Content
+			systemMsgs = append(systemMsgs, m)
 		} else {
 			convMsgs = append(convMsgs, m)
 		}
@@ -135,10 +137,20 @@ func (c *ClaudeClient) BuildRequestBodyFromRequest(req *mcp.Request) map[string]
 	body := map[string]any{
 		"model":      req.Model,
 		"max_tokens": c.MaxTokens,
-		"system":     systemPrompt,
+		"system":     anthropicSystem(systemMsgs),
 		"messages":   anthropicMsgs,
 	}
 
+	// Structured output: Anthropic has no response_format, so the schema is
+	// offered as the only tool and the model is forced to call it.
+	if req.ResponseFormat != nil {
+		anthropicTools = append(anthropicTools, map[string]any{
+			"name":         req.ResponseFormat.Name,
+			"description":  req.ResponseFormat.Description,
+			"input_schema": req.ResponseFormat.Schema,
+		})
+	}
+
 	if len(anthropicTools) > 0 {
 		body["tools"] = anthropicTools
 	}
@@ -152,6 +164,9 @@ func (c *ClaudeClient) BuildRequestBodyFromRequest(req *mcp.Request) map[string]
 	case "none", "":
 		// omit — no tool_choice sent
 	}
+	if req.ResponseFormat != nil {
+		body["tool_choice"] = map[string]any{"type": "tool", "name": req.ResponseFormat.Name}
+	}
 
 	if req.Temperature != nil {
 		body["temperature"] = *req.Temperature
@@ -160,6 +175,40 @@ func (c *ClaudeClient) BuildRequestBodyFromRequest(req *mcp.Request) map[string]
 	return body
 }
 
+// anthropicSystem builds the top-level "system" field. Without a cacheable
+// message it is the plain joined text; otherwise it is a list of text blocks
+// with a cache_control breakpoint on the last cacheable one, which caches the
+// tools and the stable system prefix. Prefixes shorter than the model's
+// minimum cacheable length are simply not cached.
+func anthropicSystem(msgs []mcp.Message) any {
+	cacheable := -1
+	for i, m := range msgs {
+		if m.Cacheable {
+			cacheable = i
+		}
+	}
+	if cacheable < 0 {
+		parts := make([]string, 0, len(msgs))
+		for _, m := range msgs {
+			parts = append(parts, m.Content)
+		}
+		return strings.Join(parts, "\n\n")
+	}
+
+	blocks := make([]map[string]any, 0, len(msgs))
+	for i, m := range msgs {
+		if m.Content == "" {
+			continue
+		}
+		block := map[string]any{"type": "text", "text": m.Content}
+		if i == cacheable {
+			block["cache_control"] = map[string]any{"type": "ephemeral"}
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
 // ConvertMessagesToAnthropic translates from the OpenAI-shaped mcp.Message
 // slice to Anthropic's messages array.
 func ConvertMessagesToAnthropic(msgs []mcp.Message) []map[string]any {
@@ -244,8 +293,10 @@ func (c *ClaudeClient) ParseMCPResponseFull(body []byte) (*mcp.LLMResponse, erro
 			Input json.RawMessage `json:"input,omitempty"`
 		} `json:"content"`
 		Usage struct {
-			InputTokens  int `json:"input_tokens"`
-			OutputTokens int `json:"output_tokens"`
+			InputTokens              int `json:"input_tokens"`
+			OutputTokens             int `json:"output_tokens"`
+			CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+			CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
 		} `json:"usage"`
 		Error *struct {
 			Type    string `json:"type"`
@@ -260,16 +311,20 @@ func (c *ClaudeClient) ParseMCPResponseFull(body []byte) (*mcp.LLMResponse, erro
 		return nil, fmt.Errorf("Anthropic API error: %s — %s", raw.Error.Type, raw.Error.Message)
 	}
 
-	total := raw.Usage.InputTokens + raw.Usage.OutputTokens
-	if mcp.TokenUsageCallback != nil && total > 0 {
-		mcp.TokenUsageCallback(mcp.TokenUsage{
-			Provider:         c.Provider,
-			Model:            c.Model,
-			PromptTokens:     raw.Usage.InputTokens,
+	// Anthropic's input_tokens excludes cache reads and writes; normalize to
+	// the total prompt size like the OpenAI-compatible providers.
+	prompt := raw.Usage.InputTokens + raw.Usage.CacheReadInputTokens + raw.Usage.CacheCreationInputTokens
+	var usage *mcp.TokenUsage
+	if prompt+raw.Usage.OutputTokens > 0 {
+		usage = &mcp.TokenUsage{
+			PromptTokens:     prompt,
 			CompletionTokens: raw.Usage.OutputTokens,
-			TotalTokens:      total,
-		})
+			TotalTokens:      prompt + raw.Usage.OutputTokens,
+			CachedTokens:     raw.Usage.CacheReadInputTokens,
+			CacheWriteTokens: raw.Usage.CacheCreationInputTokens,
+		}
 	}
+	c.RecordUsage(usage)
 
 	result := &mcp.LLMResponse{}
 	for _, block := range raw.Content {
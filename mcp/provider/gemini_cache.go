@@ -0,0 +1,178 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"nofx/mcp"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Gemini context caching: the cacheable system prefix of a request is stored
+// once as a cachedContents resource of the native API and referenced from the
+// OpenAI-compatible call via extra_body.google.cached_content. Gemini rejects
+// a cached_content call that also sets a system instruction or tools, so the
+// rest of the system prompt moves into the first user message and requests
+// with tools are sent uncached.
+const (
+	geminiCacheTTL        = time.Hour
+	geminiCacheRenewAhead = 5 * time.Minute  // stop using an entry this long before it expires
+	geminiCacheRetryAfter = 10 * time.Minute // after a failed create, send uncached until then
+	geminiCacheMinTokens  = 4096             // minimum explicit cache size (Pro models; Flash needs 1024)
+	geminiCacheTimeout    = 15 * time.Second
+)
+
+type geminiCacheEntry struct {
+	name    string    // cachedContents/..., empty after a failed create
+	expires time.Time // entry (or failure backoff) valid until
+}
+
+// geminiContextCaches cached prefixes by endpoint, API key, model and
+// prefix, shared by all Gemini clients so reloaded traders reuse their caches
+var geminiContextCaches = struct {
+	sync.Mutex
+	entries map[string]geminiCacheEntry
+}{entries: make(map[string]geminiCacheEntry)}
+
+// geminiCacheCreates lets concurrent requests for one prefix share a single
+// create call; the cache lock is never held while creating
+var geminiCacheCreates singleflight.Group
+
+// BuildRequestBodyFromRequest references the cached system prefix when the
+// request has one large enough for explicit caching. Any caching failure
+// falls back to the plain request, which still gets implicit caching.
+func (c *GeminiClient) BuildRequestBodyFromRequest(req *mcp.Request) map[string]any {
+	prefix, last := mcp.CachedSystemPrefix(req)
+	if prefix == "" || len(req.Tools) > 0 || utf8.RuneCountInString(prefix)/3 < geminiCacheMinTokens {
+		return c.Client.BuildRequestBodyFromRequest(req)
+	}
+	name := c.cachedContent(req.Model, prefix)
+	if name == "" {
+		return c.Client.BuildRequestBodyFromRequest(req)
+	}
+
+	uncached := *req
+	uncached.Messages = foldSystemIntoUser(req.Messages[last+1:])
+	body := c.Client.BuildRequestBodyFromRequest(&uncached)
+	body["extra_body"] = map[string]any{
+		"google": map[string]any{"cached_content": name},
+	}
+	return body
+}
+
+// cachedContent returns the cachedContents name holding prefix, creating it
+// when missing or about to expire; "" when caching is unavailable
+func (c *GeminiClient) cachedContent(model, prefix string) string {
+	nativeURL, ok := strings.CutSuffix(strings.TrimRight(c.BaseURL, "/"), "/openai")
+	if !ok || model == "" || c.APIKey == "" {
+		return "" // custom endpoint without the native API
+	}
+	key := mcp.PromptCacheKey(nativeURL + "\x00" + c.APIKey + "\x00" + model + "\x00" + prefix)
+
+	geminiContextCaches.Lock()
+	entry, ok := geminiContextCaches.entries[key]
+	geminiContextCaches.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.name
+	}
+
+	v, _, _ := geminiCacheCreates.Do(key, func() (interface{}, error) {
+		name, err := c.createCachedContent(nativeURL, model, prefix)
+		entry := geminiCacheEntry{name: name, expires: time.Now().Add(geminiCacheTTL - geminiCacheRenewAhead)}
+		if err != nil {
+			c.Log.Warnf("⚠️  [MCP] Gemini context cache unavailable, sending prompt uncached for %v: %v", geminiCacheRetryAfter, err)
+			entry = geminiCacheEntry{expires: time.Now().Add(geminiCacheRetryAfter)}
+		} else {
+			c.Log.Infof("🗄️  [MCP] Gemini context cache created: %s (%s)", name, model)
+		}
+		storeGeminiCacheEntry(key, entry)
+		return entry.name, nil
+	})
+	return v.(string)
+}
+
+// storeGeminiCacheEntry saves entry and drops the expired ones, whose cached
+// contents Gemini has already deleted
+func storeGeminiCacheEntry(key string, entry geminiCacheEntry) {
+	geminiContextCaches.Lock()
+	defer geminiContextCaches.Unlock()
+	now := time.Now()
+	for k, e := range geminiContextCaches.entries {
+		if !now.Before(e.expires) {
+			delete(geminiContextCaches.entries, k)
+		}
+	}
+	geminiContextCaches.entries[key] = entry
+}
+
+func (c *GeminiClient) createCachedContent(nativeURL, model, prefix string) (string, error) {
+	payload, err := json.Marshal(map[string]any{
+		"model": "models/" + model,
+		"systemInstruction": map[string]any{
+			"parts": []map[string]string{{"text": prefix}},
+		},
+		"ttl": fmt.Sprintf("%ds", int(geminiCacheTTL.Seconds())),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), geminiCacheTimeout)
+	defer cancel()
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, nativeURL+"/cachedContents", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-goog-api-key", c.APIKey)
+
+	resp, err := c.HTTPClient.Do(httpReq)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	var created struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(body, &created); err != nil || created.Name == "" {
+		return "", fmt.Errorf("unexpected cachedContents response: %s", strings.TrimSpace(string(body)))
+	}
+	return created.Name, nil
+}
+
+// foldSystemIntoUser moves the remaining system messages into the first
+// user message
+func foldSystemIntoUser(msgs []mcp.Message) []mcp.Message {
+	var system []string
+	out := make([]mcp.Message, 0, len(msgs))
+	for _, m := range msgs {
+		if m.Role == "system" {
+			system = append(system, m.Content)
+			continue
+		}
+		out = append(out, m)
+	}
+	if len(system) == 0 {
+		return out
+	}
+	for i := range out {
+		if out[i].Role == "user" {
+			out[i].Content = strings.Join(append(system, out[i].Content), "\n\n")
+			return out
+		}
+	}
+	return append([]mcp.Message{mcp.NewUserMessage(strings.Join(system, "\n\n"))}, out...)
+}
@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"nofx/mcp"
+)
+
+func TestGeminiContextCacheReusesCachedPrefix(t *testing.T) {
+	creates := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1beta/cachedContents" || r.Header.Get("x-goog-api-key") != "test-key" {
+			t.Errorf("unexpected cache request %s", r.URL.Path)
+		}
+		var payload map[string]any
+		json.NewDecoder(r.Body).Decode(&payload)
+		if payload["model"] != "models/gemini-test" {
+			t.Errorf("cache model = %v", payload["model"])
+		}
+		creates++
+		w.Write([]byte(`{"name":"cachedContents/abc"}`))
+	}))
+	defer srv.Close()
+
+	c := NewGeminiClientWithOptions(mcp.WithLogger(mcp.NewNoopLogger()), mcp.WithHTTPClient(srv.Client()), mcp.WithBaseURL(srv.URL+"/v1beta/openai")).(*GeminiClient)
+	c.APIKey = "test-key"
+	prefix := strings.Repeat("static rule ", geminiCacheMinTokens)
+	req := &mcp.Request{
+		Model:    "gemini-test",
+		Messages: append(mcp.NewCachedSystemMessages(prefix, "equity 1000"), mcp.NewUserMessage("market data")),
+	}
+
+	for i := 0; i < 2; i++ {
+		body := c.BuildRequestBodyFromRequest(req)
+		extra, _ := body["extra_body"].(map[string]any)
+		google, _ := extra["google"].(map[string]any)
+		if google["cached_content"] != "cachedContents/abc" {
+			t.Fatalf("call %d: cached_content missing: %v", i, body["extra_body"])
+		}
+		messages := body["messages"].([]map[string]any)
+		if len(messages) != 1 || messages[0]["role"] != "user" || messages[0]["content"] != "equity 1000\n\nmarket data" {
+			t.Fatalf("call %d: messages = %v", i, messages)
+		}
+	}
+	if creates != 1 {
+		t.Errorf("cache created %d times, want 1", creates)
+	}
+
+	// Short prefixes are below the explicit cache minimum and sent inline
+	short := &mcp.Request{Model: "gemini-test", Messages: append(mcp.NewCachedSystemMessages("rules", ""), mcp.NewUserMessage("hi"))}
+	if _, ok := c.BuildRequestBodyFromRequest(short)["extra_body"]; ok {
+		t.Error("short prefix should not use context caching")
+	}
+}
+
+func TestGeminiContextCacheCreatesOutsideTheLock(t *testing.T) {
+	release := make(chan struct{})
+	var creates atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		creates.Add(1)
+		<-release
+		w.Write([]byte(`{"name":"cachedContents/slow"}`))
+	}))
+	defer srv.Close()
+
+	c := NewGeminiClientWithOptions(mcp.WithLogger(mcp.NewNoopLogger()), mcp.WithHTTPClient(srv.Client()), mcp.WithBaseURL(srv.URL+"/v1beta/openai")).(*GeminiClient)
+	c.APIKey = "slow-key"
+	nativeURL := srv.URL + "/v1beta"
+	cachedKey := mcp.PromptCacheKey(nativeURL + "\x00slow-key\x00gemini-test\x00cached prefix")
+	expiredKey := mcp.PromptCacheKey(nativeURL + "\x00slow-key\x00gemini-test\x00expired prefix")
+	geminiContextCaches.Lock()
+	geminiContextCaches.entries[cachedKey] = geminiCacheEntry{name: "cachedContents/ready", expires: time.Now().Add(time.Hour)}
+	geminiContextCaches.entries[expiredKey] = geminiCacheEntry{name: "cachedContents/old", expires: time.Now().Add(-time.Minute)}
+	geminiContextCaches.Unlock()
+
+	var wg sync.WaitGroup
+	names := make([]string, 3)
+	for i := range names {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			names[i] = c.cachedContent("gemini-test", "slow prefix")
+		}(i)
+	}
+	for creates.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// A pending create does not block lookups of other prefixes
+	done := make(chan string)
+	go func() { done <- c.cachedContent("gemini-test", "cached prefix") }()
+	select {
+	case name := <-done:
+		if name != "cachedContents/ready" {
+			t.Errorf("cached lookup = %q", name)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("cached lookup blocked behind a pending create")
+	}
+
+	close(release)
+	wg.Wait()
+	for i, name := range names {
+		if name != "cachedContents/slow" {
+			t.Errorf("caller %d got %q", i, name)
+		}
+	}
+	if n := creates.Load(); n != 1 {
+		t.Errorf("cache created %d times, want 1", n)
+	}
+	geminiContextCaches.Lock()
+	_, expiredKept := geminiContextCaches.entries[expiredKey]
+	geminiContextCaches.Unlock()
+	if expiredKept {
+		t.Error("expired entry was not pruned")
+	}
+}
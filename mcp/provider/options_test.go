@@ -81,3 +81,77 @@ func TestOptionsWithQwenClient(t *testing.T) {
 		t.Error("MaxTokens should be 6000")
 	}
 }
+
+func TestClaudeStructuredOutputForcesTool(t *testing.T) {
+	client := NewClaudeClientWithOptions(mcp.WithLogger(mcp.NewNoopLogger())).(*ClaudeClient)
+
+	body := client.BuildRequestBodyFromRequest(&mcp.Request{
+		Model:    "claude-test",
+		Messages: []mcp.Message{mcp.NewSystemMessage("sys"), mcp.NewUserMessage("hi")},
+		ResponseFormat: &mcp.ResponseFormat{
+			Name:   "submit_decisions",
+			Schema: map[string]any{"type": "object"},
+		},
+	})
+
+	tools, _ := body["tools"].([]map[string]any)
+	if len(tools) != 1 || tools[0]["name"] != "submit_decisions" {
+		t.Fatalf("expected the schema as the only tool, got %v", body["tools"])
+	}
+	choice, _ := body["tool_choice"].(map[string]any)
+	if choice["type"] != "tool" || choice["name"] != "submit_decisions" {
+		t.Fatalf("expected forced tool choice, got %v", body["tool_choice"])
+	}
+}
+
+func TestOpenAIStructuredOutputResponseFormat(t *testing.T) {
+	client := NewOpenAIClientWithOptions(mcp.WithLogger(mcp.NewNoopLogger())).(*OpenAIClient)
+	req := &mcp.Request{
+		Model:          "gpt-test",
+		Messages:       []mcp.Message{mcp.NewUserMessage("hi")},
+		ResponseFormat: &mcp.ResponseFormat{Name: "submit_decisions", Schema: map[string]any{"type": "object"}},
+	}
+	if _, ok := client.BuildRequestBodyFromRequest(req)["response_format"]; !ok {
+		t.Fatalf("expected response_format for OpenAI")
+	}
+
+	deepseek := NewDeepSeekClientWithOptions(mcp.WithLogger(mcp.NewNoopLogger())).(*DeepSeekClient)
+	if _, ok := deepseek.BuildRequestBodyFromRequest(req)["response_format"]; ok {
+		t.Fatalf("response_format must not be sent to providers without schema support")
+	}
+}
+
+func TestClaudeUsageIncludesCacheTokens(t *testing.T) {
+	c := NewClaudeClient().(*ClaudeClient)
+	body := `{"content":[{"type":"text","text":"ok"}],"usage":{"input_tokens":100,"output_tokens":40,"cache_read_input_tokens":900,"cache_creation_input_tokens":50}}`
+	if _, err := c.ParseMCPResponseFull([]byte(body)); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	u := c.LastCallTokenUsage()
+	if u == nil || u.PromptTokens != 1050 || u.CachedTokens != 900 || u.CacheWriteTokens != 50 || u.TotalTokens != 1090 {
+		t.Fatalf("usage = %+v", u)
+	}
+}
+
+func TestClaudeCacheControlOnSystemPrefix(t *testing.T) {
+	c := NewClaudeClientWithOptions(mcp.WithLogger(mcp.NewNoopLogger())).(*ClaudeClient)
+	req := &mcp.Request{
+		Model:    "claude-test",
+		Messages: append(mcp.NewCachedSystemMessages("static rules", "equity 1000"), mcp.NewUserMessage("hi")),
+	}
+	blocks, ok := c.BuildRequestBodyFromRequest(req)["system"].([]map[string]any)
+	if !ok || len(blocks) != 2 {
+		t.Fatalf("expected two system blocks, got %v", c.BuildRequestBodyFromRequest(req)["system"])
+	}
+	if blocks[0]["text"] != "static rules" || blocks[0]["cache_control"] == nil {
+		t.Errorf("prefix block = %v", blocks[0])
+	}
+	if blocks[1]["text"] != "equity 1000" || blocks[1]["cache_control"] != nil {
+		t.Errorf("suffix block = %v", blocks[1])
+	}
+
+	plain := &mcp.Request{Messages: []mcp.Message{mcp.NewSystemMessage("rules"), mcp.NewUserMessage("hi")}}
+	if system := c.BuildRequestBodyFromRequest(plain)["system"]; system != "rules" {
+		t.Errorf("uncached system = %v, want plain string", system)
+	}
+}
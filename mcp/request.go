@@ -11,6 +11,7 @@ type Message struct {
 	ReasoningContent string     `json:"reasoning_content,omitempty"`     // Thinking-model reasoning (must be echoed back in multi-turn)
 	ToolCalls        []ToolCall `json:"tool_calls,omitempty"`            // Set by assistant when calling tools
 	ToolCallID       string     `json:"tool_call_id,omitempty"`          // Set on role="tool" result messages
+	Cacheable        bool       `json:"-"`                               // Ends the stable prompt prefix (see prompt_cache.go)
 }
 
 // ToolCall is a single function call requested by the LLM.
@@ -48,6 +49,26 @@ type FunctionDef struct {
 	Parameters  map[string]any `json:"parameters,omitempty"`  // Parameter schema (JSON Schema)
 }
 
+// ResponseFormat a JSON schema the reply must conform to.
+// OpenAI-compatible APIs receive it as response_format (Gemini's compatibility
+// layer maps it to responseSchema); Anthropic receives it as a forced tool call.
+type ResponseFormat struct {
+	Name        string         // Schema / tool name, e.g. "submit_decisions"
+	Description string         // What the object represents
+	Schema      map[string]any // JSON Schema of the top-level object
+}
+
+// StructuredContent returns the JSON object produced for a ResponseFormat:
+// the arguments of the tool call of that name (Anthropic) or the text content.
+func (r *LLMResponse) StructuredContent(name string) string {
+	for _, tc := range r.ToolCalls {
+		if tc.Function.Name == name {
+			return tc.Function.Arguments
+		}
+	}
+	return r.Content
+}
+
 // Request AI API request (supports advanced features)
 type Request struct {
 	// Basic fields
@@ -67,6 +88,10 @@ type Request struct {
 	Tools      []Tool `json:"tools,omitempty"`       // Available tools list
 	ToolChoice string `json:"tool_choice,omitempty"` // Tool choice strategy ("auto", "none", {"type": "function", "function": {"name": "xxx"}})
 
+	// ResponseFormat constrains the reply to a JSON schema on providers that
+	// support it natively (see Client.SupportsStructuredOutput). Ignored elsewhere.
+	ResponseFormat *ResponseFormat `json:"-"`
+
 	// Context for cancellation; not serialized.
 	Ctx context.Context `json:"-"`
 }
@@ -0,0 +1,30 @@
+package mcp
+
+import (
+	"nofx/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startRequestSpan starts the span of a whole AI request (all retries) under
+// req.Ctx and returns a copy of req carrying the span, so the caller's
+// request keeps its own context
+func (client *Client) startRequestSpan(req *Request) (*Request, trace.Span) {
+	ctx, span := tracing.Start(contextFromRequest(req), "mcp.request",
+		attribute.String("ai.provider", client.Provider),
+		attribute.String("ai.model", req.Model),
+		attribute.Int("ai.messages", len(req.Messages)),
+	)
+	traced := *req
+	traced.Ctx = ctx
+	return &traced, span
+}
+
+// startAttemptSpan starts the span of one HTTP attempt under the request span
+func startAttemptSpan(req *Request, attempt int) (*Request, trace.Span) {
+	ctx, span := tracing.Start(contextFromRequest(req), "mcp.attempt", attribute.Int("attempt", attempt))
+	traced := *req
+	traced.Ctx = ctx
+	return &traced, span
+}
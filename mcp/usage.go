@@ -0,0 +1,62 @@
+package mcp
+
+// openAIUsage is the usage block of OpenAI-compatible responses and stream
+// chunks. Providers report prompt tokens served from their cache in
+// different fields:
+//
+//	OpenAI, Gemini, Qwen, Grok, MiniMax: prompt_tokens_details.cached_tokens
+//	DeepSeek:                            prompt_cache_hit_tokens
+//	Kimi (Moonshot):                     cached_tokens
+type openAIUsage struct {
+	PromptTokens        int `json:"prompt_tokens"`
+	CompletionTokens    int `json:"completion_tokens"`
+	TotalTokens         int `json:"total_tokens"`
+	PromptTokensDetails *struct {
+		CachedTokens int `json:"cached_tokens"`
+	} `json:"prompt_tokens_details,omitempty"`
+	PromptCacheHitTokens int `json:"prompt_cache_hit_tokens"`
+	CachedTokens         int `json:"cached_tokens"`
+}
+
+// tokenUsage normalizes the block, nil when it carries no tokens
+func (u *openAIUsage) tokenUsage() *TokenUsage {
+	if u == nil || u.PromptTokens+u.CompletionTokens <= 0 {
+		return nil
+	}
+	usage := &TokenUsage{
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+		TotalTokens:      u.TotalTokens,
+	}
+	if usage.TotalTokens <= 0 {
+		usage.TotalTokens = u.PromptTokens + u.CompletionTokens
+	}
+	switch {
+	case u.PromptTokensDetails != nil && u.PromptTokensDetails.CachedTokens > 0:
+		usage.CachedTokens = u.PromptTokensDetails.CachedTokens
+	case u.PromptCacheHitTokens > 0:
+		usage.CachedTokens = u.PromptCacheHitTokens
+	default:
+		usage.CachedTokens = u.CachedTokens
+	}
+	return usage
+}
+
+// RecordUsage stores the token usage of the call that just completed as
+// LastCallUsage and reports it to TokenUsageCallback. A nil usage clears
+// LastCallUsage so a response without a usage block is never charged with
+// the tokens of an earlier call.
+func (client *Client) RecordUsage(usage *TokenUsage) {
+	if usage != nil {
+		usage.Provider = client.Provider
+		usage.Model = client.Model
+	}
+	client.LastCallUsage = usage
+	ReportStreamUsage(usage, client.Provider, client.Model)
+}
+
+// LastCallTokenUsage returns the token usage of the most recent call, nil
+// when the provider reported none
+func (client *Client) LastCallTokenUsage() *TokenUsage {
+	return client.LastCallUsage
+}
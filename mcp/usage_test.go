@@ -0,0 +1,57 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseMCPResponseFull_CachedTokenFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		usage string
+	}{
+		{"openai", `{"prompt_tokens":1000,"completion_tokens":50,"total_tokens":1050,"prompt_tokens_details":{"cached_tokens":800}}`},
+		{"deepseek", `{"prompt_tokens":1000,"completion_tokens":50,"total_tokens":1050,"prompt_cache_hit_tokens":800,"prompt_cache_miss_tokens":200}`},
+		{"kimi", `{"prompt_tokens":1000,"completion_tokens":50,"total_tokens":1050,"cached_tokens":800}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(WithProvider(tt.name), WithModel("m")).(*Client)
+			body := `{"choices":[{"message":{"content":"ok"}}],"usage":` + tt.usage + `}`
+			if _, err := client.ParseMCPResponseFull([]byte(body)); err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			u := client.LastCallTokenUsage()
+			if u == nil || u.PromptTokens != 1000 || u.CompletionTokens != 50 || u.CachedTokens != 800 {
+				t.Fatalf("usage = %+v", u)
+			}
+			if u.Provider != tt.name || u.Model != "m" {
+				t.Errorf("usage attributed to %s/%s", u.Provider, u.Model)
+			}
+		})
+	}
+}
+
+func TestParseMCPResponseFull_NoUsageClearsLastCall(t *testing.T) {
+	client := NewClient().(*Client)
+	client.LastCallUsage = &TokenUsage{PromptTokens: 10}
+	if _, err := client.ParseMCPResponseFull([]byte(`{"choices":[{"message":{"content":"ok"}}]}`)); err != nil {
+		t.Fatal(err)
+	}
+	if client.LastCallUsage != nil {
+		t.Errorf("stale usage kept: %+v", client.LastCallUsage)
+	}
+}
+
+func TestParseSSEStream_CachedTokens(t *testing.T) {
+	stream := "data: {\"choices\":[{\"delta\":{\"content\":\"hi\"}}]}\n" +
+		"data: {\"choices\":[],\"usage\":{\"prompt_tokens\":300,\"completion_tokens\":20,\"total_tokens\":320,\"prompt_tokens_details\":{\"cached_tokens\":256}}}\n" +
+		"data: [DONE]\n"
+	text, usage, err := ParseSSEStream(strings.NewReader(stream), nil, nil)
+	if err != nil || text != "hi" {
+		t.Fatalf("text=%q err=%v", text, err)
+	}
+	if usage == nil || usage.CachedTokens != 256 || usage.TotalTokens != 320 {
+		t.Fatalf("usage = %+v", usage)
+	}
+}
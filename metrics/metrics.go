@@ -0,0 +1,218 @@
+// Package metrics exposes Prometheus metrics for operators. Collectors live
+// on a private registry so only nofx series (plus the Go runtime and process
+// collectors) are served on /metrics.
+package metrics
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "nofx"
+
+// Order results
+const (
+	OrderPlaced   = "placed"
+	OrderRejected = "rejected"
+)
+
+// Registry holds every nofx collector
+var Registry = prometheus.NewRegistry()
+
+var (
+	cycleDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "trader_cycle_duration_seconds",
+		Help:      "Duration of trader decision cycles.",
+		Buckets:   []float64{1, 2.5, 5, 10, 20, 30, 60, 120, 300},
+	}, []string{"trader_id"})
+	cycles = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "trader_cycles_total",
+		Help:      "Trader decision cycles by result (ok or error).",
+	}, []string{"trader_id", "result"})
+
+	aiRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "ai_request_duration_seconds",
+		Help:      "Latency of AI decision requests by provider.",
+		Buckets:   []float64{0.5, 1, 2.5, 5, 10, 20, 30, 60, 120},
+	}, []string{"provider"})
+	aiRequestErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ai_request_errors_total",
+		Help:      "Failed AI decision requests by provider.",
+	}, []string{"provider"})
+	aiParseFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ai_parse_failures_total",
+		Help:      "AI replies that could not be parsed into decisions, by provider.",
+	}, []string{"provider"})
+	aiSpend = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ai_spend_usd_total",
+		Help:      "AI spend in USD by trader and provider.",
+	}, []string{"trader_id", "provider"})
+
+	orders = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "orders_total",
+		Help:      "Orders by trader, exchange and result (placed or rejected).",
+	}, []string{"trader_id", "exchange", "result"})
+
+	exchangeSyncs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "exchange_sync_total",
+		Help:      "Exchange order-sync attempts.",
+	}, []string{"trader_id", "exchange"})
+	exchangeSyncErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "exchange_sync_errors_total",
+		Help:      "Failed exchange order-sync attempts (exchange API errors).",
+	}, []string{"trader_id", "exchange"})
+	exchangeBackoff = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "exchange_sync_backoff_seconds",
+		Help:      "Extra wait added by the order-sync failure backoff; 0 when the exchange is healthy.",
+	}, []string{"trader_id", "exchange"})
+
+	openPositions = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "trader_open_positions",
+		Help:      "Open positions per trader.",
+	}, []string{"trader_id"})
+	equity = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "trader_equity_usd",
+		Help:      "Account equity per trader.",
+	}, []string{"trader_id"})
+	marginUsage = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "trader_margin_usage_ratio",
+		Help:      "Used margin as a fraction of equity per trader.",
+	}, []string{"trader_id"})
+	safeMode = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "trader_safe_mode",
+		Help:      "1 while the trader is in safe mode after repeated AI failures.",
+	}, []string{"trader_id"})
+)
+
+// traderVecs the collectors with a trader_id label, cleared by ForgetTrader
+var traderVecs = []interface {
+	DeletePartialMatch(labels prometheus.Labels) int
+}{
+	cycleDuration, cycles, aiSpend, orders, exchangeSyncs, exchangeSyncErrors,
+	exchangeBackoff, openPositions, equity, marginUsage, safeMode,
+}
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		cycleDuration, cycles,
+		aiRequestDuration, aiRequestErrors, aiParseFailures, aiSpend,
+		orders,
+		exchangeSyncs, exchangeSyncErrors, exchangeBackoff,
+		openPositions, equity, marginUsage, safeMode,
+	)
+}
+
+// Handler serves the registry in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// ObserveCycle records one decision cycle
+func ObserveCycle(traderID string, d time.Duration, err error) {
+	cycleDuration.WithLabelValues(traderID).Observe(d.Seconds())
+	cycles.WithLabelValues(traderID, result(err)).Inc()
+}
+
+// ObserveAIRequest records one AI decision request
+func ObserveAIRequest(provider string, d time.Duration, err error) {
+	provider = label(provider)
+	aiRequestDuration.WithLabelValues(provider).Observe(d.Seconds())
+	if err != nil {
+		aiRequestErrors.WithLabelValues(provider).Inc()
+	}
+}
+
+// IncAIParseFailure records an AI reply that could not be parsed
+func IncAIParseFailure(provider string) {
+	aiParseFailures.WithLabelValues(label(provider)).Inc()
+}
+
+// AddAISpend adds the cost of an AI call
+func AddAISpend(traderID, provider string, usd float64) {
+	if usd <= 0 {
+		return
+	}
+	aiSpend.WithLabelValues(traderID, label(provider)).Add(usd)
+}
+
+// ObserveOrder records an order the trader tried to place; err is the
+// exchange or risk-check rejection, nil when the order was placed
+func ObserveOrder(traderID, exchange string, err error) {
+	r := OrderPlaced
+	if err != nil {
+		r = OrderRejected
+	}
+	orders.WithLabelValues(traderID, label(exchange), r).Inc()
+}
+
+// ObserveExchangeSync records an order-sync attempt and the backoff now in
+// effect (0 once the exchange answers again)
+func ObserveExchangeSync(traderID, exchange string, err error, backoff time.Duration) {
+	exchange = label(exchange)
+	exchangeSyncs.WithLabelValues(traderID, exchange).Inc()
+	if err != nil {
+		exchangeSyncErrors.WithLabelValues(traderID, exchange).Inc()
+	}
+	exchangeBackoff.WithLabelValues(traderID, exchange).Set(backoff.Seconds())
+}
+
+// SetTraderAccount records the account state seen in the latest cycle.
+// marginUsedPct is a percentage, as reported to the AI.
+func SetTraderAccount(traderID string, totalEquity, marginUsedPct float64, positions int) {
+	equity.WithLabelValues(traderID).Set(totalEquity)
+	marginUsage.WithLabelValues(traderID).Set(marginUsedPct / 100)
+	openPositions.WithLabelValues(traderID).Set(float64(positions))
+}
+
+// SetSafeMode records whether the trader is in safe mode
+func SetSafeMode(traderID string, active bool) {
+	v := 0.0
+	if active {
+		v = 1
+	}
+	safeMode.WithLabelValues(traderID).Set(v)
+}
+
+// ForgetTrader drops every series of a removed trader so deleted traders do
+// not linger on /metrics
+func ForgetTrader(traderID string) {
+	for _, v := range traderVecs {
+		v.DeletePartialMatch(prometheus.Labels{"trader_id": traderID})
+	}
+}
+
+func result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+func label(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
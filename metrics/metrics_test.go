@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestObserveOrderAndSync(t *testing.T) {
+	ObserveOrder("t-order", "Binance", nil)
+	ObserveOrder("t-order", "binance", errors.New("insufficient margin"))
+	ObserveOrder("t-order", "binance", errors.New("min notional"))
+	if got := testutil.ToFloat64(orders.WithLabelValues("t-order", "binance", OrderPlaced)); got != 1 {
+		t.Fatalf("placed = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(orders.WithLabelValues("t-order", "binance", OrderRejected)); got != 2 {
+		t.Fatalf("rejected = %v, want 2", got)
+	}
+
+	ObserveExchangeSync("t-order", "okx", errors.New("429"), time.Minute)
+	if got := testutil.ToFloat64(exchangeBackoff.WithLabelValues("t-order", "okx")); got != 60 {
+		t.Fatalf("backoff = %v, want 60", got)
+	}
+	ObserveExchangeSync("t-order", "okx", nil, 0)
+	if got := testutil.ToFloat64(exchangeBackoff.WithLabelValues("t-order", "okx")); got != 0 {
+		t.Fatalf("backoff after recovery = %v, want 0", got)
+	}
+	if got := testutil.ToFloat64(exchangeSyncErrors.WithLabelValues("t-order", "okx")); got != 1 {
+		t.Fatalf("sync errors = %v, want 1", got)
+	}
+}
+
+func TestForgetTraderDropsSeries(t *testing.T) {
+	SetTraderAccount("t-gone", 1000, 25, 2)
+	SetSafeMode("t-gone", true)
+	AddAISpend("t-gone", "deepseek", 0.01)
+	SetTraderAccount("t-kept", 500, 0, 0)
+	if got := testutil.ToFloat64(marginUsage.WithLabelValues("t-gone")); got != 0.25 {
+		t.Fatalf("margin usage = %v, want 0.25", got)
+	}
+
+	ForgetTrader("t-gone")
+	if n := testutil.CollectAndCount(equity, "nofx_trader_equity_usd"); n != 1 {
+		t.Fatalf("equity series after ForgetTrader = %d, want 1", n)
+	}
+	if n := testutil.CollectAndCount(safeMode); n != 0 {
+		t.Fatalf("safe mode series after ForgetTrader = %d, want 0", n)
+	}
+}
+
+func TestHandlerServesNofxMetrics(t *testing.T) {
+	ObserveCycle("t-http", 3*time.Second, nil)
+	ObserveAIRequest("", time.Second, errors.New("timeout"))
+
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body := w.Body.String()
+	for _, want := range []string{
+		`nofx_trader_cycles_total{result="ok",trader_id="t-http"} 1`,
+		`nofx_ai_request_errors_total{provider="unknown"} 1`,
+		"go_goroutines",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("metrics output misses %q", want)
+		}
+	}
+}
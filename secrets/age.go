@@ -0,0 +1,99 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"filippo.io/age"
+)
+
+// Environment variable names for the age backend
+const (
+	EnvAgeFile     = "NOFX_SECRETS_AGE_FILE"     // age-encrypted JSON object of name -> secret
+	EnvAgeIdentity = "NOFX_SECRETS_AGE_IDENTITY" // identity file (age-keygen output) that decrypts it
+)
+
+// AgeBackend reads secrets from a local age-encrypted file holding a JSON
+// object of name -> secret. secret://age/binance_api_key reads the
+// "binance_api_key" entry. The file is decrypted again when it changes.
+type AgeBackend struct {
+	path       string
+	identities []age.Identity
+
+	mu      sync.Mutex
+	modTime time.Time
+	secrets map[string]string
+}
+
+// NewAgeBackend creates a backend for the secrets file at path, decrypted
+// with the identities in identityPath
+func NewAgeBackend(path, identityPath string) (*AgeBackend, error) {
+	if identityPath == "" {
+		return nil, fmt.Errorf("%s is not set", EnvAgeIdentity)
+	}
+	f, err := os.Open(identityPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	identities, err := age.ParseIdentities(f)
+	if err != nil {
+		return nil, fmt.Errorf("parse identity file: %w", err)
+	}
+	b := &AgeBackend{path: path, identities: identities}
+	// Fail at startup rather than at the first trader start
+	if _, err := b.load(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Resolve returns the entry named by the reference path
+func (b *AgeBackend) Resolve(_ context.Context, ref Reference) (string, error) {
+	secrets, err := b.load()
+	if err != nil {
+		return "", err
+	}
+	value, ok := secrets[ref.Path]
+	if !ok {
+		return "", fmt.Errorf("no entry %q in %s", ref.Path, b.path)
+	}
+	return value, nil
+}
+
+func (b *AgeBackend) load() (map[string]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	info, err := os.Stat(b.path)
+	if err != nil {
+		return nil, err
+	}
+	if b.secrets != nil && info.ModTime().Equal(b.modTime) {
+		return b.secrets, nil
+	}
+
+	data, err := os.ReadFile(b.path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := age.Decrypt(bytes.NewReader(data), b.identities...)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt %s: %w", b.path, err)
+	}
+	plain, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt %s: %w", b.path, err)
+	}
+	var secrets map[string]string
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return nil, fmt.Errorf("%s must hold a JSON object of strings: %w", b.path, err)
+	}
+	b.secrets, b.modTime = secrets, info.ModTime()
+	return secrets, nil
+}
@@ -0,0 +1,26 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EnvPrefix is prepended to env references: secret://env/BINANCE_SECRET reads
+// NOFX_SECRET_BINANCE_SECRET. The prefix keeps references away from the
+// server's own settings such as JWT_SECRET.
+const EnvPrefix = "NOFX_SECRET_"
+
+// EnvBackend reads secrets from environment variables
+type EnvBackend struct{}
+
+// Resolve reads the variable named by the reference path
+func (EnvBackend) Resolve(_ context.Context, ref Reference) (string, error) {
+	name := EnvPrefix + strings.TrimPrefix(ref.Path, EnvPrefix)
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return value, nil
+}
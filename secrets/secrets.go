@@ -0,0 +1,292 @@
+// Package secrets resolves references to secrets kept outside the database.
+//
+// An exchange or AI model secret field may hold a reference such as
+// "secret://vault/binance#api_key" instead of the secret itself. The
+// reference is stored (encrypted, like any other value) and only resolved
+// when a trader is built, so the resolved value never reaches the API.
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"nofx/crypto"
+	"nofx/store"
+)
+
+// Scheme prefixes every secret reference
+const Scheme = "secret://"
+
+// Environment variable names
+const (
+	// EnvAllowedUsers lists the user IDs allowed to use references (comma
+	// separated, "*" for everyone). References resolve with the server's
+	// backend credentials, so they are off unless the operator opts in.
+	EnvAllowedUsers = "NOFX_SECRETS_USERS"
+)
+
+// resolveTimeout bounds a single lookup against a remote backend
+const resolveTimeout = 10 * time.Second
+
+// ErrNotAllowed is returned when a user may not use secret references
+var ErrNotAllowed = errors.New("secret references are not enabled for this user")
+
+// Reference points to a secret in a backend: secret://<backend>/<path>[#<field>]
+type Reference struct {
+	Backend string
+	Path    string
+	Field   string
+	UserID  string // owner of the stored value, set by the resolver; not part of the reference text
+}
+
+func (r Reference) String() string {
+	s := Scheme + r.Backend + "/" + r.Path
+	if r.Field != "" {
+		s += "#" + r.Field
+	}
+	return s
+}
+
+// IsReference reports whether a stored value is a secret reference
+func IsReference(value string) bool {
+	return strings.HasPrefix(strings.TrimSpace(value), Scheme)
+}
+
+// ParseReference parses a secret reference
+func ParseReference(value string) (Reference, error) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, Scheme) {
+		return Reference{}, fmt.Errorf("not a secret reference")
+	}
+	rest := strings.TrimPrefix(value, Scheme)
+	rest, field, _ := strings.Cut(rest, "#")
+	backend, path, _ := strings.Cut(rest, "/")
+	path = strings.Trim(path, "/")
+	if backend == "" || path == "" {
+		return Reference{}, fmt.Errorf("invalid secret reference %q: want %s<backend>/<path>[#field]", value, Scheme)
+	}
+	if err := checkPath(path); err != nil {
+		return Reference{}, fmt.Errorf("invalid secret reference %q: %w", value, err)
+	}
+	return Reference{Backend: backend, Path: path, Field: field}, nil
+}
+
+// checkPath rejects "." and ".." segments, which would let a reference
+// climb out of the location a backend scopes it to
+func checkPath(path string) error {
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "." || segment == ".." {
+			return fmt.Errorf("path segment %q is not allowed", segment)
+		}
+	}
+	return nil
+}
+
+// Backend looks up secrets by reference
+type Backend interface {
+	Resolve(ctx context.Context, ref Reference) (string, error)
+}
+
+// Resolver dispatches references to the configured backends
+type Resolver struct {
+	mu        sync.RWMutex
+	backends  map[string]Backend
+	users     map[string]bool
+	allowsAll bool
+}
+
+// NewResolver creates a resolver with no backends and no allowed users
+func NewResolver() *Resolver {
+	return &Resolver{backends: make(map[string]Backend), users: make(map[string]bool)}
+}
+
+// Register adds a backend under the name used in references
+func (r *Resolver) Register(name string, b Backend) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.backends[name] = b
+}
+
+// AllowUsers lets the given users store and resolve references; "*" allows everyone
+func (r *Resolver) AllowUsers(userIDs ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range userIDs {
+		id = strings.TrimSpace(id)
+		switch id {
+		case "":
+		case "*":
+			r.allowsAll = true
+		default:
+			r.users[id] = true
+		}
+	}
+}
+
+// Backends the names of the registered backends
+func (r *Resolver) Backends() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.backends))
+	for name := range r.backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate checks that a value about to be stored is usable: plain values
+// always are, references must parse, name a configured backend and belong to
+// an allowed user. The secret itself is not looked up.
+func (r *Resolver) Validate(userID, value string) error {
+	if !IsReference(value) {
+		return nil
+	}
+	_, _, err := r.backendFor(userID, value)
+	return err
+}
+
+// Resolve returns the secret a reference points to, or value unchanged when
+// it is not a reference
+func (r *Resolver) Resolve(ctx context.Context, userID, value string) (string, error) {
+	if !IsReference(value) {
+		return value, nil
+	}
+	ref, b, err := r.backendFor(userID, value)
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, resolveTimeout)
+	defer cancel()
+	secret, err := b.Resolve(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("resolve %s: %w", ref, err)
+	}
+	if secret == "" {
+		return "", fmt.Errorf("resolve %s: secret is empty", ref)
+	}
+	return secret, nil
+}
+
+func (r *Resolver) backendFor(userID, value string) (Reference, Backend, error) {
+	ref, err := ParseReference(value)
+	if err != nil {
+		return Reference{}, nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if !r.allowsAll && !r.users[userID] {
+		return Reference{}, nil, ErrNotAllowed
+	}
+	b, ok := r.backends[ref.Backend]
+	if !ok {
+		return Reference{}, nil, fmt.Errorf("secret backend %q is not configured", ref.Backend)
+	}
+	ref.UserID = userID
+	return ref, b, nil
+}
+
+// NewResolverFromEnv creates a resolver with the env backend plus the age
+// and Vault backends when their settings are present
+func NewResolverFromEnv() (*Resolver, error) {
+	r := NewResolver()
+	r.AllowUsers(strings.Split(os.Getenv(EnvAllowedUsers), ",")...)
+	r.Register("env", EnvBackend{})
+
+	if file := strings.TrimSpace(os.Getenv(EnvAgeFile)); file != "" {
+		b, err := NewAgeBackend(file, strings.TrimSpace(os.Getenv(EnvAgeIdentity)))
+		if err != nil {
+			return nil, fmt.Errorf("age secret backend: %w", err)
+		}
+		r.Register("age", b)
+	}
+	if addr := strings.TrimSpace(os.Getenv(EnvVaultAddr)); addr != "" {
+		b, err := NewVaultBackendFromEnv(addr)
+		if err != nil {
+			return nil, fmt.Errorf("vault secret backend: %w", err)
+		}
+		r.Register("vault", b)
+	}
+	return r, nil
+}
+
+// ============================================================================
+// Default resolver used by the trader manager and API
+// ============================================================================
+
+var (
+	defaultMu       sync.RWMutex
+	defaultResolver = NewResolver()
+)
+
+// SetDefault sets the resolver used by the package-level helpers
+func SetDefault(r *Resolver) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultResolver = r
+}
+
+// Default returns the resolver used by the package-level helpers
+func Default() *Resolver {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultResolver
+}
+
+// Validate checks a value with the default resolver
+func Validate(userID, value string) error {
+	return Default().Validate(userID, value)
+}
+
+// Resolve resolves a value with the default resolver
+func Resolve(userID, value string) (string, error) {
+	return Default().Resolve(context.Background(), userID, value)
+}
+
+// ResolveExchange returns a copy of ex with every secret reference resolved.
+// ex itself is left untouched so the references are what gets saved back.
+func ResolveExchange(ex *store.Exchange) (*store.Exchange, error) {
+	if ex == nil {
+		return nil, nil
+	}
+	resolved := *ex
+	fields := []struct {
+		name  string
+		value *crypto.EncryptedString
+	}{
+		{"api_key", &resolved.APIKey},
+		{"secret_key", &resolved.SecretKey},
+		{"passphrase", &resolved.Passphrase},
+		{"aster_private_key", &resolved.AsterPrivateKey},
+		{"lighter_private_key", &resolved.LighterPrivateKey},
+		{"lighter_api_key_private_key", &resolved.LighterAPIKeyPrivateKey},
+	}
+	for _, f := range fields {
+		secret, err := Resolve(ex.UserID, string(*f.value))
+		if err != nil {
+			return nil, fmt.Errorf("exchange %s %s: %w", ex.AccountName, f.name, err)
+		}
+		*f.value = crypto.EncryptedString(secret)
+	}
+	return &resolved, nil
+}
+
+// ResolveAIModel returns a copy of m with its API key resolved
+func ResolveAIModel(m *store.AIModel) (*store.AIModel, error) {
+	if m == nil {
+		return nil, nil
+	}
+	resolved := *m
+	secret, err := Resolve(m.UserID, string(m.APIKey))
+	if err != nil {
+		return nil, fmt.Errorf("AI model %s api_key: %w", m.Name, err)
+	}
+	resolved.APIKey = crypto.EncryptedString(secret)
+	return &resolved, nil
+}
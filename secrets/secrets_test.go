@@ -0,0 +1,146 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"nofx/crypto"
+	"nofx/store"
+
+	"filippo.io/age"
+)
+
+func TestParseReference(t *testing.T) {
+	ref, err := ParseReference(" secret://vault/nofx/binance/#api_key ")
+	if err != nil {
+		t.Fatalf("ParseReference: %v", err)
+	}
+	if ref.Backend != "vault" || ref.Path != "nofx/binance" || ref.Field != "api_key" {
+		t.Fatalf("ref = %+v", ref)
+	}
+	if ref.String() != "secret://vault/nofx/binance#api_key" {
+		t.Fatalf("String() = %q", ref.String())
+	}
+	for _, bad := range []string{"secret://", "secret://vault", "secret:///path", "plain-key", "secret://vault/../u2/binance", "secret://vault/a/./b"} {
+		if _, err := ParseReference(bad); err == nil {
+			t.Fatalf("ParseReference(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestResolverChecksUserAndBackend(t *testing.T) {
+	t.Setenv("NOFX_SECRET_BINANCE", "s3cret")
+	r := NewResolver()
+	r.Register("env", EnvBackend{})
+
+	if v, err := r.Resolve(context.Background(), "u1", "plain"); err != nil || v != "plain" {
+		t.Fatalf("plain value = %q, %v", v, err)
+	}
+	if _, err := r.Resolve(context.Background(), "u1", "secret://env/BINANCE"); !errors.Is(err, ErrNotAllowed) {
+		t.Fatalf("resolve for a user not allowed = %v, want ErrNotAllowed", err)
+	}
+
+	r.AllowUsers("u1")
+	if v, err := r.Resolve(context.Background(), "u1", "secret://env/BINANCE"); err != nil || v != "s3cret" {
+		t.Fatalf("env reference = %q, %v", v, err)
+	}
+	if err := r.Validate("u2", "secret://env/BINANCE"); !errors.Is(err, ErrNotAllowed) {
+		t.Fatalf("validate for another user = %v", err)
+	}
+	if ref, _, err := r.backendFor("u1", "secret://env/BINANCE"); err != nil || ref.UserID != "u1" {
+		t.Fatalf("backendFor = %+v, %v, want the reference owned by u1", ref, err)
+	}
+	if err := r.Validate("u1", "secret://vault/x"); err == nil {
+		t.Fatal("reference to an unconfigured backend validated")
+	}
+	// Only NOFX_SECRET_ variables are reachable
+	t.Setenv("JWT_SECRET", "server-secret")
+	if _, err := r.Resolve(context.Background(), "u1", "secret://env/JWT_SECRET"); err == nil {
+		t.Fatal("env reference read a server setting")
+	}
+}
+
+func TestAgeBackend(t *testing.T) {
+	dir := t.TempDir()
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	identityPath := filepath.Join(dir, "key.txt")
+	if err := os.WriteFile(identityPath, []byte(identity.String()+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	secretsPath := filepath.Join(dir, "secrets.age")
+	writeAgeSecrets(t, secretsPath, identity.Recipient(), `{"binance_secret":"from-age"}`)
+
+	b, err := NewAgeBackend(secretsPath, identityPath)
+	if err != nil {
+		t.Fatalf("NewAgeBackend: %v", err)
+	}
+	if v, err := b.Resolve(context.Background(), Reference{Backend: "age", Path: "binance_secret"}); err != nil || v != "from-age" {
+		t.Fatalf("Resolve = %q, %v", v, err)
+	}
+	if _, err := b.Resolve(context.Background(), Reference{Backend: "age", Path: "missing"}); err == nil {
+		t.Fatal("missing entry resolved")
+	}
+
+	other, _ := age.GenerateX25519Identity()
+	otherPath := filepath.Join(dir, "other.txt")
+	_ = os.WriteFile(otherPath, []byte(other.String()+"\n"), 0o600)
+	if _, err := NewAgeBackend(secretsPath, otherPath); err == nil {
+		t.Fatal("secrets decrypted with the wrong identity")
+	}
+}
+
+func writeAgeSecrets(t *testing.T, path string, recipient age.Recipient, content string) {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, recipient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolveExchangeLeavesOriginal(t *testing.T) {
+	t.Setenv("NOFX_SECRET_OKX_SECRET", "resolved-secret")
+	r := NewResolver()
+	r.Register("env", EnvBackend{})
+	r.AllowUsers("*")
+	SetDefault(r)
+	t.Cleanup(func() { SetDefault(NewResolver()) })
+
+	ex := &store.Exchange{
+		UserID:     "u1",
+		APIKey:     crypto.EncryptedString("plain-api-key"),
+		SecretKey:  crypto.EncryptedString("secret://env/OKX_SECRET"),
+		Passphrase: crypto.EncryptedString(""),
+	}
+	resolved, err := ResolveExchange(ex)
+	if err != nil {
+		t.Fatalf("ResolveExchange: %v", err)
+	}
+	if resolved.APIKey != "plain-api-key" || resolved.SecretKey != "resolved-secret" {
+		t.Fatalf("resolved = %+v", resolved)
+	}
+	if ex.SecretKey != "secret://env/OKX_SECRET" {
+		t.Fatal("ResolveExchange changed the stored config")
+	}
+
+	ex.Passphrase = "secret://env/MISSING"
+	if _, err := ResolveExchange(ex); err == nil {
+		t.Fatal("unresolvable reference did not fail")
+	}
+}
@@ -0,0 +1,158 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Environment variable names for the Vault backend
+const (
+	EnvVaultAddr      = "VAULT_ADDR"
+	EnvVaultToken     = "VAULT_TOKEN"
+	EnvVaultNamespace = "VAULT_NAMESPACE"
+	EnvVaultMount     = "NOFX_SECRETS_VAULT_MOUNT"      // KV mount, default "secret"
+	EnvVaultKVVersion = "NOFX_SECRETS_VAULT_KV_VERSION" // 1 or 2, default 2
+	EnvVaultPrefix    = "NOFX_SECRETS_VAULT_PREFIX"     // per-user path prefix, default "nofx"
+)
+
+// defaultVaultField is read when a reference names no field
+const defaultVaultField = "value"
+
+// defaultVaultPrefix is the top-level path of the per-user secrets
+const defaultVaultPrefix = "nofx"
+
+// VaultBackend reads secrets from a HashiCorp Vault KV engine over its HTTP
+// API. Every user reads under their own path: secret://vault/binance#api_key
+// saved by user u1 reads field "api_key" of the secret at nofx/u1/binance in
+// the configured mount, so one user cannot name another user's secrets.
+type VaultBackend struct {
+	Addr      string
+	Token     string
+	Namespace string
+	Mount     string
+	Prefix    string // path the per-user directories live under, default "nofx"
+	KVVersion int
+	Client    *http.Client
+}
+
+// NewVaultBackendFromEnv creates a Vault backend for addr with the token,
+// namespace, mount and KV version from the environment
+func NewVaultBackendFromEnv(addr string) (*VaultBackend, error) {
+	token := strings.TrimSpace(os.Getenv(EnvVaultToken))
+	if token == "" {
+		return nil, fmt.Errorf("%s is not set", EnvVaultToken)
+	}
+	b := &VaultBackend{
+		Addr:      addr,
+		Token:     token,
+		Namespace: strings.TrimSpace(os.Getenv(EnvVaultNamespace)),
+		Mount:     strings.TrimSpace(os.Getenv(EnvVaultMount)),
+		Prefix:    strings.TrimSpace(os.Getenv(EnvVaultPrefix)),
+		KVVersion: 2,
+	}
+	switch strings.TrimSpace(os.Getenv(EnvVaultKVVersion)) {
+	case "", "2":
+	case "1":
+		b.KVVersion = 1
+	default:
+		return nil, fmt.Errorf("%s must be 1 or 2", EnvVaultKVVersion)
+	}
+	return b, nil
+}
+
+// Resolve reads one field of a KV secret
+func (b *VaultBackend) Resolve(ctx context.Context, ref Reference) (string, error) {
+	secretURL, err := b.secretURL(ref)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, secretURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", b.Token)
+	if b.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", b.Namespace)
+	}
+	client := b.Client
+	if client == nil {
+		client = &http.Client{Timeout: resolveTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		// Vault error bodies only carry messages, never secret data
+		var body struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&body)
+		return "", fmt.Errorf("vault returned %s %s", resp.Status, strings.Join(body.Errors, "; "))
+	}
+
+	var body struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("decode vault response: %w", err)
+	}
+	data := body.Data
+	if b.KVVersion != 1 {
+		// KV v2 nests the secret under data.data next to its metadata
+		var v2 struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(body.Data, &v2); err != nil {
+			return "", fmt.Errorf("decode vault response: %w", err)
+		}
+		data = v2.Data
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil || fields == nil {
+		return "", fmt.Errorf("secret %s has no data", ref.Path)
+	}
+
+	field := ref.Field
+	if field == "" {
+		field = defaultVaultField
+	}
+	value, ok := fields[field].(string)
+	if !ok {
+		return "", fmt.Errorf("secret %s has no string field %q", ref.Path, field)
+	}
+	return value, nil
+}
+
+// secretURL returns the KV read URL of ref under its owner's directory
+func (b *VaultBackend) secretURL(ref Reference) (string, error) {
+	if ref.UserID == "" {
+		return "", fmt.Errorf("vault reference %s has no owner", ref)
+	}
+	if err := checkPath(ref.UserID + "/" + ref.Path); err != nil {
+		return "", err
+	}
+	mount := strings.Trim(b.Mount, "/")
+	if mount == "" {
+		mount = "secret"
+	}
+	prefix := strings.Trim(b.Prefix, "/")
+	if prefix == "" {
+		prefix = defaultVaultPrefix
+	}
+	segments := []string{strings.TrimRight(b.Addr, "/"), "v1", mount}
+	if b.KVVersion != 1 {
+		segments = append(segments, "data")
+	}
+	segments = append(segments, prefix, url.PathEscape(ref.UserID))
+	for _, p := range strings.Split(ref.Path, "/") {
+		segments = append(segments, url.PathEscape(p))
+	}
+	return strings.Join(segments, "/"), nil
+}
@@ -0,0 +1,95 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+// fakeVault serves one KV secret the way a Vault dev server does
+func fakeVault(t *testing.T, kvVersion int) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{"permission denied"}})
+			return
+		}
+		fields := map[string]interface{}{"api_key": "vault-key", "value": "default-value"}
+		switch {
+		case kvVersion == 2 && r.URL.Path == "/v1/secret/data/nofx/u1/binance":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{"data": fields, "metadata": map[string]interface{}{"version": 1}},
+			})
+		case kvVersion == 1 && r.URL.Path == "/v1/kv/nofx/u1/binance":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": fields})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+}
+
+func TestVaultBackendKV2(t *testing.T) {
+	srv := fakeVault(t, 2)
+	defer srv.Close()
+	b := &VaultBackend{Addr: srv.URL, Token: "root", KVVersion: 2}
+
+	if v, err := b.Resolve(context.Background(), Reference{UserID: "u1", Path: "binance", Field: "api_key"}); err != nil || v != "vault-key" {
+		t.Fatalf("Resolve = %q, %v", v, err)
+	}
+	if v, err := b.Resolve(context.Background(), Reference{UserID: "u1", Path: "binance"}); err != nil || v != "default-value" {
+		t.Fatalf("Resolve without a field = %q, %v", v, err)
+	}
+	if _, err := b.Resolve(context.Background(), Reference{UserID: "u1", Path: "binance", Field: "missing"}); err == nil {
+		t.Fatal("missing field resolved")
+	}
+	if _, err := b.Resolve(context.Background(), Reference{UserID: "u1", Path: "other"}); err == nil {
+		t.Fatal("missing secret resolved")
+	}
+	// The same reference saved by another user reads that user's directory
+	if _, err := b.Resolve(context.Background(), Reference{UserID: "u2", Path: "binance"}); err == nil {
+		t.Fatal("another user's secret resolved")
+	}
+	for _, ref := range []Reference{
+		{Path: "binance"},
+		{UserID: "u2", Path: "../u1/binance"},
+		{UserID: "..", Path: "nofx/u1/binance"},
+	} {
+		if _, err := b.Resolve(context.Background(), ref); err == nil {
+			t.Fatalf("Resolve(%+v) escaped the user's directory", ref)
+		}
+	}
+	b.Token = "wrong"
+	if _, err := b.Resolve(context.Background(), Reference{UserID: "u1", Path: "binance"}); err == nil {
+		t.Fatal("resolved with a bad token")
+	}
+}
+
+func TestVaultBackendKV1(t *testing.T) {
+	srv := fakeVault(t, 1)
+	defer srv.Close()
+	b := &VaultBackend{Addr: srv.URL, Token: "root", Mount: "kv", KVVersion: 1}
+	if v, err := b.Resolve(context.Background(), Reference{UserID: "u1", Path: "binance", Field: "api_key"}); err != nil || v != "vault-key" {
+		t.Fatalf("Resolve = %q, %v", v, err)
+	}
+}
+
+// TestVaultDevServer runs against a real Vault dev server when one is given:
+//
+//	vault server -dev -dev-root-token-id=root &
+//	vault kv put secret/nofx/u1/test api_key=dev-key
+//	NOFX_TEST_VAULT_ADDR=http://127.0.0.1:8200 go test ./secrets -run DevServer
+func TestVaultDevServer(t *testing.T) {
+	addr := os.Getenv("NOFX_TEST_VAULT_ADDR")
+	if addr == "" {
+		t.Skip("NOFX_TEST_VAULT_ADDR not set")
+	}
+	b := &VaultBackend{Addr: addr, Token: "root", KVVersion: 2}
+	if v, err := b.Resolve(context.Background(), Reference{UserID: "u1", Path: "test", Field: "api_key"}); err != nil || v != "dev-key" {
+		t.Fatalf("Resolve = %q, %v", v, err)
+	}
+}
@@ -0,0 +1,252 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AI budget levels
+const (
+	AIBudgetOK        = "ok"
+	AIBudgetDegraded  = "degraded"  // AIBudgetDegradeRatio of a limit spent, AI usage is degraded
+	AIBudgetExhausted = "exhausted" // a limit is spent, AI calls stop
+)
+
+// AIBudgetDegradeRatio share of a limit at which a budget degrades
+const AIBudgetDegradeRatio = 0.8
+
+// AI budget degrade modes
+const (
+	AIBudgetDegradeModel    = "model"    // switch to the cheapest model of the failover chain
+	AIBudgetDegradeInterval = "interval" // call the AI less often
+)
+
+// AIBudget daily and monthly USD limits on AI spend, for one trader or, with
+// an empty TraderID, for all traders of a user. A zero limit is unlimited.
+type AIBudget struct {
+	ID                     int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID                 string    `gorm:"column:user_id;not null;uniqueIndex:idx_ai_budgets_scope" json:"user_id"`
+	TraderID               string    `gorm:"column:trader_id;not null;default:'';uniqueIndex:idx_ai_budgets_scope" json:"trader_id"`
+	DailyLimitUSD          float64   `gorm:"column:daily_limit_usd;default:0" json:"daily_limit_usd"`
+	MonthlyLimitUSD        float64   `gorm:"column:monthly_limit_usd;default:0" json:"monthly_limit_usd"`
+	DegradeMode            string    `gorm:"column:degrade_mode;default:'model'" json:"degrade_mode"`
+	DegradeIntervalMinutes int       `gorm:"column:degrade_interval_minutes;default:0" json:"degrade_interval_minutes"` // minimum minutes between AI calls when degraded, 0 = twice the scan interval
+	CreatedAt              time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt              time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (AIBudget) TableName() string { return "ai_budgets" }
+
+// AIBudgetUsage spend against one limit
+type AIBudgetUsage struct {
+	LimitUSD float64 `json:"limit_usd"`
+	SpentUSD float64 `json:"spent_usd"`
+	UsedPct  float64 `json:"used_pct"`
+	Level    string  `json:"level"`
+}
+
+// AIBudgetScopeState state of one budget (trader or user)
+type AIBudgetScopeState struct {
+	Scope   string         `json:"scope"` // "trader" or "user"
+	Level   string         `json:"level"`
+	Daily   *AIBudgetUsage `json:"daily,omitempty"`
+	Monthly *AIBudgetUsage `json:"monthly,omitempty"`
+}
+
+// AIBudgetStatus combined budget state of a trader: the worst of its own
+// budget and its user's budget
+type AIBudgetStatus struct {
+	Level                  string              `json:"level"`
+	Reason                 string              `json:"reason,omitempty"`
+	DegradeMode            string              `json:"degrade_mode,omitempty"`
+	DegradeIntervalMinutes int                 `json:"degrade_interval_minutes,omitempty"`
+	Trader                 *AIBudgetScopeState `json:"trader,omitempty"`
+	User                   *AIBudgetScopeState `json:"user,omitempty"`
+}
+
+// AIBudgetStore AI spend budget storage
+type AIBudgetStore struct {
+	db *gorm.DB
+}
+
+// NewAIBudgetStore creates a new AIBudgetStore
+func NewAIBudgetStore(db *gorm.DB) *AIBudgetStore {
+	return &AIBudgetStore{db: db}
+}
+
+func (s *AIBudgetStore) initTables() error {
+	return s.db.AutoMigrate(&AIBudget{})
+}
+
+// List returns the user's budgets, the user-wide budget first
+func (s *AIBudgetStore) List(userID string) ([]*AIBudget, error) {
+	var budgets []*AIBudget
+	err := s.db.Where("user_id = ?", userID).Order("trader_id ASC").Find(&budgets).Error
+	return budgets, err
+}
+
+// Get returns the budget of a trader, or the user-wide budget when traderID
+// is empty. Returns nil without error when no budget is set.
+func (s *AIBudgetStore) Get(userID, traderID string) (*AIBudget, error) {
+	var budget AIBudget
+	err := s.db.Where("user_id = ? AND trader_id = ?", userID, traderID).First(&budget).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &budget, nil
+}
+
+// Set creates or replaces the budget of its scope
+func (s *AIBudgetStore) Set(budget *AIBudget) error {
+	if budget.DailyLimitUSD < 0 || budget.MonthlyLimitUSD < 0 || budget.DegradeIntervalMinutes < 0 {
+		return fmt.Errorf("budget limits must not be negative")
+	}
+	switch budget.DegradeMode {
+	case "":
+		budget.DegradeMode = AIBudgetDegradeModel
+	case AIBudgetDegradeModel, AIBudgetDegradeInterval:
+	default:
+		return fmt.Errorf("invalid degrade mode: %s", budget.DegradeMode)
+	}
+
+	existing, err := s.Get(budget.UserID, budget.TraderID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return s.db.Create(budget).Error
+	}
+	budget.ID = existing.ID
+	budget.CreatedAt = existing.CreatedAt
+	return s.db.Save(budget).Error
+}
+
+// Delete removes the budget of a scope
+func (s *AIBudgetStore) Delete(userID, traderID string) error {
+	return s.db.Where("user_id = ? AND trader_id = ?", userID, traderID).Delete(&AIBudget{}).Error
+}
+
+// Evaluate computes the budget state of a trader against today's and this
+// month's AI charges. With an empty traderID only the user-wide budget is
+// evaluated.
+func (s *AIBudgetStore) Evaluate(userID, traderID string) (*AIBudgetStatus, error) {
+	status := &AIBudgetStatus{Level: AIBudgetOK}
+	now := time.Now()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	var degradeFrom *AIBudget
+	if traderID != "" {
+		budget, err := s.Get(userID, traderID)
+		if err != nil {
+			return nil, err
+		}
+		if budget != nil {
+			state, err := s.evaluateScope(budget, "trader", "trader_id = ?", traderID, dayStart, monthStart)
+			if err != nil {
+				return nil, err
+			}
+			status.Trader = state
+			degradeFrom = budget
+		}
+	}
+
+	userBudget, err := s.Get(userID, "")
+	if err != nil {
+		return nil, err
+	}
+	if userBudget != nil {
+		// Charges carry the user, so deleted and relaunched traders keep counting
+		state, err := s.evaluateScope(userBudget, "user", "user_id = ?", userID, dayStart, monthStart)
+		if err != nil {
+			return nil, err
+		}
+		status.User = state
+		if degradeFrom == nil {
+			degradeFrom = userBudget
+		}
+	}
+
+	for _, scope := range []*AIBudgetScopeState{status.Trader, status.User} {
+		if scope == nil || budgetLevelRank(scope.Level) <= budgetLevelRank(status.Level) {
+			continue
+		}
+		status.Level = scope.Level
+		usage, period := scope.Daily, "daily"
+		if usage == nil || usage.Level != scope.Level {
+			usage, period = scope.Monthly, "monthly"
+		}
+		status.Reason = fmt.Sprintf("%s %s budget %.0f%% used ($%.2f of $%.2f)",
+			scope.Scope, period, usage.UsedPct, usage.SpentUSD, usage.LimitUSD)
+	}
+	if degradeFrom != nil {
+		status.DegradeMode = degradeFrom.DegradeMode
+		status.DegradeIntervalMinutes = degradeFrom.DegradeIntervalMinutes
+	}
+	return status, nil
+}
+
+func (s *AIBudgetStore) evaluateScope(budget *AIBudget, scope, where string, arg interface{}, dayStart, monthStart time.Time) (*AIBudgetScopeState, error) {
+	state := &AIBudgetScopeState{Scope: scope, Level: AIBudgetOK}
+	if budget.DailyLimitUSD > 0 {
+		spent, err := s.spentSince(where, arg, dayStart)
+		if err != nil {
+			return nil, err
+		}
+		state.Daily = newBudgetUsage(budget.DailyLimitUSD, spent)
+	}
+	if budget.MonthlyLimitUSD > 0 {
+		spent, err := s.spentSince(where, arg, monthStart)
+		if err != nil {
+			return nil, err
+		}
+		state.Monthly = newBudgetUsage(budget.MonthlyLimitUSD, spent)
+	}
+	for _, usage := range []*AIBudgetUsage{state.Daily, state.Monthly} {
+		if usage != nil && budgetLevelRank(usage.Level) > budgetLevelRank(state.Level) {
+			state.Level = usage.Level
+		}
+	}
+	return state, nil
+}
+
+// spentSince sums the AI charges of a scope (trader_id or user_id) since a time
+func (s *AIBudgetStore) spentSince(where string, arg interface{}, since time.Time) (float64, error) {
+	var total float64
+	err := s.db.Model(&AICharge{}).
+		Select("COALESCE(SUM(cost_usd), 0)").
+		Where(where+" AND created_at >= ?", arg, since).
+		Scan(&total).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum AI charges: %w", err)
+	}
+	return total, nil
+}
+
+func newBudgetUsage(limit, spent float64) *AIBudgetUsage {
+	usage := &AIBudgetUsage{LimitUSD: limit, SpentUSD: spent, UsedPct: spent / limit * 100, Level: AIBudgetOK}
+	switch {
+	case spent >= limit:
+		usage.Level = AIBudgetExhausted
+	case spent >= limit*AIBudgetDegradeRatio:
+		usage.Level = AIBudgetDegraded
+	}
+	return usage
+}
+
+func budgetLevelRank(level string) int {
+	switch level {
+	case AIBudgetExhausted:
+		return 2
+	case AIBudgetDegraded:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,99 @@
+package store
+
+import (
+	"testing"
+)
+
+func TestAIBudgetEvaluateLevels(t *testing.T) {
+	st := newTestStore(t)
+	budgets, charges, db := st.AIBudget(), st.AICharge(), st.GormDB()
+	db.Create(&Trader{ID: "t1", UserID: "u1", Name: "A", AIModelID: "m", ExchangeID: "e"})
+	db.Create(&Trader{ID: "t2", UserID: "u1", Name: "B", AIModelID: "m", ExchangeID: "e"})
+
+	if err := budgets.Set(&AIBudget{UserID: "u1", TraderID: "t1", DailyLimitUSD: 1}); err != nil {
+		t.Fatalf("set trader budget: %v", err)
+	}
+	if err := budgets.Set(&AIBudget{UserID: "u1", MonthlyLimitUSD: 2, DegradeMode: AIBudgetDegradeInterval}); err != nil {
+		t.Fatalf("set user budget: %v", err)
+	}
+
+	status, err := budgets.Evaluate("u1", "t1")
+	if err != nil || status.Level != AIBudgetOK {
+		t.Fatalf("no spend: status=%+v err=%v", status, err)
+	}
+	if status.DegradeMode != AIBudgetDegradeModel {
+		t.Errorf("degrade mode = %q, want the trader budget's default", status.DegradeMode)
+	}
+
+	// 0.85 of the trader's daily $1 → degraded
+	charges.RecordWithCost("t1", "deepseek", "deepseek", 0.85)
+	if status, _ = budgets.Evaluate("u1", "t1"); status.Level != AIBudgetDegraded || status.Trader.Daily.Level != AIBudgetDegraded {
+		t.Fatalf("85%% spent: %+v", status)
+	}
+
+	// Another trader pushes the user's monthly $2 over the limit
+	charges.RecordWithCost("t2", "deepseek", "deepseek", 1.2)
+	status, _ = budgets.Evaluate("u1", "t1")
+	if status.Level != AIBudgetExhausted || status.User.Monthly.SpentUSD < 2 {
+		t.Fatalf("user budget spent: %+v", status)
+	}
+	if status.Reason == "" {
+		t.Error("expected a reason for the exhausted budget")
+	}
+
+	// A trader without its own budget still answers to the user budget
+	if status, _ = budgets.Evaluate("u1", "t2"); status.Level != AIBudgetExhausted || status.Trader != nil {
+		t.Fatalf("t2 status: %+v", status)
+	}
+}
+
+func TestAIBudgetUserScopeKeepsDeletedTraderSpend(t *testing.T) {
+	st := newTestStore(t)
+	budgets, charges, db := st.AIBudget(), st.AICharge(), st.GormDB()
+	db.Create(&Trader{ID: "t1", UserID: "u1", Name: "A", AIModelID: "m", ExchangeID: "e"})
+	if err := budgets.Set(&AIBudget{UserID: "u1", DailyLimitUSD: 1}); err != nil {
+		t.Fatalf("set user budget: %v", err)
+	}
+	charges.RecordWithCost("t1", "deepseek", "deepseek", 1.5)
+
+	// Deleting the trader, or relaunching it as a new trader, keeps the spend
+	db.Where("id = ?", "t1").Delete(&Trader{})
+	db.Create(&Trader{ID: "t2", UserID: "u1", Name: "A relaunched", AIModelID: "m", ExchangeID: "e"})
+	status, err := budgets.Evaluate("u1", "t2")
+	if err != nil || status.Level != AIBudgetExhausted || status.User.Daily.SpentUSD != 1.5 {
+		t.Fatalf("status after deleting the trader = %+v, err=%v", status, err)
+	}
+
+	// A failed sum is an error, not an untouched budget
+	if err := db.Migrator().DropTable(&AICharge{}); err != nil {
+		t.Fatalf("drop charges: %v", err)
+	}
+	if _, err := budgets.Evaluate("u1", "t2"); err == nil {
+		t.Fatal("expected an error when the charges cannot be summed")
+	}
+}
+
+func TestAIBudgetSetReplacesAndValidates(t *testing.T) {
+	budgets := newTestStore(t).AIBudget()
+
+	if err := budgets.Set(&AIBudget{UserID: "u1", DailyLimitUSD: -1}); err == nil {
+		t.Error("expected negative limit to be rejected")
+	}
+	if err := budgets.Set(&AIBudget{UserID: "u1", DegradeMode: "panic"}); err == nil {
+		t.Error("expected unknown degrade mode to be rejected")
+	}
+
+	budgets.Set(&AIBudget{UserID: "u1", DailyLimitUSD: 5})
+	budgets.Set(&AIBudget{UserID: "u1", DailyLimitUSD: 7})
+	list, err := budgets.List("u1")
+	if err != nil || len(list) != 1 || list[0].DailyLimitUSD != 7 {
+		t.Fatalf("list after replace: %+v err=%v", list, err)
+	}
+
+	if err := budgets.Delete("u1", ""); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if budget, err := budgets.Get("u1", ""); budget != nil || err != nil {
+		t.Fatalf("get after delete: %+v err=%v", budget, err)
+	}
+}
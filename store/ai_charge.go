@@ -1,19 +1,36 @@
 package store
 
 import (
+	"sort"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+// AI charge cost sources (AICharge.CostSource)
+const (
+	AICostSettled = "settled" // amount settled by the payment gateway
+	AICostTokens  = "tokens"  // token usage × model token price
+	AICostFlat    = "flat"    // flat per-call estimate (no usage reported)
+)
+
 // AICharge represents a single AI call charge record
 type AICharge struct {
 	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
 	TraderID  string    `gorm:"column:trader_id;not null;index:idx_ai_charges_trader" json:"trader_id"`
+	UserID    string    `gorm:"column:user_id;not null;default:'';index:idx_ai_charges_user" json:"user_id"` // owner when charged, kept after the trader is deleted
 	Model     string    `gorm:"column:model;not null" json:"model"`
 	Provider  string    `gorm:"column:provider;not null" json:"provider"`
 	CostUSD   float64   `gorm:"column:cost_usd;not null" json:"cost_usd"`
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	// Token usage reported by the provider (zero when it reported none)
+	CycleNumber      int    `gorm:"column:cycle_number;default:0" json:"cycle_number"` // decision cycle of the call, 0 = unknown
+	PromptTokens     int    `gorm:"column:prompt_tokens;default:0" json:"prompt_tokens"`
+	CompletionTokens int    `gorm:"column:completion_tokens;default:0" json:"completion_tokens"`
+	CachedTokens     int    `gorm:"column:cached_tokens;default:0" json:"cached_tokens"`
+	CacheWriteTokens int    `gorm:"column:cache_write_tokens;default:0" json:"cache_write_tokens"`
+	CostSource       string `gorm:"column:cost_source;default:''" json:"cost_source"`
 }
 
 func (AICharge) TableName() string { return "ai_charges" }
@@ -90,12 +107,32 @@ func NewAIChargeStore(db *gorm.DB) *AIChargeStore {
 }
 
 func (s *AIChargeStore) initTables() error {
-	return s.db.AutoMigrate(&AICharge{})
+	return s.db.AutoMigrate(&AICharge{}, &AIModelPrice{})
+}
+
+// RecordCharge records a charge with its token usage and cost source. A
+// charge without a UserID is credited to the trader's current owner; the
+// charge is recorded even when the owner cannot be looked up.
+func (s *AIChargeStore) RecordCharge(charge *AICharge) error {
+	if charge.UserID == "" {
+		var owners []string
+		s.db.Model(&Trader{}).Where("id = ?", charge.TraderID).Limit(1).Pluck("user_id", &owners)
+		if len(owners) > 0 {
+			charge.UserID = owners[0]
+		}
+	}
+	return s.db.Create(charge).Error
 }
 
 // Record records a new AI charge
 func (s *AIChargeStore) Record(traderID, model, provider string) error {
-	return s.RecordWithCost(traderID, model, provider, GetModelPrice(model))
+	return s.RecordCharge(&AICharge{
+		TraderID:   traderID,
+		Model:      model,
+		Provider:   provider,
+		CostUSD:    GetModelPrice(model),
+		CostSource: AICostFlat,
+	})
 }
 
 // RecordWithCost records a charge with an explicit cost — e.g. the actual
@@ -103,12 +140,13 @@ func (s *AIChargeStore) Record(traderID, model, provider string) error {
 // the flat per-call estimate from modelPrices.
 func (s *AIChargeStore) RecordWithCost(traderID, model, provider string, costUSD float64) error {
 	charge := &AICharge{
-		TraderID: traderID,
-		Model:    model,
-		Provider: provider,
-		CostUSD:  costUSD,
+		TraderID:   traderID,
+		Model:      model,
+		Provider:   provider,
+		CostUSD:    costUSD,
+		CostSource: AICostSettled,
 	}
-	return s.db.Create(charge).Error
+	return s.RecordCharge(charge)
 }
 
 // GetCharges returns charges for a trader within a period, plus total cost
@@ -136,15 +174,15 @@ func (s *AIChargeStore) GetDailyCost(period string) float64 {
 	return total
 }
 
-// GetSummary returns summary stats for a period
-func (s *AIChargeStore) GetSummary(period string) (total float64, count int64, byModel map[string]float64) {
+// GetSummary returns summary stats of the traders' charges for a period
+func (s *AIChargeStore) GetSummary(traderIDs []string, period string) (total float64, count int64, byModel map[string]float64) {
 	byModel = make(map[string]float64)
 
-	query := s.db.Model(&AICharge{})
+	query := s.db.Model(&AICharge{}).Where("trader_id IN ?", traderIDs)
 	query = applyPeriodFilter(query, period)
 	query.Count(&count)
 
-	query2 := s.db.Model(&AICharge{}).Select("COALESCE(SUM(cost_usd), 0)")
+	query2 := s.db.Model(&AICharge{}).Select("COALESCE(SUM(cost_usd), 0)").Where("trader_id IN ?", traderIDs)
 	query2 = applyPeriodFilter(query2, period)
 	query2.Scan(&total)
 
@@ -154,7 +192,7 @@ func (s *AIChargeStore) GetSummary(period string) (total float64, count int64, b
 		Total float64 `gorm:"column:total"`
 	}
 	var results []modelCost
-	query3 := s.db.Model(&AICharge{}).Select("model, SUM(cost_usd) as total").Group("model")
+	query3 := s.db.Model(&AICharge{}).Select("model, SUM(cost_usd) as total").Where("trader_id IN ?", traderIDs).Group("model")
 	query3 = applyPeriodFilter(query3, period)
 	query3.Find(&results)
 	for _, r := range results {
@@ -164,6 +202,99 @@ func (s *AIChargeStore) GetSummary(period string) (total float64, count int64, b
 	return total, count, byModel
 }
 
+// AIChargeTokens token and cost totals of a set of charges
+type AIChargeTokens struct {
+	Calls            int64   `gorm:"column:calls" json:"calls"`
+	CostUSD          float64 `gorm:"column:cost_usd" json:"cost_usd"`
+	PromptTokens     int64   `gorm:"column:prompt_tokens" json:"prompt_tokens"`
+	CompletionTokens int64   `gorm:"column:completion_tokens" json:"completion_tokens"`
+	CachedTokens     int64   `gorm:"column:cached_tokens" json:"cached_tokens"`
+	CacheWriteTokens int64   `gorm:"column:cache_write_tokens" json:"cache_write_tokens"`
+	CacheHitRate     float64 `gorm:"-" json:"cache_hit_rate"` // share of prompt tokens read from a prompt cache
+}
+
+func (t *AIChargeTokens) setCacheHitRate() {
+	t.CacheHitRate = 0
+	if t.PromptTokens > 0 {
+		t.CacheHitRate = float64(t.CachedTokens) / float64(t.PromptTokens)
+	}
+}
+
+// AICycleCost AI cost of one decision cycle
+type AICycleCost struct {
+	CycleNumber int       `gorm:"column:cycle_number" json:"cycle_number"`
+	StartedAt   time.Time `gorm:"column:started_at" json:"started_at"`
+	AIChargeTokens
+}
+
+const chargeTokenColumns = "COUNT(*) AS calls, COALESCE(SUM(cost_usd), 0) AS cost_usd, " +
+	"COALESCE(SUM(prompt_tokens), 0) AS prompt_tokens, COALESCE(SUM(completion_tokens), 0) AS completion_tokens, " +
+	"COALESCE(SUM(cached_tokens), 0) AS cached_tokens, COALESCE(SUM(cache_write_tokens), 0) AS cache_write_tokens"
+
+// GetTokenTotals returns token and cost totals of the traders' charges for a
+// period
+func (s *AIChargeStore) GetTokenTotals(traderIDs []string, period string) AIChargeTokens {
+	var totals AIChargeTokens
+	query := s.db.Model(&AICharge{}).Select(chargeTokenColumns).Where("trader_id IN ?", traderIDs)
+	applyPeriodFilter(query, period).Scan(&totals)
+	totals.setCacheHitRate()
+	return totals
+}
+
+// GetTokensByModel returns token and cost totals per model of the traders'
+// charges for a period
+func (s *AIChargeStore) GetTokensByModel(traderIDs []string, period string) map[string]AIChargeTokens {
+	var rows []struct {
+		Model string `gorm:"column:model"`
+		AIChargeTokens
+	}
+	query := s.db.Model(&AICharge{}).Select("model, "+chargeTokenColumns).Where("trader_id IN ?", traderIDs).Group("model")
+	applyPeriodFilter(query, period).Scan(&rows)
+
+	byModel := make(map[string]AIChargeTokens, len(rows))
+	for _, r := range rows {
+		r.setCacheHitRate()
+		byModel[r.Model] = r.AIChargeTokens
+	}
+	return byModel
+}
+
+// GetCycleCosts returns the AI cost of each decision cycle of a trader in a
+// period, newest first. Charges recorded before cycle tracking are skipped.
+func (s *AIChargeStore) GetCycleCosts(traderID, period string) ([]AICycleCost, error) {
+	// Aggregated in Go: MIN(created_at) comes back as text on sqlite
+	var charges []AICharge
+	query := s.db.Where("trader_id = ? AND cycle_number > 0", traderID).Order("created_at ASC")
+	if err := applyPeriodFilter(query, period).Find(&charges).Error; err != nil {
+		return nil, err
+	}
+
+	byCycle := make(map[int]*AICycleCost)
+	var cycles []*AICycleCost
+	for _, c := range charges {
+		cycle, ok := byCycle[c.CycleNumber]
+		if !ok {
+			cycle = &AICycleCost{CycleNumber: c.CycleNumber, StartedAt: c.CreatedAt}
+			byCycle[c.CycleNumber] = cycle
+			cycles = append(cycles, cycle)
+		}
+		cycle.Calls++
+		cycle.CostUSD += c.CostUSD
+		cycle.PromptTokens += int64(c.PromptTokens)
+		cycle.CompletionTokens += int64(c.CompletionTokens)
+		cycle.CachedTokens += int64(c.CachedTokens)
+		cycle.CacheWriteTokens += int64(c.CacheWriteTokens)
+	}
+
+	result := make([]AICycleCost, 0, len(cycles))
+	for _, c := range cycles {
+		c.setCacheHitRate()
+		result = append(result, *c)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CycleNumber > result[j].CycleNumber })
+	return result, nil
+}
+
 func applyPeriodFilter(query *gorm.DB, period string) *gorm.DB {
 	now := time.Now()
 	switch period {
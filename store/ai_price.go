@@ -0,0 +1,163 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TokenPrice USD per 1M tokens of a model. Zero cache prices are charged at
+// the input price.
+type TokenPrice struct {
+	InputPerMTok       float64 `json:"input_per_mtok"`
+	OutputPerMTok      float64 `json:"output_per_mtok"`
+	CachedInputPerMTok float64 `json:"cached_input_per_mtok"` // prompt tokens read from cache
+	CacheWritePerMTok  float64 `json:"cache_write_per_mtok"`  // prompt tokens written to cache (Anthropic)
+}
+
+// Cost returns the USD cost of a call. promptTokens includes cached and
+// cache-write tokens, as in mcp.TokenUsage.
+func (p TokenPrice) Cost(promptTokens, completionTokens, cachedTokens, cacheWriteTokens int) float64 {
+	cachedPrice, writePrice := p.CachedInputPerMTok, p.CacheWritePerMTok
+	if cachedPrice <= 0 {
+		cachedPrice = p.InputPerMTok
+	}
+	if writePrice <= 0 {
+		writePrice = p.InputPerMTok
+	}
+	uncached := promptTokens - cachedTokens - cacheWriteTokens
+	if uncached < 0 {
+		uncached = 0
+	}
+	return (float64(uncached)*p.InputPerMTok +
+		float64(cachedTokens)*cachedPrice +
+		float64(cacheWriteTokens)*writePrice +
+		float64(completionTokens)*p.OutputPerMTok) / 1e6
+}
+
+// nativeTokenPrices list prices of models called with the user's own API key.
+// Models missing here fall back to the claw402 table (modelTokenPrices).
+var nativeTokenPrices = map[string]TokenPrice{
+	"deepseek-chat":     {0.27, 1.1, 0.07, 0},
+	"deepseek-reasoner": {0.55, 2.19, 0.14, 0},
+	"gpt-4o":            {2.5, 10, 1.25, 0},
+	"gpt-4o-mini":       {0.15, 0.6, 0.075, 0},
+	"gpt-4.1":           {2, 8, 0.5, 0},
+	"gpt-4.1-mini":      {0.4, 1.6, 0.1, 0},
+	"claude-sonnet-4":   {3, 15, 0.3, 3.75},
+	"claude-3-5-haiku":  {0.8, 4, 0.08, 1},
+	"gemini-2.5-pro":    {1.25, 10, 0.31, 0},
+	"gemini-2.5-flash":  {0.3, 2.5, 0.075, 0},
+	"grok-3":            {3, 15, 0.75, 0},
+}
+
+// DefaultTokenPrice returns the built-in token price of a model. Dated or
+// suffixed model names (claude-sonnet-4-20250514) match their longest
+// listed prefix.
+func DefaultTokenPrice(model string) (TokenPrice, bool) {
+	if p, ok := lookupTokenPrice(nativeTokenPrices, model); ok {
+		return p, true
+	}
+	gateway := make(map[string]TokenPrice, len(modelTokenPrices))
+	for name, p := range modelTokenPrices {
+		gateway[name] = TokenPrice{InputPerMTok: p.In, OutputPerMTok: p.Out}
+	}
+	return lookupTokenPrice(gateway, model)
+}
+
+func lookupTokenPrice(prices map[string]TokenPrice, model string) (TokenPrice, bool) {
+	if p, ok := prices[model]; ok {
+		return p, true
+	}
+	best := ""
+	for name := range prices {
+		if strings.HasPrefix(model, name+"-") && len(name) > len(best) {
+			best = name
+		}
+	}
+	if best == "" {
+		return TokenPrice{}, false
+	}
+	return prices[best], true
+}
+
+// AIModelPrice a user's token price override for a model
+type AIModelPrice struct {
+	UserID    string    `gorm:"column:user_id;primaryKey" json:"-"`
+	Model     string    `gorm:"column:model;primaryKey" json:"model"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+	TokenPrice
+}
+
+func (AIModelPrice) TableName() string { return "ai_model_prices" }
+
+// ModelPriceEntry a model's effective token price
+type ModelPriceEntry struct {
+	Model  string `json:"model"`
+	Source string `json:"source"` // "default" or "custom"
+	TokenPrice
+}
+
+// ResolveTokenPrice returns the token price used for a user's calls to a
+// model: the user's override, else the built-in price
+func (s *AIChargeStore) ResolveTokenPrice(userID, model string) (TokenPrice, bool) {
+	var override AIModelPrice
+	err := s.db.Where("user_id = ? AND model = ?", userID, model).First(&override).Error
+	if err == nil {
+		return override.TokenPrice, true
+	}
+	return DefaultTokenPrice(model)
+}
+
+// ListPrices returns the built-in price table merged with the user's overrides
+func (s *AIChargeStore) ListPrices(userID string) ([]ModelPriceEntry, error) {
+	entries := make(map[string]ModelPriceEntry)
+	for model := range modelTokenPrices {
+		p, _ := DefaultTokenPrice(model)
+		entries[model] = ModelPriceEntry{Model: model, Source: "default", TokenPrice: p}
+	}
+	for model, p := range nativeTokenPrices {
+		entries[model] = ModelPriceEntry{Model: model, Source: "default", TokenPrice: p}
+	}
+
+	var overrides []AIModelPrice
+	if err := s.db.Where("user_id = ?", userID).Find(&overrides).Error; err != nil {
+		return nil, err
+	}
+	for _, o := range overrides {
+		entries[o.Model] = ModelPriceEntry{Model: o.Model, Source: "custom", TokenPrice: o.TokenPrice}
+	}
+
+	list := make([]ModelPriceEntry, 0, len(entries))
+	for _, e := range entries {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Model < list[j].Model })
+	return list, nil
+}
+
+// SetPrice creates or replaces a user's price override
+func (s *AIChargeStore) SetPrice(price *AIModelPrice) error {
+	if price.Model == "" {
+		return fmt.Errorf("model is required")
+	}
+	if price.InputPerMTok < 0 || price.OutputPerMTok < 0 || price.CachedInputPerMTok < 0 || price.CacheWritePerMTok < 0 {
+		return fmt.Errorf("prices must not be negative")
+	}
+	return s.db.Save(price).Error
+}
+
+// DeletePrice removes a user's price override, restoring the built-in price
+func (s *AIChargeStore) DeletePrice(userID, model string) error {
+	result := s.db.Where("user_id = ? AND model = ?", userID, model).Delete(&AIModelPrice{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
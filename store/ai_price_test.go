@@ -0,0 +1,84 @@
+package store
+
+import (
+	"math"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestTokenPriceCostWithCache(t *testing.T) {
+	price := TokenPrice{InputPerMTok: 3, OutputPerMTok: 15, CachedInputPerMTok: 0.3, CacheWritePerMTok: 3.75}
+	// 1M prompt tokens: 600k cached, 100k cache-write, 300k uncached; 100k completion
+	got := price.Cost(1_000_000, 100_000, 600_000, 100_000)
+	want := 0.3*3 + 0.6*0.3 + 0.1*3.75 + 0.1*15
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("cost = %f, want %f", got, want)
+	}
+
+	// Without cache prices, cached tokens are charged at the input price
+	if got := (TokenPrice{InputPerMTok: 1, OutputPerMTok: 2}).Cost(1_000_000, 0, 500_000, 0); math.Abs(got-1) > 1e-9 {
+		t.Errorf("cost without cache price = %f, want 1", got)
+	}
+}
+
+func TestResolveTokenPriceOverridesAndPrefix(t *testing.T) {
+	st := newTestStore(t).AICharge()
+
+	if p, ok := st.ResolveTokenPrice("u1", "claude-sonnet-4-20250514"); !ok || p.InputPerMTok != 3 {
+		t.Fatalf("dated model should match its prefix: %+v ok=%v", p, ok)
+	}
+	if _, ok := st.ResolveTokenPrice("u1", "unknown-model"); ok {
+		t.Error("unknown model should have no token price")
+	}
+
+	override := &AIModelPrice{UserID: "u1", Model: "deepseek-chat", TokenPrice: TokenPrice{InputPerMTok: 0.1, OutputPerMTok: 0.2}}
+	if err := st.SetPrice(override); err != nil {
+		t.Fatalf("set price: %v", err)
+	}
+	if p, _ := st.ResolveTokenPrice("u1", "deepseek-chat"); p.InputPerMTok != 0.1 {
+		t.Errorf("override not applied: %+v", p)
+	}
+	if p, _ := st.ResolveTokenPrice("u2", "deepseek-chat"); p.InputPerMTok != 0.27 {
+		t.Errorf("override leaked to another user: %+v", p)
+	}
+
+	if err := st.DeletePrice("u1", "deepseek-chat"); err != nil {
+		t.Fatalf("delete price: %v", err)
+	}
+	if err := st.DeletePrice("u1", "deepseek-chat"); err != gorm.ErrRecordNotFound {
+		t.Errorf("second delete err = %v, want not found", err)
+	}
+}
+
+func TestCycleCostsAndTokenTotals(t *testing.T) {
+	st := newTestStore(t).AICharge()
+	st.RecordCharge(&AICharge{TraderID: "t1", Model: "m", Provider: "p", CycleNumber: 1, CostUSD: 0.01, PromptTokens: 1000, CompletionTokens: 100, CachedTokens: 400})
+	st.RecordCharge(&AICharge{TraderID: "t1", Model: "m", Provider: "p", CycleNumber: 2, CostUSD: 0.02, PromptTokens: 2000, CompletionTokens: 200})
+	st.RecordCharge(&AICharge{TraderID: "t1", Model: "m", Provider: "p", CycleNumber: 2, CostUSD: 0.03, PromptTokens: 500})
+	st.Record("t1", "m", "p") // legacy charge without cycle
+	// Another user's trader
+	st.RecordCharge(&AICharge{TraderID: "t2", Model: "m", Provider: "p", CycleNumber: 1, CostUSD: 1, PromptTokens: 9000})
+
+	cycles, err := st.GetCycleCosts("t1", "today")
+	if err != nil {
+		t.Fatalf("cycle costs: %v", err)
+	}
+	if len(cycles) != 2 || cycles[0].CycleNumber != 2 || cycles[0].Calls != 2 || math.Abs(cycles[0].CostUSD-0.05) > 1e-9 {
+		t.Fatalf("cycles = %+v", cycles)
+	}
+
+	totals := st.GetTokenTotals([]string{"t1"}, "today")
+	if totals.Calls != 4 || totals.PromptTokens != 3500 || totals.CachedTokens != 400 || totals.CompletionTokens != 300 {
+		t.Errorf("totals = %+v", totals)
+	}
+	if byModel := st.GetTokensByModel([]string{"t1"}, "today"); byModel["m"].PromptTokens != 3500 {
+		t.Errorf("by model = %+v", byModel)
+	}
+	if total, count, _ := st.GetSummary([]string{"t1"}, "today"); count != 4 || math.Abs(total-totals.CostUSD) > 1e-9 {
+		t.Errorf("summary = %v over %d charges, want t1's %v over 4", total, count, totals.CostUSD)
+	}
+	if none := st.GetTokenTotals(nil, "today"); none.Calls != 0 {
+		t.Errorf("totals without traders = %+v, want none", none)
+	}
+}
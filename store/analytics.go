@@ -0,0 +1,642 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// decisionMatchWindow how far apart a position's entry (exit) and the
+// decision action that opened (closed) it may be. Exchange fill times lag
+// the action timestamp by the order round trip.
+const decisionMatchWindow = 10 * time.Minute
+
+// minAnnualizedYears the shortest equity curve (one week) whose return is
+// annualized for the annualized return and the Calmar ratio
+const minAnnualizedYears = 7.0 / 365
+
+// AnalyticsFilter selects the positions and equity snapshots analyzed
+type AnalyticsFilter struct {
+	TraderIDs        []string  // traders analyzed; the equity curve is the first one's
+	TraderIDPatterns []string  // legacy trader ID patterns (see closedPositionsByTraderFilters)
+	Since            time.Time // positions closed / snapshots taken at or after; zero = unbounded
+	Until            time.Time // positions closed / snapshots taken at or before; zero = now
+	StartingEquity   float64   // account baseline for the trade drawdown; 0 when unknown
+}
+
+// PerformanceAnalytics strategy-level analytics beyond TraderStats
+type PerformanceAnalytics struct {
+	Since             *time.Time       `json:"since,omitempty"`
+	Until             *time.Time       `json:"until,omitempty"`
+	EquityCurve       EquityCurveStats `json:"equity_curve"`
+	Expectancy        float64          `json:"expectancy"` // average net P&L per trade
+	LongestWinStreak  int              `json:"longest_win_streak"`
+	LongestLossStreak int              `json:"longest_loss_streak"`
+	ExposurePct       float64          `json:"exposure_pct"` // share of the period with at least one position open
+	AvgHoldMins       float64          `json:"avg_hold_mins"`
+	Excursions        ExcursionStats   `json:"excursions"`
+	Attribution       Attribution      `json:"attribution"`
+}
+
+// EquityCurveStats risk-adjusted returns of the equity curve. Ratios use
+// daily returns (last snapshot of each UTC day), annualized over 365 days
+// since crypto trades every day, with a zero risk-free rate.
+type EquityCurveStats struct {
+	Snapshots           int     `json:"snapshots"`
+	Days                int     `json:"days"` // daily returns the ratios are based on
+	StartEquity         float64 `json:"start_equity"`
+	EndEquity           float64 `json:"end_equity"`
+	ReturnPct           float64 `json:"return_pct"`
+	AnnualizedReturnPct float64 `json:"annualized_return_pct"`
+	VolatilityPct       float64 `json:"volatility_pct"` // annualized standard deviation of daily returns
+	SharpeRatio         float64 `json:"sharpe_ratio"`
+	SortinoRatio        float64 `json:"sortino_ratio"`
+	CalmarRatio         float64 `json:"calmar_ratio"`
+	MaxDrawdownPct      float64 `json:"max_drawdown_pct"`
+}
+
+// ExcursionStats MAE/MFE over the positions that have an excursion computed
+type ExcursionStats struct {
+	Positions       int            `json:"positions"`
+	AvgMAEPct       float64        `json:"avg_mae_pct"`
+	AvgMFEPct       float64        `json:"avg_mfe_pct"`
+	WinnerAvgMAEPct float64        `json:"winner_avg_mae_pct"` // heat winners took before paying off
+	LoserAvgMFEPct  float64        `json:"loser_avg_mfe_pct"`  // profit losers showed before turning
+	ByPosition      []ExcursionRow `json:"by_position"`
+}
+
+// ExcursionRow the excursion of one position next to what it captured
+type ExcursionRow struct {
+	PositionID  int64   `json:"position_id"`
+	Symbol      string  `json:"symbol"`
+	Side        string  `json:"side"`
+	MAEPct      float64 `json:"mae_pct"`
+	MFEPct      float64 `json:"mfe_pct"`
+	CapturedPct float64 `json:"captured_pct"` // unleveraged price move from entry to exit in the position's favor
+	NetPnL      float64 `json:"net_pnl"`
+}
+
+// AttributionRow trade results of one value of an attribution dimension
+type AttributionRow struct {
+	Key      string  `json:"key"`
+	Trades   int     `json:"trades"`
+	Wins     int     `json:"wins"`
+	WinRate  float64 `json:"win_rate"`
+	TotalPnL float64 `json:"total_pnl"`
+	AvgPnL   float64 `json:"avg_pnl"`
+}
+
+// Attribution trade results broken down per setup dimension
+type Attribution struct {
+	Symbol     []AttributionRow `json:"symbol"`
+	Side       []AttributionRow `json:"side"`
+	HourOfDay  []AttributionRow `json:"hour_of_day"` // UTC hour of entry
+	Confidence []AttributionRow `json:"confidence"`  // AI confidence of the opening decision
+	Leverage   []AttributionRow `json:"leverage"`
+	CoinSource []AttributionRow `json:"coin_source"` // coin source of the strategy that opened the position
+}
+
+// GetPerformanceAnalytics computes trade statistics and strategy analytics
+// for the positions closed in the filter's range. MAE/MFE only cover
+// positions whose excursion has been stored (see SaveExcursion).
+func (s *Store) GetPerformanceAnalytics(filter AnalyticsFilter) (*TraderStats, *PerformanceAnalytics, error) {
+	positions, err := s.Position().closedPositionsInRange(filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	stats := computeTraderStats(positions, filter.StartingEquity)
+
+	analytics := &PerformanceAnalytics{}
+	if !filter.Since.IsZero() {
+		since := filter.Since.UTC()
+		analytics.Since = &since
+	}
+	if !filter.Until.IsZero() {
+		until := filter.Until.UTC()
+		analytics.Until = &until
+	}
+
+	if len(filter.TraderIDs) > 0 {
+		start, end := filter.Since, filter.Until
+		if end.IsZero() {
+			end = time.Now().UTC()
+		}
+		snapshots, err := s.Equity().GetByTimeRange(filter.TraderIDs[0], start, end)
+		if err != nil {
+			return nil, nil, err
+		}
+		analytics.EquityCurve = computeEquityCurveStats(snapshots)
+	}
+
+	if stats.TotalTrades > 0 {
+		analytics.Expectancy = stats.TotalPnL / float64(stats.TotalTrades)
+	}
+	analytics.LongestWinStreak, analytics.LongestLossStreak = longestStreaks(positions)
+	analytics.AvgHoldMins, analytics.ExposurePct = holdAndExposure(positions, filter.Since, filter.Until)
+
+	ids := make([]int64, len(positions))
+	for i, pos := range positions {
+		ids[i] = pos.ID
+	}
+	excursions, err := s.Position().GetExcursions(ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	analytics.Excursions = computeExcursionStats(positions, excursions)
+
+	openings, err := s.linkDecisions(positions, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	analytics.Attribution = s.computeAttribution(positions, openings)
+
+	return stats, analytics, nil
+}
+
+// closedPositionsInRange closed positions of the filter's traders by exit
+// time, oldest first
+func (s *PositionStore) closedPositionsInRange(filter AnalyticsFilter) ([]TraderPosition, error) {
+	query := s.closedPositionsByTraderFilters(filter.TraderIDs, filter.TraderIDPatterns)
+	if !filter.Since.IsZero() {
+		query = query.Where("exit_time >= ?", filter.Since.UnixMilli())
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where("exit_time <= ?", filter.Until.UnixMilli())
+	}
+	var positions []TraderPosition
+	if err := query.Order("exit_time ASC").Find(&positions).Error; err != nil {
+		return nil, fmt.Errorf("failed to query position statistics: %w", err)
+	}
+	return positions, nil
+}
+
+// computeEquityCurveStats computes the return ratios of equity snapshots
+// ordered by time
+func computeEquityCurveStats(snapshots []*EquitySnapshot) EquityCurveStats {
+	stats := EquityCurveStats{Snapshots: len(snapshots)}
+	if len(snapshots) == 0 {
+		return stats
+	}
+	first, last := snapshots[0], snapshots[len(snapshots)-1]
+	stats.StartEquity = first.TotalEquity
+	stats.EndEquity = last.TotalEquity
+
+	peak := 0.0
+	for _, snap := range snapshots {
+		peak = max(peak, snap.TotalEquity)
+		if peak > 0 {
+			stats.MaxDrawdownPct = max(stats.MaxDrawdownPct, (peak-snap.TotalEquity)/peak*100)
+		}
+	}
+	if stats.StartEquity <= 0 {
+		return stats
+	}
+	stats.ReturnPct = (stats.EndEquity/stats.StartEquity - 1) * 100
+
+	// Daily closes: the last snapshot of each UTC day
+	var closes []float64
+	var day string
+	for _, snap := range snapshots {
+		d := snap.Timestamp.UTC().Format("2006-01-02")
+		if d != day || len(closes) == 0 {
+			closes = append(closes, snap.TotalEquity)
+			day = d
+			continue
+		}
+		closes[len(closes)-1] = snap.TotalEquity
+	}
+	returns := make([]float64, 0, len(closes))
+	for i := 1; i < len(closes); i++ {
+		if closes[i-1] > 0 {
+			returns = append(returns, closes[i]/closes[i-1]-1)
+		}
+	}
+	stats.Days = len(returns)
+
+	// Annualizing a few days of returns compounds noise into absurd figures
+	years := last.Timestamp.Sub(first.Timestamp).Hours() / 24 / 365
+	if years >= minAnnualizedYears && stats.EndEquity > 0 {
+		stats.AnnualizedReturnPct = (math.Pow(stats.EndEquity/stats.StartEquity, 1/years) - 1) * 100
+	}
+	if stats.MaxDrawdownPct > 0 {
+		stats.CalmarRatio = stats.AnnualizedReturnPct / stats.MaxDrawdownPct
+	}
+	if len(returns) < 2 {
+		return stats
+	}
+
+	var sum float64
+	for _, r := range returns {
+		sum += r
+	}
+	mean := sum / float64(len(returns))
+	var variance, downside float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+		if r < 0 {
+			downside += r * r
+		}
+	}
+	stdDev := math.Sqrt(variance / float64(len(returns)-1))
+	downsideDev := math.Sqrt(downside / float64(len(returns)))
+	annualize := math.Sqrt(365)
+
+	stats.VolatilityPct = stdDev * annualize * 100
+	if stdDev > 0 {
+		stats.SharpeRatio = mean / stdDev * annualize
+	}
+	if downsideDev > 0 {
+		stats.SortinoRatio = mean / downsideDev * annualize
+	}
+	return stats
+}
+
+// longestStreaks longest runs of winning and losing trades in exit order;
+// break-even trades end both
+func longestStreaks(positions []TraderPosition) (wins, losses int) {
+	var w, l int
+	for _, pos := range positions {
+		switch pnl := pos.NetPnL(); {
+		case pnl > 0:
+			w, l = w+1, 0
+		case pnl < 0:
+			w, l = 0, l+1
+		default:
+			w, l = 0, 0
+		}
+		wins, losses = max(wins, w), max(losses, l)
+	}
+	return wins, losses
+}
+
+// holdAndExposure average hold in minutes and the percent of the period
+// (since..until, or first entry..last exit when unbounded) during which at
+// least one of the positions was open
+func holdAndExposure(positions []TraderPosition, since, until time.Time) (avgHoldMins, exposurePct float64) {
+	type span struct{ start, end int64 }
+	spans := make([]span, 0, len(positions))
+	var totalHold int64
+	for _, pos := range positions {
+		if pos.ExitTime <= pos.EntryTime {
+			continue
+		}
+		totalHold += pos.ExitTime - pos.EntryTime
+		spans = append(spans, span{pos.EntryTime, pos.ExitTime})
+	}
+	if len(spans) == 0 {
+		return 0, 0
+	}
+	avgHoldMins = float64(totalHold) / float64(len(spans)) / 60000.0
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	periodStart, periodEnd := spans[0].start, spans[0].end
+	for _, sp := range spans {
+		periodEnd = max(periodEnd, sp.end)
+	}
+	if !since.IsZero() {
+		periodStart = since.UnixMilli()
+	}
+	if !until.IsZero() {
+		periodEnd = until.UnixMilli()
+	}
+	if periodEnd <= periodStart {
+		return avgHoldMins, 0
+	}
+
+	var covered int64
+	curStart, curEnd := int64(-1), int64(-1)
+	flush := func() {
+		if curEnd > curStart {
+			covered += min(curEnd, periodEnd) - max(curStart, periodStart)
+		}
+	}
+	for _, sp := range spans {
+		start, end := max(sp.start, periodStart), min(sp.end, periodEnd)
+		if end <= start {
+			continue
+		}
+		if curEnd < 0 || start > curEnd {
+			flush()
+			curStart, curEnd = start, end
+			continue
+		}
+		curEnd = max(curEnd, end)
+	}
+	flush()
+	return avgHoldMins, float64(covered) / float64(periodEnd-periodStart) * 100
+}
+
+// computeExcursionStats averages the stored excursions of the positions
+func computeExcursionStats(positions []TraderPosition, excursions map[int64]*PositionExcursion) ExcursionStats {
+	stats := ExcursionStats{ByPosition: []ExcursionRow{}}
+	var winners, losers int
+	for _, pos := range positions {
+		e, ok := excursions[pos.ID]
+		if !ok || e.Timeframe == ExcursionUnavailable {
+			continue
+		}
+		row := ExcursionRow{
+			PositionID: pos.ID,
+			Symbol:     pos.Symbol,
+			Side:       strings.ToLower(pos.Side),
+			MAEPct:     e.MAEPct,
+			MFEPct:     e.MFEPct,
+			NetPnL:     pos.NetPnL(),
+		}
+		if pos.EntryPrice > 0 {
+			row.CapturedPct = (pos.ExitPrice - pos.EntryPrice) / pos.EntryPrice * 100
+			if row.Side == "short" {
+				row.CapturedPct = -row.CapturedPct
+			}
+		}
+		stats.ByPosition = append(stats.ByPosition, row)
+		stats.AvgMAEPct += e.MAEPct
+		stats.AvgMFEPct += e.MFEPct
+		if row.NetPnL > 0 {
+			winners++
+			stats.WinnerAvgMAEPct += e.MAEPct
+		} else if row.NetPnL < 0 {
+			losers++
+			stats.LoserAvgMFEPct += e.MFEPct
+		}
+	}
+	stats.Positions = len(stats.ByPosition)
+	if stats.Positions > 0 {
+		stats.AvgMAEPct /= float64(stats.Positions)
+		stats.AvgMFEPct /= float64(stats.Positions)
+	}
+	if winners > 0 {
+		stats.WinnerAvgMAEPct /= float64(winners)
+	}
+	if losers > 0 {
+		stats.LoserAvgMFEPct /= float64(losers)
+	}
+	return stats
+}
+
+// matchedDecision the decision action linked to a position
+type matchedDecision struct {
+	Record *DecisionRecord
+	Action DecisionAction
+}
+
+// linkDecisions links positions to the decision actions that opened them
+// or, with closing, closed them. Decisions are looked up under the
+// positions' own trader IDs, so legacy Autopilot history links too.
+func (s *Store) linkDecisions(positions []TraderPosition, closing bool) (map[int64]matchedDecision, error) {
+	var traderIDs []string
+	seen := make(map[string]bool)
+	var start, end int64
+	for _, pos := range positions {
+		at := pos.EntryTime
+		if closing {
+			at = pos.ExitTime
+		}
+		if at <= 0 {
+			continue
+		}
+		if start == 0 || at < start {
+			start = at
+		}
+		end = max(end, at)
+		if !seen[pos.TraderID] {
+			seen[pos.TraderID] = true
+			traderIDs = append(traderIDs, pos.TraderID)
+		}
+	}
+	if len(traderIDs) == 0 {
+		return map[int64]matchedDecision{}, nil
+	}
+	records, err := s.Decision().GetActionRecordsInRange(traderIDs,
+		time.UnixMilli(start).Add(-decisionMatchWindow), time.UnixMilli(end).Add(decisionMatchWindow))
+	if err != nil {
+		return nil, err
+	}
+	return matchDecisionActions(positions, records, closing), nil
+}
+
+// matchDecisionActions links each position to its trader's successful open
+// (or, with closing, close) action for its symbol and side nearest to its
+// entry (exit) time within decisionMatchWindow. An action is linked at most
+// once.
+func matchDecisionActions(positions []TraderPosition, records []*DecisionRecord, closing bool) map[int64]matchedDecision {
+	type candidate struct {
+		record *DecisionRecord
+		action DecisionAction
+		used   bool
+	}
+	byKey := make(map[string][]*candidate)
+	for _, record := range records {
+		for _, action := range record.Decisions {
+			if !action.Success {
+				continue
+			}
+			key := record.TraderID + "|" + strings.ToUpper(action.Symbol) + "|" + action.Action
+			byKey[key] = append(byKey[key], &candidate{record: record, action: action})
+		}
+	}
+
+	prefix := "open_"
+	if closing {
+		prefix = "close_"
+	}
+	result := make(map[int64]matchedDecision)
+	for _, pos := range positions {
+		at := pos.EntryTime
+		if closing {
+			at = pos.ExitTime
+		}
+		if at <= 0 {
+			continue
+		}
+		key := pos.TraderID + "|" + strings.ToUpper(pos.Symbol) + "|" + prefix + strings.ToLower(pos.Side)
+		var best *candidate
+		bestGap := decisionMatchWindow + 1
+		for _, c := range byKey[key] {
+			if c.used {
+				continue
+			}
+			actionAt := c.action.Timestamp
+			if actionAt.IsZero() {
+				actionAt = c.record.Timestamp
+			}
+			gap := time.UnixMilli(at).Sub(actionAt)
+			if gap < 0 {
+				gap = -gap
+			}
+			if gap <= decisionMatchWindow && gap < bestGap {
+				best, bestGap = c, gap
+			}
+		}
+		if best != nil {
+			best.used = true
+			result[pos.ID] = matchedDecision{Record: best.record, Action: best.action}
+		}
+	}
+	return result
+}
+
+// computeAttribution breaks the trades down per setup dimension
+func (s *Store) computeAttribution(positions []TraderPosition, openings map[int64]matchedDecision) Attribution {
+	symbol, side, hour := newAttributionGroup(), newAttributionGroup(), newAttributionGroup()
+	confidence, leverage, coinSource := newAttributionGroup(), newAttributionGroup(), newAttributionGroup()
+	sources := make(map[string]string)
+
+	for _, pos := range positions {
+		pnl := pos.NetPnL()
+		symbol.add(pos.Symbol, pnl)
+		side.add(strings.ToLower(pos.Side), pnl)
+		hour.add(time.UnixMilli(pos.EntryTime).UTC().Format("15"), pnl)
+		leverage.add(leverageBucket(pos.Leverage), pnl)
+
+		opening, ok := openings[pos.ID]
+		if !ok {
+			confidence.add("unknown", pnl)
+			coinSource.add("unknown", pnl)
+			continue
+		}
+		confidence.add(confidenceBucket(opening.Action.Confidence), pnl)
+
+		key := fmt.Sprintf("%s@%d", opening.Record.StrategyID, opening.Record.StrategyVersion)
+		src, cached := sources[key]
+		if !cached {
+			src = s.Strategy().coinSourceAt(opening.Record.StrategyID, opening.Record.StrategyVersion)
+			sources[key] = src
+		}
+		coinSource.add(src, pnl)
+	}
+
+	return Attribution{
+		Symbol:     symbol.rows(nil),
+		Side:       side.rows(nil),
+		HourOfDay:  hour.rows(hourBuckets),
+		Confidence: confidence.rows(confidenceBuckets),
+		Leverage:   leverage.rows(leverageBuckets),
+		CoinSource: coinSource.rows(nil),
+	}
+}
+
+// coinSourceAt the coin source type of a strategy at a version, falling back
+// to its current config; "unknown" when neither can be read
+func (s *StrategyStore) coinSourceAt(strategyID string, version int) string {
+	if strategyID == "" {
+		return "unknown"
+	}
+	var config string
+	if version > 0 {
+		var v StrategyVersion
+		if err := s.db.Where("strategy_id = ? AND version = ?", strategyID, version).First(&v).Error; err == nil {
+			config = v.Config
+		}
+	}
+	if config == "" {
+		var st Strategy
+		if err := s.db.Where("id = ?", strategyID).First(&st).Error; err != nil {
+			return "unknown"
+		}
+		config = st.Config
+	}
+	var parsed StrategyConfig
+	if err := json.Unmarshal([]byte(config), &parsed); err != nil {
+		return "unknown"
+	}
+	parsed.NormalizeProductSchema()
+	if parsed.CoinSource.SourceType == "" {
+		return "unknown"
+	}
+	return parsed.CoinSource.SourceType
+}
+
+// Bucket orders of the ordinal attribution dimensions
+var (
+	confidenceBuckets = []string{"<60", "60-69", "70-79", "80-89", "90+", "unknown"}
+	leverageBuckets   = []string{"1x", "2-3x", "4-5x", "6-10x", "11-20x", ">20x"}
+	hourBuckets       = func() []string {
+		hours := make([]string, 24)
+		for h := range hours {
+			hours[h] = fmt.Sprintf("%02d", h)
+		}
+		return hours
+	}()
+)
+
+// confidenceBucket groups AI confidence (0-100) in tens from 60 up
+func confidenceBucket(confidence int) string {
+	switch {
+	case confidence <= 0:
+		return "unknown"
+	case confidence < 60:
+		return "<60"
+	case confidence >= 90:
+		return "90+"
+	default:
+		lo := confidence / 10 * 10
+		return fmt.Sprintf("%d-%d", lo, lo+9)
+	}
+}
+
+// leverageBucket groups leverage into the ranges strategies usually configure
+func leverageBucket(leverage int) string {
+	switch {
+	case leverage <= 1:
+		return "1x"
+	case leverage <= 3:
+		return "2-3x"
+	case leverage <= 5:
+		return "4-5x"
+	case leverage <= 10:
+		return "6-10x"
+	case leverage <= 20:
+		return "11-20x"
+	default:
+		return ">20x"
+	}
+}
+
+type attributionGroup map[string]*AttributionRow
+
+func newAttributionGroup() attributionGroup {
+	return make(attributionGroup)
+}
+
+func (g attributionGroup) add(key string, pnl float64) {
+	row, ok := g[key]
+	if !ok {
+		row = &AttributionRow{Key: key}
+		g[key] = row
+	}
+	row.Trades++
+	row.TotalPnL += pnl
+	if pnl > 0 {
+		row.Wins++
+	}
+}
+
+// rows the finished rows in bucket order, or by total P&L descending when
+// the dimension has no order
+func (g attributionGroup) rows(order []string) []AttributionRow {
+	rows := make([]AttributionRow, 0, len(g))
+	for _, row := range g {
+		row.WinRate = float64(row.Wins) / float64(row.Trades) * 100
+		row.AvgPnL = row.TotalPnL / float64(row.Trades)
+		rows = append(rows, *row)
+	}
+	rank := make(map[string]int, len(order))
+	for i, key := range order {
+		rank[key] = i + 1
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		ri, rj := rank[rows[i].Key], rank[rows[j].Key]
+		if ri != rj {
+			return ri > 0 && (rj == 0 || ri < rj)
+		}
+		if order == nil && rows[i].TotalPnL != rows[j].TotalPnL {
+			return rows[i].TotalPnL > rows[j].TotalPnL
+		}
+		return rows[i].Key < rows[j].Key
+	})
+	return rows
+}
@@ -0,0 +1,193 @@
+package store
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestComputeExcursion(t *testing.T) {
+	mae, mfe := ComputeExcursion("long", 100, 95, 110)
+	if mae != 5 || mfe != 10 {
+		t.Fatalf("long: mae=%v mfe=%v, want 5 and 10", mae, mfe)
+	}
+	mae, mfe = ComputeExcursion("SHORT", 100, 95, 110)
+	if mae != 10 || mfe != 5 {
+		t.Fatalf("short: mae=%v mfe=%v, want 10 and 5", mae, mfe)
+	}
+	// A long that never traded below its entry took no heat
+	if mae, _ = ComputeExcursion("long", 100, 101, 110); mae != 0 {
+		t.Fatalf("mae = %v, want 0", mae)
+	}
+}
+
+func TestComputeEquityCurveStats(t *testing.T) {
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var snapshots []*EquitySnapshot
+	for i, equity := range []float64{1000, 1100, 990, 1089, 1200, 1150, 1210, 1300} {
+		// Two snapshots a day; only the later one closes the day
+		snapshots = append(snapshots,
+			&EquitySnapshot{Timestamp: day.AddDate(0, 0, i).Add(time.Hour), TotalEquity: equity - 50},
+			&EquitySnapshot{Timestamp: day.AddDate(0, 0, i).Add(20 * time.Hour), TotalEquity: equity},
+		)
+	}
+	stats := computeEquityCurveStats(snapshots)
+
+	if stats.Days != 7 {
+		t.Fatalf("days = %d, want 7 daily returns", stats.Days)
+	}
+	if math.Abs(stats.ReturnPct-((1300.0/950-1)*100)) > 1e-9 {
+		t.Fatalf("return = %v", stats.ReturnPct)
+	}
+	// Peak 1100 to 940 (the intraday low of day three)
+	if want := (1100.0 - 940) / 1100 * 100; math.Abs(stats.MaxDrawdownPct-want) > 1e-9 {
+		t.Fatalf("max drawdown = %v, want %v", stats.MaxDrawdownPct, want)
+	}
+	if stats.SharpeRatio <= 0 || stats.SortinoRatio <= stats.SharpeRatio {
+		t.Fatalf("sharpe = %v, sortino = %v: a rising curve with few down days should have sortino > sharpe > 0", stats.SharpeRatio, stats.SortinoRatio)
+	}
+	if stats.AnnualizedReturnPct <= 0 || stats.CalmarRatio <= 0 {
+		t.Fatalf("annualized = %v, calmar = %v, want both positive over a week", stats.AnnualizedReturnPct, stats.CalmarRatio)
+	}
+
+	// Under a week no annualized figures are reported
+	short := computeEquityCurveStats(snapshots[:4])
+	if short.AnnualizedReturnPct != 0 || short.CalmarRatio != 0 {
+		t.Fatalf("annualized = %v, calmar = %v over two days, want 0", short.AnnualizedReturnPct, short.CalmarRatio)
+	}
+}
+
+func TestHoldAndExposure(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(h int) int64 { return base.Add(time.Duration(h) * time.Hour).UnixMilli() }
+	positions := []TraderPosition{
+		{EntryTime: at(0), ExitTime: at(2)},
+		{EntryTime: at(1), ExitTime: at(3)}, // overlaps the first
+		{EntryTime: at(6), ExitTime: at(8)},
+	}
+	avgHold, exposure := holdAndExposure(positions, base, base.Add(10*time.Hour))
+	if avgHold != 120 {
+		t.Fatalf("avg hold = %v min, want 120", avgHold)
+	}
+	if math.Abs(exposure-50) > 1e-9 {
+		t.Fatalf("exposure = %v%%, want 50%% (5 of 10 hours)", exposure)
+	}
+}
+
+func TestGetPerformanceAnalytics(t *testing.T) {
+	st := newTestStore(t)
+	db := st.GormDB()
+	base := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	hour := func(h int) time.Time { return base.Add(time.Duration(h) * time.Hour) }
+
+	closed := []struct {
+		symbol, side string
+		leverage     int
+		entry, exit  int
+		pnl          float64
+	}{
+		{"BTCUSDT", "LONG", 5, 1, 3, 40},
+		{"ETHUSDT", "SHORT", 10, 4, 6, -10},
+		{"BTCUSDT", "LONG", 5, 7, 9, -20},
+		{"SOLUSDT", "LONG", 3, 10, 12, -5},
+		{"BTCUSDT", "SHORT", 20, 30, 31, 15}, // closed the next day
+	}
+	var ids []int64
+	for _, c := range closed {
+		pos := &TraderPosition{
+			TraderID: "t1", Symbol: c.symbol, Side: c.side, Quantity: 1, Leverage: c.leverage,
+			EntryPrice: 100, ExitPrice: 101, EntryTime: hour(c.entry).UnixMilli(), ExitTime: hour(c.exit).UnixMilli(),
+			RealizedPnL: c.pnl, Status: "CLOSED",
+		}
+		if err := db.Create(pos).Error; err != nil {
+			t.Fatalf("create position: %v", err)
+		}
+		ids = append(ids, pos.ID)
+	}
+
+	// The first trade was opened by a high-confidence decision two minutes
+	// before its fill; an unrelated symbol must not be linked
+	if err := st.Decision().LogDecision(&DecisionRecord{
+		TraderID: "t1", Timestamp: hour(1).Add(-2 * time.Minute), Success: true,
+		Decisions: []DecisionAction{
+			{Action: "open_long", Symbol: "BTCUSDT", Confidence: 85, Success: true, Timestamp: hour(1).Add(-2 * time.Minute)},
+			{Action: "open_long", Symbol: "XRPUSDT", Confidence: 95, Success: true, Timestamp: hour(1).Add(-2 * time.Minute)},
+		},
+	}); err != nil {
+		t.Fatalf("log decision: %v", err)
+	}
+
+	if err := st.Position().SaveExcursion(&PositionExcursion{PositionID: ids[0], MAEPct: 2, MFEPct: 6, Timeframe: "1m"}); err != nil {
+		t.Fatalf("save excursion: %v", err)
+	}
+	if err := st.Position().SaveExcursion(&PositionExcursion{PositionID: ids[1], Timeframe: ExcursionUnavailable}); err != nil {
+		t.Fatalf("save excursion: %v", err)
+	}
+
+	filter := AnalyticsFilter{TraderIDs: []string{"t1"}, Since: base, Until: base.Add(24*time.Hour - time.Millisecond)}
+	missing, err := st.Position().ClosedPositionsWithoutExcursion(filter, 0)
+	if err != nil {
+		t.Fatalf("positions without excursion: %v", err)
+	}
+	if len(missing) != 2 || missing[0].ID != ids[3] {
+		t.Fatalf("positions without excursion = %+v, want the 3rd and 4th trade newest first", missing)
+	}
+
+	stats, analytics, err := st.GetPerformanceAnalytics(filter)
+	if err != nil {
+		t.Fatalf("GetPerformanceAnalytics: %v", err)
+	}
+	if stats.TotalTrades != 4 {
+		t.Fatalf("total trades = %d, want 4 in range", stats.TotalTrades)
+	}
+	if analytics.Expectancy != (40.0-10-20-5)/4 {
+		t.Fatalf("expectancy = %v", analytics.Expectancy)
+	}
+	if analytics.LongestWinStreak != 1 || analytics.LongestLossStreak != 3 {
+		t.Fatalf("streaks = %d/%d, want 1 win and 3 losses", analytics.LongestWinStreak, analytics.LongestLossStreak)
+	}
+	if analytics.Excursions.Positions != 1 || analytics.Excursions.WinnerAvgMAEPct != 2 {
+		t.Fatalf("excursions = %+v, want only the first trade", analytics.Excursions)
+	}
+
+	rows := func(dim []AttributionRow) map[string]AttributionRow {
+		m := make(map[string]AttributionRow)
+		for _, r := range dim {
+			m[r.Key] = r
+		}
+		return m
+	}
+	confidence := rows(analytics.Attribution.Confidence)
+	if confidence["80-89"].Trades != 1 || confidence["80-89"].TotalPnL != 40 || confidence["unknown"].Trades != 3 {
+		t.Fatalf("confidence attribution = %+v", analytics.Attribution.Confidence)
+	}
+	if btc := rows(analytics.Attribution.Symbol)["BTCUSDT"]; btc.Trades != 2 || btc.Wins != 1 || btc.WinRate != 50 {
+		t.Fatalf("BTCUSDT attribution = %+v", btc)
+	}
+	leverage := analytics.Attribution.Leverage
+	if len(leverage) != 3 || leverage[0].Key != "2-3x" || leverage[2].Key != "6-10x" {
+		t.Fatalf("leverage attribution out of bucket order: %+v", leverage)
+	}
+}
+
+func TestMatchDecisionActionsKeepsTradersApart(t *testing.T) {
+	at := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	positions := []TraderPosition{
+		{ID: 1, TraderID: "bot_u1_claw402_old", Symbol: "BTCUSDT", Side: "LONG", EntryTime: at.UnixMilli()},
+		{ID: 2, TraderID: "bot_u1_claw402_new", Symbol: "BTCUSDT", Side: "LONG", EntryTime: at.UnixMilli()},
+	}
+	// The newer incarnation's decision is closer in time, but belongs to it only
+	records := []*DecisionRecord{
+		{ID: 10, TraderID: "bot_u1_claw402_old", Timestamp: at.Add(-3 * time.Minute), Decisions: []DecisionAction{
+			{Action: "open_long", Symbol: "BTCUSDT", Confidence: 72, Success: true}}},
+		{ID: 20, TraderID: "bot_u1_claw402_new", Timestamp: at.Add(-time.Minute), Decisions: []DecisionAction{
+			{Action: "open_long", Symbol: "BTCUSDT", Confidence: 91, Success: true}}},
+	}
+	matched := matchDecisionActions(positions, records, false)
+	if matched[1].Record == nil || matched[1].Record.ID != 10 {
+		t.Fatalf("old incarnation's position linked to %+v, want decision 10", matched[1].Record)
+	}
+	if matched[2].Record == nil || matched[2].Record.ID != 20 {
+		t.Fatalf("new incarnation's position linked to %+v, want decision 20", matched[2].Record)
+	}
+}
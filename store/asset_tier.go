@@ -0,0 +1,283 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Asset categories a tier rule can match: crypto perps, plus the Hyperliquid
+// XYZ categories (see hyperliquid.XYZCategory)
+const (
+	AssetCategoryCrypto    = "crypto"
+	AssetCategoryStock     = "stock"
+	AssetCategoryCommodity = "commodity"
+	AssetCategoryIndex     = "index"
+	AssetCategoryForex     = "forex"
+	AssetCategoryPreIPO    = "pre_ipo"
+)
+
+// Names of the tiers derived from the flat BTC/ETH and altcoin limits when
+// no asset tiers are configured
+const (
+	LegacyMajorTier   = "btc_eth"
+	LegacyAltcoinTier = "altcoin"
+)
+
+// MaxAssetTiers caps the number of configured tiers (each one is rendered
+// into the system prompt)
+const MaxAssetTiers = 8
+
+var assetCategories = map[string]bool{
+	AssetCategoryCrypto: true, AssetCategoryStock: true, AssetCategoryCommodity: true,
+	AssetCategoryIndex: true, AssetCategoryForex: true, AssetCategoryPreIPO: true,
+}
+
+// AssetTierConfig a user-defined asset tier with its own limits.
+// A symbol belongs to the first tier listing it in Symbols; otherwise to the
+// first tier whose rules (Categories, market cap bounds) all match. A tier
+// with neither symbols nor rules matches everything and is usually last.
+type AssetTierConfig struct {
+	Name string `json:"name"`
+
+	// Explicit assignment, e.g. BTCUSDT, SOL, xyz:TSLA (matched by base asset)
+	Symbols []string `json:"symbols,omitempty"`
+	// Rule: asset categories (crypto, stock, commodity, index, forex, pre_ipo)
+	Categories []string `json:"categories,omitempty"`
+	// Rule: market cap bounds in USD; a symbol with unknown market cap does not match
+	MinMarketCapUSD float64 `json:"min_market_cap_usd,omitempty"`
+	MaxMarketCapUSD float64 `json:"max_market_cap_usd,omitempty"`
+
+	// Exchange leverage for opening positions (AI guided, capped on validation)
+	MaxLeverage int `json:"max_leverage"`
+	// Single position max value = equity × this ratio (CODE ENFORCED)
+	MaxPositionValueRatio float64 `json:"max_position_value_ratio"`
+	// Min position size in USDT, on top of the global minimum (CODE ENFORCED)
+	MinPositionSize float64 `json:"min_position_size,omitempty"`
+	// Max positions held in this tier at once, 0 = global limit only (CODE ENFORCED)
+	MaxPositions int `json:"max_positions,omitempty"`
+}
+
+// AssetInfo what the tier rules know about a symbol
+type AssetInfo struct {
+	Category     string  // AssetCategory*
+	MarketCapUSD float64 // 0 when unknown
+}
+
+// hasRules reports whether the tier assigns symbols by rule
+func (t *AssetTierConfig) hasRules() bool {
+	return len(t.Categories) > 0 || t.UsesMarketCap()
+}
+
+// MatchesAll reports whether the tier is a catch-all (no symbols, no rules)
+func (t *AssetTierConfig) MatchesAll() bool {
+	return len(t.Symbols) == 0 && !t.hasRules()
+}
+
+// UsesMarketCap reports whether the tier has a market cap rule
+func (t *AssetTierConfig) UsesMarketCap() bool {
+	return t.MinMarketCapUSD > 0 || t.MaxMarketCapUSD > 0
+}
+
+// Describe a short summary of the tier's assignment, for prompts and logs
+func (t *AssetTierConfig) Describe() string {
+	var parts []string
+	if len(t.Symbols) > 0 {
+		parts = append(parts, strings.Join(t.Symbols, ", "))
+	}
+	if len(t.Categories) > 0 {
+		parts = append(parts, "category "+strings.Join(t.Categories, "/"))
+	}
+	switch {
+	case t.MinMarketCapUSD > 0 && t.MaxMarketCapUSD > 0:
+		parts = append(parts, fmt.Sprintf("market cap %s-%s", formatUSDShort(t.MinMarketCapUSD), formatUSDShort(t.MaxMarketCapUSD)))
+	case t.MinMarketCapUSD > 0:
+		parts = append(parts, "market cap ≥"+formatUSDShort(t.MinMarketCapUSD))
+	case t.MaxMarketCapUSD > 0:
+		parts = append(parts, "market cap <"+formatUSDShort(t.MaxMarketCapUSD))
+	}
+	if len(parts) == 0 {
+		return "all other symbols"
+	}
+	return strings.Join(parts, "; ")
+}
+
+func (t *AssetTierConfig) matchesRules(info AssetInfo) bool {
+	if len(t.Categories) > 0 {
+		found := false
+		for _, c := range t.Categories {
+			if c == info.Category {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if t.UsesMarketCap() {
+		if info.MarketCapUSD <= 0 {
+			return false
+		}
+		if t.MinMarketCapUSD > 0 && info.MarketCapUSD < t.MinMarketCapUSD {
+			return false
+		}
+		if t.MaxMarketCapUSD > 0 && info.MarketCapUSD >= t.MaxMarketCapUSD {
+			return false
+		}
+	}
+	return true
+}
+
+// EffectiveAssetTiers returns the configured tiers, or when none are
+// configured the legacy two tiers derived from the flat limits: BTC/ETH plus
+// the non-crypto Hyperliquid XYZ assets, and every other symbol as altcoin
+func (r RiskControlConfig) EffectiveAssetTiers() []AssetTierConfig {
+	if len(r.AssetTiers) > 0 {
+		return r.AssetTiers
+	}
+	return []AssetTierConfig{
+		{
+			Name:                  LegacyMajorTier,
+			Symbols:               []string{"BTC", "ETH"},
+			Categories:            []string{AssetCategoryStock, AssetCategoryCommodity, AssetCategoryIndex, AssetCategoryForex, AssetCategoryPreIPO},
+			MaxLeverage:           r.BTCETHMaxLeverage,
+			MaxPositionValueRatio: defaultPositiveRatio(r.BTCETHMaxPositionValueRatio, 5.0),
+		},
+		r.FallbackAssetTier(),
+	}
+}
+
+// FallbackAssetTier the flat altcoin limits, also used for symbols no
+// configured tier matches
+func (r RiskControlConfig) FallbackAssetTier() AssetTierConfig {
+	return AssetTierConfig{
+		Name:                  LegacyAltcoinTier,
+		MaxLeverage:           r.AltcoinMaxLeverage,
+		MaxPositionValueRatio: defaultPositiveRatio(r.AltcoinMaxPositionValueRatio, 1.0),
+	}
+}
+
+// UsesMarketCap reports whether any tier needs the symbol's market cap
+func (r RiskControlConfig) UsesMarketCap() bool {
+	for i := range r.AssetTiers {
+		if r.AssetTiers[i].UsesMarketCap() {
+			return true
+		}
+	}
+	return false
+}
+
+// AssetTierFor resolves the tier of symbol. Symbols matching no configured
+// tier fall back to the flat altcoin limits.
+func (r RiskControlConfig) AssetTierFor(symbol string, info AssetInfo) AssetTierConfig {
+	tiers := r.EffectiveAssetTiers()
+	base := AssetBase(symbol)
+	for _, t := range tiers {
+		for _, s := range t.Symbols {
+			if AssetBase(s) == base {
+				return t
+			}
+		}
+	}
+	for _, t := range tiers {
+		if len(t.Symbols) > 0 && !t.hasRules() {
+			continue // explicit-only tier
+		}
+		if t.MatchesAll() || t.matchesRules(info) {
+			return t
+		}
+	}
+	return r.FallbackAssetTier()
+}
+
+// AssetBase the base asset of an exchange symbol (BTCUSDT, BTC-USDC,
+// xyz:TSLA → BTC, BTC, TSLA), used to match tier symbols across exchanges
+func AssetBase(symbol string) string {
+	base := strings.ToUpper(strings.TrimSpace(symbol))
+	base = strings.TrimPrefix(base, "XYZ:")
+	for _, suffix := range []string{"-USDC", "-USDT", "USDT", "USDC", "-USD"} {
+		if b, ok := strings.CutSuffix(base, suffix); ok && b != "" {
+			return b
+		}
+	}
+	return base
+}
+
+// normalizeAssetTiers cleans up configured tiers: names, symbols and
+// categories are normalized, unknown categories dropped and limits clamped
+// to the product ranges
+func normalizeAssetTiers(tiers []AssetTierConfig) []AssetTierConfig {
+	if len(tiers) == 0 {
+		return nil
+	}
+	if len(tiers) > MaxAssetTiers {
+		tiers = tiers[:MaxAssetTiers]
+	}
+	out := make([]AssetTierConfig, 0, len(tiers))
+	for i, t := range tiers {
+		t.Name = strings.TrimSpace(t.Name)
+		if t.Name == "" {
+			t.Name = fmt.Sprintf("tier_%d", i+1)
+		}
+		t.Symbols = normalizeSymbols(t.Symbols)
+		var categories []string
+		for _, c := range t.Categories {
+			c = strings.ToLower(strings.TrimSpace(c))
+			if assetCategories[c] {
+				categories = append(categories, c)
+			}
+		}
+		t.Categories = categories
+		if t.MinMarketCapUSD < 0 {
+			t.MinMarketCapUSD = 0
+		}
+		if t.MaxMarketCapUSD < 0 {
+			t.MaxMarketCapUSD = 0
+		}
+
+		if t.MaxLeverage < MinLeverage {
+			t.MaxLeverage = MinLeverage
+		}
+		if t.MaxLeverage > MaxAltLeverage {
+			t.MaxLeverage = MaxAltLeverage
+		}
+		if t.MaxPositionValueRatio < MinPositionRatio {
+			t.MaxPositionValueRatio = MinPositionRatio
+		}
+		if t.MaxPositionValueRatio > MaxPositionRatio {
+			t.MaxPositionValueRatio = MaxPositionRatio
+		}
+		if t.MinPositionSize < 0 {
+			t.MinPositionSize = 0
+		}
+		if t.MinPositionSize > MaxPositionSize {
+			t.MinPositionSize = MaxPositionSize
+		}
+		if t.MaxPositions < 0 {
+			t.MaxPositions = 0
+		}
+		if t.MaxPositions > MaxPositions {
+			t.MaxPositions = MaxPositions
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+func defaultPositiveRatio(v, def float64) float64 {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
+
+func formatUSDShort(v float64) string {
+	switch {
+	case v >= 1e9:
+		return fmt.Sprintf("$%gB", v/1e9)
+	case v >= 1e6:
+		return fmt.Sprintf("$%gM", v/1e6)
+	default:
+		return fmt.Sprintf("$%g", v)
+	}
+}
@@ -0,0 +1,95 @@
+package store
+
+import "testing"
+
+func TestAssetTierForLegacyTiers(t *testing.T) {
+	rc := RiskControlConfig{
+		BTCETHMaxLeverage:            10,
+		AltcoinMaxLeverage:           5,
+		BTCETHMaxPositionValueRatio:  5,
+		AltcoinMaxPositionValueRatio: 1,
+	}
+	tests := []struct {
+		symbol   string
+		category string
+		want     string
+	}{
+		{"BTCUSDT", AssetCategoryCrypto, LegacyMajorTier},
+		{"ETH-USDC", AssetCategoryCrypto, LegacyMajorTier},
+		{"xyz:TSLA", AssetCategoryStock, LegacyMajorTier},
+		{"SOLUSDT", AssetCategoryCrypto, LegacyAltcoinTier},
+		{"ETHFIUSDT", AssetCategoryCrypto, LegacyAltcoinTier},
+	}
+	for _, tt := range tests {
+		tier := rc.AssetTierFor(tt.symbol, AssetInfo{Category: tt.category})
+		if tier.Name != tt.want {
+			t.Errorf("%s: got tier %q, want %q", tt.symbol, tier.Name, tt.want)
+		}
+	}
+	if tier := rc.AssetTierFor("BTCUSDT", AssetInfo{Category: AssetCategoryCrypto}); tier.MaxLeverage != 10 || tier.MaxPositionValueRatio != 5 {
+		t.Errorf("BTC tier should carry the BTC/ETH limits, got %+v", tier)
+	}
+}
+
+func TestAssetTierForConfiguredTiers(t *testing.T) {
+	rc := RiskControlConfig{
+		AltcoinMaxLeverage:           3,
+		AltcoinMaxPositionValueRatio: 0.5,
+		AssetTiers: []AssetTierConfig{
+			{Name: "majors", Symbols: []string{"BTCUSDT", "ETHUSDT"}, MaxLeverage: 10, MaxPositionValueRatio: 5},
+			{Name: "equities", Categories: []string{AssetCategoryStock, AssetCategoryIndex}, MaxLeverage: 5, MaxPositionValueRatio: 3},
+			{Name: "large_caps", MinMarketCapUSD: 10e9, MaxLeverage: 5, MaxPositionValueRatio: 2, MaxPositions: 2},
+			{Name: "memes", Symbols: []string{"DOGE", "PEPE"}, Categories: []string{AssetCategoryCrypto}, MaxMarketCapUSD: 1e9, MaxLeverage: 2, MaxPositionValueRatio: 0.5},
+		},
+	}
+	tests := []struct {
+		symbol string
+		info   AssetInfo
+		want   string
+	}{
+		{"BTC-USDC", AssetInfo{Category: AssetCategoryCrypto, MarketCapUSD: 1e12}, "majors"},
+		{"xyz:NVDA", AssetInfo{Category: AssetCategoryStock}, "equities"},
+		{"SOLUSDT", AssetInfo{Category: AssetCategoryCrypto, MarketCapUSD: 80e9}, "large_caps"},
+		{"DOGEUSDT", AssetInfo{Category: AssetCategoryCrypto, MarketCapUSD: 20e9}, "memes"}, // explicit beats rules
+		{"WIFUSDT", AssetInfo{Category: AssetCategoryCrypto, MarketCapUSD: 5e8}, "memes"},
+		{"NEWUSDT", AssetInfo{Category: AssetCategoryCrypto}, LegacyAltcoinTier}, // unknown market cap
+		{"xyz:GOLD", AssetInfo{Category: AssetCategoryCommodity}, LegacyAltcoinTier},
+	}
+	for _, tt := range tests {
+		if tier := rc.AssetTierFor(tt.symbol, tt.info); tier.Name != tt.want {
+			t.Errorf("%s: got tier %q, want %q", tt.symbol, tier.Name, tt.want)
+		}
+	}
+	if fallback := rc.AssetTierFor("NEWUSDT", AssetInfo{}); fallback.MaxLeverage != 3 || fallback.MaxPositionValueRatio != 0.5 {
+		t.Errorf("unmatched symbols should use the altcoin limits, got %+v", fallback)
+	}
+	if !rc.UsesMarketCap() {
+		t.Error("market cap rules should be reported")
+	}
+}
+
+func TestNormalizeAssetTiers(t *testing.T) {
+	cfg := GetDefaultStrategyConfig("en")
+	cfg.RiskControl.AssetTiers = []AssetTierConfig{
+		{Symbols: []string{" btcusdt", "BTCUSDT"}, Categories: []string{"Stock", "bonds"}, MaxLeverage: 50, MaxPositionValueRatio: 0.1, MaxPositions: 20},
+	}
+	before := cfg
+	cfg.ClampLimits()
+
+	tier := cfg.RiskControl.AssetTiers[0]
+	if tier.Name != "tier_1" {
+		t.Errorf("unnamed tier should get a default name, got %q", tier.Name)
+	}
+	if len(tier.Symbols) != 1 || tier.Symbols[0] != "BTCUSDT" {
+		t.Errorf("symbols not normalized: %v", tier.Symbols)
+	}
+	if len(tier.Categories) != 1 || tier.Categories[0] != AssetCategoryStock {
+		t.Errorf("categories not normalized: %v", tier.Categories)
+	}
+	if tier.MaxLeverage != MaxAltLeverage || tier.MaxPositionValueRatio != MinPositionRatio || tier.MaxPositions != MaxPositions {
+		t.Errorf("limits not clamped: %+v", tier)
+	}
+	if warnings := StrategyClampWarnings(before, cfg, "en"); len(warnings) != 3 {
+		t.Errorf("expected 3 tier clamp warnings, got %v", warnings)
+	}
+}
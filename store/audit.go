@@ -0,0 +1,287 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Audit sources: where a recorded action came from.
+const (
+	AuditSourceWeb      = "web"            // browser / direct API client
+	AuditSourceTelegram = "telegram_agent" // Telegram LLM agent via apiCallTool
+	AuditSourceCLI      = "cli"            // local admin subcommands
+)
+
+// auditRedacted replaces secret values in recorded changes.
+const auditRedacted = "[REDACTED]"
+
+// maxAuditChanges bounds the number of field changes stored per entry.
+const maxAuditChanges = 200
+
+// AuditLog one recorded action: who (actor) did what (action) to which
+// resource (target), on whose resources (owner), from where (source, IP) and
+// what changed. The table is append-only: the store exposes no update or
+// delete, and reset-account leaves it in place.
+type AuditLog struct {
+	ID          int64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	OwnerID     string        `gorm:"column:owner_id;not null;index:idx_audit_logs_owner_time" json:"owner_id"`
+	ActorID     string        `gorm:"column:actor_id;not null;index" json:"actor_id"`
+	WorkspaceID string        `gorm:"column:workspace_id;default:'';index" json:"workspace_id,omitempty"`
+	Source      string        `gorm:"column:source;default:'web'" json:"source"`
+	Action      string        `gorm:"column:action;not null" json:"action"`
+	Target      string        `gorm:"column:target;default:''" json:"target,omitempty"`
+	Status      int           `gorm:"column:status;default:0" json:"status"`
+	IP          string        `gorm:"column:ip;default:''" json:"ip,omitempty"`
+	Changes     string        `gorm:"column:changes;type:text;default:''" json:"-"`
+	ChangeList  []AuditChange `gorm:"-" json:"changes,omitempty"`
+	CreatedAt   time.Time     `gorm:"column:created_at;autoCreateTime;index:idx_audit_logs_owner_time,sort:desc" json:"created_at"`
+}
+
+func (AuditLog) TableName() string { return "audit_logs" }
+
+// AuditChange a single field difference between the before and after state.
+// Secret fields keep their path but both values are redacted, so a reader can
+// see that a key was rotated without learning it.
+type AuditChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before,omitempty"`
+	After  any    `json:"after,omitempty"`
+}
+
+// AuditFilter narrows an audit log query. Empty fields are ignored.
+type AuditFilter struct {
+	OwnerID     string
+	WorkspaceID string
+	ActorID     string
+	Source      string
+	Action      string // prefix match, e.g. "strategy." or "trader.start"
+	Target      string
+	Since       time.Time
+	Until       time.Time
+	Limit       int
+	Offset      int
+}
+
+// AuditStore audit trail storage
+type AuditStore struct {
+	db *gorm.DB
+}
+
+// NewAuditStore creates a new AuditStore
+func NewAuditStore(db *gorm.DB) *AuditStore {
+	return &AuditStore{db: db}
+}
+
+func (s *AuditStore) initTables() error {
+	return s.db.AutoMigrate(&AuditLog{})
+}
+
+// Record appends an entry to the audit trail
+func (s *AuditStore) Record(entry *AuditLog) error {
+	if entry.Source == "" {
+		entry.Source = AuditSourceWeb
+	}
+	if len(entry.ChangeList) > 0 {
+		data, err := json.Marshal(entry.ChangeList)
+		if err != nil {
+			return fmt.Errorf("failed to encode audit changes: %w", err)
+		}
+		entry.Changes = string(data)
+	}
+	return s.db.Create(entry).Error
+}
+
+// List returns audit entries matching the filter, newest first
+func (s *AuditStore) List(filter AuditFilter) ([]AuditLog, error) {
+	query := s.db.Model(&AuditLog{})
+	if filter.OwnerID != "" {
+		query = query.Where("owner_id = ?", filter.OwnerID)
+	}
+	if filter.WorkspaceID != "" {
+		query = query.Where("workspace_id = ?", filter.WorkspaceID)
+	}
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Source != "" {
+		query = query.Where("source = ?", filter.Source)
+	}
+	if filter.Action != "" {
+		query = query.Where("action LIKE ?", filter.Action+"%")
+	}
+	if filter.Target != "" {
+		query = query.Where("target = ?", filter.Target)
+	}
+	if !filter.Since.IsZero() {
+		query = query.Where("created_at >= ?", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where("created_at < ?", filter.Until)
+	}
+	limit := filter.Limit
+	if limit <= 0 || limit > 10000 {
+		limit = 100
+	}
+	var logs []AuditLog
+	err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(filter.Offset).Find(&logs).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range logs {
+		if logs[i].Changes != "" {
+			_ = json.Unmarshal([]byte(logs[i].Changes), &logs[i].ChangeList)
+		}
+	}
+	return logs, nil
+}
+
+// DiffAuditState compares two snapshots of a resource (any JSON-serializable
+// value, nil for "did not exist") and returns the changed fields with secrets
+// redacted. Lists of objects carrying an "id" are keyed by that id so a
+// reordered list does not show up as a change.
+func DiffAuditState(before, after any) []AuditChange {
+	b := flattenAuditValue(toAuditJSON(before))
+	a := flattenAuditValue(toAuditJSON(after))
+
+	fields := make([]string, 0, len(b)+len(a))
+	for k := range b {
+		fields = append(fields, k)
+	}
+	for k := range a {
+		if _, ok := b[k]; !ok {
+			fields = append(fields, k)
+		}
+	}
+	sort.Strings(fields)
+
+	var changes []AuditChange
+	for _, field := range fields {
+		bv, bok := b[field]
+		av, aok := a[field]
+		if bok && aok && reflect.DeepEqual(bv, av) {
+			continue
+		}
+		change := AuditChange{Field: field, Before: bv, After: av}
+		if isAuditSecretField(field) {
+			if bok && !isEmptyAuditValue(bv) {
+				change.Before = auditRedacted
+			}
+			if aok && !isEmptyAuditValue(av) {
+				change.After = auditRedacted
+			}
+		}
+		changes = append(changes, change)
+		if len(changes) >= maxAuditChanges {
+			break
+		}
+	}
+	return changes
+}
+
+func toAuditJSON(v any) any {
+	if v == nil {
+		return nil
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil
+	}
+	return out
+}
+
+func flattenAuditValue(v any) map[string]any {
+	out := make(map[string]any)
+	flattenAuditInto(out, "", v)
+	return out
+}
+
+func flattenAuditInto(out map[string]any, prefix string, v any) {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+	switch val := v.(type) {
+	case map[string]any:
+		for k, child := range val {
+			flattenAuditInto(out, join(k), child)
+		}
+	case []any:
+		// Objects are flattened element by element so secrets nested inside
+		// them (e.g. external source headers) are still redacted; lists of
+		// scalars are compared whole.
+		objects, keyed := len(val) > 0, len(val) > 0
+		for _, item := range val {
+			obj, ok := item.(map[string]any)
+			if !ok {
+				objects, keyed = false, false
+				break
+			}
+			if id, ok := obj["id"].(string); !ok || id == "" {
+				keyed = false
+			}
+		}
+		if !objects {
+			if prefix != "" {
+				out[prefix] = val
+			}
+			return
+		}
+		for i, item := range val {
+			obj := item.(map[string]any)
+			key := fmt.Sprintf("[%d]", i)
+			if keyed {
+				key = "[" + obj["id"].(string) + "]"
+			}
+			flattenAuditInto(out, join(key), obj)
+		}
+	case nil:
+		// absent
+	default:
+		if prefix != "" {
+			out[prefix] = val
+		}
+	}
+}
+
+// isAuditSecretField reports whether a flattened field path holds a secret:
+// the last segment names a key/secret/password/token, or the value sits
+// under a "headers" map (external source auth headers).
+func isAuditSecretField(field string) bool {
+	normalize := strings.NewReplacer("_", "", "-", "").Replace
+	segments := strings.Split(strings.ToLower(field), ".")
+	for _, seg := range segments[:len(segments)-1] {
+		if normalize(seg) == "headers" {
+			return true
+		}
+	}
+	last := normalize(segments[len(segments)-1])
+	if strings.HasSuffix(last, "index") {
+		return false
+	}
+	for _, marker := range []string{"apikey", "secret", "passphrase", "privatekey", "password", "mnemonic", "authorization", "headers"} {
+		if strings.Contains(last, marker) {
+			return true
+		}
+	}
+	return strings.HasSuffix(last, "token")
+}
+
+func isEmptyAuditValue(v any) bool {
+	s, ok := v.(string)
+	return v == nil || (ok && s == "")
+}
@@ -0,0 +1,90 @@
+package store
+
+import (
+	"testing"
+)
+
+func findAuditChange(changes []AuditChange, field string) *AuditChange {
+	for i := range changes {
+		if changes[i].Field == field {
+			return &changes[i]
+		}
+	}
+	return nil
+}
+
+func TestDiffAuditStateRedactsSecrets(t *testing.T) {
+	before := []map[string]any{
+		{"id": "ex-1", "enabled": true, "apiKey": "old-key", "lighterAPIKeyIndex": 1},
+		{"id": "ex-2", "enabled": true, "secretKey": "s"},
+	}
+	after := []map[string]any{
+		{"id": "ex-2", "enabled": true, "secretKey": "s"},
+		{"id": "ex-1", "enabled": false, "apiKey": "new-key", "lighterAPIKeyIndex": 2},
+	}
+
+	changes := DiffAuditState(before, after)
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 changes (reorder ignored), got %+v", changes)
+	}
+	key := findAuditChange(changes, "[ex-1].apiKey")
+	if key == nil || key.Before != auditRedacted || key.After != auditRedacted {
+		t.Fatalf("expected redacted api key change, got %+v", key)
+	}
+	idx := findAuditChange(changes, "[ex-1].lighterAPIKeyIndex")
+	if idx == nil || idx.Before != float64(1) || idx.After != float64(2) {
+		t.Fatalf("key index is not a secret, got %+v", idx)
+	}
+	if enabled := findAuditChange(changes, "[ex-1].enabled"); enabled == nil || enabled.After != false {
+		t.Fatalf("expected enabled change, got %+v", enabled)
+	}
+}
+
+func TestDiffAuditStateRedactsNestedHeaders(t *testing.T) {
+	after := map[string]any{
+		"indicators": map[string]any{
+			"nofxos_api_key": "cm_123",
+			"max_tokens":     4000,
+			"external_data_sources": []any{
+				map[string]any{"name": "feed", "headers": map[string]any{"X-Custom": "secret-value"}},
+			},
+		},
+	}
+	changes := DiffAuditState(nil, after)
+	for _, c := range changes {
+		if c.After == "secret-value" || c.After == "cm_123" {
+			t.Fatalf("secret leaked in %+v", c)
+		}
+	}
+	if c := findAuditChange(changes, "indicators.max_tokens"); c == nil || c.After != float64(4000) {
+		t.Fatalf("non-secret token count should be kept, got %+v", c)
+	}
+	if c := findAuditChange(changes, "indicators.external_data_sources.[0].headers.X-Custom"); c == nil || c.After != auditRedacted {
+		t.Fatalf("expected redacted header, got %+v", changes)
+	}
+}
+
+func TestAuditStoreRecordAndFilter(t *testing.T) {
+	audit := newTestStore(t).Audit()
+
+	entries := []*AuditLog{
+		{OwnerID: "u1", ActorID: "u1", Action: "strategy.update", Target: "s1",
+			ChangeList: []AuditChange{{Field: "name", Before: "a", After: "b"}}},
+		{OwnerID: "u1", ActorID: "u1", Source: AuditSourceTelegram, Action: "trader.start", Target: "t1"},
+		{OwnerID: "u2", ActorID: "u2", Action: "trader.stop", Target: "t2"},
+	}
+	for _, e := range entries {
+		if err := audit.Record(e); err != nil {
+			t.Fatalf("record: %v", err)
+		}
+	}
+
+	got, err := audit.List(AuditFilter{OwnerID: "u1", Action: "strategy."})
+	if err != nil || len(got) != 1 || got[0].Source != AuditSourceWeb || len(got[0].ChangeList) != 1 || got[0].ChangeList[0].After != "b" {
+		t.Fatalf("unexpected strategy entries %+v err=%v", got, err)
+	}
+	got, err = audit.List(AuditFilter{OwnerID: "u1", Source: AuditSourceTelegram})
+	if err != nil || len(got) != 1 || got[0].Target != "t1" {
+		t.Fatalf("unexpected telegram entries %+v err=%v", got, err)
+	}
+}
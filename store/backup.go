@@ -0,0 +1,577 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"nofx/crypto"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// Backup formats
+const (
+	BackupFormatSQLite = "sqlite" // consistent copy of the SQLite file (VACUUM INTO)
+	BackupFormatDump   = "dump"   // gzipped JSON lines, one row per line (PostgreSQL)
+)
+
+// dumpFormatName identifies nofx logical dumps
+const dumpFormatName = "nofx-dump"
+
+// restoreBatchSize rows inserted per statement when loading a dump
+const restoreBatchSize = 200
+
+// sqliteFileHeader the first bytes of every SQLite database file
+var sqliteFileHeader = []byte("SQLite format 3\x00")
+
+// DumpHeader the first line of a logical dump
+type DumpHeader struct {
+	Format        string    `json:"format"`
+	Dialect       string    `json:"dialect"`
+	SchemaVersion int       `json:"schema_version"`
+	CreatedAt     time.Time `json:"created_at"`
+	Tables        []string  `json:"tables"`
+}
+
+// dumpRow one table row of a logical dump
+type dumpRow struct {
+	Table string                 `json:"t"`
+	Row   map[string]interface{} `json:"r"`
+}
+
+// BackupInfo a finished backup
+type BackupInfo struct {
+	Path          string    `json:"path"`
+	Format        string    `json:"format"`
+	SchemaVersion int       `json:"schema_version"`
+	Size          int64     `json:"size"`
+	CreatedAt     time.Time `json:"created_at"`
+	DurationMs    int64     `json:"duration_ms"`
+}
+
+// BackupVerification what verifying a backup found
+type BackupVerification struct {
+	Format          string           `json:"format"`
+	SchemaVersion   int              `json:"schema_version"`
+	Tables          int              `json:"tables"`
+	Rows            int64            `json:"rows"`
+	TableRows       map[string]int64 `json:"table_rows"`
+	EncryptedValues int              `json:"encrypted_values"`
+	DecryptFailures []string         `json:"decrypt_failures,omitempty"` // table.column of values the current keys cannot decrypt
+}
+
+// OK reports whether every encrypted value decrypted
+func (v *BackupVerification) OK() bool {
+	return len(v.DecryptFailures) == 0
+}
+
+// BackupExtension the file extension of the backup format for this database
+func (s *Store) BackupExtension() string {
+	if s.DBType() == DBTypePostgres {
+		return ".jsonl.gz"
+	}
+	return ".db"
+}
+
+// Backup writes an online, consistent backup to path, which must not exist:
+// a VACUUM INTO copy for SQLite, a logical dump for PostgreSQL
+func (s *Store) Backup(path string) (*BackupInfo, error) {
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("backup file %s already exists", path)
+	}
+	start := time.Now().UTC()
+	version, err := s.Migrator().CurrentVersion()
+	if err != nil {
+		return nil, err
+	}
+	info := &BackupInfo{Path: path, SchemaVersion: version, CreatedAt: start}
+
+	if s.DBType() == DBTypePostgres {
+		info.Format = BackupFormatDump
+		err = s.dump(path, version)
+	} else {
+		info.Format = BackupFormatSQLite
+		err = s.vacuumInto(path)
+	}
+	if err != nil {
+		os.Remove(path)
+		return nil, fmt.Errorf("backup failed: %w", err)
+	}
+	if fi, err := os.Stat(path); err == nil {
+		info.Size = fi.Size()
+	}
+	info.DurationMs = time.Since(start).Milliseconds()
+	return info, nil
+}
+
+// vacuumInto copies the SQLite database to path. VACUUM INTO creates its
+// file with the process umask, so the file is created empty with owner-only
+// permissions first (VACUUM INTO accepts an empty target): the backup holds
+// the same encrypted credentials as the database.
+func (s *Store) vacuumInto(path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return s.gdb.Exec("VACUUM INTO ?", path).Error
+}
+
+// dump writes every table as gzipped JSON lines inside one read transaction,
+// so the dump is a consistent snapshot
+func (s *Store) dump(path string, version int) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	enc := json.NewEncoder(gz)
+
+	err = s.gdb.Transaction(func(tx *gorm.DB) error {
+		if tx.Dialector.Name() == "postgres" {
+			if err := tx.Exec("SET TRANSACTION ISOLATION LEVEL REPEATABLE READ READ ONLY").Error; err != nil {
+				return err
+			}
+		}
+		tables, err := tx.Migrator().GetTables()
+		if err != nil {
+			return fmt.Errorf("failed to list tables: %w", err)
+		}
+		sort.Strings(tables)
+		header := DumpHeader{Format: dumpFormatName, Dialect: tx.Dialector.Name(), SchemaVersion: version,
+			CreatedAt: time.Now().UTC(), Tables: tables}
+		if err := enc.Encode(header); err != nil {
+			return err
+		}
+		for _, table := range tables {
+			err := scanTableRows(tx, table, func(row map[string]interface{}) error {
+				return enc.Encode(dumpRow{Table: table, Row: row})
+			})
+			if err != nil {
+				return fmt.Errorf("failed to dump %s: %w", table, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// scanTableRows streams the rows of a table as column → value maps
+func scanTableRows(db *gorm.DB, table string, fn func(row map[string]interface{}) error) error {
+	rows, err := db.Table(table).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		row := make(map[string]interface{})
+		if err := db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		for k, v := range row {
+			if b, ok := v.([]byte); ok {
+				row[k] = string(b)
+			}
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// RestoreDump replaces the rows of every table in a logical dump with the
+// dumped rows, in one transaction. The schema must already be migrated;
+// schema_migrations itself is not restored.
+func (s *Store) RestoreDump(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	header, dec, err := openDump(f)
+	if err != nil {
+		return err
+	}
+	m := s.Migrator()
+	if header.SchemaVersion > m.LatestVersion() {
+		return &SchemaTooNewError{DBVersion: header.SchemaVersion, BinaryVersion: m.LatestVersion()}
+	}
+
+	return s.gdb.Transaction(func(tx *gorm.DB) error {
+		restored := make(map[string]bool)
+		for _, table := range header.Tables {
+			if table == (SchemaMigration{}).TableName() || !tx.Migrator().HasTable(table) {
+				continue
+			}
+			if err := tx.Exec("DELETE FROM " + quoteIdent(tx, table)).Error; err != nil {
+				return fmt.Errorf("failed to clear %s: %w", table, err)
+			}
+			restored[table] = true
+		}
+
+		var batch []map[string]interface{}
+		batchTable := ""
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			if err := tx.Table(batchTable).Create(&batch).Error; err != nil {
+				return fmt.Errorf("failed to restore %s: %w", batchTable, err)
+			}
+			batch = batch[:0]
+			return nil
+		}
+		for {
+			var line dumpRow
+			if err := dec.Decode(&line); err == io.EOF {
+				break
+			} else if err != nil {
+				return fmt.Errorf("invalid dump: %w", err)
+			}
+			if !restored[line.Table] {
+				continue
+			}
+			if line.Table != batchTable || len(batch) >= restoreBatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+				batchTable = line.Table
+			}
+			batch = append(batch, normalizeDumpRow(line.Row))
+		}
+		if err := flush(); err != nil {
+			return err
+		}
+
+		if tx.Dialector.Name() == "postgres" {
+			for table := range restored {
+				if !tx.Migrator().HasColumn(table, "id") {
+					continue
+				}
+				// Move serial sequences past the restored ids
+				if err := tx.Exec(fmt.Sprintf(
+					"SELECT setval(pg_get_serial_sequence('%s', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM %s",
+					table, quoteIdent(tx, table))).Error; err != nil {
+					return fmt.Errorf("failed to reset %s sequence: %w", table, err)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// openDump reads the header of a gzipped dump and returns a decoder
+// positioned on the first row
+func openDump(r io.Reader) (*DumpHeader, *json.Decoder, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("not a nofx dump: %w", err)
+	}
+	dec := json.NewDecoder(bufio.NewReader(gz))
+	dec.UseNumber()
+	var header DumpHeader
+	if err := dec.Decode(&header); err != nil || header.Format != dumpFormatName {
+		return nil, nil, errors.New("not a nofx dump: missing header")
+	}
+	return &header, dec, nil
+}
+
+// normalizeDumpRow turns JSON numbers back into int64 / float64
+func normalizeDumpRow(row map[string]interface{}) map[string]interface{} {
+	for k, v := range row {
+		if n, ok := v.(json.Number); ok {
+			if i, err := n.Int64(); err == nil {
+				row[k] = i
+			} else if f, err := n.Float64(); err == nil {
+				row[k] = f
+			}
+		}
+	}
+	return row
+}
+
+func quoteIdent(db *gorm.DB, name string) string {
+	var b strings.Builder
+	db.Dialector.QuoteTo(&b, name)
+	return b.String()
+}
+
+// DetectBackupFormat tells a SQLite backup from a logical dump
+func DetectBackupFormat(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	head := make([]byte, len(sqliteFileHeader))
+	n, _ := io.ReadFull(f, head)
+	switch {
+	case n == len(sqliteFileHeader) && bytes.Equal(head, sqliteFileHeader):
+		return BackupFormatSQLite, nil
+	case n >= 2 && head[0] == 0x1f && head[1] == 0x8b:
+		return BackupFormatDump, nil
+	}
+	return "", fmt.Errorf("%s is neither a SQLite backup nor a nofx dump", path)
+}
+
+// VerifyBackup opens a backup, reads every row and checks that each
+// encrypted value still decrypts with the given crypto service's data key
+func VerifyBackup(path string, cs *crypto.CryptoService) (*BackupVerification, error) {
+	format, err := DetectBackupFormat(path)
+	if err != nil {
+		return nil, err
+	}
+	v := &BackupVerification{Format: format, TableRows: make(map[string]int64)}
+	failed := make(map[string]bool)
+	check := func(table string, row map[string]interface{}) {
+		v.Rows++
+		v.TableRows[table]++
+		for col, val := range row {
+			str, ok := val.(string)
+			if !ok || !strings.HasPrefix(str, "ENC:") || cs == nil || !cs.IsEncryptedStorageValue(str) {
+				continue
+			}
+			v.EncryptedValues++
+			if _, err := cs.DecryptFromStorage(str); err != nil && !failed[table+"."+col] {
+				failed[table+"."+col] = true
+				v.DecryptFailures = append(v.DecryptFailures, table+"."+col)
+			}
+		}
+	}
+
+	if format == BackupFormatSQLite {
+		err = verifySQLiteBackup(path, v, check)
+	} else {
+		err = verifyDump(path, v, check)
+	}
+	if err != nil {
+		return nil, err
+	}
+	v.Tables = len(v.TableRows)
+	sort.Strings(v.DecryptFailures)
+	return v, nil
+}
+
+func verifySQLiteBackup(path string, v *BackupVerification, check func(string, map[string]interface{})) error {
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+	if err != nil {
+		return fmt.Errorf("failed to open backup: %w", err)
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+
+	var result string
+	if err := db.Raw("PRAGMA integrity_check").Scan(&result).Error; err != nil {
+		return fmt.Errorf("integrity check failed: %w", err)
+	}
+	if result != "ok" {
+		return fmt.Errorf("integrity check failed: %s", result)
+	}
+	if v.SchemaVersion, err = NewMigrator(db).CurrentVersion(); err != nil {
+		return err
+	}
+	tables, err := db.Migrator().GetTables()
+	if err != nil {
+		return fmt.Errorf("failed to list tables: %w", err)
+	}
+	for _, table := range tables {
+		v.TableRows[table] = 0
+		err := scanTableRows(db, table, func(row map[string]interface{}) error {
+			check(table, row)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+func verifyDump(path string, v *BackupVerification, check func(string, map[string]interface{})) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	header, dec, err := openDump(f)
+	if err != nil {
+		return err
+	}
+	v.SchemaVersion = header.SchemaVersion
+	for _, table := range header.Tables {
+		v.TableRows[table] = 0
+	}
+	for {
+		var line dumpRow
+		if err := dec.Decode(&line); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("dump is truncated or corrupt: %w", err)
+		}
+		check(line.Table, line.Row)
+	}
+}
+
+// RestoreSQLiteFile replaces the SQLite database at dbPath with a backup
+// file. The current database is kept next to it as <dbPath>.pre-restore-<time>.
+// The server must not be running.
+func RestoreSQLiteFile(backupPath, dbPath string) (previous string, err error) {
+	if format, err := DetectBackupFormat(backupPath); err != nil {
+		return "", err
+	} else if format != BackupFormatSQLite {
+		return "", errors.New("not a SQLite backup")
+	}
+
+	tmp := dbPath + ".restore-tmp"
+	if err := copyFile(backupPath, tmp); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("failed to copy backup: %w", err)
+	}
+	if _, err := os.Stat(dbPath); err == nil {
+		previous = fmt.Sprintf("%s.pre-restore-%s", dbPath, time.Now().UTC().Format("20060102-150405"))
+		if err := os.Rename(dbPath, previous); err != nil {
+			os.Remove(tmp)
+			return "", fmt.Errorf("failed to move current database aside: %w", err)
+		}
+		// A leftover rollback journal belongs to the replaced database
+		os.Remove(dbPath + "-journal")
+	}
+	if err := os.Rename(tmp, dbPath); err != nil {
+		return previous, fmt.Errorf("failed to move backup into place: %w", err)
+	}
+	return previous, nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// System config keys of scheduled backups
+const (
+	SystemConfigBackupPolicy     = "backup_policy"
+	SystemConfigBackupLastReport = "backup_last_report"
+)
+
+// BackupPolicy scheduled backups to a local directory, stored as JSON in system_config
+type BackupPolicy struct {
+	Enabled       bool   `json:"enabled"`
+	IntervalHours int    `json:"interval_hours,omitempty"` // time between backups
+	Dir           string `json:"dir,omitempty"`            // where backups are written
+	Keep          int    `json:"keep,omitempty"`           // newest backups kept, older ones are deleted
+	Verify        bool   `json:"verify"`                   // verify each backup after writing it
+}
+
+// DefaultBackupPolicy the policy used until one is saved. Scheduled backups
+// are off by default.
+func DefaultBackupPolicy() BackupPolicy {
+	return BackupPolicy{IntervalHours: 24, Dir: "data/backups", Keep: 7, Verify: true}
+}
+
+// Normalize fills unset fields with the defaults
+func (p BackupPolicy) Normalize() BackupPolicy {
+	def := DefaultBackupPolicy()
+	if p.IntervalHours <= 0 {
+		p.IntervalHours = def.IntervalHours
+	}
+	if p.Dir == "" {
+		p.Dir = def.Dir
+	}
+	if p.Keep <= 0 {
+		p.Keep = def.Keep
+	}
+	return p
+}
+
+// BackupReport the outcome of a scheduled backup
+type BackupReport struct {
+	StartedAt    time.Time           `json:"started_at"`
+	Backup       *BackupInfo         `json:"backup,omitempty"`
+	Verification *BackupVerification `json:"verification,omitempty"`
+	Rotated      []string            `json:"rotated,omitempty"` // old backups deleted
+	Error        string              `json:"error,omitempty"`
+}
+
+// GetBackupPolicy returns the saved backup policy, or the default
+func (s *Store) GetBackupPolicy() (BackupPolicy, error) {
+	raw, err := s.GetSystemConfig(SystemConfigBackupPolicy)
+	if err != nil {
+		return BackupPolicy{}, fmt.Errorf("failed to load backup policy: %w", err)
+	}
+	if raw == "" {
+		return DefaultBackupPolicy(), nil
+	}
+	var p BackupPolicy
+	if err := json.Unmarshal([]byte(raw), &p); err != nil {
+		return BackupPolicy{}, fmt.Errorf("invalid backup policy: %w", err)
+	}
+	return p.Normalize(), nil
+}
+
+// SetBackupPolicy saves the backup policy
+func (s *Store) SetBackupPolicy(p BackupPolicy) error {
+	data, err := json.Marshal(p.Normalize())
+	if err != nil {
+		return err
+	}
+	return s.SetSystemConfig(SystemConfigBackupPolicy, string(data))
+}
+
+// GetLastBackupReport returns the report of the last scheduled backup, nil when none ran
+func (s *Store) GetLastBackupReport() (*BackupReport, error) {
+	raw, err := s.GetSystemConfig(SystemConfigBackupLastReport)
+	if err != nil || raw == "" {
+		return nil, err
+	}
+	var r BackupReport
+	if err := json.Unmarshal([]byte(raw), &r); err != nil {
+		return nil, fmt.Errorf("invalid backup report: %w", err)
+	}
+	return &r, nil
+}
+
+// SaveBackupReport records the report of a scheduled backup
+func (s *Store) SaveBackupReport(r *BackupReport) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return s.SetSystemConfig(SystemConfigBackupLastReport, string(data))
+}
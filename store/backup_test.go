@@ -0,0 +1,161 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"nofx/crypto"
+)
+
+func newTestCryptoService(t *testing.T) *crypto.CryptoService {
+	t.Helper()
+	privateKey, _, err := crypto.GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("generate key pair: %v", err)
+	}
+	dataKey, err := crypto.GenerateDataKey()
+	if err != nil {
+		t.Fatalf("generate data key: %v", err)
+	}
+	t.Setenv("RSA_PRIVATE_KEY", privateKey)
+	t.Setenv("DATA_ENCRYPTION_KEY", dataKey)
+	cs, err := crypto.NewCryptoService()
+	if err != nil {
+		t.Fatalf("crypto service: %v", err)
+	}
+	return cs
+}
+
+// seedBackupData stores an AI model with an encrypted API key and a few
+// equity snapshots
+func seedBackupData(t *testing.T, st *Store, cs *crypto.CryptoService) {
+	t.Helper()
+	encrypted, err := cs.EncryptForStorage("sk-test")
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if err := st.GormDB().Exec(`INSERT INTO ai_models (id, user_id, name, provider, api_key) VALUES (?, ?, ?, ?, ?)`,
+		"m1", "u1", "DeepSeek", "deepseek", encrypted).Error; err != nil {
+		t.Fatalf("insert ai model: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := st.Equity().Save(&EquitySnapshot{TraderID: "t1", Timestamp: time.Now().Add(-time.Duration(i) * time.Hour), TotalEquity: float64(100 + i)}); err != nil {
+			t.Fatalf("save equity: %v", err)
+		}
+	}
+}
+
+func TestSQLiteBackupVerify(t *testing.T) {
+	cs := newTestCryptoService(t)
+	st := newTestStore(t)
+	seedBackupData(t, st, cs)
+
+	path := filepath.Join(t.TempDir(), "backup.db")
+	info, err := st.Backup(path)
+	if err != nil {
+		t.Fatalf("Backup: %v", err)
+	}
+	if info.Format != BackupFormatSQLite || info.Size == 0 || info.SchemaVersion != st.Migrator().LatestVersion() {
+		t.Fatalf("backup info = %+v", info)
+	}
+	if fi, err := os.Stat(path); err != nil {
+		t.Fatalf("stat backup: %v", err)
+	} else if fi.Mode().Perm() != 0o600 {
+		t.Fatalf("backup file mode = %v, want 0600", fi.Mode().Perm())
+	}
+	if _, err := st.Backup(path); err == nil {
+		t.Fatal("Backup overwrote an existing file")
+	}
+
+	v, err := VerifyBackup(path, cs)
+	if err != nil {
+		t.Fatalf("VerifyBackup: %v", err)
+	}
+	if !v.OK() || v.EncryptedValues != 1 || v.TableRows["trader_equity_snapshots"] != 3 {
+		t.Fatalf("verification = %+v", v)
+	}
+
+	// A different data key cannot decrypt the backup
+	other := newTestCryptoService(t)
+	v, err = VerifyBackup(path, other)
+	if err != nil {
+		t.Fatalf("VerifyBackup: %v", err)
+	}
+	if v.OK() || len(v.DecryptFailures) != 1 || v.DecryptFailures[0] != "ai_models.api_key" {
+		t.Fatalf("verification with the wrong key = %+v, want ai_models.api_key failure", v)
+	}
+}
+
+func TestDumpRestoreRoundTrip(t *testing.T) {
+	cs := newTestCryptoService(t)
+	src := newTestStore(t)
+	seedBackupData(t, src, cs)
+
+	path := filepath.Join(t.TempDir(), "backup.jsonl.gz")
+	if err := src.dump(path, src.Migrator().LatestVersion()); err != nil {
+		t.Fatalf("dump: %v", err)
+	}
+	if format, err := DetectBackupFormat(path); err != nil || format != BackupFormatDump {
+		t.Fatalf("DetectBackupFormat = %q, %v", format, err)
+	}
+	v, err := VerifyBackup(path, cs)
+	if err != nil || !v.OK() || v.EncryptedValues != 1 {
+		t.Fatalf("VerifyBackup = %+v, %v", v, err)
+	}
+
+	dst := newTestStore(t)
+	// Rows not in the dump are replaced
+	if err := dst.Equity().Save(&EquitySnapshot{TraderID: "other", TotalEquity: 1}); err != nil {
+		t.Fatalf("save equity: %v", err)
+	}
+	if err := dst.RestoreDump(path); err != nil {
+		t.Fatalf("RestoreDump: %v", err)
+	}
+
+	var snapshots []EquitySnapshot
+	dst.GormDB().Order("total_equity ASC").Find(&snapshots)
+	if len(snapshots) != 3 || snapshots[0].TraderID != "t1" || snapshots[0].TotalEquity != 100 {
+		t.Fatalf("restored snapshots = %+v", snapshots)
+	}
+	if snapshots[0].Timestamp.IsZero() {
+		t.Fatal("restored snapshot lost its timestamp")
+	}
+	var apiKey string
+	dst.GormDB().Raw(`SELECT api_key FROM ai_models WHERE id = ?`, "m1").Scan(&apiKey)
+	if plain, err := cs.DecryptFromStorage(apiKey); err != nil || plain != "sk-test" {
+		t.Fatalf("restored api key = %q (%v), want it to decrypt to sk-test", plain, err)
+	}
+	if v, _ := dst.Migrator().CurrentVersion(); v != dst.Migrator().LatestVersion() {
+		t.Fatalf("schema version after restore = %d", v)
+	}
+}
+
+func TestRestoreSQLiteFileKeepsPrevious(t *testing.T) {
+	dir := t.TempDir()
+	st := newTestStore(t)
+	backup := filepath.Join(dir, "backup.db")
+	if _, err := st.Backup(backup); err != nil {
+		t.Fatalf("Backup: %v", err)
+	}
+
+	dbPath := filepath.Join(dir, "data.db")
+	if err := os.WriteFile(dbPath, []byte("current"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	previous, err := RestoreSQLiteFile(backup, dbPath)
+	if err != nil {
+		t.Fatalf("RestoreSQLiteFile: %v", err)
+	}
+	if data, err := os.ReadFile(previous); err != nil || string(data) != "current" {
+		t.Fatalf("previous database not kept: %q, %v", data, err)
+	}
+	if format, err := DetectBackupFormat(dbPath); err != nil || format != BackupFormatSQLite {
+		t.Fatalf("restored file format = %q, %v", format, err)
+	}
+
+	if _, err := RestoreSQLiteFile(dbPath+".missing", dbPath); err == nil {
+		t.Fatal("restoring a missing backup succeeded")
+	}
+}
@@ -0,0 +1,121 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Circuit breaker actions
+const (
+	BreakerActionPauseOpens = "pause_opens" // block new positions, closes still run
+	BreakerActionCloseAll   = "close_all"   // close every position and pause the trader
+	BreakerActionStopTrader = "stop_trader" // close every position and stop the trader
+)
+
+// DefaultBreakerCooldownMinutes how long a tripped breaker pauses trading when
+// no cooldown is configured. A daily loss trip always lasts until the next UTC day.
+const DefaultBreakerCooldownMinutes = 240
+
+// CircuitBreakerConfig account-level hard limits for AI traders, evaluated
+// every cycle from the equity snapshots and the closed position history
+// (CODE ENFORCED). A zero limit is disabled.
+type CircuitBreakerConfig struct {
+	// Equity % lost since the first snapshot of the UTC day
+	DailyLossPct float64 `json:"daily_loss_pct,omitempty"`
+	// Equity % below the peak equity since the trader started or last tripped
+	MaxDrawdownPct float64 `json:"max_drawdown_pct,omitempty"`
+	// Losing closed trades in a row
+	MaxConsecutiveLosses int `json:"max_consecutive_losses,omitempty"`
+	// What a trip does: pause_opens (default), close_all, stop_trader
+	Action string `json:"action,omitempty"`
+	// Pause length after a trip
+	CooldownMinutes int `json:"cooldown_minutes,omitempty"`
+}
+
+// Enabled reports whether any breaker limit is set
+func (c CircuitBreakerConfig) Enabled() bool {
+	return c.DailyLossPct > 0 || c.MaxDrawdownPct > 0 || c.MaxConsecutiveLosses > 0
+}
+
+// WithDefaults returns the config with unset fields defaulted
+func (c CircuitBreakerConfig) WithDefaults() CircuitBreakerConfig {
+	if c.Action == "" {
+		c.Action = BreakerActionPauseOpens
+	}
+	if c.CooldownMinutes <= 0 {
+		c.CooldownMinutes = DefaultBreakerCooldownMinutes
+	}
+	return c
+}
+
+// normalizeCircuitBreaker drops unknown actions and clamps the limits to sane
+// ranges
+func normalizeCircuitBreaker(c CircuitBreakerConfig) CircuitBreakerConfig {
+	c.Action = strings.ToLower(strings.TrimSpace(c.Action))
+	switch c.Action {
+	case BreakerActionPauseOpens, BreakerActionCloseAll, BreakerActionStopTrader:
+	default:
+		c.Action = ""
+	}
+	c.DailyLossPct = clampNonNegative(c.DailyLossPct, 100)
+	c.MaxDrawdownPct = clampNonNegative(c.MaxDrawdownPct, 100)
+	if c.MaxConsecutiveLosses < 0 {
+		c.MaxConsecutiveLosses = 0
+	}
+	if c.MaxConsecutiveLosses > 100 {
+		c.MaxConsecutiveLosses = 100
+	}
+	if c.CooldownMinutes < 0 {
+		c.CooldownMinutes = 0
+	}
+	if c.CooldownMinutes > 7*24*60 {
+		c.CooldownMinutes = 7 * 24 * 60
+	}
+	return c
+}
+
+// TraderBreakerState the circuit breaker state of a trader, kept across
+// restarts so a restarted trader neither forgets a pause nor recounts the
+// drawdown peak and losing streak from its new start time
+type TraderBreakerState struct {
+	TraderID  string    `gorm:"column:trader_id;primaryKey"`
+	Since     time.Time `gorm:"column:since"`      // drawdown peak and losing streak count from here
+	StopUntil time.Time `gorm:"column:stop_until"` // end of the pause of the last trip
+	Action    string    `gorm:"column:action;not null;default:''"`
+	Reason    string    `gorm:"column:reason;type:text;not null;default:''"`
+	UpdatedAt time.Time
+}
+
+func (TraderBreakerState) TableName() string { return "trader_breaker_states" }
+
+// GetBreakerState returns the trader's saved circuit breaker state, or nil
+// when its breakers never ran
+func (s *TraderStore) GetBreakerState(traderID string) (*TraderBreakerState, error) {
+	var state TraderBreakerState
+	err := s.db.Where("trader_id = ?", traderID).First(&state).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load circuit breaker state: %w", err)
+	}
+	return &state, nil
+}
+
+// SaveBreakerState stores the trader's circuit breaker state
+func (s *TraderStore) SaveBreakerState(state *TraderBreakerState) error {
+	state.UpdatedAt = time.Now().UTC()
+	err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "trader_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"since", "stop_until", "action", "reason", "updated_at"}),
+	}).Create(state).Error
+	if err != nil {
+		return fmt.Errorf("failed to save circuit breaker state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerQueries(t *testing.T) {
+	st := newTestStore(t)
+	db := st.GormDB()
+	equity, positions := st.Equity(), st.Position()
+
+	now := time.Now().UTC()
+	for i, v := range []float64{1000, 1200, 1100, 900} {
+		if err := equity.Save(&EquitySnapshot{TraderID: "t1", Timestamp: now.Add(time.Duration(i-4) * time.Hour), TotalEquity: v}); err != nil {
+			t.Fatalf("save snapshot: %v", err)
+		}
+	}
+	peak, err := equity.GetPeakEquity("t1", now.Add(-5*time.Hour))
+	if err != nil || peak != 1200 {
+		t.Fatalf("peak = %v (%v), want 1200", peak, err)
+	}
+	peak, _ = equity.GetPeakEquity("t1", now.Add(-150*time.Minute))
+	if peak != 1100 {
+		t.Fatalf("peak since 150m ago = %v, want 1100", peak)
+	}
+	if peak, _ = equity.GetPeakEquity("other", now.Add(-5*time.Hour)); peak != 0 {
+		t.Fatalf("peak without records = %v, want 0", peak)
+	}
+	first, err := equity.GetFirstSince("t1", now.Add(-150*time.Minute))
+	if err != nil || first == nil || first.TotalEquity != 1100 {
+		t.Fatalf("first since = %+v (%v), want 1100", first, err)
+	}
+
+	// newest first: loss, loss, win, loss
+	for i, pnl := range []float64{-5, 10, -3, -2} {
+		exit := now.Add(time.Duration(i-4) * time.Hour).UnixMilli()
+		if err := db.Create(&TraderPosition{
+			TraderID: "t1", Symbol: "BTCUSDT", Side: "LONG", Quantity: 1, EntryPrice: 100,
+			EntryTime: exit - 1000, ExitTime: exit, RealizedPnL: pnl, Status: "CLOSED",
+		}).Error; err != nil {
+			t.Fatalf("create position: %v", err)
+		}
+	}
+	losses, err := positions.GetConsecutiveLosses("t1", now.Add(-5*time.Hour))
+	if err != nil || losses != 2 {
+		t.Fatalf("losses = %d (%v), want 2", losses, err)
+	}
+	if losses, _ = positions.GetConsecutiveLosses("t1", now.Add(-90*time.Minute)); losses != 1 {
+		t.Fatalf("losses since 90m ago = %d, want 1", losses)
+	}
+}
+
+func TestNormalizeCircuitBreaker(t *testing.T) {
+	got := normalizeCircuitBreaker(CircuitBreakerConfig{DailyLossPct: 150, MaxConsecutiveLosses: -1, Action: " Close_All "})
+	if got.DailyLossPct != 100 || got.MaxConsecutiveLosses != 0 || got.Action != BreakerActionCloseAll {
+		t.Fatalf("normalized = %+v", got)
+	}
+	if got := normalizeCircuitBreaker(CircuitBreakerConfig{Action: "explode"}).WithDefaults(); got.Action != BreakerActionPauseOpens {
+		t.Fatalf("unknown action should default to pause_opens, got %q", got.Action)
+	}
+}
+
+func TestTraderBreakerState(t *testing.T) {
+	traders := newTestStore(t).Trader()
+
+	if state, err := traders.GetBreakerState("t1"); err != nil || state != nil {
+		t.Fatalf("state before any save = %+v (%v), want nil", state, err)
+	}
+	since := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	if err := traders.SaveBreakerState(&TraderBreakerState{TraderID: "t1", Since: since}); err != nil {
+		t.Fatalf("SaveBreakerState: %v", err)
+	}
+	until := since.Add(4 * time.Hour)
+	if err := traders.SaveBreakerState(&TraderBreakerState{TraderID: "t1", Since: since, StopUntil: until, Action: BreakerActionPauseOpens, Reason: "drawdown"}); err != nil {
+		t.Fatalf("SaveBreakerState after trip: %v", err)
+	}
+	state, err := traders.GetBreakerState("t1")
+	if err != nil || state == nil {
+		t.Fatalf("GetBreakerState: %+v (%v)", state, err)
+	}
+	if !state.Since.Equal(since) || !state.StopUntil.Equal(until) || state.Action != BreakerActionPauseOpens || state.Reason != "drawdown" {
+		t.Fatalf("state = %+v", state)
+	}
+}
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"nofx/crypto"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// encryptedModels the models with crypto.EncryptedString fields. Their
+// columns are found from the field types, so new secret fields are covered.
+var encryptedModels = []interface{}{&Exchange{}, &AIModel{}, &TelegramConfig{}}
+
+// EncryptedColumn a column holding crypto.EncryptedString values
+type EncryptedColumn struct {
+	Table  string `json:"table"`
+	Column string `json:"column"`
+}
+
+func (c EncryptedColumn) String() string {
+	return c.Table + "." + c.Column
+}
+
+// DataKeyRotationReport the outcome of re-encrypting the stored secrets
+type DataKeyRotationReport struct {
+	KeyID     string         `json:"key_id"`
+	DryRun    bool           `json:"dry_run"`
+	Columns   map[string]int `json:"columns"`   // table.column -> values re-encrypted
+	Plaintext int            `json:"plaintext"` // values that were not encrypted before
+	Current   int            `json:"current"`   // values already under the new key
+}
+
+// Total the number of values re-encrypted
+func (r *DataKeyRotationReport) Total() int {
+	n := 0
+	for _, c := range r.Columns {
+		n += c
+	}
+	return n
+}
+
+// EncryptedColumns lists the columns that hold encrypted secrets
+func (s *Store) EncryptedColumns() ([]EncryptedColumn, error) {
+	cache := &sync.Map{}
+	encryptedType := reflect.TypeOf(crypto.EncryptedString(""))
+	var cols []EncryptedColumn
+	for _, model := range encryptedModels {
+		sch, err := schema.Parse(model, cache, s.gdb.NamingStrategy)
+		if err != nil {
+			return nil, fmt.Errorf("parse %T: %w", model, err)
+		}
+		for _, f := range sch.Fields {
+			if f.FieldType == encryptedType && f.DBName != "" {
+				cols = append(cols, EncryptedColumn{Table: sch.Table, Column: f.DBName})
+			}
+		}
+	}
+	return cols, nil
+}
+
+// ReEncryptSecrets re-encrypts every stored secret with the primary key of
+// cs in one transaction. cs must also hold the keys the values are encrypted
+// with now; if any value does not decrypt nothing is changed. Plaintext
+// values left from before encryption are encrypted as well.
+func (s *Store) ReEncryptSecrets(cs *crypto.CryptoService, dryRun bool) (*DataKeyRotationReport, error) {
+	if cs == nil || !cs.HasDataKey() {
+		return nil, fmt.Errorf("data encryption key not configured")
+	}
+	cols, err := s.EncryptedColumns()
+	if err != nil {
+		return nil, err
+	}
+	report := &DataKeyRotationReport{
+		KeyID:   cs.Keyring().PrimaryID(),
+		DryRun:  dryRun,
+		Columns: make(map[string]int),
+	}
+
+	err = s.gdb.Transaction(func(tx *gorm.DB) error {
+		for _, col := range cols {
+			if !tx.Migrator().HasTable(col.Table) {
+				continue
+			}
+			table, column := quoteIdent(tx, col.Table), quoteIdent(tx, col.Column)
+			var values []string
+			if err := tx.Raw(fmt.Sprintf("SELECT DISTINCT %s FROM %s WHERE %s IS NOT NULL AND %s <> ''",
+				column, table, column, column)).Scan(&values).Error; err != nil {
+				return fmt.Errorf("read %s: %w", col, err)
+			}
+			for _, value := range values {
+				if !cs.NeedsReEncrypt(value) {
+					report.Current++
+					continue
+				}
+				if !cs.IsEncryptedStorageValue(value) {
+					report.Plaintext++
+				}
+				encrypted, err := cs.ReEncryptForStorage(value)
+				if err != nil {
+					return fmt.Errorf("%s: %w", col, err)
+				}
+				// Raw SQL so EncryptedString does not encrypt the value again
+				if !dryRun {
+					if err := tx.Exec(fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s = ?", table, column, column),
+						encrypted, value).Error; err != nil {
+						return fmt.Errorf("update %s: %w", col, err)
+					}
+				}
+				report.Columns[col.String()]++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,101 @@
+package store
+
+import (
+	"testing"
+
+	"nofx/crypto"
+)
+
+func TestEncryptedColumnsFromModels(t *testing.T) {
+	st := newTestStore(t)
+	cols, err := st.EncryptedColumns()
+	if err != nil {
+		t.Fatalf("EncryptedColumns: %v", err)
+	}
+	found := make(map[string]bool)
+	for _, c := range cols {
+		found[c.String()] = true
+	}
+	for _, want := range []string{"exchanges.api_key", "exchanges.lighter_api_key_private_key", "ai_models.api_key", "telegram_configs.bot_token"} {
+		if !found[want] {
+			t.Fatalf("encrypted columns %v miss %s", cols, want)
+		}
+	}
+}
+
+func TestReEncryptSecretsRotatesKey(t *testing.T) {
+	cs := newTestCryptoService(t)
+	crypto.SetGlobalCryptoService(cs)
+	t.Cleanup(func() { crypto.SetGlobalCryptoService(nil) })
+
+	st := newTestStore(t)
+	seedBackupData(t, st, cs)
+	// A token stored before it was encrypted
+	if err := st.GormDB().Exec(`INSERT INTO telegram_configs (id, bot_token) VALUES (1, 'plain-token')`).Error; err != nil {
+		t.Fatalf("insert telegram config: %v", err)
+	}
+
+	newKey, err := crypto.GenerateDataKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyBytes, _ := crypto.ParseDataKey(newKey)
+	ring, err := cs.Keyring().WithPrimary(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated := cs.WithKeyring(ring)
+
+	report, err := st.ReEncryptSecrets(rotated, true)
+	if err != nil || report.Total() != 2 || report.Plaintext != 1 {
+		t.Fatalf("dry run = %+v, %v", report, err)
+	}
+	var apiKey string
+	st.GormDB().Raw(`SELECT api_key FROM ai_models WHERE id = ?`, "m1").Scan(&apiKey)
+	if crypto.StorageKeyID(apiKey) != cs.Keyring().PrimaryID() {
+		t.Fatal("dry run changed the stored value")
+	}
+
+	report, err = st.ReEncryptSecrets(rotated, false)
+	if err != nil || report.Columns["ai_models.api_key"] != 1 || report.Columns["telegram_configs.bot_token"] != 1 {
+		t.Fatalf("ReEncryptSecrets = %+v, %v", report, err)
+	}
+
+	// Only the new key is needed from now on
+	newOnly, err := crypto.NewKeyring(keyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	crypto.SetGlobalCryptoService(cs.WithKeyring(newOnly))
+	var model AIModel
+	if err := st.GormDB().First(&model, "id = ?", "m1").Error; err != nil || model.APIKey != "sk-test" {
+		t.Fatalf("api key after rotation = %q, %v", model.APIKey, err)
+	}
+	cfg, err := st.TelegramConfig().Get()
+	if err != nil || cfg.BotToken != "plain-token" {
+		t.Fatalf("bot token after rotation = %+v, %v", cfg, err)
+	}
+
+	// Running again finds nothing to do
+	report, err = st.ReEncryptSecrets(rotated, false)
+	if err != nil || report.Total() != 0 || report.Current != 2 {
+		t.Fatalf("second run = %+v, %v", report, err)
+	}
+}
+
+func TestReEncryptSecretsFailsWithoutOldKey(t *testing.T) {
+	cs := newTestCryptoService(t)
+	st := newTestStore(t)
+	seedBackupData(t, st, cs)
+
+	// A service that never held the key the data is encrypted with
+	other := newTestCryptoService(t)
+	if _, err := st.ReEncryptSecrets(other, false); err == nil {
+		t.Fatal("re-encryption succeeded without the old key")
+	}
+	var apiKey string
+	st.GormDB().Raw(`SELECT api_key FROM ai_models WHERE id = ?`, "m1").Scan(&apiKey)
+	if plain, err := cs.DecryptFromStorage(apiKey); err != nil || plain != "sk-test" {
+		t.Fatalf("stored value changed after a failed rotation: %q, %v", plain, err)
+	}
+}
@@ -2,6 +2,7 @@ package store
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -30,6 +31,14 @@ type DecisionRecordDB struct {
 	Success             bool      `gorm:"default:false"`
 	ErrorMessage        string    `gorm:"column:error_message;default:''"`
 	AIRequestDurationMs int64     `gorm:"column:ai_request_duration_ms;default:0"`
+	StrategyID          string    `gorm:"column:strategy_id;default:''"`
+	StrategyVersion     int       `gorm:"column:strategy_version;default:0"`
+	AIProvider          string    `gorm:"column:ai_provider;default:''"`
+	AIModel             string    `gorm:"column:ai_model;default:''"`
+	MarketRegime        string    `gorm:"column:market_regime;default:''"`
+	PromptVariant       string    `gorm:"column:prompt_variant;default:''"`
+	AccountState        string    `gorm:"column:account_state;default:''"` // JSON AccountSnapshot
+	Positions           string    `gorm:"column:positions;default:''"`     // JSON []PositionSnapshot
 	CreatedAt           time.Time `json:"created_at"`
 }
 
@@ -51,6 +60,12 @@ type DecisionRecord struct {
 	Success             bool               `json:"success"`
 	ErrorMessage        string             `json:"error_message"`
 	AIRequestDurationMs int64              `json:"ai_request_duration_ms"`
+	StrategyID          string             `json:"strategy_id,omitempty"`
+	StrategyVersion     int                `json:"strategy_version,omitempty"` // strategy version that produced this decision
+	AIProvider          string             `json:"ai_provider,omitempty"`      // provider that answered (may be a fallback)
+	AIModel             string             `json:"ai_model,omitempty"`         // model that answered
+	MarketRegime        string             `json:"market_regime,omitempty"`    // regime detected for the cycle, empty when the variant is fixed
+	PromptVariant       string             `json:"prompt_variant,omitempty"`   // system prompt mode variant used
 	AccountState        AccountSnapshot    `json:"account_state"`
 	Positions           []PositionSnapshot `json:"positions"`
 	Decisions           []DecisionAction   `json:"decisions"`
@@ -89,6 +104,7 @@ type DecisionAction struct {
 	TakeProfit float64   `json:"take_profit,omitempty"` // Take profit price
 	Confidence int       `json:"confidence,omitempty"`  // AI confidence (0-100)
 	Reasoning  string    `json:"reasoning,omitempty"`   // Brief reasoning
+	SizingNote string    `json:"sizing_note,omitempty"` // Position sizing rationale
 	OrderID    int64     `json:"order_id"`
 	Timestamp  time.Time `json:"timestamp"`
 	Success    bool      `json:"success"`
@@ -137,10 +153,18 @@ func (db *DecisionRecordDB) toRecord() *DecisionRecord {
 		Success:             db.Success,
 		ErrorMessage:        db.ErrorMessage,
 		AIRequestDurationMs: db.AIRequestDurationMs,
+		StrategyID:          db.StrategyID,
+		StrategyVersion:     db.StrategyVersion,
+		AIProvider:          db.AIProvider,
+		AIModel:             db.AIModel,
+		MarketRegime:        db.MarketRegime,
+		PromptVariant:       db.PromptVariant,
 	}
 	json.Unmarshal([]byte(db.CandidateCoins), &record.CandidateCoins)
 	json.Unmarshal([]byte(db.ExecutionLog), &record.ExecutionLog)
 	json.Unmarshal([]byte(db.Decisions), &record.Decisions)
+	json.Unmarshal([]byte(db.AccountState), &record.AccountState)
+	json.Unmarshal([]byte(db.Positions), &record.Positions)
 	return record
 }
 
@@ -156,6 +180,8 @@ func (s *DecisionStore) LogDecision(record *DecisionRecord) error {
 	candidateCoinsJSON, _ := json.Marshal(record.CandidateCoins)
 	executionLogJSON, _ := json.Marshal(record.ExecutionLog)
 	decisionsJSON, _ := json.Marshal(record.Decisions)
+	accountStateJSON, _ := json.Marshal(record.AccountState)
+	positionsJSON, _ := json.Marshal(record.Positions)
 
 	dbRecord := &DecisionRecordDB{
 		TraderID:            record.TraderID,
@@ -172,6 +198,14 @@ func (s *DecisionStore) LogDecision(record *DecisionRecord) error {
 		Success:             record.Success,
 		ErrorMessage:        record.ErrorMessage,
 		AIRequestDurationMs: record.AIRequestDurationMs,
+		StrategyID:          record.StrategyID,
+		StrategyVersion:     record.StrategyVersion,
+		AIProvider:          record.AIProvider,
+		AIModel:             record.AIModel,
+		MarketRegime:        record.MarketRegime,
+		PromptVariant:       record.PromptVariant,
+		AccountState:        string(accountStateJSON),
+		Positions:           string(positionsJSON),
 	}
 
 	if err := s.db.Create(dbRecord).Error; err != nil {
@@ -246,6 +280,44 @@ func (s *DecisionStore) GetRecordsByDate(traderID string, date time.Time) ([]*De
 	return records, nil
 }
 
+// GetActionRecordsInRange gets the records of the given traders between start
+// and end (inclusive), oldest first, without the prompts, reasoning chain,
+// raw response and execution log. Used to link positions to the decisions
+// that opened and closed them.
+func (s *DecisionStore) GetActionRecordsInRange(traderIDs []string, start, end time.Time) ([]*DecisionRecord, error) {
+	if len(traderIDs) == 0 {
+		return nil, nil
+	}
+	var dbRecords []*DecisionRecordDB
+	err := s.db.Select("id", "trader_id", "cycle_number", "timestamp", "decisions", "success",
+		"strategy_id", "strategy_version", "ai_provider", "ai_model", "market_regime", "prompt_variant").
+		Where("trader_id IN ? AND timestamp >= ? AND timestamp <= ?", traderIDs, start.UTC(), end.UTC()).
+		Order("timestamp ASC").
+		Find(&dbRecords).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query decision records: %w", err)
+	}
+
+	records := make([]*DecisionRecord, len(dbRecords))
+	for i, db := range dbRecords {
+		records[i] = db.toRecord()
+	}
+	return records, nil
+}
+
+// GetRecordByID gets a full decision record, nil when it does not exist
+func (s *DecisionStore) GetRecordByID(id int64) (*DecisionRecord, error) {
+	var dbRecord DecisionRecordDB
+	err := s.db.Where("id = ?", id).First(&dbRecord).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query decision record: %w", err)
+	}
+	return dbRecord.toRecord(), nil
+}
+
 // CleanOldRecords cleans old records from N days ago
 func (s *DecisionStore) CleanOldRecords(traderID string, days int) (int64, error) {
 	cutoffTime := time.Now().AddDate(0, 0, -days)
@@ -258,6 +330,23 @@ func (s *DecisionStore) CleanOldRecords(traderID string, days int) (int64, error
 	return result.RowsAffected, nil
 }
 
+// StripPrompts clears the system prompt, input prompt and raw response of
+// records older than the cutoff. The decisions, reasoning and execution log
+// are kept. Returns the number of records stripped.
+func (s *DecisionStore) StripPrompts(before time.Time) (int64, error) {
+	result := s.db.Model(&DecisionRecordDB{}).
+		Where("timestamp < ? AND (system_prompt <> '' OR input_prompt <> '' OR raw_response <> '')", before).
+		Updates(map[string]interface{}{
+			"system_prompt": "",
+			"input_prompt":  "",
+			"raw_response":  "",
+		})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to strip prompts: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
 // GetStatistics gets statistics information for specified trader
 func (s *DecisionStore) GetStatistics(traderID string) (*Statistics, error) {
 	stats := &Statistics{}
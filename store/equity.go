@@ -93,6 +93,38 @@ func (s *EquityStore) GetByTimeRange(traderID string, start, end time.Time) ([]*
 	return snapshots, nil
 }
 
+// GetFirstSince gets the earliest equity record at or after since, nil when there is none
+func (s *EquityStore) GetFirstSince(traderID string, since time.Time) (*EquitySnapshot, error) {
+	var snapshots []*EquitySnapshot
+	err := s.db.Where("trader_id = ? AND timestamp >= ?", traderID, since.UTC()).
+		Order("timestamp ASC").
+		Limit(1).
+		Find(&snapshots).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query equity records: %w", err)
+	}
+	if len(snapshots) == 0 {
+		return nil, nil
+	}
+	return snapshots[0], nil
+}
+
+// GetPeakEquity gets the highest total equity recorded at or after since (0 when there are no records)
+func (s *EquityStore) GetPeakEquity(traderID string, since time.Time) (float64, error) {
+	var peak *float64
+	err := s.db.Model(&EquitySnapshot{}).
+		Where("trader_id = ? AND timestamp >= ?", traderID, since.UTC()).
+		Select("MAX(total_equity)").
+		Scan(&peak).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to query peak equity: %w", err)
+	}
+	if peak == nil {
+		return 0, nil
+	}
+	return *peak, nil
+}
+
 // GetAllTradersLatest gets latest equity for all traders (for leaderboards)
 func (s *EquityStore) GetAllTradersLatest() (map[string]*EquitySnapshot, error) {
 	// Use raw SQL for this complex query with subquery
@@ -131,6 +163,44 @@ func (s *EquityStore) CleanOldRecords(traderID string, days int) (int64, error)
 	return result.RowsAffected, nil
 }
 
+// Downsample thins a trader's snapshots older than the cutoff to the last
+// snapshot of each bucket (e.g. one per hour). Returns the number deleted.
+func (s *EquityStore) Downsample(traderID string, before time.Time, bucket time.Duration) (int64, error) {
+	var rows []struct {
+		ID        int64
+		Timestamp time.Time
+	}
+	err := s.db.Model(&EquitySnapshot{}).
+		Select("id, timestamp").
+		Where("trader_id = ? AND timestamp < ?", traderID, before).
+		Order("timestamp ASC, id ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to query equity snapshots: %w", err)
+	}
+
+	var drop []int64
+	for i := 0; i+1 < len(rows); i++ {
+		if rows[i].Timestamp.UTC().Truncate(bucket).Equal(rows[i+1].Timestamp.UTC().Truncate(bucket)) {
+			drop = append(drop, rows[i].ID)
+		}
+	}
+
+	var deleted int64
+	for start := 0; start < len(drop); start += 500 {
+		end := start + 500
+		if end > len(drop) {
+			end = len(drop)
+		}
+		result := s.db.Where("id IN ?", drop[start:end]).Delete(&EquitySnapshot{})
+		if result.Error != nil {
+			return deleted, fmt.Errorf("failed to delete equity snapshots: %w", result.Error)
+		}
+		deleted += result.RowsAffected
+	}
+	return deleted, nil
+}
+
 // GetCount gets record count for specified trader
 func (s *EquityStore) GetCount(traderID string) (int, error) {
 	var count int64
@@ -56,45 +56,12 @@ func (s *ExchangeStore) initTables() error {
 		var tableExists int64
 		s.db.Raw(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'exchanges'`).Scan(&tableExists)
 		if tableExists > 0 {
-			// Still run schema/data migrations
-			if err := s.ensureHyperliquidBuilderApprovedColumn(); err != nil {
-				logger.Warnf("Exchange builder approval column migration warning: %v", err)
-			}
-			s.migrateToMultiAccount()
-			s.db.Model(&Exchange{}).Where("account_name = '' OR account_name IS NULL").Update("account_name", "Default")
-			if err := s.cleanupIncompleteExchangeConfigs(); err != nil {
-				logger.Warnf("Exchange cleanup migration warning: %v", err)
-			}
 			return nil
 		}
 	}
 
-	if err := s.db.AutoMigrate(&Exchange{}); err != nil {
-		return err
-	}
-
-	// Run migration to multi-account if needed
-	if err := s.ensureHyperliquidBuilderApprovedColumn(); err != nil {
-		logger.Warnf("Exchange builder approval column migration warning: %v", err)
-	}
-	if err := s.migrateToMultiAccount(); err != nil {
-		logger.Warnf("Multi-account migration warning: %v", err)
-	}
-
-	// Fix empty account_name for existing records
-	s.db.Model(&Exchange{}).Where("account_name = '' OR account_name IS NULL").Update("account_name", "Default")
-	if err := s.cleanupIncompleteExchangeConfigs(); err != nil {
-		logger.Warnf("Exchange cleanup migration warning: %v", err)
-	}
-
-	return nil
-}
-
-func (s *ExchangeStore) ensureHyperliquidBuilderApprovedColumn() error {
-	if s.db.Migrator().HasColumn(&Exchange{}, "HyperliquidBuilderApproved") {
-		return nil
-	}
-	return s.db.Migrator().AddColumn(&Exchange{}, "HyperliquidBuilderApproved")
+	// Column and data migrations run as versioned schema migrations
+	return s.db.AutoMigrate(&Exchange{})
 }
 
 func (s *ExchangeStore) cleanupIncompleteExchangeConfigs() error {
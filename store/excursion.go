@@ -0,0 +1,97 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// PositionExcursion maximum adverse and favorable excursion of a closed
+// position, measured on the price range traded while it was held. Computed
+// once from kline data and kept, since the klines of a closed position never
+// change.
+type PositionExcursion struct {
+	PositionID int64   `gorm:"column:position_id;primaryKey;autoIncrement:false" json:"position_id"`
+	MAEPct     float64 `gorm:"column:mae_pct;not null;default:0" json:"mae_pct"` // worst move against the position, % of entry price (>= 0)
+	MFEPct     float64 `gorm:"column:mfe_pct;not null;default:0" json:"mfe_pct"` // best move in its favor, % of entry price (>= 0)
+	Timeframe  string  `gorm:"column:timeframe;not null;default:''" json:"timeframe"`
+	ComputedAt int64   `gorm:"column:computed_at" json:"computed_at"` // Unix milliseconds UTC
+}
+
+func (PositionExcursion) TableName() string { return "position_excursions" }
+
+// ExcursionUnavailable marks a position whose klines could not be fetched
+// (e.g. a symbol the kline source does not list), so it is not retried.
+// Such rows are left out of the excursion statistics.
+const ExcursionUnavailable = "unavailable"
+
+// ComputeExcursion returns the MAE and MFE in percent of the entry price from
+// the lowest and highest price traded during the hold. Both are unleveraged
+// price moves and never negative.
+func ComputeExcursion(side string, entryPrice, low, high float64) (maePct, mfePct float64) {
+	if entryPrice <= 0 || low <= 0 || high <= 0 {
+		return 0, 0
+	}
+	down := (entryPrice - low) / entryPrice * 100
+	up := (high - entryPrice) / entryPrice * 100
+	if strings.EqualFold(side, "short") {
+		down, up = up, down
+	}
+	return max(down, 0), max(up, 0)
+}
+
+// SaveExcursion stores the excursion of a position, replacing an earlier one
+func (s *PositionStore) SaveExcursion(e *PositionExcursion) error {
+	if e.ComputedAt == 0 {
+		e.ComputedAt = time.Now().UTC().UnixMilli()
+	}
+	err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "position_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"mae_pct", "mfe_pct", "timeframe", "computed_at"}),
+	}).Create(e).Error
+	if err != nil {
+		return fmt.Errorf("failed to save position excursion: %w", err)
+	}
+	return nil
+}
+
+// GetExcursions returns the stored excursions of the given positions, keyed
+// by position ID. Positions without one are absent.
+func (s *PositionStore) GetExcursions(positionIDs []int64) (map[int64]*PositionExcursion, error) {
+	result := make(map[int64]*PositionExcursion, len(positionIDs))
+	if len(positionIDs) == 0 {
+		return result, nil
+	}
+	var rows []*PositionExcursion
+	if err := s.db.Where("position_id IN ?", positionIDs).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to query position excursions: %w", err)
+	}
+	for _, row := range rows {
+		result[row.PositionID] = row
+	}
+	return result, nil
+}
+
+// ClosedPositionsWithoutExcursion returns the closed positions in the
+// filter's range that have no excursion stored yet, newest first
+func (s *PositionStore) ClosedPositionsWithoutExcursion(filter AnalyticsFilter, limit int) ([]TraderPosition, error) {
+	query := s.closedPositionsByTraderFilters(filter.TraderIDs, filter.TraderIDPatterns).
+		Where("exit_time > entry_time AND entry_price > 0").
+		Where("id NOT IN (?)", s.db.Model(&PositionExcursion{}).Select("position_id"))
+	if !filter.Since.IsZero() {
+		query = query.Where("exit_time >= ?", filter.Since.UnixMilli())
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where("exit_time <= ?", filter.Until.UnixMilli())
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	var positions []TraderPosition
+	if err := query.Order("exit_time DESC").Find(&positions).Error; err != nil {
+		return nil, fmt.Errorf("failed to query positions without excursion: %w", err)
+	}
+	return positions, nil
+}
@@ -0,0 +1,131 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FundingPayment a funding fee settled on a perpetual position, pulled from
+// the exchange's funding history. Payments are attributed to the position
+// open on the symbol at settlement time; the amount accumulates in
+// TraderPosition.FundingFee.
+type FundingPayment struct {
+	ID         int64   `gorm:"primaryKey;autoIncrement" json:"id"`
+	TraderID   string  `gorm:"column:trader_id;not null;uniqueIndex:idx_funding_trader_payment;index:idx_funding_trader_time" json:"trader_id"`
+	PaymentID  string  `gorm:"column:payment_id;not null;uniqueIndex:idx_funding_trader_payment" json:"payment_id"`
+	Symbol     string  `gorm:"column:symbol;not null" json:"symbol"`
+	Amount     float64 `gorm:"column:amount;not null;default:0" json:"amount"` // positive = received, negative = paid
+	Rate       float64 `gorm:"column:rate;default:0" json:"rate"`
+	PositionID int64   `gorm:"column:position_id;default:0;index" json:"position_id"`          // 0 = no open position matched
+	Time       int64   `gorm:"column:time;not null;index:idx_funding_trader_time" json:"time"` // Unix milliseconds UTC
+	CreatedAt  int64   `gorm:"column:created_at" json:"created_at"`                            // Unix milliseconds UTC
+}
+
+func (FundingPayment) TableName() string { return "trader_funding_payments" }
+
+// FundingStore funding payment storage
+type FundingStore struct {
+	db *gorm.DB
+}
+
+// NewFundingStore creates a new FundingStore
+func NewFundingStore(db *gorm.DB) *FundingStore {
+	return &FundingStore{db: db}
+}
+
+func (s *FundingStore) initTables() error {
+	return s.db.AutoMigrate(&FundingPayment{})
+}
+
+// RecordPayments stores new funding payments and adds each one to the
+// funding fee of the position held on its symbol when it settled.
+// Payments already recorded are skipped. Returns the number of new payments.
+func (s *FundingStore) RecordPayments(traderID string, payments []FundingPayment) (int, error) {
+	if len(payments) == 0 {
+		return 0, nil
+	}
+	recorded := 0
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		earliest := payments[0].Time
+		for _, p := range payments {
+			if p.Time < earliest {
+				earliest = p.Time
+			}
+		}
+		// Positions closed since the earliest settlement still get their funding
+		var positions []TraderPosition
+		if err := tx.Where("trader_id = ? AND (status = ? OR exit_time >= ?)", traderID, "OPEN", earliest).
+			Order("entry_time ASC").
+			Find(&positions).Error; err != nil {
+			return fmt.Errorf("failed to query positions: %w", err)
+		}
+
+		nowMs := time.Now().UTC().UnixMilli()
+		for i := range payments {
+			p := payments[i]
+			p.ID = 0
+			p.TraderID = traderID
+			p.CreatedAt = nowMs
+			if pos := fundingPosition(positions, p.Symbol, p.Time); pos != nil {
+				p.PositionID = pos.ID
+			}
+
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&p)
+			if result.Error != nil {
+				return fmt.Errorf("failed to save funding payment: %w", result.Error)
+			}
+			if result.RowsAffected == 0 {
+				continue // already recorded
+			}
+			recorded++
+
+			if p.PositionID > 0 {
+				if err := tx.Model(&TraderPosition{}).Where("id = ?", p.PositionID).Updates(map[string]interface{}{
+					"funding_fee": gorm.Expr("funding_fee + ?", p.Amount),
+					"updated_at":  nowMs,
+				}).Error; err != nil {
+					return fmt.Errorf("failed to update position funding: %w", err)
+				}
+			}
+		}
+		return nil
+	})
+	return recorded, err
+}
+
+// fundingPosition finds the position a payment settles on: same base asset,
+// held at the settlement time. In hedge mode with both sides open the payment
+// goes to the older position, as exchanges don't report the side.
+func fundingPosition(positions []TraderPosition, symbol string, settledAt int64) *TraderPosition {
+	base := AssetBase(symbol)
+	for i := range positions {
+		pos := &positions[i]
+		if AssetBase(pos.Symbol) != base || pos.EntryTime > settledAt {
+			continue
+		}
+		if pos.Status == "OPEN" || pos.ExitTime >= settledAt {
+			return pos
+		}
+	}
+	return nil
+}
+
+// GetLatestTime returns the settlement time (Unix ms) of the newest recorded
+// payment, 0 when there is none
+func (s *FundingStore) GetLatestTime(traderID string) (int64, error) {
+	var latest *int64
+	err := s.db.Model(&FundingPayment{}).
+		Where("trader_id = ?", traderID).
+		Select("MAX(time)").
+		Scan(&latest).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to query latest funding payment: %w", err)
+	}
+	if latest == nil {
+		return 0, nil
+	}
+	return *latest, nil
+}
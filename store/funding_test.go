@@ -0,0 +1,64 @@
+package store
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRecordFundingPaymentsAttributesToHeldPositions(t *testing.T) {
+	st := newTestStore(t)
+	db := st.GormDB()
+	positions, funding := st.Position(), st.Funding()
+
+	now := time.Now().UTC()
+	open := &TraderPosition{TraderID: "t1", Symbol: "BTCUSDT", Side: "LONG", Quantity: 1, EntryPrice: 100, EntryTime: now.Add(-10 * time.Hour).UnixMilli()}
+	if err := positions.Create(open); err != nil {
+		t.Fatalf("create open position: %v", err)
+	}
+	closed := &TraderPosition{TraderID: "t1", Symbol: "ETHUSDT", Side: "SHORT", Quantity: 1, EntryPrice: 10,
+		EntryTime: now.Add(-20 * time.Hour).UnixMilli(), ExitTime: now.Add(-5 * time.Hour).UnixMilli(), RealizedPnL: 3, Status: "CLOSED"}
+	if err := db.Create(closed).Error; err != nil {
+		t.Fatalf("create closed position: %v", err)
+	}
+
+	payments := []FundingPayment{
+		{PaymentID: "1", Symbol: "BTCUSDT", Amount: -1.5, Time: now.Add(-8 * time.Hour).UnixMilli()},
+		{PaymentID: "2", Symbol: "BTC-USDT", Amount: -0.5, Time: now.Add(-1 * time.Hour).UnixMilli()},
+		{PaymentID: "3", Symbol: "ETHUSDT", Amount: 0.25, Time: now.Add(-6 * time.Hour).UnixMilli()},
+		{PaymentID: "4", Symbol: "ETHUSDT", Amount: 9, Time: now.Add(-1 * time.Hour).UnixMilli()},  // after the close
+		{PaymentID: "5", Symbol: "BTCUSDT", Amount: 9, Time: now.Add(-11 * time.Hour).UnixMilli()}, // before the open
+	}
+	recorded, err := funding.RecordPayments("t1", payments)
+	if err != nil || recorded != 5 {
+		t.Fatalf("recorded %d (%v), want 5", recorded, err)
+	}
+	// Re-reading the same history books nothing twice
+	if recorded, err = funding.RecordPayments("t1", payments); err != nil || recorded != 0 {
+		t.Fatalf("re-recorded %d (%v), want 0", recorded, err)
+	}
+
+	var got TraderPosition
+	db.First(&got, open.ID)
+	if math.Abs(got.FundingFee-(-2)) > 1e-9 {
+		t.Fatalf("open position funding = %v, want -2", got.FundingFee)
+	}
+	var gotClosed TraderPosition
+	db.First(&gotClosed, closed.ID)
+	if math.Abs(gotClosed.FundingFee-0.25) > 1e-9 {
+		t.Fatalf("closed position funding = %v, want 0.25", gotClosed.FundingFee)
+	}
+
+	stats, err := positions.GetFullStats("t1", 0)
+	if err != nil {
+		t.Fatalf("full stats: %v", err)
+	}
+	if math.Abs(stats.TotalPnL-3.25) > 1e-9 || math.Abs(stats.TotalFunding-0.25) > 1e-9 {
+		t.Fatalf("stats pnl=%v funding=%v, want 3.25 and 0.25", stats.TotalPnL, stats.TotalFunding)
+	}
+
+	latest, err := funding.GetLatestTime("t1")
+	if err != nil || latest != payments[1].Time {
+		t.Fatalf("latest = %d (%v), want %d", latest, err, payments[1].Time)
+	}
+}
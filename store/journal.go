@@ -0,0 +1,327 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PositionNote a user's review notes and tags on a position
+type PositionNote struct {
+	PositionID int64     `gorm:"column:position_id;primaryKey;autoIncrement:false" json:"position_id"`
+	Note       string    `gorm:"column:note;type:text;not null;default:''" json:"note"`
+	Tags       string    `gorm:"column:tags;not null;default:'[]'" json:"-"` // JSON array of lowercase tags
+	UpdatedBy  string    `gorm:"column:updated_by;not null;default:''" json:"updated_by"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (PositionNote) TableName() string { return "position_notes" }
+
+// TagList the note's tags
+func (n *PositionNote) TagList() []string {
+	tags := []string{}
+	_ = json.Unmarshal([]byte(n.Tags), &tags)
+	return tags
+}
+
+// NormalizeTags trims and lowercases tags, dropping empty and repeated ones
+func NormalizeTags(tags []string) []string {
+	result := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	return result
+}
+
+// JournalDecision the decision action that opened or closed a position, with
+// the AI's reasoning and the market context of its cycle
+type JournalDecision struct {
+	DecisionID      int64     `json:"decision_id"`
+	CycleNumber     int       `json:"cycle_number"`
+	Timestamp       time.Time `json:"timestamp"`
+	Action          string    `json:"action"`
+	Confidence      int       `json:"confidence"`
+	Reasoning       string    `json:"reasoning"`
+	SizingNote      string    `json:"sizing_note,omitempty"`
+	Price           float64   `json:"price"` // market price when the action was taken
+	StopLoss        float64   `json:"stop_loss,omitempty"`
+	TakeProfit      float64   `json:"take_profit,omitempty"`
+	Leverage        int       `json:"leverage,omitempty"`
+	MarketRegime    string    `json:"market_regime,omitempty"`
+	PromptVariant   string    `json:"prompt_variant,omitempty"`
+	AIProvider      string    `json:"ai_provider,omitempty"`
+	AIModel         string    `json:"ai_model,omitempty"`
+	StrategyID      string    `json:"strategy_id,omitempty"`
+	StrategyVersion int       `json:"strategy_version,omitempty"`
+	// The cycle's context, single-position view only
+	CoTTrace       string             `json:"cot_trace,omitempty"`
+	AccountState   *AccountSnapshot   `json:"account_state,omitempty"`
+	Positions      []PositionSnapshot `json:"positions,omitempty"` // positions open when the cycle decided
+	CandidateCoins []string           `json:"candidate_coins,omitempty"`
+	InputPrompt    string             `json:"input_prompt,omitempty"` // the market data the AI was given
+}
+
+// JournalEntry a position next to the decisions that opened and closed it
+// and the user's review notes. Closing is nil for open positions and for
+// positions closed by the exchange (stop loss, take profit, liquidation) or
+// by hand; see Position.CloseReason.
+type JournalEntry struct {
+	Position      TraderPosition     `json:"position"`
+	NetPnL        float64            `json:"net_pnl"`
+	HoldMins      float64            `json:"hold_mins"`
+	Opening       *JournalDecision   `json:"opening"`
+	Closing       *JournalDecision   `json:"closing"`
+	Excursion     *PositionExcursion `json:"excursion,omitempty"`
+	Note          string             `json:"note"`
+	Tags          []string           `json:"tags"`
+	NoteUpdatedAt *time.Time         `json:"note_updated_at,omitempty"`
+}
+
+// JournalFilter selects the closed positions of a journal
+type JournalFilter struct {
+	TraderIDs        []string
+	TraderIDPatterns []string  // legacy trader ID patterns (see closedPositionsByTraderFilters)
+	Since            time.Time // closed at or after; zero = unbounded
+	Until            time.Time // closed at or before; zero = unbounded
+	Symbol           string
+	Tag              string
+	Limit            int // 0 = all
+}
+
+// SaveNote sets the notes and tags of a position, replacing earlier ones
+func (s *PositionStore) SaveNote(positionID int64, note string, tags []string, updatedBy string) (*PositionNote, error) {
+	tagsJSON, _ := json.Marshal(NormalizeTags(tags))
+	row := &PositionNote{
+		PositionID: positionID,
+		Note:       strings.TrimSpace(note),
+		Tags:       string(tagsJSON),
+		UpdatedBy:  updatedBy,
+		UpdatedAt:  time.Now().UTC(),
+	}
+	err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "position_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"note", "tags", "updated_by", "updated_at"}),
+	}).Create(row).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to save position note: %w", err)
+	}
+	return row, nil
+}
+
+// GetNotes returns the notes of the given positions, keyed by position ID
+func (s *PositionStore) GetNotes(positionIDs []int64) (map[int64]*PositionNote, error) {
+	result := make(map[int64]*PositionNote, len(positionIDs))
+	if len(positionIDs) == 0 {
+		return result, nil
+	}
+	var rows []*PositionNote
+	if err := s.db.Where("position_id IN ?", positionIDs).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to query position notes: %w", err)
+	}
+	for _, row := range rows {
+		result[row.PositionID] = row
+	}
+	return result, nil
+}
+
+// GetByTraderFilters gets a position of any status if it belongs to the
+// trader IDs or legacy patterns, nil otherwise
+func (s *PositionStore) GetByTraderFilters(positionID int64, traderIDs []string, traderIDPatterns []string) (*TraderPosition, error) {
+	var pos TraderPosition
+	err := s.positionsByTraderFilters(traderIDs, traderIDPatterns).Where("id = ?", positionID).First(&pos).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query position: %w", err)
+	}
+	return &pos, nil
+}
+
+// GetJournal builds the journal of the closed positions matching the filter,
+// most recently closed first
+func (s *Store) GetJournal(filter JournalFilter) ([]JournalEntry, error) {
+	tag := strings.ToLower(strings.TrimSpace(filter.Tag))
+	// Tags live in a JSON column, so a tag filter is applied after the notes
+	// are loaded and the limit only after it
+	limit := filter.Limit
+	if tag != "" {
+		limit = 0
+	}
+	positions, err := s.Position().journalPositions(filter, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.journalEntries(positions)
+	if err != nil {
+		return nil, err
+	}
+	if tag != "" {
+		kept := entries[:0]
+		for _, e := range entries {
+			for _, t := range e.Tags {
+				if t == tag {
+					kept = append(kept, e)
+					break
+				}
+			}
+		}
+		entries = kept
+		if filter.Limit > 0 && len(entries) > filter.Limit {
+			entries = entries[:filter.Limit]
+		}
+	}
+	return entries, nil
+}
+
+// journalPositions lists the closed positions of a journal, most recently
+// closed first; limit 0 = all
+func (s *PositionStore) journalPositions(filter JournalFilter, limit int) ([]TraderPosition, error) {
+	query := s.closedPositionsByTraderFilters(filter.TraderIDs, filter.TraderIDPatterns)
+	if !filter.Since.IsZero() {
+		query = query.Where("exit_time >= ?", filter.Since.UnixMilli())
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where("exit_time <= ?", filter.Until.UnixMilli())
+	}
+	if symbol := strings.TrimSpace(filter.Symbol); symbol != "" {
+		query = query.Where("UPPER(symbol) = ?", strings.ToUpper(symbol))
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	var positions []TraderPosition
+	if err := query.Order("exit_time DESC, id DESC").Find(&positions).Error; err != nil {
+		return nil, fmt.Errorf("failed to query journal positions: %w", err)
+	}
+	return positions, nil
+}
+
+// GetJournalEntry builds the journal entry of one position, including the
+// full chain-of-thought, account state, open positions and market data of its
+// opening and closing cycles. Returns nil when the position does not belong
+// to the trader IDs or patterns.
+func (s *Store) GetJournalEntry(positionID int64, traderIDs []string, traderIDPatterns []string) (*JournalEntry, error) {
+	pos, err := s.Position().GetByTraderFilters(positionID, traderIDs, traderIDPatterns)
+	if err != nil || pos == nil {
+		return nil, err
+	}
+	entries, err := s.journalEntries([]TraderPosition{*pos})
+	if err != nil {
+		return nil, err
+	}
+	entry := &entries[0]
+	for _, d := range []*JournalDecision{entry.Opening, entry.Closing} {
+		if d == nil {
+			continue
+		}
+		record, err := s.Decision().GetRecordByID(d.DecisionID)
+		if err != nil {
+			return nil, err
+		}
+		if record != nil {
+			account := record.AccountState
+			d.CoTTrace = record.CoTTrace
+			d.AccountState = &account
+			d.Positions = record.Positions
+			d.CandidateCoins = record.CandidateCoins
+			d.InputPrompt = record.InputPrompt
+		}
+	}
+	return entry, nil
+}
+
+// journalEntries links the positions to their decisions, excursions and
+// notes, keeping their order
+func (s *Store) journalEntries(positions []TraderPosition) ([]JournalEntry, error) {
+	openings, err := s.linkDecisions(positions, false)
+	if err != nil {
+		return nil, err
+	}
+	// A partial close sets the exit of a position that is still open
+	closed := make([]TraderPosition, 0, len(positions))
+	for _, pos := range positions {
+		if pos.Status == "CLOSED" {
+			closed = append(closed, pos)
+		}
+	}
+	closings, err := s.linkDecisions(closed, true)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, len(positions))
+	for i, pos := range positions {
+		ids[i] = pos.ID
+	}
+	excursions, err := s.Position().GetExcursions(ids)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := s.Position().GetNotes(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]JournalEntry, len(positions))
+	for i, pos := range positions {
+		entry := JournalEntry{Position: pos, NetPnL: pos.NetPnL(), Tags: []string{}}
+		if pos.ExitTime > pos.EntryTime {
+			entry.HoldMins = float64(pos.ExitTime-pos.EntryTime) / 60000.0
+		}
+		if m, ok := openings[pos.ID]; ok {
+			entry.Opening = newJournalDecision(m)
+		}
+		if m, ok := closings[pos.ID]; ok {
+			entry.Closing = newJournalDecision(m)
+		}
+		if e, ok := excursions[pos.ID]; ok && e.Timeframe != ExcursionUnavailable {
+			entry.Excursion = e
+		}
+		if n, ok := notes[pos.ID]; ok {
+			entry.Note = n.Note
+			entry.Tags = n.TagList()
+			updated := n.UpdatedAt.UTC()
+			entry.NoteUpdatedAt = &updated
+		}
+		entries[i] = entry
+	}
+	return entries, nil
+}
+
+func newJournalDecision(m matchedDecision) *JournalDecision {
+	at := m.Action.Timestamp
+	if at.IsZero() {
+		at = m.Record.Timestamp
+	}
+	return &JournalDecision{
+		DecisionID:      m.Record.ID,
+		CycleNumber:     m.Record.CycleNumber,
+		Timestamp:       at.UTC(),
+		Action:          m.Action.Action,
+		Confidence:      m.Action.Confidence,
+		Reasoning:       m.Action.Reasoning,
+		SizingNote:      m.Action.SizingNote,
+		Price:           m.Action.Price,
+		StopLoss:        m.Action.StopLoss,
+		TakeProfit:      m.Action.TakeProfit,
+		Leverage:        m.Action.Leverage,
+		MarketRegime:    m.Record.MarketRegime,
+		PromptVariant:   m.Record.PromptVariant,
+		AIProvider:      m.Record.AIProvider,
+		AIModel:         m.Record.AIModel,
+		StrategyID:      m.Record.StrategyID,
+		StrategyVersion: m.Record.StrategyVersion,
+	}
+}
@@ -0,0 +1,118 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJournalLinksOpeningAndClosingDecisions(t *testing.T) {
+	st := newTestStore(t)
+	db := st.GormDB()
+	base := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+
+	positions := []*TraderPosition{
+		{TraderID: "t1", Symbol: "BTCUSDT", Side: "LONG", Quantity: 1, Leverage: 5, EntryPrice: 100, ExitPrice: 104,
+			EntryTime: base.UnixMilli(), ExitTime: base.Add(2 * time.Hour).UnixMilli(), RealizedPnL: 4, Status: "CLOSED"},
+		{TraderID: "t1", Symbol: "ETHUSDT", Side: "SHORT", Quantity: 1, Leverage: 3, EntryPrice: 50, ExitPrice: 52,
+			EntryTime: base.Add(3 * time.Hour).UnixMilli(), ExitTime: base.Add(4 * time.Hour).UnixMilli(), RealizedPnL: -2,
+			Status: "CLOSED", CloseReason: "stop_loss"},
+		// Another trader's position on the same symbol and side must not take t1's decisions
+		{TraderID: "t2", Symbol: "BTCUSDT", Side: "LONG", Quantity: 1, EntryPrice: 100, ExitPrice: 101,
+			EntryTime: base.UnixMilli(), ExitTime: base.Add(2 * time.Hour).UnixMilli(), RealizedPnL: 1, Status: "CLOSED"},
+	}
+	for _, pos := range positions {
+		if err := db.Create(pos).Error; err != nil {
+			t.Fatalf("create position: %v", err)
+		}
+	}
+
+	decisions := []*DecisionRecord{
+		{TraderID: "t1", CycleNumber: 10, Timestamp: base.Add(-time.Minute), Success: true, CoTTrace: "trend is up",
+			MarketRegime: "trending", AIProvider: "deepseek", AIModel: "deepseek-chat",
+			InputPrompt: "BTCUSDT 1h: price 99.8, EMA20 98.5", CandidateCoins: []string{"BTCUSDT", "SOLUSDT"},
+			AccountState: AccountSnapshot{TotalBalance: 1000, AvailableBalance: 900, PositionCount: 1, MarginUsedPct: 10},
+			Positions:    []PositionSnapshot{{Symbol: "SOLUSDT", Side: "long", PositionAmt: 2, EntryPrice: 150, Leverage: 3}},
+			Decisions: []DecisionAction{{Action: "open_long", Symbol: "BTCUSDT", Confidence: 82, Reasoning: "breakout above range",
+				Price: 99.8, StopLoss: 97, TakeProfit: 106, Leverage: 5, Success: true, Timestamp: base.Add(-time.Minute)}}},
+		{TraderID: "t1", CycleNumber: 14, Timestamp: base.Add(2*time.Hour - 30*time.Second), Success: true, CoTTrace: "momentum fading",
+			Decisions: []DecisionAction{{Action: "close_long", Symbol: "BTCUSDT", Confidence: 70, Reasoning: "take profit into resistance",
+				Success: true, Timestamp: base.Add(2*time.Hour - 30*time.Second)}}},
+		{TraderID: "t1", CycleNumber: 16, Timestamp: base.Add(3 * time.Hour), Success: true,
+			Decisions: []DecisionAction{{Action: "open_short", Symbol: "ETHUSDT", Confidence: 65, Reasoning: "rejected at resistance",
+				Success: true, Timestamp: base.Add(3 * time.Hour)}}},
+	}
+	for _, d := range decisions {
+		if err := st.Decision().LogDecision(d); err != nil {
+			t.Fatalf("log decision: %v", err)
+		}
+	}
+
+	if _, err := st.Position().SaveNote(positions[1].ID, " moved the stop too tight ", []string{"Tight-Stop", "review", "tight-stop "}, "u1"); err != nil {
+		t.Fatalf("save note: %v", err)
+	}
+
+	entries, err := st.GetJournal(JournalFilter{TraderIDs: []string{"t1"}})
+	if err != nil {
+		t.Fatalf("GetJournal: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Position.ID != positions[1].ID {
+		t.Fatalf("entries = %d, want t1's 2 positions, most recently closed first", len(entries))
+	}
+
+	eth, btc := entries[0], entries[1]
+	if btc.Opening == nil || btc.Opening.Reasoning != "breakout above range" || btc.Opening.Confidence != 82 ||
+		btc.Opening.StopLoss != 97 || btc.Opening.MarketRegime != "trending" || btc.Opening.AIModel != "deepseek-chat" {
+		t.Fatalf("BTC opening = %+v", btc.Opening)
+	}
+	if btc.Closing == nil || btc.Closing.CycleNumber != 14 || btc.Closing.Reasoning != "take profit into resistance" {
+		t.Fatalf("BTC closing = %+v", btc.Closing)
+	}
+	if btc.Opening.CoTTrace != "" || btc.Opening.AccountState != nil || btc.Opening.InputPrompt != "" {
+		t.Fatal("the journal list should not carry the cycle context")
+	}
+	if eth.Opening == nil || eth.Closing != nil {
+		t.Fatalf("ETH opening = %+v, closing = %+v; want an opening and no closing (stop loss)", eth.Opening, eth.Closing)
+	}
+	if eth.Note != "moved the stop too tight" || len(eth.Tags) != 2 || eth.Tags[0] != "tight-stop" {
+		t.Fatalf("ETH note = %q, tags = %v", eth.Note, eth.Tags)
+	}
+
+	tagged, err := st.GetJournal(JournalFilter{TraderIDs: []string{"t1"}, Tag: "Review"})
+	if err != nil {
+		t.Fatalf("GetJournal by tag: %v", err)
+	}
+	if len(tagged) != 1 || tagged[0].Position.ID != positions[1].ID {
+		t.Fatalf("tag filter returned %d entries", len(tagged))
+	}
+
+	limited, err := st.GetJournal(JournalFilter{TraderIDs: []string{"t1"}, Limit: 1})
+	if err != nil {
+		t.Fatalf("GetJournal with limit: %v", err)
+	}
+	if len(limited) != 1 || limited[0].Position.ID != positions[1].ID {
+		t.Fatalf("limit 1 returned %d entries, want the most recently closed", len(limited))
+	}
+	bySymbol, err := st.GetJournal(JournalFilter{TraderIDs: []string{"t1"}, Symbol: "btcusdt"})
+	if err != nil {
+		t.Fatalf("GetJournal by symbol: %v", err)
+	}
+	if len(bySymbol) != 1 || bySymbol[0].Position.ID != positions[0].ID {
+		t.Fatalf("symbol filter returned %d entries", len(bySymbol))
+	}
+
+	entry, err := st.GetJournalEntry(positions[0].ID, []string{"t1"}, nil)
+	if err != nil {
+		t.Fatalf("GetJournalEntry: %v", err)
+	}
+	if entry.Opening.CoTTrace != "trend is up" || entry.Closing.CoTTrace != "momentum fading" {
+		t.Fatalf("chain-of-thought = %q / %q", entry.Opening.CoTTrace, entry.Closing.CoTTrace)
+	}
+	opening := entry.Opening
+	if opening.AccountState == nil || opening.AccountState.TotalBalance != 1000 || len(opening.Positions) != 1 ||
+		opening.Positions[0].Symbol != "SOLUSDT" || len(opening.CandidateCoins) != 2 || opening.InputPrompt == "" {
+		t.Fatalf("opening cycle context = %+v", opening)
+	}
+	if other, err := st.GetJournalEntry(positions[2].ID, []string{"t1"}, nil); err != nil || other != nil {
+		t.Fatalf("another trader's position = %+v, %v; want nil", other, err)
+	}
+}
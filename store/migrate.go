@@ -0,0 +1,262 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration a numbered schema change. Migrations are applied in version order
+// and recorded in schema_migrations. Up must be idempotent: databases created
+// before versioning existed replay every migration once.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(db *gorm.DB) error
+	Down    func(db *gorm.DB) error // nil = irreversible
+	// NoTransaction runs the step outside a transaction. Needed for steps
+	// that tolerate failing statements, which abort a PostgreSQL transaction.
+	NoTransaction bool
+}
+
+// Reversible reports whether the migration can be rolled back
+func (m Migration) Reversible() bool {
+	return m.Down != nil
+}
+
+// SchemaMigration an applied migration
+type SchemaMigration struct {
+	Version   int       `gorm:"column:version;primaryKey;autoIncrement:false" json:"version"`
+	Name      string    `gorm:"column:name;not null;default:''" json:"name"`
+	AppliedAt time.Time `gorm:"column:applied_at" json:"applied_at"`
+}
+
+func (SchemaMigration) TableName() string { return "schema_migrations" }
+
+// MigrationStatus a known migration and whether it is applied
+type MigrationStatus struct {
+	Version    int        `json:"version"`
+	Name       string     `json:"name"`
+	Applied    bool       `json:"applied"`
+	AppliedAt  *time.Time `json:"applied_at,omitempty"`
+	Reversible bool       `json:"reversible"`
+}
+
+// SchemaTooNewError the database was migrated by a newer nofx than this binary
+type SchemaTooNewError struct {
+	DBVersion     int
+	BinaryVersion int
+}
+
+func (e *SchemaTooNewError) Error() string {
+	return fmt.Sprintf("database schema version %d is newer than this binary supports (%d); upgrade nofx or restore a matching backup",
+		e.DBVersion, e.BinaryVersion)
+}
+
+// ErrIrreversibleMigration a rollback hit a migration without a Down step
+var ErrIrreversibleMigration = errors.New("migration is irreversible")
+
+// Migrator applies and rolls back schema migrations
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+	// DryRun reports the planned migrations without executing them
+	DryRun bool
+}
+
+// NewMigrator creates a migrator for the registered schema migrations
+func NewMigrator(db *gorm.DB) *Migrator {
+	return newMigrator(db, schemaMigrations)
+}
+
+func newMigrator(db *gorm.DB, migrations []Migration) *Migrator {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	return &Migrator{db: db, migrations: sorted}
+}
+
+// LatestVersion the newest schema version this binary knows
+func (m *Migrator) LatestVersion() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+func (m *Migrator) ensureTable() error {
+	if m.db.Migrator().HasTable(&SchemaMigration{}) {
+		return nil
+	}
+	if err := m.db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+	return nil
+}
+
+// applied returns the applied migrations keyed by version
+func (m *Migrator) applied() (map[int]SchemaMigration, error) {
+	result := make(map[int]SchemaMigration)
+	if !m.db.Migrator().HasTable(&SchemaMigration{}) {
+		return result, nil
+	}
+	var rows []SchemaMigration
+	if err := m.db.Order("version ASC").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	for _, row := range rows {
+		result[row.Version] = row
+	}
+	return result, nil
+}
+
+// CurrentVersion the highest applied schema version, 0 for an unversioned database
+func (m *Migrator) CurrentVersion() (int, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return 0, err
+	}
+	current := 0
+	for version := range applied {
+		if version > current {
+			current = version
+		}
+	}
+	return current, nil
+}
+
+// CheckVersion fails with a SchemaTooNewError when the database has a
+// migration applied that this binary does not know
+func (m *Migrator) CheckVersion() error {
+	current, err := m.CurrentVersion()
+	if err != nil {
+		return err
+	}
+	if latest := m.LatestVersion(); current > latest {
+		return &SchemaTooNewError{DBVersion: current, BinaryVersion: latest}
+	}
+	return nil
+}
+
+// Status lists every known migration with its applied state
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		st := MigrationStatus{Version: mig.Version, Name: mig.Name, Reversible: mig.Reversible()}
+		if row, ok := applied[mig.Version]; ok {
+			appliedAt := row.AppliedAt
+			st.Applied = true
+			st.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, st)
+	}
+	return statuses, nil
+}
+
+// Up applies the pending migrations up to target (0 = latest) and returns
+// them in the order they ran. In dry-run mode nothing is executed.
+func (m *Migrator) Up(target int) ([]Migration, error) {
+	if err := m.CheckVersion(); err != nil {
+		return nil, err
+	}
+	if target <= 0 {
+		target = m.LatestVersion()
+	}
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, mig := range m.migrations {
+		if _, ok := applied[mig.Version]; !ok && mig.Version <= target {
+			pending = append(pending, mig)
+		}
+	}
+	if m.DryRun || len(pending) == 0 {
+		return pending, nil
+	}
+
+	if err := m.ensureTable(); err != nil {
+		return nil, err
+	}
+	done := make([]Migration, 0, len(pending))
+	for _, mig := range pending {
+		record := func(db *gorm.DB) error {
+			if err := mig.Up(db); err != nil {
+				return err
+			}
+			return db.Create(&SchemaMigration{Version: mig.Version, Name: mig.Name, AppliedAt: time.Now().UTC()}).Error
+		}
+		if err := m.run(mig, record); err != nil {
+			return done, fmt.Errorf("migration %d (%s) failed: %w", mig.Version, mig.Name, err)
+		}
+		done = append(done, mig)
+	}
+	return done, nil
+}
+
+// Down rolls back the newest `steps` applied migrations and returns them in
+// the order they were rolled back. Nothing is rolled back when one of them is
+// irreversible.
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	if err := m.CheckVersion(); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var plan []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(plan) < steps; i-- {
+		mig := m.migrations[i]
+		if _, ok := applied[mig.Version]; !ok {
+			continue
+		}
+		if !mig.Reversible() {
+			return nil, fmt.Errorf("cannot roll back migration %d (%s): %w", mig.Version, mig.Name, ErrIrreversibleMigration)
+		}
+		plan = append(plan, mig)
+	}
+	if m.DryRun {
+		return plan, nil
+	}
+
+	done := make([]Migration, 0, len(plan))
+	for _, mig := range plan {
+		unrecord := func(db *gorm.DB) error {
+			if err := mig.Down(db); err != nil {
+				return err
+			}
+			return db.Delete(&SchemaMigration{}, "version = ?", mig.Version).Error
+		}
+		if err := m.run(mig, unrecord); err != nil {
+			return done, fmt.Errorf("rollback of migration %d (%s) failed: %w", mig.Version, mig.Name, err)
+		}
+		done = append(done, mig)
+	}
+	return done, nil
+}
+
+func (m *Migrator) run(mig Migration, fn func(db *gorm.DB) error) error {
+	if mig.NoTransaction {
+		return fn(m.db)
+	}
+	return m.db.Transaction(fn)
+}
+
+// dialectSQL picks the statement for the connection's dialect
+func dialectSQL(db *gorm.DB, sqlite, postgres string) string {
+	if db.Dialector.Name() == "postgres" {
+		return postgres
+	}
+	return sqlite
+}
@@ -0,0 +1,201 @@
+package store
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newTestMigrateDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open in-memory sqlite: %v", err)
+	}
+	return db
+}
+
+func testMigrations() []Migration {
+	return []Migration{
+		{
+			Version: 1,
+			Name:    "create widgets",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`CREATE TABLE IF NOT EXISTS widgets (id INTEGER PRIMARY KEY)`).Error
+			},
+		},
+		{
+			Version: 2,
+			Name:    "add widgets.color",
+			Up:      func(db *gorm.DB) error { return db.Exec(`ALTER TABLE widgets ADD COLUMN color TEXT`).Error },
+			Down:    func(db *gorm.DB) error { return db.Exec(`ALTER TABLE widgets DROP COLUMN color`).Error },
+		},
+		{
+			Version: 3,
+			Name:    "create gadgets",
+			Up:      func(db *gorm.DB) error { return db.Exec(`CREATE TABLE gadgets (id INTEGER PRIMARY KEY)`).Error },
+			Down:    func(db *gorm.DB) error { return db.Exec(`DROP TABLE gadgets`).Error },
+		},
+	}
+}
+
+func TestMigratorUpDown(t *testing.T) {
+	db := newTestMigrateDB(t)
+	m := newMigrator(db, testMigrations())
+
+	applied, err := m.Up(2)
+	if err != nil {
+		t.Fatalf("Up(2): %v", err)
+	}
+	if len(applied) != 2 {
+		t.Fatalf("Up(2) applied %d migrations, want 2", len(applied))
+	}
+	if v, _ := m.CurrentVersion(); v != 2 {
+		t.Fatalf("version after Up(2) = %d, want 2", v)
+	}
+
+	applied, err = m.Up(0)
+	if err != nil || len(applied) != 1 || applied[0].Version != 3 {
+		t.Fatalf("Up(0) = %v, %v; want only migration 3", applied, err)
+	}
+	if !db.Migrator().HasTable("gadgets") {
+		t.Fatal("gadgets table missing after Up")
+	}
+
+	// Re-running is a no-op
+	if applied, err = m.Up(0); err != nil || len(applied) != 0 {
+		t.Fatalf("second Up(0) = %v, %v; want nothing", applied, err)
+	}
+
+	rolled, err := m.Down(2)
+	if err != nil {
+		t.Fatalf("Down(2): %v", err)
+	}
+	if len(rolled) != 2 || rolled[0].Version != 3 || rolled[1].Version != 2 {
+		t.Fatalf("Down(2) rolled back %v, want 3 then 2", rolled)
+	}
+	if db.Migrator().HasTable("gadgets") || db.Migrator().HasColumn("widgets", "color") {
+		t.Fatal("Down did not undo migrations 2 and 3")
+	}
+	if v, _ := m.CurrentVersion(); v != 1 {
+		t.Fatalf("version after Down(2) = %d, want 1", v)
+	}
+
+	if _, err := m.Down(1); !errors.Is(err, ErrIrreversibleMigration) {
+		t.Fatalf("Down over an irreversible migration = %v, want ErrIrreversibleMigration", err)
+	}
+}
+
+func TestMigratorDryRunChangesNothing(t *testing.T) {
+	db := newTestMigrateDB(t)
+	m := newMigrator(db, testMigrations())
+	m.DryRun = true
+
+	planned, err := m.Up(0)
+	if err != nil {
+		t.Fatalf("dry-run Up: %v", err)
+	}
+	if len(planned) != 3 {
+		t.Fatalf("dry-run planned %d migrations, want 3", len(planned))
+	}
+	if db.Migrator().HasTable("widgets") || db.Migrator().HasTable(&SchemaMigration{}) {
+		t.Fatal("dry run executed migrations")
+	}
+}
+
+func TestMigratorFailedMigrationIsNotRecorded(t *testing.T) {
+	db := newTestMigrateDB(t)
+	migrations := testMigrations()
+	migrations[2].Up = func(db *gorm.DB) error {
+		if err := db.Exec(`CREATE TABLE gadgets (id INTEGER PRIMARY KEY)`).Error; err != nil {
+			return err
+		}
+		return errors.New("boom")
+	}
+	m := newMigrator(db, migrations)
+
+	applied, err := m.Up(0)
+	if err == nil {
+		t.Fatal("Up succeeded despite a failing migration")
+	}
+	if len(applied) != 2 {
+		t.Fatalf("applied %d migrations before the failure, want 2", len(applied))
+	}
+	if v, _ := m.CurrentVersion(); v != 2 {
+		t.Fatalf("version = %d, want 2", v)
+	}
+	if db.Migrator().HasTable("gadgets") {
+		t.Fatal("failed migration was not rolled back")
+	}
+}
+
+func TestMigratorRefusesNewerSchema(t *testing.T) {
+	db := newTestMigrateDB(t)
+	if _, err := newMigrator(db, testMigrations()).Up(0); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	older := newMigrator(db, testMigrations()[:2])
+	err := older.CheckVersion()
+	var tooNew *SchemaTooNewError
+	if !errors.As(err, &tooNew) {
+		t.Fatalf("CheckVersion = %v, want SchemaTooNewError", err)
+	}
+	if tooNew.DBVersion != 3 || tooNew.BinaryVersion != 2 {
+		t.Fatalf("SchemaTooNewError = %+v, want db 3 / binary 2", tooNew)
+	}
+	if _, err := older.Up(0); !errors.As(err, &tooNew) {
+		t.Fatalf("Up on a newer schema = %v, want SchemaTooNewError", err)
+	}
+}
+
+func TestSchemaMigrationsApplyToFreshDatabase(t *testing.T) {
+	db := newTestMigrateDB(t)
+	m := NewMigrator(db)
+	if _, err := m.Up(0); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if v, _ := m.CurrentVersion(); v != m.LatestVersion() {
+		t.Fatalf("version = %d, want %d", v, m.LatestVersion())
+	}
+	if !db.Migrator().HasColumn(&TraderPosition{}, "funding_fee") {
+		t.Fatal("trader_positions.funding_fee missing")
+	}
+
+	// Roll back through migration 5 (funding_fee)
+	steps := m.LatestVersion() - 4
+	rolled, err := m.Down(steps)
+	if err != nil || len(rolled) != steps {
+		t.Fatalf("Down(%d) = %v, %v", steps, rolled, err)
+	}
+	if db.Migrator().HasColumn(&TraderPosition{}, "funding_fee") {
+		t.Fatal("funding_fee still present after Down")
+	}
+	if db.Migrator().HasTable(&PositionExcursion{}) {
+		t.Fatal("position_excursions still present after Down")
+	}
+	if db.Migrator().HasColumn(&DecisionRecordDB{}, "market_regime") || db.Migrator().HasColumn(&Trader{}, "fallback_ai_model_ids") {
+		t.Fatal("decision_records/traders columns still present after Down")
+	}
+	if applied, err := m.Up(0); err != nil || len(applied) != steps {
+		t.Fatalf("Up after Down = %v, %v", applied, err)
+	}
+	if !db.Migrator().HasColumn(&TraderPosition{}, "funding_fee") {
+		t.Fatal("funding_fee missing after re-applying")
+	}
+	if !db.Migrator().HasTable(&PositionExcursion{}) {
+		t.Fatal("position_excursions missing after re-applying")
+	}
+	for _, c := range decisionAttributionColumns {
+		if !db.Migrator().HasColumn(&DecisionRecordDB{}, c.name) {
+			t.Fatalf("decision_records.%s missing after re-applying", c.name)
+		}
+	}
+	for _, c := range traderPinningColumns {
+		if !db.Migrator().HasColumn(&Trader{}, c.name) {
+			t.Fatalf("traders.%s missing after re-applying", c.name)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package store
+
+import (
+	"fmt"
+	"nofx/logger"
+
+	"gorm.io/gorm"
+)
+
+// schemaMigrations every schema change, in version order. Append new
+// migrations at the end; never renumber or edit one that has shipped.
+var schemaMigrations = []Migration{
+	{
+		Version:       1,
+		Name:          "baseline tables",
+		Up:            createBaselineTables,
+		NoTransaction: true,
+	},
+	{
+		Version: 2,
+		Name:    "exchanges.hyperliquid_builder_approved",
+		Up: func(db *gorm.DB) error {
+			if db.Migrator().HasColumn(&Exchange{}, "HyperliquidBuilderApproved") {
+				return nil
+			}
+			return db.Migrator().AddColumn(&Exchange{}, "HyperliquidBuilderApproved")
+		},
+		Down: func(db *gorm.DB) error {
+			if !db.Migrator().HasColumn(&Exchange{}, "HyperliquidBuilderApproved") {
+				return nil
+			}
+			return db.Migrator().DropColumn(&Exchange{}, "HyperliquidBuilderApproved")
+		},
+	},
+	{
+		Version: 3,
+		Name:    "exchanges multi-account ids",
+		Up: func(db *gorm.DB) error {
+			s := NewExchangeStore(db)
+			if err := s.migrateToMultiAccount(); err != nil {
+				return err
+			}
+			// Fix empty account_name for existing records
+			if err := db.Model(&Exchange{}).Where("account_name = '' OR account_name IS NULL").Update("account_name", "Default").Error; err != nil {
+				return err
+			}
+			return s.cleanupIncompleteExchangeConfigs()
+		},
+	},
+	{
+		Version: 4,
+		Name:    "equity snapshots from decision_account_snapshots",
+		Up: func(db *gorm.DB) error {
+			// The legacy table only ever existed in SQLite deployments
+			if db.Dialector.Name() == "postgres" {
+				return nil
+			}
+			migrated, err := NewEquityStore(db).MigrateFromDecision()
+			if err != nil {
+				return err
+			}
+			if migrated > 0 {
+				logger.Infof("✅ Migrated %d equity records to new table", migrated)
+			}
+			return nil
+		},
+	},
+	{
+		Version: 5,
+		Name:    "trader_positions.funding_fee",
+		Up: func(db *gorm.DB) error {
+			if db.Migrator().HasColumn(&TraderPosition{}, "funding_fee") {
+				return nil
+			}
+			return db.Exec(dialectSQL(db,
+				`ALTER TABLE trader_positions ADD COLUMN funding_fee REAL DEFAULT 0`,
+				`ALTER TABLE trader_positions ADD COLUMN IF NOT EXISTS funding_fee DOUBLE PRECISION DEFAULT 0`,
+			)).Error
+		},
+		Down: func(db *gorm.DB) error {
+			if !db.Migrator().HasColumn(&TraderPosition{}, "funding_fee") {
+				return nil
+			}
+			return db.Exec(`ALTER TABLE trader_positions DROP COLUMN funding_fee`).Error
+		},
+	},
+	{
+		Version: 6,
+		Name:    "position_excursions",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&PositionExcursion{})
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Migrator().DropTable(&PositionExcursion{})
+		},
+	},
+	{
+		Version: 7,
+		Name:    "position_notes",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&PositionNote{})
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Migrator().DropTable(&PositionNote{})
+		},
+	},
+	{
+		Version: 8,
+		Name:    "decision_records.account_state and positions",
+		Up: func(db *gorm.DB) error {
+			for _, column := range []string{"account_state", "positions"} {
+				if db.Migrator().HasColumn(&DecisionRecordDB{}, column) {
+					continue
+				}
+				if err := db.Exec(`ALTER TABLE decision_records ADD COLUMN ` + column + ` TEXT DEFAULT ''`).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(db *gorm.DB) error {
+			for _, column := range []string{"account_state", "positions"} {
+				if !db.Migrator().HasColumn(&DecisionRecordDB{}, column) {
+					continue
+				}
+				if err := db.Exec(`ALTER TABLE decision_records DROP COLUMN ` + column).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version: 9,
+		Name:    "trader_breaker_states",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&TraderBreakerState{})
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Migrator().DropTable(&TraderBreakerState{})
+		},
+	},
+	{
+		Version: 10,
+		Name:    "ai_charges.user_id",
+		Up: func(db *gorm.DB) error {
+			if !db.Migrator().HasColumn(&AICharge{}, "user_id") {
+				if err := db.Exec(`ALTER TABLE ai_charges ADD COLUMN user_id TEXT NOT NULL DEFAULT ''`).Error; err != nil {
+					return err
+				}
+			}
+			if err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_ai_charges_user ON ai_charges (user_id)`).Error; err != nil {
+				return err
+			}
+			// Credit existing charges to their trader's owner; charges of
+			// already deleted traders stay unattributed
+			return db.Exec(`UPDATE ai_charges SET user_id = (SELECT traders.user_id FROM traders WHERE traders.id = ai_charges.trader_id)
+				WHERE user_id = '' AND EXISTS (SELECT 1 FROM traders WHERE traders.id = ai_charges.trader_id)`).Error
+		},
+		Down: func(db *gorm.DB) error {
+			if !db.Migrator().HasColumn(&AICharge{}, "user_id") {
+				return nil
+			}
+			if err := db.Exec(`DROP INDEX IF EXISTS idx_ai_charges_user`).Error; err != nil {
+				return err
+			}
+			return db.Exec(`ALTER TABLE ai_charges DROP COLUMN user_id`).Error
+		},
+	},
+	{
+		Version: 11,
+		Name:    "decision_records strategy, model and regime columns",
+		Up: func(db *gorm.DB) error {
+			return addColumns(db, &DecisionRecordDB{}, "decision_records", decisionAttributionColumns)
+		},
+		Down: func(db *gorm.DB) error {
+			return dropColumns(db, &DecisionRecordDB{}, "decision_records", decisionAttributionColumns)
+		},
+	},
+	{
+		Version: 12,
+		Name:    "traders.strategy_version and fallback_ai_model_ids",
+		Up: func(db *gorm.DB) error {
+			return addColumns(db, &Trader{}, "traders", traderPinningColumns)
+		},
+		Down: func(db *gorm.DB) error {
+			return dropColumns(db, &Trader{}, "traders", traderPinningColumns)
+		},
+	},
+}
+
+// columnDef a column added by a migration: name and type with default
+type columnDef struct{ name, def string }
+
+var decisionAttributionColumns = []columnDef{
+	{"strategy_id", "TEXT DEFAULT ''"},
+	{"strategy_version", "INTEGER DEFAULT 0"},
+	{"ai_provider", "TEXT DEFAULT ''"},
+	{"ai_model", "TEXT DEFAULT ''"},
+	{"market_regime", "TEXT DEFAULT ''"},
+	{"prompt_variant", "TEXT DEFAULT ''"},
+}
+
+var traderPinningColumns = []columnDef{
+	{"strategy_version", "INTEGER DEFAULT 0"},
+	{"fallback_ai_model_ids", "TEXT DEFAULT ''"},
+}
+
+// addColumns adds the columns the table does not have yet
+func addColumns(db *gorm.DB, model interface{}, table string, columns []columnDef) error {
+	for _, c := range columns {
+		if db.Migrator().HasColumn(model, c.name) {
+			continue
+		}
+		if err := db.Exec(dialectSQL(db,
+			`ALTER TABLE `+table+` ADD COLUMN `+c.name+` `+c.def,
+			`ALTER TABLE `+table+` ADD COLUMN IF NOT EXISTS `+c.name+` `+c.def,
+		)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropColumns drops the columns the table still has
+func dropColumns(db *gorm.DB, model interface{}, table string, columns []columnDef) error {
+	for _, c := range columns {
+		if !db.Migrator().HasColumn(model, c.name) {
+			continue
+		}
+		if err := db.Exec(dialectSQL(db,
+			`ALTER TABLE `+table+` DROP COLUMN `+c.name,
+			`ALTER TABLE `+table+` DROP COLUMN IF EXISTS `+c.name,
+		)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createBaselineTables creates every table through the sub-store
+// initializers. Tables of existing databases are left as they are, so this
+// also serves as the first migration of databases created before versioning.
+func createBaselineTables(db *gorm.DB) error {
+	if err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS system_config (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL
+		)
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create system_config table: %w", err)
+	}
+
+	steps := []struct {
+		name string
+		init func() error
+	}{
+		{"user", NewUserStore(db).initTables},
+		{"AI model", NewAIModelStore(db).initTables},
+		{"exchange", NewExchangeStore(db).initTables},
+		{"trader", NewTraderStore(db).initTables},
+		{"decision log", NewDecisionStore(db).initTables},
+		{"position", NewPositionStore(db).InitTables},
+		{"strategy", NewStrategyStore(db).initTables},
+		{"equity", NewEquityStore(db).initTables},
+		{"order", NewOrderStore(db).InitTables},
+		{"grid", NewGridStore(db).InitTables},
+		{"telegram config", NewTelegramConfigStore(db).(*telegramConfigStore).initTables},
+		{"AI charge", NewAIChargeStore(db).initTables},
+		{"workspace", NewWorkspaceStore(db).initTables},
+		{"audit", NewAuditStore(db).initTables},
+		{"notification", NewNotificationStore(db).initTables},
+		{"AI budget", NewAIBudgetStore(db).initTables},
+		{"funding", NewFundingStore(db).initTables},
+	}
+	for _, step := range steps {
+		if err := step.init(); err != nil {
+			return fmt.Errorf("failed to initialize %s tables: %w", step.name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package store
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Notification kinds
+const (
+	NotificationStrategyUpdate  = "strategy_update"  // a subscribed strategy published a new version
+	NotificationStrategyUpdated = "strategy_updated" // a fork was auto-updated to a new version
+	NotificationRiskBreaker     = "risk_breaker"     // a trader's account circuit breaker tripped
+)
+
+// Notification an in-app message for a user. Unread notifications are listed
+// in the web UI; the Telegram bot forwards undelivered ones to the bound chat.
+type Notification struct {
+	ID          int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID      string     `gorm:"column:user_id;not null;index:idx_notifications_user_time" json:"user_id"`
+	Kind        string     `gorm:"column:kind;not null" json:"kind"`
+	Title       string     `gorm:"column:title;not null" json:"title"`
+	Body        string     `gorm:"column:body;type:text;default:''" json:"body"`
+	Ref         string     `gorm:"column:ref;default:''" json:"ref,omitempty"` // related resource id
+	ReadAt      *time.Time `gorm:"column:read_at" json:"read_at,omitempty"`
+	DeliveredAt *time.Time `gorm:"column:delivered_at;index" json:"-"` // pushed to Telegram
+	CreatedAt   time.Time  `gorm:"column:created_at;autoCreateTime;index:idx_notifications_user_time,sort:desc" json:"created_at"`
+}
+
+func (Notification) TableName() string { return "notifications" }
+
+// NotificationStore notification storage
+type NotificationStore struct {
+	db *gorm.DB
+}
+
+// NewNotificationStore creates a new NotificationStore
+func NewNotificationStore(db *gorm.DB) *NotificationStore {
+	return &NotificationStore{db: db}
+}
+
+func (s *NotificationStore) initTables() error {
+	return s.db.AutoMigrate(&Notification{})
+}
+
+// Create adds a notification
+func (s *NotificationStore) Create(n *Notification) error {
+	return s.db.Create(n).Error
+}
+
+// List returns a user's notifications, newest first
+func (s *NotificationStore) List(userID string, unreadOnly bool, limit int) ([]*Notification, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+	query := s.db.Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	var list []*Notification
+	err := query.Order("created_at DESC, id DESC").Limit(limit).Find(&list).Error
+	return list, err
+}
+
+// CountUnread counts a user's unread notifications
+func (s *NotificationStore) CountUnread(userID string) (int64, error) {
+	var count int64
+	err := s.db.Model(&Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+// MarkRead marks one notification (id > 0) or all of a user's notifications (id == 0) as read
+func (s *NotificationStore) MarkRead(userID string, id int64) error {
+	query := s.db.Model(&Notification{}).Where("user_id = ? AND read_at IS NULL", userID)
+	if id > 0 {
+		query = query.Where("id = ?", id)
+	}
+	return query.Update("read_at", time.Now().UTC()).Error
+}
+
+// ListUndelivered returns notifications created after since and not yet
+// pushed to Telegram, oldest first
+func (s *NotificationStore) ListUndelivered(userID string, since time.Time, limit int) ([]*Notification, error) {
+	var list []*Notification
+	err := s.db.Where("user_id = ? AND delivered_at IS NULL AND created_at >= ?", userID, since).
+		Order("created_at ASC, id ASC").Limit(limit).Find(&list).Error
+	return list, err
+}
+
+// MarkDelivered records that a notification was pushed to Telegram
+func (s *NotificationStore) MarkDelivered(id int64) error {
+	return s.db.Model(&Notification{}).Where("id = ?", id).Update("delivered_at", time.Now().UTC()).Error
+}
@@ -1,7 +1,9 @@
 package store
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"strconv"
 	"time"
 
@@ -426,3 +428,78 @@ func (s *OrderStore) GetRecentFillSymbolsByExchange(exchangeID string, sinceMs i
 	}
 	return symbols, nil
 }
+
+// closedOrderStatuses order statuses that can no longer change
+var closedOrderStatuses = []string{"FILLED", "CANCELED", "CANCELLED", "EXPIRED", "REJECTED"}
+
+// orderArchiveBatch orders archived per transaction
+const orderArchiveBatch = 500
+
+// OrderArchiveRecord one line of an order archive: a closed order with its fills
+type OrderArchiveRecord struct {
+	Order *TraderOrder  `json:"order"`
+	Fills []*TraderFill `json:"fills,omitempty"`
+}
+
+// ArchiveSink the destination of an order archive. Sync makes everything
+// written so far durable.
+type ArchiveSink interface {
+	io.Writer
+	Sync() error
+}
+
+// ArchiveClosedOrders writes closed orders created before the cutoff (Unix ms),
+// together with their fills, to w as JSON lines and deletes them. Each batch
+// is deleted only after it was written and synced. Returns the archived order
+// and fill counts.
+func (s *OrderStore) ArchiveClosedOrders(beforeMs int64, w ArchiveSink) (orders, fills int64, err error) {
+	enc := json.NewEncoder(w)
+	for {
+		var batchFills int64
+		var batch []*TraderOrder
+		err = s.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Where("created_at < ? AND status IN ?", beforeMs, closedOrderStatuses).
+				Order("id ASC").
+				Limit(orderArchiveBatch).
+				Find(&batch).Error; err != nil {
+				return fmt.Errorf("failed to query closed orders: %w", err)
+			}
+			if len(batch) == 0 {
+				return nil
+			}
+			ids := make([]int64, len(batch))
+			for i, o := range batch {
+				ids[i] = o.ID
+			}
+			var orderFills []*TraderFill
+			if err := tx.Where("order_id IN ?", ids).Order("id ASC").Find(&orderFills).Error; err != nil {
+				return fmt.Errorf("failed to query fills: %w", err)
+			}
+			byOrder := make(map[int64][]*TraderFill)
+			for _, f := range orderFills {
+				byOrder[f.OrderID] = append(byOrder[f.OrderID], f)
+			}
+			for _, o := range batch {
+				if err := enc.Encode(OrderArchiveRecord{Order: o, Fills: byOrder[o.ID]}); err != nil {
+					return fmt.Errorf("failed to write archive: %w", err)
+				}
+			}
+			if err := w.Sync(); err != nil {
+				return fmt.Errorf("failed to sync archive: %w", err)
+			}
+			if err := tx.Where("order_id IN ?", ids).Delete(&TraderFill{}).Error; err != nil {
+				return fmt.Errorf("failed to delete archived fills: %w", err)
+			}
+			if err := tx.Where("id IN ?", ids).Delete(&TraderOrder{}).Error; err != nil {
+				return fmt.Errorf("failed to delete archived orders: %w", err)
+			}
+			batchFills = int64(len(orderFills))
+			return nil
+		})
+		if err != nil || len(batch) == 0 {
+			return orders, fills, err
+		}
+		orders += int64(len(batch))
+		fills += batchFills
+	}
+}
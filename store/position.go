@@ -112,6 +112,7 @@ type TraderPosition struct {
 	ExitTime           int64   `gorm:"column:exit_time;index:idx_positions_exit" json:"exit_time"` // Unix milliseconds UTC, 0 means not set
 	RealizedPnL        float64 `gorm:"column:realized_pnl;default:0" json:"realized_pnl"`
 	Fee                float64 `gorm:"column:fee;default:0" json:"fee"`
+	FundingFee         float64 `gorm:"column:funding_fee;default:0" json:"funding_fee"` // Accumulated funding, positive = received
 	Leverage           int     `gorm:"column:leverage;default:1" json:"leverage"`
 	Status             string  `gorm:"column:status;default:OPEN;index:idx_positions_status" json:"status"`
 	CloseReason        string  `gorm:"column:close_reason;default:''" json:"close_reason"`
@@ -125,6 +126,11 @@ func (TraderPosition) TableName() string {
 	return "trader_positions"
 }
 
+// NetPnL realized P&L including the funding settled while the position was held
+func (p *TraderPosition) NetPnL() float64 {
+	return p.RealizedPnL + p.FundingFee
+}
+
 // PositionStore position storage
 type PositionStore struct {
 	db *gorm.DB
@@ -407,7 +413,13 @@ func (s *PositionStore) GetClosedPositions(traderID string, limit int) ([]*Trade
 }
 
 func (s *PositionStore) closedPositionsByTraderFilters(traderIDs []string, traderIDPatterns []string) *gorm.DB {
-	query := s.db.Where("status = ?", "CLOSED")
+	return s.positionsByTraderFilters(traderIDs, traderIDPatterns).Where("status = ?", "CLOSED")
+}
+
+// positionsByTraderFilters positions of any status of explicit trader IDs and
+// legacy trader ID patterns (see GetClosedPositionsByTraderFilters)
+func (s *PositionStore) positionsByTraderFilters(traderIDs []string, traderIDPatterns []string) *gorm.DB {
+	query := s.db.Model(&TraderPosition{})
 
 	conditions := make([]string, 0, len(traderIDs)+len(traderIDPatterns))
 	args := make([]interface{}, 0, len(traderIDs)+len(traderIDPatterns))
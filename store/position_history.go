@@ -103,8 +103,8 @@ func (s *PositionStore) GetHistorySummary(traderID string) (*HistorySummary, err
 	s.db.Where("trader_id = ? AND status = ?", traderID, "CLOSED").
 		Order("exit_time DESC").Limit(20).Find(&recent)
 	for _, pos := range recent {
-		summary.RecentPnL += pos.RealizedPnL
-		if pos.RealizedPnL > 0 {
+		summary.RecentPnL += pos.NetPnL()
+		if pos.NetPnL() > 0 {
 			summary.RecentWinRate++
 		}
 	}
@@ -133,7 +133,7 @@ func (s *PositionStore) calculateStreaks(traderID string, summary *HistorySummar
 	isFirst := true
 
 	for _, pos := range positions {
-		isWin := pos.RealizedPnL > 0
+		isWin := pos.NetPnL() > 0
 
 		if isFirst {
 			if isWin {
@@ -173,6 +173,28 @@ func (s *PositionStore) calculateStreaks(traderID string, summary *HistorySummar
 	summary.MaxLoseStreak = maxLose
 }
 
+// GetConsecutiveLosses counts the losing positions closed in a row, newest
+// first, among those closed at or after since
+func (s *PositionStore) GetConsecutiveLosses(traderID string, since time.Time) (int, error) {
+	var pnls []float64
+	err := s.db.Model(&TraderPosition{}).
+		Where("trader_id = ? AND status = ? AND exit_time >= ?", traderID, "CLOSED", since.UnixMilli()).
+		Order("exit_time DESC").
+		Limit(500).
+		Pluck("realized_pnl + funding_fee", &pnls).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to query closed positions: %w", err)
+	}
+	losses := 0
+	for _, pnl := range pnls {
+		if pnl >= 0 {
+			break
+		}
+		losses++
+	}
+	return losses, nil
+}
+
 // ClosedPnLRecord represents a closed position record from exchange
 // All time fields use int64 millisecond timestamps (UTC)
 type ClosedPnLRecord struct {
@@ -16,6 +16,7 @@ type TraderStats struct {
 	SharpeRatio    float64 `json:"sharpe_ratio"`
 	TotalPnL       float64 `json:"total_pnl"`
 	TotalFee       float64 `json:"total_fee"`
+	TotalFunding   float64 `json:"total_funding"` // net funding, positive = received; included in TotalPnL
 	AvgWin         float64 `json:"avg_win"`
 	AvgLoss        float64 `json:"avg_loss"`
 	MaxDrawdownPct float64 `json:"max_drawdown_pct"`
@@ -26,15 +27,16 @@ func (s *PositionStore) GetPositionStats(traderID string) (map[string]interface{
 	stats := make(map[string]interface{})
 
 	type result struct {
-		Total    int
-		Wins     int
-		TotalPnL float64
-		TotalFee float64
+		Total        int
+		Wins         int
+		TotalPnL     float64
+		TotalFee     float64
+		TotalFunding float64
 	}
 	var r result
 
 	err := s.db.Model(&TraderPosition{}).
-		Select("COUNT(*) as total, SUM(CASE WHEN realized_pnl > 0 THEN 1 ELSE 0 END) as wins, COALESCE(SUM(realized_pnl), 0) as total_pnl, COALESCE(SUM(fee), 0) as total_fee").
+		Select("COUNT(*) as total, SUM(CASE WHEN realized_pnl + funding_fee > 0 THEN 1 ELSE 0 END) as wins, COALESCE(SUM(realized_pnl + funding_fee), 0) as total_pnl, COALESCE(SUM(fee), 0) as total_fee, COALESCE(SUM(funding_fee), 0) as total_funding").
 		Where("trader_id = ? AND status = ?", traderID, "CLOSED").
 		Scan(&r).Error
 	if err != nil {
@@ -45,6 +47,7 @@ func (s *PositionStore) GetPositionStats(traderID string) (map[string]interface{
 	stats["win_trades"] = r.Wins
 	stats["total_pnl"] = r.TotalPnL
 	stats["total_fee"] = r.TotalFee
+	stats["total_funding"] = r.TotalFunding
 	if r.Total > 0 {
 		stats["win_rate"] = float64(r.Wins) / float64(r.Total) * 100
 	} else {
@@ -64,8 +67,6 @@ func (s *PositionStore) GetFullStats(traderID string, startingEquity float64) (*
 // trader IDs plus optional legacy trader ID patterns. startingEquity is the
 // real account baseline for the drawdown calculation; pass 0 when unknown.
 func (s *PositionStore) GetFullStatsByTraderFilters(traderIDs []string, traderIDPatterns []string, startingEquity float64) (*TraderStats, error) {
-	stats := &TraderStats{}
-
 	var positions []TraderPosition
 	err := s.closedPositionsByTraderFilters(traderIDs, traderIDPatterns).
 		Order("exit_time ASC").
@@ -73,22 +74,30 @@ func (s *PositionStore) GetFullStatsByTraderFilters(traderIDs []string, traderID
 	if err != nil {
 		return nil, fmt.Errorf("failed to query position statistics: %w", err)
 	}
+	return computeTraderStats(positions, startingEquity), nil
+}
 
+// computeTraderStats computes the trade statistics of closed positions
+// ordered by exit time
+func computeTraderStats(positions []TraderPosition, startingEquity float64) *TraderStats {
+	stats := &TraderStats{}
 	var pnls []float64
 	var totalWin, totalLoss float64
 
 	for _, pos := range positions {
+		pnl := pos.NetPnL()
 		stats.TotalTrades++
-		stats.TotalPnL += pos.RealizedPnL
+		stats.TotalPnL += pnl
 		stats.TotalFee += pos.Fee
-		pnls = append(pnls, pos.RealizedPnL)
+		stats.TotalFunding += pos.FundingFee
+		pnls = append(pnls, pnl)
 
-		if pos.RealizedPnL > 0 {
+		if pnl > 0 {
 			stats.WinTrades++
-			totalWin += pos.RealizedPnL
-		} else if pos.RealizedPnL < 0 {
+			totalWin += pnl
+		} else if pnl < 0 {
 			stats.LossTrades++
-			totalLoss += -pos.RealizedPnL
+			totalLoss += -pnl
 		}
 	}
 
@@ -111,7 +120,7 @@ func (s *PositionStore) GetFullStatsByTraderFilters(traderIDs []string, traderID
 		stats.MaxDrawdownPct = calculateMaxDrawdownFromPnls(pnls, startingEquity)
 	}
 
-	return stats, nil
+	return stats
 }
 
 // RecentTrade recent trade record
@@ -120,7 +129,8 @@ type RecentTrade struct {
 	Side         string  `json:"side"`
 	EntryPrice   float64 `json:"entry_price"`
 	ExitPrice    float64 `json:"exit_price"`
-	RealizedPnL  float64 `json:"realized_pnl"`
+	RealizedPnL  float64 `json:"realized_pnl"` // includes funding
+	FundingFee   float64 `json:"funding_fee"`
 	PnLPct       float64 `json:"pnl_pct"`
 	EntryTime    int64   `json:"entry_time"`
 	ExitTime     int64   `json:"exit_time"`
@@ -145,7 +155,8 @@ func (s *PositionStore) GetRecentTrades(traderID string, limit int) ([]RecentTra
 			Side:        strings.ToLower(pos.Side),
 			EntryPrice:  pos.EntryPrice,
 			ExitPrice:   pos.ExitPrice,
-			RealizedPnL: pos.RealizedPnL,
+			RealizedPnL: pos.NetPnL(),
+			FundingFee:  pos.FundingFee,
 			EntryTime:   pos.EntryTime / 1000, // Convert ms to seconds for API compatibility
 		}
 
@@ -265,8 +276,8 @@ func (s *PositionStore) GetSymbolStatsByTraderFilters(traderIDs []string, trader
 		}
 		s := symbolMap[pos.Symbol]
 		s.TotalTrades++
-		s.TotalPnL += pos.RealizedPnL
-		if pos.RealizedPnL > 0 {
+		s.TotalPnL += pos.NetPnL()
+		if pos.NetPnL() > 0 {
 			s.WinTrades++
 		}
 
@@ -355,8 +366,8 @@ func (s *PositionStore) GetHoldingTimeStats(traderID string) ([]HoldingTimeStats
 
 		r := rangeStats[rangeKey]
 		r.count++
-		r.totalPnL += pos.RealizedPnL
-		if pos.RealizedPnL > 0 {
+		r.totalPnL += pos.NetPnL()
+		if pos.NetPnL() > 0 {
 			r.wins++
 		}
 	}
@@ -407,8 +418,8 @@ func (s *PositionStore) GetDirectionStatsByTraderFilters(traderIDs []string, tra
 		}
 		s := sideStats[pos.Side]
 		s.TradeCount++
-		s.TotalPnL += pos.RealizedPnL
-		if pos.RealizedPnL > 0 {
+		s.TotalPnL += pos.NetPnL()
+		if pos.NetPnL() > 0 {
 			s.WinRate++
 		}
 	}
@@ -0,0 +1,113 @@
+package store
+
+import "strings"
+
+// Position sizing modes
+const (
+	PositionSizingAI              = "ai"               // the model's position_size_usd, only capped by the limits
+	PositionSizingFixedFractional = "fixed_fractional" // loss at the stop loss = RiskPerTradePct of equity
+	PositionSizingATR             = "atr"              // loss over ATRMultiple × ATR = RiskPerTradePct of equity
+	PositionSizingKelly           = "kelly"            // risk = fractional Kelly from the historical win rate and payoff
+)
+
+// Roles of the model's position_size_usd under a code sizing mode
+const (
+	AISizeUpperBound = "upper_bound" // final size = min(computed size, AI size)
+	AISizeHint       = "hint"        // computed size is used; the AI size only when no size can be computed
+)
+
+// Position sizing defaults, applied when a field is unset
+const (
+	DefaultRiskPerTradePct    = 1.0
+	DefaultATRMultiple        = 2.0
+	DefaultKellyFraction      = 0.5 // half Kelly
+	DefaultKellyMinTrades     = 20
+	DefaultMaxRiskPerTradePct = 5.0
+)
+
+// PositionSizingConfig code-enforced position sizing. The computed size is
+// still capped by the position value ratio of the symbol's asset tier and by
+// the available margin.
+type PositionSizingConfig struct {
+	// Sizing mode: ai (default), fixed_fractional, atr, kelly
+	Mode string `json:"mode,omitempty"`
+	// Role of the AI's position_size_usd: upper_bound (default) or hint
+	AISizeRole string `json:"ai_size_role,omitempty"`
+	// Equity % lost when the stop (fixed_fractional) or the ATR move (atr) is hit; Kelly before enough trades
+	RiskPerTradePct float64 `json:"risk_per_trade_pct,omitempty"`
+	// atr: adverse move the risk budget covers, in 4h ATR14
+	ATRMultiple float64 `json:"atr_multiple,omitempty"`
+	// kelly: fraction of the full Kelly bet
+	KellyFraction float64 `json:"kelly_fraction,omitempty"`
+	// kelly: closed trades needed before the win rate is trusted; fixed fractional until then
+	KellyMinTrades int `json:"kelly_min_trades,omitempty"`
+	// kelly: cap on the equity % risked per trade
+	MaxRiskPerTradePct float64 `json:"max_risk_per_trade_pct,omitempty"`
+}
+
+// Enabled reports whether position size is computed by code
+func (c PositionSizingConfig) Enabled() bool {
+	return c.Mode != "" && c.Mode != PositionSizingAI
+}
+
+// WithDefaults returns the config with unset fields defaulted
+func (c PositionSizingConfig) WithDefaults() PositionSizingConfig {
+	if c.AISizeRole == "" {
+		c.AISizeRole = AISizeUpperBound
+	}
+	if c.RiskPerTradePct <= 0 {
+		c.RiskPerTradePct = DefaultRiskPerTradePct
+	}
+	if c.ATRMultiple <= 0 {
+		c.ATRMultiple = DefaultATRMultiple
+	}
+	if c.KellyFraction <= 0 {
+		c.KellyFraction = DefaultKellyFraction
+	}
+	if c.KellyMinTrades <= 0 {
+		c.KellyMinTrades = DefaultKellyMinTrades
+	}
+	if c.MaxRiskPerTradePct <= 0 {
+		c.MaxRiskPerTradePct = DefaultMaxRiskPerTradePct
+	}
+	return c
+}
+
+// normalizePositionSizing drops unknown modes and roles and clamps the
+// parameters to sane ranges
+func normalizePositionSizing(c PositionSizingConfig) PositionSizingConfig {
+	c.Mode = strings.ToLower(strings.TrimSpace(c.Mode))
+	switch c.Mode {
+	case PositionSizingFixedFractional, PositionSizingATR, PositionSizingKelly:
+	default:
+		c.Mode = ""
+	}
+	c.AISizeRole = strings.ToLower(strings.TrimSpace(c.AISizeRole))
+	if c.AISizeRole != AISizeUpperBound && c.AISizeRole != AISizeHint {
+		c.AISizeRole = ""
+	}
+	c.RiskPerTradePct = clampNonNegative(c.RiskPerTradePct, 10)
+	c.MaxRiskPerTradePct = clampNonNegative(c.MaxRiskPerTradePct, 20)
+	c.KellyFraction = clampNonNegative(c.KellyFraction, 1)
+	if c.ATRMultiple > 0 && c.ATRMultiple < 0.5 {
+		c.ATRMultiple = 0.5
+	}
+	c.ATRMultiple = clampNonNegative(c.ATRMultiple, 10)
+	if c.KellyMinTrades < 0 {
+		c.KellyMinTrades = 0
+	}
+	if c.KellyMinTrades > 500 {
+		c.KellyMinTrades = 500
+	}
+	return c
+}
+
+func clampNonNegative(v, max float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
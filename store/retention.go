@@ -0,0 +1,294 @@
+package store
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// System config keys of the retention service
+const (
+	SystemConfigRetentionPolicy     = "retention_policy"
+	SystemConfigRetentionLastReport = "retention_last_report"
+)
+
+// RetentionPolicy per-table data retention, stored as JSON in system_config.
+// A zero day count disables that step.
+type RetentionPolicy struct {
+	Enabled       bool `json:"enabled"`
+	IntervalHours int  `json:"interval_hours,omitempty"` // time between runs
+	// decision_records: clear prompts and raw responses, keep the decisions
+	StripPromptsAfterDays int `json:"strip_prompts_after_days,omitempty"`
+	// decision_records: delete whole records
+	DeleteDecisionsAfterDays int `json:"delete_decisions_after_days,omitempty"`
+	// trader_equity_snapshots: keep the last snapshot per hour / per UTC day
+	EquityHourlyAfterDays int `json:"equity_hourly_after_days,omitempty"`
+	EquityDailyAfterDays  int `json:"equity_daily_after_days,omitempty"`
+	// trader_equity_snapshots: delete snapshots
+	DeleteEquityAfterDays int `json:"delete_equity_after_days,omitempty"`
+	// trader_orders / trader_fills: move closed orders and their fills to gzipped JSON lines in ArchiveDir
+	ArchiveOrdersAfterDays int    `json:"archive_orders_after_days,omitempty"`
+	ArchiveDir             string `json:"archive_dir,omitempty"`
+	// Compact the database after a run so freed pages are returned to the OS
+	Vacuum bool `json:"vacuum"`
+}
+
+// DefaultRetentionPolicy the policy used until one is saved. Scheduled
+// retention is off by default; `nofx retention set --enabled` turns it on.
+// Nothing is deleted outright: prompts are stripped, equity is thinned and
+// orders are archived to files.
+func DefaultRetentionPolicy() RetentionPolicy {
+	return RetentionPolicy{
+		Enabled:                false,
+		IntervalHours:          24,
+		StripPromptsAfterDays:  30,
+		EquityHourlyAfterDays:  30,
+		EquityDailyAfterDays:   365,
+		ArchiveOrdersAfterDays: 180,
+		ArchiveDir:             "data/archive",
+		Vacuum:                 true,
+	}
+}
+
+// Normalize clamps negative values and fills the interval and archive dir
+func (p RetentionPolicy) Normalize() RetentionPolicy {
+	for _, days := range []*int{&p.StripPromptsAfterDays, &p.DeleteDecisionsAfterDays, &p.EquityHourlyAfterDays,
+		&p.EquityDailyAfterDays, &p.DeleteEquityAfterDays, &p.ArchiveOrdersAfterDays} {
+		if *days < 0 {
+			*days = 0
+		}
+	}
+	if p.IntervalHours <= 0 {
+		p.IntervalHours = 24
+	}
+	if p.ArchiveDir == "" {
+		p.ArchiveDir = DefaultRetentionPolicy().ArchiveDir
+	}
+	return p
+}
+
+// RetentionReport what a retention run changed
+type RetentionReport struct {
+	StartedAt         time.Time `json:"started_at"`
+	DurationMs        int64     `json:"duration_ms"`
+	PromptsStripped   int64     `json:"prompts_stripped"`
+	DecisionsDeleted  int64     `json:"decisions_deleted"`
+	EquityDownsampled int64     `json:"equity_downsampled"`
+	EquityDeleted     int64     `json:"equity_deleted"`
+	OrdersArchived    int64     `json:"orders_archived"`
+	FillsArchived     int64     `json:"fills_archived"`
+	ArchiveFile       string    `json:"archive_file,omitempty"`
+	BytesBefore       int64     `json:"bytes_before"`
+	BytesAfter        int64     `json:"bytes_after"`
+	BytesReclaimed    int64     `json:"bytes_reclaimed"`
+	Errors            []string  `json:"errors,omitempty"`
+}
+
+// GetRetentionPolicy returns the saved retention policy, or the default
+func (s *Store) GetRetentionPolicy() (RetentionPolicy, error) {
+	raw, err := s.GetSystemConfig(SystemConfigRetentionPolicy)
+	if err != nil {
+		return RetentionPolicy{}, fmt.Errorf("failed to load retention policy: %w", err)
+	}
+	if raw == "" {
+		return DefaultRetentionPolicy(), nil
+	}
+	var p RetentionPolicy
+	if err := json.Unmarshal([]byte(raw), &p); err != nil {
+		return RetentionPolicy{}, fmt.Errorf("invalid retention policy: %w", err)
+	}
+	return p.Normalize(), nil
+}
+
+// SetRetentionPolicy saves the retention policy
+func (s *Store) SetRetentionPolicy(p RetentionPolicy) error {
+	data, err := json.Marshal(p.Normalize())
+	if err != nil {
+		return err
+	}
+	return s.SetSystemConfig(SystemConfigRetentionPolicy, string(data))
+}
+
+// GetLastRetentionReport returns the report of the last run, nil when none ran
+func (s *Store) GetLastRetentionReport() (*RetentionReport, error) {
+	raw, err := s.GetSystemConfig(SystemConfigRetentionLastReport)
+	if err != nil || raw == "" {
+		return nil, err
+	}
+	var r RetentionReport
+	if err := json.Unmarshal([]byte(raw), &r); err != nil {
+		return nil, fmt.Errorf("invalid retention report: %w", err)
+	}
+	return &r, nil
+}
+
+// ApplyRetention runs every step of the policy and records the report.
+// A failing step is recorded in the report and does not stop the others.
+func (s *Store) ApplyRetention(p RetentionPolicy) *RetentionReport {
+	p = p.Normalize()
+	now := time.Now().UTC()
+	report := &RetentionReport{StartedAt: now}
+	fail := func(step string, err error) {
+		report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", step, err))
+	}
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+
+	if size, err := s.DatabaseSize(); err == nil {
+		report.BytesBefore = size
+	}
+
+	if p.StripPromptsAfterDays > 0 {
+		n, err := s.Decision().StripPrompts(daysAgo(p.StripPromptsAfterDays))
+		if err != nil {
+			fail("strip prompts", err)
+		}
+		report.PromptsStripped = n
+	}
+	if p.DeleteDecisionsAfterDays > 0 {
+		for _, traderID := range distinctTraderIDs(s.gdb, &DecisionRecordDB{}) {
+			n, err := s.Decision().CleanOldRecords(traderID, p.DeleteDecisionsAfterDays)
+			if err != nil {
+				fail("delete decisions", err)
+			}
+			report.DecisionsDeleted += n
+		}
+	}
+
+	for _, traderID := range distinctTraderIDs(s.gdb, &EquitySnapshot{}) {
+		if p.DeleteEquityAfterDays > 0 {
+			n, err := s.Equity().CleanOldRecords(traderID, p.DeleteEquityAfterDays)
+			if err != nil {
+				fail("delete equity", err)
+			}
+			report.EquityDeleted += n
+		}
+		// Daily first, so the hourly pass scans fewer rows
+		if p.EquityDailyAfterDays > 0 {
+			n, err := s.Equity().Downsample(traderID, daysAgo(p.EquityDailyAfterDays), 24*time.Hour)
+			if err != nil {
+				fail("downsample equity", err)
+			}
+			report.EquityDownsampled += n
+		}
+		if p.EquityHourlyAfterDays > 0 {
+			n, err := s.Equity().Downsample(traderID, daysAgo(p.EquityHourlyAfterDays), time.Hour)
+			if err != nil {
+				fail("downsample equity", err)
+			}
+			report.EquityDownsampled += n
+		}
+	}
+
+	if p.ArchiveOrdersAfterDays > 0 {
+		if err := s.archiveOrders(p, daysAgo(p.ArchiveOrdersAfterDays), report); err != nil {
+			fail("archive orders", err)
+		}
+	}
+
+	if p.Vacuum {
+		if err := s.Vacuum(); err != nil {
+			fail("vacuum", err)
+		}
+	}
+	if size, err := s.DatabaseSize(); err == nil {
+		report.BytesAfter = size
+		if report.BytesBefore > size {
+			report.BytesReclaimed = report.BytesBefore - size
+		}
+	}
+	report.DurationMs = time.Since(now).Milliseconds()
+
+	if data, err := json.Marshal(report); err == nil {
+		if err := s.SetSystemConfig(SystemConfigRetentionLastReport, string(data)); err != nil {
+			fail("save report", err)
+		}
+	}
+	return report
+}
+
+// archiveOrders writes the archived orders to a new gzip file in the archive
+// dir. The file is removed again when nothing was archived.
+func (s *Store) archiveOrders(p RetentionPolicy, before time.Time, report *RetentionReport) error {
+	if err := os.MkdirAll(p.ArchiveDir, 0o700); err != nil {
+		return fmt.Errorf("create archive dir: %w", err)
+	}
+	path := filepath.Join(p.ArchiveDir, fmt.Sprintf("orders-%s.jsonl.gz", report.StartedAt.Format("20060102-150405")))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("create archive file: %w", err)
+	}
+	gz := gzip.NewWriter(f)
+	orders, fills, archiveErr := s.Order().ArchiveClosedOrders(before.UnixMilli(), &gzipArchive{gz: gz, f: f})
+	if err := gz.Close(); err != nil && archiveErr == nil {
+		archiveErr = err
+	}
+	if err := f.Sync(); err != nil && archiveErr == nil {
+		archiveErr = err
+	}
+	if err := f.Close(); err != nil && archiveErr == nil {
+		archiveErr = err
+	}
+	report.OrdersArchived = orders
+	report.FillsArchived = fills
+	if orders == 0 && archiveErr == nil {
+		os.Remove(path)
+		return nil
+	}
+	report.ArchiveFile = path
+	return archiveErr
+}
+
+// gzipArchive a gzip archive file. Sync flushes the compressor and fsyncs the
+// file, so the archived batches can be read back even if the run is cut short.
+type gzipArchive struct {
+	gz *gzip.Writer
+	f  *os.File
+}
+
+func (a *gzipArchive) Write(p []byte) (int, error) { return a.gz.Write(p) }
+
+func (a *gzipArchive) Sync() error {
+	if err := a.gz.Flush(); err != nil {
+		return err
+	}
+	return a.f.Sync()
+}
+
+// DatabaseSize returns the bytes the database uses: the pages in use for
+// SQLite, the database size for PostgreSQL
+func (s *Store) DatabaseSize() (int64, error) {
+	if s.DBType() == DBTypePostgres {
+		var size int64
+		err := s.gdb.Raw(`SELECT pg_database_size(current_database())`).Scan(&size).Error
+		return size, err
+	}
+	var pageCount, freePages, pageSize int64
+	if err := s.gdb.Raw(`PRAGMA page_count`).Scan(&pageCount).Error; err != nil {
+		return 0, err
+	}
+	if err := s.gdb.Raw(`PRAGMA freelist_count`).Scan(&freePages).Error; err != nil {
+		return 0, err
+	}
+	if err := s.gdb.Raw(`PRAGMA page_size`).Scan(&pageSize).Error; err != nil {
+		return 0, err
+	}
+	return (pageCount - freePages) * pageSize, nil
+}
+
+// Vacuum compacts the database (VACUUM on SQLite shrinks the file; on
+// PostgreSQL it marks dead rows reusable)
+func (s *Store) Vacuum() error {
+	return s.gdb.Exec(`VACUUM`).Error
+}
+
+// distinctTraderIDs lists the trader ids that have rows in a table
+func distinctTraderIDs(db *gorm.DB, model interface{}) []string {
+	var ids []string
+	db.Model(model).Distinct("trader_id").Pluck("trader_id", &ids)
+	return ids
+}
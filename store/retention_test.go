@@ -0,0 +1,188 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestApplyRetention(t *testing.T) {
+	st := newTestStore(t)
+	db := st.GormDB()
+	now := time.Now().UTC()
+	old := now.AddDate(0, 0, -40)
+
+	// Decisions: one old with prompts, one recent
+	for _, ts := range []time.Time{old, now} {
+		rec := &DecisionRecordDB{TraderID: "t1", Timestamp: ts, SystemPrompt: "system", InputPrompt: "input", RawResponse: "raw", DecisionJSON: `[{"action":"hold"}]`}
+		if err := db.Create(rec).Error; err != nil {
+			t.Fatalf("create decision: %v", err)
+		}
+	}
+
+	// Equity: 12 snapshots 5 minutes apart within one old hour, 3 recent ones
+	hour := old.Truncate(time.Hour)
+	for i := 0; i < 12; i++ {
+		if err := st.Equity().Save(&EquitySnapshot{TraderID: "t1", Timestamp: hour.Add(time.Duration(i) * 5 * time.Minute), TotalEquity: float64(100 + i)}); err != nil {
+			t.Fatalf("save equity: %v", err)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		if err := st.Equity().Save(&EquitySnapshot{TraderID: "t1", Timestamp: now.Add(-time.Duration(i) * time.Minute), TotalEquity: 200}); err != nil {
+			t.Fatalf("save equity: %v", err)
+		}
+	}
+
+	// Orders: an old filled order with a fill, an old open order, a recent filled order
+	oldMs := now.AddDate(0, 0, -200).UnixMilli()
+	orders := []*TraderOrder{
+		{TraderID: "t1", ExchangeID: "ex", ExchangeOrderID: "1", Symbol: "BTCUSDT", Side: "BUY", Type: "MARKET", Quantity: 1, Status: "FILLED", CreatedAt: oldMs},
+		{TraderID: "t1", ExchangeID: "ex", ExchangeOrderID: "2", Symbol: "BTCUSDT", Side: "SELL", Type: "LIMIT", Quantity: 1, Status: "NEW", CreatedAt: oldMs},
+		{TraderID: "t1", ExchangeID: "ex", ExchangeOrderID: "3", Symbol: "BTCUSDT", Side: "BUY", Type: "MARKET", Quantity: 1, Status: "FILLED", CreatedAt: now.UnixMilli()},
+	}
+	for _, o := range orders {
+		if err := db.Create(o).Error; err != nil {
+			t.Fatalf("create order: %v", err)
+		}
+	}
+	fill := &TraderFill{TraderID: "t1", ExchangeID: "ex", OrderID: orders[0].ID, ExchangeOrderID: "1", ExchangeTradeID: "f1",
+		Symbol: "BTCUSDT", Side: "BUY", Price: 100, Quantity: 1, QuoteQuantity: 100, CommissionAsset: "USDT", CreatedAt: oldMs}
+	if err := db.Create(fill).Error; err != nil {
+		t.Fatalf("create fill: %v", err)
+	}
+
+	policy := DefaultRetentionPolicy()
+	policy.ArchiveDir = filepath.Join(t.TempDir(), "archive")
+	report := st.ApplyRetention(policy)
+	if len(report.Errors) > 0 {
+		t.Fatalf("retention errors: %v", report.Errors)
+	}
+
+	if report.PromptsStripped != 1 {
+		t.Fatalf("PromptsStripped = %d, want 1", report.PromptsStripped)
+	}
+	var decisions []DecisionRecordDB
+	db.Order("timestamp ASC").Find(&decisions)
+	if len(decisions) != 2 {
+		t.Fatalf("decisions = %d, want 2 (stripping must keep records)", len(decisions))
+	}
+	if decisions[0].SystemPrompt != "" || decisions[0].InputPrompt != "" || decisions[0].RawResponse != "" || decisions[0].DecisionJSON == "" {
+		t.Fatalf("old decision not stripped correctly: %+v", decisions[0])
+	}
+	if decisions[1].SystemPrompt != "system" {
+		t.Fatal("recent decision was stripped")
+	}
+
+	if report.EquityDownsampled != 11 {
+		t.Fatalf("EquityDownsampled = %d, want 11", report.EquityDownsampled)
+	}
+	var kept []EquitySnapshot
+	db.Where("timestamp < ?", now.AddDate(0, 0, -30)).Find(&kept)
+	if len(kept) != 1 || kept[0].TotalEquity != 111 {
+		t.Fatalf("old hour kept %+v, want only the last snapshot (111)", kept)
+	}
+
+	if report.OrdersArchived != 1 || report.FillsArchived != 1 {
+		t.Fatalf("archived %d orders / %d fills, want 1 / 1", report.OrdersArchived, report.FillsArchived)
+	}
+	var remaining int64
+	db.Model(&TraderOrder{}).Count(&remaining)
+	if remaining != 2 {
+		t.Fatalf("remaining orders = %d, want 2 (open and recent orders stay)", remaining)
+	}
+	db.Model(&TraderFill{}).Count(&remaining)
+	if remaining != 0 {
+		t.Fatalf("remaining fills = %d, want 0", remaining)
+	}
+
+	f, err := os.Open(report.ArchiveFile)
+	if err != nil {
+		t.Fatalf("open archive: %v", err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	scanner := bufio.NewScanner(gz)
+	var lines []OrderArchiveRecord
+	for scanner.Scan() {
+		var rec OrderArchiveRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("decode archive line: %v", err)
+		}
+		lines = append(lines, rec)
+	}
+	if len(lines) != 1 || lines[0].Order.ExchangeOrderID != "1" || len(lines[0].Fills) != 1 {
+		t.Fatalf("archive content = %+v, want order 1 with its fill", lines)
+	}
+
+	last, err := st.GetLastRetentionReport()
+	if err != nil || last == nil || last.OrdersArchived != 1 {
+		t.Fatalf("last report = %+v, %v", last, err)
+	}
+
+	// A second run has nothing left to do and leaves no empty archive behind
+	again := st.ApplyRetention(policy)
+	if again.PromptsStripped != 0 || again.EquityDownsampled != 0 || again.OrdersArchived != 0 || again.ArchiveFile != "" {
+		t.Fatalf("second run changed data: %+v", again)
+	}
+}
+
+// failingSyncSink accepts writes but cannot make them durable
+type failingSyncSink struct{ bytes.Buffer }
+
+func (*failingSyncSink) Sync() error { return errors.New("disk full") }
+
+func TestArchiveClosedOrdersKeepsOrdersWhenSyncFails(t *testing.T) {
+	st := newTestStore(t)
+	db := st.GormDB()
+	order := &TraderOrder{TraderID: "t1", ExchangeID: "ex", ExchangeOrderID: "1", Symbol: "BTCUSDT", Side: "BUY", Type: "MARKET",
+		Quantity: 1, Status: "FILLED", CreatedAt: time.Now().AddDate(0, 0, -200).UnixMilli()}
+	if err := db.Create(order).Error; err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+
+	orders, _, err := st.Order().ArchiveClosedOrders(time.Now().UnixMilli(), &failingSyncSink{})
+	if err == nil || orders != 0 {
+		t.Fatalf("archived %d orders, err %v; want the sync error and nothing archived", orders, err)
+	}
+	var count int64
+	db.Model(&TraderOrder{}).Count(&count)
+	if count != 1 {
+		t.Fatalf("%d orders left, want the order kept when its archive could not be synced", count)
+	}
+}
+
+func TestRetentionPolicyRoundTrip(t *testing.T) {
+	st := newTestStore(t)
+
+	got, err := st.GetRetentionPolicy()
+	if err != nil {
+		t.Fatalf("GetRetentionPolicy: %v", err)
+	}
+	if got != DefaultRetentionPolicy() {
+		t.Fatalf("unsaved policy = %+v, want the default", got)
+	}
+	if got.Enabled {
+		t.Fatal("retention must stay off until it is enabled")
+	}
+
+	p := RetentionPolicy{Enabled: true, StripPromptsAfterDays: -5, DeleteDecisionsAfterDays: 90}
+	if err := st.SetRetentionPolicy(p); err != nil {
+		t.Fatalf("SetRetentionPolicy: %v", err)
+	}
+	got, err = st.GetRetentionPolicy()
+	if err != nil {
+		t.Fatalf("GetRetentionPolicy: %v", err)
+	}
+	if !got.Enabled || got.StripPromptsAfterDays != 0 || got.DeleteDecisionsAfterDays != 90 || got.IntervalHours != 24 || got.ArchiveDir == "" {
+		t.Fatalf("saved policy = %+v", got)
+	}
+}
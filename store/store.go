@@ -30,6 +30,11 @@ type Store struct {
 	grid           *GridStore
 	aiCharge       *AIChargeStore
 	telegramConfig TelegramConfigStore
+	workspace      *WorkspaceStore
+	audit          *AuditStore
+	notification   *NotificationStore
+	aiBudget       *AIBudgetStore
+	funding        *FundingStore
 
 	mu sync.RWMutex
 }
@@ -49,10 +54,10 @@ func New(dbPath string) (*Store, error) {
 
 	s := &Store{gdb: gdb, db: sqlDB}
 
-	// Initialize all table structures
-	if err := s.initTables(); err != nil {
+	// Create tables and apply pending schema migrations
+	if err := s.migrate(); err != nil {
 		sqlDB.Close()
-		return nil, fmt.Errorf("failed to initialize table structure: %w", err)
+		return nil, fmt.Errorf("failed to migrate database schema: %w", err)
 	}
 
 	// Initialize default data
@@ -80,10 +85,10 @@ func NewWithConfig(cfg DBConfig) (*Store, error) {
 
 	s := &Store{gdb: gdb, db: sqlDB}
 
-	// Initialize all table structures
-	if err := s.initTables(); err != nil {
+	// Create tables and apply pending schema migrations
+	if err := s.migrate(); err != nil {
 		sqlDB.Close()
-		return nil, fmt.Errorf("failed to initialize table structure: %w", err)
+		return nil, fmt.Errorf("failed to migrate database schema: %w", err)
 	}
 
 	// Initialize default data
@@ -100,6 +105,16 @@ func NewWithConfig(cfg DBConfig) (*Store, error) {
 	return s, nil
 }
 
+// OpenWithConfig opens the database without creating tables or applying
+// migrations, for maintenance commands that manage the schema themselves
+func OpenWithConfig(cfg DBConfig) (*Store, error) {
+	gdb, err := InitGormWithConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	return NewFromGorm(gdb)
+}
+
 // NewFromGorm creates Store from existing GORM connection
 func NewFromGorm(gdb *gorm.DB) (*Store, error) {
 	sqlDB, err := gdb.DB()
@@ -115,56 +130,23 @@ func NewFromDB(db *sql.DB) *Store {
 	return &Store{db: db}
 }
 
-// initTables initializes all database tables using GORM AutoMigrate
-func (s *Store) initTables() error {
-	// Create system_config table (GORM handles this via raw SQL for simplicity)
-	if err := s.gdb.Exec(`
-		CREATE TABLE IF NOT EXISTS system_config (
-			key TEXT PRIMARY KEY,
-			value TEXT NOT NULL
-		)
-	`).Error; err != nil {
-		return fmt.Errorf("failed to create system_config table: %w", err)
-	}
-
-	// Initialize sub-store tables
-	if err := s.User().initTables(); err != nil {
-		return fmt.Errorf("failed to initialize user tables: %w", err)
-	}
-	if err := s.AIModel().initTables(); err != nil {
-		return fmt.Errorf("failed to initialize AI model tables: %w", err)
-	}
-	if err := s.Exchange().initTables(); err != nil {
-		return fmt.Errorf("failed to initialize exchange tables: %w", err)
-	}
-	if err := s.Trader().initTables(); err != nil {
-		return fmt.Errorf("failed to initialize trader tables: %w", err)
-	}
-	if err := s.Decision().initTables(); err != nil {
-		return fmt.Errorf("failed to initialize decision log tables: %w", err)
-	}
-	if err := s.Position().InitTables(); err != nil {
-		return fmt.Errorf("failed to initialize position tables: %w", err)
-	}
-	if err := s.Strategy().initTables(); err != nil {
-		return fmt.Errorf("failed to initialize strategy tables: %w", err)
-	}
-	if err := s.Equity().initTables(); err != nil {
-		return fmt.Errorf("failed to initialize equity tables: %w", err)
-	}
-	if err := s.Order().InitTables(); err != nil {
-		return fmt.Errorf("failed to initialize order tables: %w", err)
-	}
-	if err := s.Grid().InitTables(); err != nil {
-		return fmt.Errorf("failed to initialize grid tables: %w", err)
-	}
-	if err := s.TelegramConfig().(*telegramConfigStore).initTables(); err != nil {
-		return fmt.Errorf("failed to initialize telegram config tables: %w", err)
+// migrate refuses to open a database migrated by a newer binary, then
+// applies the pending schema migrations
+func (s *Store) migrate() error {
+	m := s.Migrator()
+	if err := m.CheckVersion(); err != nil {
+		return err
 	}
-	if err := s.AICharge().initTables(); err != nil {
-		return fmt.Errorf("failed to initialize AI charge tables: %w", err)
+	applied, err := m.Up(0)
+	for _, mig := range applied {
+		logger.Infof("✅ Applied schema migration %d: %s", mig.Version, mig.Name)
 	}
-	return nil
+	return err
+}
+
+// Migrator returns the schema migrator for this database
+func (s *Store) Migrator() *Migrator {
+	return NewMigrator(s.gdb)
 }
 
 // initDefaultData initializes default data
@@ -178,12 +160,6 @@ func (s *Store) initDefaultData() error {
 	if err := s.Strategy().initDefaultData(); err != nil {
 		return err
 	}
-	// Migrate old decision_account_snapshots data to new trader_equity_snapshots table
-	if migrated, err := s.Equity().MigrateFromDecision(); err != nil {
-		logger.Warnf("failed to migrate equity data: %v", err)
-	} else if migrated > 0 {
-		logger.Infof("✅ Migrated %d equity records to new table", migrated)
-	}
 	return nil
 }
 
@@ -297,6 +273,56 @@ func (s *Store) AICharge() *AIChargeStore {
 	return s.aiCharge
 }
 
+// Workspace gets workspace and membership storage
+func (s *Store) Workspace() *WorkspaceStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.workspace == nil {
+		s.workspace = NewWorkspaceStore(s.gdb)
+	}
+	return s.workspace
+}
+
+// Audit gets audit trail storage
+func (s *Store) Audit() *AuditStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.audit == nil {
+		s.audit = NewAuditStore(s.gdb)
+	}
+	return s.audit
+}
+
+// Notification gets notification storage
+func (s *Store) Notification() *NotificationStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.notification == nil {
+		s.notification = NewNotificationStore(s.gdb)
+	}
+	return s.notification
+}
+
+// AIBudget gets AI spend budget storage
+func (s *Store) AIBudget() *AIBudgetStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.aiBudget == nil {
+		s.aiBudget = NewAIBudgetStore(s.gdb)
+	}
+	return s.aiBudget
+}
+
+// Funding gets funding payment storage
+func (s *Store) Funding() *FundingStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.funding == nil {
+		s.funding = NewFundingStore(s.gdb)
+	}
+	return s.funding
+}
+
 // TelegramConfig gets Telegram bot configuration storage
 func (s *Store) TelegramConfig() TelegramConfigStore {
 	s.mu.Lock()
@@ -139,6 +139,10 @@ func (c *StrategyConfig) ClampLimits() {
 // must use the exact frontend/backend enum values.
 func (c *StrategyConfig) NormalizeProductSchema() {
 	c.StrategyType = normalizeStrategyType(c.StrategyType)
+	c.MarketRegime.Variants = normalizeRegimeVariants(c.MarketRegime.Variants)
+	c.RiskControl.AssetTiers = normalizeAssetTiers(c.RiskControl.AssetTiers)
+	c.RiskControl.PositionSizing = normalizePositionSizing(c.RiskControl.PositionSizing)
+	c.RiskControl.CircuitBreaker = normalizeCircuitBreaker(c.RiskControl.CircuitBreaker)
 	c.CoinSource.StaticCoins = normalizeSymbols(c.CoinSource.StaticCoins)
 	c.CoinSource.ExcludedCoins = normalizeSymbols(c.CoinSource.ExcludedCoins)
 	c.CoinSource.SourceType = normalizeCoinSourceType(c.CoinSource.SourceType)
@@ -584,6 +588,18 @@ func StrategyClampWarnings(before, after StrategyConfig, lang string) []string {
 	appendFloat("Max Margin Usage", "max_margin_usage", before.RiskControl.MaxMarginUsage, after.RiskControl.MaxMarginUsage)
 	appendFloat("Min Position Size", "min_position_size", before.RiskControl.MinPositionSize, after.RiskControl.MinPositionSize)
 	appendInt("Min Confidence", "min_confidence", before.RiskControl.MinConfidence, after.RiskControl.MinConfidence)
+	for i, tier := range after.RiskControl.AssetTiers {
+		if i >= len(before.RiskControl.AssetTiers) {
+			break
+		}
+		prev := before.RiskControl.AssetTiers[i]
+		label := "Asset Tier " + tier.Name
+		key := "asset_tiers." + tier.Name
+		appendInt(label+" Max Leverage", key+".max_leverage", prev.MaxLeverage, tier.MaxLeverage)
+		appendFloat(label+" Max Position Value Ratio", key+".max_position_value_ratio", prev.MaxPositionValueRatio, tier.MaxPositionValueRatio)
+		appendFloat(label+" Min Position Size", key+".min_position_size", prev.MinPositionSize, tier.MinPositionSize)
+		appendInt(label+" Max Positions", key+".max_positions", prev.MaxPositions, tier.MaxPositions)
+	}
 	return warnings
 }
 
@@ -605,6 +621,16 @@ type Strategy struct {
 	Config        string    `gorm:"not null;default:'{}'" json:"config"`
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
+
+	// Versioning: CurrentVersion is the strategy_versions entry matching Config
+	// (0 = saved before versioning); PublishedVersion is the version shown in
+	// the strategy market (0 = live config)
+	CurrentVersion   int `gorm:"column:current_version;default:0" json:"current_version"`
+	PublishedVersion int `gorm:"column:published_version;default:0" json:"published_version"`
+
+	// Fork lineage: the strategy and version this one was forked from
+	ForkedFromID      string `gorm:"column:forked_from_id;default:'';index" json:"forked_from_id,omitempty"`
+	ForkedFromVersion int    `gorm:"column:forked_from_version;default:0" json:"forked_from_version,omitempty"`
 }
 
 func (Strategy) TableName() string { return "strategies" }
@@ -624,6 +650,7 @@ type StrategyConfig struct {
 	CustomPrompt   string               `json:"-"`
 	RiskControl    RiskControlConfig    `json:"-"`
 	PromptSections PromptSectionsConfig `json:"-"`
+	MarketRegime   MarketRegimeConfig   `json:"-"`
 
 	// Grid trading configuration (only used when StrategyType == "grid_trading")
 	GridConfig *GridStrategyConfig `json:"grid_config,omitempty"`
@@ -641,6 +668,7 @@ type AIStrategyConfig struct {
 	CustomPrompt   string               `json:"custom_prompt,omitempty"`
 	RiskControl    RiskControlConfig    `json:"risk_control"`
 	PromptSections PromptSectionsConfig `json:"prompt_sections,omitempty"`
+	MarketRegime   *MarketRegimeConfig  `json:"market_regime,omitempty"`
 }
 
 // PublishStrategyConfig contains settings shared by all strategy types.
@@ -679,6 +707,10 @@ func (c StrategyConfig) MarshalJSON() ([]byte, error) {
 			RiskControl:    c.RiskControl,
 			PromptSections: c.PromptSections,
 		}
+		if c.MarketRegime.Enabled || len(c.MarketRegime.Variants) > 0 {
+			regime := c.MarketRegime
+			out.AIConfig.MarketRegime = &regime
+		}
 	}
 
 	return json.Marshal(out)
@@ -717,6 +749,9 @@ func (c *StrategyConfig) UnmarshalJSON(data []byte) error {
 		c.CustomPrompt = raw.AIConfig.CustomPrompt
 		c.RiskControl = raw.AIConfig.RiskControl
 		c.PromptSections = raw.AIConfig.PromptSections
+		if raw.AIConfig.MarketRegime != nil {
+			c.MarketRegime = *raw.AIConfig.MarketRegime
+		}
 	} else {
 		if raw.CoinSource != nil {
 			c.CoinSource = *raw.CoinSource
@@ -787,6 +822,75 @@ type PromptSectionsConfig struct {
 	DecisionProcess string `json:"decision_process,omitempty"`
 }
 
+// Market regimes detected each cycle (keys of MarketRegimeConfig.Variants)
+const (
+	MarketRegimeTrendingUp   = "trending_up"   // strong uptrend with broad participation
+	MarketRegimeTrendingDown = "trending_down" // strong downtrend with broad participation
+	MarketRegimeRanging      = "ranging"       // no trend, normal volatility
+	MarketRegimeVolatile     = "volatile"      // no trend, wide or extreme volatility
+)
+
+// System prompt mode variants
+const (
+	PromptVariantBalanced     = "balanced"
+	PromptVariantAggressive   = "aggressive"
+	PromptVariantConservative = "conservative"
+	PromptVariantScalping     = "scalping"
+)
+
+// DefaultRegimeVariants the prompt variant used for each regime unless the
+// strategy maps it to another one
+var DefaultRegimeVariants = map[string]string{
+	MarketRegimeTrendingUp:   PromptVariantAggressive,
+	MarketRegimeTrendingDown: PromptVariantAggressive,
+	MarketRegimeRanging:      PromptVariantScalping,
+	MarketRegimeVolatile:     PromptVariantConservative,
+}
+
+// MarketRegimeConfig automatic prompt variant selection from the market
+// regime detected each cycle
+type MarketRegimeConfig struct {
+	// Enabled detects the regime each cycle and selects the prompt variant
+	Enabled bool `json:"enabled"`
+	// Variants maps regimes to the user's variants, overriding
+	// DefaultRegimeVariants, e.g. {"volatile": "scalping"}
+	Variants map[string]string `json:"variants,omitempty"`
+}
+
+// VariantFor returns the prompt variant of a regime
+func (c MarketRegimeConfig) VariantFor(regime string) string {
+	if v, ok := c.Variants[regime]; ok && v != "" {
+		return v
+	}
+	if v, ok := DefaultRegimeVariants[regime]; ok {
+		return v
+	}
+	return PromptVariantBalanced
+}
+
+// normalizeRegimeVariants drops mappings of unknown regimes or variants
+func normalizeRegimeVariants(variants map[string]string) map[string]string {
+	if len(variants) == 0 {
+		return nil
+	}
+	valid := map[string]bool{
+		PromptVariantBalanced: true, PromptVariantAggressive: true,
+		PromptVariantConservative: true, PromptVariantScalping: true,
+	}
+	out := make(map[string]string, len(variants))
+	for regime, variant := range variants {
+		regime = strings.ToLower(strings.TrimSpace(regime))
+		variant = strings.ToLower(strings.TrimSpace(variant))
+		if _, known := DefaultRegimeVariants[regime]; known && valid[variant] {
+			out[regime] = variant
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
 // CoinSourceConfig coin source configuration
 type CoinSourceConfig struct {
 	// source type shown in the product editor: "static" | "ai500" | "oi_top" | "oi_low"
@@ -932,6 +1036,16 @@ type RiskControlConfig struct {
 	MinRiskRewardRatio float64 `json:"min_risk_reward_ratio"`
 	// Min AI confidence to open position (AI guided)
 	MinConfidence int `json:"min_confidence"`
+
+	// User-defined asset tiers with their own leverage, position value, min size
+	// and concurrent position limits; replace the BTC/ETH and altcoin split when set
+	AssetTiers []AssetTierConfig `json:"asset_tiers,omitempty"`
+
+	// Code-enforced position sizing; the AI's size becomes an upper bound or a hint
+	PositionSizing PositionSizingConfig `json:"position_sizing"`
+
+	// Account daily loss, drawdown and losing streak limits (CODE ENFORCED)
+	CircuitBreaker CircuitBreakerConfig `json:"circuit_breaker"`
 }
 
 // NewStrategyStore creates a new StrategyStore
@@ -941,7 +1055,7 @@ func NewStrategyStore(db *gorm.DB) *StrategyStore {
 
 func (s *StrategyStore) initTables() error {
 	// AutoMigrate will add missing columns without dropping existing data
-	return s.db.AutoMigrate(&Strategy{})
+	return s.db.AutoMigrate(&Strategy{}, &StrategyVersion{}, &StrategyStar{}, &StrategyRating{}, &StrategySubscription{})
 }
 
 func (s *StrategyStore) initDefaultData() error {
@@ -1071,23 +1185,15 @@ Open only when Claw402 Signal Lab, cost/liquidation heatmap and raw candles broa
 	return config
 }
 
-// Create create a strategy
+// Create create a strategy (recorded as version 1, authored by the owner)
 func (s *StrategyStore) Create(strategy *Strategy) error {
-	return s.db.Create(strategy).Error
+	return s.CreateWithVersion(strategy, StrategyVersionMeta{})
 }
 
-// Update update a strategy
+// Update update a strategy (recorded as a new version, authored by the owner)
 func (s *StrategyStore) Update(strategy *Strategy) error {
-	return s.db.Model(&Strategy{}).
-		Where("id = ? AND user_id = ?", strategy.ID, strategy.UserID).
-		Updates(map[string]interface{}{
-			"name":           strategy.Name,
-			"description":    strategy.Description,
-			"config":         strategy.Config,
-			"is_public":      strategy.IsPublic,
-			"config_visible": strategy.ConfigVisible,
-			"updated_at":     time.Now().UTC(),
-		}).Error
+	_, err := s.UpdateWithVersion(strategy, StrategyVersionMeta{})
+	return err
 }
 
 // Delete delete a strategy
@@ -1111,7 +1217,13 @@ func (s *StrategyStore) Delete(userID, id string) error {
 		return fmt.Errorf("cannot delete strategy in use by %d trader(s) - reassign those traders first", count)
 	}
 
-	return s.db.Where("id = ? AND user_id = ?", id, userID).Delete(&Strategy{}).Error
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", id, userID).Delete(&Strategy{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return tx.Where("strategy_id = ?", id).Delete(&StrategyVersion{}).Error
+	})
 }
 
 // List get user's strategy list
@@ -1138,6 +1250,17 @@ func (s *StrategyStore) ListPublic() ([]*Strategy, error) {
 	return strategies, nil
 }
 
+// GetPublic get a strategy published to the market with its config visible
+func (s *StrategyStore) GetPublic(id string) (*Strategy, error) {
+	var st Strategy
+	err := s.db.Where("id = ? AND is_public = ? AND config_visible = ?", id, true, true).
+		First(&st).Error
+	if err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
 // Get get a single strategy
 func (s *StrategyStore) Get(userID, id string) (*Strategy, error) {
 	var st Strategy
@@ -1208,7 +1331,7 @@ func (s *StrategyStore) Duplicate(userID, sourceID, newID, newName string) error
 		Config:      source.Config,
 	}
 
-	return s.Create(newStrategy)
+	return s.CreateWithVersion(newStrategy, StrategyVersionMeta{Note: "duplicated from " + source.Name})
 }
 
 // ParseConfig parse strategy configuration JSON
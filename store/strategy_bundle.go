@@ -0,0 +1,347 @@
+package store
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// StrategyBundleFormat identifies a portable strategy bundle
+const StrategyBundleFormat = "nofx-strategy-bundle"
+
+// Strategy bundle schema versions:
+//
+//	1 — flat config: coin_source, indicators, risk_control, ... at the top level
+//	2 — product schema: strategy_type + ai_config | grid_config (current)
+const (
+	StrategyBundleSchemaV1      = 1
+	StrategyBundleSchemaVersion = 2
+)
+
+// Errors returned while reading a bundle
+var (
+	ErrStrategyBundleFormat    = errors.New("not a strategy bundle")
+	ErrStrategyBundleSchema    = errors.New("unsupported strategy bundle schema version")
+	ErrStrategyBundleSignature = errors.New("strategy bundle signature is invalid")
+)
+
+// StrategyBundle a self-contained, secret-free strategy export that can be
+// imported on any instance. The signature, when present, covers the bundle
+// with the signature field itself omitted.
+type StrategyBundle struct {
+	Format        string                   `json:"format"`
+	SchemaVersion int                      `json:"schema_version"`
+	ExportedAt    time.Time                `json:"exported_at"`
+	Strategy      StrategyBundleMeta       `json:"strategy"`
+	Config        json.RawMessage          `json:"config"`
+	Signature     *StrategyBundleSignature `json:"signature,omitempty"`
+}
+
+// StrategyBundleMeta descriptive fields carried alongside the config
+type StrategyBundleMeta struct {
+	Name          string `json:"name"`
+	Description   string `json:"description,omitempty"`
+	StrategyType  string `json:"strategy_type,omitempty"`
+	SourceVersion int    `json:"source_version,omitempty"` // strategy version on the exporting instance
+}
+
+// StrategyBundleSignature detached ed25519 signature of a bundle
+type StrategyBundleSignature struct {
+	Algorithm string `json:"algorithm"`  // always "ed25519"
+	PublicKey string `json:"public_key"` // base64 (std) encoded 32-byte public key
+	Value     string `json:"value"`      // base64 (std) encoded 64-byte signature
+}
+
+// StrategyBundleImport the result of reading a bundle: a clean config plus a
+// report of what was migrated and dropped on the way in
+type StrategyBundleImport struct {
+	Meta              StrategyBundleMeta
+	Config            StrategyConfig
+	FromSchemaVersion int
+	Migrated          bool
+	DroppedFields     []string // dotted paths of input fields that were not imported
+	Signed            bool
+	SignerKey         string // base64 public key of a valid signature
+	SignerFingerprint string // short hex fingerprint of SignerKey
+}
+
+// StripSecrets removes credentials that must never leave the instance:
+// the NofxOS API key and external data source headers (usually auth tokens).
+// It returns the dotted paths that were cleared.
+func (c *StrategyConfig) StripSecrets() []string {
+	var stripped []string
+	if c.Indicators.NofxOSAPIKey != "" {
+		c.Indicators.NofxOSAPIKey = ""
+		stripped = append(stripped, "ai_config.indicators.nofxos_api_key")
+	}
+	for i := range c.Indicators.ExternalDataSources {
+		if len(c.Indicators.ExternalDataSources[i].Headers) > 0 {
+			c.Indicators.ExternalDataSources[i].Headers = nil
+			stripped = append(stripped, fmt.Sprintf("ai_config.indicators.external_data_sources.[%d].headers", i))
+		}
+	}
+	return stripped
+}
+
+// NewStrategyBundle exports a strategy as an unsigned bundle with secrets
+// and publish settings removed
+func NewStrategyBundle(strategy *Strategy) (*StrategyBundle, error) {
+	config, err := strategy.ParseConfig()
+	if err != nil {
+		return nil, err
+	}
+	config.StripSecrets()
+	// Visibility is a property of the strategy on this instance, not of the config
+	config.PublishConfig = nil
+
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize strategy configuration: %w", err)
+	}
+	strategyType := config.StrategyType
+	if strategyType == "" {
+		strategyType = "ai_trading"
+	}
+	return &StrategyBundle{
+		Format:        StrategyBundleFormat,
+		SchemaVersion: StrategyBundleSchemaVersion,
+		ExportedAt:    time.Now().UTC().Truncate(time.Second),
+		Strategy: StrategyBundleMeta{
+			Name:          strategy.Name,
+			Description:   strategy.Description,
+			StrategyType:  strategyType,
+			SourceVersion: strategy.CurrentVersion,
+		},
+		Config: raw,
+	}, nil
+}
+
+// signingPayload is the canonical byte sequence covered by the signature
+func (b *StrategyBundle) signingPayload() ([]byte, error) {
+	unsigned := *b
+	unsigned.Signature = nil
+	return json.Marshal(unsigned)
+}
+
+// Sign signs the bundle with an ed25519 private key, replacing any existing signature
+func (b *StrategyBundle) Sign(key ed25519.PrivateKey) error {
+	if len(key) != ed25519.PrivateKeySize {
+		return fmt.Errorf("invalid ed25519 private key length %d", len(key))
+	}
+	payload, err := b.signingPayload()
+	if err != nil {
+		return err
+	}
+	b.Signature = &StrategyBundleSignature{
+		Algorithm: "ed25519",
+		PublicKey: base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)),
+		Value:     base64.StdEncoding.EncodeToString(ed25519.Sign(key, payload)),
+	}
+	return nil
+}
+
+// Verify checks the embedded signature. It returns (false, nil) for an
+// unsigned bundle and an error when a signature is present but does not match.
+func (b *StrategyBundle) Verify() (bool, error) {
+	if b.Signature == nil {
+		return false, nil
+	}
+	if b.Signature.Algorithm != "ed25519" {
+		return false, fmt.Errorf("%w: unsupported algorithm %q", ErrStrategyBundleSignature, b.Signature.Algorithm)
+	}
+	pub, err := base64.StdEncoding.DecodeString(b.Signature.PublicKey)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return false, fmt.Errorf("%w: malformed public key", ErrStrategyBundleSignature)
+	}
+	sig, err := base64.StdEncoding.DecodeString(b.Signature.Value)
+	if err != nil {
+		return false, fmt.Errorf("%w: malformed signature", ErrStrategyBundleSignature)
+	}
+	payload, err := b.signingPayload()
+	if err != nil {
+		return false, err
+	}
+	if !ed25519.Verify(ed25519.PublicKey(pub), payload, sig) {
+		return false, ErrStrategyBundleSignature
+	}
+	return true, nil
+}
+
+// StrategyKeyFingerprint short, human-comparable identifier of a public key
+func StrategyKeyFingerprint(publicKey string) string {
+	sum := sha256.Sum256([]byte(publicKey))
+	return hex.EncodeToString(sum[:8])
+}
+
+// ReadStrategyBundle parses, verifies and migrates a bundle. Secrets and
+// fields this version does not understand are dropped and reported; limits
+// are not clamped here so the caller can report clamp warnings.
+func ReadStrategyBundle(data []byte) (*StrategyBundleImport, error) {
+	var bundle StrategyBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrStrategyBundleFormat, err)
+	}
+	if bundle.Format != StrategyBundleFormat || len(bundle.Config) == 0 {
+		return nil, ErrStrategyBundleFormat
+	}
+	if bundle.SchemaVersion < StrategyBundleSchemaV1 || bundle.SchemaVersion > StrategyBundleSchemaVersion {
+		return nil, fmt.Errorf("%w %d (this instance supports up to %d)", ErrStrategyBundleSchema, bundle.SchemaVersion, StrategyBundleSchemaVersion)
+	}
+
+	signed, err := bundle.Verify()
+	if err != nil {
+		return nil, err
+	}
+
+	var input map[string]any
+	if err := json.Unmarshal(bundle.Config, &input); err != nil {
+		return nil, fmt.Errorf("%w: config is not a JSON object", ErrStrategyBundleFormat)
+	}
+	migrated := false
+	if bundle.SchemaVersion == StrategyBundleSchemaV1 {
+		input = migrateStrategyBundleV1(input)
+		migrated = true
+	}
+
+	migratedJSON, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+	var config StrategyConfig
+	if err := json.Unmarshal(migratedJSON, &config); err != nil {
+		return nil, fmt.Errorf("invalid strategy config: %w", err)
+	}
+	config.PublishConfig = nil
+	stripped := config.StripSecrets()
+
+	result := &StrategyBundleImport{
+		Meta:              bundle.Strategy,
+		Config:            config,
+		FromSchemaVersion: bundle.SchemaVersion,
+		Migrated:          migrated,
+		DroppedFields:     droppedStrategyFields(input, &config, stripped),
+		Signed:            signed,
+	}
+	if signed {
+		result.SignerKey = bundle.Signature.PublicKey
+		result.SignerFingerprint = StrategyKeyFingerprint(bundle.Signature.PublicKey)
+	}
+	return result, nil
+}
+
+// migrateStrategyBundleV1 nests the flat v1 AI fields under ai_config
+func migrateStrategyBundleV1(input map[string]any) map[string]any {
+	out := make(map[string]any, len(input))
+	aiConfig := make(map[string]any)
+	for key, value := range input {
+		switch key {
+		case "coin_source", "indicators", "custom_prompt", "risk_control", "prompt_sections":
+			aiConfig[key] = value
+		default:
+			out[key] = value
+		}
+	}
+	if _, ok := out["strategy_type"]; !ok {
+		if _, grid := out["grid_config"]; grid {
+			out["strategy_type"] = "grid_trading"
+		} else {
+			out["strategy_type"] = "ai_trading"
+		}
+	}
+	if len(aiConfig) > 0 {
+		if _, ok := out["ai_config"]; !ok {
+			out["ai_config"] = aiConfig
+		}
+	}
+	return out
+}
+
+// droppedStrategyFields lists the non-empty input leaves that did not survive
+// parsing (unknown fields, the wrong section for the strategy type, publish
+// settings) plus stripped secrets
+func droppedStrategyFields(input map[string]any, config *StrategyConfig, stripped []string) []string {
+	parsedJSON, err := json.Marshal(config)
+	if err != nil {
+		return stripped
+	}
+	var parsed any
+	_ = json.Unmarshal(parsedJSON, &parsed)
+
+	kept := make(map[string]bool)
+	collectStrategyFieldPaths("", parsed, kept, nil)
+
+	dropped := make(map[string]bool)
+	for _, path := range stripped {
+		dropped[path] = true
+	}
+	inputLeaves := make(map[string]bool)
+	collectStrategyFieldPaths("", input, inputLeaves, isEmptyStrategyValue)
+	for path := range inputLeaves {
+		if kept[path] || coveredByStrippedPath(path, stripped) {
+			continue
+		}
+		dropped[path] = true
+	}
+
+	result := make([]string, 0, len(dropped))
+	for path := range dropped {
+		result = append(result, path)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// collectStrategyFieldPaths records the dotted path of every leaf; arrays of
+// objects are walked by index, other arrays count as one leaf. skip filters leaves.
+func collectStrategyFieldPaths(prefix string, v any, out map[string]bool, skip func(any) bool) {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+	switch val := v.(type) {
+	case map[string]any:
+		for key, child := range val {
+			collectStrategyFieldPaths(join(key), child, out, skip)
+		}
+		return
+	case []any:
+		if len(val) > 0 {
+			if _, ok := val[0].(map[string]any); ok {
+				for i, item := range val {
+					collectStrategyFieldPaths(join(fmt.Sprintf("[%d]", i)), item, out, skip)
+				}
+				return
+			}
+		}
+	}
+	if prefix != "" && (skip == nil || !skip(v)) {
+		out[prefix] = true
+	}
+}
+
+func coveredByStrippedPath(path string, stripped []string) bool {
+	for _, s := range stripped {
+		if path == s || strings.HasPrefix(path, s+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmptyStrategyValue zero values are dropped silently by omitempty and are not worth reporting
+func isEmptyStrategyValue(v any) bool {
+	if v == nil {
+		return true
+	}
+	return reflect.ValueOf(v).IsZero() || (reflect.ValueOf(v).Kind() == reflect.Slice && reflect.ValueOf(v).Len() == 0)
+}
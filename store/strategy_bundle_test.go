@@ -0,0 +1,122 @@
+package store
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestStrategyBundleStripsSecretsAndRoundTrips(t *testing.T) {
+	cfg := GetDefaultStrategyConfig("en")
+	cfg.Indicators.NofxOSAPIKey = "cm_secret"
+	cfg.Indicators.ExternalDataSources = []ExternalDataSource{
+		{Name: "feed", Type: "api", URL: "https://example.com", Method: "GET", Headers: map[string]string{"Authorization": "Bearer x"}},
+	}
+	cfg.PublishConfig = &PublishStrategyConfig{IsPublic: true, ConfigVisible: true}
+	strategy := &Strategy{ID: "s1", UserID: "u1", Name: "Trend", CurrentVersion: 4}
+	if err := strategy.SetConfig(&cfg); err != nil {
+		t.Fatalf("set config: %v", err)
+	}
+
+	bundle, err := NewStrategyBundle(strategy)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	data, _ := json.Marshal(bundle)
+	if strings.Contains(string(data), "cm_secret") || strings.Contains(string(data), "Bearer x") {
+		t.Fatalf("bundle leaks secrets: %s", data)
+	}
+	if strings.Contains(string(data), "publish_config") {
+		t.Fatalf("bundle should not carry publish settings: %s", data)
+	}
+
+	imported, err := ReadStrategyBundle(data)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if imported.Signed || imported.Migrated || len(imported.DroppedFields) != 0 {
+		t.Fatalf("unexpected import report %+v", imported)
+	}
+	if imported.Meta.SourceVersion != 4 || imported.Config.RiskControl.MaxPositions != cfg.RiskControl.MaxPositions {
+		t.Fatalf("config did not round-trip: %+v", imported)
+	}
+	if len(imported.Config.Indicators.ExternalDataSources) != 1 || imported.Config.Indicators.ExternalDataSources[0].URL != "https://example.com" {
+		t.Fatalf("external source lost: %+v", imported.Config.Indicators.ExternalDataSources)
+	}
+}
+
+func TestStrategyBundleSignature(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	cfg := GetDefaultStrategyConfig("en")
+	strategy := &Strategy{ID: "s1", Name: "Signed"}
+	_ = strategy.SetConfig(&cfg)
+
+	bundle, err := NewStrategyBundle(strategy)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if err := bundle.Sign(key); err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	// Whitespace changes must not break the signature
+	data, _ := json.MarshalIndent(bundle, "", "  ")
+	imported, err := ReadStrategyBundle(data)
+	if err != nil || !imported.Signed || imported.SignerFingerprint == "" {
+		t.Fatalf("expected valid signature, got %+v err=%v", imported, err)
+	}
+
+	bundle.Strategy.Name = "Tampered"
+	data, _ = json.Marshal(bundle)
+	if _, err := ReadStrategyBundle(data); !errors.Is(err, ErrStrategyBundleSignature) {
+		t.Fatalf("expected signature error for tampered bundle, got %v", err)
+	}
+}
+
+func TestStrategyBundleMigratesV1AndReportsDroppedFields(t *testing.T) {
+	data := []byte(`{
+		"format": "nofx-strategy-bundle",
+		"schema_version": 1,
+		"strategy": {"name": "Legacy"},
+		"config": {
+			"language": "en",
+			"coin_source": {"source_type": "static", "static_coins": ["BTCUSDT"]},
+			"risk_control": {"max_positions": 2, "legacy_knob": 7},
+			"indicators": {"nofxos_api_key": "cm_old"},
+			"publish_config": {"is_public": true},
+			"unknown_section": {"x": 1}
+		}
+	}`)
+
+	imported, err := ReadStrategyBundle(data)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if !imported.Migrated || imported.FromSchemaVersion != 1 {
+		t.Fatalf("expected v1 migration, got %+v", imported)
+	}
+	if imported.Config.RiskControl.MaxPositions != 2 || len(imported.Config.CoinSource.StaticCoins) != 1 {
+		t.Fatalf("flat v1 fields not migrated: %+v", imported.Config)
+	}
+	if imported.Config.Indicators.NofxOSAPIKey != "" {
+		t.Fatalf("secret should be dropped on import")
+	}
+	want := []string{
+		"ai_config.indicators.nofxos_api_key",
+		"ai_config.risk_control.legacy_knob",
+		"publish_config.is_public",
+		"unknown_section.x",
+	}
+	if strings.Join(imported.DroppedFields, ",") != strings.Join(want, ",") {
+		t.Fatalf("dropped fields = %v, want %v", imported.DroppedFields, want)
+	}
+
+	future := []byte(`{"format":"nofx-strategy-bundle","schema_version":99,"config":{}}`)
+	if _, err := ReadStrategyBundle(future); !errors.Is(err, ErrStrategyBundleSchema) {
+		t.Fatalf("expected schema error, got %v", err)
+	}
+}
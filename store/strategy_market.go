@@ -0,0 +1,383 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Subscription modes
+const (
+	SubscriptionModeNotify     = "notify"      // notify the subscriber of new versions
+	SubscriptionModeAutoUpdate = "auto_update" // also apply new versions to the fork
+)
+
+// ErrStrategyNotPublic is returned for marketplace actions on a private strategy
+var ErrStrategyNotPublic = errors.New("strategy is not published to the market")
+
+// StrategyStar a user starring a public strategy
+type StrategyStar struct {
+	StrategyID string    `gorm:"column:strategy_id;primaryKey" json:"strategy_id"`
+	UserID     string    `gorm:"column:user_id;primaryKey" json:"user_id"`
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (StrategyStar) TableName() string { return "strategy_stars" }
+
+// StrategyRating a user's 1–5 rating of a public strategy (one per user)
+type StrategyRating struct {
+	StrategyID string    `gorm:"column:strategy_id;primaryKey" json:"strategy_id"`
+	UserID     string    `gorm:"column:user_id;primaryKey" json:"user_id"`
+	Score      int       `gorm:"column:score;not null" json:"score"`
+	Review     string    `gorm:"column:review;type:text;default:''" json:"review,omitempty"`
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (StrategyRating) TableName() string { return "strategy_ratings" }
+
+// StrategySubscription links a user's fork to the public strategy it was
+// forked from, so new published versions reach the fork
+type StrategySubscription struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	StrategyID  string    `gorm:"column:strategy_id;not null;uniqueIndex:idx_strategy_subscriptions_source_fork" json:"strategy_id"`
+	ForkID      string    `gorm:"column:fork_id;not null;uniqueIndex:idx_strategy_subscriptions_source_fork" json:"fork_id"`
+	UserID      string    `gorm:"column:user_id;not null;index" json:"user_id"`
+	Mode        string    `gorm:"column:mode;not null;default:'notify'" json:"mode"`
+	SeenVersion int       `gorm:"column:seen_version;default:0" json:"seen_version"` // last published version delivered
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (StrategySubscription) TableName() string { return "strategy_subscriptions" }
+
+// StrategyMarketStats social counters of a public strategy
+type StrategyMarketStats struct {
+	Stars       int64   `json:"stars"`
+	Forks       int64   `json:"forks"`
+	RatingCount int64   `json:"rating_count"`
+	RatingAvg   float64 `json:"rating_avg"`
+}
+
+// IsValidSubscriptionMode reports whether mode is a known subscription mode
+func IsValidSubscriptionMode(mode string) bool {
+	return mode == SubscriptionModeNotify || mode == SubscriptionModeAutoUpdate
+}
+
+// GetPublishedConfig returns the name, description and config of the
+// published version of a public strategy (the live config for strategies
+// published before versioning)
+func (s *StrategyStore) GetPublishedConfig(st *Strategy) (name, description, config string, version int) {
+	if st.PublishedVersion > 0 {
+		var v StrategyVersion
+		if err := s.db.Where("strategy_id = ? AND version = ?", st.ID, st.PublishedVersion).First(&v).Error; err == nil {
+			return v.Name, v.Description, v.Config, v.Version
+		}
+	}
+	return st.Name, st.Description, st.Config, st.CurrentVersion
+}
+
+// Publish makes the current version of a strategy the published market
+// version and returns it
+func (s *StrategyStore) Publish(userID, strategyID string) (*Strategy, error) {
+	st, err := s.Get(userID, strategyID)
+	if err != nil {
+		return nil, err
+	}
+	if st.IsDefault || st.UserID != userID {
+		return nil, fmt.Errorf("cannot publish system default strategy")
+	}
+	if st.CurrentVersion == 0 {
+		// Saved before versioning: record the live state as the baseline
+		// version so there is something to publish
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := ensureBaselineStrategyVersion(tx, st); err != nil {
+				return err
+			}
+			if err := tx.Model(&StrategyVersion{}).Where("strategy_id = ?", st.ID).
+				Select("COALESCE(MAX(version), 0)").Scan(&st.CurrentVersion).Error; err != nil {
+				return err
+			}
+			return tx.Model(&Strategy{}).Where("id = ? AND user_id = ?", st.ID, userID).
+				Update("current_version", st.CurrentVersion).Error
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := s.db.Model(&Strategy{}).Where("id = ? AND user_id = ?", strategyID, userID).
+		Updates(map[string]interface{}{
+			"is_public":         true,
+			"published_version": st.CurrentVersion,
+		}).Error; err != nil {
+		return nil, err
+	}
+	st.IsPublic = true
+	st.PublishedVersion = st.CurrentVersion
+	return st, nil
+}
+
+// Fork copies the published version of a public strategy (or any version of
+// the user's own strategy) into a new private strategy, records its lineage
+// and optionally subscribes the fork to future versions. Secrets are stripped.
+func (s *StrategyStore) Fork(userID, sourceID, newID, newName, subscribeMode string) (*Strategy, error) {
+	source, err := s.Get(userID, sourceID)
+	if err != nil || source.UserID != userID {
+		if source, err = s.GetPublic(sourceID); err != nil {
+			return nil, ErrStrategyNotPublic
+		}
+	}
+	name, description, configJSON, version := source.Name, source.Description, source.Config, source.CurrentVersion
+	if source.UserID != userID {
+		name, description, configJSON, version = s.GetPublishedConfig(source)
+	}
+
+	config, err := (&Strategy{Config: configJSON}).ParseConfig()
+	if err != nil {
+		return nil, err
+	}
+	config.StripSecrets()
+	config.PublishConfig = nil
+	if newName == "" {
+		newName = name + " (fork)"
+	}
+	fork := &Strategy{
+		ID:                newID,
+		UserID:            userID,
+		Name:              newName,
+		Description:       description,
+		ConfigVisible:     true,
+		ForkedFromID:      source.ID,
+		ForkedFromVersion: version,
+	}
+	if err := fork.SetConfig(config); err != nil {
+		return nil, err
+	}
+
+	note := fmt.Sprintf("forked from %s v%d", source.Name, version)
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		fork.CurrentVersion = 1
+		if err := tx.Create(fork).Error; err != nil {
+			return err
+		}
+		if _, err := appendStrategyVersion(tx, fork, StrategyVersionMeta{AuthorID: userID, Note: note}); err != nil {
+			return err
+		}
+		if subscribeMode == "" {
+			return nil
+		}
+		return tx.Create(&StrategySubscription{
+			StrategyID:  source.ID,
+			ForkID:      fork.ID,
+			UserID:      userID,
+			Mode:        subscribeMode,
+			SeenVersion: version,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fork, nil
+}
+
+// Star stars (on=true) or unstars a public strategy
+func (s *StrategyStore) Star(userID, strategyID string, on bool) error {
+	if !on {
+		return s.db.Where("strategy_id = ? AND user_id = ?", strategyID, userID).Delete(&StrategyStar{}).Error
+	}
+	if _, err := s.GetListed(strategyID); err != nil {
+		return err
+	}
+	return s.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&StrategyStar{StrategyID: strategyID, UserID: userID}).Error
+}
+
+// Rate sets the user's rating of a public strategy (score 1–5). Authors
+// cannot rate their own strategies.
+func (s *StrategyStore) Rate(userID, strategyID string, score int, review string) error {
+	if score < 1 || score > 5 {
+		return fmt.Errorf("score must be between 1 and 5")
+	}
+	st, err := s.GetListed(strategyID)
+	if err != nil {
+		return err
+	}
+	if st.UserID == userID {
+		return fmt.Errorf("cannot rate your own strategy")
+	}
+	rating := &StrategyRating{StrategyID: strategyID, UserID: userID, Score: score, Review: review}
+	return s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "strategy_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"score", "review", "updated_at"}),
+	}).Create(rating).Error
+}
+
+// ListRatings lists the ratings of a strategy, newest first
+func (s *StrategyStore) ListRatings(strategyID string, limit int) ([]*StrategyRating, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	var ratings []*StrategyRating
+	err := s.db.Where("strategy_id = ?", strategyID).Order("updated_at DESC").Limit(limit).Find(&ratings).Error
+	return ratings, err
+}
+
+// MarketStats returns stars, forks and rating aggregates keyed by strategy ID
+func (s *StrategyStore) MarketStats(strategyIDs []string) (map[string]*StrategyMarketStats, error) {
+	stats := make(map[string]*StrategyMarketStats, len(strategyIDs))
+	for _, id := range strategyIDs {
+		stats[id] = &StrategyMarketStats{}
+	}
+	if len(strategyIDs) == 0 {
+		return stats, nil
+	}
+
+	type countRow struct {
+		ID    string
+		Count int64
+		Avg   float64
+	}
+	var rows []countRow
+	if err := s.db.Model(&StrategyStar{}).Select("strategy_id AS id, COUNT(*) AS count").
+		Where("strategy_id IN ?", strategyIDs).Group("strategy_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		stats[r.ID].Stars = r.Count
+	}
+	rows = nil
+	if err := s.db.Model(&Strategy{}).Select("forked_from_id AS id, COUNT(*) AS count").
+		Where("forked_from_id IN ?", strategyIDs).Group("forked_from_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		stats[r.ID].Forks = r.Count
+	}
+	rows = nil
+	if err := s.db.Model(&StrategyRating{}).Select("strategy_id AS id, COUNT(*) AS count, AVG(score) AS avg").
+		Where("strategy_id IN ?", strategyIDs).Group("strategy_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		stats[r.ID].RatingCount = r.Count
+		stats[r.ID].RatingAvg = r.Avg
+	}
+	return stats, nil
+}
+
+// Lineage walks forked_from links from a strategy back to its root (nearest first).
+// A deleted ancestor ends the walk.
+func (s *StrategyStore) Lineage(strategy *Strategy) []*Strategy {
+	var lineage []*Strategy
+	seen := map[string]bool{strategy.ID: true}
+	next := strategy.ForkedFromID
+	for next != "" && !seen[next] && len(lineage) < 20 {
+		seen[next] = true
+		var parent Strategy
+		if err := s.db.Where("id = ?", next).First(&parent).Error; err != nil {
+			break
+		}
+		lineage = append(lineage, &parent)
+		next = parent.ForkedFromID
+	}
+	return lineage
+}
+
+// Subscribe subscribes one of the user's forks to its source strategy
+func (s *StrategyStore) Subscribe(userID, forkID, mode string) (*StrategySubscription, error) {
+	if !IsValidSubscriptionMode(mode) {
+		return nil, fmt.Errorf("invalid subscription mode %q", mode)
+	}
+	fork, err := s.Get(userID, forkID)
+	if err != nil || fork.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if fork.ForkedFromID == "" {
+		return nil, fmt.Errorf("strategy is not a fork")
+	}
+	sub := &StrategySubscription{
+		StrategyID:  fork.ForkedFromID,
+		ForkID:      fork.ID,
+		UserID:      userID,
+		Mode:        mode,
+		SeenVersion: fork.ForkedFromVersion,
+	}
+	err = s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "strategy_id"}, {Name: "fork_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"mode"}),
+	}).Create(sub).Error
+	if err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// Unsubscribe removes the subscription of one of the user's forks
+func (s *StrategyStore) Unsubscribe(userID, forkID string) error {
+	return s.db.Where("user_id = ? AND fork_id = ?", userID, forkID).Delete(&StrategySubscription{}).Error
+}
+
+// ListSubscriptions lists a user's subscriptions
+func (s *StrategyStore) ListSubscriptions(userID string) ([]*StrategySubscription, error) {
+	var subs []*StrategySubscription
+	err := s.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&subs).Error
+	return subs, err
+}
+
+// PendingSubscriptions lists subscriptions of a strategy that have not yet
+// seen the given published version
+func (s *StrategyStore) PendingSubscriptions(strategyID string, version int) ([]*StrategySubscription, error) {
+	var subs []*StrategySubscription
+	err := s.db.Where("strategy_id = ? AND seen_version < ?", strategyID, version).Find(&subs).Error
+	return subs, err
+}
+
+// MarkSubscriptionSeen records that a subscription received a version
+func (s *StrategyStore) MarkSubscriptionSeen(id int64, version int) error {
+	return s.db.Model(&StrategySubscription{}).Where("id = ?", id).Update("seen_version", version).Error
+}
+
+// ApplyUpstreamVersion updates a fork to a new version of its source, keeping
+// the fork's own secrets (NofxOS key, external source headers by name)
+func (s *StrategyStore) ApplyUpstreamVersion(fork *Strategy, upstreamConfig string, upstreamVersion int, note string) (*StrategyVersion, error) {
+	next, err := (&Strategy{Config: upstreamConfig}).ParseConfig()
+	if err != nil {
+		return nil, err
+	}
+	next.StripSecrets()
+	if current, err := fork.ParseConfig(); err == nil {
+		next.Indicators.NofxOSAPIKey = current.Indicators.NofxOSAPIKey
+		headers := make(map[string]map[string]string)
+		for _, src := range current.Indicators.ExternalDataSources {
+			headers[src.Name] = src.Headers
+		}
+		for i := range next.Indicators.ExternalDataSources {
+			next.Indicators.ExternalDataSources[i].Headers = headers[next.Indicators.ExternalDataSources[i].Name]
+		}
+		next.PublishConfig = current.PublishConfig
+	}
+
+	updated := *fork
+	if err := updated.SetConfig(next); err != nil {
+		return nil, err
+	}
+	version, err := s.UpdateWithVersion(&updated, StrategyVersionMeta{AuthorID: fork.UserID, Note: note})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.Model(&Strategy{}).Where("id = ?", fork.ID).Update("forked_from_version", upstreamVersion).Error; err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// GetListed gets a strategy listed on the market, whether or not its config is visible
+func (s *StrategyStore) GetListed(strategyID string) (*Strategy, error) {
+	var st Strategy
+	if err := s.db.Where("id = ? AND is_public = ?", strategyID, true).First(&st).Error; err != nil {
+		return nil, ErrStrategyNotPublic
+	}
+	return &st, nil
+}
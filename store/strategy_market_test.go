@@ -0,0 +1,154 @@
+package store
+
+import (
+	"testing"
+)
+
+func TestStrategyForkStripsSecretsAndTracksLineage(t *testing.T) {
+	st := newTestStore(t).Strategy()
+
+	source := &Strategy{ID: "src", UserID: "author", Name: "Momentum", ConfigVisible: true,
+		Config: `{"strategy_type":"ai_trading","ai_config":{"indicators":{"nofxos_api_key":"secret"}}}`}
+	if err := st.CreateWithVersion(source, StrategyVersionMeta{}); err != nil {
+		t.Fatalf("create source: %v", err)
+	}
+	if _, err := st.Fork("other", "src", "f1", "", ""); err != ErrStrategyNotPublic {
+		t.Fatalf("expected private strategy to be unforkable, got %v", err)
+	}
+	if _, err := st.Publish("author", "src"); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	fork, err := st.Fork("other", "src", "f1", "", SubscriptionModeNotify)
+	if err != nil {
+		t.Fatalf("fork: %v", err)
+	}
+	if fork.ForkedFromID != "src" || fork.ForkedFromVersion != 1 || fork.Name != "Momentum (fork)" {
+		t.Fatalf("unexpected fork lineage: %+v", fork)
+	}
+	config, _ := fork.ParseConfig()
+	if config.Indicators.NofxOSAPIKey != "" {
+		t.Fatalf("expected secrets stripped from fork")
+	}
+
+	second, err := st.Fork("other", "f1", "f2", "Mine", "")
+	if err != nil {
+		t.Fatalf("fork own fork: %v", err)
+	}
+	lineage := st.Lineage(second)
+	if len(lineage) != 2 || lineage[0].ID != "f1" || lineage[1].ID != "src" {
+		t.Fatalf("expected lineage f1 -> src, got %d entries", len(lineage))
+	}
+
+	stats, err := st.MarketStats([]string{"src"})
+	if err != nil || stats["src"].Forks != 1 {
+		t.Fatalf("expected 1 fork of src, got %+v err=%v", stats["src"], err)
+	}
+}
+
+func TestStrategySubscriptionAutoUpdateKeepsForkSecrets(t *testing.T) {
+	st := newTestStore(t).Strategy()
+
+	source := &Strategy{ID: "src", UserID: "author", Name: "Grid", ConfigVisible: true,
+		Config: `{"strategy_type":"ai_trading","ai_config":{"risk_control":{"max_positions":2}}}`}
+	if err := st.CreateWithVersion(source, StrategyVersionMeta{}); err != nil {
+		t.Fatalf("create source: %v", err)
+	}
+	if _, err := st.Publish("author", "src"); err != nil {
+		t.Fatalf("publish v1: %v", err)
+	}
+	fork, err := st.Fork("other", "src", "f1", "", SubscriptionModeAutoUpdate)
+	if err != nil {
+		t.Fatalf("fork: %v", err)
+	}
+	forkConfig, _ := fork.ParseConfig()
+	forkConfig.Indicators.NofxOSAPIKey = "fork-key"
+	fork.SetConfig(forkConfig)
+	if _, err := st.UpdateWithVersion(fork, StrategyVersionMeta{}); err != nil {
+		t.Fatalf("set fork key: %v", err)
+	}
+
+	source.Config = `{"strategy_type":"ai_trading","ai_config":{"risk_control":{"max_positions":4}}}`
+	if _, err := st.UpdateWithVersion(source, StrategyVersionMeta{}); err != nil {
+		t.Fatalf("edit source: %v", err)
+	}
+	if subs, _ := st.PendingSubscriptions("src", 2); len(subs) != 1 {
+		t.Fatalf("expected one pending subscription, got %d", len(subs))
+	}
+	published, err := st.Publish("author", "src")
+	if err != nil || published.PublishedVersion != 2 {
+		t.Fatalf("publish v2: %+v err=%v", published, err)
+	}
+	_, _, upstream, version := st.GetPublishedConfig(published)
+	if _, err := st.ApplyUpstreamVersion(fork, upstream, version, "auto-update"); err != nil {
+		t.Fatalf("apply upstream: %v", err)
+	}
+
+	updated, _ := st.Get("other", "f1")
+	config, _ := updated.ParseConfig()
+	if config.RiskControl.MaxPositions != 4 || config.Indicators.NofxOSAPIKey != "fork-key" {
+		t.Fatalf("expected upstream risk settings with fork secret kept, got %+v", config.RiskControl)
+	}
+	if updated.ForkedFromVersion != 2 {
+		t.Fatalf("expected fork to track upstream v2, got %d", updated.ForkedFromVersion)
+	}
+}
+
+func TestStrategyPublishLegacyRecordsOneBaseline(t *testing.T) {
+	st := newTestStore(t).Strategy()
+
+	// Saved before versioning existed: no history, current_version 0
+	legacy := &Strategy{ID: "legacy", UserID: "author", Name: "Old", Config: `{"strategy_type":"ai_trading"}`}
+	if err := st.db.Create(legacy).Error; err != nil {
+		t.Fatalf("seed legacy strategy: %v", err)
+	}
+	published, err := st.Publish("author", "legacy")
+	if err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	versions, err := st.ListVersions("author", "legacy")
+	if err != nil || len(versions) != 1 || versions[0].Note != "baseline" {
+		t.Fatalf("expected a single baseline version, got %d err=%v", len(versions), err)
+	}
+	if published.PublishedVersion != 1 || published.CurrentVersion != 1 {
+		t.Fatalf("expected v1 published, got published=%d current=%d", published.PublishedVersion, published.CurrentVersion)
+	}
+}
+
+func TestStrategyRatings(t *testing.T) {
+	st := newTestStore(t).Strategy()
+
+	source := &Strategy{ID: "src", UserID: "author", Name: "Rated", Config: `{}`}
+	if err := st.CreateWithVersion(source, StrategyVersionMeta{}); err != nil {
+		t.Fatalf("create source: %v", err)
+	}
+	if err := st.Rate("u1", "src", 5, ""); err != ErrStrategyNotPublic {
+		t.Fatalf("expected rating an unlisted strategy to fail, got %v", err)
+	}
+	if _, err := st.Publish("author", "src"); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if err := st.Rate("author", "src", 5, ""); err == nil {
+		t.Fatalf("expected self-rating to be rejected")
+	}
+	if err := st.Rate("u1", "src", 2, ""); err != nil {
+		t.Fatalf("rate: %v", err)
+	}
+	if err := st.Rate("u1", "src", 4, "better after v2"); err != nil {
+		t.Fatalf("re-rate: %v", err)
+	}
+	if err := st.Rate("u2", "src", 5, ""); err != nil {
+		t.Fatalf("rate: %v", err)
+	}
+	if err := st.Star("u1", "src", true); err != nil {
+		t.Fatalf("star: %v", err)
+	}
+
+	stats, err := st.MarketStats([]string{"src"})
+	if err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	if s := stats["src"]; s.RatingCount != 2 || s.RatingAvg != 4.5 || s.Stars != 1 {
+		t.Fatalf("unexpected market stats: %+v", s)
+	}
+}
@@ -0,0 +1,222 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrStrategyVersionNotFound is returned when a requested version does not exist
+var ErrStrategyVersionNotFound = errors.New("strategy version not found")
+
+// StrategyVersion an immutable snapshot of a strategy taken on every save.
+// Versions are numbered per strategy starting at 1 and are never updated;
+// a rollback records a new version carrying the old config.
+type StrategyVersion struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	StrategyID  string    `gorm:"column:strategy_id;not null;uniqueIndex:idx_strategy_versions_strategy_version" json:"strategy_id"`
+	Version     int       `gorm:"column:version;not null;uniqueIndex:idx_strategy_versions_strategy_version" json:"version"`
+	UserID      string    `gorm:"column:user_id;not null;default:'';index" json:"user_id"`
+	AuthorID    string    `gorm:"column:author_id;not null;default:''" json:"author_id"`
+	Note        string    `gorm:"column:note;default:''" json:"note"`
+	Name        string    `gorm:"column:name;not null;default:''" json:"name"`
+	Description string    `gorm:"column:description;default:''" json:"description"`
+	Config      string    `gorm:"column:config;not null;default:'{}'" json:"config"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (StrategyVersion) TableName() string { return "strategy_versions" }
+
+// StrategyVersionMeta who saved a version and why
+type StrategyVersionMeta struct {
+	AuthorID string
+	Note     string
+}
+
+// ParseConfig parse the versioned strategy configuration JSON
+func (v *StrategyVersion) ParseConfig() (*StrategyConfig, error) {
+	st := Strategy{Config: v.Config}
+	return st.ParseConfig()
+}
+
+// CreateWithVersion creates a strategy and records it as version 1
+func (s *StrategyStore) CreateWithVersion(strategy *Strategy, meta StrategyVersionMeta) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		strategy.CurrentVersion = 1
+		if err := tx.Create(strategy).Error; err != nil {
+			return err
+		}
+		_, err := appendStrategyVersion(tx, strategy, meta)
+		return err
+	})
+}
+
+// UpdateWithVersion saves the strategy and records the result as a new version.
+// Strategies created before versioning get their previous state recorded as a
+// baseline version first, so the edit can always be rolled back.
+func (s *StrategyStore) UpdateWithVersion(strategy *Strategy, meta StrategyVersionMeta) (*StrategyVersion, error) {
+	var version *StrategyVersion
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var existing Strategy
+		if err := tx.Where("id = ? AND user_id = ?", strategy.ID, strategy.UserID).First(&existing).Error; err != nil {
+			return err
+		}
+		if err := ensureBaselineStrategyVersion(tx, &existing); err != nil {
+			return err
+		}
+		var err error
+		if version, err = appendStrategyVersion(tx, strategy, meta); err != nil {
+			return err
+		}
+		return tx.Model(&Strategy{}).
+			Where("id = ? AND user_id = ?", strategy.ID, strategy.UserID).
+			Updates(map[string]interface{}{
+				"name":            strategy.Name,
+				"description":     strategy.Description,
+				"config":          strategy.Config,
+				"is_public":       strategy.IsPublic,
+				"config_visible":  strategy.ConfigVisible,
+				"current_version": version.Version,
+				"updated_at":      time.Now().UTC(),
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	strategy.CurrentVersion = version.Version
+	return version, nil
+}
+
+// Rollback restores the name, description and config of an earlier version.
+// History stays linear: the restored state is recorded as a new version.
+func (s *StrategyStore) Rollback(userID, strategyID string, toVersion int, meta StrategyVersionMeta) (*StrategyVersion, error) {
+	target, err := s.GetVersion(userID, strategyID, toVersion)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.Get(userID, strategyID)
+	if err != nil {
+		return nil, err
+	}
+	if current.IsDefault {
+		return nil, fmt.Errorf("cannot modify system default strategy")
+	}
+	if meta.Note == "" {
+		meta.Note = fmt.Sprintf("rollback to v%d", toVersion)
+	}
+	// Versions saved before a limit was tightened are clamped like any save
+	var config StrategyConfig
+	if err := json.Unmarshal([]byte(target.Config), &config); err != nil {
+		return nil, fmt.Errorf("strategy %s v%d has an invalid config: %w", strategyID, toVersion, err)
+	}
+	config.ClampLimits()
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	restored := &Strategy{
+		ID:            current.ID,
+		UserID:        current.UserID,
+		Name:          target.Name,
+		Description:   target.Description,
+		Config:        string(configJSON),
+		IsPublic:      current.IsPublic,
+		ConfigVisible: current.ConfigVisible,
+	}
+	return s.UpdateWithVersion(restored, meta)
+}
+
+// ListVersions lists a strategy's versions, newest first
+func (s *StrategyStore) ListVersions(userID, strategyID string) ([]*StrategyVersion, error) {
+	if _, err := s.Get(userID, strategyID); err != nil {
+		return nil, err
+	}
+	var versions []*StrategyVersion
+	err := s.db.Where("strategy_id = ?", strategyID).
+		Order("version DESC").
+		Find(&versions).Error
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// GetVersion gets one version of a strategy visible to the user
+func (s *StrategyStore) GetVersion(userID, strategyID string, version int) (*StrategyVersion, error) {
+	if _, err := s.Get(userID, strategyID); err != nil {
+		return nil, err
+	}
+	var v StrategyVersion
+	err := s.db.Where("strategy_id = ? AND version = ?", strategyID, version).First(&v).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrStrategyVersionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// GetAtVersion returns the strategy as it was at the given version, or the
+// live strategy when version is 0. Used for traders pinned to a version.
+func (s *StrategyStore) GetAtVersion(userID, strategyID string, version int) (*Strategy, error) {
+	st, err := s.Get(userID, strategyID)
+	if err != nil || version <= 0 {
+		return st, err
+	}
+	v, err := s.GetVersion(userID, strategyID, version)
+	if err != nil {
+		return nil, fmt.Errorf("strategy %s v%d: %w", strategyID, version, err)
+	}
+	pinned := *st
+	pinned.Name = v.Name
+	pinned.Description = v.Description
+	pinned.Config = v.Config
+	pinned.CurrentVersion = v.Version
+	return &pinned, nil
+}
+
+// ensureBaselineStrategyVersion records the current state of a strategy that
+// has no version history yet.
+func ensureBaselineStrategyVersion(tx *gorm.DB, existing *Strategy) error {
+	var count int64
+	if err := tx.Model(&StrategyVersion{}).Where("strategy_id = ?", existing.ID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	_, err := appendStrategyVersion(tx, existing, StrategyVersionMeta{AuthorID: existing.UserID, Note: "baseline"})
+	return err
+}
+
+func appendStrategyVersion(tx *gorm.DB, strategy *Strategy, meta StrategyVersionMeta) (*StrategyVersion, error) {
+	var latest int
+	if err := tx.Model(&StrategyVersion{}).
+		Where("strategy_id = ?", strategy.ID).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&latest).Error; err != nil {
+		return nil, err
+	}
+	author := meta.AuthorID
+	if author == "" {
+		author = strategy.UserID
+	}
+	version := &StrategyVersion{
+		StrategyID:  strategy.ID,
+		Version:     latest + 1,
+		UserID:      strategy.UserID,
+		AuthorID:    author,
+		Note:        meta.Note,
+		Name:        strategy.Name,
+		Description: strategy.Description,
+		Config:      strategy.Config,
+	}
+	if err := tx.Create(version).Error; err != nil {
+		return nil, fmt.Errorf("failed to record strategy version: %w", err)
+	}
+	return version, nil
+}
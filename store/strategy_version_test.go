@@ -0,0 +1,90 @@
+package store
+
+import (
+	"testing"
+)
+
+func TestStrategyVersionHistoryAndRollback(t *testing.T) {
+	st := newTestStore(t).Strategy()
+
+	strategy := &Strategy{ID: "s1", UserID: "u1", Name: "Trend", Config: `{"risk_control":{"max_positions":3}}`}
+	if err := st.CreateWithVersion(strategy, StrategyVersionMeta{AuthorID: "u1", Note: "created"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if strategy.CurrentVersion != 1 {
+		t.Fatalf("expected version 1 on create, got %d", strategy.CurrentVersion)
+	}
+
+	edited := &Strategy{ID: "s1", UserID: "u1", Name: "Trend", Config: `{"risk_control":{"max_positions":5}}`}
+	v2, err := st.UpdateWithVersion(edited, StrategyVersionMeta{AuthorID: "u2", Note: "more positions"})
+	if err != nil || v2.Version != 2 || v2.AuthorID != "u2" {
+		t.Fatalf("update: version=%+v err=%v", v2, err)
+	}
+
+	v3, err := st.Rollback("u1", "s1", 1, StrategyVersionMeta{AuthorID: "u1"})
+	if err != nil || v3.Version != 3 || v3.Note != "rollback to v1" {
+		t.Fatalf("rollback: version=%+v err=%v", v3, err)
+	}
+	current, err := st.Get("u1", "s1")
+	if err != nil || current.CurrentVersion != 3 {
+		t.Fatalf("expected v1 restored as v3, got %+v err=%v", current, err)
+	}
+	if restored, err := current.ParseConfig(); err != nil || restored.RiskControl.MaxPositions != 3 {
+		t.Fatalf("expected v1 config restored, got %+v err=%v", restored, err)
+	}
+
+	versions, err := st.ListVersions("u1", "s1")
+	if err != nil || len(versions) != 3 || versions[0].Version != 3 {
+		t.Fatalf("expected 3 versions newest first, got %d err=%v", len(versions), err)
+	}
+
+	pinned, err := st.GetAtVersion("u1", "s1", 2)
+	if err != nil || pinned.Config != `{"risk_control":{"max_positions":5}}` || pinned.CurrentVersion != 2 {
+		t.Fatalf("expected pinned v2 config, got %+v err=%v", pinned, err)
+	}
+	if _, err := st.GetVersion("u2", "s1", 1); err == nil {
+		t.Fatalf("expected versions of another user's strategy to be hidden")
+	}
+}
+
+func TestStrategyRollbackClampsLimits(t *testing.T) {
+	st := newTestStore(t).Strategy()
+
+	// Saved before the position limit was introduced
+	legacy := &Strategy{ID: "s1", UserID: "u1", Name: "Wide", Config: `{"risk_control":{"max_positions":20}}`}
+	if err := st.CreateWithVersion(legacy, StrategyVersionMeta{}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := st.UpdateWithVersion(&Strategy{ID: "s1", UserID: "u1", Name: "Wide", Config: `{"risk_control":{"max_positions":4}}`}, StrategyVersionMeta{}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if _, err := st.Rollback("u1", "s1", 1, StrategyVersionMeta{}); err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+	current, err := st.Get("u1", "s1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if restored, err := current.ParseConfig(); err != nil || restored.RiskControl.MaxPositions != MaxPositions {
+		t.Fatalf("expected max positions clamped to %d, got %+v err=%v", MaxPositions, restored, err)
+	}
+}
+
+func TestStrategyUpdateRecordsBaselineForLegacyStrategy(t *testing.T) {
+	st := newTestStore(t).Strategy()
+
+	// Saved before versioning existed: no history, current_version 0
+	legacy := &Strategy{ID: "legacy", UserID: "u1", Name: "Old", Config: `{"a":1}`}
+	if err := st.db.Create(legacy).Error; err != nil {
+		t.Fatalf("seed legacy strategy: %v", err)
+	}
+
+	v, err := st.UpdateWithVersion(&Strategy{ID: "legacy", UserID: "u1", Name: "Old", Config: `{"a":2}`}, StrategyVersionMeta{})
+	if err != nil || v.Version != 2 {
+		t.Fatalf("expected edit recorded as v2 after baseline, got %+v err=%v", v, err)
+	}
+	baseline, err := st.GetVersion("u1", "legacy", 1)
+	if err != nil || baseline.Config != `{"a":1}` || baseline.Note != "baseline" {
+		t.Fatalf("expected baseline v1 with original config, got %+v err=%v", baseline, err)
+	}
+}
@@ -0,0 +1,18 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// newTestStore opens a fully migrated SQLite store in a temporary directory,
+// the same way the server opens its database
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	st, err := New(filepath.Join(t.TempDir(), "nofx.db"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { st.Close() })
+	return st
+}
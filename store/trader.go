@@ -2,6 +2,7 @@ package store
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -25,6 +26,8 @@ type Trader struct {
 	AIModelID           string    `gorm:"column:ai_model_id;not null" json:"ai_model_id"`
 	ExchangeID          string    `gorm:"column:exchange_id;not null" json:"exchange_id"`
 	StrategyID          string    `gorm:"column:strategy_id;default:''" json:"strategy_id"`
+	StrategyVersion     int       `gorm:"column:strategy_version;default:0" json:"strategy_version"`            // pinned strategy version, 0 = follow latest
+	FallbackAIModelIDs  string    `gorm:"column:fallback_ai_model_ids;default:''" json:"fallback_ai_model_ids"` // comma-separated AI model IDs tried in order when the primary fails
 	InitialBalance      float64   `gorm:"column:initial_balance;not null" json:"initial_balance"`
 	ScanIntervalMinutes int       `gorm:"column:scan_interval_minutes;default:15" json:"scan_interval_minutes"`
 	IsRunning           bool      `gorm:"column:is_running;default:false" json:"is_running"`
@@ -142,6 +145,31 @@ func (s *TraderStore) Update(trader *Trader) error {
 		Updates(updates).Error
 }
 
+// UpdateStrategyVersion pins the trader to a strategy version (0 = follow latest)
+func (s *TraderStore) UpdateStrategyVersion(userID, id string, version int) error {
+	return s.db.Model(&Trader{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("strategy_version", version).Error
+}
+
+// UpdateFallbackAIModels sets the ordered AI model failover chain of a trader
+func (s *TraderStore) UpdateFallbackAIModels(userID, id string, modelIDs []string) error {
+	return s.db.Model(&Trader{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("fallback_ai_model_ids", strings.Join(modelIDs, ",")).Error
+}
+
+// FallbackIDs returns the ordered fallback AI model IDs
+func (t *Trader) FallbackIDs() []string {
+	var ids []string
+	for _, id := range strings.Split(t.FallbackAIModelIDs, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
 // UpdateInitialBalance updates initial balance
 func (s *TraderStore) UpdateInitialBalance(userID, id string, newBalance float64) error {
 	return s.db.Model(&Trader{}).
@@ -161,8 +189,9 @@ func (s *TraderStore) UpdateCustomPrompt(userID, id string, customPrompt string,
 
 // Delete deletes trader and associated data
 func (s *TraderStore) Delete(userID, id string) error {
-	// Delete associated equity snapshots first
+	// Delete associated equity snapshots and breaker state first
 	s.db.Where("trader_id = ?", id).Delete(&EquitySnapshot{})
+	s.db.Where("trader_id = ?", id).Delete(&TraderBreakerState{})
 
 	// Delete the trader
 	return s.db.Where("id = ? AND user_id = ?", id, userID).Delete(&Trader{}).Error
@@ -276,3 +305,14 @@ func (s *TraderStore) ListByAIModelID(userID, aiModelID string) ([]*Trader, erro
 	}
 	return traders, nil
 }
+
+// ListPublicByStrategyID gets the traders of any user running a strategy that
+// are shown in the competition (used for marketplace performance)
+func (s *TraderStore) ListPublicByStrategyID(strategyID string) ([]*Trader, error) {
+	var traders []*Trader
+	err := s.db.Where("strategy_id = ? AND show_in_competition = ?", strategyID, true).Find(&traders).Error
+	if err != nil {
+		return nil, err
+	}
+	return traders, nil
+}
@@ -0,0 +1,339 @@
+package store
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Workspace roles. A workspace shares the owner's resources (exchange accounts,
+// AI models, traders, strategies) with its members; the role decides what a
+// member may do with them.
+const (
+	WorkspaceRoleOwner    = "owner"    // holds the exchange accounts, full control
+	WorkspaceRoleOperator = "operator" // start/stop traders, edit strategies
+	WorkspaceRoleViewer   = "viewer"   // read-only dashboards
+)
+
+// workspaceInvitationTTL is how long an invitation token stays redeemable.
+const workspaceInvitationTTL = 7 * 24 * time.Hour
+
+var (
+	// ErrInvitationNotFound is returned for unknown tokens.
+	ErrInvitationNotFound = errors.New("invitation not found")
+	// ErrInvitationExpired is returned when the token is past its expiry or already used.
+	ErrInvitationExpired = errors.New("invitation expired or already used")
+	// ErrLastOwner prevents a workspace from being left without an owner.
+	ErrLastOwner = errors.New("workspace must keep at least one owner")
+)
+
+// IsValidWorkspaceRole reports whether role is one of the known workspace roles.
+func IsValidWorkspaceRole(role string) bool {
+	switch role {
+	case WorkspaceRoleOwner, WorkspaceRoleOperator, WorkspaceRoleViewer:
+		return true
+	}
+	return false
+}
+
+// WorkspaceRoleRank orders roles so permission checks can compare them:
+// viewer < operator < owner. Unknown roles rank below viewer.
+func WorkspaceRoleRank(role string) int {
+	switch role {
+	case WorkspaceRoleOwner:
+		return 3
+	case WorkspaceRoleOperator:
+		return 2
+	case WorkspaceRoleViewer:
+		return 1
+	}
+	return 0
+}
+
+// Workspace a shared team space backed by the owner's resources
+type Workspace struct {
+	ID        string    `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"column:name;not null" json:"name"`
+	OwnerID   string    `gorm:"column:owner_id;not null;index" json:"owner_id"` // user whose resources the workspace operates on
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (Workspace) TableName() string { return "workspaces" }
+
+// WorkspaceMember membership of a user in a workspace
+type WorkspaceMember struct {
+	WorkspaceID string    `gorm:"column:workspace_id;primaryKey" json:"workspace_id"`
+	UserID      string    `gorm:"column:user_id;primaryKey;index" json:"user_id"`
+	Role        string    `gorm:"column:role;not null" json:"role"`
+	InvitedBy   string    `gorm:"column:invited_by;default:''" json:"invited_by,omitempty"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (WorkspaceMember) TableName() string { return "workspace_members" }
+
+// WorkspaceInvitation a pending invitation, redeemed with its token
+type WorkspaceInvitation struct {
+	ID          string     `gorm:"primaryKey" json:"id"`
+	WorkspaceID string     `gorm:"column:workspace_id;not null;index" json:"workspace_id"`
+	Email       string     `gorm:"column:email;not null" json:"email"`
+	Role        string     `gorm:"column:role;not null" json:"role"`
+	Token       string     `gorm:"column:token;not null;uniqueIndex:idx_workspace_invitations_token" json:"-"`
+	InvitedBy   string     `gorm:"column:invited_by;not null" json:"invited_by"`
+	ExpiresAt   time.Time  `gorm:"column:expires_at;not null" json:"expires_at"`
+	AcceptedAt  *time.Time `gorm:"column:accepted_at" json:"accepted_at,omitempty"`
+	AcceptedBy  string     `gorm:"column:accepted_by;default:''" json:"accepted_by,omitempty"`
+	CreatedAt   time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (WorkspaceInvitation) TableName() string { return "workspace_invitations" }
+
+// WorkspaceMembership a workspace together with the caller's role in it
+type WorkspaceMembership struct {
+	Workspace
+	Role string `json:"role"`
+}
+
+// WorkspaceStore workspace, membership and invitation storage
+type WorkspaceStore struct {
+	db *gorm.DB
+}
+
+// NewWorkspaceStore creates a new WorkspaceStore
+func NewWorkspaceStore(db *gorm.DB) *WorkspaceStore {
+	return &WorkspaceStore{db: db}
+}
+
+func (s *WorkspaceStore) initTables() error {
+	return s.db.AutoMigrate(&Workspace{}, &WorkspaceMember{}, &WorkspaceInvitation{})
+}
+
+// Create creates a workspace and registers the owner as its first member
+func (s *WorkspaceStore) Create(ws *Workspace) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(ws).Error; err != nil {
+			return err
+		}
+		return tx.Create(&WorkspaceMember{
+			WorkspaceID: ws.ID,
+			UserID:      ws.OwnerID,
+			Role:        WorkspaceRoleOwner,
+		}).Error
+	})
+}
+
+// Get gets a workspace by ID
+func (s *WorkspaceStore) Get(id string) (*Workspace, error) {
+	var ws Workspace
+	if err := s.db.Where("id = ?", id).First(&ws).Error; err != nil {
+		return nil, err
+	}
+	return &ws, nil
+}
+
+// Delete removes a workspace with its members and invitations. The owner's
+// resources are untouched.
+func (s *WorkspaceStore) Delete(id string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("workspace_id = ?", id).Delete(&WorkspaceInvitation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("workspace_id = ?", id).Delete(&WorkspaceMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&Workspace{}).Error
+	})
+}
+
+// ListForUser lists the workspaces a user belongs to, with their role in each
+func (s *WorkspaceStore) ListForUser(userID string) ([]WorkspaceMembership, error) {
+	var rows []WorkspaceMembership
+	err := s.db.Table("workspaces").
+		Select("workspaces.*, workspace_members.role").
+		Joins("JOIN workspace_members ON workspace_members.workspace_id = workspaces.id").
+		Where("workspace_members.user_id = ?", userID).
+		Order("workspaces.created_at").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// GetMember gets a user's membership in a workspace
+func (s *WorkspaceStore) GetMember(workspaceID, userID string) (*WorkspaceMember, error) {
+	var m WorkspaceMember
+	if err := s.db.Where("workspace_id = ? AND user_id = ?", workspaceID, userID).First(&m).Error; err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// ListMembers lists all members of a workspace
+func (s *WorkspaceStore) ListMembers(workspaceID string) ([]WorkspaceMember, error) {
+	var members []WorkspaceMember
+	err := s.db.Where("workspace_id = ?", workspaceID).Order("created_at").Find(&members).Error
+	return members, err
+}
+
+// UpdateMemberRole changes a member's role. Demoting the last owner is refused.
+func (s *WorkspaceStore) UpdateMemberRole(workspaceID, userID, role string) error {
+	if !IsValidWorkspaceRole(role) {
+		return fmt.Errorf("invalid role: %s", role)
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var m WorkspaceMember
+		if err := tx.Where("workspace_id = ? AND user_id = ?", workspaceID, userID).First(&m).Error; err != nil {
+			return err
+		}
+		if m.Role == WorkspaceRoleOwner && role != WorkspaceRoleOwner {
+			if err := ensureAnotherOwner(tx, workspaceID, userID); err != nil {
+				return err
+			}
+		}
+		return tx.Model(&WorkspaceMember{}).
+			Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+			Update("role", role).Error
+	})
+}
+
+// RemoveMember removes a member from a workspace. Removing the last owner is refused.
+func (s *WorkspaceStore) RemoveMember(workspaceID, userID string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var m WorkspaceMember
+		if err := tx.Where("workspace_id = ? AND user_id = ?", workspaceID, userID).First(&m).Error; err != nil {
+			return err
+		}
+		if m.Role == WorkspaceRoleOwner {
+			if err := ensureAnotherOwner(tx, workspaceID, userID); err != nil {
+				return err
+			}
+		}
+		return tx.Where("workspace_id = ? AND user_id = ?", workspaceID, userID).Delete(&WorkspaceMember{}).Error
+	})
+}
+
+func ensureAnotherOwner(tx *gorm.DB, workspaceID, exceptUserID string) error {
+	var owners int64
+	if err := tx.Model(&WorkspaceMember{}).
+		Where("workspace_id = ? AND role = ? AND user_id <> ?", workspaceID, WorkspaceRoleOwner, exceptUserID).
+		Count(&owners).Error; err != nil {
+		return err
+	}
+	if owners == 0 {
+		return ErrLastOwner
+	}
+	return nil
+}
+
+// CreateInvitation creates an invitation with a fresh random token
+func (s *WorkspaceStore) CreateInvitation(inv *WorkspaceInvitation) error {
+	if !IsValidWorkspaceRole(inv.Role) {
+		return fmt.Errorf("invalid role: %s", inv.Role)
+	}
+	token, err := newInvitationToken()
+	if err != nil {
+		return err
+	}
+	inv.Token = token
+	inv.Email = strings.ToLower(strings.TrimSpace(inv.Email))
+	if inv.ExpiresAt.IsZero() {
+		inv.ExpiresAt = time.Now().UTC().Add(workspaceInvitationTTL)
+	}
+	return s.db.Create(inv).Error
+}
+
+// ListInvitations lists pending (unaccepted, unexpired) invitations of a workspace
+func (s *WorkspaceStore) ListInvitations(workspaceID string) ([]WorkspaceInvitation, error) {
+	var invs []WorkspaceInvitation
+	err := s.db.Where("workspace_id = ? AND accepted_at IS NULL AND expires_at > ?", workspaceID, time.Now().UTC()).
+		Order("created_at DESC").
+		Find(&invs).Error
+	return invs, err
+}
+
+// RevokeInvitation deletes a pending invitation
+func (s *WorkspaceStore) RevokeInvitation(workspaceID, invitationID string) error {
+	result := s.db.Where("workspace_id = ? AND id = ? AND accepted_at IS NULL", workspaceID, invitationID).
+		Delete(&WorkspaceInvitation{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInvitationNotFound
+	}
+	return nil
+}
+
+// GetInvitationByToken looks up a redeemable invitation by token
+func (s *WorkspaceStore) GetInvitationByToken(token string) (*WorkspaceInvitation, error) {
+	var inv WorkspaceInvitation
+	if err := s.db.Where("token = ?", token).First(&inv).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvitationNotFound
+		}
+		return nil, err
+	}
+	if inv.AcceptedAt != nil || time.Now().UTC().After(inv.ExpiresAt) {
+		return nil, ErrInvitationExpired
+	}
+	return &inv, nil
+}
+
+// AcceptInvitation redeems an invitation for userID: the user becomes a member
+// with the invited role (an existing membership keeps the higher of the two).
+func (s *WorkspaceStore) AcceptInvitation(token, userID string) (*WorkspaceInvitation, error) {
+	inv, err := s.GetInvitationByToken(token)
+	if err != nil {
+		return nil, err
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UTC()
+		result := tx.Model(&WorkspaceInvitation{}).
+			Where("id = ? AND accepted_at IS NULL", inv.ID).
+			Updates(map[string]interface{}{"accepted_at": now, "accepted_by": userID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvitationExpired
+		}
+
+		var existing WorkspaceMember
+		err := tx.Where("workspace_id = ? AND user_id = ?", inv.WorkspaceID, userID).First(&existing).Error
+		switch {
+		case err == nil:
+			if WorkspaceRoleRank(inv.Role) <= WorkspaceRoleRank(existing.Role) {
+				return nil
+			}
+			return tx.Model(&WorkspaceMember{}).
+				Where("workspace_id = ? AND user_id = ?", inv.WorkspaceID, userID).
+				Update("role", inv.Role).Error
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return tx.Create(&WorkspaceMember{
+				WorkspaceID: inv.WorkspaceID,
+				UserID:      userID,
+				Role:        inv.Role,
+				InvitedBy:   inv.InvitedBy,
+			}).Error
+		default:
+			return err
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	inv.AcceptedBy = userID
+	return inv, nil
+}
+
+func newInvitationToken() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate invitation token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,67 @@
+package store
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWorkspaceInvitationLifecycle(t *testing.T) {
+	st := newTestStore(t).Workspace()
+	if err := st.Create(&Workspace{ID: "ws-1", Name: "Desk", OwnerID: "owner"}); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+
+	inv := &WorkspaceInvitation{ID: "inv-1", WorkspaceID: "ws-1", Email: " Op@Example.com ", Role: WorkspaceRoleOperator, InvitedBy: "owner"}
+	if err := st.CreateInvitation(inv); err != nil {
+		t.Fatalf("create invitation: %v", err)
+	}
+	if inv.Token == "" || inv.Email != "op@example.com" {
+		t.Fatalf("expected token and normalized email, got %+v", inv)
+	}
+
+	if _, err := st.AcceptInvitation(inv.Token, "op"); err != nil {
+		t.Fatalf("accept invitation: %v", err)
+	}
+	member, err := st.GetMember("ws-1", "op")
+	if err != nil || member.Role != WorkspaceRoleOperator {
+		t.Fatalf("expected operator membership, got %+v err=%v", member, err)
+	}
+
+	if _, err := st.AcceptInvitation(inv.Token, "someone-else"); !errors.Is(err, ErrInvitationExpired) {
+		t.Fatalf("expected reused token to be rejected, got %v", err)
+	}
+
+	memberships, err := st.ListForUser("op")
+	if err != nil || len(memberships) != 1 || memberships[0].Role != WorkspaceRoleOperator || memberships[0].Name != "Desk" {
+		t.Fatalf("unexpected memberships %+v err=%v", memberships, err)
+	}
+}
+
+func TestWorkspaceInvitationExpired(t *testing.T) {
+	st := newTestStore(t).Workspace()
+	if err := st.Create(&Workspace{ID: "ws-1", Name: "Desk", OwnerID: "owner"}); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	inv := &WorkspaceInvitation{ID: "inv-1", WorkspaceID: "ws-1", Email: "v@example.com", Role: WorkspaceRoleViewer,
+		InvitedBy: "owner", ExpiresAt: time.Now().UTC().Add(-time.Minute)}
+	if err := st.CreateInvitation(inv); err != nil {
+		t.Fatalf("create invitation: %v", err)
+	}
+	if _, err := st.AcceptInvitation(inv.Token, "v"); !errors.Is(err, ErrInvitationExpired) {
+		t.Fatalf("expected expired invitation, got %v", err)
+	}
+}
+
+func TestWorkspaceKeepsLastOwner(t *testing.T) {
+	st := newTestStore(t).Workspace()
+	if err := st.Create(&Workspace{ID: "ws-1", Name: "Desk", OwnerID: "owner"}); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	if err := st.UpdateMemberRole("ws-1", "owner", WorkspaceRoleViewer); !errors.Is(err, ErrLastOwner) {
+		t.Fatalf("expected ErrLastOwner on demotion, got %v", err)
+	}
+	if err := st.RemoveMember("ws-1", "owner"); !errors.Is(err, ErrLastOwner) {
+		t.Fatalf("expected ErrLastOwner on removal, got %v", err)
+	}
+}
@@ -65,6 +65,10 @@ var botAPIAllowlist = []allowedRoute{
 	{"GET", regexp.MustCompile(`^/api/strategies/active$`)},
 	{"GET", regexp.MustCompile(`^/api/strategies/default-config$`)},
 	{"GET", regexp.MustCompile(`^/api/strategies/public$`)},
+	{"GET", regexp.MustCompile(`^/api/strategies/[^/]+/versions(/[0-9]+)?$`)},
+	{"GET", regexp.MustCompile(`^/api/strategies/[^/]+/diff$`)},
+	{"GET", regexp.MustCompile(`^/api/strategies/public/[^/]+$`)},
+	{"GET", regexp.MustCompile(`^/api/notifications$`)},
 	{"GET", regexp.MustCompile(`^/api/my-traders$`)},
 	{"GET", regexp.MustCompile(`^/api/traders$`)},
 	{"GET", regexp.MustCompile(`^/api/traders/[^/]+/config$`)},
@@ -110,6 +114,8 @@ var botAPIAllowlist = []allowedRoute{
 	{"DELETE", regexp.MustCompile(`^/api/strategies/[^/]+$`)},
 	{"POST", regexp.MustCompile(`^/api/strategies/[^/]+/activate$`)},
 	{"POST", regexp.MustCompile(`^/api/strategies/[^/]+/duplicate$`)},
+	{"POST", regexp.MustCompile(`^/api/strategies/[^/]+/rollback$`)},
+	{"POST", regexp.MustCompile(`^/api/strategies/[^/]+/fork$`)},
 }
 
 // isPathAllowed returns true when the (method, path) pair is in botAPIAllowlist.
@@ -172,6 +178,8 @@ func (t *apiCallTool) execute(req *apiRequest) string {
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Authorization", "Bearer "+t.token)
+	// Label the call in the server's audit log; the actor still comes from the JWT.
+	httpReq.Header.Set("X-NOFX-Source", "telegram_agent")
 
 	resp, err := t.client.Do(httpReq)
 	if err != nil {
@@ -45,7 +45,7 @@ func Start(cfg *config.Config, st *store.Store, reloadCh <-chan struct{}) {
 func resolveToken(cfg *config.Config, st *store.Store) string {
 	dbCfg, err := st.TelegramConfig().Get()
 	if err == nil && dbCfg.BotToken != "" {
-		return dbCfg.BotToken
+		return string(dbCfg.BotToken)
 	}
 	return ""
 }
@@ -59,6 +59,11 @@ func runBot(token string, cfg *config.Config, st *store.Store) bool {
 	}
 	logger.Infof("Telegram bot @%s started", bot.Self.UserName)
 
+	// Push in-app notifications (strategy updates, ...) to the bound chat.
+	notifyDone := make(chan struct{})
+	defer close(notifyDone)
+	go forwardNotifications(bot, st, notifyDone)
+
 	// Allowed chat ID: read from DB binding (0 = unbound, first /start will bind).
 	allowedChatID := int64(0)
 	if id, err := st.TelegramConfig().GetBoundChatID(); err == nil && id != 0 {
@@ -0,0 +1,59 @@
+package telegram
+
+import (
+	"nofx/logger"
+	"nofx/store"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	notifyPollInterval = 30 * time.Second
+	notifyMaxAge       = 24 * time.Hour // older undelivered notifications stay in-app only
+	notifyBatchSize    = 10
+)
+
+// forwardNotifications pushes the bot user's undelivered in-app notifications
+// to the bound chat until done is closed.
+func forwardNotifications(bot *tgbotapi.BotAPI, st *store.Store, done <-chan struct{}) {
+	ticker := time.NewTicker(notifyPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			deliverNotifications(bot, st)
+		}
+	}
+}
+
+func deliverNotifications(bot *tgbotapi.BotAPI, st *store.Store) {
+	chatID, err := st.TelegramConfig().GetBoundChatID()
+	if err != nil || chatID == 0 {
+		return
+	}
+	users, err := st.User().GetAll()
+	if err != nil || len(users) == 0 {
+		return
+	}
+	pending, err := st.Notification().ListUndelivered(users[0].ID, time.Now().UTC().Add(-notifyMaxAge), notifyBatchSize)
+	if err != nil {
+		logger.Warnf("Telegram: failed to load notifications: %v", err)
+		return
+	}
+	for _, n := range pending {
+		text := "🔔 " + n.Title
+		if n.Body != "" {
+			text += "\n" + n.Body
+		}
+		if _, err := bot.Send(tgbotapi.NewMessage(chatID, text)); err != nil {
+			logger.Warnf("Telegram: failed to deliver notification %d: %v", n.ID, err)
+			return
+		}
+		if err := st.Notification().MarkDelivered(n.ID); err != nil {
+			logger.Warnf("Telegram: failed to mark notification %d delivered: %v", n.ID, err)
+		}
+	}
+}
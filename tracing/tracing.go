@@ -0,0 +1,105 @@
+// Package tracing records OpenTelemetry spans across the decision cycle:
+// market data fetches, the AI request (with retries and x402 payment) and
+// every exchange call. Spans are only exported when an OTLP endpoint is
+// configured; otherwise the global no-op provider makes them free.
+package tracing
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+
+	"nofx/logger"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName is reported as service.name unless OTEL_SERVICE_NAME is set
+const ServiceName = "nofx"
+
+// Environment variables read by InitFromEnv. The OTLP exporter reads the rest
+// of the standard OTEL_EXPORTER_OTLP_* settings (headers, TLS, timeout) itself.
+const (
+	EnvEndpoint       = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	EnvTracesEndpoint = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+	EnvSDKDisabled    = "OTEL_SDK_DISABLED"
+	EnvServiceName    = "OTEL_SERVICE_NAME"
+)
+
+const instrumentationName = "nofx"
+
+// Tracer returns the nofx tracer from the global provider
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start starts a span; a nil ctx starts a root span
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on the span, if any, and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Install makes exp the destination of every span and returns the function
+// that flushes and stops it. sync exports each span as it ends, which is what
+// tests with an in-memory exporter want; production uses batching.
+func Install(exp sdktrace.SpanExporter, sync bool) func(context.Context) error {
+	processor := sdktrace.NewBatchSpanProcessor(exp)
+	if sync {
+		processor = sdktrace.NewSimpleSpanProcessor(exp)
+	}
+	name := strings.TrimSpace(os.Getenv(EnvServiceName))
+	if name == "" {
+		name = ServiceName
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(attribute.String("service.name", name)))
+	if err != nil {
+		res = resource.NewSchemaless(attribute.String("service.name", name))
+	}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(processor), sdktrace.WithResource(res))
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return tp.Shutdown
+}
+
+// Enabled reports whether the environment asks for span export
+func Enabled() bool {
+	if disabled, _ := strconv.ParseBool(os.Getenv(EnvSDKDisabled)); disabled {
+		return false
+	}
+	return strings.TrimSpace(os.Getenv(EnvEndpoint)) != "" || strings.TrimSpace(os.Getenv(EnvTracesEndpoint)) != ""
+}
+
+// InitFromEnv exports spans over OTLP/HTTP when an OTLP endpoint is set.
+// The returned shutdown function flushes pending spans; it is a no-op when
+// tracing is off.
+func InitFromEnv(ctx context.Context) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+	if !Enabled() {
+		return noop, nil
+	}
+	exp, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return noop, err
+	}
+	logger.Infof("🔭 OpenTelemetry tracing enabled")
+	return Install(exp, false), nil
+}
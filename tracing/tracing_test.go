@@ -0,0 +1,56 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSpansNestAndRecordErrors(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	shutdown := Install(exp, true)
+	defer shutdown(context.Background())
+
+	ctx, parent := Start(nil, "trader.cycle", attribute.String("trader_id", "t1"))
+	_, child := Start(ctx, "exchange.GetBalance")
+	End(child, errors.New("timeout"))
+	End(parent, nil)
+
+	spans := exp.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	c, p := spans[0], spans[1]
+	if c.Name != "exchange.GetBalance" || p.Name != "trader.cycle" {
+		t.Fatalf("unexpected span order: %s, %s", c.Name, p.Name)
+	}
+	if c.Parent.SpanID() != p.SpanContext.SpanID() {
+		t.Fatal("exchange span is not a child of the cycle span")
+	}
+	if c.Status.Code != codes.Error || len(c.Events) == 0 {
+		t.Fatalf("error not recorded: status=%v events=%d", c.Status.Code, len(c.Events))
+	}
+	if p.Status.Code == codes.Error {
+		t.Fatal("successful span marked as error")
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	t.Setenv(EnvEndpoint, "")
+	t.Setenv(EnvTracesEndpoint, "")
+	if Enabled() {
+		t.Fatal("enabled without an endpoint")
+	}
+	t.Setenv(EnvEndpoint, "http://localhost:4318")
+	if !Enabled() {
+		t.Fatal("not enabled with an endpoint")
+	}
+	t.Setenv(EnvSDKDisabled, "true")
+	if Enabled() {
+		t.Fatal("enabled with OTEL_SDK_DISABLED=true")
+	}
+}
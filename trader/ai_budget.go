@@ -0,0 +1,158 @@
+package trader
+
+import (
+	"fmt"
+	"time"
+
+	"nofx/mcp"
+	"nofx/metrics"
+	"nofx/store"
+)
+
+// aiBudgetGate checks the trader's and user's AI spend budgets before an AI
+// call. It returns the client to call, or a non-empty reason to skip the
+// call: at 100% of a budget the AI is not called at all; at
+// store.AIBudgetDegradeRatio the call goes to the cheapest model of the
+// failover chain, or is throttled to one call per degraded interval.
+// Budget lookup errors fail open so a database hiccup never stops trading.
+func (at *AutoTrader) aiBudgetGate() (mcp.AIClient, string) {
+	if at.store == nil {
+		return at.mcpClient, ""
+	}
+	status, err := at.store.AIBudget().Evaluate(at.userID, at.id)
+	if err != nil {
+		at.logWarnf("⚠️ AI budget check failed, continuing: %v", err)
+		return at.mcpClient, ""
+	}
+	at.aiBudgetMu.Lock()
+	at.aiBudgetStatus = status
+	lastCall := at.lastAICallAt
+	at.aiBudgetMu.Unlock()
+
+	switch status.Level {
+	case store.AIBudgetExhausted:
+		return nil, "AI budget exhausted: " + status.Reason
+
+	case store.AIBudgetDegraded:
+		if status.DegradeMode == store.AIBudgetDegradeModel {
+			if client, name := at.cheapestAIClient(); client != nil {
+				at.logWarnf("💸 AI budget degraded (%s), using cheaper model %s", status.Reason, name)
+				return client, ""
+			}
+		}
+		interval := time.Duration(status.DegradeIntervalMinutes) * time.Minute
+		if interval <= 0 {
+			interval = 2 * at.config.ScanInterval
+		}
+		if wait := interval - time.Since(lastCall); wait > 0 {
+			return nil, fmt.Sprintf("AI budget degraded (%s): AI throttled to one call every %v, next in %v",
+				status.Reason, interval, wait.Round(time.Minute))
+		}
+	}
+	return at.mcpClient, ""
+}
+
+// recordAICharge records the cost and token usage of the last AI call made
+// with client, attributed to the decision cycle being run.
+// Use the effective model name (custom model, e.g. "gpt-5.6") so the
+// price lookup matches what was actually invoked — at.aiModel is the
+// provider id (e.g. "claw402") and would fall back to the default price.
+// Cost precedence: the gateway-reported settled amount (upto scheme), then
+// the reported token usage at the user's price for the model, then the flat
+// per-call catalog estimate.
+// With a failover chain the charge goes to the model that answered.
+func (at *AutoTrader) recordAICharge(client mcp.AIClient) {
+	if at.store == nil {
+		return
+	}
+	chargeProvider, chargeModel, _ := at.answeredAIModel(client)
+	charge := &store.AICharge{
+		TraderID:    at.id,
+		UserID:      at.userID,
+		Model:       chargeModel,
+		Provider:    chargeProvider,
+		CycleNumber: at.cycleNumber + 1, // saveDecision advances cycleNumber after the call
+		CostUSD:     store.GetModelPrice(chargeModel),
+		CostSource:  store.AICostFlat,
+	}
+
+	var usage *mcp.TokenUsage
+	if r, ok := client.(interface{ LastCallTokenUsage() *mcp.TokenUsage }); ok {
+		usage = r.LastCallTokenUsage()
+	}
+	if usage != nil {
+		charge.PromptTokens = usage.PromptTokens
+		charge.CompletionTokens = usage.CompletionTokens
+		charge.CachedTokens = usage.CachedTokens
+		charge.CacheWriteTokens = usage.CacheWriteTokens
+		if price, ok := at.store.AICharge().ResolveTokenPrice(at.userID, chargeModel); ok {
+			charge.CostUSD = price.Cost(usage.PromptTokens, usage.CompletionTokens, usage.CachedTokens, usage.CacheWriteTokens)
+			charge.CostSource = store.AICostTokens
+		}
+	}
+	if r, ok := client.(interface{ LastCallCostUSD() (float64, bool) }); ok {
+		if actual, has := r.LastCallCostUSD(); has {
+			charge.CostUSD = actual
+			charge.CostSource = store.AICostSettled
+		}
+	}
+
+	if err := at.store.AICharge().RecordCharge(charge); err != nil {
+		at.logWarnf("⚠️ Failed to record AI charge: %v", err)
+	}
+	metrics.AddAISpend(at.id, charge.Provider, charge.CostUSD)
+}
+
+// markAICall records the time of an AI call for interval degradation
+func (at *AutoTrader) markAICall() {
+	at.aiBudgetMu.Lock()
+	at.lastAICallAt = time.Now()
+	at.aiBudgetMu.Unlock()
+}
+
+// cheapestAIClient returns the failover chain member with the lowest
+// per-call price, when it is cheaper than the primary
+func (at *AutoTrader) cheapestAIClient() (mcp.AIClient, string) {
+	fc, ok := at.mcpClient.(*mcp.FailoverClient)
+	if !ok {
+		return nil, ""
+	}
+	var cheapest mcp.AIClient
+	var cheapestName string
+	var cheapestPrice float64
+	for i, m := range fc.Members() {
+		price := store.GetModelPrice(clientModelName(m.Client))
+		if i == 0 || price < cheapestPrice {
+			cheapest, cheapestName, cheapestPrice = m.Client, m.Name, price
+		}
+	}
+	if cheapest == fc.Members()[0].Client {
+		return nil, ""
+	}
+	return cheapest, cheapestName
+}
+
+// clientModelName returns the model a client calls, or its provider when
+// the model is unknown
+func clientModelName(client mcp.AIClient) string {
+	embedder, ok := client.(mcp.ClientEmbedder)
+	if !ok {
+		return ""
+	}
+	base := embedder.BaseClient()
+	if base.Model != "" {
+		return base.Model
+	}
+	return base.Provider
+}
+
+// GetAIBudgetStatus returns the last evaluated AI budget state, nil before
+// the first AI cycle or when no budget applies
+func (at *AutoTrader) GetAIBudgetStatus() *store.AIBudgetStatus {
+	at.aiBudgetMu.RLock()
+	defer at.aiBudgetMu.RUnlock()
+	if at.aiBudgetStatus == nil || (at.aiBudgetStatus.Trader == nil && at.aiBudgetStatus.User == nil) {
+		return nil
+	}
+	return at.aiBudgetStatus
+}
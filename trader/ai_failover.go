@@ -0,0 +1,88 @@
+package trader
+
+import (
+	"nofx/mcp"
+)
+
+// FallbackAIModel an AI model in the trader's failover chain
+type FallbackAIModel struct {
+	ID              string // AI model config ID
+	Provider        string
+	APIKey          string
+	CustomAPIURL    string
+	CustomModelName string
+}
+
+// newProviderClient creates and configures an AI client for a provider
+func newProviderClient(provider, apiKey, customURL, customModel string) mcp.AIClient {
+	// Create client via registry (covers all registered providers)
+	var client mcp.AIClient
+	if provider != "custom" {
+		client = mcp.NewAIClientByProvider(provider)
+	}
+	if client == nil {
+		client = mcp.New()
+	}
+
+	// Payment providers (claw402) ignore customURL
+	switch provider {
+	case "claw402":
+		client.SetAPIKey(apiKey, "", customModel)
+	default:
+		client.SetAPIKey(apiKey, customURL, customModel)
+	}
+	return client
+}
+
+// setAIClientLogger makes client (and every failover chain member) log
+// through the trader's scope, so AI request lines carry the trader and cycle IDs
+func setAIClientLogger(client mcp.AIClient, log mcp.Logger) {
+	if fc, ok := client.(*mcp.FailoverClient); ok {
+		fc.SetLogger(log)
+		for _, m := range fc.Members() {
+			setAIClientLogger(m.Client, log)
+		}
+		return
+	}
+	if embedder, ok := client.(mcp.ClientEmbedder); ok {
+		if base := embedder.BaseClient(); base != nil {
+			base.Log = log
+		}
+	}
+}
+
+// answeredAIModel returns the provider and model that answered the last AI
+// call made with client, falling back to the configured primary
+func (at *AutoTrader) answeredAIModel(client mcp.AIClient) (provider, model string, failedOver bool) {
+	provider = at.config.AIModel
+	model = at.config.CustomModelName
+	if model == "" {
+		model = at.aiModel
+	}
+	if client != at.mcpClient {
+		// A single chain member called directly (budget degradation)
+		if embedder, ok := client.(mcp.ClientEmbedder); ok {
+			base := embedder.BaseClient()
+			return base.Provider, base.Model, false
+		}
+		return provider, model, false
+	}
+	fc, ok := client.(*mcp.FailoverClient)
+	if !ok {
+		return provider, model, false
+	}
+	name, answeredProvider, answeredModel := fc.LastAnswered()
+	if name == at.aiModel || answeredProvider == "" {
+		return provider, model, false
+	}
+	return answeredProvider, answeredModel, true
+}
+
+// GetAIFailoverStatus returns the circuit breaker state of each model in the
+// failover chain, or nil when no fallback is configured
+func (at *AutoTrader) GetAIFailoverStatus() []mcp.BreakerStatus {
+	if fc, ok := at.mcpClient.(*mcp.FailoverClient); ok {
+		return fc.BreakerStatus()
+	}
+	return nil
+}
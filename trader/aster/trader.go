@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"net/url"
 	"nofx/hook"
+	"nofx/logger"
 	"sort"
 	"strconv"
 	"strings"
@@ -35,6 +36,12 @@ type AsterTrader struct {
 	// Cache symbol precision information
 	symbolPrecision map[string]SymbolPrecision
 	mu              sync.RWMutex
+
+	log *logger.Scope // see types.LogScoped
+}
+
+func (t *AsterTrader) SetLogScope(scope *logger.Scope) {
+	t.log = scope
 }
 
 // SymbolPrecision Symbol precision information
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"nofx/logger"
 	"nofx/trader/types"
 	"strconv"
 	"time"
@@ -57,13 +56,13 @@ func (t *AsterTrader) GetBalance() (map[string]interface{}, error) {
 	}
 
 	if !foundUSDT {
-		logger.Infof("⚠️  USDT asset record not found!")
+		t.log.Infof("⚠️  USDT asset record not found!")
 	}
 
 	// Get positions to calculate margin used and real unrealized PnL
 	positions, err := t.GetPositions()
 	if err != nil {
-		logger.Infof("⚠️  Failed to get position information: %v", err)
+		t.log.Infof("⚠️  Failed to get position information: %v", err)
 		// fallback: use simple calculation when unable to get positions
 		return map[string]interface{}{
 			"totalWalletBalance":    crossWalletBalance,
@@ -222,13 +221,13 @@ func (t *AsterTrader) GetTrades(startTime time.Time, limit int) ([]types.TradeRe
 	// Use existing request method with signing
 	body, err := t.request("GET", "/fapi/v3/userTrades", params)
 	if err != nil {
-		logger.Infof("⚠️  Aster userTrades API error: %v", err)
+		t.log.Infof("⚠️  Aster userTrades API error: %v", err)
 		return []types.TradeRecord{}, nil
 	}
 
 	var asterTrades []AsterTradeRecord
 	if err := json.Unmarshal(body, &asterTrades); err != nil {
-		logger.Infof("⚠️  Failed to parse Aster trades response: %v", err)
+		t.log.Infof("⚠️  Failed to parse Aster trades response: %v", err)
 		return []types.TradeRecord{}, nil
 	}
 
@@ -3,7 +3,6 @@ package aster
 import (
 	"encoding/json"
 	"fmt"
-	"nofx/logger"
 	"nofx/trader/types"
 	"strconv"
 	"strings"
@@ -21,7 +20,7 @@ const (
 func (t *AsterTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
 	// Cancel all pending orders before opening position to prevent position stacking from residual orders
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel pending orders (continuing to open position): %v", err)
+		t.log.Infof("  ⚠ Failed to cancel pending orders (continuing to open position): %v", err)
 	}
 
 	// Set leverage first (non-fatal if position already exists)
@@ -29,7 +28,7 @@ func (t *AsterTrader) OpenLong(symbol string, quantity float64, leverage int) (m
 		// Error -2030: Cannot adjust leverage when position exists
 		// This is expected when adding to an existing position, continue with current leverage
 		if strings.Contains(err.Error(), "-2030") {
-			logger.Infof("  ⚠ Cannot change leverage (position exists), using current leverage: %v", err)
+			t.log.Infof("  ⚠ Cannot change leverage (position exists), using current leverage: %v", err)
 		} else {
 			return nil, fmt.Errorf("failed to set leverage: %w", err)
 		}
@@ -64,7 +63,7 @@ func (t *AsterTrader) OpenLong(symbol string, quantity float64, leverage int) (m
 	priceStr := t.formatFloatWithPrecision(formattedPrice, prec.PricePrecision)
 	qtyStr := t.formatFloatWithPrecision(formattedQty, prec.QuantityPrecision)
 
-	logger.Infof("  📏 Precision handling: price %.8f -> %s (precision=%d), quantity %.8f -> %s (precision=%d)",
+	t.log.Infof("  📏 Precision handling: price %.8f -> %s (precision=%d), quantity %.8f -> %s (precision=%d)",
 		limitPrice, priceStr, prec.PricePrecision, quantity, qtyStr, prec.QuantityPrecision)
 
 	params := map[string]interface{}{
@@ -94,7 +93,7 @@ func (t *AsterTrader) OpenLong(symbol string, quantity float64, leverage int) (m
 func (t *AsterTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
 	// Cancel all pending orders before opening position to prevent position stacking from residual orders
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel pending orders (continuing to open position): %v", err)
+		t.log.Infof("  ⚠ Failed to cancel pending orders (continuing to open position): %v", err)
 	}
 
 	// Set leverage first (non-fatal if position already exists)
@@ -102,7 +101,7 @@ func (t *AsterTrader) OpenShort(symbol string, quantity float64, leverage int) (
 		// Error -2030: Cannot adjust leverage when position exists
 		// This is expected when adding to an existing position, continue with current leverage
 		if strings.Contains(err.Error(), "-2030") {
-			logger.Infof("  ⚠ Cannot change leverage (position exists), using current leverage: %v", err)
+			t.log.Infof("  ⚠ Cannot change leverage (position exists), using current leverage: %v", err)
 		} else {
 			return nil, fmt.Errorf("failed to set leverage: %w", err)
 		}
@@ -137,7 +136,7 @@ func (t *AsterTrader) OpenShort(symbol string, quantity float64, leverage int) (
 	priceStr := t.formatFloatWithPrecision(formattedPrice, prec.PricePrecision)
 	qtyStr := t.formatFloatWithPrecision(formattedQty, prec.QuantityPrecision)
 
-	logger.Infof("  📏 Precision handling: price %.8f -> %s (precision=%d), quantity %.8f -> %s (precision=%d)",
+	t.log.Infof("  📏 Precision handling: price %.8f -> %s (precision=%d), quantity %.8f -> %s (precision=%d)",
 		limitPrice, priceStr, prec.PricePrecision, quantity, qtyStr, prec.QuantityPrecision)
 
 	params := map[string]interface{}{
@@ -182,7 +181,7 @@ func (t *AsterTrader) CloseLong(symbol string, quantity float64) (map[string]int
 		if quantity == 0 {
 			return nil, fmt.Errorf("no long position found for %s", symbol)
 		}
-		logger.Infof("  📊 Retrieved long position quantity: %.8f", quantity)
+		t.log.Infof("  📊 Retrieved long position quantity: %.8f", quantity)
 	}
 
 	price, err := t.GetMarketPrice(symbol)
@@ -212,7 +211,7 @@ func (t *AsterTrader) CloseLong(symbol string, quantity float64) (map[string]int
 	priceStr := t.formatFloatWithPrecision(formattedPrice, prec.PricePrecision)
 	qtyStr := t.formatFloatWithPrecision(formattedQty, prec.QuantityPrecision)
 
-	logger.Infof("  📏 Precision handling: price %.8f -> %s (precision=%d), quantity %.8f -> %s (precision=%d)",
+	t.log.Infof("  📏 Precision handling: price %.8f -> %s (precision=%d), quantity %.8f -> %s (precision=%d)",
 		limitPrice, priceStr, prec.PricePrecision, quantity, qtyStr, prec.QuantityPrecision)
 
 	params := map[string]interface{}{
@@ -235,11 +234,11 @@ func (t *AsterTrader) CloseLong(symbol string, quantity float64) (map[string]int
 		return nil, err
 	}
 
-	logger.Infof("✓ Successfully closed long position: %s quantity: %s", symbol, qtyStr)
+	t.log.Infof("✓ Successfully closed long position: %s quantity: %s", symbol, qtyStr)
 
 	// Cancel all pending orders for this symbol after closing position (stop-loss/take-profit orders)
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel pending orders: %v", err)
+		t.log.Infof("  ⚠ Failed to cancel pending orders: %v", err)
 	}
 
 	return result, nil
@@ -265,7 +264,7 @@ func (t *AsterTrader) CloseShort(symbol string, quantity float64) (map[string]in
 		if quantity == 0 {
 			return nil, fmt.Errorf("no short position found for %s", symbol)
 		}
-		logger.Infof("  📊 Retrieved short position quantity: %.8f", quantity)
+		t.log.Infof("  📊 Retrieved short position quantity: %.8f", quantity)
 	}
 
 	price, err := t.GetMarketPrice(symbol)
@@ -295,7 +294,7 @@ func (t *AsterTrader) CloseShort(symbol string, quantity float64) (map[string]in
 	priceStr := t.formatFloatWithPrecision(formattedPrice, prec.PricePrecision)
 	qtyStr := t.formatFloatWithPrecision(formattedQty, prec.QuantityPrecision)
 
-	logger.Infof("  📏 Precision handling: price %.8f -> %s (precision=%d), quantity %.8f -> %s (precision=%d)",
+	t.log.Infof("  📏 Precision handling: price %.8f -> %s (precision=%d), quantity %.8f -> %s (precision=%d)",
 		limitPrice, priceStr, prec.PricePrecision, quantity, qtyStr, prec.QuantityPrecision)
 
 	params := map[string]interface{}{
@@ -318,11 +317,11 @@ func (t *AsterTrader) CloseShort(symbol string, quantity float64) (map[string]in
 		return nil, err
 	}
 
-	logger.Infof("✓ Successfully closed short position: %s quantity: %s", symbol, qtyStr)
+	t.log.Infof("✓ Successfully closed short position: %s quantity: %s", symbol, qtyStr)
 
 	// Cancel all pending orders for this symbol after closing position (stop-loss/take-profit orders)
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel pending orders: %v", err)
+		t.log.Infof("  ⚠ Failed to cancel pending orders: %v", err)
 	}
 
 	return result, nil
@@ -446,19 +445,19 @@ func (t *AsterTrader) CancelStopLossOrders(symbol string) error {
 			if err != nil {
 				errMsg := fmt.Sprintf("order ID %d: %v", int64(orderID), err)
 				cancelErrors = append(cancelErrors, fmt.Errorf("%s", errMsg))
-				logger.Infof("  ⚠ Failed to cancel stop-loss order: %s", errMsg)
+				t.log.Infof("  ⚠ Failed to cancel stop-loss order: %s", errMsg)
 				continue
 			}
 
 			canceledCount++
-			logger.Infof("  ✓ Canceled stop-loss order (order ID: %d, type: %s, direction: %s)", int64(orderID), orderType, positionSide)
+			t.log.Infof("  ✓ Canceled stop-loss order (order ID: %d, type: %s, direction: %s)", int64(orderID), orderType, positionSide)
 		}
 	}
 
 	if canceledCount == 0 && len(cancelErrors) == 0 {
-		logger.Infof("  ℹ %s no stop-loss orders to cancel", symbol)
+		t.log.Infof("  ℹ %s no stop-loss orders to cancel", symbol)
 	} else if canceledCount > 0 {
-		logger.Infof("  ✓ Canceled %d stop-loss order(s) for %s", canceledCount, symbol)
+		t.log.Infof("  ✓ Canceled %d stop-loss order(s) for %s", canceledCount, symbol)
 	}
 
 	// Return error if all cancellations failed
@@ -505,19 +504,19 @@ func (t *AsterTrader) CancelTakeProfitOrders(symbol string) error {
 			if err != nil {
 				errMsg := fmt.Sprintf("order ID %d: %v", int64(orderID), err)
 				cancelErrors = append(cancelErrors, fmt.Errorf("%s", errMsg))
-				logger.Infof("  ⚠ Failed to cancel take-profit order: %s", errMsg)
+				t.log.Infof("  ⚠ Failed to cancel take-profit order: %s", errMsg)
 				continue
 			}
 
 			canceledCount++
-			logger.Infof("  ✓ Canceled take-profit order (order ID: %d, type: %s, direction: %s)", int64(orderID), orderType, positionSide)
+			t.log.Infof("  ✓ Canceled take-profit order (order ID: %d, type: %s, direction: %s)", int64(orderID), orderType, positionSide)
 		}
 	}
 
 	if canceledCount == 0 && len(cancelErrors) == 0 {
-		logger.Infof("  ℹ %s no take-profit orders to cancel", symbol)
+		t.log.Infof("  ℹ %s no take-profit orders to cancel", symbol)
 	} else if canceledCount > 0 {
-		logger.Infof("  ✓ Canceled %d take-profit order(s) for %s", canceledCount, symbol)
+		t.log.Infof("  ✓ Canceled %d take-profit order(s) for %s", canceledCount, symbol)
 	}
 
 	// Return error if all cancellations failed
@@ -574,20 +573,20 @@ func (t *AsterTrader) CancelStopOrders(symbol string) error {
 
 			_, err := t.request("DELETE", "/fapi/v3/order", cancelParams)
 			if err != nil {
-				logger.Infof("  ⚠ Failed to cancel order %d: %v", int64(orderID), err)
+				t.log.Infof("  ⚠ Failed to cancel order %d: %v", int64(orderID), err)
 				continue
 			}
 
 			canceledCount++
-			logger.Infof("  ✓ Canceled take-profit/stop-loss order for %s (order ID: %d, type: %s)",
+			t.log.Infof("  ✓ Canceled take-profit/stop-loss order for %s (order ID: %d, type: %s)",
 				symbol, int64(orderID), orderType)
 		}
 	}
 
 	if canceledCount == 0 {
-		logger.Infof("  ℹ %s no take-profit/stop-loss orders to cancel", symbol)
+		t.log.Infof("  ℹ %s no take-profit/stop-loss orders to cancel", symbol)
 	} else {
-		logger.Infof("  ✓ Canceled %d take-profit/stop-loss order(s) for %s", canceledCount, symbol)
+		t.log.Infof("  ✓ Canceled %d take-profit/stop-loss order(s) for %s", canceledCount, symbol)
 	}
 
 	return nil
@@ -697,7 +696,7 @@ func (t *AsterTrader) GetOpenOrders(symbol string) ([]types.OpenOrder, error) {
 		})
 	}
 
-	logger.Infof("✓ ASTER GetOpenOrders: found %d open orders for %s", len(result), symbol)
+	t.log.Infof("✓ ASTER GetOpenOrders: found %d open orders for %s", len(result), symbol)
 	return result, nil
 }
 
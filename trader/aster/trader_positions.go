@@ -3,7 +3,6 @@ package aster
 import (
 	"encoding/json"
 	"fmt"
-	"nofx/logger"
 	"strconv"
 	"strings"
 )
@@ -82,30 +81,30 @@ func (t *AsterTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
 		// Ignore error if it indicates no need to change
 		if strings.Contains(err.Error(), "No need to change") ||
 			strings.Contains(err.Error(), "Margin type cannot be changed") {
-			logger.Infof("  ✓ %s margin mode is already %s or cannot be changed due to existing positions", symbol, marginType)
+			t.log.Infof("  ✓ %s margin mode is already %s or cannot be changed due to existing positions", symbol, marginType)
 			return nil
 		}
 		// Detect multi-assets mode (error code -4168)
 		if strings.Contains(err.Error(), "Multi-Assets mode") ||
 			strings.Contains(err.Error(), "-4168") ||
 			strings.Contains(err.Error(), "4168") {
-			logger.Infof("  ⚠️ %s detected multi-assets mode, forcing cross margin mode", symbol)
-			logger.Infof("  💡 Tip: To use isolated margin mode, please disable multi-assets mode on the exchange")
+			t.log.Infof("  ⚠️ %s detected multi-assets mode, forcing cross margin mode", symbol)
+			t.log.Infof("  💡 Tip: To use isolated margin mode, please disable multi-assets mode on the exchange")
 			return nil
 		}
 		// Detect unified account API
 		if strings.Contains(err.Error(), "unified") ||
 			strings.Contains(err.Error(), "portfolio") ||
 			strings.Contains(err.Error(), "Portfolio") {
-			logger.Infof("  ❌ %s detected unified account API, cannot perform futures trading", symbol)
+			t.log.Infof("  ❌ %s detected unified account API, cannot perform futures trading", symbol)
 			return fmt.Errorf("please use 'Spot & Futures Trading' API permission, not 'Unified Account API'")
 		}
-		logger.Infof("  ⚠️ Failed to set margin mode: %v", err)
+		t.log.Infof("  ⚠️ Failed to set margin mode: %v", err)
 		// Don't return error, let trading continue
 		return nil
 	}
 
-	logger.Infof("  ✓ %s margin mode has been set to %s", symbol, marginType)
+	t.log.Infof("  ✓ %s margin mode has been set to %s", symbol, marginType)
 	return nil
 }
 
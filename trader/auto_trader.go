@@ -1,6 +1,7 @@
 package trader
 
 import (
+	"context"
 	"fmt"
 	"github.com/ethereum/go-ethereum/crypto"
 	"nofx/kernel"
@@ -21,6 +22,7 @@ import (
 	"nofx/trader/okx"
 	"nofx/wallet"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -34,14 +36,48 @@ func (at *AutoTrader) logTag() string {
 	return fmt.Sprintf("[trader_id=%s]", at.id)
 }
 
+// beginCycleLog derives the scope of the cycle about to run from the
+// trader's base scope and hands it to the AI client. The base scope is never
+// changed, so lines the exchange adapter logs from its own goroutines (order
+// sync) never pick up a cycle's fields.
+func (at *AutoTrader) beginCycleLog() {
+	scope := at.log.With(logger.FieldCycle, at.callCount).With(logger.FieldCycleID, logger.NewCorrelationID())
+	at.cycleLog.Store(scope)
+	setAIClientLogger(at.mcpClient, scope)
+}
+
+// endCycleLog drops the finished cycle's scope
+func (at *AutoTrader) endCycleLog() {
+	at.cycleLog.Store(nil)
+	setAIClientLogger(at.mcpClient, at.log)
+}
+
+// cycleScope returns the scope of the running cycle, or the base scope
+// between cycles
+func (at *AutoTrader) cycleScope() *logger.Scope {
+	if scope := at.cycleLog.Load(); scope != nil {
+		return scope
+	}
+	return at.log
+}
+
+// tagged prefixes the trader tag in text logs; JSON lines carry the
+// trader_id field instead
+func (at *AutoTrader) tagged(format string, args []interface{}) (string, []interface{}) {
+	if logger.IsJSON() {
+		return format, args
+	}
+	return "%s " + format, append([]interface{}{at.logTag()}, args...)
+}
+
 func (at *AutoTrader) logInfof(format string, args ...interface{}) {
-	values := append([]interface{}{at.logTag()}, args...)
-	logger.Infof("%s "+format, values...)
+	format, args = at.tagged(format, args)
+	at.cycleScope().Infof(format, args...)
 }
 
 func (at *AutoTrader) logWarnf(format string, args ...interface{}) {
-	values := append([]interface{}{at.logTag()}, args...)
-	logger.Warnf("%s "+format, values...)
+	format, args = at.tagged(format, args)
+	at.cycleScope().Warnf(format, args...)
 }
 
 // extractInitialBalance prefers an exchange's explicit mark-to-market equity.
@@ -65,8 +101,8 @@ func extractInitialBalance(account map[string]interface{}) float64 {
 }
 
 func (at *AutoTrader) logErrorf(format string, args ...interface{}) {
-	values := append([]interface{}{at.logTag()}, args...)
-	logger.Errorf("%s "+format, values...)
+	format, args = at.tagged(format, args)
+	at.cycleScope().Errorf(format, args...)
 }
 
 // AutoTraderConfig auto trading configuration (simplified version - AI makes all decisions)
@@ -141,15 +177,18 @@ type AutoTraderConfig struct {
 	CustomModelName  string
 	Claw402WalletKey string
 
+	// AI failover chain, tried in order when the primary model fails
+	FallbackAIModels []FallbackAIModel
+
 	// Scan configuration
 	ScanInterval time.Duration // Scan interval (recommended 15 minutes)
 
 	// Account configuration
 	InitialBalance float64 // Initial balance (for P&L calculation, must be set manually)
 
-	// Risk control (only as hints, AI can make autonomous decisions)
-	MaxDailyLoss    float64       // Maximum daily loss percentage (hint)
-	MaxDrawdown     float64       // Maximum drawdown percentage (hint)
+	// Risk control fallbacks for the circuit breakers the strategy leaves unset (see circuit_breaker.go)
+	MaxDailyLoss    float64       // Maximum daily loss percentage
+	MaxDrawdown     float64       // Maximum drawdown percentage from the equity peak
 	StopTradingTime time.Duration // Pause duration after risk control triggers
 
 	// Position mode
@@ -161,6 +200,10 @@ type AutoTraderConfig struct {
 	// Strategy configuration (use complete strategy config)
 	StrategyConfig    *store.StrategyConfig // Strategy configuration (includes coin sources, indicators, risk control, prompts, etc.)
 	StrategyConfigRaw string                // Raw strategy config JSON from DB, used to detect live edits
+	StrategyVersion   int                   // Strategy version StrategyConfig was loaded from (0 = pre-versioning)
+	// Pinned strategy version (0 = follow latest). A pinned trader ignores edits
+	// to the strategy until the pin is moved (promoted) to a newer version.
+	PinnedStrategyVersion int
 }
 
 // AutoTrader automatic trader
@@ -172,7 +215,10 @@ type AutoTrader struct {
 	exchangeID            string // Exchange account UUID
 	showInCompetition     bool   // Whether to show in competition page
 	config                AutoTraderConfig
-	trader                Trader // Use Trader interface (supports multiple platforms)
+	trader                Trader                       // Use Trader interface (supports multiple platforms)
+	log                   *logger.Scope                // Base correlation fields (trader, exchange); never changed, shared with the exchange adapter
+	cycleLog              atomic.Pointer[logger.Scope] // Base scope plus the running cycle's fields, nil between cycles
+	cycleCtx              context.Context              // Span of the running decision cycle; only touched by the cycle goroutine
 	mcpClient             mcp.AIClient
 	store                 *store.Store           // Data storage (decision records, etc.)
 	strategyEngine        *kernel.StrategyEngine // Strategy engine (uses strategy configuration)
@@ -183,6 +229,9 @@ type AutoTrader struct {
 	overrideBasePrompt    bool   // Whether to override base prompt
 	lastResetTime         time.Time
 	stopUntil             time.Time
+	breakerPauseOpens     bool      // stopUntil only blocks opens (pause_opens circuit breaker)
+	breakerReason         string    // Why the last circuit breaker tripped
+	breakerSince          time.Time // Drawdown peak and losing streak count from here
 	isRunning             bool
 	isRunningMutex        sync.RWMutex       // Mutex to protect isRunning flag
 	startTime             time.Time          // System start time
@@ -193,6 +242,7 @@ type AutoTrader struct {
 	peakPnLCache          map[string]float64 // Peak profit cache (symbol -> peak P&L percentage)
 	peakPnLCacheMutex     sync.RWMutex       // Cache read-write lock
 	lastBalanceSyncTime   time.Time          // Last balance sync time
+	lastFundingSync       time.Time          // Last funding payment sync
 	userID                string             // User ID
 	gridState             *GridState         // Grid trading state (only used when StrategyType == "grid_trading")
 	claw402WalletAddr     string             // Claw402 wallet address (derived from private key at start)
@@ -203,6 +253,12 @@ type AutoTrader struct {
 	aiWalletStatus        string             // "ok"|"low"|"empty"|"unknown" — see runtime_health.go
 	aiWalletBalanceUSDC   float64            // Last observed Base USDC balance of the claw402 wallet
 	aiWalletCheckedAt     time.Time          // When the balance was last observed
+
+	strategyMu sync.RWMutex // Guards the pinned strategy version and the strategy config swap (API writes the pin, loop swaps)
+
+	aiBudgetMu     sync.RWMutex
+	aiBudgetStatus *store.AIBudgetStatus // Last evaluated AI spend budget state
+	lastAICallAt   time.Time             // Last AI call, for interval degradation
 }
 
 // NewAutoTrader creates an automatic trader
@@ -244,32 +300,29 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 		}
 	}
 
-	// Create client via registry (covers all registered providers)
-	if aiModel == "custom" {
-		mcpClient = mcp.New()
-	} else if aiModel == "" {
+	if aiModel == "" {
 		aiModel = "deepseek"
-		mcpClient = mcp.NewAIClientByProvider(aiModel)
-	} else {
-		mcpClient = mcp.NewAIClientByProvider(aiModel)
-	}
-	if mcpClient == nil {
-		mcpClient = mcp.New()
-	}
-
-	// Payment providers (claw402) ignore customURL
-	switch aiModel {
-	case "claw402":
-		mcpClient.SetAPIKey(apiKey, "", config.CustomModelName)
-	default:
-		mcpClient.SetAPIKey(apiKey, customURL, config.CustomModelName)
 	}
+	mcpClient = newProviderClient(aiModel, apiKey, customURL, config.CustomModelName)
 	logger.Infof("🤖 [%s] Using %s AI", config.Name, aiModel)
 
 	if config.CustomAPIURL != "" || config.CustomModelName != "" {
 		logger.Infof("🔧 [%s] Custom config - URL: %s, Model: %s", config.Name, config.CustomAPIURL, config.CustomModelName)
 	}
 
+	// Wrap the primary in a failover chain when fallback models are configured
+	if len(config.FallbackAIModels) > 0 {
+		members := []mcp.FailoverMember{{Name: aiModel, Client: mcpClient}}
+		for _, fb := range config.FallbackAIModels {
+			members = append(members, mcp.FailoverMember{
+				Name:   fb.ID,
+				Client: newProviderClient(fb.Provider, fb.APIKey, fb.CustomAPIURL, fb.CustomModelName),
+			})
+			logger.Infof("🔀 [%s] AI fallback #%d: %s", config.Name, len(members)-1, fb.ID)
+		}
+		mcpClient = mcp.NewFailoverClient(members, logger.NewMCPLogger())
+	}
+
 	// Set default trading platform
 	if config.Exchange == "" {
 		config.Exchange = "binance"
@@ -387,6 +440,16 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 	strategyEngine := kernel.NewStrategyEngine(config.StrategyConfig, claw402Key)
 	logger.Infof("✓ [%s] Using strategy engine (strategy configuration loaded)", config.Name)
 
+	scope := logger.NewScope(logger.Fields{
+		logger.FieldTraderID: config.ID,
+		"trader_name":        config.Name,
+		logger.FieldExchange: config.Exchange,
+	})
+	setAIClientLogger(mcpClient, scope)
+	if scoped, ok := trader.(LogScoped); ok {
+		scoped.SetLogScope(scope)
+	}
+
 	return &AutoTrader{
 		id:                    config.ID,
 		name:                  config.Name,
@@ -396,6 +459,7 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 		showInCompetition:     config.ShowInCompetition,
 		config:                config,
 		trader:                trader,
+		log:                   scope,
 		mcpClient:             mcpClient,
 		store:                 st,
 		strategyEngine:        strategyEngine,
@@ -420,12 +484,18 @@ func (at *AutoTrader) reloadStrategyConfigIfChanged() error {
 		return nil
 	}
 
-	strategy, err := at.store.Strategy().Get(at.userID, at.config.StrategyID)
+	at.strategyMu.RLock()
+	pinned := at.config.PinnedStrategyVersion
+	at.strategyMu.RUnlock()
+	strategy, err := at.store.Strategy().GetAtVersion(at.userID, at.config.StrategyID, pinned)
 	if err != nil {
 		return fmt.Errorf("failed to load strategy %s: %w", at.config.StrategyID, err)
 	}
 
 	if at.strategyEngine != nil && strategy.Config == at.config.StrategyConfigRaw {
+		at.strategyMu.Lock()
+		at.config.StrategyVersion = strategy.CurrentVersion
+		at.strategyMu.Unlock()
 		return nil
 	}
 
@@ -446,10 +516,14 @@ func (at *AutoTrader) reloadStrategyConfigIfChanged() error {
 		claw402Key = at.config.CustomAPIKey
 	}
 
+	engine := kernel.NewStrategyEngine(strategyConfig, claw402Key)
+	at.strategyMu.Lock()
 	at.config.StrategyConfig = strategyConfig
 	at.config.StrategyConfigRaw = strategy.Config
-	at.strategyEngine = kernel.NewStrategyEngine(strategyConfig, claw402Key)
-	at.logInfof("🔄 Strategy config refreshed from DB: %s", strategy.Name)
+	at.config.StrategyVersion = strategy.CurrentVersion
+	at.strategyEngine = engine
+	at.strategyMu.Unlock()
+	at.logInfof("🔄 Strategy config refreshed from DB: %s (v%d)", strategy.Name, strategy.CurrentVersion)
 	return nil
 }
 
@@ -461,6 +535,7 @@ func (at *AutoTrader) Run() error {
 
 	at.stopMonitorCh = make(chan struct{})
 	at.startTime = time.Now()
+	at.restoreBreakerState()
 
 	logger.Info("🚀 AI-driven automatic trading system started")
 	at.logInfof("💰 Initial balance: %.2f USDT", at.initialBalance)
@@ -658,6 +733,14 @@ func (at *AutoTrader) SetShowInCompetition(show bool) {
 	at.showInCompetition = show
 }
 
+// SetPinnedStrategyVersion pins the strategy version (0 = follow latest);
+// the new config is clamped and picked up at the start of the next cycle
+func (at *AutoTrader) SetPinnedStrategyVersion(version int) {
+	at.strategyMu.Lock()
+	defer at.strategyMu.Unlock()
+	at.config.PinnedStrategyVersion = version
+}
+
 // SetCustomPrompt sets custom trading strategy prompt
 func (at *AutoTrader) SetCustomPrompt(prompt string) {
 	at.customPrompt = prompt
@@ -689,6 +772,8 @@ func (at *AutoTrader) GetCandidateCoins() ([]kernel.CandidateCoin, error) {
 
 // GetStrategyConfig returns the current strategy config used by the trader.
 func (at *AutoTrader) GetStrategyConfig() *store.StrategyConfig {
+	at.strategyMu.RLock()
+	defer at.strategyMu.RUnlock()
 	if at.strategyEngine == nil {
 		return at.config.StrategyConfig
 	}
@@ -29,7 +29,7 @@ func (at *AutoTrader) saveEquitySnapshot(ctx *kernel.Context) {
 	}
 
 	if err := at.store.Equity().Save(snapshot); err != nil {
-		logger.Infof("⚠️ Failed to save equity snapshot: %v", err)
+		at.cycleScope().Infof("⚠️ Failed to save equity snapshot: %v", err)
 	}
 }
 
@@ -42,20 +42,49 @@ func (at *AutoTrader) saveDecision(record *store.DecisionRecord) error {
 	at.cycleNumber++
 	record.CycleNumber = at.cycleNumber
 	record.TraderID = at.id
+	record.StrategyID = at.config.StrategyID
+	record.StrategyVersion = at.config.StrategyVersion
 
 	if record.Timestamp.IsZero() {
 		record.Timestamp = time.Now().UTC()
 	}
 
 	if err := at.store.Decision().LogDecision(record); err != nil {
-		logger.Infof("⚠️ Failed to save decision record: %v", err)
+		at.cycleScope().Infof("⚠️ Failed to save decision record: %v", err)
 		return err
 	}
 
-	logger.Infof("📝 Decision record saved: trader=%s, cycle=%d", at.id, at.cycleNumber)
+	at.cycleScope().Infof("📝 Decision record saved: trader=%s, cycle=%d", at.id, at.cycleNumber)
 	return nil
 }
 
+// cycleSnapshot the account and open positions a cycle decided on, kept with
+// its decision record
+func (at *AutoTrader) cycleSnapshot(ctx *kernel.Context) (store.AccountSnapshot, []store.PositionSnapshot) {
+	account := store.AccountSnapshot{
+		TotalBalance:          ctx.Account.TotalEquity,
+		AvailableBalance:      ctx.Account.AvailableBalance,
+		TotalUnrealizedProfit: ctx.Account.UnrealizedPnL,
+		PositionCount:         ctx.Account.PositionCount,
+		MarginUsedPct:         ctx.Account.MarginUsedPct,
+		InitialBalance:        at.initialBalance,
+	}
+	positions := make([]store.PositionSnapshot, 0, len(ctx.Positions))
+	for _, p := range ctx.Positions {
+		positions = append(positions, store.PositionSnapshot{
+			Symbol:           p.Symbol,
+			Side:             p.Side,
+			PositionAmt:      p.Quantity,
+			EntryPrice:       p.EntryPrice,
+			MarkPrice:        p.MarkPrice,
+			UnrealizedProfit: p.UnrealizedPnL,
+			Leverage:         float64(p.Leverage),
+			LiquidationPrice: p.LiquidationPrice,
+		})
+	}
+	return account, positions
+}
+
 // GetStatus gets system status (for API)
 func (at *AutoTrader) GetStatus() map[string]interface{} {
 	aiProvider := "DeepSeek"
@@ -84,12 +113,16 @@ func (at *AutoTrader) GetStatus() map[string]interface{} {
 	}
 
 	// Add strategy info
+	at.strategyMu.RLock()
 	if at.config.StrategyConfig != nil {
 		result["strategy_type"] = at.config.StrategyConfig.StrategyType
+		result["strategy_version"] = at.config.StrategyVersion
+		result["strategy_version_pinned"] = at.config.PinnedStrategyVersion > 0
 		if at.config.StrategyConfig.GridConfig != nil {
 			result["grid_symbol"] = at.config.StrategyConfig.GridConfig.Symbol
 		}
 	}
+	at.strategyMu.RUnlock()
 
 	// Runtime health: safe mode + AI fee wallet, so the dashboard can show a
 	// persistent banner instead of the user digging through logs.
@@ -98,11 +131,21 @@ func (at *AutoTrader) GetStatus() map[string]interface{} {
 	if safeModeReason != "" {
 		result["safe_mode_reason"] = safeModeReason
 	}
+	if time.Now().Before(at.stopUntil) && at.breakerReason != "" {
+		result["circuit_breaker_reason"] = at.breakerReason
+		result["circuit_breaker_pause_opens"] = at.breakerPauseOpens
+	}
 	if status, balance, checkedAt := at.aiWalletHealth(); status != "" {
 		result["ai_wallet_status"] = status
 		result["ai_wallet_balance_usdc"] = balance
 		result["ai_wallet_checked_at"] = checkedAt.Format(time.RFC3339)
 	}
+	if budget := at.GetAIBudgetStatus(); budget != nil {
+		result["ai_budget"] = budget
+	}
+	if breakers := at.GetAIFailoverStatus(); breakers != nil {
+		result["ai_failover"] = breakers
+	}
 
 	return result
 }
@@ -167,7 +210,7 @@ func (at *AutoTrader) GetAccountInfo() (map[string]interface{}, error) {
 	// Note: Lighter API may return 0 for unrealized PnL, this is a known limitation
 	diff := math.Abs(totalUnrealizedProfit - totalUnrealizedPnLCalculated)
 	if diff > 5.0 { // Only warn if difference is significant (> 5 USDT)
-		logger.Infof("⚠️ Unrealized P&L inconsistency (Lighter API limitation): API=%.4f, Calculated=%.4f, Diff=%.4f",
+		at.log.Infof("⚠️ Unrealized P&L inconsistency (Lighter API limitation): API=%.4f, Calculated=%.4f, Diff=%.4f",
 			totalUnrealizedProfit, totalUnrealizedPnLCalculated, diff)
 	}
 
@@ -176,7 +219,7 @@ func (at *AutoTrader) GetAccountInfo() (map[string]interface{}, error) {
 	if at.initialBalance > 0 {
 		totalPnLPct = (totalPnL / at.initialBalance) * 100
 	} else {
-		logger.Infof("⚠️ Initial Balance abnormal: %.2f, cannot calculate P&L percentage", at.initialBalance)
+		at.log.Infof("⚠️ Initial Balance abnormal: %.2f, cannot calculate P&L percentage", at.initialBalance)
 	}
 
 	marginUsedPct := 0.0
@@ -255,7 +298,7 @@ func (at *AutoTrader) GetPositions() ([]map[string]interface{}, error) {
 // recordAndConfirmOrder polls order status for actual fill data and records position
 // action: open_long, open_short, close_long, close_short
 // entryPrice: entry price when closing (0 when opening)
-func (at *AutoTrader) recordAndConfirmOrder(orderResult map[string]interface{}, symbol, action string, quantity float64, price float64, leverage int, entryPrice float64) {
+func (at *AutoTrader) recordAndConfirmOrder(log *logger.Scope, orderResult map[string]interface{}, symbol, action string, quantity float64, price float64, leverage int, entryPrice float64) {
 	if at.store == nil {
 		return
 	}
@@ -274,7 +317,7 @@ func (at *AutoTrader) recordAndConfirmOrder(orderResult map[string]interface{},
 	}
 
 	if orderID == "" || orderID == "0" {
-		logger.Infof("  ⚠️ Order ID is empty, skipping record")
+		log.Infof("  ⚠️ Order ID is empty, skipping record")
 		return
 	}
 
@@ -295,16 +338,16 @@ func (at *AutoTrader) recordAndConfirmOrder(orderResult map[string]interface{},
 	// This ensures accurate data from GetTrades API and avoids duplicate records
 	switch at.exchange {
 	case "binance", "lighter", "hyperliquid", "bybit", "okx", "bitget", "aster", "kucoin", "gate":
-		logger.Infof("  📝 Order submitted (id: %s), will be synced by OrderSync", orderID)
+		log.Infof("  📝 Order submitted (id: %s), will be synced by OrderSync", orderID)
 		return
 	}
 
 	// For exchanges without OrderSync (e.g., Binance): record immediately and poll for fill data
 	orderRecord := at.createOrderRecord(orderID, symbol, action, positionSide, quantity, price, leverage)
 	if err := at.store.Order().CreateOrder(orderRecord); err != nil {
-		logger.Infof("  ⚠️ Failed to record order: %v", err)
+		log.Infof("  ⚠️ Failed to record order: %v", err)
 	} else {
-		logger.Infof("  📝 Order recorded: %s [%s] %s", orderID, action, symbol)
+		log.Infof("  📝 Order recorded: %s [%s] %s", orderID, action, symbol)
 	}
 
 	// Wait for order to be filled and get actual fill data
@@ -326,21 +369,21 @@ func (at *AutoTrader) recordAndConfirmOrder(orderResult map[string]interface{},
 				if commission, ok := status["commission"].(float64); ok {
 					fee = commission
 				}
-				logger.Infof("  ✅ Order filled: avgPrice=%.6f, qty=%.6f, fee=%.6f", actualPrice, actualQty, fee)
+				log.Infof("  ✅ Order filled: avgPrice=%.6f, qty=%.6f, fee=%.6f", actualPrice, actualQty, fee)
 
 				// Update order status to FILLED
 				if err := at.store.Order().UpdateOrderStatus(orderRecord.ID, "FILLED", actualQty, actualPrice, fee); err != nil {
-					logger.Infof("  ⚠️ Failed to update order status: %v", err)
+					log.Infof("  ⚠️ Failed to update order status: %v", err)
 				}
 
 				// Record fill details
-				at.recordOrderFill(orderRecord.ID, orderID, symbol, action, actualPrice, actualQty, fee)
+				at.recordOrderFill(log, orderRecord.ID, orderID, symbol, action, actualPrice, actualQty, fee)
 				break
 			} else if statusStr == "CANCELED" || statusStr == "EXPIRED" || statusStr == "REJECTED" {
-				logger.Infof("  ⚠️ Order %s, skipping position record", statusStr)
+				log.Infof("  ⚠️ Order %s, skipping position record", statusStr)
 				// Update order status
 				if err := at.store.Order().UpdateOrderStatus(orderRecord.ID, statusStr, 0, 0, 0); err != nil {
-					logger.Infof("  ⚠️ Failed to update order status: %v", err)
+					log.Infof("  ⚠️ Failed to update order status: %v", err)
 				}
 				return
 			}
@@ -351,11 +394,11 @@ func (at *AutoTrader) recordAndConfirmOrder(orderResult map[string]interface{},
 	// Normalize symbol for position record consistency
 	normalizedSymbolForPosition := market.Normalize(symbol)
 
-	logger.Infof("  📝 Recording position (ID: %s, action: %s, price: %.6f, qty: %.6f, fee: %.4f)",
+	log.Infof("  📝 Recording position (ID: %s, action: %s, price: %.6f, qty: %.6f, fee: %.4f)",
 		orderID, action, actualPrice, actualQty, fee)
 
 	// Record position change with actual fill data (use normalized symbol)
-	at.recordPositionChange(orderID, normalizedSymbolForPosition, positionSide, action, actualQty, actualPrice, leverage, entryPrice, fee)
+	at.recordPositionChange(log, orderID, normalizedSymbolForPosition, positionSide, action, actualQty, actualPrice, leverage, entryPrice, fee)
 
 	// Send anonymous trade statistics for experience improvement (async, non-blocking)
 	// This helps us understand overall product usage across all deployments
@@ -371,7 +414,7 @@ func (at *AutoTrader) recordAndConfirmOrder(orderResult map[string]interface{},
 }
 
 // recordPositionChange records position change (create record on open, update record on close)
-func (at *AutoTrader) recordPositionChange(orderID, symbol, side, action string, quantity, price float64, leverage int, entryPrice float64, fee float64) {
+func (at *AutoTrader) recordPositionChange(log *logger.Scope, orderID, symbol, side, action string, quantity, price float64, leverage int, entryPrice float64, fee float64) {
 	if at.store == nil {
 		return
 	}
@@ -396,9 +439,9 @@ func (at *AutoTrader) recordPositionChange(orderID, symbol, side, action string,
 			UpdatedAt:    nowMs,
 		}
 		if err := at.store.Position().Create(pos); err != nil {
-			logger.Infof("  ⚠️ Failed to record position: %v", err)
+			log.Infof("  ⚠️ Failed to record position: %v", err)
 		} else {
-			logger.Infof("  📊 Position recorded [%s] %s %s @ %.4f", at.id[:8], symbol, side, price)
+			log.Infof("  📊 Position recorded [%s] %s %s @ %.4f", at.id[:8], symbol, side, price)
 		}
 
 	case "close_long", "close_short":
@@ -413,9 +456,9 @@ func (at *AutoTrader) recordPositionChange(orderID, symbol, side, action string,
 			quantity, price, fee, 0, // realizedPnL will be calculated
 			time.Now().UTC().UnixMilli(), orderID,
 		); err != nil {
-			logger.Infof("  ⚠️ Failed to process close position: %v", err)
+			log.Infof("  ⚠️ Failed to process close position: %v", err)
 		} else {
-			logger.Infof("  ✅ Position closed [%s] %s %s @ %.4f", at.id[:8], symbol, side, price)
+			log.Infof("  ✅ Position closed [%s] %s %s @ %.4f", at.id[:8], symbol, side, price)
 		}
 	}
 }
@@ -470,7 +513,7 @@ func (at *AutoTrader) createOrderRecord(orderID, symbol, action, positionSide st
 }
 
 // recordOrderFill records order fill/trade details
-func (at *AutoTrader) recordOrderFill(orderRecordID int64, exchangeOrderID, symbol, action string, price, quantity, fee float64) {
+func (at *AutoTrader) recordOrderFill(log *logger.Scope, orderRecordID int64, exchangeOrderID, symbol, action string, price, quantity, fee float64) {
 	if at.store == nil {
 		return
 	}
@@ -529,9 +572,9 @@ func (at *AutoTrader) recordOrderFill(orderRecordID int64, exchangeOrderID, symb
 	}
 
 	if err := at.store.Order().CreateFill(fill); err != nil {
-		logger.Infof("  ⚠️ Failed to record fill: %v", err)
+		log.Infof("  ⚠️ Failed to record fill: %v", err)
 	} else {
-		logger.Infof("  📋 Fill recorded: %.4f @ %.6f, fee: %.4f", quantity, price, fee)
+		log.Infof("  📋 Fill recorded: %.4f @ %.6f, fee: %.4f", quantity, price, fee)
 	}
 }
 
@@ -351,6 +351,9 @@ func (at *AutoTrader) InitializeGrid() error {
 
 // RunGridCycle executes one grid trading cycle
 func (at *AutoTrader) RunGridCycle() error {
+	at.beginCycleLog()
+	defer at.endCycleLog()
+
 	// Check if trader is stopped (early exit to prevent trades after Stop() is called)
 	at.isRunningMutex.RLock()
 	running := at.isRunning
@@ -421,8 +424,17 @@ func (at *AutoTrader) RunGridCycle() error {
 		return fmt.Errorf("failed to build grid context: %w", err)
 	}
 
-	// Get AI decisions
-	decision, err := kernel.GetGridDecisions(gridCtx, at.mcpClient, gridConfig, lang)
+	// Get AI decisions (within the AI spend budgets)
+	aiClient, budgetSkip := at.aiBudgetGate()
+	if budgetSkip != "" {
+		logger.Warnf("[Grid] %s, skipping cycle", budgetSkip)
+		return nil
+	}
+	at.markAICall()
+	decision, err := kernel.GetGridDecisions(gridCtx, aiClient, gridConfig, lang)
+	if decision != nil {
+		at.recordAICharge(aiClient)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to get grid decisions: %w", err)
 	}
@@ -584,6 +596,8 @@ func (at *AutoTrader) saveGridDecisionRecord(decision *kernel.FullDecision) {
 		CoTTrace:            decision.CoTTrace,
 		RawResponse:         decision.RawResponse,
 		AIRequestDurationMs: decision.AIRequestDurationMs,
+		StrategyID:          at.config.StrategyID,
+		StrategyVersion:     at.config.StrategyVersion,
 		Success:             true,
 	}
 
@@ -0,0 +1,31 @@
+package trader
+
+import (
+	"testing"
+
+	"nofx/logger"
+)
+
+func TestCycleLogLeavesBaseScopeUnchanged(t *testing.T) {
+	base := logger.NewScope(logger.Fields{logger.FieldTraderID: "t1", logger.FieldExchange: "binance"})
+	at := &AutoTrader{id: "t1", log: base, callCount: 7}
+
+	at.beginCycleLog()
+	cycle := at.cycleScope().Fields()
+	if cycle[logger.FieldTraderID] != "t1" || cycle[logger.FieldCycle] != 7 || cycle[logger.FieldCycleID] == "" {
+		t.Fatalf("cycle scope fields = %v", cycle)
+	}
+	order := at.cycleScope().With(logger.FieldSymbol, "BTCUSDT").Fields()
+	if order[logger.FieldCycleID] != cycle[logger.FieldCycleID] || order[logger.FieldSymbol] != "BTCUSDT" {
+		t.Fatalf("order scope fields = %v", order)
+	}
+	// The exchange adapter and its sync goroutine log through the base scope
+	if fields := base.Fields(); len(fields) != 2 {
+		t.Fatalf("base scope picked up cycle fields: %v", fields)
+	}
+
+	at.endCycleLog()
+	if at.cycleScope() != base {
+		t.Fatal("between cycles the trader should log through its base scope")
+	}
+}
@@ -1,6 +1,7 @@
 package trader
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,20 +9,39 @@ import (
 	"nofx/logger"
 	"nofx/market"
 	"nofx/mcp/payment"
+	"nofx/metrics"
 	"nofx/provider/hyperliquid"
 	"nofx/store"
+	"nofx/tracing"
 	"nofx/wallet"
 	"strings"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
-// runCycle runs one trading cycle (using AI full decision-making)
+// runCycle runs one trading cycle and reports its duration, outcome and the
+// trader's safe-mode state to metrics. The cycle is one trace: its span is the
+// parent of the market data, AI and exchange spans.
 func (at *AutoTrader) runCycle() error {
 	at.callCount++
+	at.beginCycleLog()
+	defer at.endCycleLog()
+	span := at.startCycleSpan("trader.cycle")
+	start := time.Now()
+	err := at.runDecisionCycle()
+	at.endCycleSpan(span, err)
+	metrics.ObserveCycle(at.id, time.Since(start), err)
+	metrics.SetSafeMode(at.id, at.isSafeMode())
+	return err
+}
 
-	logger.Info("\n" + strings.Repeat("=", 70) + "\n")
-	logger.Infof("⏰ %s - AI decision cycle #%d", time.Now().Format("2006-01-02 15:04:05"), at.callCount)
-	logger.Info(strings.Repeat("=", 70))
+// runDecisionCycle runs one trading cycle (using AI full decision-making)
+func (at *AutoTrader) runDecisionCycle() error {
+	log := at.cycleScope()
+	log.Info("\n" + strings.Repeat("=", 70) + "\n")
+	log.Infof("⏰ %s - AI decision cycle #%d", time.Now().Format("2006-01-02 15:04:05"), at.callCount)
+	log.Info(strings.Repeat("=", 70))
 
 	// 0. Check if trader is stopped (early exit to prevent trades after Stop() is called)
 	at.isRunningMutex.RLock()
@@ -48,7 +68,7 @@ func (at *AutoTrader) runCycle() error {
 	}
 
 	// 1. Check if trading needs to be stopped
-	if time.Now().Before(at.stopUntil) {
+	if time.Now().Before(at.stopUntil) && !at.breakerPauseOpens {
 		remaining := at.stopUntil.Sub(time.Now())
 		at.logWarnf("⏸ Risk control: Trading paused, remaining %.0f minutes", remaining.Minutes())
 		record.Success = false
@@ -63,9 +83,12 @@ func (at *AutoTrader) runCycle() error {
 	if time.Since(at.lastResetTime) > 24*time.Hour {
 		at.dailyPnL = 0
 		at.lastResetTime = time.Now()
-		logger.Info("📅 Daily P&L reset")
+		log.Info("📅 Daily P&L reset")
 	}
 
+	// 3. Book new funding payments so position P&L and the prompt include them
+	at.syncFundingPayments()
+
 	// 4. Collect trading context
 	ctx, err := at.buildTradingContext()
 	if err != nil {
@@ -77,30 +100,36 @@ func (at *AutoTrader) runCycle() error {
 		}
 		return fmt.Errorf("failed to build trading context: %w", err)
 	}
+	record.AccountState, record.Positions = at.cycleSnapshot(ctx)
+
+	metrics.SetTraderAccount(at.id, ctx.Account.TotalEquity, ctx.Account.MarginUsedPct, ctx.Account.PositionCount)
 
 	// Save equity snapshot independently (decoupled from AI decision, used for drawing profit curve)
 	// NOTE: Must be called BEFORE candidate coins check to ensure equity is always recorded
 	at.saveEquitySnapshot(ctx)
 
+	// Account circuit breakers (daily loss, drawdown, losing streak)
+	if at.checkCircuitBreakers(ctx, record) {
+		record.Success = false
+		record.ErrorMessage = "Circuit breaker tripped: " + at.breakerReason
+		if err := at.saveDecision(record); err != nil {
+			at.logWarnf("⚠ Failed to save decision record: %v", err)
+		}
+		return nil
+	}
+
 	// If no candidate coins available, log but do not error
 	if len(ctx.CandidateCoins) == 0 {
 		at.logInfof("ℹ️ No candidate coins available, skipping this cycle")
 		record.Success = true // Not an error, just no candidate coins
 		record.ExecutionLog = append(record.ExecutionLog, "No candidate coins available, cycle skipped")
-		record.AccountState = store.AccountSnapshot{
-			TotalBalance:          ctx.Account.TotalEquity,
-			AvailableBalance:      ctx.Account.AvailableBalance,
-			TotalUnrealizedProfit: ctx.Account.UnrealizedPnL,
-			PositionCount:         ctx.Account.PositionCount,
-			InitialBalance:        at.initialBalance,
-		}
 		if err := at.saveDecision(record); err != nil {
 			at.logWarnf("⚠ Failed to save decision record: %v", err)
 		}
 		return nil
 	}
 
-	logger.Info(strings.Repeat("=", 70))
+	log.Info(strings.Repeat("=", 70))
 	for _, coin := range ctx.CandidateCoins {
 		record.CandidateCoins = append(record.CandidateCoins, coin.Symbol)
 	}
@@ -108,9 +137,23 @@ func (at *AutoTrader) runCycle() error {
 	at.logInfof("📊 Account equity: %.2f USDT | Available: %.2f USDT | Positions: %d",
 		ctx.Account.TotalEquity, ctx.Account.AvailableBalance, ctx.Account.PositionCount)
 
-	// 5. Use strategy engine to call AI for decision
+	// 5. Check AI spend budgets, then use strategy engine to call AI for decision
+	aiClient, budgetSkip := at.aiBudgetGate()
+	if budgetSkip != "" {
+		at.logWarnf("💸 %s, skipping this cycle", budgetSkip)
+		record.ExecutionLog = append(record.ExecutionLog, budgetSkip+", cycle skipped")
+		if err := at.saveDecision(record); err != nil {
+			at.logWarnf("⚠ Failed to save decision record: %v", err)
+		}
+		return nil
+	}
+	if aiClient != at.mcpClient {
+		record.ExecutionLog = append(record.ExecutionLog, "AI budget degraded: using the cheapest fallback model")
+	}
+
 	at.logInfof("🤖 Requesting AI analysis and decision... [Strategy Engine]")
-	aiDecision, err := kernel.GetFullDecisionWithStrategy(ctx, at.mcpClient, at.strategyEngine, "balanced")
+	at.markAICall()
+	aiDecision, err := kernel.GetFullDecisionWithStrategy(ctx, aiClient, at.strategyEngine, "balanced")
 
 	if aiDecision != nil && aiDecision.AIRequestDurationMs > 0 {
 		record.AIRequestDurationMs = aiDecision.AIRequestDurationMs
@@ -125,6 +168,22 @@ func (at *AutoTrader) runCycle() error {
 		record.InputPrompt = aiDecision.UserPrompt
 		record.CoTTrace = aiDecision.CoTTrace
 		record.RawResponse = aiDecision.RawResponse // Save raw AI response for debugging
+		if aiDecision.OutputMode != "" {
+			record.ExecutionLog = append(record.ExecutionLog,
+				fmt.Sprintf("AI output: %s", aiDecision.OutputMode))
+		}
+		record.PromptVariant = aiDecision.PromptVariant
+		if regime := aiDecision.MarketRegime; regime != nil {
+			record.MarketRegime = regime.Regime
+			record.ExecutionLog = append(record.ExecutionLog,
+				fmt.Sprintf("Market regime: %s → %s mode", regime, regime.Variant))
+		}
+		var failedOver bool
+		record.AIProvider, record.AIModel, failedOver = at.answeredAIModel(aiClient)
+		if failedOver {
+			record.ExecutionLog = append(record.ExecutionLog,
+				fmt.Sprintf("AI failover: answered by %s/%s", record.AIProvider, record.AIModel))
+		}
 		if len(aiDecision.Decisions) > 0 {
 			decisionJSON, _ := json.MarshalIndent(aiDecision.Decisions, "", "  ")
 			record.DecisionJSON = string(decisionJSON)
@@ -132,29 +191,8 @@ func (at *AutoTrader) runCycle() error {
 	}
 
 	// Record AI charge (track cost regardless of decision outcome).
-	// Use the effective model name (custom model, e.g. "gpt-5.6") so the
-	// per-call price lookup matches what was actually invoked — at.aiModel is
-	// the provider id (e.g. "claw402") and would fall back to the default price.
-	// Prefer the gateway-reported settled amount (upto scheme) over the flat
-	// catalog estimate when the client exposes it.
-	if aiDecision != nil && at.store != nil {
-		chargeModel := at.config.CustomModelName
-		if chargeModel == "" {
-			chargeModel = at.aiModel
-		}
-		var chargeErr error
-		if r, ok := at.mcpClient.(interface{ LastCallCostUSD() (float64, bool) }); ok {
-			if actual, has := r.LastCallCostUSD(); has {
-				chargeErr = at.store.AICharge().RecordWithCost(at.id, chargeModel, at.config.AIModel, actual)
-			} else {
-				chargeErr = at.store.AICharge().Record(at.id, chargeModel, at.config.AIModel)
-			}
-		} else {
-			chargeErr = at.store.AICharge().Record(at.id, chargeModel, at.config.AIModel)
-		}
-		if chargeErr != nil {
-			at.logWarnf("⚠️ Failed to record AI charge: %v", chargeErr)
-		}
+	if aiDecision != nil {
+		at.recordAICharge(aiClient)
 	}
 
 	if err != nil {
@@ -184,18 +222,18 @@ func (at *AutoTrader) runCycle() error {
 
 		// Print system prompt and AI chain of thought (output even with errors for debugging)
 		if aiDecision != nil {
-			logger.Info("\n" + strings.Repeat("=", 70) + "\n")
-			logger.Infof("📋 System prompt (error case)")
-			logger.Info(strings.Repeat("=", 70))
-			logger.Info(aiDecision.SystemPrompt)
-			logger.Info(strings.Repeat("=", 70))
+			log.Info("\n" + strings.Repeat("=", 70) + "\n")
+			log.Infof("📋 System prompt (error case)")
+			log.Info(strings.Repeat("=", 70))
+			log.Info(aiDecision.SystemPrompt)
+			log.Info(strings.Repeat("=", 70))
 
 			if aiDecision.CoTTrace != "" {
-				logger.Info("\n" + strings.Repeat("-", 70) + "\n")
-				logger.Info("💭 AI chain of thought analysis (error case):")
-				logger.Info(strings.Repeat("-", 70))
-				logger.Info(aiDecision.CoTTrace)
-				logger.Info(strings.Repeat("-", 70))
+				log.Info("\n" + strings.Repeat("-", 70) + "\n")
+				log.Info("💭 AI chain of thought analysis (error case):")
+				log.Info(strings.Repeat("-", 70))
+				log.Info(aiDecision.CoTTrace)
+				log.Info(strings.Repeat("-", 70))
 			}
 		}
 
@@ -245,10 +283,10 @@ func (at *AutoTrader) runCycle() error {
 	//           d.Leverage, d.PositionSizeUSD, d.StopLoss, d.TakeProfit)
 	//     }
 	// }
-	logger.Info()
-	logger.Info(strings.Repeat("-", 70))
+	log.Info()
+	log.Info(strings.Repeat("-", 70))
 	// 8. Sort decisions: ensure close positions first, then open positions (prevent position stacking overflow)
-	logger.Info(strings.Repeat("-", 70))
+	log.Info(strings.Repeat("-", 70))
 
 	// 8. Sort decisions: ensure close positions first, then open positions (prevent position stacking overflow)
 	sortedDecisions := sortDecisionsByPriority(aiDecision.Decisions)
@@ -257,11 +295,11 @@ func (at *AutoTrader) runCycle() error {
 	// the strongest bullish/bearish candidate (account-sized, risk-enforced).
 	sortedDecisions = at.ensureLongShortCoverage(sortedDecisions, ctx, ctx.Account.TotalEquity)
 
-	logger.Info("🔄 Execution order (optimized): Close positions first → Open positions later")
+	log.Info("🔄 Execution order (optimized): Close positions first → Open positions later")
 	for i, d := range sortedDecisions {
-		logger.Infof("  [%d] %s %s", i+1, d.Symbol, d.Action)
+		log.Infof("  [%d] %s %s", i+1, d.Symbol, d.Action)
 	}
-	logger.Info()
+	log.Info()
 
 	// Check if trader is stopped before executing any decisions (prevent trades after Stop())
 	at.isRunningMutex.RLock()
@@ -288,6 +326,21 @@ func (at *AutoTrader) runCycle() error {
 		}
 	}
 
+	// Circuit breaker pause: same filter until the cooldown ends
+	if at.breakerPauseActive() {
+		filtered := make([]kernel.Decision, 0, len(sortedDecisions))
+		for _, d := range sortedDecisions {
+			if d.Action == "open_long" || d.Action == "open_short" {
+				at.logWarnf("⛔ Circuit breaker: BLOCKED %s %s (%s)", d.Action, d.Symbol, at.breakerReason)
+				record.ExecutionLog = append(record.ExecutionLog,
+					fmt.Sprintf("⛔ %s %s blocked: circuit breaker pause until %s", d.Symbol, d.Action, at.stopUntil.Format(time.RFC3339)))
+				continue
+			}
+			filtered = append(filtered, d)
+		}
+		sortedDecisions = filtered
+	}
+
 	// Execute decisions and record results. Trade throttle is applied here,
 	// immediately before order placement, so AI churn cannot become live orders.
 	opensAllowedThisCycle := 0
@@ -326,7 +379,14 @@ func (at *AutoTrader) runCycle() error {
 			opensAllowedThisCycle++
 		}
 
-		if err := at.executeDecisionWithRecord(&d, &actionRecord); err != nil {
+		err := at.executeDecisionWithRecord(log.With(logger.FieldSymbol, d.Symbol), &d, &actionRecord)
+		if isOpenAction(d.Action) || isCloseAction(d.Action) {
+			metrics.ObserveOrder(at.id, at.exchange, err)
+		}
+		if actionRecord.SizingNote != "" {
+			record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("📐 %s %s", d.Symbol, actionRecord.SizingNote))
+		}
+		if err != nil {
 			at.logErrorf("❌ Failed to execute decision (%s %s): %v", d.Symbol, d.Action, err)
 			actionRecord.Error = err.Error()
 			record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("❌ %s %s failed: %v", d.Symbol, d.Action, err))
@@ -458,8 +518,20 @@ func canonicalUniverseSymbolForBase(ctx *kernel.Context, base string) string {
 
 // buildTradingContext builds trading context
 func (at *AutoTrader) buildTradingContext() (*kernel.Context, error) {
+	spanCtx, span := tracing.Start(at.cycleCtx, "trader.buildTradingContext")
+	ctx, err := at.collectTradingContext(spanCtx)
+	tracing.End(span, err)
+	return ctx, err
+}
+
+// collectTradingContext gathers the account, positions, candidates and market
+// data of the trading context; traceCtx parents the exchange and data spans
+func (at *AutoTrader) collectTradingContext(traceCtx context.Context) (*kernel.Context, error) {
+	log := at.cycleScope()
+	exchange := at.tracedIn(traceCtx)
+
 	// 1. Get account information
-	balance, err := at.trader.GetBalance()
+	balance, err := exchange.GetBalance()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get account balance: %w", err)
 	}
@@ -489,7 +561,7 @@ func (at *AutoTrader) buildTradingContext() (*kernel.Context, error) {
 	}
 
 	// 2. Get position information
-	positions, err := at.trader.GetPositions()
+	positions, err := exchange.GetPositions()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get positions: %w", err)
 	}
@@ -534,12 +606,14 @@ func (at *AutoTrader) buildTradingContext() (*kernel.Context, error) {
 		currentPositionKeys[posKey] = true
 
 		var updateTime int64
+		var fundingFee float64
 		// Priority 1: Get from database (trader_positions table) - most accurate
 		if at.store != nil {
 			if dbPos, err := at.store.Position().GetOpenPositionBySymbol(at.id, symbol, side); err == nil && dbPos != nil {
 				if dbPos.EntryTime > 0 {
 					updateTime = dbPos.EntryTime
 				}
+				fundingFee = dbPos.FundingFee
 			}
 		}
 		// Priority 2: Get from exchange API (Bybit: createdTime, OKX: createdTime)
@@ -574,6 +648,7 @@ func (at *AutoTrader) buildTradingContext() (*kernel.Context, error) {
 			LiquidationPrice: liquidationPrice,
 			MarginUsed:       marginUsed,
 			UpdateTime:       updateTime,
+			FundingFee:       fundingFee,
 		})
 	}
 
@@ -589,13 +664,16 @@ func (at *AutoTrader) buildTradingContext() (*kernel.Context, error) {
 	if at.strategyEngine == nil {
 		at.logWarnf("⚠️ No strategy engine configured, skipping candidate coins")
 	} else {
+		_, candidateSpan := tracing.Start(traceCtx, "kernel.GetCandidateCoins")
 		coins, err := at.strategyEngine.GetCandidateCoins()
+		candidateSpan.SetAttributes(attribute.Int("candidates", len(coins)))
+		tracing.End(candidateSpan, err)
 		if err != nil {
 			// Log warning but don't fail - equity snapshot should still be saved
 			at.logWarnf("⚠️ Failed to get candidate coins: %v (will use empty list)", err)
 		} else {
 			candidateCoins = coins
-			logger.Infof("📋 [%s] Strategy engine fetched candidate coins: %d", at.name, len(candidateCoins))
+			log.Infof("📋 [%s] Strategy engine fetched candidate coins: %d", at.name, len(candidateCoins))
 		}
 	}
 
@@ -615,10 +693,12 @@ func (at *AutoTrader) buildTradingContext() (*kernel.Context, error) {
 	strategyConfig := at.strategyEngine.GetConfig()
 	btcEthLeverage := strategyConfig.RiskControl.BTCETHMaxLeverage
 	altcoinLeverage := strategyConfig.RiskControl.AltcoinMaxLeverage
-	logger.Infof("📋 [%s] Strategy leverage config: BTC/ETH=%dx, Altcoin=%dx", at.name, btcEthLeverage, altcoinLeverage)
+	log.Infof("📋 [%s] Strategy leverage config: BTC/ETH=%dx, Altcoin=%dx", at.name, btcEthLeverage, altcoinLeverage)
 
 	// 6. Build context
 	ctx := &kernel.Context{
+		Log:             log,
+		TraceCtx:        at.cycleCtx,
 		CurrentTime:     time.Now().UTC().Format("2006-01-02 15:04:05 UTC"),
 		RuntimeMinutes:  int(time.Since(at.startTime).Minutes()),
 		CallCount:       at.callCount,
@@ -645,7 +725,7 @@ func (at *AutoTrader) buildTradingContext() (*kernel.Context, error) {
 		if err != nil {
 			at.logWarnf("⚠️ Failed to get recent trades: %v", err)
 		} else {
-			logger.Infof("📊 [%s] Found %d recent closed trades for AI context", at.name, len(recentTrades))
+			log.Infof("📊 [%s] Found %d recent closed trades for AI context", at.name, len(recentTrades))
 			for _, trade := range recentTrades {
 				// Convert Unix timestamps to formatted strings for AI readability
 				entryTimeStr := ""
@@ -689,7 +769,7 @@ func (at *AutoTrader) buildTradingContext() (*kernel.Context, error) {
 				AvgLoss:        stats.AvgLoss,
 				MaxDrawdownPct: stats.MaxDrawdownPct,
 			}
-			logger.Infof("📈 [%s] Trading stats: %d trades, %.1f%% win rate, PF=%.2f, Sharpe=%.2f, DD=%.1f%%",
+			log.Infof("📈 [%s] Trading stats: %d trades, %.1f%% win rate, PF=%.2f, Sharpe=%.2f, DD=%.1f%%",
 				at.name, stats.TotalTrades, stats.WinRate, stats.ProfitFactor, stats.SharpeRatio, stats.MaxDrawdownPct)
 		}
 	} else {
@@ -712,37 +792,37 @@ func (at *AutoTrader) buildTradingContext() (*kernel.Context, error) {
 			symbols = append(symbols, sym)
 		}
 
-		logger.Infof("📊 [%s] Fetching quantitative data for %d symbols...", at.name, len(symbols))
-		ctx.QuantDataMap = at.strategyEngine.FetchQuantDataBatch(symbols)
-		logger.Infof("📊 [%s] Successfully fetched quantitative data for %d symbols", at.name, len(ctx.QuantDataMap))
+		log.Infof("📊 [%s] Fetching quantitative data for %d symbols...", at.name, len(symbols))
+		ctx.QuantDataMap = at.strategyEngine.FetchQuantDataBatch(traceCtx, symbols)
+		log.Infof("📊 [%s] Successfully fetched quantitative data for %d symbols", at.name, len(ctx.QuantDataMap))
 	}
 
 	// 9. Get OI ranking data (market-wide position changes)
 	if strategyConfig.Indicators.EnableOIRanking {
-		logger.Infof("📊 [%s] Fetching OI ranking data...", at.name)
+		log.Infof("📊 [%s] Fetching OI ranking data...", at.name)
 		ctx.OIRankingData = at.strategyEngine.FetchOIRankingData()
 		if ctx.OIRankingData != nil {
-			logger.Infof("📊 [%s] OI ranking data ready: %d top, %d low positions",
+			log.Infof("📊 [%s] OI ranking data ready: %d top, %d low positions",
 				at.name, len(ctx.OIRankingData.TopPositions), len(ctx.OIRankingData.LowPositions))
 		}
 	}
 
 	// 10. Get NetFlow ranking data (market-wide fund flow)
 	if strategyConfig.Indicators.EnableNetFlowRanking {
-		logger.Infof("💰 [%s] Fetching NetFlow ranking data...", at.name)
+		log.Infof("💰 [%s] Fetching NetFlow ranking data...", at.name)
 		ctx.NetFlowRankingData = at.strategyEngine.FetchNetFlowRankingData()
 		if ctx.NetFlowRankingData != nil {
-			logger.Infof("💰 [%s] NetFlow ranking data ready: inst_in=%d, inst_out=%d",
+			log.Infof("💰 [%s] NetFlow ranking data ready: inst_in=%d, inst_out=%d",
 				at.name, len(ctx.NetFlowRankingData.InstitutionFutureTop), len(ctx.NetFlowRankingData.InstitutionFutureLow))
 		}
 	}
 
 	// 11. Get Price ranking data (market-wide gainers/losers)
 	if strategyConfig.Indicators.EnablePriceRanking {
-		logger.Infof("📈 [%s] Fetching Price ranking data...", at.name)
+		log.Infof("📈 [%s] Fetching Price ranking data...", at.name)
 		ctx.PriceRankingData = at.strategyEngine.FetchPriceRankingData()
 		if ctx.PriceRankingData != nil {
-			logger.Infof("📈 [%s] Price ranking data ready for %d durations",
+			log.Infof("📈 [%s] Price ranking data ready for %d durations",
 				at.name, len(ctx.PriceRankingData.Durations))
 		}
 	}
@@ -794,7 +874,7 @@ func (at *AutoTrader) checkClaw402Balance() {
 		scanMinutes = 15
 	}
 	dailyCost, _ := store.EstimateRunway(1.0, at.config.CustomModelName, scanMinutes)
-	logger.Infof("💰 [%s] Estimated daily AI cost: ~$%.2f (model: %s, interval: %dm)",
+	at.cycleScope().Infof("💰 [%s] Estimated daily AI cost: ~$%.2f (model: %s, interval: %dm)",
 		at.name, dailyCost, at.config.CustomModelName, scanMinutes)
 
 	if at.claw402WalletAddr != "" {
@@ -817,7 +897,7 @@ func (at *AutoTrader) checkClaw402Balance() {
 		if dailyCost > 0 {
 			runway = balance / dailyCost
 		}
-		logger.Infof("💰 [%s] USDC Balance: $%.2f | Daily AI cost: ~$%.2f | Runway: ~%.1f days",
+		at.cycleScope().Infof("💰 [%s] USDC Balance: $%.2f | Daily AI cost: ~$%.2f | Runway: ~%.1f days",
 			at.name, balance, dailyCost, runway)
 	}
 }
@@ -24,16 +24,16 @@ const (
 )
 
 // executeDecisionWithRecord executes AI decision and records detailed information
-func (at *AutoTrader) executeDecisionWithRecord(decision *kernel.Decision, actionRecord *store.DecisionAction) error {
+func (at *AutoTrader) executeDecisionWithRecord(log *logger.Scope, decision *kernel.Decision, actionRecord *store.DecisionAction) error {
 	switch decision.Action {
 	case "open_long":
-		return at.executeOpenLongWithRecord(decision, actionRecord)
+		return at.executeOpenLongWithRecord(log, decision, actionRecord)
 	case "open_short":
-		return at.executeOpenShortWithRecord(decision, actionRecord)
+		return at.executeOpenShortWithRecord(log, decision, actionRecord)
 	case "close_long":
-		return at.executeCloseLongWithRecord(decision, actionRecord)
+		return at.executeCloseLongWithRecord(log, decision, actionRecord)
 	case "close_short":
-		return at.executeCloseShortWithRecord(decision, actionRecord)
+		return at.executeCloseShortWithRecord(log, decision, actionRecord)
 	case "hold", "wait":
 		// No execution needed, just record
 		return nil
@@ -43,17 +43,17 @@ func (at *AutoTrader) executeDecisionWithRecord(decision *kernel.Decision, actio
 }
 
 // executeOpenLongWithRecord executes open long position and records detailed information
-func (at *AutoTrader) executeOpenLongWithRecord(decision *kernel.Decision, actionRecord *store.DecisionAction) error {
-	logger.Infof("  📈 Open long: %s", decision.Symbol)
+func (at *AutoTrader) executeOpenLongWithRecord(log *logger.Scope, decision *kernel.Decision, actionRecord *store.DecisionAction) error {
+	log.Infof("  📈 Open long: %s", decision.Symbol)
 
 	// ⚠️ Get current positions for multiple checks
-	positions, err := at.trader.GetPositions()
+	positions, err := at.traced().GetPositions()
 	if err != nil {
 		return fmt.Errorf("failed to get positions: %w", err)
 	}
 
 	// [CODE ENFORCED] Check max positions limit
-	if err := at.enforceMaxPositions(len(positions)); err != nil {
+	if err := at.enforceMaxPositions(positions, decision.Symbol); err != nil {
 		return err
 	}
 
@@ -71,7 +71,7 @@ func (at *AutoTrader) executeOpenLongWithRecord(decision *kernel.Decision, actio
 	}
 
 	// Get balance (needed for multiple checks)
-	balance, err := at.trader.GetBalance()
+	balance, err := at.traced().GetBalance()
 	if err != nil {
 		return fmt.Errorf("failed to get account balance: %w", err)
 	}
@@ -92,8 +92,13 @@ func (at *AutoTrader) executeOpenLongWithRecord(decision *kernel.Decision, actio
 
 	at.applyAutopilotFullSizeOpen(decision, equity)
 
+	// [CODE ENFORCED] Position sizing mode: the AI size is an upper bound or a hint
+	if err := at.applyPositionSizing(log, decision, equity, marketData, actionRecord); err != nil {
+		return err
+	}
+
 	// [CODE ENFORCED] Position Value Ratio Check: position_value <= equity × ratio
-	adjustedPositionSize, wasCapped := at.enforcePositionValueRatio(decision.PositionSizeUSD, equity, decision.Symbol)
+	adjustedPositionSize, wasCapped := at.enforcePositionValueRatio(log, decision.PositionSizeUSD, equity, decision.Symbol)
 	if wasCapped {
 		decision.PositionSizeUSD = adjustedPositionSize
 	}
@@ -105,14 +110,14 @@ func (at *AutoTrader) executeOpenLongWithRecord(decision *kernel.Decision, actio
 	actualPositionSize := decision.PositionSizeUSD
 	if actualPositionSize > maxAffordablePositionSize {
 		adjustedSize := maxAffordablePositionSize * positionSizeSafetyFactor
-		logger.Infof("  ⚠️ Position size %.2f exceeds max affordable %.2f, auto-reducing to %.2f",
+		log.Infof("  ⚠️ Position size %.2f exceeds max affordable %.2f, auto-reducing to %.2f",
 			actualPositionSize, maxAffordablePositionSize, adjustedSize)
 		actualPositionSize = adjustedSize
 		decision.PositionSizeUSD = actualPositionSize
 	}
 
 	// [CODE ENFORCED] Minimum position size check
-	if err := at.enforceMinPositionSize(decision.PositionSizeUSD); err != nil {
+	if err := at.enforceMinPositionSize(decision.PositionSizeUSD, decision.Symbol); err != nil {
 		return err
 	}
 
@@ -122,13 +127,13 @@ func (at *AutoTrader) executeOpenLongWithRecord(decision *kernel.Decision, actio
 	actionRecord.Price = marketData.CurrentPrice
 
 	// Set margin mode
-	if err := at.trader.SetMarginMode(decision.Symbol, at.config.IsCrossMargin); err != nil {
-		logger.Infof("  ⚠️ Failed to set margin mode: %v", err)
+	if err := at.traced().SetMarginMode(decision.Symbol, at.config.IsCrossMargin); err != nil {
+		log.Infof("  ⚠️ Failed to set margin mode: %v", err)
 		// Continue execution, doesn't affect trading
 	}
 
 	// Open position
-	order, err := at.trader.OpenLong(decision.Symbol, quantity, decision.Leverage)
+	order, err := at.traced().OpenLong(decision.Symbol, quantity, decision.Leverage)
 	if err != nil {
 		return fmt.Errorf("failed to open long position for %s: %w", decision.Symbol, err)
 	}
@@ -138,38 +143,38 @@ func (at *AutoTrader) executeOpenLongWithRecord(decision *kernel.Decision, actio
 		actionRecord.OrderID = orderID
 	}
 
-	logger.Infof("  ✓ Position opened successfully, order ID: %v, quantity: %.4f", order["orderId"], quantity)
+	log.Infof("  ✓ Position opened successfully, order ID: %v, quantity: %.4f", order["orderId"], quantity)
 
 	// Record order to database and poll for confirmation
-	at.recordAndConfirmOrder(order, decision.Symbol, "open_long", quantity, marketData.CurrentPrice, decision.Leverage, 0)
+	at.recordAndConfirmOrder(log, order, decision.Symbol, "open_long", quantity, marketData.CurrentPrice, decision.Leverage, 0)
 
 	// Record position opening time
 	posKey := decision.Symbol + "_long"
 	at.positionFirstSeenTime[posKey] = time.Now().UnixMilli()
 
 	// Set stop loss and take profit
-	if err := at.trader.SetStopLoss(decision.Symbol, "LONG", quantity, decision.StopLoss); err != nil {
-		logger.Infof("  ⚠ Failed to set stop loss: %v", err)
+	if err := at.traced().SetStopLoss(decision.Symbol, "LONG", quantity, decision.StopLoss); err != nil {
+		log.Infof("  ⚠ Failed to set stop loss: %v", err)
 	}
-	if err := at.trader.SetTakeProfit(decision.Symbol, "LONG", quantity, decision.TakeProfit); err != nil {
-		logger.Infof("  ⚠ Failed to set take profit: %v", err)
+	if err := at.traced().SetTakeProfit(decision.Symbol, "LONG", quantity, decision.TakeProfit); err != nil {
+		log.Infof("  ⚠ Failed to set take profit: %v", err)
 	}
 
 	return nil
 }
 
 // executeOpenShortWithRecord executes open short position and records detailed information
-func (at *AutoTrader) executeOpenShortWithRecord(decision *kernel.Decision, actionRecord *store.DecisionAction) error {
-	logger.Infof("  📉 Open short: %s", decision.Symbol)
+func (at *AutoTrader) executeOpenShortWithRecord(log *logger.Scope, decision *kernel.Decision, actionRecord *store.DecisionAction) error {
+	log.Infof("  📉 Open short: %s", decision.Symbol)
 
 	// ⚠️ Get current positions for multiple checks
-	positions, err := at.trader.GetPositions()
+	positions, err := at.traced().GetPositions()
 	if err != nil {
 		return fmt.Errorf("failed to get positions: %w", err)
 	}
 
 	// [CODE ENFORCED] Check max positions limit
-	if err := at.enforceMaxPositions(len(positions)); err != nil {
+	if err := at.enforceMaxPositions(positions, decision.Symbol); err != nil {
 		return err
 	}
 
@@ -187,7 +192,7 @@ func (at *AutoTrader) executeOpenShortWithRecord(decision *kernel.Decision, acti
 	}
 
 	// Get balance (needed for multiple checks)
-	balance, err := at.trader.GetBalance()
+	balance, err := at.traced().GetBalance()
 	if err != nil {
 		return fmt.Errorf("failed to get account balance: %w", err)
 	}
@@ -208,8 +213,13 @@ func (at *AutoTrader) executeOpenShortWithRecord(decision *kernel.Decision, acti
 
 	at.applyAutopilotFullSizeOpen(decision, equity)
 
+	// [CODE ENFORCED] Position sizing mode: the AI size is an upper bound or a hint
+	if err := at.applyPositionSizing(log, decision, equity, marketData, actionRecord); err != nil {
+		return err
+	}
+
 	// [CODE ENFORCED] Position Value Ratio Check: position_value <= equity × ratio
-	adjustedPositionSize, wasCapped := at.enforcePositionValueRatio(decision.PositionSizeUSD, equity, decision.Symbol)
+	adjustedPositionSize, wasCapped := at.enforcePositionValueRatio(log, decision.PositionSizeUSD, equity, decision.Symbol)
 	if wasCapped {
 		decision.PositionSizeUSD = adjustedPositionSize
 	}
@@ -221,14 +231,14 @@ func (at *AutoTrader) executeOpenShortWithRecord(decision *kernel.Decision, acti
 	actualPositionSize := decision.PositionSizeUSD
 	if actualPositionSize > maxAffordablePositionSize {
 		adjustedSize := maxAffordablePositionSize * positionSizeSafetyFactor
-		logger.Infof("  ⚠️ Position size %.2f exceeds max affordable %.2f, auto-reducing to %.2f",
+		log.Infof("  ⚠️ Position size %.2f exceeds max affordable %.2f, auto-reducing to %.2f",
 			actualPositionSize, maxAffordablePositionSize, adjustedSize)
 		actualPositionSize = adjustedSize
 		decision.PositionSizeUSD = actualPositionSize
 	}
 
 	// [CODE ENFORCED] Minimum position size check
-	if err := at.enforceMinPositionSize(decision.PositionSizeUSD); err != nil {
+	if err := at.enforceMinPositionSize(decision.PositionSizeUSD, decision.Symbol); err != nil {
 		return err
 	}
 
@@ -238,13 +248,13 @@ func (at *AutoTrader) executeOpenShortWithRecord(decision *kernel.Decision, acti
 	actionRecord.Price = marketData.CurrentPrice
 
 	// Set margin mode
-	if err := at.trader.SetMarginMode(decision.Symbol, at.config.IsCrossMargin); err != nil {
-		logger.Infof("  ⚠️ Failed to set margin mode: %v", err)
+	if err := at.traced().SetMarginMode(decision.Symbol, at.config.IsCrossMargin); err != nil {
+		log.Infof("  ⚠️ Failed to set margin mode: %v", err)
 		// Continue execution, doesn't affect trading
 	}
 
 	// Open position
-	order, err := at.trader.OpenShort(decision.Symbol, quantity, decision.Leverage)
+	order, err := at.traced().OpenShort(decision.Symbol, quantity, decision.Leverage)
 	if err != nil {
 		return fmt.Errorf("failed to open short position for %s: %w", decision.Symbol, err)
 	}
@@ -254,29 +264,29 @@ func (at *AutoTrader) executeOpenShortWithRecord(decision *kernel.Decision, acti
 		actionRecord.OrderID = orderID
 	}
 
-	logger.Infof("  ✓ Position opened successfully, order ID: %v, quantity: %.4f", order["orderId"], quantity)
+	log.Infof("  ✓ Position opened successfully, order ID: %v, quantity: %.4f", order["orderId"], quantity)
 
 	// Record order to database and poll for confirmation
-	at.recordAndConfirmOrder(order, decision.Symbol, "open_short", quantity, marketData.CurrentPrice, decision.Leverage, 0)
+	at.recordAndConfirmOrder(log, order, decision.Symbol, "open_short", quantity, marketData.CurrentPrice, decision.Leverage, 0)
 
 	// Record position opening time
 	posKey := decision.Symbol + "_short"
 	at.positionFirstSeenTime[posKey] = time.Now().UnixMilli()
 
 	// Set stop loss and take profit
-	if err := at.trader.SetStopLoss(decision.Symbol, "SHORT", quantity, decision.StopLoss); err != nil {
-		logger.Infof("  ⚠ Failed to set stop loss: %v", err)
+	if err := at.traced().SetStopLoss(decision.Symbol, "SHORT", quantity, decision.StopLoss); err != nil {
+		log.Infof("  ⚠ Failed to set stop loss: %v", err)
 	}
-	if err := at.trader.SetTakeProfit(decision.Symbol, "SHORT", quantity, decision.TakeProfit); err != nil {
-		logger.Infof("  ⚠ Failed to set take profit: %v", err)
+	if err := at.traced().SetTakeProfit(decision.Symbol, "SHORT", quantity, decision.TakeProfit); err != nil {
+		log.Infof("  ⚠ Failed to set take profit: %v", err)
 	}
 
 	return nil
 }
 
 // executeCloseLongWithRecord executes close long position and records detailed information
-func (at *AutoTrader) executeCloseLongWithRecord(decision *kernel.Decision, actionRecord *store.DecisionAction) error {
-	logger.Infof("  🔄 Close long: %s", decision.Symbol)
+func (at *AutoTrader) executeCloseLongWithRecord(log *logger.Scope, decision *kernel.Decision, actionRecord *store.DecisionAction) error {
+	log.Infof("  🔄 Close long: %s", decision.Symbol)
 
 	// Get current price
 	marketData, err := market.GetWithExchange(decision.Symbol, at.exchange)
@@ -297,13 +307,13 @@ func (at *AutoTrader) executeCloseLongWithRecord(decision *kernel.Decision, acti
 		if openPos, err := at.store.Position().GetOpenPositionBySymbol(at.id, normalizedSymbol, "LONG"); err == nil && openPos != nil {
 			quantity = openPos.Quantity
 			entryPrice = openPos.EntryPrice
-			logger.Infof("  📊 Using local position data: qty=%.8f, entry=%.2f", quantity, entryPrice)
+			log.Infof("  📊 Using local position data: qty=%.8f, entry=%.2f", quantity, entryPrice)
 		}
 	}
 
 	// Fallback to exchange API if local data not found
 	if quantity == 0 {
-		positions, err := at.trader.GetPositions()
+		positions, err := at.traced().GetPositions()
 		if err == nil {
 			for _, pos := range positions {
 				if pos["symbol"] == decision.Symbol && pos["side"] == "long" {
@@ -317,11 +327,11 @@ func (at *AutoTrader) executeCloseLongWithRecord(decision *kernel.Decision, acti
 				}
 			}
 		}
-		logger.Infof("  📊 Using exchange position data: qty=%.8f, entry=%.2f", quantity, entryPrice)
+		log.Infof("  📊 Using exchange position data: qty=%.8f, entry=%.2f", quantity, entryPrice)
 	}
 
 	// Close position
-	order, err := at.trader.CloseLong(decision.Symbol, 0) // 0 = close all
+	order, err := at.traced().CloseLong(decision.Symbol, 0) // 0 = close all
 	if err != nil {
 		return fmt.Errorf("failed to close long position for %s: %w", decision.Symbol, err)
 	}
@@ -332,15 +342,15 @@ func (at *AutoTrader) executeCloseLongWithRecord(decision *kernel.Decision, acti
 	}
 
 	// Record order to database and poll for confirmation
-	at.recordAndConfirmOrder(order, decision.Symbol, "close_long", quantity, marketData.CurrentPrice, 0, entryPrice)
+	at.recordAndConfirmOrder(log, order, decision.Symbol, "close_long", quantity, marketData.CurrentPrice, 0, entryPrice)
 
-	logger.Infof("  ✓ Position closed successfully")
+	log.Infof("  ✓ Position closed successfully")
 	return nil
 }
 
 // executeCloseShortWithRecord executes close short position and records detailed information
-func (at *AutoTrader) executeCloseShortWithRecord(decision *kernel.Decision, actionRecord *store.DecisionAction) error {
-	logger.Infof("  🔄 Close short: %s", decision.Symbol)
+func (at *AutoTrader) executeCloseShortWithRecord(log *logger.Scope, decision *kernel.Decision, actionRecord *store.DecisionAction) error {
+	log.Infof("  🔄 Close short: %s", decision.Symbol)
 
 	// Get current price
 	marketData, err := market.GetWithExchange(decision.Symbol, at.exchange)
@@ -361,13 +371,13 @@ func (at *AutoTrader) executeCloseShortWithRecord(decision *kernel.Decision, act
 		if openPos, err := at.store.Position().GetOpenPositionBySymbol(at.id, normalizedSymbol, "SHORT"); err == nil && openPos != nil {
 			quantity = openPos.Quantity
 			entryPrice = openPos.EntryPrice
-			logger.Infof("  📊 Using local position data: qty=%.8f, entry=%.2f", quantity, entryPrice)
+			log.Infof("  📊 Using local position data: qty=%.8f, entry=%.2f", quantity, entryPrice)
 		}
 	}
 
 	// Fallback to exchange API if local data not found
 	if quantity == 0 {
-		positions, err := at.trader.GetPositions()
+		positions, err := at.traced().GetPositions()
 		if err == nil {
 			for _, pos := range positions {
 				if pos["symbol"] == decision.Symbol && pos["side"] == "short" {
@@ -381,11 +391,11 @@ func (at *AutoTrader) executeCloseShortWithRecord(decision *kernel.Decision, act
 				}
 			}
 		}
-		logger.Infof("  📊 Using exchange position data: qty=%.8f, entry=%.2f", quantity, entryPrice)
+		log.Infof("  📊 Using exchange position data: qty=%.8f, entry=%.2f", quantity, entryPrice)
 	}
 
 	// Close position
-	order, err := at.trader.CloseShort(decision.Symbol, 0) // 0 = close all
+	order, err := at.traced().CloseShort(decision.Symbol, 0) // 0 = close all
 	if err != nil {
 		return fmt.Errorf("failed to close short position for %s: %w", decision.Symbol, err)
 	}
@@ -396,8 +406,8 @@ func (at *AutoTrader) executeCloseShortWithRecord(decision *kernel.Decision, act
 	}
 
 	// Record order to database and poll for confirmation
-	at.recordAndConfirmOrder(order, decision.Symbol, "close_short", quantity, marketData.CurrentPrice, 0, entryPrice)
+	at.recordAndConfirmOrder(log, order, decision.Symbol, "close_short", quantity, marketData.CurrentPrice, 0, entryPrice)
 
-	logger.Infof("  ✓ Position closed successfully")
+	log.Infof("  ✓ Position closed successfully")
 	return nil
 }
@@ -1,12 +1,11 @@
 package trader
 
 import (
+	"context"
 	"fmt"
 	"nofx/kernel"
 	"nofx/logger"
-	"nofx/market"
 	"nofx/store"
-	"strings"
 	"time"
 )
 
@@ -54,7 +53,7 @@ func (at *AutoTrader) checkPositionDrawdown() {
 	// Get current positions
 	positions, err := at.trader.GetPositions()
 	if err != nil {
-		logger.Infof("❌ Drawdown monitoring: failed to get positions: %v", err)
+		at.log.Infof("❌ Drawdown monitoring: failed to get positions: %v", err)
 		return
 	}
 
@@ -70,7 +69,7 @@ func (at *AutoTrader) checkPositionDrawdown() {
 
 		// Guard: skip if entry price is zero (prevents division by zero panic)
 		if entryPrice <= 0 {
-			logger.Warnf("⚠️ Drawdown monitoring: %s %s has zero entry price, skipping", symbol, side)
+			at.log.Warnf("⚠️ Drawdown monitoring: %s %s has zero entry price, skipping", symbol, side)
 			continue
 		}
 
@@ -117,20 +116,20 @@ func (at *AutoTrader) checkPositionDrawdown() {
 
 		// Check close position condition: price move > +5% and drawdown >= 40%
 		if shouldDrawdownClose(pricePnLPct, drawdownPct) {
-			logger.Infof("🚨 Drawdown close position condition triggered: %s %s | Price move: %.2f%% | Current profit: %.2f%% | Peak profit: %.2f%% | Drawdown: %.2f%%",
+			at.log.Infof("🚨 Drawdown close position condition triggered: %s %s | Price move: %.2f%% | Current profit: %.2f%% | Peak profit: %.2f%% | Drawdown: %.2f%%",
 				symbol, side, pricePnLPct, currentPnLPct, peakPnLPct, drawdownPct)
 
 			// Execute close position
 			if err := at.emergencyClosePosition(symbol, side); err != nil {
-				logger.Infof("❌ Drawdown close position failed (%s %s): %v", symbol, side, err)
+				at.log.Infof("❌ Drawdown close position failed (%s %s): %v", symbol, side, err)
 			} else {
-				logger.Infof("✅ Drawdown close position succeeded: %s %s", symbol, side)
+				at.log.Infof("✅ Drawdown close position succeeded: %s %s", symbol, side)
 				// Clear cache for this position after closing
 				at.ClearPeakPnLCache(symbol, side)
 			}
 		} else if pricePnLPct > drawdownClosePriceGainPct {
 			// Record situations close to close position condition (for debugging)
-			logger.Infof("📊 Drawdown monitoring: %s %s | Price move: %.2f%% | Profit: %.2f%% | Peak: %.2f%% | Drawdown: %.2f%%",
+			at.log.Infof("📊 Drawdown monitoring: %s %s | Price move: %.2f%% | Profit: %.2f%% | Peak: %.2f%% | Drawdown: %.2f%%",
 				symbol, side, pricePnLPct, currentPnLPct, peakPnLPct, drawdownPct)
 		}
 	}
@@ -140,17 +139,17 @@ func (at *AutoTrader) checkPositionDrawdown() {
 func (at *AutoTrader) emergencyClosePosition(symbol, side string) error {
 	switch side {
 	case "long":
-		order, err := at.trader.CloseLong(symbol, 0) // 0 = close all
+		order, err := at.tracedIn(context.Background()).CloseLong(symbol, 0) // 0 = close all
 		if err != nil {
 			return err
 		}
-		logger.Infof("✅ Emergency close long position succeeded, order ID: %v", order["orderId"])
+		at.log.Infof("✅ Emergency close long position succeeded, order ID: %v", order["orderId"])
 	case "short":
-		order, err := at.trader.CloseShort(symbol, 0) // 0 = close all
+		order, err := at.tracedIn(context.Background()).CloseShort(symbol, 0) // 0 = close all
 		if err != nil {
 			return err
 		}
-		logger.Infof("✅ Emergency close short position succeeded, order ID: %v", order["orderId"])
+		at.log.Infof("✅ Emergency close short position succeeded, order ID: %v", order["orderId"])
 	default:
 		return fmt.Errorf("unknown position direction: %s", side)
 	}
@@ -201,22 +200,13 @@ func (at *AutoTrader) ClearPeakPnLCache(symbol, side string) {
 // Risk Control Helpers
 // ============================================================================
 
-// isBTCETH checks if a symbol is BTC or ETH
-func isBTCETH(symbol string) bool {
-	symbol = strings.ToUpper(symbol)
-	return strings.HasPrefix(symbol, "BTC") || strings.HasPrefix(symbol, "ETH")
-}
-
-// isMajorAsset returns true for assets that should use the BTC/ETH higher
-// position-value tier rather than the altcoin (1x equity) tier. This covers
-// BTC/ETH crypto perps AND Hyperliquid XYZ assets (US equities, commodities,
-// forex) — none of which are "altcoins" and all of which deserve the higher
-// per-position cap so the AI can actually take meaningful positions.
-func isMajorAsset(symbol string) bool {
-	if isBTCETH(symbol) {
-		return true
-	}
-	return market.IsXyzDexAsset(symbol)
+// assetTier returns the asset tier whose limits apply to symbol. Without
+// configured tiers, BTC/ETH crypto perps AND Hyperliquid XYZ assets (US
+// equities, commodities, forex) use the BTC/ETH tier — none of those are
+// "altcoins" and all of them deserve the higher per-position cap — and
+// everything else uses the altcoin tier.
+func (at *AutoTrader) assetTier(symbol string) store.AssetTierConfig {
+	return kernel.ResolveAssetTier(at.config.StrategyConfig.RiskControl, symbol)
 }
 
 // enforcePositionValueRatio checks and enforces position value ratio limits (CODE ENFORCED)
@@ -224,36 +214,23 @@ func isMajorAsset(symbol string) bool {
 // positionSizeUSD: the original position size in USD
 // equity: the account equity
 // symbol: the trading symbol
-func (at *AutoTrader) enforcePositionValueRatio(positionSizeUSD float64, equity float64, symbol string) (float64, bool) {
+func (at *AutoTrader) enforcePositionValueRatio(log *logger.Scope, positionSizeUSD float64, equity float64, symbol string) (float64, bool) {
 	if at.config.StrategyConfig == nil {
 		return positionSizeUSD, false
 	}
 
-	riskControl := at.config.StrategyConfig.RiskControl
-
-	// Get the appropriate position value ratio limit. BTC/ETH AND Hyperliquid
-	// XYZ assets (US stocks etc.) use the higher tier; pure altcoins use the
-	// lower tier.
-	var maxPositionValueRatio float64
-	if isMajorAsset(symbol) {
-		maxPositionValueRatio = riskControl.BTCETHMaxPositionValueRatio
-		if maxPositionValueRatio <= 0 {
-			maxPositionValueRatio = 5.0 // Default: 5x for BTC/ETH and XYZ assets
-		}
-	} else {
-		maxPositionValueRatio = riskControl.AltcoinMaxPositionValueRatio
-		if maxPositionValueRatio <= 0 {
-			maxPositionValueRatio = 1.0 // Default: 1x for altcoins
-		}
-	}
+	// Get the position value ratio limit of the symbol's asset tier
+	// (defaults: 5x for BTC/ETH and XYZ assets, 1x for altcoins)
+	tier := at.assetTier(symbol)
+	maxPositionValueRatio := tier.MaxPositionValueRatio
 
 	// Calculate max allowed position value = equity × ratio
 	maxPositionValue := equity * maxPositionValueRatio
 
 	// Check if position size exceeds limit
 	if positionSizeUSD > maxPositionValue {
-		logger.Infof("  ⚠️ [RISK CONTROL] Position %.2f USDT exceeds limit (equity %.2f × %.1fx = %.2f USDT max for %s), capping",
-			positionSizeUSD, equity, maxPositionValueRatio, maxPositionValue, symbol)
+		log.Infof("  ⚠️ [RISK CONTROL] Position %.2f USDT exceeds limit (equity %.2f × %.1fx = %.2f USDT max for %s, %s tier), capping",
+			positionSizeUSD, equity, maxPositionValueRatio, maxPositionValue, symbol, tier.Name)
 		return maxPositionValue, true
 	}
 
@@ -270,13 +247,9 @@ func (at *AutoTrader) applyAutopilotFullSizeOpen(decision *kernel.Decision, equi
 		return
 	}
 
-	riskControl := cfg.RiskControl
-	leverage := riskControl.AltcoinMaxLeverage
-	positionValueRatio := riskControl.AltcoinMaxPositionValueRatio
-	if isMajorAsset(decision.Symbol) {
-		leverage = riskControl.BTCETHMaxLeverage
-		positionValueRatio = riskControl.BTCETHMaxPositionValueRatio
-	}
+	tier := at.assetTier(decision.Symbol)
+	leverage := tier.MaxLeverage
+	positionValueRatio := tier.MaxPositionValueRatio
 	if leverage < store.MinLeverage {
 		leverage = store.MinLeverage
 	}
@@ -293,15 +266,16 @@ func (at *AutoTrader) applyAutopilotFullSizeOpen(decision *kernel.Decision, equi
 	}
 
 	if decision.Leverage != leverage || decision.PositionSizeUSD != fullPositionSize {
-		logger.Infof("  📏 [AUTOPILOT] Full-size open enforced for %s: leverage %dx → %dx, notional %.2f → %.2f USDT",
+		at.cycleScope().Infof("  📏 [AUTOPILOT] Full-size open enforced for %s: leverage %dx → %dx, notional %.2f → %.2f USDT",
 			decision.Symbol, decision.Leverage, leverage, decision.PositionSizeUSD, fullPositionSize)
 	}
 	decision.Leverage = leverage
 	decision.PositionSizeUSD = fullPositionSize
 }
 
-// enforceMinPositionSize checks minimum position size (CODE ENFORCED)
-func (at *AutoTrader) enforceMinPositionSize(positionSizeUSD float64) error {
+// enforceMinPositionSize checks minimum position size, the higher of the
+// global and the asset tier minimum (CODE ENFORCED)
+func (at *AutoTrader) enforceMinPositionSize(positionSizeUSD float64, symbol string) error {
 	if at.config.StrategyConfig == nil {
 		return nil
 	}
@@ -310,6 +284,9 @@ func (at *AutoTrader) enforceMinPositionSize(positionSizeUSD float64) error {
 	if minSize <= 0 {
 		minSize = 12 // Default: 12 USDT
 	}
+	if tierMin := at.assetTier(symbol).MinPositionSize; tierMin > minSize {
+		minSize = tierMin
+	}
 
 	if positionSizeUSD < minSize {
 		return fmt.Errorf("❌ [RISK CONTROL] Position %.2f USDT below minimum (%.2f USDT)", positionSizeUSD, minSize)
@@ -317,8 +294,9 @@ func (at *AutoTrader) enforceMinPositionSize(positionSizeUSD float64) error {
 	return nil
 }
 
-// enforceMaxPositions checks maximum positions count (CODE ENFORCED)
-func (at *AutoTrader) enforceMaxPositions(currentPositionCount int) error {
+// enforceMaxPositions checks the maximum positions count, overall and in
+// the asset tier of the symbol to open (CODE ENFORCED)
+func (at *AutoTrader) enforceMaxPositions(positions []map[string]interface{}, symbol string) error {
 	if at.config.StrategyConfig == nil {
 		return nil
 	}
@@ -328,8 +306,22 @@ func (at *AutoTrader) enforceMaxPositions(currentPositionCount int) error {
 		maxPositions = 3 // Default: 3 positions
 	}
 
-	if currentPositionCount >= maxPositions {
-		return fmt.Errorf("❌ [RISK CONTROL] Already at max positions (%d/%d)", currentPositionCount, maxPositions)
+	if len(positions) >= maxPositions {
+		return fmt.Errorf("❌ [RISK CONTROL] Already at max positions (%d/%d)", len(positions), maxPositions)
+	}
+
+	tier := at.assetTier(symbol)
+	if tier.MaxPositions <= 0 {
+		return nil
+	}
+	inTier := 0
+	for _, pos := range positions {
+		if posSymbol, _ := pos["symbol"].(string); posSymbol != "" && at.assetTier(posSymbol).Name == tier.Name {
+			inTier++
+		}
+	}
+	if inTier >= tier.MaxPositions {
+		return fmt.Errorf("❌ [RISK CONTROL] Already at max positions for the %s tier (%d/%d)", tier.Name, inTier, tier.MaxPositions)
 	}
 	return nil
 }
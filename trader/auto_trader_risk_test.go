@@ -1,6 +1,9 @@
 package trader
 
-import "testing"
+import (
+	"nofx/store"
+	"testing"
+)
 
 func TestDrawdownCloseArmsOnPriceBasisOnly(t *testing.T) {
 	cases := []struct {
@@ -25,3 +28,23 @@ func TestDrawdownCloseArmsOnPriceBasisOnly(t *testing.T) {
 		}
 	}
 }
+
+func TestEnforceMaxPositionsPerAssetTier(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en")
+	cfg.RiskControl.MaxPositions = 5
+	cfg.RiskControl.AssetTiers = []store.AssetTierConfig{
+		{Name: "memes", Symbols: []string{"DOGE", "PEPE"}, MaxLeverage: 2, MaxPositionValueRatio: 0.5, MaxPositions: 1},
+	}
+	at := &AutoTrader{config: AutoTraderConfig{StrategyConfig: &cfg}}
+	positions := []map[string]interface{}{
+		{"symbol": "DOGEUSDT", "side": "long"},
+		{"symbol": "BTCUSDT", "side": "long"},
+	}
+
+	if err := at.enforceMaxPositions(positions, "PEPEUSDT"); err == nil {
+		t.Fatal("second meme position should exceed the memes tier limit")
+	}
+	if err := at.enforceMaxPositions(positions, "SOLUSDT"); err != nil {
+		t.Fatalf("other tiers should only be bound by the global limit: %v", err)
+	}
+}
@@ -59,6 +59,12 @@ type FuturesTrader struct {
 
 	// Cache validity period (15 seconds)
 	cacheDuration time.Duration
+
+	log *logger.Scope // see types.LogScoped
+}
+
+func (t *FuturesTrader) SetLogScope(scope *logger.Scope) {
+	t.log = scope
 }
 
 // NewFuturesTrader creates futures trader
@@ -99,15 +105,15 @@ func (t *FuturesTrader) setDualSidePosition() error {
 	if err != nil {
 		// If error message contains "No need to change", it means already in dual-side position mode
 		if strings.Contains(err.Error(), "No need to change position side") {
-			logger.Infof("  ✓ Account is already in dual-side position mode (Hedge Mode)")
+			t.log.Infof("  ✓ Account is already in dual-side position mode (Hedge Mode)")
 			return nil
 		}
 		// Other errors are returned (but won't interrupt initialization in the caller)
 		return err
 	}
 
-	logger.Infof("  ✓ Account has been switched to dual-side position mode (Hedge Mode)")
-	logger.Infof("  ℹ️  Dual-side position mode allows holding both long and short positions simultaneously")
+	t.log.Infof("  ✓ Account has been switched to dual-side position mode (Hedge Mode)")
+	t.log.Infof("  ℹ️  Dual-side position mode allows holding both long and short positions simultaneously")
 	return nil
 }
 
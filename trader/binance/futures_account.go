@@ -3,7 +3,6 @@ package binance
 import (
 	"context"
 	"fmt"
-	"nofx/logger"
 	"nofx/trader/types"
 	"strconv"
 	"time"
@@ -16,16 +15,16 @@ func (t *FuturesTrader) GetBalance() (map[string]interface{}, error) {
 	if t.cachedBalance != nil && time.Since(t.balanceCacheTime) < t.cacheDuration {
 		cacheAge := time.Since(t.balanceCacheTime)
 		t.balanceCacheMutex.RUnlock()
-		logger.Infof("✓ Using cached account balance (cache age: %.1f seconds ago)", cacheAge.Seconds())
+		t.log.Infof("✓ Using cached account balance (cache age: %.1f seconds ago)", cacheAge.Seconds())
 		return t.cachedBalance, nil
 	}
 	t.balanceCacheMutex.RUnlock()
 
 	// Cache expired or doesn't exist, call API
-	logger.Infof("🔄 Cache expired, calling Binance API to get account balance...")
+	t.log.Infof("🔄 Cache expired, calling Binance API to get account balance...")
 	account, err := t.client.NewGetAccountService().Do(context.Background())
 	if err != nil {
-		logger.Infof("❌ Binance API call failed: %v", err)
+		t.log.Infof("❌ Binance API call failed: %v", err)
 		return nil, fmt.Errorf("failed to get account info: %w", err)
 	}
 
@@ -42,7 +41,7 @@ func (t *FuturesTrader) GetBalance() (map[string]interface{}, error) {
 		result[field] = parsed
 	}
 
-	logger.Infof("✓ Binance API returned: total balance=%s, available=%s, unrealized PnL=%s",
+	t.log.Infof("✓ Binance API returned: total balance=%s, available=%s, unrealized PnL=%s",
 		account.TotalWalletBalance,
 		account.AvailableBalance,
 		account.TotalUnrealizedProfit)
@@ -297,3 +296,37 @@ func (t *FuturesTrader) GetPnLSymbols(lastSyncTime time.Time) ([]string, error)
 
 	return symbols, nil
 }
+
+// GetFundingPayments retrieves funding fee settlements from the Binance Futures Income API
+func (t *FuturesTrader) GetFundingPayments(startTime time.Time, limit int) ([]types.FundingPayment, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	incomes, err := t.client.NewGetIncomeHistoryService().
+		IncomeType("FUNDING_FEE").
+		StartTime(startTime.UnixMilli()).
+		Limit(int64(limit)).
+		Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get funding fee history: %w", err)
+	}
+
+	payments := make([]types.FundingPayment, 0, len(incomes))
+	for _, income := range incomes {
+		amount, err := strconv.ParseFloat(income.Income, 64)
+		if err != nil || amount == 0 {
+			continue
+		}
+		payments = append(payments, types.FundingPayment{
+			PaymentID: strconv.FormatInt(income.TranID, 10) + "_" + income.Symbol,
+			Symbol:    income.Symbol,
+			Amount:    amount,
+			Time:      time.UnixMilli(income.Time).UTC(),
+		})
+	}
+	return payments, nil
+}
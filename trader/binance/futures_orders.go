@@ -3,7 +3,6 @@ package binance
 import (
 	"context"
 	"fmt"
-	"nofx/logger"
 	"nofx/trader/types"
 	"strconv"
 
@@ -14,7 +13,7 @@ import (
 func (t *FuturesTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
 	// First cancel all pending orders for this symbol (clean up old stop-loss and take-profit orders)
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
+		t.log.Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
 	}
 
 	// Set leverage
@@ -55,8 +54,8 @@ func (t *FuturesTrader) OpenLong(symbol string, quantity float64, leverage int)
 		return nil, fmt.Errorf("failed to open long position: %w", err)
 	}
 
-	logger.Infof("✓ Opened long position successfully: %s quantity: %s", symbol, quantityStr)
-	logger.Infof("  Order ID: %d", order.OrderID)
+	t.log.Infof("✓ Opened long position successfully: %s quantity: %s", symbol, quantityStr)
+	t.log.Infof("  Order ID: %d", order.OrderID)
 
 	result := make(map[string]interface{})
 	result["orderId"] = order.OrderID
@@ -69,7 +68,7 @@ func (t *FuturesTrader) OpenLong(symbol string, quantity float64, leverage int)
 func (t *FuturesTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
 	// First cancel all pending orders for this symbol (clean up old stop-loss and take-profit orders)
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
+		t.log.Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
 	}
 
 	// Set leverage
@@ -110,8 +109,8 @@ func (t *FuturesTrader) OpenShort(symbol string, quantity float64, leverage int)
 		return nil, fmt.Errorf("failed to open short position: %w", err)
 	}
 
-	logger.Infof("✓ Opened short position successfully: %s quantity: %s", symbol, quantityStr)
-	logger.Infof("  Order ID: %d", order.OrderID)
+	t.log.Infof("✓ Opened short position successfully: %s quantity: %s", symbol, quantityStr)
+	t.log.Infof("  Order ID: %d", order.OrderID)
 
 	result := make(map[string]interface{})
 	result["orderId"] = order.OrderID
@@ -161,11 +160,11 @@ func (t *FuturesTrader) CloseLong(symbol string, quantity float64) (map[string]i
 		return nil, fmt.Errorf("failed to close long position: %w", err)
 	}
 
-	logger.Infof("✓ Closed long position successfully: %s quantity: %s", symbol, quantityStr)
+	t.log.Infof("✓ Closed long position successfully: %s quantity: %s", symbol, quantityStr)
 
 	// After closing position, cancel all pending orders for this symbol (stop-loss and take-profit orders)
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel pending orders: %v", err)
+		t.log.Infof("  ⚠ Failed to cancel pending orders: %v", err)
 	}
 
 	result := make(map[string]interface{})
@@ -216,11 +215,11 @@ func (t *FuturesTrader) CloseShort(symbol string, quantity float64) (map[string]
 		return nil, fmt.Errorf("failed to close short position: %w", err)
 	}
 
-	logger.Infof("✓ Closed short position successfully: %s quantity: %s", symbol, quantityStr)
+	t.log.Infof("✓ Closed short position successfully: %s quantity: %s", symbol, quantityStr)
 
 	// After closing position, cancel all pending orders for this symbol (stop-loss and take-profit orders)
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel pending orders: %v", err)
+		t.log.Infof("  ⚠ Failed to cancel pending orders: %v", err)
 	}
 
 	result := make(map[string]interface{})
@@ -256,12 +255,12 @@ func (t *FuturesTrader) CancelStopLossOrders(symbol string) error {
 				if err != nil {
 					errMsg := fmt.Sprintf("Order ID %d: %v", order.OrderID, err)
 					cancelErrors = append(cancelErrors, fmt.Errorf("%s", errMsg))
-					logger.Infof("  ⚠ Failed to cancel legacy stop-loss order: %s", errMsg)
+					t.log.Infof("  ⚠ Failed to cancel legacy stop-loss order: %s", errMsg)
 					continue
 				}
 
 				canceledCount++
-				logger.Infof("  ✓ Canceled legacy stop-loss order (Order ID: %d, Type: %s, Side: %s)", order.OrderID, orderType, order.PositionSide)
+				t.log.Infof("  ✓ Canceled legacy stop-loss order (Order ID: %d, Type: %s, Side: %s)", order.OrderID, orderType, order.PositionSide)
 			}
 		}
 	}
@@ -282,20 +281,20 @@ func (t *FuturesTrader) CancelStopLossOrders(symbol string) error {
 				if err != nil {
 					errMsg := fmt.Sprintf("Algo ID %d: %v", algoOrder.AlgoId, err)
 					cancelErrors = append(cancelErrors, fmt.Errorf("%s", errMsg))
-					logger.Infof("  ⚠ Failed to cancel Algo stop-loss order: %s", errMsg)
+					t.log.Infof("  ⚠ Failed to cancel Algo stop-loss order: %s", errMsg)
 					continue
 				}
 
 				canceledCount++
-				logger.Infof("  ✓ Canceled Algo stop-loss order (Algo ID: %d, Type: %s)", algoOrder.AlgoId, algoOrder.OrderType)
+				t.log.Infof("  ✓ Canceled Algo stop-loss order (Algo ID: %d, Type: %s)", algoOrder.AlgoId, algoOrder.OrderType)
 			}
 		}
 	}
 
 	if canceledCount == 0 && len(cancelErrors) == 0 {
-		logger.Infof("  ℹ %s has no stop-loss orders to cancel", symbol)
+		t.log.Infof("  ℹ %s has no stop-loss orders to cancel", symbol)
 	} else if canceledCount > 0 {
-		logger.Infof("  ✓ Canceled %d stop-loss order(s) for %s", canceledCount, symbol)
+		t.log.Infof("  ✓ Canceled %d stop-loss order(s) for %s", canceledCount, symbol)
 	}
 
 	// If all cancellations failed, return error
@@ -332,12 +331,12 @@ func (t *FuturesTrader) CancelTakeProfitOrders(symbol string) error {
 				if err != nil {
 					errMsg := fmt.Sprintf("Order ID %d: %v", order.OrderID, err)
 					cancelErrors = append(cancelErrors, fmt.Errorf("%s", errMsg))
-					logger.Infof("  ⚠ Failed to cancel legacy take-profit order: %s", errMsg)
+					t.log.Infof("  ⚠ Failed to cancel legacy take-profit order: %s", errMsg)
 					continue
 				}
 
 				canceledCount++
-				logger.Infof("  ✓ Canceled legacy take-profit order (Order ID: %d, Type: %s, Side: %s)", order.OrderID, orderType, order.PositionSide)
+				t.log.Infof("  ✓ Canceled legacy take-profit order (Order ID: %d, Type: %s, Side: %s)", order.OrderID, orderType, order.PositionSide)
 			}
 		}
 	}
@@ -358,20 +357,20 @@ func (t *FuturesTrader) CancelTakeProfitOrders(symbol string) error {
 				if err != nil {
 					errMsg := fmt.Sprintf("Algo ID %d: %v", algoOrder.AlgoId, err)
 					cancelErrors = append(cancelErrors, fmt.Errorf("%s", errMsg))
-					logger.Infof("  ⚠ Failed to cancel Algo take-profit order: %s", errMsg)
+					t.log.Infof("  ⚠ Failed to cancel Algo take-profit order: %s", errMsg)
 					continue
 				}
 
 				canceledCount++
-				logger.Infof("  ✓ Canceled Algo take-profit order (Algo ID: %d, Type: %s)", algoOrder.AlgoId, algoOrder.OrderType)
+				t.log.Infof("  ✓ Canceled Algo take-profit order (Algo ID: %d, Type: %s)", algoOrder.AlgoId, algoOrder.OrderType)
 			}
 		}
 	}
 
 	if canceledCount == 0 && len(cancelErrors) == 0 {
-		logger.Infof("  ℹ %s has no take-profit orders to cancel", symbol)
+		t.log.Infof("  ℹ %s has no take-profit orders to cancel", symbol)
 	} else if canceledCount > 0 {
-		logger.Infof("  ✓ Canceled %d take-profit order(s) for %s", canceledCount, symbol)
+		t.log.Infof("  ✓ Canceled %d take-profit order(s) for %s", canceledCount, symbol)
 	}
 
 	// If all cancellations failed, return error
@@ -391,9 +390,9 @@ func (t *FuturesTrader) CancelAllOrders(symbol string) error {
 		Do(context.Background())
 
 	if err != nil {
-		logger.Infof("  ⚠ Failed to cancel legacy orders: %v", err)
+		t.log.Infof("  ⚠ Failed to cancel legacy orders: %v", err)
 	} else {
-		logger.Infof("  ✓ Canceled all legacy pending orders for %s", symbol)
+		t.log.Infof("  ✓ Canceled all legacy pending orders for %s", symbol)
 	}
 
 	// 2. Cancel all Algo orders
@@ -404,10 +403,10 @@ func (t *FuturesTrader) CancelAllOrders(symbol string) error {
 	if err != nil {
 		// Ignore "no algo orders" error
 		if !contains(err.Error(), "no algo") && !contains(err.Error(), "No algo") {
-			logger.Infof("  ⚠ Failed to cancel Algo orders: %v", err)
+			t.log.Infof("  ⚠ Failed to cancel Algo orders: %v", err)
 		}
 	} else {
-		logger.Infof("  ✓ Canceled all Algo orders for %s", symbol)
+		t.log.Infof("  ✓ Canceled all Algo orders for %s", symbol)
 	}
 
 	return nil
@@ -431,7 +430,7 @@ func (t *FuturesTrader) PlaceLimitOrder(req *types.LimitOrderRequest) (*types.Li
 	// Set leverage if specified
 	if req.Leverage > 0 {
 		if err := t.SetLeverage(req.Symbol, req.Leverage); err != nil {
-			logger.Warnf("Failed to set leverage: %v", err)
+			t.log.Warnf("Failed to set leverage: %v", err)
 		}
 	}
 
@@ -464,7 +463,7 @@ func (t *FuturesTrader) PlaceLimitOrder(req *types.LimitOrderRequest) (*types.Li
 		return nil, fmt.Errorf("failed to place limit order: %w", err)
 	}
 
-	logger.Infof("✓ [Grid] Placed limit order: %s %s %s @ %s, qty=%s, orderID=%d",
+	t.log.Infof("✓ [Grid] Placed limit order: %s %s %s @ %s, qty=%s, orderID=%d",
 		req.Symbol, req.Side, positionSide, priceStr, quantityStr, order.OrderID)
 
 	return &types.LimitOrderResult{
@@ -497,7 +496,7 @@ func (t *FuturesTrader) CancelOrder(symbol, orderID string) error {
 		return fmt.Errorf("failed to cancel order: %w", err)
 	}
 
-	logger.Infof("✓ [Grid] Cancelled order: %s/%s", symbol, orderID)
+	t.log.Infof("✓ [Grid] Cancelled order: %s/%s", symbol, orderID)
 	return nil
 }
 
@@ -559,12 +558,12 @@ func (t *FuturesTrader) CancelStopOrders(symbol string) error {
 					Do(context.Background())
 
 				if err != nil {
-					logger.Infof("  ⚠ Failed to cancel legacy order %d: %v", order.OrderID, err)
+					t.log.Infof("  ⚠ Failed to cancel legacy order %d: %v", order.OrderID, err)
 					continue
 				}
 
 				canceledCount++
-				logger.Infof("  ✓ Canceled legacy stop order for %s (Order ID: %d, Type: %s)",
+				t.log.Infof("  ✓ Canceled legacy stop order for %s (Order ID: %d, Type: %s)",
 					symbol, order.OrderID, orderType)
 			}
 		}
@@ -578,15 +577,15 @@ func (t *FuturesTrader) CancelStopOrders(symbol string) error {
 	if err != nil {
 		// Ignore "no algo orders" error
 		if !contains(err.Error(), "no algo") && !contains(err.Error(), "No algo") {
-			logger.Infof("  ⚠ Failed to cancel Algo orders: %v", err)
+			t.log.Infof("  ⚠ Failed to cancel Algo orders: %v", err)
 		}
 	} else {
-		logger.Infof("  ✓ Canceled all Algo orders for %s", symbol)
+		t.log.Infof("  ✓ Canceled all Algo orders for %s", symbol)
 		canceledCount++
 	}
 
 	if canceledCount == 0 {
-		logger.Infof("  ℹ %s has no take-profit/stop-loss orders to cancel", symbol)
+		t.log.Infof("  ℹ %s has no take-profit/stop-loss orders to cancel", symbol)
 	}
 
 	return nil
@@ -680,7 +679,7 @@ func (t *FuturesTrader) SetStopLoss(symbol string, positionSide string, quantity
 		return fmt.Errorf("failed to set stop-loss: %w", err)
 	}
 
-	logger.Infof("  Stop-loss price set (Algo Order): %.4f", stopPrice)
+	t.log.Infof("  Stop-loss price set (Algo Order): %.4f", stopPrice)
 	return nil
 }
 
@@ -714,7 +713,7 @@ func (t *FuturesTrader) SetTakeProfit(symbol string, positionSide string, quanti
 		return fmt.Errorf("failed to set take-profit: %w", err)
 	}
 
-	logger.Infof("  Take-profit price set (Algo Order): %.4f", takeProfitPrice)
+	t.log.Infof("  Take-profit price set (Algo Order): %.4f", takeProfitPrice)
 	return nil
 }
 
@@ -3,7 +3,6 @@ package binance
 import (
 	"context"
 	"fmt"
-	"nofx/logger"
 	"strconv"
 	"time"
 
@@ -17,13 +16,13 @@ func (t *FuturesTrader) GetPositions() ([]map[string]interface{}, error) {
 	if t.cachedPositions != nil && time.Since(t.positionsCacheTime) < t.cacheDuration {
 		cacheAge := time.Since(t.positionsCacheTime)
 		t.positionsCacheMutex.RUnlock()
-		logger.Infof("✓ Using cached position information (cache age: %.1f seconds ago)", cacheAge.Seconds())
+		t.log.Infof("✓ Using cached position information (cache age: %.1f seconds ago)", cacheAge.Seconds())
 		return t.cachedPositions, nil
 	}
 	t.positionsCacheMutex.RUnlock()
 
 	// Cache expired or doesn't exist, call API
-	logger.Infof("🔄 Cache expired, calling Binance API to get position information...")
+	t.log.Infof("🔄 Cache expired, calling Binance API to get position information...")
 	positions, err := t.client.NewGetPositionRiskService().Do(context.Background())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get positions: %w", err)
@@ -88,31 +87,31 @@ func (t *FuturesTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
 	if err != nil {
 		// If error message contains "No need to change", margin mode is already set to target value
 		if contains(err.Error(), "No need to change margin type") {
-			logger.Infof("  ✓ %s margin mode is already %s", symbol, marginModeStr)
+			t.log.Infof("  ✓ %s margin mode is already %s", symbol, marginModeStr)
 			return nil
 		}
 		// If there is an open position, margin mode cannot be changed, but this doesn't affect trading
 		if contains(err.Error(), "Margin type cannot be changed if there exists position") {
-			logger.Infof("  ⚠️ %s has open positions, cannot change margin mode, continuing with current mode", symbol)
+			t.log.Infof("  ⚠️ %s has open positions, cannot change margin mode, continuing with current mode", symbol)
 			return nil
 		}
 		// Detect Multi-Assets mode (error code -4168)
 		if contains(err.Error(), "Multi-Assets mode") || contains(err.Error(), "-4168") || contains(err.Error(), "4168") {
-			logger.Infof("  ⚠️ %s detected Multi-Assets mode, forcing Cross Margin mode", symbol)
-			logger.Infof("  💡 Tip: To use Isolated Margin mode, please disable Multi-Assets mode in Binance")
+			t.log.Infof("  ⚠️ %s detected Multi-Assets mode, forcing Cross Margin mode", symbol)
+			t.log.Infof("  💡 Tip: To use Isolated Margin mode, please disable Multi-Assets mode in Binance")
 			return nil
 		}
 		// Detect Unified Account API (Portfolio Margin)
 		if contains(err.Error(), "unified") || contains(err.Error(), "portfolio") || contains(err.Error(), "Portfolio") {
-			logger.Infof("  ❌ %s detected Unified Account API, unable to trade futures", symbol)
+			t.log.Infof("  ❌ %s detected Unified Account API, unable to trade futures", symbol)
 			return fmt.Errorf("please use 'Spot & Futures Trading' API permission, do not use 'Unified Account API'")
 		}
-		logger.Infof("  ⚠️ Failed to set margin mode: %v", err)
+		t.log.Infof("  ⚠️ Failed to set margin mode: %v", err)
 		// Don't return error, let trading continue
 		return nil
 	}
 
-	logger.Infof("  ✓ %s margin mode set to %s", symbol, marginModeStr)
+	t.log.Infof("  ✓ %s margin mode set to %s", symbol, marginModeStr)
 	return nil
 }
 
@@ -134,7 +133,7 @@ func (t *FuturesTrader) SetLeverage(symbol string, leverage int) error {
 
 	// If current leverage is already the target leverage, skip
 	if currentLeverage == leverage && currentLeverage > 0 {
-		logger.Infof("  ✓ %s leverage is already %dx, no need to change", symbol, leverage)
+		t.log.Infof("  ✓ %s leverage is already %dx, no need to change", symbol, leverage)
 		return nil
 	}
 
@@ -147,16 +146,16 @@ func (t *FuturesTrader) SetLeverage(symbol string, leverage int) error {
 	if err != nil {
 		// If error message contains "No need to change", leverage is already the target value
 		if contains(err.Error(), "No need to change") {
-			logger.Infof("  ✓ %s leverage is already %dx", symbol, leverage)
+			t.log.Infof("  ✓ %s leverage is already %dx", symbol, leverage)
 			return nil
 		}
 		return fmt.Errorf("failed to set leverage: %w", err)
 	}
 
-	logger.Infof("  ✓ %s leverage changed to %dx", symbol, leverage)
+	t.log.Infof("  ✓ %s leverage changed to %dx", symbol, leverage)
 
 	// Wait 5 seconds after changing leverage (to avoid cooldown period errors)
-	logger.Infof("  ⏱ Waiting 5 seconds for cooldown period...")
+	t.log.Infof("  ⏱ Waiting 5 seconds for cooldown period...")
 	time.Sleep(5 * time.Second)
 
 	return nil
@@ -229,14 +228,14 @@ func (t *FuturesTrader) GetSymbolPrecision(symbol string) (int, error) {
 				if filter["filterType"] == "LOT_SIZE" {
 					stepSize := filter["stepSize"].(string)
 					precision := calculatePrecision(stepSize)
-					logger.Infof("  %s quantity precision: %d (stepSize: %s)", symbol, precision, stepSize)
+					t.log.Infof("  %s quantity precision: %d (stepSize: %s)", symbol, precision, stepSize)
 					return precision, nil
 				}
 			}
 		}
 	}
 
-	logger.Infof("  ⚠ %s precision information not found, using default precision 3", symbol)
+	t.log.Infof("  ⚠ %s precision information not found, using default precision 3", symbol)
 	return 3, nil // Default precision is 3
 }
 
@@ -2,7 +2,6 @@ package binance
 
 import (
 	"fmt"
-	"nofx/logger"
 	"nofx/market"
 	"nofx/store"
 	"nofx/trader/syncloop"
@@ -41,30 +40,30 @@ func (t *FuturesTrader) SyncOrdersFromBinance(traderID string, exchangeID string
 		if err == nil && lastFillTimeMs > 0 {
 			// If recovered time is in the future, it's clearly wrong - use default
 			if lastFillTimeMs > nowMs {
-				logger.Infof("⚠️ DB sync time %d is in the future (now: %d), using default",
+				t.log.Infof("⚠️ DB sync time %d is in the future (now: %d), using default",
 					lastFillTimeMs, nowMs)
 				lastSyncTimeMs = nowMs - 24*60*60*1000 // 24 hours ago
 			} else {
 				// Add 1 second buffer to avoid re-fetching the same fill
 				lastSyncTimeMs = lastFillTimeMs + 1000
-				logger.Infof("📅 Recovered last sync time from DB: %s (UTC)",
+				t.log.Infof("📅 Recovered last sync time from DB: %s (UTC)",
 					time.UnixMilli(lastSyncTimeMs).UTC().Format("2006-01-02 15:04:05"))
 			}
 		} else {
 			// First sync: go back 24 hours
 			lastSyncTimeMs = nowMs - 24*60*60*1000
-			logger.Infof("📅 First sync, starting from 24 hours ago: %s (UTC)",
+			t.log.Infof("📅 First sync, starting from 24 hours ago: %s (UTC)",
 				time.UnixMilli(lastSyncTimeMs).UTC().Format("2006-01-02 15:04:05"))
 		}
 	}
 
-	logger.Infof("🔄 Syncing Binance trades from: %s (UTC) [ms: %d, now: %d]",
+	t.log.Infof("🔄 Syncing Binance trades from: %s (UTC) [ms: %d, now: %d]",
 		time.UnixMilli(lastSyncTimeMs).UTC().Format("2006-01-02 15:04:05"), lastSyncTimeMs, nowMs)
 
 	// Step 1: Get max trade IDs from local DB for incremental sync
 	maxTradeIDs, err := orderStore.GetMaxTradeIDsByExchange(exchangeID)
 	if err != nil {
-		logger.Infof("  ⚠️ Failed to get max trade IDs: %v, will use time-based query", err)
+		t.log.Infof("  ⚠️ Failed to get max trade IDs: %v, will use time-based query", err)
 		maxTradeIDs = make(map[string]int64)
 	}
 
@@ -76,9 +75,9 @@ func (t *FuturesTrader) SyncOrdersFromBinance(traderID string, exchangeID string
 	// Method 1: COMMISSION income detection
 	commissionSymbols, err := t.GetCommissionSymbols(lastSyncTime)
 	if err != nil {
-		logger.Infof("  ⚠️ Failed to get commission symbols: %v", err)
+		t.log.Infof("  ⚠️ Failed to get commission symbols: %v", err)
 	} else {
-		logger.Infof("  📋 COMMISSION symbols found: %d - %v", len(commissionSymbols), commissionSymbols)
+		t.log.Infof("  📋 COMMISSION symbols found: %d - %v", len(commissionSymbols), commissionSymbols)
 		for _, s := range commissionSymbols {
 			symbolMap[s] = true
 		}
@@ -86,14 +85,14 @@ func (t *FuturesTrader) SyncOrdersFromBinance(traderID string, exchangeID string
 
 	// Method 2: Always include active positions (catches trades that COMMISSION missed)
 	positionSymbols := t.getPositionSymbols()
-	logger.Infof("  📋 Position symbols found: %d - %v", len(positionSymbols), positionSymbols)
+	t.log.Infof("  📋 Position symbols found: %d - %v", len(positionSymbols), positionSymbols)
 	for _, s := range positionSymbols {
 		symbolMap[s] = true
 	}
 
 	// Method 3: Include symbols from recent fills in DB (in case some were partially synced)
 	recentSymbols, _ := orderStore.GetRecentFillSymbolsByExchange(exchangeID, lastSyncTimeMs)
-	logger.Infof("  📋 Recent fill symbols found: %d - %v", len(recentSymbols), recentSymbols)
+	t.log.Infof("  📋 Recent fill symbols found: %d - %v", len(recentSymbols), recentSymbols)
 	for _, s := range recentSymbols {
 		symbolMap[s] = true
 	}
@@ -104,9 +103,9 @@ func (t *FuturesTrader) SyncOrdersFromBinance(traderID string, exchangeID string
 	// because a position might be fully closed (no active position) but have PnL
 	pnlSymbols, err := t.GetPnLSymbols(lastSyncTime)
 	if err != nil {
-		logger.Infof("  ⚠️ Failed to get PnL symbols: %v", err)
+		t.log.Infof("  ⚠️ Failed to get PnL symbols: %v", err)
 	} else {
-		logger.Infof("  📋 REALIZED_PNL symbols found: %d - %v", len(pnlSymbols), pnlSymbols)
+		t.log.Infof("  📋 REALIZED_PNL symbols found: %d - %v", len(pnlSymbols), pnlSymbols)
 		for _, s := range pnlSymbols {
 			symbolMap[s] = true
 		}
@@ -118,14 +117,14 @@ func (t *FuturesTrader) SyncOrdersFromBinance(traderID string, exchangeID string
 	}
 
 	if len(changedSymbols) == 0 {
-		logger.Infof("📭 No symbols with new trades to sync")
+		t.log.Infof("📭 No symbols with new trades to sync")
 		// DON'T update lastSyncTime to current time here!
 		// Keep using the last actual trade time from DB to avoid creating gaps
 		// The lastSyncTimeMs from DB already has +1000ms buffer added
 		return nil
 	}
 
-	logger.Infof("📊 Found %d symbols with new trades: %v", len(changedSymbols), changedSymbols)
+	t.log.Infof("📊 Found %d symbols with new trades: %v", len(changedSymbols), changedSymbols)
 
 	// Step 3: Query trades for changed symbols using fromId (incremental) or time-based (new symbols)
 	var allTrades []types.TradeRecord
@@ -145,20 +144,20 @@ func (t *FuturesTrader) SyncOrdersFromBinance(traderID string, exchangeID string
 		apiCalls++
 
 		if queryErr != nil {
-			logger.Infof("  ⚠️ Failed to get trades for %s: %v", symbol, queryErr)
+			t.log.Infof("  ⚠️ Failed to get trades for %s: %v", symbol, queryErr)
 			failedSymbols = append(failedSymbols, symbol)
 			continue
 		}
 		allTrades = append(allTrades, trades...)
 	}
 
-	logger.Infof("📥 Received %d trades from Binance (%d API calls)", len(allTrades), apiCalls)
+	t.log.Infof("📥 Received %d trades from Binance (%d API calls)", len(allTrades), apiCalls)
 
 	if len(allTrades) == 0 {
 		// No trades returned, but symbols were detected - might be false positive from COMMISSION/PnL detection
 		// Don't update lastSyncTime, keep using DB value
 		if len(failedSymbols) > 0 {
-			logger.Infof("  ⚠️ %d symbols failed: %v", len(failedSymbols), failedSymbols)
+			t.log.Infof("  ⚠️ %d symbols failed: %v", len(failedSymbols), failedSymbols)
 		}
 		return nil
 	}
@@ -227,7 +226,7 @@ func (t *FuturesTrader) SyncOrdersFromBinance(traderID string, exchangeID string
 
 		// Insert order record
 		if err := orderStore.CreateOrder(orderRecord); err != nil {
-			logger.Infof("  ⚠️ Failed to sync trade %s: %v", trade.TradeID, err)
+			t.log.Infof("  ⚠️ Failed to sync trade %s: %v", trade.TradeID, err)
 			continue
 		}
 
@@ -252,7 +251,7 @@ func (t *FuturesTrader) SyncOrdersFromBinance(traderID string, exchangeID string
 		}
 
 		if err := orderStore.CreateFill(fillRecord); err != nil {
-			logger.Infof("  ⚠️ Failed to sync fill for trade %s: %v", trade.TradeID, err)
+			t.log.Infof("  ⚠️ Failed to sync fill for trade %s: %v", trade.TradeID, err)
 		}
 
 		// Create/update position record using PositionBuilder
@@ -262,13 +261,13 @@ func (t *FuturesTrader) SyncOrdersFromBinance(traderID string, exchangeID string
 			trade.Quantity, trade.Price, trade.Fee, trade.RealizedPnL,
 			tradeTimeMs, trade.TradeID,
 		); err != nil {
-			logger.Infof("  ⚠️ Failed to sync position for trade %s: %v", trade.TradeID, err)
+			t.log.Infof("  ⚠️ Failed to sync position for trade %s: %v", trade.TradeID, err)
 		} else {
-			logger.Infof("  📍 Position updated for trade: %s (action: %s, qty: %.6f)", trade.TradeID, orderAction, trade.Quantity)
+			t.log.Infof("  📍 Position updated for trade: %s (action: %s, qty: %.6f)", trade.TradeID, orderAction, trade.Quantity)
 		}
 
 		syncedCount++
-		logger.Infof("  ✅ Synced trade: %s %s %s qty=%.6f price=%.6f pnl=%.2f fee=%.6f action=%s time=%s(UTC)",
+		t.log.Infof("  ✅ Synced trade: %s %s %s qty=%.6f price=%.6f pnl=%.2f fee=%.6f action=%s time=%s(UTC)",
 			trade.TradeID, symbol, side, trade.Quantity, trade.Price, trade.RealizedPnL, trade.Fee, orderAction,
 			trade.Time.UTC().Format("01-02 15:04:05"))
 	}
@@ -281,13 +280,13 @@ func (t *FuturesTrader) SyncOrdersFromBinance(traderID string, exchangeID string
 		binanceSyncStateMutex.Lock()
 		binanceSyncState[exchangeID] = latestTradeTimeMs
 		binanceSyncStateMutex.Unlock()
-		logger.Infof("📅 Updated lastSyncTime to latest trade: %s (UTC)",
+		t.log.Infof("📅 Updated lastSyncTime to latest trade: %s (UTC)",
 			time.UnixMilli(latestTradeTimeMs).UTC().Format("2006-01-02 15:04:05"))
 	} else if len(failedSymbols) > 0 {
-		logger.Infof("  ⚠️ %d symbols failed, not updating lastSyncTime to retry next time: %v", len(failedSymbols), failedSymbols)
+		t.log.Infof("  ⚠️ %d symbols failed, not updating lastSyncTime to retry next time: %v", len(failedSymbols), failedSymbols)
 	}
 
-	logger.Infof("✅ Binance order sync completed: %d new trades synced, %d skipped (already exist)", syncedCount, skippedCount)
+	t.log.Infof("✅ Binance order sync completed: %d new trades synced, %d skipped (already exist)", syncedCount, skippedCount)
 	return nil
 }
 
@@ -353,14 +352,14 @@ func (t *FuturesTrader) determineOrderAction(side, positionSide string, realized
 func (t *FuturesTrader) StartOrderSync(traderID string, exchangeID string, exchangeType string, st *store.Store, interval time.Duration, stop <-chan struct{}) {
 	// Run first sync immediately
 	go func() {
-		logger.Infof("🔄 Running initial Binance order sync...")
+		t.log.Infof("🔄 Running initial Binance order sync...")
 		if err := t.SyncOrdersFromBinance(traderID, exchangeID, exchangeType, st); err != nil {
-			logger.Infof("⚠️  Initial Binance order sync failed: %v", err)
+			t.log.Infof("⚠️  Initial Binance order sync failed: %v", err)
 		}
 	}()
 
 	// Then run periodically
-	syncloop.Run(stop, interval, "Binance", func() error {
+	syncloop.RunForTrader(stop, interval, "Binance", traderID, func() error {
 		return t.SyncOrdersFromBinance(traderID, exchangeID, exchangeType, st)
 	})
 }
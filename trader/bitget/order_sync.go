@@ -3,7 +3,6 @@ package bitget
 import (
 	"encoding/json"
 	"fmt"
-	"nofx/logger"
 	"nofx/market"
 	"nofx/store"
 	"nofx/trader/syncloop"
@@ -76,15 +75,15 @@ func (t *BitgetTrader) GetTrades(startTime time.Time, limit int) ([]BitgetTrade,
 
 	// Try wrapped format first
 	if err := json.Unmarshal(data, &wrappedResp); err == nil && len(wrappedResp.FillList) > 0 {
-		logger.Infof("🔍 Bitget: parsed as wrapped format, fillList count: %d", len(wrappedResp.FillList))
+		t.log.Infof("🔍 Bitget: parsed as wrapped format, fillList count: %d", len(wrappedResp.FillList))
 		directFills = wrappedResp.FillList
 	} else {
 		// Try direct array format
 		if err := json.Unmarshal(data, &directFills); err != nil {
-			logger.Infof("⚠️ Bitget fill-history parse failed, raw: %s", string(data))
+			t.log.Infof("⚠️ Bitget fill-history parse failed, raw: %s", string(data))
 			return nil, fmt.Errorf("failed to parse fills: %w", err)
 		}
-		logger.Infof("🔍 Bitget: parsed as direct array, fills count: %d", len(directFills))
+		t.log.Infof("🔍 Bitget: parsed as direct array, fills count: %d", len(directFills))
 	}
 
 	trades := make([]BitgetTrade, 0, len(directFills))
@@ -164,7 +163,7 @@ func (t *BitgetTrader) SyncOrdersFromBitget(traderID string, exchangeID string,
 	// Get recent trades (last 24 hours)
 	startTime := time.Now().Add(-24 * time.Hour)
 
-	logger.Infof("🔄 Syncing Bitget trades from: %s", startTime.Format(time.RFC3339))
+	t.log.Infof("🔄 Syncing Bitget trades from: %s", startTime.Format(time.RFC3339))
 
 	// Use GetTrades method to fetch trade records
 	trades, err := t.GetTrades(startTime, 100)
@@ -172,7 +171,7 @@ func (t *BitgetTrader) SyncOrdersFromBitget(traderID string, exchangeID string,
 		return fmt.Errorf("failed to get trades: %w", err)
 	}
 
-	logger.Infof("📥 Received %d trades from Bitget", len(trades))
+	t.log.Infof("📥 Received %d trades from Bitget", len(trades))
 
 	// Sort trades by time ASC (oldest first) for proper position building
 	sort.Slice(trades, func(i, j int) bool {
@@ -229,7 +228,7 @@ func (t *BitgetTrader) SyncOrdersFromBitget(traderID string, exchangeID string,
 
 		// Insert order record
 		if err := orderStore.CreateOrder(orderRecord); err != nil {
-			logger.Infof("  ⚠️ Failed to sync trade %s: %v", trade.TradeID, err)
+			t.log.Infof("  ⚠️ Failed to sync trade %s: %v", trade.TradeID, err)
 			continue
 		}
 
@@ -254,7 +253,7 @@ func (t *BitgetTrader) SyncOrdersFromBitget(traderID string, exchangeID string,
 		}
 
 		if err := orderStore.CreateFill(fillRecord); err != nil {
-			logger.Infof("  ⚠️ Failed to sync fill for trade %s: %v", trade.TradeID, err)
+			t.log.Infof("  ⚠️ Failed to sync fill for trade %s: %v", trade.TradeID, err)
 		}
 
 		// Create/update position record using PositionBuilder
@@ -264,23 +263,23 @@ func (t *BitgetTrader) SyncOrdersFromBitget(traderID string, exchangeID string,
 			trade.FillQty, trade.FillPrice, trade.Fee, trade.ProfitLoss,
 			execTimeMs, trade.TradeID,
 		); err != nil {
-			logger.Infof("  ⚠️ Failed to sync position for trade %s: %v", trade.TradeID, err)
+			t.log.Infof("  ⚠️ Failed to sync position for trade %s: %v", trade.TradeID, err)
 		} else {
-			logger.Infof("  📍 Position updated for trade: %s (action: %s, qty: %.6f)", trade.TradeID, trade.OrderAction, trade.FillQty)
+			t.log.Infof("  📍 Position updated for trade: %s (action: %s, qty: %.6f)", trade.TradeID, trade.OrderAction, trade.FillQty)
 		}
 
 		syncedCount++
-		logger.Infof("  ✅ Synced trade: %s %s %s qty=%.6f price=%.6f pnl=%.2f fee=%.6f action=%s",
+		t.log.Infof("  ✅ Synced trade: %s %s %s qty=%.6f price=%.6f pnl=%.2f fee=%.6f action=%s",
 			trade.TradeID, symbol, side, trade.FillQty, trade.FillPrice, trade.ProfitLoss, trade.Fee, trade.OrderAction)
 	}
 
-	logger.Infof("✅ Bitget order sync completed: %d new trades synced", syncedCount)
+	t.log.Infof("✅ Bitget order sync completed: %d new trades synced", syncedCount)
 	return nil
 }
 
 // StartOrderSync starts background order sync task for Bitget
 func (t *BitgetTrader) StartOrderSync(traderID string, exchangeID string, exchangeType string, st *store.Store, interval time.Duration, stop <-chan struct{}) {
-	syncloop.Run(stop, interval, "Bitget", func() error {
+	syncloop.RunForTrader(stop, interval, "Bitget", traderID, func() error {
 		return t.SyncOrdersFromBitget(traderID, exchangeID, exchangeType, st)
 	})
 }
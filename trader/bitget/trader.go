@@ -58,6 +58,12 @@ type BitgetTrader struct {
 
 	// Cache duration
 	cacheDuration time.Duration
+
+	log *logger.Scope // see types.LogScoped
+}
+
+func (t *BitgetTrader) SetLogScope(scope *logger.Scope) {
+	t.log = scope
 }
 
 // BitgetContract Bitget contract info
@@ -121,7 +127,7 @@ func (t *BitgetTrader) setPositionMode() error {
 		return err
 	}
 
-	logger.Infof("  ✓ Bitget account switched to one-way position mode")
+	t.log.Infof("  ✓ Bitget account switched to one-way position mode")
 	return nil
 }
 
@@ -3,7 +3,6 @@ package bitget
 import (
 	"encoding/json"
 	"fmt"
-	"nofx/logger"
 	"nofx/trader/types"
 	"strconv"
 	"strings"
@@ -53,7 +52,7 @@ func (t *BitgetTrader) GetBalance() (map[string]interface{}, error) {
 			if unrealizedPnL, err = types.ParseFloatField("unrealizedPL", acc.UnrealizedPL); err != nil {
 				return nil, err
 			}
-			logger.Infof("✓ [Bitget] Balance: equity=%.2f, available=%.2f", totalEquity, availableBalance)
+			t.log.Infof("✓ [Bitget] Balance: equity=%.2f, available=%.2f", totalEquity, availableBalance)
 			break
 		}
 	}
@@ -96,13 +95,13 @@ func (t *BitgetTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
 			return nil
 		}
 		if strings.Contains(err.Error(), "position") {
-			logger.Infof("  ⚠️ %s has positions, cannot change margin mode", symbol)
+			t.log.Infof("  ⚠️ %s has positions, cannot change margin mode", symbol)
 			return nil
 		}
 		return err
 	}
 
-	logger.Infof("  ✓ %s margin mode set to %s", symbol, marginMode)
+	t.log.Infof("  ✓ %s margin mode set to %s", symbol, marginMode)
 	return nil
 }
 
@@ -122,11 +121,11 @@ func (t *BitgetTrader) SetLeverage(symbol string, leverage int) error {
 		if strings.Contains(err.Error(), "same") {
 			return nil
 		}
-		logger.Infof("  ⚠️ Failed to set %s leverage: %v", symbol, err)
+		t.log.Infof("  ⚠️ Failed to set %s leverage: %v", symbol, err)
 		return err
 	}
 
-	logger.Infof("  ✓ %s leverage set to %dx", symbol, leverage)
+	t.log.Infof("  ✓ %s leverage set to %dx", symbol, leverage)
 	return nil
 }
 
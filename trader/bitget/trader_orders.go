@@ -3,7 +3,6 @@ package bitget
 import (
 	"encoding/json"
 	"fmt"
-	"nofx/logger"
 	"nofx/trader/types"
 	"strconv"
 	"strings"
@@ -15,12 +14,12 @@ func (t *BitgetTrader) OpenLong(symbol string, quantity float64, leverage int) (
 
 	// Cancel old orders first
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
+		t.log.Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
 	}
 
 	// Set leverage
 	if err := t.SetLeverage(symbol, leverage); err != nil {
-		logger.Infof("  ⚠️ Failed to set leverage: %v", err)
+		t.log.Infof("  ⚠️ Failed to set leverage: %v", err)
 	}
 
 	// Format quantity
@@ -37,7 +36,7 @@ func (t *BitgetTrader) OpenLong(symbol string, quantity float64, leverage int) (
 		"clientOid":   genBitgetClientOid(),
 	}
 
-	logger.Infof("  📊 Bitget OpenLong: symbol=%s, qty=%s, leverage=%d", symbol, qtyStr, leverage)
+	t.log.Infof("  📊 Bitget OpenLong: symbol=%s, qty=%s, leverage=%d", symbol, qtyStr, leverage)
 
 	data, err := t.doRequest("POST", bitgetOrderPath, body)
 	if err != nil {
@@ -56,7 +55,7 @@ func (t *BitgetTrader) OpenLong(symbol string, quantity float64, leverage int) (
 	// Clear cache
 	t.clearCache()
 
-	logger.Infof("✓ Bitget opened long position successfully: %s", symbol)
+	t.log.Infof("✓ Bitget opened long position successfully: %s", symbol)
 
 	return map[string]interface{}{
 		"orderId": order.OrderId,
@@ -71,12 +70,12 @@ func (t *BitgetTrader) OpenShort(symbol string, quantity float64, leverage int)
 
 	// Cancel old orders first
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
+		t.log.Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
 	}
 
 	// Set leverage
 	if err := t.SetLeverage(symbol, leverage); err != nil {
-		logger.Infof("  ⚠️ Failed to set leverage: %v", err)
+		t.log.Infof("  ⚠️ Failed to set leverage: %v", err)
 	}
 
 	// Format quantity
@@ -93,7 +92,7 @@ func (t *BitgetTrader) OpenShort(symbol string, quantity float64, leverage int)
 		"clientOid":   genBitgetClientOid(),
 	}
 
-	logger.Infof("  📊 Bitget OpenShort: symbol=%s, qty=%s, leverage=%d", symbol, qtyStr, leverage)
+	t.log.Infof("  📊 Bitget OpenShort: symbol=%s, qty=%s, leverage=%d", symbol, qtyStr, leverage)
 
 	data, err := t.doRequest("POST", bitgetOrderPath, body)
 	if err != nil {
@@ -112,7 +111,7 @@ func (t *BitgetTrader) OpenShort(symbol string, quantity float64, leverage int)
 	// Clear cache
 	t.clearCache()
 
-	logger.Infof("✓ Bitget opened short position successfully: %s", symbol)
+	t.log.Infof("✓ Bitget opened short position successfully: %s", symbol)
 
 	return map[string]interface{}{
 		"orderId": order.OrderId,
@@ -157,7 +156,7 @@ func (t *BitgetTrader) CloseLong(symbol string, quantity float64) (map[string]in
 		"clientOid":   genBitgetClientOid(),
 	}
 
-	logger.Infof("  📊 Bitget CloseLong: symbol=%s, qty=%s", symbol, qtyStr)
+	t.log.Infof("  📊 Bitget CloseLong: symbol=%s, qty=%s", symbol, qtyStr)
 
 	data, err := t.doRequest("POST", bitgetOrderPath, body)
 	if err != nil {
@@ -175,7 +174,7 @@ func (t *BitgetTrader) CloseLong(symbol string, quantity float64) (map[string]in
 	// Clear cache
 	t.clearCache()
 
-	logger.Infof("✓ Bitget closed long position successfully: %s", symbol)
+	t.log.Infof("✓ Bitget closed long position successfully: %s", symbol)
 
 	return map[string]interface{}{
 		"orderId": order.OrderId,
@@ -225,7 +224,7 @@ func (t *BitgetTrader) CloseShort(symbol string, quantity float64) (map[string]i
 		"clientOid":   genBitgetClientOid(),
 	}
 
-	logger.Infof("  📊 Bitget CloseShort: symbol=%s, qty=%s", symbol, qtyStr)
+	t.log.Infof("  📊 Bitget CloseShort: symbol=%s, qty=%s", symbol, qtyStr)
 
 	data, err := t.doRequest("POST", bitgetOrderPath, body)
 	if err != nil {
@@ -243,7 +242,7 @@ func (t *BitgetTrader) CloseShort(symbol string, quantity float64) (map[string]i
 	// Clear cache
 	t.clearCache()
 
-	logger.Infof("✓ Bitget closed short position successfully: %s", symbol)
+	t.log.Infof("✓ Bitget closed short position successfully: %s", symbol)
 
 	return map[string]interface{}{
 		"orderId": order.OrderId,
@@ -287,7 +286,7 @@ func (t *BitgetTrader) SetStopLoss(symbol string, positionSide string, quantity,
 		return fmt.Errorf("failed to set stop loss: %w", err)
 	}
 
-	logger.Infof("  ✓ [Bitget] Stop loss set: %s @ %.4f", symbol, stopPrice)
+	t.log.Infof("  ✓ [Bitget] Stop loss set: %s @ %.4f", symbol, stopPrice)
 	return nil
 }
 
@@ -326,7 +325,7 @@ func (t *BitgetTrader) SetTakeProfit(symbol string, positionSide string, quantit
 		return fmt.Errorf("failed to set take profit: %w", err)
 	}
 
-	logger.Infof("  ✓ [Bitget] Take profit set: %s @ %.4f", symbol, takeProfitPrice)
+	t.log.Infof("  ✓ [Bitget] Take profit set: %s @ %.4f", symbol, takeProfitPrice)
 	return nil
 }
 
@@ -507,7 +506,7 @@ func (t *BitgetTrader) GetOpenOrders(symbol string) ([]types.OpenOrder, error) {
 
 	data, err := t.doRequest("GET", bitgetPendingPath, params)
 	if err != nil {
-		logger.Warnf("[Bitget] Failed to get pending orders: %v", err)
+		t.log.Warnf("[Bitget] Failed to get pending orders: %v", err)
 	}
 	if err == nil && data != nil {
 		var orders struct {
@@ -556,7 +555,7 @@ func (t *BitgetTrader) GetOpenOrders(symbol string) ([]types.OpenOrder, error) {
 
 	planData, err := t.doRequest("GET", "/api/v2/mix/order/orders-plan-pending", planParams)
 	if err != nil {
-		logger.Warnf("[Bitget] Failed to get plan orders: %v", err)
+		t.log.Warnf("[Bitget] Failed to get plan orders: %v", err)
 	}
 	if err == nil && planData != nil {
 		var planOrders struct {
@@ -620,7 +619,7 @@ func (t *BitgetTrader) GetOpenOrders(symbol string) ([]types.OpenOrder, error) {
 		}
 	}
 
-	logger.Infof("✓ BITGET GetOpenOrders: found %d open orders for %s", len(result), symbol)
+	t.log.Infof("✓ BITGET GetOpenOrders: found %d open orders for %s", len(result), symbol)
 	return result, nil
 }
 
@@ -632,7 +631,7 @@ func (t *BitgetTrader) PlaceLimitOrder(req *types.LimitOrderRequest) (*types.Lim
 	// Set leverage if specified
 	if req.Leverage > 0 {
 		if err := t.SetLeverage(symbol, req.Leverage); err != nil {
-			logger.Warnf("[Bitget] Failed to set leverage: %v", err)
+			t.log.Warnf("[Bitget] Failed to set leverage: %v", err)
 		}
 	}
 
@@ -663,7 +662,7 @@ func (t *BitgetTrader) PlaceLimitOrder(req *types.LimitOrderRequest) (*types.Lim
 		body["reduceOnly"] = "YES"
 	}
 
-	logger.Infof("[Bitget] PlaceLimitOrder: %s %s @ %.4f, qty=%s", symbol, side, req.Price, qtyStr)
+	t.log.Infof("[Bitget] PlaceLimitOrder: %s %s @ %.4f, qty=%s", symbol, side, req.Price, qtyStr)
 
 	data, err := t.doRequest("POST", bitgetOrderPath, body)
 	if err != nil {
@@ -679,7 +678,7 @@ func (t *BitgetTrader) PlaceLimitOrder(req *types.LimitOrderRequest) (*types.Lim
 		return nil, fmt.Errorf("failed to parse order response: %w", err)
 	}
 
-	logger.Infof("✓ [Bitget] Limit order placed: %s %s @ %.4f, orderID=%s",
+	t.log.Infof("✓ [Bitget] Limit order placed: %s %s @ %.4f, orderID=%s",
 		symbol, side, req.Price, order.OrderId)
 
 	return &types.LimitOrderResult{
@@ -710,6 +709,6 @@ func (t *BitgetTrader) CancelOrder(symbol, orderID string) error {
 		return fmt.Errorf("failed to cancel order: %w", err)
 	}
 
-	logger.Infof("✓ [Bitget] Order cancelled: %s %s", symbol, orderID)
+	t.log.Infof("✓ [Bitget] Order cancelled: %s %s", symbol, orderID)
 	return nil
 }
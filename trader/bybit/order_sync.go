@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"nofx/logger"
 	"nofx/market"
 	"nofx/store"
 	"nofx/trader/syncloop"
@@ -184,7 +183,7 @@ func (t *BybitTrader) SyncOrdersFromBybit(traderID string, exchangeID string, ex
 	// Get recent trades (last 24 hours)
 	startTime := time.Now().Add(-24 * time.Hour)
 
-	logger.Infof("🔄 Syncing Bybit trades from: %s", startTime.Format(time.RFC3339))
+	t.log.Infof("🔄 Syncing Bybit trades from: %s", startTime.Format(time.RFC3339))
 
 	// Use GetTrades method to fetch trade records
 	trades, err := t.GetTrades(startTime, 1000)
@@ -192,7 +191,7 @@ func (t *BybitTrader) SyncOrdersFromBybit(traderID string, exchangeID string, ex
 		return fmt.Errorf("failed to get trades: %w", err)
 	}
 
-	logger.Infof("📥 Received %d trades from Bybit", len(trades))
+	t.log.Infof("📥 Received %d trades from Bybit", len(trades))
 
 	// Sort trades by time ASC (oldest first) for proper position building
 	sort.Slice(trades, func(i, j int) bool {
@@ -249,7 +248,7 @@ func (t *BybitTrader) SyncOrdersFromBybit(traderID string, exchangeID string, ex
 
 		// Insert order record
 		if err := orderStore.CreateOrder(orderRecord); err != nil {
-			logger.Infof("  ⚠️ Failed to sync trade %s: %v", trade.ExecID, err)
+			t.log.Infof("  ⚠️ Failed to sync trade %s: %v", trade.ExecID, err)
 			continue
 		}
 
@@ -274,7 +273,7 @@ func (t *BybitTrader) SyncOrdersFromBybit(traderID string, exchangeID string, ex
 		}
 
 		if err := orderStore.CreateFill(fillRecord); err != nil {
-			logger.Infof("  ⚠️ Failed to sync fill for trade %s: %v", trade.ExecID, err)
+			t.log.Infof("  ⚠️ Failed to sync fill for trade %s: %v", trade.ExecID, err)
 		}
 
 		// Create/update position record using PositionBuilder
@@ -284,23 +283,23 @@ func (t *BybitTrader) SyncOrdersFromBybit(traderID string, exchangeID string, ex
 			trade.ExecQty, trade.ExecPrice, trade.ExecFee, trade.ClosedPnL,
 			execTimeMs, trade.ExecID,
 		); err != nil {
-			logger.Infof("  ⚠️ Failed to sync position for trade %s: %v", trade.ExecID, err)
+			t.log.Infof("  ⚠️ Failed to sync position for trade %s: %v", trade.ExecID, err)
 		} else {
-			logger.Infof("  📍 Position updated for trade: %s (action: %s, qty: %.6f)", trade.ExecID, trade.OrderAction, trade.ExecQty)
+			t.log.Infof("  📍 Position updated for trade: %s (action: %s, qty: %.6f)", trade.ExecID, trade.OrderAction, trade.ExecQty)
 		}
 
 		syncedCount++
-		logger.Infof("  ✅ Synced trade: %s %s %s qty=%.6f price=%.6f pnl=%.2f fee=%.6f action=%s",
+		t.log.Infof("  ✅ Synced trade: %s %s %s qty=%.6f price=%.6f pnl=%.2f fee=%.6f action=%s",
 			trade.ExecID, symbol, side, trade.ExecQty, trade.ExecPrice, trade.ClosedPnL, trade.ExecFee, trade.OrderAction)
 	}
 
-	logger.Infof("✅ Bybit order sync completed: %d new trades synced", syncedCount)
+	t.log.Infof("✅ Bybit order sync completed: %d new trades synced", syncedCount)
 	return nil
 }
 
 // StartOrderSync starts background order sync task for Bybit
 func (t *BybitTrader) StartOrderSync(traderID string, exchangeID string, exchangeType string, st *store.Store, interval time.Duration, stop <-chan struct{}) {
-	syncloop.Run(stop, interval, "Bybit", func() error {
+	syncloop.RunForTrader(stop, interval, "Bybit", traderID, func() error {
 		return t.SyncOrdersFromBybit(traderID, exchangeID, exchangeType, st)
 	})
 }
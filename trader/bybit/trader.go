@@ -37,6 +37,12 @@ type BybitTrader struct {
 
 	// Cache duration (15 seconds)
 	cacheDuration time.Duration
+
+	log *logger.Scope // see types.LogScoped
+}
+
+func (t *BybitTrader) SetLogScope(scope *logger.Scope) {
+	t.log = scope
 }
 
 // NewBybitTrader creates a Bybit trader
@@ -102,7 +108,7 @@ func (t *BybitTrader) getQtyStep(symbol string) float64 {
 	url := fmt.Sprintf("https://api.bybit.com/v5/market/instruments-info?category=linear&symbol=%s", symbol)
 	resp, err := http.Get(url)
 	if err != nil {
-		logger.Infof("⚠️ [Bybit] Failed to get precision info for %s: %v", symbol, err)
+		t.log.Infof("⚠️ [Bybit] Failed to get precision info for %s: %v", symbol, err)
 		return 1 // Default to integer
 	}
 	defer resp.Body.Close()
@@ -141,7 +147,7 @@ func (t *BybitTrader) getQtyStep(symbol string) float64 {
 	t.qtyStepCache[symbol] = qtyStep
 	t.qtyStepCacheMutex.Unlock()
 
-	logger.Infof("🔵 [Bybit] %s qtyStep: %v", symbol, qtyStep)
+	t.log.Infof("🔵 [Bybit] %s qtyStep: %v", symbol, qtyStep)
 
 	return qtyStep
 }
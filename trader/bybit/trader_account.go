@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"nofx/trader/types"
 	"strconv"
 	"time"
@@ -106,9 +107,18 @@ func (t *BybitTrader) GetClosedPnL(startTime time.Time, limit int) ([]types.Clos
 
 // getClosedPnLViaHTTP makes direct HTTP call to Bybit API for closed PnL with proper signing
 func (t *BybitTrader) getClosedPnLViaHTTP(startTime time.Time, limit int) ([]types.ClosedPnLRecord, error) {
-	// Build query string
 	queryParams := fmt.Sprintf("category=linear&startTime=%d&limit=%d", startTime.UnixMilli(), limit)
-	url := "https://api.bybit.com/v5/position/closed-pnl?" + queryParams
+	result, err := t.signedGet("/v5/position/closed-pnl", queryParams)
+	if err != nil {
+		return nil, err
+	}
+	return t.parseClosedPnLResult(result)
+}
+
+// signedGet makes a signed GET call to a Bybit V5 endpoint the SDK doesn't
+// expose and returns the "result" object
+func (t *BybitTrader) signedGet(endpoint, queryParams string) (map[string]interface{}, error) {
+	url := "https://api.bybit.com" + endpoint + "?" + queryParams
 
 	// Generate timestamp
 	timestamp := fmt.Sprintf("%d", time.Now().UnixMilli())
@@ -162,7 +172,73 @@ func (t *BybitTrader) getClosedPnLViaHTTP(startTime time.Time, limit int) ([]typ
 		return nil, fmt.Errorf("Bybit API error: %s", result.RetMsg)
 	}
 
-	return t.parseClosedPnLResult(result.Result)
+	return result.Result, nil
+}
+
+// fundingPageLimit caps the transaction log pages read per funding sync
+const fundingPageLimit = 20
+
+// GetFundingPayments retrieves funding settlements from the Bybit transaction
+// log. The log is newest first and paged by cursor, so all pages since
+// startTime are read and the oldest limit payments returned; the next sync
+// continues after them.
+func (t *BybitTrader) GetFundingPayments(startTime time.Time, limit int) ([]types.FundingPayment, error) {
+	// The transaction log only covers 7 days per query
+	if minStart := time.Now().Add(-7 * 24 * time.Hour); startTime.Before(minStart) {
+		startTime = minStart
+	}
+	var payments []types.FundingPayment
+	cursor := ""
+	for page := 0; page < fundingPageLimit; page++ {
+		queryParams := "accountType=UNIFIED&category=linear"
+		if cursor != "" {
+			queryParams += "&cursor=" + url.QueryEscape(cursor)
+		}
+		queryParams += fmt.Sprintf("&limit=50&startTime=%d&type=SETTLEMENT", startTime.UnixMilli())
+		result, err := t.signedGet("/v5/account/transaction-log", queryParams)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get funding settlements: %w", err)
+		}
+
+		list, _ := result["list"].([]interface{})
+		for _, item := range list {
+			entry, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			id, _ := entry["id"].(string)
+			symbol, _ := entry["symbol"].(string)
+			changeStr, _ := entry["change"].(string)
+			rateStr, _ := entry["feeRate"].(string)
+			timeStr, _ := entry["transactionTime"].(string)
+
+			amount, err := strconv.ParseFloat(changeStr, 64)
+			if err != nil || amount == 0 || id == "" {
+				continue
+			}
+			rate, _ := strconv.ParseFloat(rateStr, 64)
+			ts, _ := strconv.ParseInt(timeStr, 10, 64)
+			payments = append(payments, types.FundingPayment{
+				PaymentID: id,
+				Symbol:    symbol,
+				Amount:    amount,
+				Rate:      rate,
+				Time:      time.UnixMilli(ts).UTC(),
+			})
+		}
+		cursor, _ = result["nextPageCursor"].(string)
+		if cursor == "" || len(list) == 0 {
+			break
+		}
+	}
+	// The log is newest first
+	for i, j := 0, len(payments)-1; i < j; i, j = i+1, j-1 {
+		payments[i], payments[j] = payments[j], payments[i]
+	}
+	if limit > 0 && len(payments) > limit {
+		payments = payments[:limit]
+	}
+	return payments, nil
 }
 
 // parseClosedPnLResult parses the closed PnL result from Bybit API
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"nofx/logger"
 	"nofx/trader/types"
 	"strconv"
 	"strings"
@@ -14,20 +13,20 @@ import (
 
 // OpenLong opens a long position
 func (t *BybitTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
-	logger.Infof("[Bybit] ===== OpenLong called: symbol=%s, qty=%.6f, leverage=%d =====", symbol, quantity, leverage)
+	t.log.Infof("[Bybit] ===== OpenLong called: symbol=%s, qty=%.6f, leverage=%d =====", symbol, quantity, leverage)
 
 	// First cancel all pending orders for this symbol (clean up old orders)
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("⚠️ [Bybit] Failed to cancel old pending orders: %v", err)
+		t.log.Infof("⚠️ [Bybit] Failed to cancel old pending orders: %v", err)
 	}
 	// Also cancel conditional orders (stop-loss/take-profit) - Bybit keeps them separate
 	if err := t.CancelStopOrders(symbol); err != nil {
-		logger.Infof("⚠️ [Bybit] Failed to cancel old stop orders: %v", err)
+		t.log.Infof("⚠️ [Bybit] Failed to cancel old stop orders: %v", err)
 	}
 
 	// Set leverage first
 	if err := t.SetLeverage(symbol, leverage); err != nil {
-		logger.Infof("⚠️ [Bybit] Failed to set leverage: %v", err)
+		t.log.Infof("⚠️ [Bybit] Failed to set leverage: %v", err)
 	}
 
 	// Use FormatQuantity to format quantity
@@ -42,7 +41,7 @@ func (t *BybitTrader) OpenLong(symbol string, quantity float64, leverage int) (m
 		"positionIdx": 0, // One-way position mode
 	}
 
-	logger.Infof("[Bybit] OpenLong placing order: %+v", params)
+	t.log.Infof("[Bybit] OpenLong placing order: %+v", params)
 
 	result, err := t.client.NewUtaBybitServiceWithParams(params).PlaceOrder(context.Background())
 	if err != nil {
@@ -57,20 +56,20 @@ func (t *BybitTrader) OpenLong(symbol string, quantity float64, leverage int) (m
 
 // OpenShort opens a short position
 func (t *BybitTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
-	logger.Infof("[Bybit] ===== OpenShort called: symbol=%s, qty=%.6f, leverage=%d =====", symbol, quantity, leverage)
+	t.log.Infof("[Bybit] ===== OpenShort called: symbol=%s, qty=%.6f, leverage=%d =====", symbol, quantity, leverage)
 
 	// First cancel all pending orders for this symbol (clean up old orders)
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("⚠️ [Bybit] Failed to cancel old pending orders: %v", err)
+		t.log.Infof("⚠️ [Bybit] Failed to cancel old pending orders: %v", err)
 	}
 	// Also cancel conditional orders (stop-loss/take-profit) - Bybit keeps them separate
 	if err := t.CancelStopOrders(symbol); err != nil {
-		logger.Infof("⚠️ [Bybit] Failed to cancel old stop orders: %v", err)
+		t.log.Infof("⚠️ [Bybit] Failed to cancel old stop orders: %v", err)
 	}
 
 	// Set leverage first
 	if err := t.SetLeverage(symbol, leverage); err != nil {
-		logger.Infof("⚠️ [Bybit] Failed to set leverage: %v", err)
+		t.log.Infof("⚠️ [Bybit] Failed to set leverage: %v", err)
 	}
 
 	// Use FormatQuantity to format quantity
@@ -85,7 +84,7 @@ func (t *BybitTrader) OpenShort(symbol string, quantity float64, leverage int) (
 		"positionIdx": 0, // One-way position mode
 	}
 
-	logger.Infof("[Bybit] OpenShort placing order: %+v", params)
+	t.log.Infof("[Bybit] OpenShort placing order: %+v", params)
 
 	result, err := t.client.NewUtaBybitServiceWithParams(params).PlaceOrder(context.Background())
 	if err != nil {
@@ -320,7 +319,7 @@ func (t *BybitTrader) SetStopLoss(symbol string, positionSide string, quantity,
 		return fmt.Errorf("failed to set stop loss: %s", result.RetMsg)
 	}
 
-	logger.Infof("  ✓ [Bybit] Stop loss order set: %s @ %.2f", symbol, stopPrice)
+	t.log.Infof("  ✓ [Bybit] Stop loss order set: %s @ %.2f", symbol, stopPrice)
 	return nil
 }
 
@@ -366,7 +365,7 @@ func (t *BybitTrader) SetTakeProfit(symbol string, positionSide string, quantity
 		return fmt.Errorf("failed to set take profit: %s", result.RetMsg)
 	}
 
-	logger.Infof("  ✓ [Bybit] Take profit order set: %s @ %.2f", symbol, takeProfitPrice)
+	t.log.Infof("  ✓ [Bybit] Take profit order set: %s @ %.2f", symbol, takeProfitPrice)
 	return nil
 }
 
@@ -398,10 +397,10 @@ func (t *BybitTrader) CancelAllOrders(symbol string) error {
 // CancelStopOrders cancels all stop loss and take profit orders
 func (t *BybitTrader) CancelStopOrders(symbol string) error {
 	if err := t.CancelStopLossOrders(symbol); err != nil {
-		logger.Infof("⚠️ [Bybit] Failed to cancel stop loss orders: %v", err)
+		t.log.Infof("⚠️ [Bybit] Failed to cancel stop loss orders: %v", err)
 	}
 	if err := t.CancelTakeProfitOrders(symbol); err != nil {
-		logger.Infof("⚠️ [Bybit] Failed to cancel take profit orders: %v", err)
+		t.log.Infof("⚠️ [Bybit] Failed to cancel take profit orders: %v", err)
 	}
 	return nil
 }
@@ -599,7 +598,7 @@ func (t *BybitTrader) PlaceLimitOrder(req *types.LimitOrderRequest) (*types.Limi
 	// Set leverage if specified
 	if req.Leverage > 0 {
 		if err := t.SetLeverage(req.Symbol, req.Leverage); err != nil {
-			logger.Warnf("[Bybit] Failed to set leverage: %v", err)
+			t.log.Warnf("[Bybit] Failed to set leverage: %v", err)
 		}
 	}
 
@@ -625,7 +624,7 @@ func (t *BybitTrader) PlaceLimitOrder(req *types.LimitOrderRequest) (*types.Limi
 		params["reduceOnly"] = true
 	}
 
-	logger.Infof("[Bybit] PlaceLimitOrder: %s %s @ %s, qty=%s", req.Symbol, side, priceStr, qtyStr)
+	t.log.Infof("[Bybit] PlaceLimitOrder: %s %s @ %s, qty=%s", req.Symbol, side, priceStr, qtyStr)
 
 	result, err := t.client.NewUtaBybitServiceWithParams(params).PlaceOrder(context.Background())
 	if err != nil {
@@ -644,7 +643,7 @@ func (t *BybitTrader) PlaceLimitOrder(req *types.LimitOrderRequest) (*types.Limi
 		return nil, fmt.Errorf("Bybit order failed: %s", result.RetMsg)
 	}
 
-	logger.Infof("✓ [Bybit] Limit order placed: %s %s @ %s, qty=%s, orderID=%s",
+	t.log.Infof("✓ [Bybit] Limit order placed: %s %s @ %s, qty=%s, orderID=%s",
 		req.Symbol, side, priceStr, qtyStr, orderID)
 
 	return &types.LimitOrderResult{
@@ -677,7 +676,7 @@ func (t *BybitTrader) CancelOrder(symbol, orderID string) error {
 		return fmt.Errorf("Bybit cancel order failed: %s", result.RetMsg)
 	}
 
-	logger.Infof("✓ [Bybit] Order cancelled: %s %s", symbol, orderID)
+	t.log.Infof("✓ [Bybit] Order cancelled: %s %s", symbol, orderID)
 	return nil
 }
 
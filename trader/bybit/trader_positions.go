@@ -3,7 +3,6 @@ package bybit
 import (
 	"context"
 	"fmt"
-	"nofx/logger"
 	"strconv"
 	"strings"
 	"time"
@@ -84,7 +83,7 @@ func (t *BybitTrader) GetPositions() ([]map[string]interface{}, error) {
 		positionSide, _ := pos["side"].(string) // Buy = long, Sell = short
 
 		// Log raw position data for debugging
-		logger.Infof("[Bybit] GetPositions raw: symbol=%v, side=%s, size=%v", pos["symbol"], positionSide, sizeStr)
+		t.log.Infof("[Bybit] GetPositions raw: symbol=%v, side=%s, size=%v", pos["symbol"], positionSide, sizeStr)
 
 		// Convert to unified format (use lowercase for consistency with other exchanges)
 		// Bybit returns "Buy" for long, "Sell" for short
@@ -96,7 +95,7 @@ func (t *BybitTrader) GetPositions() ([]map[string]interface{}, error) {
 			positionAmt = -size
 		}
 
-		logger.Infof("[Bybit] GetPositions converted: symbol=%v, rawSide=%s -> side=%s", pos["symbol"], positionSide, side)
+		t.log.Infof("[Bybit] GetPositions converted: symbol=%v, rawSide=%s -> side=%s", pos["symbol"], positionSide, side)
 
 		position := map[string]interface{}{
 			"symbol":           pos["symbol"],
@@ -0,0 +1,219 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/kernel"
+	"nofx/logger"
+	"nofx/store"
+	"time"
+)
+
+// breakerInput what the account circuit breakers are evaluated on
+type breakerInput struct {
+	Equity            float64 // current total equity
+	DayStartEquity    float64 // first equity snapshot of the UTC day, 0 when unknown
+	PeakEquity        float64 // highest equity since the breaker window started, 0 when unknown
+	ConsecutiveLosses int     // losing closed trades in a row since the window started
+}
+
+// evaluateCircuitBreakers returns why a breaker trips, or "" when none does.
+// daily is true when the daily loss limit tripped.
+func evaluateCircuitBreakers(cfg store.CircuitBreakerConfig, in breakerInput) (reason string, daily bool) {
+	if in.Equity <= 0 {
+		return "", false
+	}
+	if cfg.DailyLossPct > 0 && in.DayStartEquity > 0 {
+		lossPct := (in.DayStartEquity - in.Equity) / in.DayStartEquity * 100
+		if lossPct >= cfg.DailyLossPct {
+			return fmt.Sprintf("daily loss %.2f%% (%.2f → %.2f USDT) reached the %.2f%% limit",
+				lossPct, in.DayStartEquity, in.Equity, cfg.DailyLossPct), true
+		}
+	}
+	if cfg.MaxDrawdownPct > 0 && in.PeakEquity > 0 {
+		drawdownPct := (in.PeakEquity - in.Equity) / in.PeakEquity * 100
+		if drawdownPct >= cfg.MaxDrawdownPct {
+			return fmt.Sprintf("drawdown %.2f%% from the %.2f USDT peak reached the %.2f%% limit",
+				drawdownPct, in.PeakEquity, cfg.MaxDrawdownPct), false
+		}
+	}
+	if cfg.MaxConsecutiveLosses > 0 && in.ConsecutiveLosses >= cfg.MaxConsecutiveLosses {
+		return fmt.Sprintf("%d consecutive losing trades reached the limit of %d",
+			in.ConsecutiveLosses, cfg.MaxConsecutiveLosses), false
+	}
+	return "", false
+}
+
+// circuitBreakerConfig returns the strategy's circuit breakers, with the
+// trader-level MaxDailyLoss / MaxDrawdown / StopTradingTime filling the
+// limits the strategy leaves unset
+func (at *AutoTrader) circuitBreakerConfig() store.CircuitBreakerConfig {
+	var cfg store.CircuitBreakerConfig
+	if at.config.StrategyConfig != nil {
+		cfg = at.config.StrategyConfig.RiskControl.CircuitBreaker
+	}
+	if cfg.DailyLossPct <= 0 {
+		cfg.DailyLossPct = at.config.MaxDailyLoss
+	}
+	if cfg.MaxDrawdownPct <= 0 {
+		cfg.MaxDrawdownPct = at.config.MaxDrawdown
+	}
+	if cfg.CooldownMinutes <= 0 && at.config.StopTradingTime > 0 {
+		cfg.CooldownMinutes = int(at.config.StopTradingTime.Minutes())
+	}
+	return cfg.WithDefaults()
+}
+
+// breakerPauseActive reports whether a pause_opens trip is still blocking opens
+func (at *AutoTrader) breakerPauseActive() bool {
+	return at.breakerPauseOpens && time.Now().Before(at.stopUntil)
+}
+
+// checkCircuitBreakers evaluates the account circuit breakers after this
+// cycle's equity snapshot was saved and fires the configured action when one
+// trips. It returns true when the rest of the cycle must be skipped.
+//
+// The drawdown peak and the losing streak only count from the trader's first
+// start or the last trip, so a trip is not repeated for the same losses once
+// the cooldown ends. Both the window and the pause are saved with the trader
+// and survive restarts.
+func (at *AutoTrader) checkCircuitBreakers(ctx *kernel.Context, record *store.DecisionRecord) bool {
+	cfg := at.circuitBreakerConfig()
+	if !cfg.Enabled() || at.store == nil || at.breakerPauseActive() {
+		return false
+	}
+
+	now := time.Now().UTC()
+	if at.breakerSince.IsZero() {
+		at.breakerSince = at.startTime
+		at.saveBreakerState("")
+	}
+	in := breakerInput{Equity: ctx.Account.TotalEquity}
+	if cfg.DailyLossPct > 0 {
+		dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		first, err := at.store.Equity().GetFirstSince(at.id, dayStart)
+		if err != nil {
+			at.logWarnf("⚠️ Circuit breaker: failed to load day-start equity: %v", err)
+		} else if first != nil {
+			in.DayStartEquity = first.TotalEquity
+		}
+	}
+	if cfg.MaxDrawdownPct > 0 {
+		peak, err := at.store.Equity().GetPeakEquity(at.id, at.breakerSince)
+		if err != nil {
+			at.logWarnf("⚠️ Circuit breaker: failed to load peak equity: %v", err)
+		}
+		in.PeakEquity = peak
+	}
+	if cfg.MaxConsecutiveLosses > 0 {
+		losses, err := at.store.Position().GetConsecutiveLosses(at.id, at.breakerSince)
+		if err != nil {
+			at.logWarnf("⚠️ Circuit breaker: failed to load losing streak: %v", err)
+		}
+		in.ConsecutiveLosses = losses
+	}
+
+	reason, daily := evaluateCircuitBreakers(cfg, in)
+	if reason == "" {
+		return false
+	}
+
+	until := now.Add(time.Duration(cfg.CooldownMinutes) * time.Minute)
+	if daily {
+		if nextDay := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC); nextDay.After(until) {
+			until = nextDay
+		}
+	}
+	at.breakerSince = now
+	at.stopUntil = until
+	at.breakerPauseOpens = cfg.Action == store.BreakerActionPauseOpens
+	at.breakerReason = reason
+	at.saveBreakerState(cfg.Action)
+
+	at.logErrorf("⛔ CIRCUIT BREAKER TRIPPED: %s → %s until %s", reason, cfg.Action, until.Format(time.RFC3339))
+	record.ExecutionLog = append(record.ExecutionLog,
+		fmt.Sprintf("⛔ Circuit breaker: %s → %s until %s", reason, cfg.Action, until.Format(time.RFC3339)))
+
+	if cfg.Action == store.BreakerActionCloseAll || cfg.Action == store.BreakerActionStopTrader {
+		for _, pos := range ctx.Positions {
+			if err := at.emergencyClosePosition(pos.Symbol, pos.Side); err != nil {
+				at.logErrorf("❌ Circuit breaker: failed to close %s %s: %v", pos.Symbol, pos.Side, err)
+				record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("❌ Close %s %s failed: %v", pos.Symbol, pos.Side, err))
+				continue
+			}
+			record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("✓ Closed %s %s", pos.Symbol, pos.Side))
+		}
+	}
+	at.notifyCircuitBreaker(reason, cfg.Action, until)
+
+	if cfg.Action == store.BreakerActionStopTrader {
+		if err := at.store.Trader().UpdateStatus(at.userID, at.id, false); err != nil {
+			at.logWarnf("⚠️ Circuit breaker: failed to persist stopped status: %v", err)
+		}
+		// Stop waits for Run to return, so it cannot be called from the loop itself
+		go at.Stop()
+	}
+	return cfg.Action != store.BreakerActionPauseOpens
+}
+
+// restoreBreakerState loads the breaker window and any running pause saved by
+// an earlier run of this trader. The pause of a stop_trader trip is not
+// restored: starting the trader again is the owner's go-ahead to resume.
+func (at *AutoTrader) restoreBreakerState() {
+	if at.store == nil || !at.breakerSince.IsZero() {
+		return
+	}
+	state, err := at.store.Trader().GetBreakerState(at.id)
+	if err != nil {
+		at.logWarnf("⚠️ Circuit breaker: %v", err)
+		return
+	}
+	if state == nil {
+		return
+	}
+	at.breakerSince = state.Since
+	if state.Action == store.BreakerActionStopTrader || !time.Now().Before(state.StopUntil) {
+		return
+	}
+	at.stopUntil = state.StopUntil
+	at.breakerPauseOpens = state.Action == store.BreakerActionPauseOpens
+	at.breakerReason = state.Reason
+	at.logInfof("⛔ Circuit breaker pause restored: %s → %s until %s", state.Reason, state.Action, state.StopUntil.Format(time.RFC3339))
+}
+
+// saveBreakerState persists the breaker window and the pause of the trip
+// that set it, if any
+func (at *AutoTrader) saveBreakerState(action string) {
+	state := &store.TraderBreakerState{
+		TraderID:  at.id,
+		Since:     at.breakerSince,
+		StopUntil: at.stopUntil,
+		Action:    action,
+		Reason:    at.breakerReason,
+	}
+	if err := at.store.Trader().SaveBreakerState(state); err != nil {
+		at.logWarnf("⚠️ Circuit breaker: %v", err)
+	}
+}
+
+// notifyCircuitBreaker tells the trader's owner that a breaker tripped
+func (at *AutoTrader) notifyCircuitBreaker(reason, action string, until time.Time) {
+	if at.userID == "" {
+		return
+	}
+	body := fmt.Sprintf("%s. Action: %s.", reason, action)
+	if action == store.BreakerActionStopTrader {
+		body += " The trader was stopped; start it again once you have reviewed the losses."
+	} else {
+		body += fmt.Sprintf(" Trading resumes after %s UTC.", until.Format("2006-01-02 15:04"))
+	}
+	n := &store.Notification{
+		UserID: at.userID,
+		Kind:   store.NotificationRiskBreaker,
+		Title:  fmt.Sprintf("Circuit breaker tripped on %s", at.name),
+		Body:   body,
+		Ref:    at.id,
+	}
+	if err := at.store.Notification().Create(n); err != nil {
+		logger.Warnf("⚠️ Failed to save circuit breaker notification: %v", err)
+	}
+}
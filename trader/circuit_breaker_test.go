@@ -0,0 +1,81 @@
+package trader
+
+import (
+	"nofx/store"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEvaluateCircuitBreakers(t *testing.T) {
+	cfg := store.CircuitBreakerConfig{DailyLossPct: 5, MaxDrawdownPct: 10, MaxConsecutiveLosses: 3}
+
+	if reason, _ := evaluateCircuitBreakers(cfg, breakerInput{Equity: 970, DayStartEquity: 1000, PeakEquity: 1050, ConsecutiveLosses: 2}); reason != "" {
+		t.Fatalf("no limit reached, got %q", reason)
+	}
+	reason, daily := evaluateCircuitBreakers(cfg, breakerInput{Equity: 950, DayStartEquity: 1000})
+	if !daily || !strings.Contains(reason, "daily loss") {
+		t.Fatalf("daily loss should trip, got %q daily=%v", reason, daily)
+	}
+	reason, daily = evaluateCircuitBreakers(cfg, breakerInput{Equity: 1080, DayStartEquity: 1080, PeakEquity: 1200})
+	if daily || !strings.Contains(reason, "drawdown") {
+		t.Fatalf("drawdown should trip, got %q daily=%v", reason, daily)
+	}
+	if reason, _ = evaluateCircuitBreakers(cfg, breakerInput{Equity: 1000, ConsecutiveLosses: 3}); !strings.Contains(reason, "consecutive") {
+		t.Fatalf("losing streak should trip, got %q", reason)
+	}
+	// Unknown equity never trips
+	if reason, _ = evaluateCircuitBreakers(cfg, breakerInput{DayStartEquity: 1000, ConsecutiveLosses: 9}); reason != "" {
+		t.Fatalf("zero equity should not trip, got %q", reason)
+	}
+}
+
+func TestCircuitBreakerConfigFallsBackToTraderConfig(t *testing.T) {
+	at := &AutoTrader{config: AutoTraderConfig{
+		MaxDailyLoss:    4,
+		MaxDrawdown:     12,
+		StopTradingTime: 30 * time.Minute,
+		StrategyConfig: &store.StrategyConfig{RiskControl: store.RiskControlConfig{
+			CircuitBreaker: store.CircuitBreakerConfig{DailyLossPct: 2, Action: store.BreakerActionCloseAll},
+		}},
+	}}
+	cfg := at.circuitBreakerConfig()
+	if cfg.DailyLossPct != 2 || cfg.MaxDrawdownPct != 12 || cfg.CooldownMinutes != 30 || cfg.Action != store.BreakerActionCloseAll {
+		t.Fatalf("effective config = %+v", cfg)
+	}
+	if (&AutoTrader{}).circuitBreakerConfig().Enabled() {
+		t.Fatal("no limits configured should leave the breakers disabled")
+	}
+}
+
+func TestCircuitBreakerStateSurvivesRestart(t *testing.T) {
+	st, err := store.New(t.TempDir() + "/nofx.db")
+	if err != nil {
+		t.Fatalf("store.New: %v", err)
+	}
+	defer st.Close()
+
+	first := &AutoTrader{id: "t1", store: st}
+	first.breakerSince = time.Now().UTC().Add(-2 * time.Hour).Truncate(time.Second)
+	first.stopUntil = time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+	first.breakerPauseOpens = true
+	first.breakerReason = "drawdown 12.00% from the 1200.00 USDT peak reached the 10.00% limit"
+	first.saveBreakerState(store.BreakerActionPauseOpens)
+
+	restarted := &AutoTrader{id: "t1", store: st}
+	restarted.restoreBreakerState()
+	if !restarted.breakerSince.Equal(first.breakerSince) || !restarted.stopUntil.Equal(first.stopUntil) {
+		t.Fatalf("restored window %v / pause until %v, want %v / %v", restarted.breakerSince, restarted.stopUntil, first.breakerSince, first.stopUntil)
+	}
+	if !restarted.breakerPauseOpens || restarted.breakerReason != first.breakerReason || !restarted.breakerPauseActive() {
+		t.Fatalf("restored pause = %v %q", restarted.breakerPauseOpens, restarted.breakerReason)
+	}
+
+	// A stop_trader trip keeps its window but not its pause: the restart resumes trading
+	first.saveBreakerState(store.BreakerActionStopTrader)
+	restarted = &AutoTrader{id: "t1", store: st}
+	restarted.restoreBreakerState()
+	if !restarted.breakerSince.Equal(first.breakerSince) || !restarted.stopUntil.IsZero() || restarted.breakerReason != "" {
+		t.Fatalf("stop_trader restore = since %v, until %v, reason %q", restarted.breakerSince, restarted.stopUntil, restarted.breakerReason)
+	}
+}
@@ -0,0 +1,64 @@
+package trader
+
+import (
+	"nofx/store"
+	"time"
+)
+
+const (
+	// Funding settles every 1-8h; polling more often only re-reads the same payments
+	fundingSyncInterval = 10 * time.Minute
+	// How far back the first sync of a trader reaches
+	fundingInitialLookback = 7 * 24 * time.Hour
+	fundingSyncLimit       = 1000
+)
+
+// syncFundingPayments pulls new funding payments from the exchange and books
+// them on the positions held when they settled. Exchanges without a funding
+// history API are skipped.
+func (at *AutoTrader) syncFundingPayments() {
+	if at.store == nil || time.Since(at.lastFundingSync) < fundingSyncInterval {
+		return
+	}
+	provider, ok := at.trader.(FundingHistoryProvider)
+	if !ok {
+		return
+	}
+	at.lastFundingSync = time.Now()
+
+	start := time.Now().Add(-fundingInitialLookback)
+	if latest, err := at.store.Funding().GetLatestTime(at.id); err != nil {
+		at.logWarnf("⚠️ Funding sync: failed to load last payment time: %v", err)
+		return
+	} else if latest > 0 {
+		start = time.UnixMilli(latest) // inclusive, recorded payments are skipped
+	}
+
+	payments, err := provider.GetFundingPayments(start, fundingSyncLimit)
+	if err != nil {
+		at.logWarnf("⚠️ Funding sync failed: %v", err)
+		return
+	}
+	if len(payments) == 0 {
+		return
+	}
+
+	records := make([]store.FundingPayment, 0, len(payments))
+	for _, p := range payments {
+		records = append(records, store.FundingPayment{
+			PaymentID: p.PaymentID,
+			Symbol:    p.Symbol,
+			Amount:    p.Amount,
+			Rate:      p.Rate,
+			Time:      p.Time.UTC().UnixMilli(),
+		})
+	}
+	recorded, err := at.store.Funding().RecordPayments(at.id, records)
+	if err != nil {
+		at.logWarnf("⚠️ Funding sync: failed to record payments: %v", err)
+		return
+	}
+	if recorded > 0 {
+		at.logInfof("💸 Funding sync: booked %d new funding payments", recorded)
+	}
+}
@@ -2,7 +2,6 @@ package gate
 
 import (
 	"fmt"
-	"nofx/logger"
 	"nofx/market"
 	"nofx/store"
 	"nofx/trader/syncloop"
@@ -50,7 +49,7 @@ func (t *GateTrader) GetTrades(startTime time.Time, limit int) ([]GateTrade, err
 		return nil, fmt.Errorf("failed to get trade history: %w", err)
 	}
 
-	logger.Infof("📥 Received %d trades from Gate", len(trades))
+	t.log.Infof("📥 Received %d trades from Gate", len(trades))
 
 	result := make([]GateTrade, 0, len(trades))
 
@@ -63,7 +62,7 @@ func (t *GateTrader) GetTrades(startTime time.Time, limit int) ([]GateTrade, err
 
 		fillPrice, err := strconv.ParseFloat(trade.Price, 64)
 		if err != nil || fillPrice == 0 {
-			logger.Infof("⚠️  Gate trade %d: fillPrice parse issue - raw='%s' parsed=%.8f err=%v",
+			t.log.Infof("⚠️  Gate trade %d: fillPrice parse issue - raw='%s' parsed=%.8f err=%v",
 				trade.Id, trade.Price, fillPrice, err)
 		}
 
@@ -159,7 +158,7 @@ func (t *GateTrader) SyncOrdersFromGate(traderID string, exchangeID string, exch
 	// Get recent trades (last 24 hours)
 	startTime := time.Now().Add(-24 * time.Hour)
 
-	logger.Infof("🔄 Syncing Gate trades from: %s", startTime.Format(time.RFC3339))
+	t.log.Infof("🔄 Syncing Gate trades from: %s", startTime.Format(time.RFC3339))
 
 	// Use GetTrades method to fetch trade records
 	trades, err := t.GetTrades(startTime, 100)
@@ -167,7 +166,7 @@ func (t *GateTrader) SyncOrdersFromGate(traderID string, exchangeID string, exch
 		return fmt.Errorf("failed to get trades: %w", err)
 	}
 
-	logger.Infof("📥 Received %d trades from Gate", len(trades))
+	t.log.Infof("📥 Received %d trades from Gate", len(trades))
 
 	// Sort trades by time ASC (oldest first) for proper position building
 	sort.Slice(trades, func(i, j int) bool {
@@ -204,7 +203,7 @@ func (t *GateTrader) SyncOrdersFromGate(traderID string, exchangeID string, exch
 					trade.FillQty, trade.FillPrice, trade.Fee, trade.ProfitLoss,
 					execTimeMs, trade.TradeID,
 				); err != nil {
-					logger.Infof("  ⚠️ Retry position update for existing trade %s failed: %v", trade.TradeID, err)
+					t.log.Infof("  ⚠️ Retry position update for existing trade %s failed: %v", trade.TradeID, err)
 				}
 			}
 			continue
@@ -237,7 +236,7 @@ func (t *GateTrader) SyncOrdersFromGate(traderID string, exchangeID string, exch
 
 		// Insert order record
 		if err := orderStore.CreateOrder(orderRecord); err != nil {
-			logger.Infof("  ⚠️ Failed to sync trade %s: %v", trade.TradeID, err)
+			t.log.Infof("  ⚠️ Failed to sync trade %s: %v", trade.TradeID, err)
 			continue
 		}
 
@@ -262,13 +261,13 @@ func (t *GateTrader) SyncOrdersFromGate(traderID string, exchangeID string, exch
 		}
 
 		if err := orderStore.CreateFill(fillRecord); err != nil {
-			logger.Infof("  ⚠️ Failed to sync fill for trade %s: %v", trade.TradeID, err)
+			t.log.Infof("  ⚠️ Failed to sync fill for trade %s: %v", trade.TradeID, err)
 		}
 
 		// Create/update position record using PositionBuilder
 		// Debug: Log the price being passed to ensure it's not 0
 		if trade.FillPrice <= 0 {
-			logger.Infof("  ⚠️ WARNING: trade %s has FillPrice=%.10f (invalid), skipping position update", trade.TradeID, trade.FillPrice)
+			t.log.Infof("  ⚠️ WARNING: trade %s has FillPrice=%.10f (invalid), skipping position update", trade.TradeID, trade.FillPrice)
 		} else {
 			if err := posBuilder.ProcessTrade(
 				traderID, exchangeID, exchangeType,
@@ -276,24 +275,24 @@ func (t *GateTrader) SyncOrdersFromGate(traderID string, exchangeID string, exch
 				trade.FillQty, trade.FillPrice, trade.Fee, trade.ProfitLoss,
 				execTimeMs, trade.TradeID,
 			); err != nil {
-				logger.Infof("  ⚠️ Failed to sync position for trade %s: %v", trade.TradeID, err)
+				t.log.Infof("  ⚠️ Failed to sync position for trade %s: %v", trade.TradeID, err)
 			} else {
-				logger.Infof("  📍 Position updated for trade: %s (action: %s, qty: %.6f, price: %.10f)", trade.TradeID, trade.OrderAction, trade.FillQty, trade.FillPrice)
+				t.log.Infof("  📍 Position updated for trade: %s (action: %s, qty: %.6f, price: %.10f)", trade.TradeID, trade.OrderAction, trade.FillQty, trade.FillPrice)
 			}
 		}
 
 		syncedCount++
-		logger.Infof("  ✅ Synced trade: %s %s %s qty=%.6f price=%.6f pnl=%.2f fee=%.6f action=%s",
+		t.log.Infof("  ✅ Synced trade: %s %s %s qty=%.6f price=%.6f pnl=%.2f fee=%.6f action=%s",
 			trade.TradeID, symbol, side, trade.FillQty, trade.FillPrice, trade.ProfitLoss, trade.Fee, trade.OrderAction)
 	}
 
-	logger.Infof("✅ Gate order sync completed: %d new trades synced", syncedCount)
+	t.log.Infof("✅ Gate order sync completed: %d new trades synced", syncedCount)
 	return nil
 }
 
 // StartOrderSync starts background order sync task for Gate
 func (t *GateTrader) StartOrderSync(traderID string, exchangeID string, exchangeType string, st *store.Store, interval time.Duration, stop <-chan struct{}) {
-	syncloop.Run(stop, interval, "Gate", func() error {
+	syncloop.RunForTrader(stop, interval, "Gate", traderID, func() error {
 		return t.SyncOrdersFromGate(traderID, exchangeID, exchangeType, st)
 	})
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"nofx/logger"
 	"nofx/trader/types"
 	"strings"
 	"sync"
@@ -29,6 +30,12 @@ type GateTrader struct {
 	contractsCache      map[string]*gateapi.Contract
 	contractsCacheMutex sync.RWMutex
 	cacheDuration       time.Duration
+
+	log *logger.Scope // see types.LogScoped
+}
+
+func (t *GateTrader) SetLogScope(scope *logger.Scope) {
+	t.log = scope
 }
 
 // NewGateTrader creates a new Gate trader instance
@@ -3,7 +3,6 @@ package gate
 import (
 	"fmt"
 	"math"
-	"nofx/logger"
 	"nofx/trader/types"
 	"strconv"
 	"strings"
@@ -20,13 +19,13 @@ func (t *GateTrader) SetLeverage(symbol string, leverage int) error {
 	if err != nil {
 		// Gate.io may return error if leverage is already set
 		if strings.Contains(err.Error(), "RISK_LIMIT_EXCEEDED") {
-			logger.Warnf("  [Gate] Leverage %d exceeds limit for %s", leverage, symbol)
+			t.log.Warnf("  [Gate] Leverage %d exceeds limit for %s", leverage, symbol)
 			return nil
 		}
 		return fmt.Errorf("failed to set leverage: %w", err)
 	}
 
-	logger.Infof("  [Gate] Leverage set to %dx for %s", leverage, symbol)
+	t.log.Infof("  [Gate] Leverage set to %dx for %s", leverage, symbol)
 	return nil
 }
 
@@ -35,7 +34,7 @@ func (t *GateTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
 	// Gate.io uses leverage=0 for cross margin, positive number for isolated
 	// This is handled through UpdatePositionLeverage with cross_leverage_limit
 	// For now, we'll skip explicit margin mode setting as it's tied to leverage
-	logger.Infof("  [Gate] Margin mode is set through leverage (0=cross)")
+	t.log.Infof("  [Gate] Margin mode is set through leverage (0=cross)")
 	return nil
 }
 
@@ -45,12 +44,12 @@ func (t *GateTrader) OpenLong(symbol string, quantity float64, leverage int) (ma
 
 	// Cancel old orders first
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
+		t.log.Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
 	}
 
 	// Set leverage
 	if err := t.SetLeverage(symbol, leverage); err != nil {
-		logger.Warnf("  [Gate] Failed to set leverage: %v", err)
+		t.log.Warnf("  [Gate] Failed to set leverage: %v", err)
 	}
 
 	// Get contract info for size calculation
@@ -75,7 +74,7 @@ func (t *GateTrader) OpenLong(symbol string, quantity float64, leverage int) (ma
 		Text:     "t-nofx",
 	}
 
-	logger.Infof("  [Gate] OpenLong: symbol=%s, size=%d, leverage=%d", symbol, size, leverage)
+	t.log.Infof("  [Gate] OpenLong: symbol=%s, size=%d, leverage=%d", symbol, size, leverage)
 
 	result, _, err := t.client.FuturesApi.CreateFuturesOrder(t.ctx, "usdt", order, nil)
 	if err != nil {
@@ -88,7 +87,7 @@ func (t *GateTrader) OpenLong(symbol string, quantity float64, leverage int) (ma
 	// Parse fill price from result
 	fillPrice, _ := strconv.ParseFloat(result.FillPrice, 64)
 
-	logger.Infof("  [Gate] Opened long position: orderId=%d, fillPrice=%.4f", result.Id, fillPrice)
+	t.log.Infof("  [Gate] Opened long position: orderId=%d, fillPrice=%.4f", result.Id, fillPrice)
 
 	return map[string]interface{}{
 		"orderId":   fmt.Sprintf("%d", result.Id),
@@ -105,12 +104,12 @@ func (t *GateTrader) OpenShort(symbol string, quantity float64, leverage int) (m
 
 	// Cancel old orders first
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
+		t.log.Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
 	}
 
 	// Set leverage
 	if err := t.SetLeverage(symbol, leverage); err != nil {
-		logger.Warnf("  [Gate] Failed to set leverage: %v", err)
+		t.log.Warnf("  [Gate] Failed to set leverage: %v", err)
 	}
 
 	// Get contract info for size calculation
@@ -134,7 +133,7 @@ func (t *GateTrader) OpenShort(symbol string, quantity float64, leverage int) (m
 		Text:     "t-nofx",
 	}
 
-	logger.Infof("  [Gate] OpenShort: symbol=%s, size=%d, leverage=%d", symbol, -size, leverage)
+	t.log.Infof("  [Gate] OpenShort: symbol=%s, size=%d, leverage=%d", symbol, -size, leverage)
 
 	result, _, err := t.client.FuturesApi.CreateFuturesOrder(t.ctx, "usdt", order, nil)
 	if err != nil {
@@ -147,7 +146,7 @@ func (t *GateTrader) OpenShort(symbol string, quantity float64, leverage int) (m
 	// Parse fill price from result
 	fillPrice, _ := strconv.ParseFloat(result.FillPrice, 64)
 
-	logger.Infof("  [Gate] Opened short position: orderId=%d, fillPrice=%.4f", result.Id, fillPrice)
+	t.log.Infof("  [Gate] Opened short position: orderId=%d, fillPrice=%.4f", result.Id, fillPrice)
 
 	return map[string]interface{}{
 		"orderId":   fmt.Sprintf("%d", result.Id),
@@ -202,7 +201,7 @@ func (t *GateTrader) CloseLong(symbol string, quantity float64) (map[string]inte
 		Text:       "t-nofx-close",
 	}
 
-	logger.Infof("  [Gate] CloseLong: symbol=%s, size=%d", symbol, -size)
+	t.log.Infof("  [Gate] CloseLong: symbol=%s, size=%d", symbol, -size)
 
 	result, _, err := t.client.FuturesApi.CreateFuturesOrder(t.ctx, "usdt", order, nil)
 	if err != nil {
@@ -215,7 +214,7 @@ func (t *GateTrader) CloseLong(symbol string, quantity float64) (map[string]inte
 	// Parse fill price from result
 	fillPrice, _ := strconv.ParseFloat(result.FillPrice, 64)
 
-	logger.Infof("  [Gate] Closed long position: orderId=%d, fillPrice=%.4f", result.Id, fillPrice)
+	t.log.Infof("  [Gate] Closed long position: orderId=%d, fillPrice=%.4f", result.Id, fillPrice)
 
 	return map[string]interface{}{
 		"orderId":   fmt.Sprintf("%d", result.Id),
@@ -275,7 +274,7 @@ func (t *GateTrader) CloseShort(symbol string, quantity float64) (map[string]int
 		Text:       "t-nofx-close",
 	}
 
-	logger.Infof("  [Gate] CloseShort: symbol=%s, size=%d", symbol, size)
+	t.log.Infof("  [Gate] CloseShort: symbol=%s, size=%d", symbol, size)
 
 	result, _, err := t.client.FuturesApi.CreateFuturesOrder(t.ctx, "usdt", order, nil)
 	if err != nil {
@@ -288,7 +287,7 @@ func (t *GateTrader) CloseShort(symbol string, quantity float64) (map[string]int
 	// Parse fill price from result
 	fillPrice, _ := strconv.ParseFloat(result.FillPrice, 64)
 
-	logger.Infof("  [Gate] Closed short position: orderId=%d, fillPrice=%.4f", result.Id, fillPrice)
+	t.log.Infof("  [Gate] Closed short position: orderId=%d, fillPrice=%.4f", result.Id, fillPrice)
 
 	return map[string]interface{}{
 		"orderId":   fmt.Sprintf("%d", result.Id),
@@ -368,7 +367,7 @@ func (t *GateTrader) SetStopLoss(symbol string, positionSide string, quantity, s
 		return fmt.Errorf("failed to set stop loss: %w", err)
 	}
 
-	logger.Infof("  [Gate] Stop loss set: %s @ %.4f", symbol, stopPrice)
+	t.log.Infof("  [Gate] Stop loss set: %s @ %.4f", symbol, stopPrice)
 	return nil
 }
 
@@ -419,7 +418,7 @@ func (t *GateTrader) SetTakeProfit(symbol string, positionSide string, quantity,
 		return fmt.Errorf("failed to set take profit: %w", err)
 	}
 
-	logger.Infof("  [Gate] Take profit set: %s @ %.4f", symbol, takeProfitPrice)
+	t.log.Infof("  [Gate] Take profit set: %s @ %.4f", symbol, takeProfitPrice)
 	return nil
 }
 
@@ -451,7 +450,7 @@ func (t *GateTrader) cancelTriggerOrders(symbol string, orderType string) error
 		// For simplicity, cancel all matching symbol orders
 		_, _, err := t.client.FuturesApi.CancelPriceTriggeredOrder(t.ctx, "usdt", fmt.Sprintf("%d", order.Id))
 		if err != nil {
-			logger.Warnf("  [Gate] Failed to cancel trigger order %d: %v", order.Id, err)
+			t.log.Warnf("  [Gate] Failed to cancel trigger order %d: %v", order.Id, err)
 		}
 	}
 
@@ -467,7 +466,7 @@ func (t *GateTrader) CancelAllOrders(symbol string) error {
 	if err != nil {
 		// Ignore if no orders to cancel
 		if !strings.Contains(err.Error(), "ORDER_NOT_FOUND") {
-			logger.Warnf("  [Gate] Error canceling orders: %v", err)
+			t.log.Warnf("  [Gate] Error canceling orders: %v", err)
 		}
 	}
 
@@ -14,23 +14,7 @@ import (
 // bollingerWidth: Bollinger band width as percentage
 // atr14Pct: ATR14 as percentage of current price
 func classifyRegimeLevel(bollingerWidth, atr14Pct float64) market.RegimeLevel {
-	// Narrow: Bollinger < 2%, ATR < 1%
-	if bollingerWidth < 2.0 && atr14Pct < 1.0 {
-		return market.RegimeLevelNarrow
-	}
-
-	// Standard: Bollinger 2-3%, ATR 1-2%
-	if bollingerWidth <= 3.0 && atr14Pct <= 2.0 {
-		return market.RegimeLevelStandard
-	}
-
-	// Wide: Bollinger 3-4%, ATR 2-3%
-	if bollingerWidth <= 4.0 && atr14Pct <= 3.0 {
-		return market.RegimeLevelWide
-	}
-
-	// Volatile: Bollinger > 4%, ATR > 3%
-	return market.RegimeLevelVolatile
+	return market.ClassifyRegimeLevel(bollingerWidth, atr14Pct)
 }
 
 // getRegimeLeverageLimit returns the effective leverage limit for a regime level
@@ -28,6 +28,12 @@ type HyperliquidTrader struct {
 	xyzMetaMutex sync.RWMutex
 	privateKey   *ecdsa.PrivateKey // For xyz dex signing
 	isTestnet    bool
+
+	log *logger.Scope // see types.LogScoped
+}
+
+func (t *HyperliquidTrader) SetLogScope(scope *logger.Scope) {
+	t.log = scope
 }
 
 // xyzDexMeta represents metadata for xyz dex assets
@@ -235,7 +241,7 @@ func (t *HyperliquidTrader) getSzDecimals(coin string) int {
 	defer t.metaMutex.RUnlock()
 
 	if t.meta == nil {
-		logger.Infof("⚠️  meta information is empty, using default precision 4")
+		t.log.Infof("⚠️  meta information is empty, using default precision 4")
 		return 4 // Default precision
 	}
 
@@ -246,7 +252,7 @@ func (t *HyperliquidTrader) getSzDecimals(coin string) int {
 		}
 	}
 
-	logger.Infof("⚠️  Precision information not found for %s, using default precision 4", coin)
+	t.log.Infof("⚠️  Precision information not found for %s, using default precision 4", coin)
 	return 4 // Default precision
 }
 
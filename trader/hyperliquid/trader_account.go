@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"nofx/logger"
 	"nofx/trader/types"
 	"strconv"
 	"strings"
@@ -17,7 +16,7 @@ import (
 
 // GetBalance gets account balance
 func (t *HyperliquidTrader) GetBalance() (map[string]interface{}, error) {
-	logger.Infof("🔄 Calling Hyperliquid API to get account balance...")
+	t.log.Infof("🔄 Calling Hyperliquid API to get account balance...")
 
 	// Step 1: Query Spot account balance
 	spotState, err := t.exchange.Info().SpotUserState(t.ctx, t.walletAddr)
@@ -27,7 +26,7 @@ func (t *HyperliquidTrader) GetBalance() (map[string]interface{}, error) {
 		if t.isUnifiedAccount {
 			return nil, fmt.Errorf("failed to get authoritative unified Spot balance: %w", err)
 		}
-		logger.Infof("⚠️ Failed to query Spot balance (may have no spot assets): %v", err)
+		t.log.Infof("⚠️ Failed to query Spot balance (may have no spot assets): %v", err)
 	} else if spotState == nil {
 		if t.isUnifiedAccount {
 			return nil, fmt.Errorf("authoritative unified Spot balance response is empty")
@@ -44,7 +43,7 @@ func (t *HyperliquidTrader) GetBalance() (map[string]interface{}, error) {
 				if err != nil {
 					return nil, err
 				}
-				logger.Infof("✓ Found Spot balance: %.2f USDC (hold %.2f)", spotUSDCBalance, spotUSDCHold)
+				t.log.Infof("✓ Found Spot balance: %.2f USDC (hold %.2f)", spotUSDCBalance, spotUSDCHold)
 				break
 			}
 		}
@@ -53,7 +52,7 @@ func (t *HyperliquidTrader) GetBalance() (map[string]interface{}, error) {
 	// Step 2: Query Perpetuals contract account status
 	accountState, err := t.exchange.Info().UserState(t.ctx, t.walletAddr)
 	if err != nil {
-		logger.Infof("❌ Hyperliquid Perpetuals API call failed: %v", err)
+		t.log.Infof("❌ Hyperliquid Perpetuals API call failed: %v", err)
 		return nil, fmt.Errorf("failed to get account information: %w", err)
 	}
 
@@ -90,8 +89,8 @@ func (t *HyperliquidTrader) GetBalance() (map[string]interface{}, error) {
 
 	// Debug: Print complete summary structure returned by API
 	summaryJSON, _ := json.MarshalIndent(summary, "  ", "  ")
-	logger.Infof("🔍 [DEBUG] Hyperliquid API %s complete data:", summaryType)
-	logger.Infof("%s", string(summaryJSON))
+	t.log.Infof("🔍 [DEBUG] Hyperliquid API %s complete data:", summaryType)
+	t.log.Infof("%s", string(summaryJSON))
 
 	// Critical fix: Accumulate actual unrealized PnL from all positions
 	totalUnrealizedPnl := 0.0
@@ -115,7 +114,7 @@ func (t *HyperliquidTrader) GetBalance() (map[string]interface{}, error) {
 		withdrawable, err := strconv.ParseFloat(accountState.Withdrawable, 64)
 		if err == nil && withdrawable > 0 {
 			availableBalance = withdrawable
-			logger.Infof("✓ Using Withdrawable as available balance: %.2f", availableBalance)
+			t.log.Infof("✓ Using Withdrawable as available balance: %.2f", availableBalance)
 		}
 	}
 
@@ -123,7 +122,7 @@ func (t *HyperliquidTrader) GetBalance() (map[string]interface{}, error) {
 	if availableBalance == 0 && accountState.Withdrawable == "" {
 		availableBalance = accountValue - totalMarginUsed
 		if availableBalance < 0 {
-			logger.Infof("⚠️ Calculated available balance is negative (%.2f), reset to 0", availableBalance)
+			t.log.Infof("⚠️ Calculated available balance is negative (%.2f), reset to 0", availableBalance)
 			availableBalance = 0
 		}
 	}
@@ -134,17 +133,17 @@ func (t *HyperliquidTrader) GetBalance() (map[string]interface{}, error) {
 	xyzAccountValue, xyzUnrealizedPnl, xyzPositions, err = t.getXYZDexBalance()
 	if err != nil {
 		// xyz dex query failed - log warning but don't fail the entire balance query
-		logger.Infof("⚠️ Failed to query xyz dex balance: %v", err)
+		t.log.Infof("⚠️ Failed to query xyz dex balance: %v", err)
 	}
 	// Always log xyz dex state for debugging
-	logger.Infof("🔍 xyz dex state: accountValue=%.4f, unrealizedPnl=%.4f, positions=%d",
+	t.log.Infof("🔍 xyz dex state: accountValue=%.4f, unrealizedPnl=%.4f, positions=%d",
 		xyzAccountValue, xyzUnrealizedPnl, len(xyzPositions))
 	for _, pos := range xyzPositions {
 		entryPx := "nil"
 		if pos.Position.EntryPx != nil {
 			entryPx = *pos.Position.EntryPx
 		}
-		logger.Infof("   └─ %s: size=%s, entryPx=%s, posValue=%s, pnl=%s",
+		t.log.Infof("   └─ %s: size=%s, entryPx=%s, posValue=%s, pnl=%s",
 			pos.Position.Coin, pos.Position.Szi, entryPx, pos.Position.PositionValue, pos.Position.UnrealizedPnl)
 	}
 	xyzMarginUsed := calculateXYZMarginUsed(xyzPositions)
@@ -177,7 +176,7 @@ func (t *HyperliquidTrader) GetBalance() (map[string]interface{}, error) {
 	// same Spot USDC. They must not be added on top of Spot or the dashboard
 	// will double count equity after a position opens.
 	if t.isUnifiedAccount && spotUSDCBalance > 0 {
-		logger.Infof("✓ Unified Account: Spot %.2f USDC used as shared collateral (available: %.2f)",
+		t.log.Infof("✓ Unified Account: Spot %.2f USDC used as shared collateral (available: %.2f)",
 			spotUSDCBalance, availableBalance)
 	}
 
@@ -195,21 +194,21 @@ func (t *HyperliquidTrader) GetBalance() (map[string]interface{}, error) {
 	result["perpAccountValue"] = accountValue               // Perp account value for debugging
 	result["totalMarginUsed"] = balanceBreakdown.TotalMarginUsed
 
-	logger.Infof("✓ Hyperliquid complete account:")
-	logger.Infof("  • Spot balance: %.2f USDC", spotUSDCBalance)
-	logger.Infof("  • Perpetuals equity: %.2f USDC (wallet %.2f + unrealized %.2f)",
+	t.log.Infof("✓ Hyperliquid complete account:")
+	t.log.Infof("  • Spot balance: %.2f USDC", spotUSDCBalance)
+	t.log.Infof("  • Perpetuals equity: %.2f USDC (wallet %.2f + unrealized %.2f)",
 		accountValue,
 		walletBalanceWithoutUnrealized,
 		totalUnrealizedPnl)
-	logger.Infof("  • Perpetuals available balance: %.2f USDC", availableBalance)
-	logger.Infof("  • Margin used (position estimate): %.2f USDC", balanceBreakdown.TotalMarginUsed)
-	logger.Infof("  • Held/reserved balance: %.2f USDC", spotUSDCHold)
-	logger.Infof("  • xyz dex equity: %.2f USDC (wallet %.2f + unrealized %.2f)",
+	t.log.Infof("  • Perpetuals available balance: %.2f USDC", availableBalance)
+	t.log.Infof("  • Margin used (position estimate): %.2f USDC", balanceBreakdown.TotalMarginUsed)
+	t.log.Infof("  • Held/reserved balance: %.2f USDC", spotUSDCHold)
+	t.log.Infof("  • xyz dex equity: %.2f USDC (wallet %.2f + unrealized %.2f)",
 		xyzAccountValue,
 		balanceBreakdown.XYZWalletBalance,
 		xyzUnrealizedPnl)
-	logger.Infof("  • Total wallet balance: %.2f USDC", totalWalletBalance)
-	logger.Infof("  ⭐ Total equity: %.2f USDC | Available: %.2f | Spot: %.2f | xyz view: %.2f",
+	t.log.Infof("  • Total wallet balance: %.2f USDC", totalWalletBalance)
+	t.log.Infof("  ⭐ Total equity: %.2f USDC | Available: %.2f | Spot: %.2f | xyz view: %.2f",
 		totalEquityCalculated, availableBalance, spotUSDCBalance, xyzAccountValue)
 
 	return result, nil
@@ -730,3 +729,35 @@ func (t *HyperliquidTrader) GetOrderBook(symbol string, depth int) (bids, asks [
 
 	return bids, asks, nil
 }
+
+// GetFundingPayments retrieves the account's funding payment history
+func (t *HyperliquidTrader) GetFundingPayments(startTime time.Time, limit int) ([]types.FundingPayment, error) {
+	history, err := t.exchange.Info().UserFundingHistory(t.ctx, t.walletAddr, startTime.UnixMilli(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user funding history: %w", err)
+	}
+
+	payments := make([]types.FundingPayment, 0, len(history))
+	for _, h := range history {
+		amount, err := strconv.ParseFloat(h.Delta.USDC, 64)
+		if err != nil || amount == 0 {
+			continue
+		}
+		rate, _ := strconv.ParseFloat(h.Delta.FundingRate, 64)
+		symbol := h.Delta.Coin
+		if !strings.HasPrefix(symbol, "xyz:") {
+			symbol += "USDT"
+		}
+		payments = append(payments, types.FundingPayment{
+			PaymentID: fmt.Sprintf("%s_%s_%d", h.Hash, h.Delta.Coin, h.Time),
+			Symbol:    symbol,
+			Amount:    amount,
+			Rate:      rate,
+			Time:      time.UnixMilli(h.Time).UTC(),
+		})
+		if limit > 0 && len(payments) >= limit {
+			break
+		}
+	}
+	return payments, nil
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"nofx/logger"
 	"nofx/trader/types"
 	"strconv"
 	"strings"
@@ -49,7 +48,7 @@ func wrapBuilderFeeNotApproved(err error) error {
 func (t *HyperliquidTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
 	// First cancel all pending orders for this coin
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel old pending orders: %v", err)
+		t.log.Infof("  ⚠ Failed to cancel old pending orders: %v", err)
 	}
 
 	// Hyperliquid symbol format
@@ -65,7 +64,7 @@ func (t *HyperliquidTrader) OpenLong(symbol string, quantity float64, leverage i
 		if !isXyz {
 			return nil, err
 		}
-		logger.Warnf("  ⚠ Failed to set leverage for xyz dex asset %s: %v", coin, err)
+		t.log.Warnf("  ⚠ Failed to set leverage for xyz dex asset %s: %v", coin, err)
 	}
 
 	// Get current price (for market order)
@@ -76,7 +75,7 @@ func (t *HyperliquidTrader) OpenLong(symbol string, quantity float64, leverage i
 
 	// Price needs to be processed to 5 significant figures
 	aggressivePrice := t.roundPriceToSigfigs(price * aggressiveBuyPriceFactor)
-	logger.Infof("  💰 Price precision handling: %.8f -> %.8f (5 significant figures)", price*aggressiveBuyPriceFactor, aggressivePrice)
+	t.log.Infof("  💰 Price precision handling: %.8f -> %.8f (5 significant figures)", price*aggressiveBuyPriceFactor, aggressivePrice)
 
 	// Handle xyz dex assets differently
 	if isXyz {
@@ -87,7 +86,7 @@ func (t *HyperliquidTrader) OpenLong(symbol string, quantity float64, leverage i
 	} else {
 		// Standard crypto order
 		roundedQuantity := t.roundToSzDecimals(coin, quantity)
-		logger.Infof("  📏 Quantity precision handling: %.8f -> %.8f (szDecimals=%d)", quantity, roundedQuantity, t.getSzDecimals(coin))
+		t.log.Infof("  📏 Quantity precision handling: %.8f -> %.8f (szDecimals=%d)", quantity, roundedQuantity, t.getSzDecimals(coin))
 
 		order := hyperliquid.CreateOrderRequest{
 			Coin:  coin,
@@ -108,7 +107,7 @@ func (t *HyperliquidTrader) OpenLong(symbol string, quantity float64, leverage i
 		}
 	}
 
-	logger.Infof("✓ Long position opened successfully: %s quantity: %.4f", symbol, quantity)
+	t.log.Infof("✓ Long position opened successfully: %s quantity: %.4f", symbol, quantity)
 
 	result := make(map[string]interface{})
 	result["orderId"] = 0
@@ -122,7 +121,7 @@ func (t *HyperliquidTrader) OpenLong(symbol string, quantity float64, leverage i
 func (t *HyperliquidTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
 	// First cancel all pending orders for this coin
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel old pending orders: %v", err)
+		t.log.Infof("  ⚠ Failed to cancel old pending orders: %v", err)
 	}
 
 	// Hyperliquid symbol format
@@ -138,7 +137,7 @@ func (t *HyperliquidTrader) OpenShort(symbol string, quantity float64, leverage
 		if !isXyz {
 			return nil, err
 		}
-		logger.Warnf("  ⚠ Failed to set leverage for xyz dex asset %s: %v", coin, err)
+		t.log.Warnf("  ⚠ Failed to set leverage for xyz dex asset %s: %v", coin, err)
 	}
 
 	// Get current price
@@ -149,7 +148,7 @@ func (t *HyperliquidTrader) OpenShort(symbol string, quantity float64, leverage
 
 	// Price needs to be processed to 5 significant figures
 	aggressivePrice := t.roundPriceToSigfigs(price * aggressiveSellPriceFactor)
-	logger.Infof("  💰 Price precision handling: %.8f -> %.8f (5 significant figures)", price*aggressiveSellPriceFactor, aggressivePrice)
+	t.log.Infof("  💰 Price precision handling: %.8f -> %.8f (5 significant figures)", price*aggressiveSellPriceFactor, aggressivePrice)
 
 	// Handle xyz dex assets differently
 	if isXyz {
@@ -160,7 +159,7 @@ func (t *HyperliquidTrader) OpenShort(symbol string, quantity float64, leverage
 	} else {
 		// Standard crypto order
 		roundedQuantity := t.roundToSzDecimals(coin, quantity)
-		logger.Infof("  📏 Quantity precision handling: %.8f -> %.8f (szDecimals=%d)", quantity, roundedQuantity, t.getSzDecimals(coin))
+		t.log.Infof("  📏 Quantity precision handling: %.8f -> %.8f (szDecimals=%d)", quantity, roundedQuantity, t.getSzDecimals(coin))
 
 		order := hyperliquid.CreateOrderRequest{
 			Coin:  coin,
@@ -181,7 +180,7 @@ func (t *HyperliquidTrader) OpenShort(symbol string, quantity float64, leverage
 		}
 	}
 
-	logger.Infof("✓ Short position opened successfully: %s quantity: %.4f", symbol, quantity)
+	t.log.Infof("✓ Short position opened successfully: %s quantity: %.4f", symbol, quantity)
 
 	result := make(map[string]interface{})
 	result["orderId"] = 0
@@ -231,7 +230,7 @@ func (t *HyperliquidTrader) CloseLong(symbol string, quantity float64) (map[stri
 
 	// Price needs to be processed to 5 significant figures
 	aggressivePrice := t.roundPriceToSigfigs(price * aggressiveSellPriceFactor)
-	logger.Infof("  💰 Price precision handling: %.8f -> %.8f (5 significant figures)", price*aggressiveSellPriceFactor, aggressivePrice)
+	t.log.Infof("  💰 Price precision handling: %.8f -> %.8f (5 significant figures)", price*aggressiveSellPriceFactor, aggressivePrice)
 
 	// Handle xyz dex assets differently
 	if isXyz {
@@ -242,7 +241,7 @@ func (t *HyperliquidTrader) CloseLong(symbol string, quantity float64) (map[stri
 	} else {
 		// Standard crypto close order
 		roundedQuantity := t.roundToSzDecimals(coin, quantity)
-		logger.Infof("  📏 Quantity precision handling: %.8f -> %.8f (szDecimals=%d)", quantity, roundedQuantity, t.getSzDecimals(coin))
+		t.log.Infof("  📏 Quantity precision handling: %.8f -> %.8f (szDecimals=%d)", quantity, roundedQuantity, t.getSzDecimals(coin))
 
 		order := hyperliquid.CreateOrderRequest{
 			Coin:  coin,
@@ -263,11 +262,11 @@ func (t *HyperliquidTrader) CloseLong(symbol string, quantity float64) (map[stri
 		}
 	}
 
-	logger.Infof("✓ Long position closed successfully: %s quantity: %.4f", symbol, quantity)
+	t.log.Infof("✓ Long position closed successfully: %s quantity: %.4f", symbol, quantity)
 
 	// Cancel all pending orders for this coin after closing position
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel pending orders: %v", err)
+		t.log.Infof("  ⚠ Failed to cancel pending orders: %v", err)
 	}
 
 	result := make(map[string]interface{})
@@ -318,7 +317,7 @@ func (t *HyperliquidTrader) CloseShort(symbol string, quantity float64) (map[str
 
 	// Price needs to be processed to 5 significant figures
 	aggressivePrice := t.roundPriceToSigfigs(price * aggressiveBuyPriceFactor)
-	logger.Infof("  💰 Price precision handling: %.8f -> %.8f (5 significant figures)", price*aggressiveBuyPriceFactor, aggressivePrice)
+	t.log.Infof("  💰 Price precision handling: %.8f -> %.8f (5 significant figures)", price*aggressiveBuyPriceFactor, aggressivePrice)
 
 	// Handle xyz dex assets differently
 	if isXyz {
@@ -329,7 +328,7 @@ func (t *HyperliquidTrader) CloseShort(symbol string, quantity float64) (map[str
 	} else {
 		// Standard crypto close order
 		roundedQuantity := t.roundToSzDecimals(coin, quantity)
-		logger.Infof("  📏 Quantity precision handling: %.8f -> %.8f (szDecimals=%d)", quantity, roundedQuantity, t.getSzDecimals(coin))
+		t.log.Infof("  📏 Quantity precision handling: %.8f -> %.8f (szDecimals=%d)", quantity, roundedQuantity, t.getSzDecimals(coin))
 
 		order := hyperliquid.CreateOrderRequest{
 			Coin:  coin,
@@ -350,11 +349,11 @@ func (t *HyperliquidTrader) CloseShort(symbol string, quantity float64) (map[str
 		}
 	}
 
-	logger.Infof("✓ Short position closed successfully: %s quantity: %.4f", symbol, quantity)
+	t.log.Infof("✓ Short position closed successfully: %s quantity: %.4f", symbol, quantity)
 
 	// Cancel all pending orders for this coin after closing position
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel pending orders: %v", err)
+		t.log.Infof("  ⚠ Failed to cancel pending orders: %v", err)
 	}
 
 	result := make(map[string]interface{})
@@ -369,7 +368,7 @@ func (t *HyperliquidTrader) CloseShort(symbol string, quantity float64) (map[str
 func (t *HyperliquidTrader) CancelStopLossOrders(symbol string) error {
 	// Hyperliquid SDK's OpenOrder structure does not expose trigger field
 	// Cannot distinguish stop loss and take profit orders, so cancel all pending orders for this coin
-	logger.Infof("  ⚠️ Hyperliquid cannot distinguish stop loss/take profit orders, will cancel all pending orders")
+	t.log.Infof("  ⚠️ Hyperliquid cannot distinguish stop loss/take profit orders, will cancel all pending orders")
 	return t.CancelStopOrders(symbol)
 }
 
@@ -377,7 +376,7 @@ func (t *HyperliquidTrader) CancelStopLossOrders(symbol string) error {
 func (t *HyperliquidTrader) CancelTakeProfitOrders(symbol string) error {
 	// Hyperliquid SDK's OpenOrder structure does not expose trigger field
 	// Cannot distinguish stop loss and take profit orders, so cancel all pending orders for this coin
-	logger.Infof("  ⚠️ Hyperliquid cannot distinguish stop loss/take profit orders, will cancel all pending orders")
+	t.log.Infof("  ⚠️ Hyperliquid cannot distinguish stop loss/take profit orders, will cancel all pending orders")
 	return t.CancelStopOrders(symbol)
 }
 
@@ -404,12 +403,12 @@ func (t *HyperliquidTrader) CancelAllOrders(symbol string) error {
 		if order.Coin == coin {
 			_, err := t.exchange.Cancel(t.ctx, coin, order.Oid)
 			if err != nil {
-				logger.Infof("  ⚠ Failed to cancel order (oid=%d): %v", order.Oid, err)
+				t.log.Infof("  ⚠ Failed to cancel order (oid=%d): %v", order.Oid, err)
 			}
 		}
 	}
 
-	logger.Infof("  ✓ Cancelled all pending orders for %s", symbol)
+	t.log.Infof("  ✓ Cancelled all pending orders for %s", symbol)
 	return nil
 }
 
@@ -439,7 +438,7 @@ func (t *HyperliquidTrader) CancelStopOrders(symbol string) error {
 		if order.Coin == coin {
 			_, err := t.exchange.Cancel(t.ctx, coin, order.Oid)
 			if err != nil {
-				logger.Infof("  ⚠ Failed to cancel order (oid=%d): %v", order.Oid, err)
+				t.log.Infof("  ⚠ Failed to cancel order (oid=%d): %v", order.Oid, err)
 				continue
 			}
 			canceledCount++
@@ -447,9 +446,9 @@ func (t *HyperliquidTrader) CancelStopOrders(symbol string) error {
 	}
 
 	if canceledCount == 0 {
-		logger.Infof("  ℹ No pending orders to cancel for %s", symbol)
+		t.log.Infof("  ℹ No pending orders to cancel for %s", symbol)
 	} else {
-		logger.Infof("  ✓ Cancelled %d pending orders for %s (including TP/SL orders)", canceledCount, symbol)
+		t.log.Infof("  ✓ Cancelled %d pending orders for %s (including TP/SL orders)", canceledCount, symbol)
 	}
 
 	return nil
@@ -507,7 +506,7 @@ func (t *HyperliquidTrader) cancelXyzOrders(coin string) error {
 	for _, order := range openOrders {
 		if order.Coin == coin {
 			if err := t.cancelXyzOrder(order.Oid); err != nil {
-				logger.Infof("  ⚠ Failed to cancel xyz dex order (oid=%d): %v", order.Oid, err)
+				t.log.Infof("  ⚠ Failed to cancel xyz dex order (oid=%d): %v", order.Oid, err)
 				continue
 			}
 			canceledCount++
@@ -515,9 +514,9 @@ func (t *HyperliquidTrader) cancelXyzOrders(coin string) error {
 	}
 
 	if canceledCount == 0 {
-		logger.Infof("  ℹ No pending xyz dex orders to cancel for %s", coin)
+		t.log.Infof("  ℹ No pending xyz dex orders to cancel for %s", coin)
 	} else {
-		logger.Infof("  ✓ Cancelled %d xyz dex orders for %s", canceledCount, coin)
+		t.log.Infof("  ✓ Cancelled %d xyz dex orders for %s", canceledCount, coin)
 	}
 
 	return nil
@@ -648,7 +647,7 @@ func (t *HyperliquidTrader) placeXyzOrder(coin string, isBuy bool, size float64,
 	// Round price to 5 significant figures
 	roundedPrice := t.roundPriceToSigfigs(price)
 
-	logger.Infof("📝 Placing xyz dex order (direct): %s %s size=%.4f price=%.4f metaIndex=%d assetIndex=%d (formula: 100000 + 1*10000 + %d) reduceOnly=%v",
+	t.log.Infof("📝 Placing xyz dex order (direct): %s %s size=%.4f price=%.4f metaIndex=%d assetIndex=%d (formula: 100000 + 1*10000 + %d) reduceOnly=%v",
 		map[bool]string{true: "BUY", false: "SELL"}[isBuy],
 		coin, roundedSize, roundedPrice, metaIndex, assetIndex, metaIndex, reduceOnly)
 
@@ -704,7 +703,7 @@ func (t *HyperliquidTrader) placeXyzOrder(coin string, isBuy bool, size float64,
 		return fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	logger.Infof("📤 Sending xyz dex order to %s/exchange", apiURL)
+	t.log.Infof("📤 Sending xyz dex order to %s/exchange", apiURL)
 
 	req, err := http.NewRequestWithContext(t.ctx, http.MethodPost, apiURL+"/exchange", bytes.NewBuffer(jsonData))
 	if err != nil {
@@ -747,7 +746,7 @@ func (t *HyperliquidTrader) placeXyzOrder(coin string, isBuy bool, size float64,
 
 	if err := json.Unmarshal(body, &result); err != nil {
 		// Try to parse as error response
-		logger.Infof("⚠️  Failed to parse response as success, raw body: %s", string(body))
+		t.log.Infof("⚠️  Failed to parse response as success, raw body: %s", string(body))
 		return fmt.Errorf("xyz dex order failed, status=%d, body=%s", resp.StatusCode, string(body))
 	}
 
@@ -763,14 +762,14 @@ func (t *HyperliquidTrader) placeXyzOrder(coin string, isBuy bool, size float64,
 			return wrapBuilderFeeNotApproved(fmt.Errorf("xyz dex order error (coin=%s, assetIndex=%d, size=%.4f, price=%.4f): %s", coin, assetIndex, roundedSize, roundedPrice, *status.Error))
 		}
 		if status.Filled != nil {
-			logger.Infof("✅ xyz dex order filled: totalSz=%s avgPx=%s oid=%d",
+			t.log.Infof("✅ xyz dex order filled: totalSz=%s avgPx=%s oid=%d",
 				status.Filled.TotalSz, status.Filled.AvgPx, status.Filled.Oid)
 		} else if status.Resting != nil {
-			logger.Infof("✅ xyz dex order resting: oid=%d", status.Resting.Oid)
+			t.log.Infof("✅ xyz dex order resting: oid=%d", status.Resting.Oid)
 		}
 	}
 
-	logger.Infof("✅ xyz dex order placed successfully: %s (response: %s)", coin, string(body))
+	t.log.Infof("✅ xyz dex order placed successfully: %s (response: %s)", coin, string(body))
 	return nil
 }
 
@@ -810,7 +809,7 @@ func (t *HyperliquidTrader) placeXyzTriggerOrder(coin string, isBuy bool, size f
 	// Round price to 5 significant figures
 	roundedPrice := t.roundPriceToSigfigs(triggerPrice)
 
-	logger.Infof("📝 Placing xyz dex %s order: %s %s size=%.4f triggerPrice=%.4f assetIndex=%d",
+	t.log.Infof("📝 Placing xyz dex %s order: %s %s size=%.4f triggerPrice=%.4f assetIndex=%d",
 		tpsl,
 		map[bool]string{true: "BUY", false: "SELL"}[isBuy],
 		coin, roundedSize, roundedPrice, assetIndex)
@@ -869,7 +868,7 @@ func (t *HyperliquidTrader) placeXyzTriggerOrder(coin string, isBuy bool, size f
 		return fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	logger.Infof("📤 Sending xyz dex %s order to %s/exchange", tpsl, apiURL)
+	t.log.Infof("📤 Sending xyz dex %s order to %s/exchange", tpsl, apiURL)
 
 	req, err := http.NewRequestWithContext(t.ctx, http.MethodPost, apiURL+"/exchange", bytes.NewBuffer(jsonData))
 	if err != nil {
@@ -906,7 +905,7 @@ func (t *HyperliquidTrader) placeXyzTriggerOrder(coin string, isBuy bool, size f
 	}
 
 	if err := json.Unmarshal(body, &result); err != nil {
-		logger.Infof("⚠️  Failed to parse response, raw body: %s", string(body))
+		t.log.Infof("⚠️  Failed to parse response, raw body: %s", string(body))
 		return fmt.Errorf("xyz dex %s order failed, status=%d, body=%s", tpsl, resp.StatusCode, string(body))
 	}
 
@@ -922,11 +921,11 @@ func (t *HyperliquidTrader) placeXyzTriggerOrder(coin string, isBuy bool, size f
 			return wrapBuilderFeeNotApproved(fmt.Errorf("xyz dex %s order error: %s", tpsl, *status.Error))
 		}
 		if status.Resting != nil {
-			logger.Infof("✅ xyz dex %s order placed: oid=%d", tpsl, status.Resting.Oid)
+			t.log.Infof("✅ xyz dex %s order placed: oid=%d", tpsl, status.Resting.Oid)
 		}
 	}
 
-	logger.Infof("✅ xyz dex %s order placed successfully: %s", tpsl, coin)
+	t.log.Infof("✅ xyz dex %s order placed successfully: %s", tpsl, coin)
 	return nil
 }
 
@@ -974,7 +973,7 @@ func (t *HyperliquidTrader) SetStopLoss(symbol string, positionSide string, quan
 		}
 	}
 
-	logger.Infof("  Stop loss price set: %.4f", roundedStopPrice)
+	t.log.Infof("  Stop loss price set: %.4f", roundedStopPrice)
 	return nil
 }
 
@@ -1022,7 +1021,7 @@ func (t *HyperliquidTrader) SetTakeProfit(symbol string, positionSide string, qu
 		}
 	}
 
-	logger.Infof("  Take profit price set: %.4f", roundedTakeProfitPrice)
+	t.log.Infof("  Take profit price set: %.4f", roundedTakeProfitPrice)
 	return nil
 }
 
@@ -1036,9 +1035,9 @@ func (t *HyperliquidTrader) PlaceLimitOrder(req *types.LimitOrderRequest) (*type
 	if req.Leverage > 0 {
 		if err := t.SetLeverage(req.Symbol, req.Leverage); err != nil {
 			if !isXyz {
-				logger.Warnf("[Hyperliquid] Failed to set leverage: %v", err)
+				t.log.Warnf("[Hyperliquid] Failed to set leverage: %v", err)
 			} else {
-				logger.Warnf("[Hyperliquid] Failed to set xyz leverage for %s: %v", coin, err)
+				t.log.Warnf("[Hyperliquid] Failed to set xyz leverage for %s: %v", coin, err)
 			}
 		}
 	}
@@ -1052,7 +1051,7 @@ func (t *HyperliquidTrader) PlaceLimitOrder(req *types.LimitOrderRequest) (*type
 	// Determine if buy or sell
 	isBuy := req.Side == "BUY"
 
-	logger.Infof("[Hyperliquid] PlaceLimitOrder: %s %s @ %.4f, qty=%.4f", coin, req.Side, roundedPrice, roundedQuantity)
+	t.log.Infof("[Hyperliquid] PlaceLimitOrder: %s %s @ %.4f, qty=%.4f", coin, req.Side, roundedPrice, roundedQuantity)
 
 	order := hyperliquid.CreateOrderRequest{
 		Coin:  coin,
@@ -1077,7 +1076,7 @@ func (t *HyperliquidTrader) PlaceLimitOrder(req *types.LimitOrderRequest) (*type
 	// we can track orders by price level instead
 	orderID := fmt.Sprintf("%d", time.Now().UnixNano())
 
-	logger.Infof("✓ [Hyperliquid] Limit order placed: %s %s @ %.4f",
+	t.log.Infof("✓ [Hyperliquid] Limit order placed: %s %s @ %.4f",
 		coin, req.Side, roundedPrice)
 
 	return &types.LimitOrderResult{
@@ -1108,6 +1107,6 @@ func (t *HyperliquidTrader) CancelOrder(symbol, orderID string) error {
 		return fmt.Errorf("failed to cancel order: %w", err)
 	}
 
-	logger.Infof("✓ [Hyperliquid] Order cancelled: %s %s", symbol, orderID)
+	t.log.Infof("✓ [Hyperliquid] Order cancelled: %s %s", symbol, orderID)
 	return nil
 }
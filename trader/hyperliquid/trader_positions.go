@@ -2,7 +2,6 @@ package hyperliquid
 
 import (
 	"fmt"
-	"nofx/logger"
 	"strconv"
 	"strings"
 )
@@ -74,7 +73,7 @@ func (t *HyperliquidTrader) GetPositions() ([]map[string]interface{}, error) {
 	_, _, xyzPositions, err := t.getXYZDexBalance()
 	if err != nil {
 		// xyz dex query failed - log warning but don't fail
-		logger.Infof("⚠️  Failed to get xyz dex positions: %v", err)
+		t.log.Infof("⚠️  Failed to get xyz dex positions: %v", err)
 	} else {
 		for _, pos := range xyzPositions {
 			posAmt, _ := strconv.ParseFloat(pos.Position.Szi, 64)
@@ -146,7 +145,7 @@ func (t *HyperliquidTrader) SetMarginMode(symbol string, isCrossMargin bool) err
 	if !isCrossMargin {
 		marginModeStr = "isolated margin"
 	}
-	logger.Infof("  ✓ %s will use %s mode", symbol, marginModeStr)
+	t.log.Infof("  ✓ %s will use %s mode", symbol, marginModeStr)
 	return nil
 }
 
@@ -162,6 +161,6 @@ func (t *HyperliquidTrader) SetLeverage(symbol string, leverage int) error {
 		return fmt.Errorf("failed to set leverage: %w", err)
 	}
 
-	logger.Infof("  ✓ %s leverage switched to %dx", symbol, leverage)
+	t.log.Infof("  ✓ %s leverage switched to %dx", symbol, leverage)
 	return nil
 }
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"nofx/logger"
 	"strings"
 	"time"
 )
@@ -22,7 +21,7 @@ func (t *HyperliquidTrader) refreshMetaIfNeeded(coin string) error {
 		return nil // Meta is normal, no refresh needed
 	}
 
-	logger.Infof("⚠️  Asset ID for %s is 0, attempting to refresh Meta information...", coin)
+	t.log.Infof("⚠️  Asset ID for %s is 0, attempting to refresh Meta information...", coin)
 
 	// Refresh Meta information
 	meta, err := t.exchange.Info().Meta(t.ctx)
@@ -35,7 +34,7 @@ func (t *HyperliquidTrader) refreshMetaIfNeeded(coin string) error {
 	t.meta = meta
 	t.metaMutex.Unlock()
 
-	logger.Infof("✅ Meta information refreshed, contains %d assets", len(meta.Universe))
+	t.log.Infof("✅ Meta information refreshed, contains %d assets", len(meta.Universe))
 
 	// Verify Asset ID after refresh
 	assetID, ok := t.exchange.Info().CoinToAsset(coin)
@@ -46,7 +45,7 @@ func (t *HyperliquidTrader) refreshMetaIfNeeded(coin string) error {
 			"  3. API connection issue", coin)
 	}
 
-	logger.Infof("✅ Asset ID check passed after refresh: %s -> %d", coin, assetID)
+	t.log.Infof("✅ Asset ID check passed after refresh: %s -> %d", coin, assetID)
 	return nil
 }
 
@@ -96,7 +95,7 @@ func (t *HyperliquidTrader) fetchXyzMeta() error {
 	t.xyzMeta = &meta
 	t.xyzMetaMutex.Unlock()
 
-	logger.Infof("✅ xyz dex meta fetched, contains %d assets", len(meta.Universe))
+	t.log.Infof("✅ xyz dex meta fetched, contains %d assets", len(meta.Universe))
 	return nil
 }
 
@@ -106,7 +105,7 @@ func (t *HyperliquidTrader) getXyzSzDecimals(coin string) int {
 	defer t.xyzMetaMutex.RUnlock()
 
 	if t.xyzMeta == nil {
-		logger.Infof("⚠️  xyz meta information is empty, using default precision 2")
+		t.log.Infof("⚠️  xyz meta information is empty, using default precision 2")
 		return 2 // Default precision for stocks/forex
 	}
 
@@ -123,7 +122,7 @@ func (t *HyperliquidTrader) getXyzSzDecimals(coin string) int {
 		}
 	}
 
-	logger.Infof("⚠️  Precision information not found for %s, using default precision 2", lookupName)
+	t.log.Infof("⚠️  Precision information not found for %s, using default precision 2", lookupName)
 	return 2 // Default precision for stocks/forex
 }
 
@@ -47,6 +47,12 @@ type IndodaxTrader struct {
 	positionCacheTime time.Time
 	cacheDuration     time.Duration
 	cacheMutex        sync.RWMutex
+
+	log *logger.Scope // see types.LogScoped
+}
+
+func (t *IndodaxTrader) SetLogScope(scope *logger.Scope) {
+	t.log = scope
 }
 
 // IndodaxPair represents a trading pair on Indodax
@@ -261,7 +267,7 @@ func (t *IndodaxTrader) loadPairs() error {
 	}
 	t.pairCacheTime = time.Now()
 
-	logger.Infof("[Indodax] Loaded %d trading pairs", len(pairs))
+	t.log.Infof("[Indodax] Loaded %d trading pairs", len(pairs))
 	return nil
 }
 
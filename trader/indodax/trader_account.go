@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
-	"nofx/logger"
 	"nofx/trader/types"
 	"strconv"
 	"strings"
@@ -191,7 +190,7 @@ func (t *IndodaxTrader) GetClosedPnL(startTime time.Time, limit int) ([]types.Cl
 
 	if err := json.Unmarshal(data, &result); err != nil {
 		// Trade history might return empty, that's fine
-		logger.Infof("[Indodax] Trade history parse note: %v", err)
+		t.log.Infof("[Indodax] Trade history parse note: %v", err)
 		return nil, nil
 	}
 
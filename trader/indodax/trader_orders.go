@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"math"
 	"net/url"
-	"nofx/logger"
 	"nofx/trader/types"
 	"strconv"
 	"strings"
@@ -42,7 +41,7 @@ func (t *IndodaxTrader) OpenLong(symbol string, quantity float64, leverage int)
 		return nil, fmt.Errorf("failed to parse trade response: %w", err)
 	}
 
-	logger.Infof("[Indodax] Buy order placed: %s qty=%.8f price=%.0f", symbol, quantity, price)
+	t.log.Infof("[Indodax] Buy order placed: %s qty=%.8f price=%.0f", symbol, quantity, price)
 
 	return map[string]interface{}{
 		"orderId": result["order_id"],
@@ -103,7 +102,7 @@ func (t *IndodaxTrader) CloseLong(symbol string, quantity float64) (map[string]i
 		return nil, fmt.Errorf("failed to parse trade response: %w", err)
 	}
 
-	logger.Infof("[Indodax] Sell order placed: %s qty=%.8f price=%.0f", symbol, quantity, price)
+	t.log.Infof("[Indodax] Sell order placed: %s qty=%.8f price=%.0f", symbol, quantity, price)
 
 	return map[string]interface{}{
 		"orderId": result["order_id"],
@@ -122,13 +121,13 @@ func (t *IndodaxTrader) CloseShort(symbol string, quantity float64) (map[string]
 
 // SetLeverage is a no-op for Indodax (spot-only, no leverage)
 func (t *IndodaxTrader) SetLeverage(symbol string, leverage int) error {
-	logger.Infof("[Indodax] SetLeverage ignored (spot-only exchange, no leverage support)")
+	t.log.Infof("[Indodax] SetLeverage ignored (spot-only exchange, no leverage support)")
 	return nil
 }
 
 // SetMarginMode is a no-op for Indodax (spot-only, no margin)
 func (t *IndodaxTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
-	logger.Infof("[Indodax] SetMarginMode ignored (spot-only exchange, no margin support)")
+	t.log.Infof("[Indodax] SetMarginMode ignored (spot-only exchange, no margin support)")
 	return nil
 }
 
@@ -211,9 +210,9 @@ func (t *IndodaxTrader) CancelAllOrders(symbol string) error {
 		cancelParams.Set("type", order.Type)
 
 		if _, err := t.doPrivateRequest(cancelParams); err != nil {
-			logger.Warnf("[Indodax] Failed to cancel order %s: %v", order.OrderID, err)
+			t.log.Warnf("[Indodax] Failed to cancel order %s: %v", order.OrderID, err)
 		} else {
-			logger.Infof("[Indodax] Cancelled order: %s", order.OrderID)
+			t.log.Infof("[Indodax] Cancelled order: %s", order.OrderID)
 		}
 	}
 
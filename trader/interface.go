@@ -8,13 +8,16 @@ import (
 
 // Re-export types for backward compatibility
 type (
-	ClosedPnLRecord   = types.ClosedPnLRecord
-	TradeRecord       = types.TradeRecord
-	Trader            = types.Trader
-	OpenOrder         = types.OpenOrder
-	LimitOrderRequest = types.LimitOrderRequest
-	LimitOrderResult  = types.LimitOrderResult
-	GridTrader        = types.GridTrader
+	ClosedPnLRecord        = types.ClosedPnLRecord
+	TradeRecord            = types.TradeRecord
+	Trader                 = types.Trader
+	OpenOrder              = types.OpenOrder
+	LimitOrderRequest      = types.LimitOrderRequest
+	LimitOrderResult       = types.LimitOrderResult
+	GridTrader             = types.GridTrader
+	FundingPayment         = types.FundingPayment
+	FundingHistoryProvider = types.FundingHistoryProvider
+	LogScoped              = types.LogScoped
 )
 
 // GridTraderAdapter wraps a basic Trader to provide GridTrader interface
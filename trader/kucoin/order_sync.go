@@ -3,7 +3,6 @@ package kucoin
 import (
 	"encoding/json"
 	"fmt"
-	"nofx/logger"
 	"nofx/store"
 	"nofx/trader/syncloop"
 	"nofx/trader/types"
@@ -75,7 +74,7 @@ func (t *KuCoinTrader) GetTrades(startTime time.Time, limit int) ([]KuCoinTrade,
 		return nil, fmt.Errorf("failed to parse trade history: %w", err)
 	}
 
-	logger.Infof("📥 Received %d trades from KuCoin", len(response.Items))
+	t.log.Infof("📥 Received %d trades from KuCoin", len(response.Items))
 
 	result := make([]KuCoinTrade, 0, len(response.Items))
 
@@ -285,7 +284,7 @@ func (t *KuCoinTrader) SyncOrdersFromKuCoin(traderID string, exchangeID string,
 	// Get recent trades (last 24 hours)
 	startTime := time.Now().Add(-24 * time.Hour)
 
-	logger.Infof("🔄 Syncing KuCoin trades from: %s", startTime.Format(time.RFC3339))
+	t.log.Infof("🔄 Syncing KuCoin trades from: %s", startTime.Format(time.RFC3339))
 
 	// Use GetTrades method to fetch trade records
 	trades, err := t.GetTrades(startTime, 100)
@@ -293,7 +292,7 @@ func (t *KuCoinTrader) SyncOrdersFromKuCoin(traderID string, exchangeID string,
 		return fmt.Errorf("failed to get trades: %w", err)
 	}
 
-	logger.Infof("📥 Received %d trades from KuCoin", len(trades))
+	t.log.Infof("📥 Received %d trades from KuCoin", len(trades))
 
 	// Sort trades by time ASC (oldest first) for proper position building
 	sort.Slice(trades, func(i, j int) bool {
@@ -350,7 +349,7 @@ func (t *KuCoinTrader) SyncOrdersFromKuCoin(traderID string, exchangeID string,
 
 		// Insert order record
 		if err := orderStore.CreateOrder(orderRecord); err != nil {
-			logger.Infof("  ⚠️ Failed to sync trade %s: %v", trade.TradeID, err)
+			t.log.Infof("  ⚠️ Failed to sync trade %s: %v", trade.TradeID, err)
 			continue
 		}
 
@@ -375,7 +374,7 @@ func (t *KuCoinTrader) SyncOrdersFromKuCoin(traderID string, exchangeID string,
 		}
 
 		if err := orderStore.CreateFill(fillRecord); err != nil {
-			logger.Infof("  ⚠️ Failed to sync fill for trade %s: %v", trade.TradeID, err)
+			t.log.Infof("  ⚠️ Failed to sync fill for trade %s: %v", trade.TradeID, err)
 		}
 
 		// Create/update position record using PositionBuilder
@@ -385,23 +384,23 @@ func (t *KuCoinTrader) SyncOrdersFromKuCoin(traderID string, exchangeID string,
 			trade.FillQty, trade.FillPrice, trade.Fee, trade.ProfitLoss,
 			execTimeMs, trade.TradeID,
 		); err != nil {
-			logger.Infof("  ⚠️ Failed to sync position for trade %s: %v", trade.TradeID, err)
+			t.log.Infof("  ⚠️ Failed to sync position for trade %s: %v", trade.TradeID, err)
 		} else {
-			logger.Infof("  📍 Position updated for trade: %s (action: %s, qty: %.6f)", trade.TradeID, trade.OrderAction, trade.FillQty)
+			t.log.Infof("  📍 Position updated for trade: %s (action: %s, qty: %.6f)", trade.TradeID, trade.OrderAction, trade.FillQty)
 		}
 
 		syncedCount++
-		logger.Infof("  ✅ Synced trade: %s %s %s qty=%.6f price=%.6f pnl=%.2f fee=%.6f action=%s",
+		t.log.Infof("  ✅ Synced trade: %s %s %s qty=%.6f price=%.6f pnl=%.2f fee=%.6f action=%s",
 			trade.TradeID, symbol, side, trade.FillQty, trade.FillPrice, trade.ProfitLoss, trade.Fee, trade.OrderAction)
 	}
 
-	logger.Infof("✅ KuCoin order sync completed: %d new trades synced", syncedCount)
+	t.log.Infof("✅ KuCoin order sync completed: %d new trades synced", syncedCount)
 	return nil
 }
 
 // StartOrderSync starts background order sync task for KuCoin
 func (t *KuCoinTrader) StartOrderSync(traderID string, exchangeID string, exchangeType string, st *store.Store, interval time.Duration, stop <-chan struct{}) {
-	syncloop.Run(stop, interval, "KuCoin", func() error {
+	syncloop.RunForTrader(stop, interval, "KuCoin", traderID, func() error {
 		return t.SyncOrdersFromKuCoin(traderID, exchangeID, exchangeType, st)
 	})
 }
@@ -70,6 +70,12 @@ type KuCoinTrader struct {
 
 	// Cache duration
 	cacheDuration time.Duration
+
+	log *logger.Scope // see types.LogScoped
+}
+
+func (t *KuCoinTrader) SetLogScope(scope *logger.Scope) {
+	t.log = scope
 }
 
 // KuCoinContract represents contract info
@@ -153,7 +159,7 @@ func (t *KuCoinTrader) syncServerTime() error {
 	t.serverTimeOffset = offset
 	t.serverTimeMutex.Unlock()
 
-	logger.Infof("✓ KuCoin time synced: offset=%dms (local %d - server %d)", offset, localTime, serverTime)
+	t.log.Infof("✓ KuCoin time synced: offset=%dms (local %d - server %d)", offset, localTime, serverTime)
 	return nil
 }
 
@@ -245,9 +251,9 @@ func (t *KuCoinTrader) doRequest(method, path string, body interface{}) ([]byte,
 	if kcResp.Code != "200000" {
 		// If timestamp error, try to re-sync server time
 		if kcResp.Code == "400002" || strings.Contains(kcResp.Msg, "TIMESTAMP") {
-			logger.Warnf("⚠️ KuCoin timestamp error, re-syncing server time...")
+			t.log.Warnf("⚠️ KuCoin timestamp error, re-syncing server time...")
 			if err := t.syncServerTime(); err != nil {
-				logger.Warnf("⚠️ Failed to re-sync server time: %v", err)
+				t.log.Warnf("⚠️ Failed to re-sync server time: %v", err)
 			}
 		}
 		return nil, fmt.Errorf("KuCoin API error: code=%s, msg=%s", kcResp.Code, kcResp.Msg)
@@ -361,7 +367,7 @@ func (t *KuCoinTrader) quantityToLots(symbol string, quantity float64) (int64, e
 
 	// Check max order quantity
 	if contract.MaxOrderQty > 0 && float64(lotsInt) > contract.MaxOrderQty {
-		logger.Infof("⚠️ KuCoin order quantity %d exceeds max %d, reducing to max", lotsInt, int64(contract.MaxOrderQty))
+		t.log.Infof("⚠️ KuCoin order quantity %d exceeds max %d, reducing to max", lotsInt, int64(contract.MaxOrderQty))
 		lotsInt = int64(contract.MaxOrderQty)
 	}
 
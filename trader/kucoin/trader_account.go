@@ -3,7 +3,6 @@ package kucoin
 import (
 	"encoding/json"
 	"fmt"
-	"nofx/logger"
 	"time"
 )
 
@@ -45,7 +44,7 @@ func (t *KuCoinTrader) GetBalance() (map[string]interface{}, error) {
 		"totalEquity":           account.AccountEquity,        // For GetAccountInfo compatibility
 	}
 
-	logger.Infof("✓ KuCoin balance: Total equity=%.2f, Available=%.2f, Unrealized PnL=%.2f",
+	t.log.Infof("✓ KuCoin balance: Total equity=%.2f, Available=%.2f, Unrealized PnL=%.2f",
 		account.AccountEquity, account.AvailableBalance, account.UnrealisedPNL)
 
 	// Update cache
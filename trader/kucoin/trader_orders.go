@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
-	"nofx/logger"
 	"nofx/trader/types"
 	"strconv"
 	"strings"
@@ -15,12 +14,12 @@ import (
 func (t *KuCoinTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
 	// Cancel old orders
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
+		t.log.Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
 	}
 
 	// Set leverage
 	if err := t.SetLeverage(symbol, leverage); err != nil {
-		logger.Infof("⚠️ Failed to set leverage: %v", err)
+		t.log.Infof("⚠️ Failed to set leverage: %v", err)
 	}
 
 	kcSymbol := t.convertSymbol(symbol)
@@ -55,7 +54,7 @@ func (t *KuCoinTrader) OpenLong(symbol string, quantity float64, leverage int) (
 		return nil, fmt.Errorf("failed to parse order response: %w", err)
 	}
 
-	logger.Infof("✓ KuCoin opened long position: %s, lots=%d, orderId=%s", symbol, lots, result.OrderId)
+	t.log.Infof("✓ KuCoin opened long position: %s, lots=%d, orderId=%s", symbol, lots, result.OrderId)
 
 	// Query order to get fill price
 	fillPrice := t.queryOrderFillPrice(result.OrderId)
@@ -72,12 +71,12 @@ func (t *KuCoinTrader) OpenLong(symbol string, quantity float64, leverage int) (
 func (t *KuCoinTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
 	// Cancel old orders
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
+		t.log.Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
 	}
 
 	// Set leverage
 	if err := t.SetLeverage(symbol, leverage); err != nil {
-		logger.Infof("⚠️ Failed to set leverage: %v", err)
+		t.log.Infof("⚠️ Failed to set leverage: %v", err)
 	}
 
 	kcSymbol := t.convertSymbol(symbol)
@@ -112,7 +111,7 @@ func (t *KuCoinTrader) OpenShort(symbol string, quantity float64, leverage int)
 		return nil, fmt.Errorf("failed to parse order response: %w", err)
 	}
 
-	logger.Infof("✓ KuCoin opened short position: %s, lots=%d, orderId=%s", symbol, lots, result.OrderId)
+	t.log.Infof("✓ KuCoin opened short position: %s, lots=%d, orderId=%s", symbol, lots, result.OrderId)
 
 	// Query order to get fill price
 	fillPrice := t.queryOrderFillPrice(result.OrderId)
@@ -133,7 +132,7 @@ func (t *KuCoinTrader) queryOrderFillPrice(orderId string) float64 {
 	path := fmt.Sprintf("%s/%s", kucoinOrderPath, orderId)
 	data, err := t.doRequest("GET", path, nil)
 	if err != nil {
-		logger.Warnf("Failed to query order %s: %v", orderId, err)
+		t.log.Warnf("Failed to query order %s: %v", orderId, err)
 		return 0
 	}
 
@@ -219,11 +218,11 @@ func (t *KuCoinTrader) CloseLong(symbol string, quantity float64) (map[string]in
 		return nil, fmt.Errorf("failed to parse order response: %w", err)
 	}
 
-	logger.Infof("✓ KuCoin closed long position: %s", symbol)
+	t.log.Infof("✓ KuCoin closed long position: %s", symbol)
 
 	// Cancel pending orders
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel pending orders: %v", err)
+		t.log.Infof("  ⚠ Failed to cancel pending orders: %v", err)
 	}
 
 	return map[string]interface{}{
@@ -302,11 +301,11 @@ func (t *KuCoinTrader) CloseShort(symbol string, quantity float64) (map[string]i
 		return nil, fmt.Errorf("failed to parse order response: %w", err)
 	}
 
-	logger.Infof("✓ KuCoin closed short position: %s", symbol)
+	t.log.Infof("✓ KuCoin closed short position: %s", symbol)
 
 	// Cancel pending orders
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel pending orders: %v", err)
+		t.log.Infof("  ⚠ Failed to cancel pending orders: %v", err)
 	}
 
 	return map[string]interface{}{
@@ -374,7 +373,7 @@ func (t *KuCoinTrader) SetStopLoss(symbol string, positionSide string, quantity,
 		return fmt.Errorf("failed to set stop loss: %w", err)
 	}
 
-	logger.Infof("✓ Stop loss set: %.4f", stopPrice)
+	t.log.Infof("✓ Stop loss set: %.4f", stopPrice)
 	return nil
 }
 
@@ -414,7 +413,7 @@ func (t *KuCoinTrader) SetTakeProfit(symbol string, positionSide string, quantit
 		return fmt.Errorf("failed to set take profit: %w", err)
 	}
 
-	logger.Infof("✓ Take profit set: %.4f", takeProfitPrice)
+	t.log.Infof("✓ Take profit set: %.4f", takeProfitPrice)
 	return nil
 }
 
@@ -473,7 +472,7 @@ func (t *KuCoinTrader) cancelStopOrdersByType(symbol string, orderType string) e
 		cancelPath := fmt.Sprintf("%s/%s", kucoinCancelStopPath, order.Id)
 		_, err := t.doRequest("DELETE", cancelPath, nil)
 		if err != nil {
-			logger.Warnf("Failed to cancel stop order %s: %v", order.Id, err)
+			t.log.Warnf("Failed to cancel stop order %s: %v", order.Id, err)
 		}
 	}
 
@@ -494,7 +493,7 @@ func (t *KuCoinTrader) CancelStopOrders(symbol string) error {
 		return err
 	}
 
-	logger.Infof("✓ Cancelled stop orders for %s", symbol)
+	t.log.Infof("✓ Cancelled stop orders for %s", symbol)
 	return nil
 }
 
@@ -506,7 +505,7 @@ func (t *KuCoinTrader) CancelAllOrders(symbol string) error {
 	path := fmt.Sprintf("%s?symbol=%s", kucoinCancelOrderPath, kcSymbol)
 	_, err := t.doRequest("DELETE", path, nil)
 	if err != nil && !strings.Contains(err.Error(), "not found") {
-		logger.Warnf("Failed to cancel regular orders: %v", err)
+		t.log.Warnf("Failed to cancel regular orders: %v", err)
 	}
 
 	// Cancel stop orders
@@ -518,7 +517,7 @@ func (t *KuCoinTrader) CancelAllOrders(symbol string) error {
 // SetMarginMode sets margin mode
 func (t *KuCoinTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
 	// KuCoin sets margin mode per position, handled automatically
-	logger.Infof("✓ KuCoin margin mode: %v (handled per position)", isCrossMargin)
+	t.log.Infof("✓ KuCoin margin mode: %v (handled per position)", isCrossMargin)
 	return nil
 }
 
@@ -535,13 +534,13 @@ func (t *KuCoinTrader) SetLeverage(symbol string, leverage int) error {
 	if err != nil {
 		// Ignore if already at target leverage
 		if strings.Contains(err.Error(), "same") || strings.Contains(err.Error(), "already") {
-			logger.Infof("✓ %s leverage is already %dx", symbol, leverage)
+			t.log.Infof("✓ %s leverage is already %dx", symbol, leverage)
 			return nil
 		}
 		return fmt.Errorf("failed to set leverage: %w", err)
 	}
 
-	logger.Infof("✓ %s leverage set to %dx", symbol, leverage)
+	t.log.Infof("✓ %s leverage set to %dx", symbol, leverage)
 	return nil
 }
 
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"nofx/logger"
 	"nofx/trader/types"
 	"strconv"
 	"strings"
@@ -166,7 +165,7 @@ func (t *LighterTraderV2) GetAccountBalance() (*AccountBalance, error) {
 		MaintenanceMargin: maintenanceMargin,
 	}
 
-	logger.Infof("✓ Lighter balance: equity=%.2f, available=%.2f, crossValue=%.2f",
+	t.log.Infof("✓ Lighter balance: equity=%.2f, available=%.2f, crossValue=%.2f",
 		totalEquity, availableBalance, crossAssetValue)
 
 	return balance, nil
@@ -271,11 +270,11 @@ func (t *LighterTraderV2) GetPositionsRaw(symbol string) ([]Position, error) {
 		}
 		positions = append(positions, pos)
 
-		logger.Infof("✓ Lighter position: %s %s size=%.4f entry=%.2f mark=%.2f lev=%.1fx pnl=%.4f",
+		t.log.Infof("✓ Lighter position: %s %s size=%.4f entry=%.2f mark=%.2f lev=%.1fx pnl=%.4f",
 			lPos.Symbol, side, size, entryPrice, markPrice, leverage, pnl)
 	}
 
-	logger.Infof("✓ Lighter positions: found %d positions", len(positions))
+	t.log.Infof("✓ Lighter positions: found %d positions", len(positions))
 	return positions, nil
 }
 
@@ -357,7 +356,7 @@ func (t *LighterTraderV2) GetMarketPrice(symbol string) (float64, error) {
 				return 0, fmt.Errorf("invalid price for %s: %.2f", normalizedSymbol, price)
 			}
 
-			logger.Infof("✓ Lighter %s price: %.2f", normalizedSymbol, price)
+			t.log.Infof("✓ Lighter %s price: %.2f", normalizedSymbol, price)
 			return price, nil
 		}
 	}
@@ -466,7 +465,7 @@ func (t *LighterTraderV2) GetOrderBook(symbol string, depth int) (bids, asks [][
 	}
 
 	if len(bids) > 0 && len(asks) > 0 {
-		logger.Infof("✓ Lighter order book: %s best_bid=%.2f, best_ask=%.2f, depth=%d/%d",
+		t.log.Infof("✓ Lighter order book: %s best_bid=%.2f, best_ask=%.2f, depth=%d/%d",
 			symbol, bids[0][0], asks[0][0], len(bids), len(asks))
 	}
 
@@ -6,7 +6,6 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"nofx/logger"
 	"strconv"
 
 	"github.com/elliottech/lighter-go/types"
@@ -19,7 +18,7 @@ func (t *LighterTraderV2) SetStopLoss(symbol string, positionSide string, quanti
 		return fmt.Errorf("TxClient not initialized")
 	}
 
-	logger.Infof("🛑 LIGHTER Setting stop-loss: %s %s qty=%.4f, trigger=%.2f", symbol, positionSide, quantity, stopPrice)
+	t.log.Infof("🛑 LIGHTER Setting stop-loss: %s %s qty=%.4f, trigger=%.2f", symbol, positionSide, quantity, stopPrice)
 
 	// Determine order direction (long position uses sell order, short position uses buy order)
 	isAsk := (positionSide == "LONG" || positionSide == "long")
@@ -30,7 +29,7 @@ func (t *LighterTraderV2) SetStopLoss(symbol string, positionSide string, quanti
 		return fmt.Errorf("failed to set stop-loss: %w", err)
 	}
 
-	logger.Infof("✓ LIGHTER stop-loss set: trigger=%.2f", stopPrice)
+	t.log.Infof("✓ LIGHTER stop-loss set: trigger=%.2f", stopPrice)
 	return nil
 }
 
@@ -41,7 +40,7 @@ func (t *LighterTraderV2) SetTakeProfit(symbol string, positionSide string, quan
 		return fmt.Errorf("TxClient not initialized")
 	}
 
-	logger.Infof("🎯 LIGHTER Setting take-profit: %s %s qty=%.4f, trigger=%.2f", symbol, positionSide, quantity, takeProfitPrice)
+	t.log.Infof("🎯 LIGHTER Setting take-profit: %s %s qty=%.4f, trigger=%.2f", symbol, positionSide, quantity, takeProfitPrice)
 
 	// Determine order direction (long position uses sell order, short position uses buy order)
 	isAsk := (positionSide == "LONG" || positionSide == "long")
@@ -52,7 +51,7 @@ func (t *LighterTraderV2) SetTakeProfit(symbol string, positionSide string, quan
 		return fmt.Errorf("failed to set take-profit: %w", err)
 	}
 
-	logger.Infof("✓ LIGHTER take-profit set: trigger=%.2f", takeProfitPrice)
+	t.log.Infof("✓ LIGHTER take-profit set: trigger=%.2f", takeProfitPrice)
 	return nil
 }
 
@@ -73,7 +72,7 @@ func (t *LighterTraderV2) CancelAllOrders(symbol string) error {
 	}
 
 	if len(orders) == 0 {
-		logger.Infof("✓ LIGHTER - No orders to cancel (no active orders)")
+		t.log.Infof("✓ LIGHTER - No orders to cancel (no active orders)")
 		return nil
 	}
 
@@ -81,13 +80,13 @@ func (t *LighterTraderV2) CancelAllOrders(symbol string) error {
 	canceledCount := 0
 	for _, order := range orders {
 		if err := t.CancelOrder(symbol, order.OrderID); err != nil {
-			logger.Infof("⚠️  Failed to cancel order (ID: %s): %v", order.OrderID, err)
+			t.log.Infof("⚠️  Failed to cancel order (ID: %s): %v", order.OrderID, err)
 		} else {
 			canceledCount++
 		}
 	}
 
-	logger.Infof("✓ LIGHTER - Canceled %d orders", canceledCount)
+	t.log.Infof("✓ LIGHTER - Canceled %d orders", canceledCount)
 	return nil
 }
 
@@ -158,14 +157,14 @@ func (t *LighterTraderV2) GetOrderStatus(symbol string, orderID string) (map[str
 // CancelStopLossOrders Cancel only stop-loss orders (implements Trader interface)
 func (t *LighterTraderV2) CancelStopLossOrders(symbol string) error {
 	// LIGHTER cannot distinguish between stop-loss and take-profit orders yet, will cancel all stop orders
-	logger.Infof("⚠️  LIGHTER cannot distinguish stop-loss/take-profit orders, will cancel all stop orders")
+	t.log.Infof("⚠️  LIGHTER cannot distinguish stop-loss/take-profit orders, will cancel all stop orders")
 	return t.CancelStopOrders(symbol)
 }
 
 // CancelTakeProfitOrders Cancel only take-profit orders (implements Trader interface)
 func (t *LighterTraderV2) CancelTakeProfitOrders(symbol string) error {
 	// LIGHTER cannot distinguish between stop-loss and take-profit orders yet, will cancel all stop orders
-	logger.Infof("⚠️  LIGHTER cannot distinguish stop-loss/take-profit orders, will cancel all stop orders")
+	t.log.Infof("⚠️  LIGHTER cannot distinguish stop-loss/take-profit orders, will cancel all stop orders")
 	return t.CancelStopOrders(symbol)
 }
 
@@ -190,13 +189,13 @@ func (t *LighterTraderV2) CancelStopOrders(symbol string) error {
 		// TODO: Check order type, only cancel stop orders
 		// For now, cancel all orders
 		if err := t.CancelOrder(symbol, order.OrderID); err != nil {
-			logger.Infof("⚠️  Failed to cancel order (ID: %s): %v", order.OrderID, err)
+			t.log.Infof("⚠️  Failed to cancel order (ID: %s): %v", order.OrderID, err)
 		} else {
 			canceledCount++
 		}
 	}
 
-	logger.Infof("✓ LIGHTER - Canceled %d stop orders", canceledCount)
+	t.log.Infof("✓ LIGHTER - Canceled %d stop orders", canceledCount)
 	return nil
 }
 
@@ -220,7 +219,7 @@ func (t *LighterTraderV2) GetActiveOrders(symbol string) ([]OrderResponse, error
 	endpoint := fmt.Sprintf("%s/api/v1/accountActiveOrders?account_index=%d&market_id=%d&auth=%s",
 		t.baseURL, t.accountIndex, marketIndex, encodedAuth)
 
-	logger.Debugf("📋 LIGHTER GetActiveOrders: endpoint=%s", endpoint[:min(len(endpoint), 120)]+"...")
+	t.log.Debugf("📋 LIGHTER GetActiveOrders: endpoint=%s", endpoint[:min(len(endpoint), 120)]+"...")
 
 	// Send GET request
 	req, err := http.NewRequest("GET", endpoint, nil)
@@ -241,7 +240,7 @@ func (t *LighterTraderV2) GetActiveOrders(symbol string) ([]OrderResponse, error
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	logger.Debugf("📋 LIGHTER GetActiveOrders raw response: %s", string(body))
+	t.log.Debugf("📋 LIGHTER GetActiveOrders raw response: %s", string(body))
 
 	// Parse response - Lighter API uses "orders" field, not "data"
 	var apiResp struct {
@@ -258,9 +257,9 @@ func (t *LighterTraderV2) GetActiveOrders(symbol string) ([]OrderResponse, error
 		return nil, fmt.Errorf("failed to get active orders (code %d): %s", apiResp.Code, apiResp.Message)
 	}
 
-	logger.Infof("✓ LIGHTER - Retrieved %d active orders", len(apiResp.Orders))
+	t.log.Infof("✓ LIGHTER - Retrieved %d active orders", len(apiResp.Orders))
 	for i, order := range apiResp.Orders {
-		logger.Debugf("   Order[%d]: order_id=%s, order_index=%d, market=%d", i, order.OrderID, order.OrderIndex, order.MarketIndex)
+		t.log.Debugf("   Order[%d]: order_id=%s, order_index=%d, market=%d", i, order.OrderID, order.OrderIndex, order.MarketIndex)
 	}
 	return apiResp.Orders, nil
 }
@@ -283,7 +282,7 @@ func (t *LighterTraderV2) CancelOrder(symbol, orderID string) error {
 	orderIndex, err := strconv.ParseInt(orderID, 10, 64)
 	if err != nil {
 		// orderID is a tx_hash, need to query order to get numeric order_index
-		logger.Debugf("📋 LIGHTER CancelOrder: orderID is tx_hash, querying order...")
+		t.log.Debugf("📋 LIGHTER CancelOrder: orderID is tx_hash, querying order...")
 		orderIndex, err = t.getOrderIndexByTxHash(symbol, orderID)
 		if err != nil {
 			return fmt.Errorf("failed to get order index from tx_hash: %w", err)
@@ -321,7 +320,7 @@ func (t *LighterTraderV2) CancelOrder(symbol, orderID string) error {
 		return fmt.Errorf("failed to submit cancel order: %w", err)
 	}
 
-	logger.Infof("✓ LIGHTER order canceled - ID: %s", orderID)
+	t.log.Infof("✓ LIGHTER order canceled - ID: %s", orderID)
 	return nil
 }
 
@@ -336,7 +335,7 @@ func (t *LighterTraderV2) getOrderIndexByTxHash(symbol, txHash string) (int64, e
 	// Search for the order with matching tx_hash (order_id)
 	for _, order := range orders {
 		if order.OrderID == txHash {
-			logger.Debugf("📋 LIGHTER Found order_index %d for tx_hash %s", order.OrderIndex, txHash)
+			t.log.Debugf("📋 LIGHTER Found order_index %d for tx_hash %s", order.OrderIndex, txHash)
 			return order.OrderIndex, nil
 		}
 	}
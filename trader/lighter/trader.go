@@ -91,6 +91,12 @@ type LighterTraderV2 struct {
 	marketMutex         sync.RWMutex
 	marketListCache     []MarketInfo // Cached market list
 	marketListCacheTime time.Time    // Time when cache was populated
+
+	log *logger.Scope // see types.LogScoped
+}
+
+func (t *LighterTraderV2) SetLogScope(scope *logger.Scope) {
+	t.log = scope
 }
 
 // NewLighterTraderV2 Create new LIGHTER trader (using official SDK)
@@ -191,7 +197,7 @@ func (t *LighterTraderV2) initializeAccount() error {
 	t.accountIndex = accountInfo.AccountIndex
 	t.accountMutex.Unlock()
 
-	logger.Infof("✓ Account index: %d", t.accountIndex)
+	t.log.Infof("✓ Account index: %d", t.accountIndex)
 	return nil
 }
 
@@ -217,7 +223,7 @@ func (t *LighterTraderV2) getAccountByL1Address() (*AccountInfo, error) {
 	}
 
 	// Log raw response for debugging
-	logger.Debugf("LIGHTER account API response: %s", string(body))
+	t.log.Debugf("LIGHTER account API response: %s", string(body))
 
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("failed to get account (status %d): %s", resp.StatusCode, string(body))
@@ -244,9 +250,9 @@ func (t *LighterTraderV2) getAccountByL1Address() (*AccountInfo, error) {
 	}
 
 	// Log account summary
-	logger.Infof("Found %d account(s) (main: %d, sub: %d)", len(allAccounts), len(accountResp.Accounts), len(accountResp.SubAccounts))
+	t.log.Infof("Found %d account(s) (main: %d, sub: %d)", len(allAccounts), len(accountResp.Accounts), len(accountResp.SubAccounts))
 	for i, acc := range allAccounts {
-		logger.Debugf("  Account[%d]: index=%d, collateral=%s", i, acc.AccountIndex, acc.Collateral)
+		t.log.Debugf("  Account[%d]: index=%d, collateral=%s", i, acc.AccountIndex, acc.Collateral)
 	}
 
 	account := &allAccounts[0]
@@ -333,7 +339,7 @@ func (t *LighterTraderV2) checkClient() error {
 		return fmt.Errorf("API Key mismatch: local=%s, server=%s", localPubKey, serverPubKey)
 	}
 
-	logger.Infof("✓ API Key verification passed")
+	t.log.Infof("✓ API Key verification passed")
 	return nil
 }
 
@@ -368,7 +374,7 @@ func (t *LighterTraderV2) refreshAuthToken() error {
 	t.tokenExpiry = deadline
 	t.accountMutex.Unlock()
 
-	logger.Infof("✓ Auth token generated (valid until: %s)", t.tokenExpiry.Format(time.RFC3339))
+	t.log.Infof("✓ Auth token generated (valid until: %s)", t.tokenExpiry.Format(time.RFC3339))
 	return nil
 }
 
@@ -471,7 +477,7 @@ func (t *LighterTraderV2) GetTrades(startTime time.Time, limit int) ([]tradertyp
 	endpoint := fmt.Sprintf("%s/api/v1/trades?account_index=%d&sort_by=timestamp&sort_dir=desc&limit=%d&auth=%s",
 		t.baseURL, t.accountIndex, limit, encodedAuth)
 
-	logger.Infof("🔍 Calling Lighter GetTrades API: %s", endpoint[:min(len(endpoint), 150)]+"...")
+	t.log.Infof("🔍 Calling Lighter GetTrades API: %s", endpoint[:min(len(endpoint), 150)]+"...")
 
 	req, err := http.NewRequest("GET", endpoint, nil)
 	if err != nil {
@@ -490,26 +496,26 @@ func (t *LighterTraderV2) GetTrades(startTime time.Time, limit int) ([]tradertyp
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		logger.Infof("⚠️  Lighter trades API returned %d: %s", resp.StatusCode, string(body))
+		t.log.Infof("⚠️  Lighter trades API returned %d: %s", resp.StatusCode, string(body))
 		return []tradertypes.TradeRecord{}, nil
 	}
 
 	// Debug: log raw response
-	logger.Debugf("Lighter trades API response: %s", string(body))
+	t.log.Debugf("Lighter trades API response: %s", string(body))
 
 	var response LighterTradeResponse
 	if err := json.Unmarshal(body, &response); err != nil {
-		logger.Infof("⚠️  Failed to parse trades response as object: %v", err)
+		t.log.Infof("⚠️  Failed to parse trades response as object: %v", err)
 		var trades []LighterTrade
 		if err := json.Unmarshal(body, &trades); err != nil {
-			logger.Infof("⚠️  Failed to parse trades response as array: %v", err)
+			t.log.Infof("⚠️  Failed to parse trades response as array: %v", err)
 			return []tradertypes.TradeRecord{}, nil
 		}
 		response.Trades = trades
 	}
 
 	if response.Code != 200 && response.Code != 0 {
-		logger.Infof("⚠️  Trades API returned non-success code: %d", response.Code)
+		t.log.Infof("⚠️  Trades API returned non-success code: %d", response.Code)
 		return []tradertypes.TradeRecord{}, nil
 	}
 
@@ -517,7 +523,7 @@ func (t *LighterTraderV2) GetTrades(startTime time.Time, limit int) ([]tradertyp
 	marketMap := make(map[int]string)
 	markets, err := t.fetchMarketList()
 	if err != nil {
-		logger.Infof("⚠️  Failed to fetch market list: %v, using fallback", err)
+		t.log.Infof("⚠️  Failed to fetch market list: %v, using fallback", err)
 		// Fallback market IDs (common ones)
 		marketMap[0] = "BTC"
 		marketMap[1] = "ETH"
@@ -644,7 +650,7 @@ func (t *LighterTraderV2) GetTrades(startTime time.Time, limit int) ([]tradertyp
 			}
 			result = append(result, openTrade)
 
-			logger.Infof("  🔄 Flip: %s %.4f → %s %.4f", closeSide, closeQty, openSide, openQty)
+			t.log.Infof("  🔄 Flip: %s %.4f → %s %.4f", closeSide, closeQty, openSide, openQty)
 			continue
 		}
 
@@ -7,7 +7,6 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
-	"nofx/logger"
 	"strconv"
 	"strings"
 	"time"
@@ -22,16 +21,16 @@ func (t *LighterTraderV2) OpenLong(symbol string, quantity float64, leverage int
 		return nil, fmt.Errorf("TxClient not initialized, please set API Key first")
 	}
 
-	logger.Infof("📈 LIGHTER opening long: %s, qty=%.4f, leverage=%dx", symbol, quantity, leverage)
+	t.log.Infof("📈 LIGHTER opening long: %s, qty=%.4f, leverage=%dx", symbol, quantity, leverage)
 
 	// 1. First cancel all pending orders for this symbol (clean up old stop-loss and take-profit orders)
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("⚠️  Failed to cancel old pending orders: %v", err)
+		t.log.Infof("⚠️  Failed to cancel old pending orders: %v", err)
 	}
 
 	// 2. Set leverage (if needed)
 	if err := t.SetLeverage(symbol, leverage); err != nil {
-		logger.Infof("⚠️  Failed to set leverage: %v", err)
+		t.log.Infof("⚠️  Failed to set leverage: %v", err)
 	}
 
 	// 3. Get market price
@@ -46,7 +45,7 @@ func (t *LighterTraderV2) OpenLong(symbol string, quantity float64, leverage int
 		return nil, fmt.Errorf("failed to open long: %w", err)
 	}
 
-	logger.Infof("✓ LIGHTER opened long successfully: %s @ %.2f", symbol, marketPrice)
+	t.log.Infof("✓ LIGHTER opened long successfully: %s @ %.2f", symbol, marketPrice)
 
 	return map[string]interface{}{
 		"orderId": orderResult["orderId"],
@@ -63,16 +62,16 @@ func (t *LighterTraderV2) OpenShort(symbol string, quantity float64, leverage in
 		return nil, fmt.Errorf("TxClient not initialized, please set API Key first")
 	}
 
-	logger.Infof("📉 LIGHTER opening short: %s, qty=%.4f, leverage=%dx", symbol, quantity, leverage)
+	t.log.Infof("📉 LIGHTER opening short: %s, qty=%.4f, leverage=%dx", symbol, quantity, leverage)
 
 	// 1. First cancel all pending orders for this symbol (clean up old stop-loss and take-profit orders)
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("⚠️  Failed to cancel old pending orders: %v", err)
+		t.log.Infof("⚠️  Failed to cancel old pending orders: %v", err)
 	}
 
 	// 2. Set leverage
 	if err := t.SetLeverage(symbol, leverage); err != nil {
-		logger.Infof("⚠️  Failed to set leverage: %v", err)
+		t.log.Infof("⚠️  Failed to set leverage: %v", err)
 	}
 
 	// 3. Get market price
@@ -87,7 +86,7 @@ func (t *LighterTraderV2) OpenShort(symbol string, quantity float64, leverage in
 		return nil, fmt.Errorf("failed to open short: %w", err)
 	}
 
-	logger.Infof("✓ LIGHTER opened short successfully: %s @ %.2f", symbol, marketPrice)
+	t.log.Infof("✓ LIGHTER opened short successfully: %s @ %.2f", symbol, marketPrice)
 
 	return map[string]interface{}{
 		"orderId": orderResult["orderId"],
@@ -119,11 +118,11 @@ func (t *LighterTraderV2) CloseLong(symbol string, quantity float64) (map[string
 		quantity = pos.Size
 	}
 
-	logger.Infof("🔻 LIGHTER closing long: %s, qty=%.4f", symbol, quantity)
+	t.log.Infof("🔻 LIGHTER closing long: %s, qty=%.4f", symbol, quantity)
 
 	// Cancel pending orders before closing
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("⚠️  Failed to cancel orders: %v", err)
+		t.log.Infof("⚠️  Failed to cancel orders: %v", err)
 	}
 
 	// Create market sell order to close (reduceOnly=true)
@@ -133,7 +132,7 @@ func (t *LighterTraderV2) CloseLong(symbol string, quantity float64) (map[string
 	}
 
 	txHash, _ := orderResult["orderId"].(string)
-	logger.Infof("✓ LIGHTER closed long successfully: %s (tx: %s)", symbol, txHash)
+	t.log.Infof("✓ LIGHTER closed long successfully: %s (tx: %s)", symbol, txHash)
 
 	return map[string]interface{}{
 		"orderId": txHash,
@@ -163,11 +162,11 @@ func (t *LighterTraderV2) CloseShort(symbol string, quantity float64) (map[strin
 		quantity = pos.Size
 	}
 
-	logger.Infof("🔺 LIGHTER closing short: %s, qty=%.4f", symbol, quantity)
+	t.log.Infof("🔺 LIGHTER closing short: %s, qty=%.4f", symbol, quantity)
 
 	// Cancel pending orders before closing
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("⚠️  Failed to cancel orders: %v", err)
+		t.log.Infof("⚠️  Failed to cancel orders: %v", err)
 	}
 
 	// Create market buy order to close (reduceOnly=true)
@@ -177,7 +176,7 @@ func (t *LighterTraderV2) CloseShort(symbol string, quantity float64) (map[strin
 	}
 
 	txHash, _ := orderResult["orderId"].(string)
-	logger.Infof("✓ LIGHTER closed short successfully: %s (tx: %s)", symbol, txHash)
+	t.log.Infof("✓ LIGHTER closed short successfully: %s (tx: %s)", symbol, txHash)
 
 	return map[string]interface{}{
 		"orderId": txHash,
@@ -210,14 +209,14 @@ func (t *LighterTraderV2) CreateOrder(symbol string, isAsk bool, quantity float6
 
 	// Convert quantity to LIGHTER base_amount format using dynamic precision from API
 	baseAmount := int64(quantity * float64(pow10(marketInfo.SizeDecimals)))
-	logger.Infof("🔸 Using size precision: %d decimals, quantity=%.4f → baseAmount=%d",
+	t.log.Infof("🔸 Using size precision: %d decimals, quantity=%.4f → baseAmount=%d",
 		marketInfo.SizeDecimals, quantity, baseAmount)
 
 	// Set price based on order type
 	priceValue := uint32(0)
 	if orderType == "limit" {
 		priceValue = uint32(price * float64(pow10(marketInfo.PriceDecimals)))
-		logger.Infof("🔸 LIMIT order - Price: %.2f (precision: %d decimals)", price, marketInfo.PriceDecimals)
+		t.log.Infof("🔸 LIMIT order - Price: %.2f (precision: %d decimals)", price, marketInfo.PriceDecimals)
 	} else {
 		// Market order - Price field is used as PRICE PROTECTION (slippage limit)
 		// NOT as the execution price! Set it wider to allow order to fill.
@@ -232,12 +231,12 @@ func (t *LighterTraderV2) CreateOrder(symbol string, isAsk bool, quantity float6
 		if isAsk {
 			// Selling: accept down to 95% of market price
 			protectedPrice = marketPrice * 0.95
-			logger.Infof("🔸 MARKET SELL order - Price protection: %.2f (95%% of market %.2f, precision: %d decimals)",
+			t.log.Infof("🔸 MARKET SELL order - Price protection: %.2f (95%% of market %.2f, precision: %d decimals)",
 				protectedPrice, marketPrice, marketInfo.PriceDecimals)
 		} else {
 			// Buying: accept up to 105% of market price
 			protectedPrice = marketPrice * 1.05
-			logger.Infof("🔸 MARKET BUY order - Price protection: %.2f (105%% of market %.2f, precision: %d decimals)",
+			t.log.Infof("🔸 MARKET BUY order - Price protection: %.2f (105%% of market %.2f, precision: %d decimals)",
 				protectedPrice, marketPrice, marketInfo.PriceDecimals)
 		}
 		priceValue = uint32(protectedPrice * float64(pow10(marketInfo.PriceDecimals)))
@@ -293,7 +292,7 @@ func (t *LighterTraderV2) CreateOrder(symbol string, isAsk bool, quantity float6
 	}
 
 	// Debug: Log the tx_info content
-	logger.Debugf("tx_type: %d, tx_info: %s", tx.GetTxType(), txInfo)
+	t.log.Debugf("tx_type: %d, tx_info: %s", tx.GetTxType(), txInfo)
 
 	// Submit order to LIGHTER API
 	orderResp, err := t.submitOrder(int(tx.GetTxType()), txInfo)
@@ -305,7 +304,7 @@ func (t *LighterTraderV2) CreateOrder(symbol string, isAsk bool, quantity float6
 	if isAsk {
 		side = "sell"
 	}
-	logger.Infof("✓ LIGHTER order created: %s %s qty=%.4f", symbol, side, quantity)
+	t.log.Infof("✓ LIGHTER order created: %s %s qty=%.4f", symbol, side, quantity)
 
 	// For limit orders, poll for the actual order_index after submission
 	// This is needed because CancelOrder requires the numeric order_index, not tx_hash
@@ -401,7 +400,7 @@ func (t *LighterTraderV2) submitOrder(txType int, txInfo string) (map[string]int
 	}
 
 	// Log full response for debugging
-	logger.Debugf("API response: %s", string(respBody))
+	t.log.Debugf("API response: %s", string(respBody))
 
 	// Check response code
 	if sendResp.Code != 200 {
@@ -427,7 +426,7 @@ func (t *LighterTraderV2) submitOrder(txType int, txInfo string) (map[string]int
 		}
 	}
 
-	logger.Infof("✓ Order submitted to LIGHTER - tx_hash: %s", txHash)
+	t.log.Infof("✓ Order submitted to LIGHTER - tx_hash: %s", txHash)
 
 	result := map[string]interface{}{
 		"tx_hash": txHash,
@@ -462,7 +461,7 @@ func (t *LighterTraderV2) pollForOrderIndex(symbol string, txHash string) (int64
 		}
 	}
 
-	logger.Infof("✓ Order created with order_index: %d (tx_hash: %s)", highestIndex, txHash)
+	t.log.Infof("✓ Order created with order_index: %d (tx_hash: %s)", highestIndex, txHash)
 	return highestIndex, nil
 }
 
@@ -504,7 +503,7 @@ func (t *LighterTraderV2) getMarketIndex(symbol string) (uint16, error) {
 	marketInfo, err := t.getMarketInfo(symbol)
 	if err != nil {
 		// Fallback to hardcoded mapping
-		logger.Infof("⚠️  Failed to get market info from API, using hardcoded mapping: %v", err)
+		t.log.Infof("⚠️  Failed to get market info from API, using hardcoded mapping: %v", err)
 		normalizedSymbol := normalizeSymbol(symbol)
 		return t.getFallbackMarketIndex(normalizedSymbol)
 	}
@@ -590,7 +589,7 @@ func (t *LighterTraderV2) fetchMarketList() ([]MarketInfo, error) {
 	t.marketListCacheTime = time.Now()
 	t.marketMutex.Unlock()
 
-	logger.Infof("✓ Retrieved %d active markets from Lighter", len(markets))
+	t.log.Infof("✓ Retrieved %d active markets from Lighter", len(markets))
 	return markets, nil
 }
 
@@ -610,7 +609,7 @@ func (t *LighterTraderV2) getFallbackMarketIndex(symbol string) (uint16, error)
 	}
 
 	if index, ok := fallbackMap[symbol]; ok {
-		logger.Infof("✓ Using hardcoded market index: %s -> %d", symbol, index)
+		t.log.Infof("✓ Using hardcoded market index: %s -> %d", symbol, index)
 		return index, nil
 	}
 
@@ -646,7 +645,7 @@ func (t *LighterTraderV2) SetLeverage(symbol string, leverage int) error {
 	marginFractionPercent := 100.0 / float64(leverage)
 	initialMarginFraction := uint16(marginFractionPercent * 100) // e.g., 5x => 20% => 2000
 
-	logger.Infof("⚙️  Setting leverage: %s = %dx (margin_fraction=%.2f%%, API value=%d)",
+	t.log.Infof("⚙️  Setting leverage: %s = %dx (margin_fraction=%.2f%%, API value=%d)",
 		symbol, leverage, marginFractionPercent, initialMarginFraction)
 
 	// Build UpdateLeverage request
@@ -677,7 +676,7 @@ func (t *LighterTraderV2) SetLeverage(symbol string, leverage int) error {
 		return fmt.Errorf("failed to submit leverage transaction: %w", err)
 	}
 
-	logger.Infof("✓ Leverage set successfully: %s = %dx (tx_hash: %v)", symbol, leverage, result["tx_hash"])
+	t.log.Infof("✓ Leverage set successfully: %s = %dx (tx_hash: %v)", symbol, leverage, result["tx_hash"])
 	return nil
 }
 
@@ -713,7 +712,7 @@ func (t *LighterTraderV2) SetMarginMode(symbol string, isCrossMargin bool) error
 		initialMarginFraction = uint16(marginFractionPercent * 100)
 	}
 
-	logger.Infof("⚙️  Setting margin mode: %s = %s (margin_mode=%d, preserving leverage)", symbol, modeStr, marginMode)
+	t.log.Infof("⚙️  Setting margin mode: %s = %s (margin_mode=%d, preserving leverage)", symbol, modeStr, marginMode)
 
 	// Build UpdateLeverage request (also updates margin mode)
 	txReq := &types.UpdateLeverageTxReq{
@@ -743,7 +742,7 @@ func (t *LighterTraderV2) SetMarginMode(symbol string, isCrossMargin bool) error
 		return fmt.Errorf("failed to submit margin mode transaction: %w", err)
 	}
 
-	logger.Infof("✓ Margin mode set successfully: %s = %s (tx_hash: %v)", symbol, modeStr, result["tx_hash"])
+	t.log.Infof("✓ Margin mode set successfully: %s = %s (tx_hash: %v)", symbol, modeStr, result["tx_hash"])
 	return nil
 }
 
@@ -822,7 +821,7 @@ func (t *LighterTraderV2) CreateStopOrder(symbol string, isAsk bool, quantity fl
 		return nil, fmt.Errorf("failed to get tx info: %w", err)
 	}
 
-	logger.Debugf("stop order - type: %d, trigger: %.2f, price: %.2f, isAsk: %v", orderTypeValue, triggerPrice, float64(priceValue)/100, isAsk)
+	t.log.Debugf("stop order - type: %d, trigger: %.2f, price: %.2f, isAsk: %v", orderTypeValue, triggerPrice, float64(priceValue)/100, isAsk)
 
 	// Submit order
 	orderResp, err := t.submitOrder(int(tx.GetTxType()), txInfo)
@@ -834,7 +833,7 @@ func (t *LighterTraderV2) CreateStopOrder(symbol string, isAsk bool, quantity fl
 	if isAsk {
 		side = "sell"
 	}
-	logger.Infof("✓ LIGHTER %s order created: %s %s qty=%.4f trigger=%.2f", orderType, symbol, side, quantity, triggerPrice)
+	t.log.Infof("✓ LIGHTER %s order created: %s %s qty=%.4f trigger=%.2f", orderType, symbol, side, quantity, triggerPrice)
 
 	return orderResp, nil
 }
@@ -920,7 +919,7 @@ func (t *LighterTraderV2) GetOpenOrders(symbol string) ([]tradertypes.OpenOrder,
 		result = append(result, openOrder)
 	}
 
-	logger.Infof("✓ LIGHTER GetOpenOrders: found %d open orders for %s", len(result), symbol)
+	t.log.Infof("✓ LIGHTER GetOpenOrders: found %d open orders for %s", len(result), symbol)
 	return result, nil
 }
 
@@ -934,13 +933,13 @@ func (t *LighterTraderV2) PlaceLimitOrder(req *tradertypes.LimitOrderRequest) (*
 	// Determine if this is a sell (ask) order
 	isAsk := req.Side == "SELL"
 
-	logger.Infof("📝 LIGHTER placing limit order: %s %s @ %.4f, qty=%.4f, leverage=%dx",
+	t.log.Infof("📝 LIGHTER placing limit order: %s %s @ %.4f, qty=%.4f, leverage=%dx",
 		req.Symbol, req.Side, req.Price, req.Quantity, req.Leverage)
 
 	// Set leverage before placing order (important for grid trading)
 	if req.Leverage > 0 {
 		if err := t.SetLeverage(req.Symbol, req.Leverage); err != nil {
-			logger.Warnf("⚠️  Failed to set leverage: %v (continuing with current leverage)", err)
+			t.log.Warnf("⚠️  Failed to set leverage: %v (continuing with current leverage)", err)
 		}
 	}
 
@@ -958,7 +957,7 @@ func (t *LighterTraderV2) PlaceLimitOrder(req *tradertypes.LimitOrderRequest) (*
 		orderID = fmt.Sprintf("%v", txHash)
 	}
 
-	logger.Infof("✓ LIGHTER limit order placed: %s %s @ %.4f, OrderID: %s",
+	t.log.Infof("✓ LIGHTER limit order placed: %s %s @ %.4f, OrderID: %s",
 		req.Symbol, req.Side, req.Price, orderID)
 
 	return &tradertypes.LimitOrderResult{
@@ -67,6 +67,12 @@ type OKXTrader struct {
 
 	// Cache duration
 	cacheDuration time.Duration
+
+	log *logger.Scope // see types.LogScoped
+}
+
+func (t *OKXTrader) SetLogScope(scope *logger.Scope) {
+	t.log = scope
 }
 
 // OKXInstrument OKX instrument info
@@ -169,7 +175,7 @@ func (t *OKXTrader) detectPositionMode() error {
 
 	if len(configs) > 0 {
 		t.positionMode = configs[0].PosMode
-		logger.Infof("✓ Detected OKX position mode: %s", t.positionMode)
+		t.log.Infof("✓ Detected OKX position mode: %s", t.positionMode)
 	}
 
 	return nil
@@ -185,13 +191,13 @@ func (t *OKXTrader) setPositionMode() error {
 	if err != nil {
 		// Ignore error if already in dual position mode
 		if strings.Contains(err.Error(), "already") || strings.Contains(err.Error(), "Position mode is not modified") {
-			logger.Infof("  ✓ OKX account is already in dual position mode")
+			t.log.Infof("  ✓ OKX account is already in dual position mode")
 			return nil
 		}
 		return err
 	}
 
-	logger.Infof("  ✓ OKX account switched to dual position mode")
+	t.log.Infof("  ✓ OKX account switched to dual position mode")
 	return nil
 }
 
@@ -3,7 +3,6 @@ package okx
 import (
 	"encoding/json"
 	"fmt"
-	"nofx/logger"
 	"nofx/trader/types"
 	"strconv"
 	"strings"
@@ -16,12 +15,12 @@ func (t *OKXTrader) GetBalance() (map[string]interface{}, error) {
 	t.balanceCacheMutex.RLock()
 	if t.cachedBalance != nil && time.Since(t.balanceCacheTime) < t.cacheDuration {
 		t.balanceCacheMutex.RUnlock()
-		logger.Infof("✓ Using cached OKX account balance")
+		t.log.Infof("✓ Using cached OKX account balance")
 		return t.cachedBalance, nil
 	}
 	t.balanceCacheMutex.RUnlock()
 
-	logger.Infof("🔄 Calling OKX API to get account balance...")
+	t.log.Infof("🔄 Calling OKX API to get account balance...")
 	data, err := t.doRequest("GET", okxAccountPath, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get account balance: %w", err)
@@ -76,7 +75,7 @@ func (t *OKXTrader) GetBalance() (map[string]interface{}, error) {
 		"totalUnrealizedProfit": usdtUPL,
 	}
 
-	logger.Infof("✓ OKX balance: Total equity=%.2f, Available=%.2f, Unrealized PnL=%.2f", totalEq, usdtAvail, usdtUPL)
+	t.log.Infof("✓ OKX balance: Total equity=%.2f, Available=%.2f, Unrealized PnL=%.2f", totalEq, usdtAvail, usdtUPL)
 
 	// Update cache
 	t.balanceCacheMutex.Lock()
@@ -108,7 +107,7 @@ func (t *OKXTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
 	// while leverage uses mgnMode on /account/set-leverage.
 	// Persist the configured mode locally so subsequent leverage/order calls use it,
 	// instead of calling the legacy isolated-mode endpoint that returns 51000 errors.
-	logger.Infof("  ✓ %s margin mode configured as %s (applied via tdMode/mgnMode on subsequent requests)", symbol, mgnMode)
+	t.log.Infof("  ✓ %s margin mode configured as %s (applied via tdMode/mgnMode on subsequent requests)", symbol, mgnMode)
 	return nil
 }
 
@@ -132,11 +131,11 @@ func (t *OKXTrader) SetLeverage(symbol string, leverage int) error {
 			if strings.Contains(err.Error(), "same") {
 				continue
 			}
-			logger.Infof("  ⚠️ Failed to set %s %s leverage: %v", symbol, posSide, err)
+			t.log.Infof("  ⚠️ Failed to set %s %s leverage: %v", symbol, posSide, err)
 		}
 	}
 
-	logger.Infof("  ✓ %s leverage set to %dx (%s)", symbol, leverage, marginMode)
+	t.log.Infof("  ✓ %s leverage set to %dx (%s)", symbol, leverage, marginMode)
 	return nil
 }
 
@@ -278,3 +277,61 @@ func (t *OKXTrader) GetClosedPnL(startTime time.Time, limit int) ([]types.Closed
 
 	return records, nil
 }
+
+// fundingPageLimit caps the bill pages read per funding sync
+const fundingPageLimit = 20
+
+// GetFundingPayments retrieves funding fee bills (type 8) for swap positions.
+// Bills are newest first and paged by bill ID, so all pages since startTime
+// are read and the oldest limit payments returned; the next sync continues
+// after them.
+func (t *OKXTrader) GetFundingPayments(startTime time.Time, limit int) ([]types.FundingPayment, error) {
+	type fundingBill struct {
+		BillID string `json:"billId"`
+		InstID string `json:"instId"`
+		BalChg string `json:"balChg"` // Balance change: positive = received
+		Ts     string `json:"ts"`
+	}
+	var bills []fundingBill
+	after := ""
+	for page := 0; page < fundingPageLimit; page++ {
+		path := fmt.Sprintf("/api/v5/account/bills?instType=SWAP&type=8&limit=100&begin=%d", startTime.UnixMilli())
+		if after != "" {
+			path += "&after=" + after // bills older than this bill ID
+		}
+		data, err := t.doRequest("GET", path, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get funding fee bills: %w", err)
+		}
+		var pageBills []fundingBill
+		if err := json.Unmarshal(data, &pageBills); err != nil {
+			return nil, fmt.Errorf("failed to parse funding fee bills: %w", err)
+		}
+		bills = append(bills, pageBills...)
+		if len(pageBills) < 100 {
+			break
+		}
+		after = pageBills[len(pageBills)-1].BillID
+	}
+
+	// Bills are newest first
+	payments := make([]types.FundingPayment, 0, len(bills))
+	for i := len(bills) - 1; i >= 0; i-- {
+		bill := bills[i]
+		amount, err := strconv.ParseFloat(bill.BalChg, 64)
+		if err != nil || amount == 0 {
+			continue
+		}
+		ts, _ := strconv.ParseInt(bill.Ts, 10, 64)
+		payments = append(payments, types.FundingPayment{
+			PaymentID: bill.BillID,
+			Symbol:    t.convertSymbolBack(bill.InstID),
+			Amount:    amount,
+			Time:      time.UnixMilli(ts).UTC(),
+		})
+	}
+	if limit > 0 && len(payments) > limit {
+		payments = payments[:limit]
+	}
+	return payments, nil
+}
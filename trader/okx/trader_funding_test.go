@@ -0,0 +1,52 @@
+package okx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// fundingBillsTransport serves 150 funding bills, newest first, 100 a page
+type fundingBillsTransport struct {
+	afters []string
+}
+
+func (ft *fundingBillsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	after := req.URL.Query().Get("after")
+	ft.afters = append(ft.afters, after)
+	first := 150
+	if after != "" {
+		fmt.Sscanf(after, "%d", &first)
+		first--
+	}
+	var bills []map[string]string
+	for id := first; id >= 1 && len(bills) < 100; id-- {
+		bills = append(bills, map[string]string{
+			"billId": fmt.Sprint(id), "instId": "BTC-USDT-SWAP", "balChg": "-0.1",
+			"ts": fmt.Sprint(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(id) * time.Hour).UnixMilli()),
+		})
+	}
+	data, _ := json.Marshal(map[string]interface{}{"code": "0", "msg": "", "data": bills})
+	return &http.Response{StatusCode: http.StatusOK, Header: make(http.Header), Body: io.NopCloser(bytes.NewReader(data))}, nil
+}
+
+func TestOKXGetFundingPaymentsReadsAllPages(t *testing.T) {
+	ft := &fundingBillsTransport{}
+	trader := &OKXTrader{apiKey: "key", secretKey: "secret", passphrase: "pass", httpClient: &http.Client{Transport: ft}}
+
+	payments, err := trader.GetFundingPayments(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), 120)
+	if err != nil {
+		t.Fatalf("GetFundingPayments: %v", err)
+	}
+	if len(ft.afters) != 2 || ft.afters[1] != "51" {
+		t.Fatalf("pages requested after %q, want a second page after bill 51", ft.afters)
+	}
+	// The oldest 120 of 150, oldest first, so the next sync continues after them
+	if len(payments) != 120 || payments[0].PaymentID != "1" || payments[119].PaymentID != "120" {
+		t.Fatalf("got %d payments from %s to %s, want bills 1 to 120", len(payments), payments[0].PaymentID, payments[len(payments)-1].PaymentID)
+	}
+}
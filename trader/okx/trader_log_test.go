@@ -0,0 +1,37 @@
+package okx
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"nofx/logger"
+)
+
+func TestOKXLogsThroughTraderScope(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := logger.Init(&logger.Config{Format: logger.FormatJSON}); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	var out bytes.Buffer
+	logger.Log.SetOutput(&out)
+	t.Cleanup(func() {
+		logger.Shutdown()
+		logger.Init(nil)
+	})
+
+	trader := &OKXTrader{}
+	trader.SetLogScope(logger.NewScope(logger.Fields{logger.FieldTraderID: "t1", logger.FieldExchange: "okx"}))
+	if err := trader.SetMarginMode("BTCUSDT", true); err != nil {
+		t.Fatalf("SetMarginMode: %v", err)
+	}
+
+	var line map[string]interface{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(out.String())), &line); err != nil {
+		t.Fatalf("line is not JSON: %v (%q)", err, out.String())
+	}
+	if line["trader_id"] != "t1" || line["exchange"] != "okx" {
+		t.Fatalf("adapter line lacks the trader's fields: %v", line)
+	}
+}
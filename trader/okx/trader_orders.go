@@ -3,7 +3,6 @@ package okx
 import (
 	"encoding/json"
 	"fmt"
-	"nofx/logger"
 	"nofx/trader/types"
 	"strconv"
 	"strings"
@@ -13,12 +12,12 @@ import (
 func (t *OKXTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
 	// Cancel old orders
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
+		t.log.Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
 	}
 
 	// Set leverage
 	if err := t.SetLeverage(symbol, leverage); err != nil {
-		logger.Infof("  ⚠️ Failed to set leverage: %v", err)
+		t.log.Infof("  ⚠️ Failed to set leverage: %v", err)
 	}
 
 	instId := t.convertSymbol(symbol)
@@ -34,11 +33,11 @@ func (t *OKXTrader) OpenLong(symbol string, quantity float64, leverage int) (map
 	sz := quantity / inst.CtVal
 	szStr := t.formatSize(sz, inst)
 
-	logger.Infof("  📊 OKX OpenLong: quantity=%.6f, ctVal=%.6f, contracts=%.2f", quantity, inst.CtVal, sz)
+	t.log.Infof("  📊 OKX OpenLong: quantity=%.6f, ctVal=%.6f, contracts=%.2f", quantity, inst.CtVal, sz)
 
 	// Check max market order size limit
 	if inst.MaxMktSz > 0 && sz > inst.MaxMktSz {
-		logger.Infof("  ⚠️ OKX market order size %.2f exceeds max %.2f, reducing to max", sz, inst.MaxMktSz)
+		t.log.Infof("  ⚠️ OKX market order size %.2f exceeds max %.2f, reducing to max", sz, inst.MaxMktSz)
 		sz = inst.MaxMktSz
 		szStr = t.formatSize(sz, inst)
 	}
@@ -80,8 +79,8 @@ func (t *OKXTrader) OpenLong(symbol string, quantity float64, leverage int) (map
 		return nil, fmt.Errorf("failed to open long position: %s", msg)
 	}
 
-	logger.Infof("✓ OKX opened long position successfully: %s size: %s", symbol, szStr)
-	logger.Infof("  Order ID: %s", orders[0].OrdId)
+	t.log.Infof("✓ OKX opened long position successfully: %s size: %s", symbol, szStr)
+	t.log.Infof("  Order ID: %s", orders[0].OrdId)
 
 	return map[string]interface{}{
 		"orderId": orders[0].OrdId,
@@ -94,12 +93,12 @@ func (t *OKXTrader) OpenLong(symbol string, quantity float64, leverage int) (map
 func (t *OKXTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
 	// Cancel old orders
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
+		t.log.Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
 	}
 
 	// Set leverage
 	if err := t.SetLeverage(symbol, leverage); err != nil {
-		logger.Infof("  ⚠️ Failed to set leverage: %v", err)
+		t.log.Infof("  ⚠️ Failed to set leverage: %v", err)
 	}
 
 	instId := t.convertSymbol(symbol)
@@ -115,11 +114,11 @@ func (t *OKXTrader) OpenShort(symbol string, quantity float64, leverage int) (ma
 	sz := quantity / inst.CtVal
 	szStr := t.formatSize(sz, inst)
 
-	logger.Infof("  📊 OKX OpenShort: quantity=%.6f, ctVal=%.6f, contracts=%.2f", quantity, inst.CtVal, sz)
+	t.log.Infof("  📊 OKX OpenShort: quantity=%.6f, ctVal=%.6f, contracts=%.2f", quantity, inst.CtVal, sz)
 
 	// Check max market order size limit
 	if inst.MaxMktSz > 0 && sz > inst.MaxMktSz {
-		logger.Infof("  ⚠️ OKX market order size %.2f exceeds max %.2f, reducing to max", sz, inst.MaxMktSz)
+		t.log.Infof("  ⚠️ OKX market order size %.2f exceeds max %.2f, reducing to max", sz, inst.MaxMktSz)
 		sz = inst.MaxMktSz
 		szStr = t.formatSize(sz, inst)
 	}
@@ -161,8 +160,8 @@ func (t *OKXTrader) OpenShort(symbol string, quantity float64, leverage int) (ma
 		return nil, fmt.Errorf("failed to open short position: %s", msg)
 	}
 
-	logger.Infof("✓ OKX opened short position successfully: %s size: %s", symbol, szStr)
-	logger.Infof("  Order ID: %s", orders[0].OrdId)
+	t.log.Infof("✓ OKX opened short position successfully: %s size: %s", symbol, szStr)
+	t.log.Infof("  Order ID: %s", orders[0].OrdId)
 
 	return map[string]interface{}{
 		"orderId": orders[0].OrdId,
@@ -192,9 +191,9 @@ func (t *OKXTrader) CloseLong(symbol string, quantity float64) (map[string]inter
 	var actualQty float64
 	var posFound bool
 	var posMgnMode string = "cross" // Default to cross margin
-	logger.Infof("🔍 OKX CloseLong: searching for symbol=%s in %d positions", symbol, len(positions))
+	t.log.Infof("🔍 OKX CloseLong: searching for symbol=%s in %d positions", symbol, len(positions))
 	for _, pos := range positions {
-		logger.Infof("🔍 OKX position: symbol=%v, side=%v, positionAmt=%v, mgnMode=%v", pos["symbol"], pos["side"], pos["positionAmt"], pos["mgnMode"])
+		t.log.Infof("🔍 OKX position: symbol=%v, side=%v, positionAmt=%v, mgnMode=%v", pos["symbol"], pos["side"], pos["positionAmt"], pos["mgnMode"])
 		if pos["symbol"] == symbol {
 			side := pos["side"].(string)
 			// In net_mode, "long" means positive position
@@ -205,14 +204,14 @@ func (t *OKXTrader) CloseLong(symbol string, quantity float64) (map[string]inter
 				if mgnMode, ok := pos["mgnMode"].(string); ok && mgnMode != "" {
 					posMgnMode = mgnMode
 				}
-				logger.Infof("🔍 OKX CloseLong: found matching position! qty=%.6f, mgnMode=%s", actualQty, posMgnMode)
+				t.log.Infof("🔍 OKX CloseLong: found matching position! qty=%.6f, mgnMode=%s", actualQty, posMgnMode)
 				break
 			}
 		}
 	}
 
 	if !posFound || actualQty == 0 {
-		logger.Infof("🔍 OKX CloseLong: NO position found for %s LONG", symbol)
+		t.log.Infof("🔍 OKX CloseLong: NO position found for %s LONG", symbol)
 		return map[string]interface{}{
 			"status":  "NO_POSITION",
 			"message": fmt.Sprintf("No long position found for %s on OKX", symbol),
@@ -229,7 +228,7 @@ func (t *OKXTrader) CloseLong(symbol string, quantity float64) (map[string]inter
 	contracts := quantity / inst.CtVal
 	szStr := t.formatSize(contracts, inst)
 
-	logger.Infof("🔻 OKX close long: symbol=%s, instId=%s, quantity=%.6f, ctVal=%.6f, contracts=%.2f, szStr=%s, posMode=%s, mgnMode=%s",
+	t.log.Infof("🔻 OKX close long: symbol=%s, instId=%s, quantity=%.6f, ctVal=%.6f, contracts=%.2f, szStr=%s, posMode=%s, mgnMode=%s",
 		symbol, instId, quantity, inst.CtVal, contracts, szStr, t.positionMode, posMgnMode)
 
 	body := map[string]interface{}{
@@ -270,11 +269,11 @@ func (t *OKXTrader) CloseLong(symbol string, quantity float64) (map[string]inter
 		return nil, fmt.Errorf("failed to close long position: %s", msg)
 	}
 
-	logger.Infof("✓ OKX closed long position successfully: %s", symbol)
+	t.log.Infof("✓ OKX closed long position successfully: %s", symbol)
 
 	// Cancel pending orders after closing position
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel pending orders: %v", err)
+		t.log.Infof("  ⚠ Failed to cancel pending orders: %v", err)
 	}
 
 	return map[string]interface{}{
@@ -305,9 +304,9 @@ func (t *OKXTrader) CloseShort(symbol string, quantity float64) (map[string]inte
 	var actualQty float64
 	var posFound bool
 	var posMgnMode string = "cross" // Default to cross margin
-	logger.Infof("🔍 OKX CloseShort searching positions: symbol=%s, current position count=%d", symbol, len(positions))
+	t.log.Infof("🔍 OKX CloseShort searching positions: symbol=%s, current position count=%d", symbol, len(positions))
 	for _, pos := range positions {
-		logger.Infof("🔍 OKX position: symbol=%v, side=%v, positionAmt=%v, mgnMode=%v",
+		t.log.Infof("🔍 OKX position: symbol=%v, side=%v, positionAmt=%v, mgnMode=%v",
 			pos["symbol"], pos["side"], pos["positionAmt"], pos["mgnMode"])
 		if pos["symbol"] == symbol && pos["side"] == "short" {
 			actualQty = pos["positionAmt"].(float64)
@@ -315,7 +314,7 @@ func (t *OKXTrader) CloseShort(symbol string, quantity float64) (map[string]inte
 			if mgnMode, ok := pos["mgnMode"].(string); ok && mgnMode != "" {
 				posMgnMode = mgnMode
 			}
-			logger.Infof("🔍 OKX found short position: quantity=%f (base asset), mgnMode=%s", actualQty, posMgnMode)
+			t.log.Infof("🔍 OKX found short position: quantity=%f (base asset), mgnMode=%s", actualQty, posMgnMode)
 			break
 		}
 	}
@@ -342,7 +341,7 @@ func (t *OKXTrader) CloseShort(symbol string, quantity float64) (map[string]inte
 	contracts := quantity / inst.CtVal
 	szStr := t.formatSize(contracts, inst)
 
-	logger.Infof("🔻 OKX close short: symbol=%s, quantity=%.6f, ctVal=%.6f, contracts=%.2f, szStr=%s, posMode=%s, mgnMode=%s",
+	t.log.Infof("🔻 OKX close short: symbol=%s, quantity=%.6f, ctVal=%.6f, contracts=%.2f, szStr=%s, posMode=%s, mgnMode=%s",
 		symbol, quantity, inst.CtVal, contracts, szStr, t.positionMode, posMgnMode)
 
 	body := map[string]interface{}{
@@ -360,7 +359,7 @@ func (t *OKXTrader) CloseShort(symbol string, quantity float64) (map[string]inte
 		body["posSide"] = "short"
 	}
 
-	logger.Infof("🔻 OKX close short request body: %+v", body)
+	t.log.Infof("🔻 OKX close short request body: %+v", body)
 
 	data, err := t.doRequest("POST", okxOrderPath, body)
 	if err != nil {
@@ -382,15 +381,15 @@ func (t *OKXTrader) CloseShort(symbol string, quantity float64) (map[string]inte
 		if len(orders) > 0 {
 			msg = fmt.Sprintf("sCode=%s, sMsg=%s", orders[0].SCode, orders[0].SMsg)
 		}
-		logger.Infof("❌ OKX failed to close short position: %s, response: %s", msg, string(data))
+		t.log.Infof("❌ OKX failed to close short position: %s, response: %s", msg, string(data))
 		return nil, fmt.Errorf("failed to close short position: %s", msg)
 	}
 
-	logger.Infof("✓ OKX closed short position successfully: %s, ordId=%s", symbol, orders[0].OrdId)
+	t.log.Infof("✓ OKX closed short position successfully: %s, ordId=%s", symbol, orders[0].OrdId)
 
 	// Cancel pending orders after closing position
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel pending orders: %v", err)
+		t.log.Infof("  ⚠ Failed to cancel pending orders: %v", err)
 	}
 
 	return map[string]interface{}{
@@ -441,7 +440,7 @@ func (t *OKXTrader) SetStopLoss(symbol string, positionSide string, quantity, st
 		return fmt.Errorf("failed to set stop loss: %w", err)
 	}
 
-	logger.Infof("  Stop loss price set: %.4f", stopPrice)
+	t.log.Infof("  Stop loss price set: %.4f", stopPrice)
 	return nil
 }
 
@@ -486,7 +485,7 @@ func (t *OKXTrader) SetTakeProfit(symbol string, positionSide string, quantity,
 		return fmt.Errorf("failed to set take profit: %w", err)
 	}
 
-	logger.Infof("  Take profit price set: %.4f", takeProfitPrice)
+	t.log.Infof("  Take profit price set: %.4f", takeProfitPrice)
 	return nil
 }
 
@@ -531,14 +530,14 @@ func (t *OKXTrader) cancelAlgoOrders(symbol string, orderType string) error {
 
 		_, err := t.doRequest("POST", okxCancelAlgoPath, body)
 		if err != nil {
-			logger.Infof("  ⚠️ Failed to cancel algo order: %v", err)
+			t.log.Infof("  ⚠️ Failed to cancel algo order: %v", err)
 			continue
 		}
 		canceledCount++
 	}
 
 	if canceledCount > 0 {
-		logger.Infof("  ✓ Canceled %d algo orders for %s", canceledCount, symbol)
+		t.log.Infof("  ✓ Canceled %d algo orders for %s", canceledCount, symbol)
 	}
 
 	return nil
@@ -571,17 +570,17 @@ func (t *OKXTrader) CancelAllOrders(symbol string) error {
 			"ordId":  order.OrdId,
 		}
 		if _, err := t.doRequest("POST", okxCancelOrderPath, body); err != nil {
-			logger.Infof("  ⚠ Failed to cancel order %s for %s: %v", order.OrdId, symbol, err)
+			t.log.Infof("  ⚠ Failed to cancel order %s for %s: %v", order.OrdId, symbol, err)
 		}
 	}
 
 	// Also cancel algo orders
 	if err := t.cancelAlgoOrders(symbol, ""); err != nil {
-		logger.Infof("  ⚠ Failed to cancel algo orders for %s: %v", symbol, err)
+		t.log.Infof("  ⚠ Failed to cancel algo orders for %s: %v", symbol, err)
 	}
 
 	if len(orders) > 0 {
-		logger.Infof("  ✓ Canceled all pending orders for %s", symbol)
+		t.log.Infof("  ✓ Canceled all pending orders for %s", symbol)
 	}
 
 	return nil
@@ -635,7 +634,7 @@ func (t *OKXTrader) GetOrderStatus(symbol string, orderID string) (map[string]in
 	inst, err := t.getInstrument(symbol)
 	if err == nil && inst.CtVal > 0 {
 		executedQty = fillSz * inst.CtVal
-		logger.Debugf("  📊 OKX order %s: fillSz(contracts)=%.4f, ctVal=%.6f, executedQty=%.6f", orderID, fillSz, inst.CtVal, executedQty)
+		t.log.Debugf("  📊 OKX order %s: fillSz(contracts)=%.4f, ctVal=%.6f, executedQty=%.6f", orderID, fillSz, inst.CtVal, executedQty)
 	}
 
 	// Status mapping
@@ -674,7 +673,7 @@ func (t *OKXTrader) GetOpenOrders(symbol string) ([]types.OpenOrder, error) {
 	path := fmt.Sprintf("%s?instId=%s&instType=SWAP", okxPendingOrdersPath, instId)
 	data, err := t.doRequest("GET", path, nil)
 	if err != nil {
-		logger.Warnf("[OKX] Failed to get pending orders: %v", err)
+		t.log.Warnf("[OKX] Failed to get pending orders: %v", err)
 	}
 	if err == nil && data != nil {
 		var orders []struct {
@@ -719,7 +718,7 @@ func (t *OKXTrader) GetOpenOrders(symbol string) ([]types.OpenOrder, error) {
 	algoPath := fmt.Sprintf("%s?instId=%s&instType=SWAP&ordType=conditional", okxAlgoPendingPath, instId)
 	algoData, err := t.doRequest("GET", algoPath, nil)
 	if err != nil {
-		logger.Warnf("[OKX] Failed to get algo orders: %v", err)
+		t.log.Warnf("[OKX] Failed to get algo orders: %v", err)
 	}
 	if err == nil && algoData != nil {
 		var algoOrders []struct {
@@ -801,7 +800,7 @@ func (t *OKXTrader) GetOpenOrders(symbol string) ([]types.OpenOrder, error) {
 		}
 	}
 
-	logger.Infof("✓ OKX GetOpenOrders: found %d open orders for %s", len(result), symbol)
+	t.log.Infof("✓ OKX GetOpenOrders: found %d open orders for %s", len(result), symbol)
 	return result, nil
 }
 
@@ -819,7 +818,7 @@ func (t *OKXTrader) PlaceLimitOrder(req *types.LimitOrderRequest) (*types.LimitO
 	// Set leverage if specified
 	if req.Leverage > 0 {
 		if err := t.SetLeverage(req.Symbol, req.Leverage); err != nil {
-			logger.Warnf("[OKX] Failed to set leverage: %v", err)
+			t.log.Warnf("[OKX] Failed to set leverage: %v", err)
 		}
 	}
 
@@ -854,7 +853,7 @@ func (t *OKXTrader) PlaceLimitOrder(req *types.LimitOrderRequest) (*types.LimitO
 		body["reduceOnly"] = true
 	}
 
-	logger.Infof("[OKX] PlaceLimitOrder: %s %s @ %.4f, sz=%s", instId, side, req.Price, szStr)
+	t.log.Infof("[OKX] PlaceLimitOrder: %s %s @ %.4f, sz=%s", instId, side, req.Price, szStr)
 
 	data, err := t.doRequest("POST", okxOrderPath, body)
 	if err != nil {
@@ -880,7 +879,7 @@ func (t *OKXTrader) PlaceLimitOrder(req *types.LimitOrderRequest) (*types.LimitO
 		return nil, fmt.Errorf("OKX order failed: %s", orders[0].SMsg)
 	}
 
-	logger.Infof("✓ [OKX] Limit order placed: %s %s @ %.4f, orderID=%s",
+	t.log.Infof("✓ [OKX] Limit order placed: %s %s @ %.4f, orderID=%s",
 		instId, side, req.Price, orders[0].OrdId)
 
 	return &types.LimitOrderResult{
@@ -910,7 +909,7 @@ func (t *OKXTrader) CancelOrder(symbol, orderID string) error {
 		return fmt.Errorf("failed to cancel order: %w", err)
 	}
 
-	logger.Infof("✓ [OKX] Order cancelled: %s %s", symbol, orderID)
+	t.log.Infof("✓ [OKX] Order cancelled: %s %s", symbol, orderID)
 	return nil
 }
 
@@ -3,7 +3,6 @@ package okx
 import (
 	"encoding/json"
 	"fmt"
-	"nofx/logger"
 	"strconv"
 	"time"
 )
@@ -14,12 +13,12 @@ func (t *OKXTrader) GetPositions() ([]map[string]interface{}, error) {
 	t.positionsCacheMutex.RLock()
 	if t.cachedPositions != nil && time.Since(t.positionsCacheTime) < t.cacheDuration {
 		t.positionsCacheMutex.RUnlock()
-		logger.Infof("✓ Using cached OKX positions")
+		t.log.Infof("✓ Using cached OKX positions")
 		return t.cachedPositions, nil
 	}
 	t.positionsCacheMutex.RUnlock()
 
-	logger.Infof("🔄 Calling OKX API to get positions...")
+	t.log.Infof("🔄 Calling OKX API to get positions...")
 	data, err := t.doRequest("GET", okxPositionPath+"?instType=SWAP", nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get positions: %w", err)
@@ -44,10 +43,10 @@ func (t *OKXTrader) GetPositions() ([]map[string]interface{}, error) {
 		return nil, fmt.Errorf("failed to parse position data: %w", err)
 	}
 
-	logger.Infof("🔍 OKX raw positions response: %d positions", len(positions))
+	t.log.Infof("🔍 OKX raw positions response: %d positions", len(positions))
 	var result []map[string]interface{}
 	for _, pos := range positions {
-		logger.Infof("🔍 OKX raw position: instId=%s, posSide=%s, pos=%s, mgnMode=%s", pos.InstId, pos.PosSide, pos.Pos, pos.MgnMode)
+		t.log.Infof("🔍 OKX raw position: instId=%s, posSide=%s, pos=%s, mgnMode=%s", pos.InstId, pos.PosSide, pos.Pos, pos.MgnMode)
 		contractCount, _ := strconv.ParseFloat(pos.Pos, 64)
 		if contractCount == 0 {
 			continue
@@ -61,7 +60,7 @@ func (t *OKXTrader) GetPositions() ([]map[string]interface{}, error) {
 
 		// Convert symbol format
 		symbol := t.convertSymbolBack(pos.InstId)
-		logger.Infof("🔍 OKX symbol conversion: %s → %s", pos.InstId, symbol)
+		t.log.Infof("🔍 OKX symbol conversion: %s → %s", pos.InstId, symbol)
 
 		// Determine direction and ensure contractCount is positive
 		side := "long"
@@ -79,7 +78,7 @@ func (t *OKXTrader) GetPositions() ([]map[string]interface{}, error) {
 		posAmt := contractCount
 		if err == nil && inst.CtVal > 0 {
 			posAmt = contractCount * inst.CtVal
-			logger.Debugf("  📊 OKX position %s: contracts=%.4f, ctVal=%.6f, posAmt=%.6f", symbol, contractCount, inst.CtVal, posAmt)
+			t.log.Debugf("  📊 OKX position %s: contracts=%.4f, ctVal=%.6f, posAmt=%.6f", symbol, contractCount, inst.CtVal, posAmt)
 		}
 
 		// Parse timestamps
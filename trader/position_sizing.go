@@ -0,0 +1,150 @@
+package trader
+
+import (
+	"fmt"
+	"math"
+	"nofx/kernel"
+	"nofx/logger"
+	"nofx/market"
+	"nofx/store"
+)
+
+// sizingInput what the sizing engine knows about an open
+type sizingInput struct {
+	Equity     float64
+	EntryPrice float64
+	StopLoss   float64
+	ATR        float64            // 4h ATR14 in price units, 0 when unknown
+	Stats      *store.TraderStats // closed-trade statistics, nil when unavailable
+}
+
+// computePositionSize returns the notional the sizing mode allows and its
+// rationale. ok is false when the mode cannot size this open (no valid stop,
+// no ATR); err is set when the mode rules the trade out (no Kelly edge).
+func computePositionSize(cfg store.PositionSizingConfig, in sizingInput) (sizeUSD float64, rationale string, ok bool, err error) {
+	cfg = cfg.WithDefaults()
+	if in.Equity <= 0 || in.EntryPrice <= 0 {
+		return 0, "no equity or entry price", false, nil
+	}
+	stopDistPct := 0.0
+	if in.StopLoss > 0 {
+		stopDistPct = math.Abs(in.EntryPrice-in.StopLoss) / in.EntryPrice * 100
+	}
+
+	switch cfg.Mode {
+	case store.PositionSizingFixedFractional:
+		return fixedFractionalSize(in.Equity, cfg.RiskPerTradePct, stopDistPct, "fixed fractional")
+
+	case store.PositionSizingATR:
+		if in.ATR <= 0 {
+			return 0, "ATR unavailable", false, nil
+		}
+		movePct := cfg.ATRMultiple * in.ATR / in.EntryPrice * 100
+		riskUSD := in.Equity * cfg.RiskPerTradePct / 100
+		sizeUSD = riskUSD / (movePct / 100)
+		return sizeUSD, fmt.Sprintf("ATR target: risk %.2f%% of equity (%.2f USDT) over %.1f×ATR = %.2f%% move → %.2f USDT",
+			cfg.RiskPerTradePct, riskUSD, cfg.ATRMultiple, movePct, sizeUSD), true, nil
+
+	case store.PositionSizingKelly:
+		if in.Stats == nil || in.Stats.TotalTrades < cfg.KellyMinTrades {
+			trades := 0
+			if in.Stats != nil {
+				trades = in.Stats.TotalTrades
+			}
+			return fixedFractionalSize(in.Equity, cfg.RiskPerTradePct, stopDistPct,
+				fmt.Sprintf("Kelly warm-up (%d/%d trades), fixed fractional", trades, cfg.KellyMinTrades))
+		}
+		kelly := kellyFraction(in.Stats)
+		if kelly <= 0 {
+			return 0, "", false, fmt.Errorf("no statistical edge for Kelly sizing (win rate %.1f%%, avg win %.2f, avg loss %.2f)",
+				in.Stats.WinRate, in.Stats.AvgWin, in.Stats.AvgLoss)
+		}
+		riskPct := math.Min(kelly*cfg.KellyFraction*100, cfg.MaxRiskPerTradePct)
+		return fixedFractionalSize(in.Equity, riskPct, stopDistPct,
+			fmt.Sprintf("Kelly %.1f%% × %.2f (win rate %.1f%%, %d trades, capped at %.1f%%)",
+				kelly*100, cfg.KellyFraction, in.Stats.WinRate, in.Stats.TotalTrades, cfg.MaxRiskPerTradePct))
+	}
+	return 0, "", false, nil
+}
+
+// fixedFractionalSize sizes so the loss at the stop is riskPct of equity
+func fixedFractionalSize(equity, riskPct, stopDistPct float64, label string) (float64, string, bool, error) {
+	if stopDistPct <= 0 {
+		return 0, label + ": no stop loss distance", false, nil
+	}
+	riskUSD := equity * riskPct / 100
+	sizeUSD := riskUSD / (stopDistPct / 100)
+	return sizeUSD, fmt.Sprintf("%s: risk %.2f%% of equity (%.2f USDT) at a %.2f%% stop → %.2f USDT",
+		label, riskPct, riskUSD, stopDistPct, sizeUSD), true, nil
+}
+
+// kellyFraction the full Kelly bet f* = W − (1 − W) / R from the win rate W
+// and payoff R = avg win / avg loss. It never exceeds the win rate, which it
+// equals when there are no losses yet.
+func kellyFraction(stats *store.TraderStats) float64 {
+	w := stats.WinRate / 100
+	if stats.AvgLoss <= 0 {
+		return w
+	}
+	r := stats.AvgWin / stats.AvgLoss
+	if r <= 0 {
+		return -1
+	}
+	return w - (1-w)/r
+}
+
+// applyPositionSizing replaces the AI's position size with the size of the
+// strategy's sizing mode: the smaller of the two when the AI size is an
+// upper bound, the computed size when it is a hint. The rationale is kept on
+// the action record for the execution log.
+func (at *AutoTrader) applyPositionSizing(log *logger.Scope, decision *kernel.Decision, equity float64, marketData *market.Data, actionRecord *store.DecisionAction) error {
+	if at.config.StrategyConfig == nil {
+		return nil
+	}
+	cfg := at.config.StrategyConfig.RiskControl.PositionSizing.WithDefaults()
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	in := sizingInput{Equity: equity, StopLoss: decision.StopLoss}
+	if marketData != nil {
+		in.EntryPrice = marketData.CurrentPrice
+		if marketData.LongerTermContext != nil {
+			in.ATR = marketData.LongerTermContext.ATR14
+		}
+		if in.ATR <= 0 && marketData.IntradaySeries != nil {
+			in.ATR = marketData.IntradaySeries.ATR14
+		}
+	}
+	if cfg.Mode == store.PositionSizingKelly && at.store != nil {
+		stats, err := at.store.Position().GetFullStats(at.id, at.initialBalance)
+		if err != nil {
+			log.Warnf("  ⚠️ [SIZING] Failed to load trading stats for Kelly sizing: %v", err)
+		}
+		in.Stats = stats
+	}
+
+	sizeUSD, rationale, ok, err := computePositionSize(cfg, in)
+	if err != nil {
+		actionRecord.SizingNote = fmt.Sprintf("%s sizing: %v", cfg.Mode, err)
+		return fmt.Errorf("❌ [SIZING] %w", err)
+	}
+	if !ok {
+		actionRecord.SizingNote = fmt.Sprintf("%s sizing unavailable (%s), using AI size %.2f USDT", cfg.Mode, rationale, decision.PositionSizeUSD)
+		log.Infof("  📐 [SIZING] %s: %s", decision.Symbol, actionRecord.SizingNote)
+		return nil
+	}
+
+	aiSize := decision.PositionSizeUSD
+	final := sizeUSD
+	if cfg.AISizeRole == store.AISizeUpperBound && aiSize > 0 && aiSize < final {
+		final = aiSize
+		rationale += fmt.Sprintf("; AI size %.2f USDT is the upper bound", aiSize)
+	} else if aiSize > 0 {
+		rationale += fmt.Sprintf("; AI size %.2f USDT replaced", aiSize)
+	}
+	decision.PositionSizeUSD = final
+	actionRecord.SizingNote = rationale
+	log.Infof("  📐 [SIZING] %s: %s", decision.Symbol, rationale)
+	return nil
+}
@@ -0,0 +1,77 @@
+package trader
+
+import (
+	"math"
+	"nofx/kernel"
+	"nofx/market"
+	"nofx/store"
+	"testing"
+)
+
+func TestComputePositionSizeModes(t *testing.T) {
+	in := sizingInput{Equity: 1000, EntryPrice: 100, StopLoss: 98, ATR: 2.5}
+
+	// 1% of 1000 at a 2% stop → 500
+	size, _, ok, err := computePositionSize(store.PositionSizingConfig{Mode: store.PositionSizingFixedFractional}, in)
+	if err != nil || !ok || math.Abs(size-500) > 1e-9 {
+		t.Fatalf("fixed fractional: got %.2f ok=%v err=%v, want 500", size, ok, err)
+	}
+
+	// 1% of 1000 over 2×2.5 = 5% move → 200
+	size, _, ok, err = computePositionSize(store.PositionSizingConfig{Mode: store.PositionSizingATR}, in)
+	if err != nil || !ok || math.Abs(size-200) > 1e-9 {
+		t.Fatalf("ATR: got %.2f ok=%v err=%v, want 200", size, ok, err)
+	}
+
+	// W=0.6, R=2 → f*=0.4, half Kelly 20% capped at 5% → 50 USDT risk at a 2% stop → 2500
+	in.Stats = &store.TraderStats{TotalTrades: 40, WinRate: 60, AvgWin: 20, AvgLoss: 10}
+	size, _, ok, err = computePositionSize(store.PositionSizingConfig{Mode: store.PositionSizingKelly}, in)
+	if err != nil || !ok || math.Abs(size-2500) > 1e-9 {
+		t.Fatalf("Kelly: got %.2f ok=%v err=%v, want 2500", size, ok, err)
+	}
+
+	// No edge: W=0.3, R=1 → f*<0 rules the trade out
+	in.Stats = &store.TraderStats{TotalTrades: 40, WinRate: 30, AvgWin: 10, AvgLoss: 10}
+	if _, _, _, err = computePositionSize(store.PositionSizingConfig{Mode: store.PositionSizingKelly}, in); err == nil {
+		t.Fatal("Kelly without an edge should reject the open")
+	}
+
+	// Warm-up: too few trades falls back to fixed fractional
+	in.Stats = &store.TraderStats{TotalTrades: 3, WinRate: 100, AvgWin: 10}
+	size, _, ok, _ = computePositionSize(store.PositionSizingConfig{Mode: store.PositionSizingKelly}, in)
+	if !ok || math.Abs(size-500) > 1e-9 {
+		t.Fatalf("Kelly warm-up: got %.2f ok=%v, want 500", size, ok)
+	}
+
+	// No stop: fixed fractional cannot size
+	if _, _, ok, _ = computePositionSize(store.PositionSizingConfig{Mode: store.PositionSizingFixedFractional}, sizingInput{Equity: 1000, EntryPrice: 100}); ok {
+		t.Fatal("fixed fractional without a stop should not size")
+	}
+}
+
+func TestApplyPositionSizingAISizeRole(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en")
+	cfg.RiskControl.PositionSizing = store.PositionSizingConfig{Mode: store.PositionSizingFixedFractional}
+	at := &AutoTrader{config: AutoTraderConfig{StrategyConfig: &cfg}}
+	data := &market.Data{CurrentPrice: 100}
+
+	// Upper bound: AI size below the computed 500 wins
+	decision := &kernel.Decision{Symbol: "SOLUSDT", PositionSizeUSD: 300, StopLoss: 98}
+	record := &store.DecisionAction{}
+	if err := at.applyPositionSizing(nil, decision, 1000, data, record); err != nil {
+		t.Fatal(err)
+	}
+	if decision.PositionSizeUSD != 300 || record.SizingNote == "" {
+		t.Fatalf("upper bound: got size %.2f note %q", decision.PositionSizeUSD, record.SizingNote)
+	}
+
+	// Hint: computed size replaces the AI size
+	cfg.RiskControl.PositionSizing.AISizeRole = store.AISizeHint
+	decision.PositionSizeUSD = 300
+	if err := at.applyPositionSizing(nil, decision, 1000, data, record); err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(decision.PositionSizeUSD-500) > 1e-9 {
+		t.Fatalf("hint: got size %.2f, want 500", decision.PositionSizeUSD)
+	}
+}
@@ -5,9 +5,11 @@
 package syncloop
 
 import (
+	"strings"
 	"time"
 
 	"nofx/logger"
+	"nofx/metrics"
 )
 
 // maxBackoff caps the failure backoff so a recovered exchange is picked up
@@ -18,9 +20,23 @@ const maxBackoff = 5 * time.Minute
 // stop is closed. After each consecutive failure the wait doubles (capped at
 // maxBackoff); the first success resets it to the base interval.
 func Run(stop <-chan struct{}, interval time.Duration, name string, syncFn func() error) {
+	RunForTrader(stop, interval, name, "", syncFn)
+}
+
+// RunForTrader is Run for a trader's exchange: its log lines carry traderID,
+// and every attempt and the backoff in effect are reported to the exchange
+// sync metrics.
+func RunForTrader(stop <-chan struct{}, interval time.Duration, name, traderID string, syncFn func() error) {
 	if interval <= 0 {
 		interval = 30 * time.Second
 	}
+	var log *logger.Scope
+	if traderID != "" {
+		log = logger.NewScope(logger.Fields{
+			logger.FieldTraderID: traderID,
+			logger.FieldExchange: strings.ToLower(name),
+		})
+	}
 	go func() {
 		wait := interval
 		timer := time.NewTimer(wait)
@@ -28,21 +44,25 @@ func Run(stop <-chan struct{}, interval time.Duration, name string, syncFn func(
 		for {
 			select {
 			case <-stop:
-				logger.Infof("⏹ %s order sync stopped", name)
+				log.Infof("⏹ %s order sync stopped", name)
 				return
 			case <-timer.C:
-				if err := syncFn(); err != nil {
+				err := syncFn()
+				if err != nil {
 					wait *= 2
 					if wait > maxBackoff {
 						wait = maxBackoff
 					}
-					logger.Infof("⚠️ %s order sync failed: %v (backing off, next attempt in %v)", name, err, wait)
+					log.Infof("⚠️ %s order sync failed: %v (backing off, next attempt in %v)", name, err, wait)
 				} else {
 					wait = interval
 				}
+				if traderID != "" {
+					metrics.ObserveExchangeSync(traderID, strings.ToLower(name), err, wait-interval)
+				}
 				timer.Reset(wait)
 			}
 		}
 	}()
-	logger.Infof("🔄 %s order sync started (interval: %v)", name, interval)
+	log.Infof("🔄 %s order sync started (interval: %v)", name, interval)
 }
@@ -0,0 +1,186 @@
+package trader
+
+import (
+	"context"
+	"time"
+
+	"nofx/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracedTrader records an "exchange.<Method>" span around each exchange
+// call of a decision cycle. It is a view used at the cycle's call sites only;
+// at.trader itself stays the concrete adapter, because callers type-assert it
+// for optional interfaces (GridTrader, FundingHistoryProvider, ...).
+type tracedTrader struct {
+	Trader
+	ctx      context.Context
+	exchange string
+}
+
+// traced returns the trader's exchange wrapped in spans under the running
+// cycle's span. Only call it from the cycle goroutine.
+func (at *AutoTrader) traced() Trader {
+	return at.tracedIn(at.cycleCtx)
+}
+
+// tracedIn returns the trader's exchange wrapped in spans under ctx
+func (at *AutoTrader) tracedIn(ctx context.Context) Trader {
+	return &tracedTrader{Trader: at.trader, ctx: ctx, exchange: at.exchange}
+}
+
+// startCycleSpan starts the span of one decision cycle and makes it the
+// parent of the cycle's exchange, market data and AI spans
+func (at *AutoTrader) startCycleSpan(name string) trace.Span {
+	ctx, span := tracing.Start(context.Background(), name,
+		attribute.String("trader_id", at.id),
+		attribute.String("exchange", at.exchange),
+		attribute.Int("cycle", at.callCount),
+	)
+	at.cycleCtx = ctx
+	return span
+}
+
+// endCycleSpan ends the cycle span; later exchange calls start new traces
+func (at *AutoTrader) endCycleSpan(span trace.Span, err error) {
+	tracing.End(span, err)
+	at.cycleCtx = nil
+}
+
+func (t *tracedTrader) start(method, symbol string) trace.Span {
+	attrs := []attribute.KeyValue{attribute.String("exchange", t.exchange)}
+	if symbol != "" {
+		attrs = append(attrs, attribute.String("symbol", symbol))
+	}
+	_, span := tracing.Start(t.ctx, "exchange."+method, attrs...)
+	return span
+}
+
+func (t *tracedTrader) GetBalance() (map[string]interface{}, error) {
+	span := t.start("GetBalance", "")
+	balance, err := t.Trader.GetBalance()
+	tracing.End(span, err)
+	return balance, err
+}
+
+func (t *tracedTrader) GetPositions() ([]map[string]interface{}, error) {
+	span := t.start("GetPositions", "")
+	positions, err := t.Trader.GetPositions()
+	span.SetAttributes(attribute.Int("positions", len(positions)))
+	tracing.End(span, err)
+	return positions, err
+}
+
+func (t *tracedTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	span := t.start("OpenLong", symbol)
+	order, err := t.Trader.OpenLong(symbol, quantity, leverage)
+	tracing.End(span, err)
+	return order, err
+}
+
+func (t *tracedTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	span := t.start("OpenShort", symbol)
+	order, err := t.Trader.OpenShort(symbol, quantity, leverage)
+	tracing.End(span, err)
+	return order, err
+}
+
+func (t *tracedTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	span := t.start("CloseLong", symbol)
+	order, err := t.Trader.CloseLong(symbol, quantity)
+	tracing.End(span, err)
+	return order, err
+}
+
+func (t *tracedTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	span := t.start("CloseShort", symbol)
+	order, err := t.Trader.CloseShort(symbol, quantity)
+	tracing.End(span, err)
+	return order, err
+}
+
+func (t *tracedTrader) SetLeverage(symbol string, leverage int) error {
+	span := t.start("SetLeverage", symbol)
+	err := t.Trader.SetLeverage(symbol, leverage)
+	tracing.End(span, err)
+	return err
+}
+
+func (t *tracedTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	span := t.start("SetMarginMode", symbol)
+	err := t.Trader.SetMarginMode(symbol, isCrossMargin)
+	tracing.End(span, err)
+	return err
+}
+
+func (t *tracedTrader) GetMarketPrice(symbol string) (float64, error) {
+	span := t.start("GetMarketPrice", symbol)
+	price, err := t.Trader.GetMarketPrice(symbol)
+	tracing.End(span, err)
+	return price, err
+}
+
+func (t *tracedTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	span := t.start("SetStopLoss", symbol)
+	err := t.Trader.SetStopLoss(symbol, positionSide, quantity, stopPrice)
+	tracing.End(span, err)
+	return err
+}
+
+func (t *tracedTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	span := t.start("SetTakeProfit", symbol)
+	err := t.Trader.SetTakeProfit(symbol, positionSide, quantity, takeProfitPrice)
+	tracing.End(span, err)
+	return err
+}
+
+func (t *tracedTrader) CancelStopLossOrders(symbol string) error {
+	span := t.start("CancelStopLossOrders", symbol)
+	err := t.Trader.CancelStopLossOrders(symbol)
+	tracing.End(span, err)
+	return err
+}
+
+func (t *tracedTrader) CancelTakeProfitOrders(symbol string) error {
+	span := t.start("CancelTakeProfitOrders", symbol)
+	err := t.Trader.CancelTakeProfitOrders(symbol)
+	tracing.End(span, err)
+	return err
+}
+
+func (t *tracedTrader) CancelAllOrders(symbol string) error {
+	span := t.start("CancelAllOrders", symbol)
+	err := t.Trader.CancelAllOrders(symbol)
+	tracing.End(span, err)
+	return err
+}
+
+func (t *tracedTrader) CancelStopOrders(symbol string) error {
+	span := t.start("CancelStopOrders", symbol)
+	err := t.Trader.CancelStopOrders(symbol)
+	tracing.End(span, err)
+	return err
+}
+
+func (t *tracedTrader) GetOrderStatus(symbol string, orderID string) (map[string]interface{}, error) {
+	span := t.start("GetOrderStatus", symbol)
+	status, err := t.Trader.GetOrderStatus(symbol, orderID)
+	tracing.End(span, err)
+	return status, err
+}
+
+func (t *tracedTrader) GetClosedPnL(startTime time.Time, limit int) ([]ClosedPnLRecord, error) {
+	span := t.start("GetClosedPnL", "")
+	records, err := t.Trader.GetClosedPnL(startTime, limit)
+	tracing.End(span, err)
+	return records, err
+}
+
+func (t *tracedTrader) GetOpenOrders(symbol string) ([]OpenOrder, error) {
+	span := t.start("GetOpenOrders", symbol)
+	orders, err := t.Trader.GetOpenOrders(symbol)
+	tracing.End(span, err)
+	return orders, err
+}
@@ -0,0 +1,70 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"nofx/tracing"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// stubExchange answers the calls the test makes; other methods panic
+type stubExchange struct {
+	Trader
+	closeErr error
+}
+
+func (s *stubExchange) GetBalance() (map[string]interface{}, error) {
+	return map[string]interface{}{"totalEquity": 100.0}, nil
+}
+
+func (s *stubExchange) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	return nil, s.closeErr
+}
+
+func TestTracedExchangeCallsNestUnderCycle(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	shutdown := tracing.Install(exp, true)
+	defer shutdown(context.Background())
+
+	at := &AutoTrader{id: "t1", exchange: "binance", callCount: 7,
+		trader: &stubExchange{closeErr: errors.New("reduce only rejected")}}
+
+	span := at.startCycleSpan("trader.cycle")
+	if _, err := at.traced().GetBalance(); err != nil {
+		t.Fatal(err)
+	}
+	_, closeErr := at.traced().CloseLong("BTCUSDT", 0)
+	at.endCycleSpan(span, closeErr)
+	if at.cycleCtx != nil {
+		t.Fatal("cycle context kept after the cycle ended")
+	}
+
+	spans := exp.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans, want 3", len(spans))
+	}
+	cycle := spans[2]
+	if cycle.Name != "trader.cycle" {
+		t.Fatalf("last span = %s, want trader.cycle", cycle.Name)
+	}
+	for _, s := range spans[:2] {
+		if s.Parent.SpanID() != cycle.SpanContext.SpanID() {
+			t.Errorf("%s is not a child of the cycle span", s.Name)
+		}
+	}
+	closeSpan := spans[1]
+	if closeSpan.Name != "exchange.CloseLong" || closeSpan.Status.Code != codes.Error {
+		t.Fatalf("close span = %s status %v, want exchange.CloseLong with error", closeSpan.Name, closeSpan.Status.Code)
+	}
+	attrs := map[string]string{}
+	for _, kv := range closeSpan.Attributes {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if attrs["symbol"] != "BTCUSDT" || attrs["exchange"] != "binance" {
+		t.Fatalf("close span attributes = %v", attrs)
+	}
+}
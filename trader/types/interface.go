@@ -38,6 +38,31 @@ type TradeRecord struct {
 	Time         time.Time // Trade execution time
 }
 
+// FundingPayment a funding fee settled on a perpetual position
+type FundingPayment struct {
+	PaymentID string    // Exchange-specific ID, unique per account
+	Symbol    string    // Trading pair as the exchange reports it
+	Amount    float64   // Signed: positive = received, negative = paid
+	Rate      float64   // Funding rate applied, 0 when the exchange does not report it
+	Time      time.Time // Settlement time
+}
+
+// FundingHistoryProvider is implemented by exchanges that expose the
+// account's funding payment history
+type FundingHistoryProvider interface {
+	// GetFundingPayments returns funding payments settled at or after startTime, oldest first
+	GetFundingPayments(startTime time.Time, limit int) ([]FundingPayment, error)
+}
+
+// LogScoped is implemented by exchanges that log through the owning trader's
+// base scope (trader_id, exchange) instead of the global logger. The trader
+// sets it once before use; the adapter logs through it from every goroutine,
+// including its order sync loop, so it never carries cycle fields. Until it
+// is set the adapter logs through the global logger.
+type LogScoped interface {
+	SetLogScope(scope *logger.Scope)
+}
+
 // Trader Unified trader interface
 // Supports multiple trading platforms (Binance, Hyperliquid, etc.)
 type Trader interface {
@@ -112,6 +112,61 @@ export interface TraderFullStats {
   avg_loss: number
   /** Percent, not a fraction: 18.5 means -18.5% peak drawdown. */
   max_drawdown_pct: number
+  analytics?: PerformanceAnalytics
+}
+
+// Strategy analytics of GET /api/statistics/full (store.PerformanceAnalytics),
+// restricted to ?since=&until= when given.
+export interface PerformanceAnalytics {
+  since?: string
+  until?: string
+  equity_curve: {
+    snapshots: number
+    days: number
+    start_equity: number
+    end_equity: number
+    return_pct: number
+    annualized_return_pct: number // 0 when the curve spans less than a week
+    volatility_pct: number
+    sharpe_ratio: number
+    sortino_ratio: number
+    calmar_ratio: number
+    max_drawdown_pct: number
+  }
+  expectancy: number
+  longest_win_streak: number
+  longest_loss_streak: number
+  exposure_pct: number
+  avg_hold_mins: number
+  excursions: {
+    positions: number
+    avg_mae_pct: number
+    avg_mfe_pct: number
+    winner_avg_mae_pct: number
+    loser_avg_mfe_pct: number
+    by_position: {
+      position_id: number
+      symbol: string
+      side: string
+      mae_pct: number
+      mfe_pct: number
+      captured_pct: number
+      net_pnl: number
+    }[]
+  }
+  attribution: Record<
+    'symbol' | 'side' | 'hour_of_day' | 'confidence' | 'leverage' | 'coin_source',
+    AttributionRow[]
+  >
+}
+
+export interface AttributionRow {
+  key: string
+  trades: number
+  wins: number
+  win_rate: number
+  total_pnl: number
+  avg_pnl: number
 }
 
 // AI Trading related types